@@ -0,0 +1,142 @@
+package bst
+
+import "fmt"
+
+// Allocator allocates the nodes of a tree, and takes back the nodes the tree releases.
+// Allocators are set with WithAllocator. Arena is the bundled implementation.
+//
+// The tree clears every node it frees, except for its generation (see Handle), and initializes every
+// node it allocates, so an Allocator only needs to manage node memory. It must not clear nodes itself.
+type Allocator[K, V, M any] interface {
+	// New returns a zeroed node, or a node freed by Free, unchanged.
+	New() *Node[K, V, M]
+
+	// Free takes back a cleared node that was released by the tree (see Tree.Release).
+	Free(n *Node[K, V, M])
+
+	// Reset takes back every node allocated so far at once. It is called by Tree.Clear,
+	// after every node of the tree has been released, and without calling Free on them.
+	Reset()
+}
+
+// WithAllocator makes the tree allocate its nodes with a, rather than individually with new,
+// for instance to place nodes in large contiguous blocks with an Arena.
+//
+// The type parameters of a must match those of the tree, otherwise New panics. Trees created from the
+// tree, by Tree.DetachSubtree and Tree.CopySubtree, share a.
+//
+// ⚠️ Warning: As with WithNodePool, nodes released by the tree may be reused by later insertions,
+// so deleted nodes must not be kept: keep a Handle to detect them instead.
+func WithAllocator[K, V, M any](a Allocator[K, V, M]) Option {
+	return func(o *options) {
+		o.allocator = a
+	}
+}
+
+// newAllocator returns the Allocator of a tree created with the given options,
+// or nil if nodes are allocated individually.
+func newAllocator[K, V, M any](o options) Allocator[K, V, M] {
+	switch {
+	case o.allocator != nil:
+		a, ok := o.allocator.(Allocator[K, V, M])
+		if !ok {
+			panic(fmt.Sprintf("bst: allocator of type %T does not allocate nodes of type %T", o.allocator, &Node[K, V, M]{}))
+		}
+		return a
+	case o.pooled:
+		return &poolAllocator[K, V, M]{}
+	}
+	return nil
+}
+
+// newNode returns a zeroed node, allocated with the tree's Allocator if it has one.
+// The generation of a recycled node is kept (see Handle): callers initializing the node must keep it too.
+func (t *Tree[K, V, M]) newNode() *Node[K, V, M] {
+	if t.alloc != nil {
+		return t.alloc.New()
+	}
+	return &Node[K, V, M]{}
+}
+
+// recycle clears the released node n, keeping its generation, and returns it to the tree's Allocator,
+// if it has one.
+func (t *Tree[K, V, M]) recycle(n *Node[K, V, M]) {
+	if t.alloc != nil {
+		*n = Node[K, V, M]{gen: n.gen}
+		t.alloc.Free(n)
+	}
+}
+
+// DefaultArenaBlockSize is the number of nodes per block of an Arena created with a block size of 0.
+const DefaultArenaBlockSize = 1024
+
+// Arena is an Allocator placing nodes in large contiguous blocks, rather than allocating them one by
+// one. This reduces the number of objects the garbage collector tracks for very large trees, and the
+// cost of allocation, at the expense of keeping a whole block alive while any of its nodes is in use.
+//
+// Nodes freed by the tree are reused by later allocations. Tree.Clear resets the arena, dropping
+// every block at once, so the garbage collector can reclaim them together.
+//
+// An Arena is not safe for concurrent use, so should only be shared between trees that are
+// used from a single goroutine.
+//
+// Example Usage:
+//
+//	arena := bst.NewArena[int, string, struct{}](4096)
+//	tree := bst.New[int, string, struct{}](less, bst.WithAllocator(arena))
+type Arena[K, V, M any] struct {
+	blockSize int
+	block     []Node[K, V, M]  // current block, of which the first used nodes are allocated
+	used      int              // number of nodes allocated from block
+	free      []*Node[K, V, M] // freed nodes, reused before allocating from block
+	blocks    int              // number of blocks allocated since the last reset
+}
+
+// NewArena returns a new Arena allocating nodes in blocks of blockSize nodes,
+// or DefaultArenaBlockSize nodes if blockSize is 0.
+//
+// NewArena panics if blockSize is negative.
+func NewArena[K, V, M any](blockSize int) *Arena[K, V, M] {
+	if blockSize < 0 {
+		panic(fmt.Sprintf("bst: invalid arena block size: %d", blockSize))
+	}
+	if blockSize == 0 {
+		blockSize = DefaultArenaBlockSize
+	}
+	return &Arena[K, V, M]{blockSize: blockSize}
+}
+
+// New returns a zeroed node, reusing a freed node, unchanged, if there is one,
+// and allocating a new block if the current block is full.
+func (a *Arena[K, V, M]) New() *Node[K, V, M] {
+	if last := len(a.free) - 1; last >= 0 {
+		n := a.free[last]
+		a.free = a.free[:last]
+		return n
+	}
+	if a.used == len(a.block) {
+		a.block = make([]Node[K, V, M], a.blockSize)
+		a.used = 0
+		a.blocks++
+	}
+	n := &a.block[a.used]
+	a.used++
+	return n
+}
+
+// Free keeps n for reuse by a later call to Arena.New.
+func (a *Arena[K, V, M]) Free(n *Node[K, V, M]) {
+	a.free = append(a.free, n)
+}
+
+// Reset drops every block and freed node, so that the next call to Arena.New allocates a new block.
+// Blocks still holding nodes in use (e.g., by another tree sharing the arena) remain valid, and are
+// reclaimed by the garbage collector once unused.
+func (a *Arena[K, V, M]) Reset() {
+	a.block, a.used, a.free, a.blocks = nil, 0, nil, 0
+}
+
+// Blocks returns the number of blocks allocated since the arena was created or last reset.
+func (a *Arena[K, V, M]) Blocks() int {
+	return a.blocks
+}
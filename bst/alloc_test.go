@@ -0,0 +1,105 @@
+package bst
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+	"unsafe"
+)
+
+func TestWithAllocator_arena(t *testing.T) {
+	arena := NewArena[int, int, struct{}](100)
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b }, WithAllocator(arena))
+
+	var nodes []*Node[int, int, struct{}]
+	for i := 0; i < 250; i++ {
+		n, _ := tree.Insert(i, i)
+		nodes = append(nodes, n)
+	}
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	assert.Equal(t, 3, arena.Blocks())
+
+	// nodes are allocated contiguously within a block
+	size := unsafe.Sizeof(Node[int, int, struct{}]{})
+	for i := 1; i < 100; i++ {
+		assert.Equal(t, uintptr(unsafe.Pointer(nodes[0]))+uintptr(i)*size, uintptr(unsafe.Pointer(nodes[i])))
+	}
+
+	// freed nodes are reused
+	n, _ := tree.Search(10)
+	tree.Delete(n)
+	m, _ := tree.Insert(1000, 1000)
+	assert.Same(t, n, m, "expected freed node to be reused")
+	assert.Equal(t, 3, arena.Blocks())
+
+	// clearing releases every node and resets the arena
+	tree.Clear()
+	assert.Equal(t, 0, tree.Size())
+	assert.True(t, tree.IsNil(tree.Root()))
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	assert.Equal(t, 0, arena.Blocks())
+	for _, n := range nodes {
+		assert.False(t, n.BelongsTo(tree), "expected cleared node not to belong to the tree")
+	}
+
+	// the tree remains usable after clearing
+	rng := rand.New(rand.NewSource(1))
+	for _, key := range rng.Perm(150) {
+		tree.Insert(key, key)
+	}
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	assert.Equal(t, 150, tree.Size())
+	assert.Equal(t, 2, arena.Blocks())
+}
+
+func TestWithAllocator_shared(t *testing.T) {
+	arena := NewArena[int, int, struct{}](0)
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b }, WithAllocator(arena))
+	for i := 0; i < 10; i++ {
+		tree.Insert(i, i)
+	}
+	n, _ := tree.Search(3)
+	branch, _ := tree.DetachSubtree(n)
+
+	// resetting the shared arena leaves the nodes of the other tree intact
+	tree.Clear()
+	require.NoError(t, branch.IsTreeValid(), "expected valid tree")
+	assert.Equal(t, []int{3, 4, 5, 6, 7, 8, 9}, keysOf(branch))
+	branch.Insert(10, 10)
+	assert.Equal(t, []int{3, 4, 5, 6, 7, 8, 9, 10}, keysOf(branch))
+	assert.Equal(t, 1, arena.Blocks())
+}
+
+func TestWithAllocator_panics(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	assert.Panics(t, func() {
+		New[int, string, struct{}](less, WithAllocator(NewArena[int, int, struct{}](0)))
+	}, "expected mismatched allocator to panic")
+	assert.Panics(t, func() { NewArena[int, int, struct{}](-1) })
+}
+
+func TestTree_Clear(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"default": nil,
+		"pooled":  {WithNodePool()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tree := New[int, int, struct{}](func(a, b int) bool { return a < b }, opts...)
+			tree.Clear()
+			assert.Equal(t, 0, tree.Size())
+
+			n, _ := tree.Insert(1, 1)
+			tree.Insert(2, 2)
+			tree.Clear()
+			assert.Equal(t, 0, tree.Size())
+			assert.False(t, n.BelongsTo(tree))
+			_, deleted := tree.Delete(n)
+			assert.False(t, deleted)
+			require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+
+			tree.Insert(3, 3)
+			assert.Equal(t, []int{3}, keysOf(tree))
+		})
+	}
+}
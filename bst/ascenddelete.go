@@ -0,0 +1,60 @@
+package bst
+
+// Action is returned by the function passed to Tree.AscendDelete, to decide what happens to the node
+// it was given and whether the traversal continues. ActionDelete and ActionStop can be combined.
+type Action uint8
+
+const (
+	ActionContinue Action = 0      // keep the node, and continue with the next node
+	ActionDelete   Action = 1 << 0 // delete the node
+	ActionStop     Action = 1 << 1 // stop the traversal after this node
+)
+
+// AscendDelete calls f for each node of the tree in ascending key order, deleting the nodes for
+// which f returns an Action including ActionDelete, until f returns an Action including ActionStop.
+//
+// The traversal moves on to the next node before deleting the current one, so f can safely request
+// the deletion of any node it is given. f must not modify the tree itself.
+//
+// Nodes are deleted with Tree.Delete. Trees extending bst.Tree must delete nodes with their own
+// Delete method, using AscendDeleteFunc (as rbtree.Tree.AscendDelete does).
+//
+// Example Usage:
+//
+//	// delete the nodes holding expired values
+//	deleted := tree.AscendDelete(func(n *bst.Node[int, Session, struct{}]) bst.Action {
+//		if n.Value().Expired() {
+//			return bst.ActionDelete
+//		}
+//		return bst.ActionContinue
+//	})
+//
+// Returns:
+//   - The number of nodes deleted.
+func (t *Tree[K, V, M]) AscendDelete(f func(n *Node[K, V, M]) Action) int {
+	return AscendDeleteFunc(t, t.deleteNode, f)
+}
+
+// AscendDeleteFunc implements Tree.AscendDelete for trees extending bst.Tree, deleting nodes
+// with del (such as rbtree.Tree.Delete) rather than with Tree.Delete.
+//
+// del must relink nodes rather than moving keys and values between them, as the trees of this
+// module do, so that the node following a deleted node remains in the tree.
+//
+// Returns:
+//   - The number of nodes deleted, i.e. for which del returned true.
+func AscendDeleteFunc[K, V, M any](t *Tree[K, V, M], del func(n *Node[K, V, M]) bool, f func(n *Node[K, V, M]) Action) int {
+	count := 0
+	for n := t.Min(t.root); !t.IsNil(n); {
+		action := f(n)
+		next := t.Successor(n)
+		if action&ActionDelete != 0 && del(n) {
+			count++
+		}
+		if action&ActionStop != 0 {
+			break
+		}
+		n = next
+	}
+	return count
+}
@@ -0,0 +1,123 @@
+package bst
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestTree_AscendDelete(t *testing.T) {
+	tests := map[string]struct {
+		action        func(key int) Action
+		expectedCount int
+		expectedSeen  []int
+		expectedKeys  []int
+	}{
+		"delete none": {
+			action:        func(key int) Action { return ActionContinue },
+			expectedCount: 0,
+			expectedSeen:  []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+			expectedKeys:  []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		},
+		"delete all": {
+			action:        func(key int) Action { return ActionDelete },
+			expectedCount: 10,
+			expectedSeen:  []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+			expectedKeys:  nil,
+		},
+		"delete odd": {
+			action: func(key int) Action {
+				if key%2 == 1 {
+					return ActionDelete
+				}
+				return ActionContinue
+			},
+			expectedCount: 5,
+			expectedSeen:  []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+			expectedKeys:  []int{0, 2, 4, 6, 8},
+		},
+		"stop": {
+			action: func(key int) Action {
+				if key == 4 {
+					return ActionStop
+				}
+				return ActionDelete
+			},
+			expectedCount: 4,
+			expectedSeen:  []int{0, 1, 2, 3, 4},
+			expectedKeys:  []int{4, 5, 6, 7, 8, 9},
+		},
+		"delete and stop": {
+			action: func(key int) Action {
+				if key == 4 {
+					return ActionDelete | ActionStop
+				}
+				return ActionContinue
+			},
+			expectedCount: 1,
+			expectedSeen:  []int{0, 1, 2, 3, 4},
+			expectedKeys:  []int{0, 1, 2, 3, 5, 6, 7, 8, 9},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tree := New[int, struct{}, struct{}](func(a, b int) bool {
+				return a < b
+			})
+			for _, key := range []int{5, 2, 8, 0, 3, 7, 9, 1, 4, 6} {
+				tree.Insert(key, struct{}{})
+			}
+
+			var seen []int
+			count := tree.AscendDelete(func(n *Node[int, struct{}, struct{}]) Action {
+				seen = append(seen, n.Key())
+				return tc.action(n.Key())
+			})
+			assert.Equal(t, tc.expectedCount, count, "unexpected number of deleted nodes")
+			assert.Equal(t, tc.expectedSeen, seen, "unexpected nodes visited")
+			require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+
+			var keys []int
+			if !tree.IsNil(tree.Root()) {
+				tree.TraverseInOrder(tree.Root(), func(n *Node[int, struct{}, struct{}]) bool {
+					keys = append(keys, tree.Key(n))
+					return true
+				})
+			}
+			assert.Equal(t, tc.expectedKeys, keys, "unexpected keys remaining after AscendDelete")
+		})
+	}
+}
+
+func TestTree_AscendDelete_empty(t *testing.T) {
+	tree := New[int, struct{}, struct{}](func(a, b int) bool {
+		return a < b
+	})
+	count := tree.AscendDelete(func(n *Node[int, struct{}, struct{}]) Action {
+		t.Fatal("unexpected call on empty tree")
+		return ActionContinue
+	})
+	assert.Equal(t, 0, count)
+}
+
+func TestTree_AscendDelete_duplicates(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool {
+		return a < b
+	}, WithDuplicateKeys())
+	for i := 0; i < 30; i++ {
+		tree.Insert(i%3, i)
+	}
+
+	// delete every other node with key 1, by value
+	count := tree.AscendDelete(func(n *Node[int, int, struct{}]) Action {
+		if n.Key() == 1 && n.Value()%2 == 0 {
+			return ActionDelete
+		}
+		return ActionContinue
+	})
+	assert.Equal(t, 5, count)
+	assert.Equal(t, 25, tree.Size())
+	assert.Equal(t, 5, tree.Count(1))
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+}
@@ -0,0 +1,119 @@
+package bst
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+)
+
+// binaryMagic starts the version header of the binary encoding of a Tree. It cannot start data written
+// before the header was added, which is a gob stream, as gob never encodes a message length of 71 ('G')
+// in the long form starting with 0xff.
+var binaryMagic = []byte("\xffGTB")
+
+// These flags record which children a node has in the binary encoding of a Tree.
+const (
+	binaryFlagLeft  uint8 = 1 << iota // node has a left child
+	binaryFlagRight                   // node has a right child
+)
+
+// binaryNode is the binary representation of a single Node.
+type binaryNode[K, V, M any] struct {
+	Flags    uint8
+	Key      K
+	Value    V
+	Metadata M
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+//
+// The tree is encoded as a version header (see FormatVersion), followed by a compact encoding/gob stream: the number of nodes, followed by every
+// node in pre-order, each holding its key, value, metadata and flags recording whether it has a
+// left and/or right child. This preserves the exact shape of the tree, so that it can be restored
+// by Tree.UnmarshalBinary in linear time without rebalancing or key comparisons beyond validation.
+//
+// Keys, values and metadata must be encodable by encoding/gob.
+// The LessFunc and any options are not encoded.
+//
+// As Tree implements encoding.BinaryMarshaler, trees can also be encoded directly with encoding/gob.
+func (t *Tree[K, V, M]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(binaryMagic)
+	buf.Write(binary.AppendUvarint(nil, FormatVersion))
+	enc := gob.NewEncoder(&buf)
+	if err := enc.Encode(t.Size()); err != nil {
+		return nil, err
+	}
+	var err error
+	t.traversePreOrder(func(n *Node[K, V, M]) {
+		if err != nil {
+			return
+		}
+		bn := binaryNode[K, V, M]{
+			Key:      n.key,
+			Value:    n.value,
+			Metadata: n.metadata,
+		}
+		if !t.IsNil(n.left) {
+			bn.Flags |= binaryFlagLeft
+		}
+		if !t.IsNil(n.right) {
+			bn.Flags |= binaryFlagRight
+		}
+		err = enc.Encode(&bn)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, restoring a tree encoded by Tree.MarshalBinary.
+//
+// The tree must have been created with New, so that it has a LessFunc. Its existing contents
+// are replaced, and any handles to its previous nodes become stale (see Node.BelongsTo).
+//
+// Data written with an older FormatVersion is migrated first (see RegisterMigration), and data without
+// a version header is version 1. The restored tree is validated with Tree.IsTreeValid. If the data
+// cannot be decoded or describes an invalid tree, an error is returned and the tree is left unchanged.
+func (t *Tree[K, V, M]) UnmarshalBinary(data []byte) error {
+	if t.less == nil || t.nil == nil {
+		return fmt.Errorf("cannot unmarshal into a tree not created with New")
+	}
+
+	version := 1
+	if bytes.HasPrefix(data, binaryMagic) {
+		v, n := binary.Uvarint(data[len(binaryMagic):])
+		if n <= 0 {
+			return fmt.Errorf("invalid version header")
+		}
+		version, data = int(v), data[len(binaryMagic)+n:]
+	}
+	data, err := migrate(FormatBinary, version, FormatVersion, data)
+	if err != nil {
+		return err
+	}
+
+	dec := gob.NewDecoder(bytes.NewReader(data))
+	var count int
+	if err := dec.Decode(&count); err != nil {
+		return err
+	}
+	if count < 0 || count > len(data) {
+		return fmt.Errorf("invalid node count: %d", count)
+	}
+
+	b := t.newBuilder(count)
+	for i := 0; i < count; i++ {
+		var bn binaryNode[K, V, M] // gob omits zero values, so each node must be decoded into a zero value
+		if err := dec.Decode(&bn); err != nil {
+			return err
+		}
+		err := b.add(bn.Key, bn.Value, bn.Metadata, bn.Flags&binaryFlagLeft != 0, bn.Flags&binaryFlagRight != 0)
+		if err != nil {
+			return err
+		}
+	}
+	return b.commit()
+}
@@ -0,0 +1,101 @@
+package bst
+
+import (
+	"bytes"
+	"encoding/gob"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestTree_MarshalBinary(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	tree := New[int, string, int](less)
+	for _, key := range []int{50, 20, 80, 10, 30, 70, 90, 25, 35, 75, 91, 92, 93, 94, 95} {
+		n, _ := tree.Insert(key, "value")
+		tree.SetMetadata(n, -key)
+	}
+
+	data, err := tree.MarshalBinary()
+	require.NoError(t, err)
+
+	restored := New[int, string, int](less)
+	old, _ := restored.Insert(1, "old")
+	require.NoError(t, restored.UnmarshalBinary(data))
+	require.NoError(t, restored.IsTreeValid(), "expected valid tree")
+	assert.True(t, tree.EqualStructure(restored, func(a, b string) bool { return a == b }), "expected identical shape")
+	assert.Equal(t, tree.Size(), restored.Size(), "unexpected size")
+	assert.False(t, restored.Contains(old), "expected previous nodes to become stale")
+	restored.TraverseInOrder(restored.Root(), func(n *Node[int, string, int]) bool {
+		assert.Equal(t, -restored.Key(n), restored.Metadata(n), "expected metadata to be restored")
+		return true
+	})
+
+	// zero values are restored correctly
+	zeros := New[int, string, int](less)
+	zeros.Insert(0, "")
+	zeros.Insert(-1, "")
+	zeros.Insert(1, "one")
+	data, err = zeros.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, restored.UnmarshalBinary(data))
+	assert.True(t, zeros.EqualStructure(restored, func(a, b string) bool { return a == b }), "expected zero values to be restored")
+
+	// empty tree
+	data, err = New[int, string, int](less).MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, restored.UnmarshalBinary(data))
+	assert.True(t, restored.IsNil(restored.Root()), "expected empty tree")
+}
+
+func TestTree_MarshalBinary_gob(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	tree := New[int, struct{}, struct{}](less)
+	for i := 0; i < 100; i++ {
+		tree.Insert(i, struct{}{})
+	}
+
+	// Tree implements encoding.BinaryMarshaler, so can be embedded in gob streams
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(tree))
+	restored := New[int, struct{}, struct{}](less)
+	require.NoError(t, gob.NewDecoder(&buf).Decode(restored))
+	assert.True(t, tree.EqualStructure(restored, nil), "expected identical shape")
+}
+
+func TestTree_UnmarshalBinary_errors(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+
+	// encode builds a binary stream from a count and nodes
+	encode := func(count int, nodes ...binaryNode[int, string, int]) []byte {
+		var buf bytes.Buffer
+		enc := gob.NewEncoder(&buf)
+		require.NoError(t, enc.Encode(count))
+		for _, n := range nodes {
+			require.NoError(t, enc.Encode(&n))
+		}
+		return buf.Bytes()
+	}
+
+	tests := map[string][]byte{
+		"garbage":           []byte("not a tree"),
+		"negative count":    encode(-1),
+		"truncated":         encode(2, binaryNode[int, string, int]{Key: 2, Flags: binaryFlagLeft}),
+		"out of order keys": encode(2, binaryNode[int, string, int]{Key: 2, Flags: binaryFlagLeft}, binaryNode[int, string, int]{Key: 3}),
+		"missing child":     encode(2, binaryNode[int, string, int]{Key: 2, Flags: binaryFlagLeft | binaryFlagRight}, binaryNode[int, string, int]{Key: 1}),
+		"trailing node":     encode(2, binaryNode[int, string, int]{Key: 2}, binaryNode[int, string, int]{Key: 3}),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			tree := New[int, string, int](less)
+			n, _ := tree.Insert(1, "one")
+			assert.Error(t, tree.UnmarshalBinary(data), "expected error")
+			require.NoError(t, tree.IsTreeValid(), "expected tree to remain valid")
+			assert.Equal(t, 1, tree.Size(), "expected tree to be unchanged")
+			assert.True(t, tree.Contains(n), "expected existing node to remain")
+		})
+	}
+
+	var tree Tree[int, string, int]
+	assert.Error(t, tree.UnmarshalBinary(encode(0)), "expected error unmarshaling into zero tree")
+}
@@ -0,0 +1,108 @@
+package bst
+
+import "fmt"
+
+// builder reconstructs the exact shape of a tree from a pre-order sequence of nodes,
+// where each node records whether it has a left and/or right child.
+//
+// It is used by the serialization formats supported by Tree. Nodes are built without
+// recursion, so that degenerate (deep) trees can be restored safely.
+type builder[K, V, M any] struct {
+	t       *Tree[K, V, M]
+	root    *Node[K, V, M]
+	nodes   []*Node[K, V, M] // nodes in pre-order
+	pending []*Node[K, V, M] // nodes with a right child still to be added
+	parent  *Node[K, V, M]   // parent of the next node, or nil if the next node is the root
+	left    bool             // whether the next node is the left child of parent
+	done    bool             // whether the tree is complete
+}
+
+// newBuilder returns a builder for restoring the contents of t, expecting about n nodes.
+func (t *Tree[K, V, M]) newBuilder(n int) *builder[K, V, M] {
+	return &builder[K, V, M]{
+		t:     t,
+		root:  t.nil,
+		nodes: make([]*Node[K, V, M], 0, n),
+		done:  n == 0,
+	}
+}
+
+// add appends the next node in pre-order.
+func (b *builder[K, V, M]) add(key K, value V, metadata M, hasLeft, hasRight bool) error {
+	if b.done {
+		return fmt.Errorf("unexpected node after end of tree: %v", key)
+	}
+
+	n := b.t.newNode()
+	*n = Node[K, V, M]{
+		key:      key,
+		value:    value,
+		metadata: metadata,
+		parent:   b.t.nil,
+		left:     b.t.nil,
+		right:    b.t.nil,
+		gen:      n.gen,
+		tree:     b.t,
+	}
+	b.nodes = append(b.nodes, n)
+
+	// attach the node to its parent
+	if b.parent == nil {
+		b.root = n
+	} else {
+		n.parent = b.parent
+		if b.left {
+			b.parent.left = n
+		} else {
+			b.parent.right = n
+		}
+	}
+
+	// determine where the next node is attached
+	switch {
+	case hasLeft:
+		if hasRight {
+			b.pending = append(b.pending, n)
+		}
+		b.parent, b.left = n, true
+	case hasRight:
+		b.parent, b.left = n, false
+	case len(b.pending) > 0:
+		b.parent, b.left = b.pending[len(b.pending)-1], false
+		b.pending = b.pending[:len(b.pending)-1]
+	default:
+		b.done = true
+	}
+	return nil
+}
+
+// commit replaces the contents of the tree with the built nodes, provided the sequence of nodes was
+// complete and the resulting tree is valid. Otherwise, the tree is left unchanged and an error is returned.
+func (b *builder[K, V, M]) commit() error {
+	if !b.done {
+		return fmt.Errorf("unexpected end of tree after %d nodes", len(b.nodes))
+	}
+
+	// children follow their parents in pre-order, so augmenting in reverse pre-order is bottom-up
+	for i := len(b.nodes) - 1; i >= 0; i-- {
+		b.t.augment(b.nodes[i])
+	}
+
+	oldRoot := b.t.root
+	b.t.root = b.root
+	b.t.refreshBounds()
+	if err := b.t.IsTreeValid(); err != nil {
+		b.t.root = oldRoot
+		b.t.refreshBounds()
+		return fmt.Errorf("invalid tree: %w", err)
+	}
+	b.t.releaseSubtree(oldRoot)
+
+	var (
+		zeroK K
+		zeroV V
+	)
+	b.t.notify(ChangeClear, zeroK, zeroV)
+	b.t.notifySubtree(ChangeInsert, b.t, b.t.root)
+	return nil
+}
@@ -0,0 +1,155 @@
+package bst
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// minParallelLoad is the smallest subtree built on its own goroutine by Tree.LoadSortedFunc,
+// below which starting a goroutine costs more than it saves.
+const minParallelLoad = 1 << 14
+
+// LoadSorted replaces the contents of the tree with the given keys and values, building a perfectly
+// balanced tree in O(n) time on up to parallelism goroutines, rather than inserting the keys one by one.
+//
+// See Tree.LoadSortedFunc.
+//
+// Example Usage:
+//
+//	// build a tree of a million keys, using every CPU
+//	keys := make([]int, 1_000_000)
+//	for i := range keys {
+//		keys[i] = i
+//	}
+//	err := tree.LoadSorted(keys, nil, 0)
+func (t *Tree[K, V, M]) LoadSorted(keys []K, values []V, parallelism int) error {
+	return t.LoadSortedFunc(keys, values, parallelism, nil)
+}
+
+// LoadSortedFunc replaces the contents of the tree with the given keys and values, building a perfectly
+// balanced tree in O(n) time, rather than inserting the keys one by one in O(n log n) time.
+//
+// The tree is split into subtrees, built on up to parallelism goroutines, or runtime.GOMAXPROCS(0)
+// goroutines if parallelism is 0, then joined under their common ancestors. Subtrees of fewer than
+// 16384 nodes are built sequentially. Once built, every level of the tree is full, except possibly the
+// deepest, so its height is ⌊log2(n)⌋.
+//
+// This is the bulk-loading primitive used by extensions such as rbtree.Tree: init, if not nil, is
+// called on every new node, with its depth (the root having a depth of 0), before its augmented data
+// is computed, e.g. to set its metadata. init and the registered AugmentFunc, if any, are called
+// concurrently on different nodes, so must only access the node they are given (and its children,
+// for the AugmentFunc). The replaced nodes are released, and the function registered with
+// Tree.OnChange, if any, is notified of ChangeClear followed by the insertion of every key in order.
+//
+// Parameters:
+//   - keys: The keys, in ascending order according to the tree's LessFunc, without duplicates unless
+//     duplicate keys are enabled (see WithDuplicateKeys). keys is not modified.
+//   - values: The values of the keys, or nil for zero values.
+//   - parallelism: The maximum number of goroutines building the tree, or 0 for runtime.GOMAXPROCS(0).
+//   - init: A function called on every new node with its depth, or nil.
+//
+// Returns:
+//   - nil if the tree was loaded.
+//   - An error if the keys are out of order, or values and keys differ in length. The tree is then
+//     left unchanged.
+//
+// LoadSortedFunc panics if parallelism is negative.
+func (t *Tree[K, V, M]) LoadSortedFunc(keys []K, values []V, parallelism int, init func(n *Node[K, V, M], depth int)) error {
+	if parallelism < 0 {
+		panic(fmt.Sprintf("bst: invalid parallelism %d", parallelism))
+	}
+	if parallelism == 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+	if values != nil && len(values) != len(keys) {
+		return fmt.Errorf("%d values for %d keys", len(values), len(keys))
+	}
+	for i := 1; i < len(keys); i++ {
+		if t.less(keys[i], keys[i-1]) || (!t.duplicates && !t.less(keys[i-1], keys[i])) {
+			return fmt.Errorf("key %v at index %d out of order", keys[i], i)
+		}
+	}
+
+	// release the replaced nodes first, so that the allocator can reuse them
+	oldRoot := t.root
+	t.root = t.nil
+	t.refreshBounds()
+	t.releaseSubtree(oldRoot)
+
+	// allocators are not safe for concurrent use, so their nodes are allocated up front
+	l := &loader[K, V, M]{t: t, keys: keys, values: values, init: init}
+	if t.alloc != nil {
+		l.nodes = make([]*Node[K, V, M], len(keys))
+		for i := range l.nodes {
+			l.nodes[i] = t.alloc.New()
+		}
+	}
+	t.augmenting = true
+	t.root = l.build(0, len(keys), t.nil, 0, parallelism)
+	t.refreshBounds()
+	t.augmenting = false
+
+	var (
+		zeroK K
+		zeroV V
+	)
+	t.notify(ChangeClear, zeroK, zeroV)
+	t.notifySubtree(ChangeInsert, t, t.root)
+	return nil
+}
+
+// loader builds a perfectly balanced tree from sorted keys and values, for Tree.LoadSortedFunc.
+type loader[K, V, M any] struct {
+	t      *Tree[K, V, M]
+	keys   []K
+	values []V                               // values of the keys, or nil for zero values
+	nodes  []*Node[K, V, M]                  // nodes allocated up front for each key, or nil to allocate with new
+	init   func(n *Node[K, V, M], depth int) // called on every new node, if not nil
+}
+
+// build links the keys in [lo, hi) into a perfectly balanced subtree with the given parent, whose root
+// is at the given depth, on up to parallelism goroutines, and returns the root of the subtree.
+//
+// The recursion depth is O(log n), where n is the number of keys.
+func (l *loader[K, V, M]) build(lo, hi int, parent *Node[K, V, M], depth, parallelism int) *Node[K, V, M] {
+	t := l.t
+	if lo == hi {
+		return t.nil
+	}
+	mid := lo + (hi-lo)/2
+	var n *Node[K, V, M]
+	if l.nodes != nil {
+		n = l.nodes[mid]
+	} else {
+		n = &Node[K, V, M]{}
+	}
+	n.key, n.parent, n.tree = l.keys[mid], parent, t
+	if l.values != nil {
+		n.value = l.values[mid]
+	}
+
+	// build the left subtree on another goroutine, sharing the goroutines between the subtrees
+	if parallelism > 1 && hi-lo > minParallelLoad {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n.left = l.build(lo, mid, n, depth+1, parallelism/2)
+		}()
+		n.right = l.build(mid+1, hi, n, depth+1, parallelism-parallelism/2)
+		wg.Wait()
+	} else {
+		n.left = l.build(lo, mid, n, depth+1, 1)
+		n.right = l.build(mid+1, hi, n, depth+1, 1)
+	}
+
+	if l.init != nil {
+		l.init(n, depth)
+	}
+	n.size = n.left.size + n.right.size + 1
+	if t.augmentFunc != nil {
+		t.augmentFunc(n)
+	}
+	return n
+}
@@ -0,0 +1,117 @@
+package bst
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/bits"
+	"testing"
+)
+
+func TestTree_LoadSorted(t *testing.T) {
+	for _, size := range []int{0, 1, 2, 3, 7, 8, 1000, 100000} {
+		for _, parallelism := range []int{1, 4} {
+			tree := New[int, int, int](func(a, b int) bool { return a < b })
+			tree.SetAugmentFunc(func(n *Node[int, int, int]) {
+				n.metadata = n.value + n.left.metadata + n.right.metadata
+			})
+			tree.Insert(-1, -1) // replaced by the loaded keys
+			keys, values := make([]int, size), make([]int, size)
+			for i := range keys {
+				keys[i], values[i] = i, i
+			}
+
+			require.NoError(t, tree.LoadSorted(keys, values, parallelism))
+			require.NoError(t, tree.IsTreeValid(), "expected valid tree with %d nodes", size)
+			assert.Equal(t, size, tree.Size())
+			assert.Equal(t, bits.Len(uint(size))-1, maxDepth(tree), "unexpected height with %d nodes", size)
+			assert.Equal(t, size*(size-1)/2, tree.Metadata(tree.Root()), "expected augmented data to be computed")
+			if size > 0 {
+				assert.Equal(t, 0, tree.Key(tree.Min(tree.Root())))
+				assert.Equal(t, size-1, tree.Key(tree.Max(tree.Root())))
+				n, found := tree.Search(size / 3)
+				require.True(t, found)
+				assert.Equal(t, size/3, tree.Value(n))
+			}
+		}
+	}
+}
+
+func TestTree_LoadSortedFunc(t *testing.T) {
+	tree := New[int, struct{}, int](func(a, b int) bool { return a < b })
+	keys := make([]int, 50000)
+	for i := range keys {
+		keys[i] = i
+	}
+	require.NoError(t, tree.LoadSortedFunc(keys, nil, 4, func(n *Node[int, struct{}, int], depth int) {
+		n.metadata = depth
+	}))
+	for n := tree.Min(tree.Root()); !tree.IsNil(n); n = tree.Successor(n) {
+		require.Equal(t, tree.Depth(n), tree.Metadata(n), "expected init to be called with the depth of %d", tree.Key(n))
+	}
+}
+
+func TestTree_LoadSorted_options(t *testing.T) {
+	t.Run("duplicates", func(t *testing.T) {
+		tree := New[int, string, struct{}](func(a, b int) bool { return a < b }, WithDuplicateKeys())
+		require.NoError(t, tree.LoadSorted([]int{1, 1, 2, 2, 2}, []string{"a", "b", "c", "d", "e"}, 0))
+		require.NoError(t, tree.IsTreeValid())
+		assert.Equal(t, 5, tree.Size())
+		var values []string
+		tree.TraverseInOrder(tree.Root(), func(n *Node[int, string, struct{}]) bool {
+			values = append(values, tree.Value(n))
+			return true
+		})
+		assert.Equal(t, []string{"a", "b", "c", "d", "e"}, values)
+	})
+
+	t.Run("reverse", func(t *testing.T) {
+		tree := New[int, string, struct{}](func(a, b int) bool { return a < b }, WithReverseOrder())
+		assert.Error(t, tree.LoadSorted([]int{1, 2, 3}, nil, 0))
+		require.NoError(t, tree.LoadSorted([]int{3, 2, 1}, nil, 0))
+		require.NoError(t, tree.IsTreeValid())
+		assert.Equal(t, 3, tree.Key(tree.Min(tree.Root())))
+	})
+
+	t.Run("allocator", func(t *testing.T) {
+		arena := NewArena[int, int, struct{}](1024)
+		tree := New[int, int, struct{}](func(a, b int) bool { return a < b }, WithAllocator(arena))
+		keys := make([]int, 40000)
+		for i := range keys {
+			keys[i] = i
+		}
+		require.NoError(t, tree.LoadSorted(keys, nil, 4))
+		require.NoError(t, tree.IsTreeValid())
+		assert.Equal(t, 40, arena.Blocks(), "expected nodes to be allocated by the arena")
+
+		// the replaced nodes are reused
+		require.NoError(t, tree.LoadSorted(keys, nil, 4))
+		assert.Equal(t, 40, arena.Blocks(), "expected the replaced nodes to be reused")
+	})
+}
+
+func TestTree_LoadSorted_errors(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	old, _ := tree.Insert(1, 1)
+
+	assert.Error(t, tree.LoadSorted([]int{1, 3, 2}, nil, 0), "expected unsorted keys to be rejected")
+	assert.Error(t, tree.LoadSorted([]int{1, 2, 2}, nil, 0), "expected duplicate keys to be rejected")
+	assert.Error(t, tree.LoadSorted([]int{1, 2}, []int{1}, 0), "expected missing values to be rejected")
+	assert.True(t, tree.Contains(old), "expected the tree to be unchanged")
+	assert.Equal(t, 1, tree.Size())
+	assert.Panics(t, func() { _ = tree.LoadSorted(nil, nil, -1) })
+}
+
+func TestTree_LoadSorted_OnChange(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	old, _ := tree.Insert(0, 0)
+	var ops []ChangeOp
+	var keys []int
+	tree.OnChange(func(op ChangeOp, key int, value int) {
+		ops = append(ops, op)
+		keys = append(keys, key)
+	})
+	require.NoError(t, tree.LoadSorted([]int{1, 2, 3}, nil, 0))
+	assert.Equal(t, []ChangeOp{ChangeClear, ChangeInsert, ChangeInsert, ChangeInsert}, ops)
+	assert.Equal(t, []int{0, 1, 2, 3}, keys)
+	assert.False(t, old.BelongsTo(tree), "expected the replaced nodes to be released")
+}
@@ -0,0 +1,108 @@
+package bst
+
+// ChangeOp identifies the kind of change notified to a ChangeFunc.
+type ChangeOp uint8
+
+const (
+	ChangeInsert ChangeOp = iota // a new key was inserted
+	ChangeUpdate                 // the value of an existing key was updated
+	ChangeDelete                 // a key was deleted
+	ChangeClear                  // every key was removed at once, by Tree.Clear or when unmarshaling
+)
+
+// String returns the name of the change, such as "insert".
+func (op ChangeOp) String() string {
+	switch op {
+	case ChangeInsert:
+		return "insert"
+	case ChangeUpdate:
+		return "update"
+	case ChangeDelete:
+		return "delete"
+	case ChangeClear:
+		return "clear"
+	}
+	return "unknown"
+}
+
+// ChangeFunc defines a function type notified of each change made to a tree. See Tree.OnChange.
+//
+// Parameters:
+//   - op: The kind of change.
+//   - key: The key inserted, updated or deleted, or the zero value for ChangeClear.
+//   - value: The new value of the key, its value when deleted, or the zero value for ChangeClear.
+type ChangeFunc[K, V any] func(op ChangeOp, key K, value V)
+
+// OnChange registers a function notified after each successful change to the tree, so that
+// applications can mirror the tree (e.g., into a cache) without funneling every change through a wrapper.
+// Passing nil disables notifications. Only one function can be registered: OnChange replaces any
+// previously registered function.
+//
+// Changes are notified as follows:
+//   - Tree.Insert notifies ChangeInsert for a new key, and ChangeUpdate for an existing key.
+//   - Tree.SetValue notifies ChangeUpdate.
+//   - Tree.UpdateKey notifies ChangeDelete for the old key, followed by ChangeInsert for the new key.
+//   - Deleting a node, with Tree.Delete or the Delete method of a tree extending bst.Tree (as every node
+//     removed is released, see Tree.Release), notifies ChangeDelete.
+//   - Tree.Clear notifies ChangeClear, as does unmarshaling into the tree, followed by ChangeInsert for
+//     each key unmarshaled.
+//   - Tree.DetachSubtree and Tree.AttachSubtree notify ChangeDelete or ChangeInsert for each key moved,
+//     in ascending order.
+//
+// f is called once the tree has changed, but possibly before a self-balancing tree extending bst.Tree
+// has finished rebalancing, so f must not modify the tree.
+//
+// Example Usage:
+//
+//	// mirror the tree into a map
+//	mirror := make(map[int]string)
+//	tree.OnChange(func(op bst.ChangeOp, key int, value string) {
+//		switch op {
+//		case bst.ChangeInsert, bst.ChangeUpdate:
+//			mirror[key] = value
+//		case bst.ChangeDelete:
+//			delete(mirror, key)
+//		case bst.ChangeClear:
+//			clear(mirror)
+//		}
+//	})
+func (t *Tree[K, V, M]) OnChange(f ChangeFunc[K, V]) {
+	t.onChange = f
+}
+
+// notify records a change to the tree: it increments the version of the tree, records the change
+// in the delta log, if enabled, and calls the registered ChangeFunc, if any.
+func (t *Tree[K, V, M]) notify(op ChangeOp, key K, value V) {
+	t.version++
+	if op == ChangeClear {
+		t.snaps.reset()
+	}
+	if t.deltaLimit > 0 {
+		t.logChange(Change[K, V]{Op: op, Key: key, Value: value})
+	}
+	if t.onChange != nil {
+		t.onChange(op, key, value)
+	}
+}
+
+// notifySubtree records a change of op for each node of the subtree rooted at n (see Tree.notify),
+// in ascending key order, without recursion. The nodes belong to tree holder, which may differ from t.
+//
+// If the changes are neither logged nor observed, the version is incremented once, without visiting the nodes.
+func (t *Tree[K, V, M]) notifySubtree(op ChangeOp, holder *Tree[K, V, M], n *Node[K, V, M]) {
+	t.snaps.reset()
+	if t.onChange == nil && t.deltaLimit == 0 {
+		t.version++
+		return
+	}
+	var stack []*Node[K, V, M]
+	for !holder.IsNil(n) || len(stack) > 0 {
+		for ; !holder.IsNil(n); n = n.left {
+			stack = append(stack, n)
+		}
+		n = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		t.notify(op, n.key, n.value)
+		n = n.right
+	}
+}
@@ -0,0 +1,95 @@
+package bst
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+// contents returns the keys and values of tree as a map.
+func contents(tree *Tree[int, int, struct{}]) map[int]int {
+	m := make(map[int]int)
+	for n := tree.Min(tree.Root()); !tree.IsNil(n); n = tree.Successor(n) {
+		m[n.key] = n.value
+	}
+	return m
+}
+
+func TestTree_OnChange(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	mirror := make(map[int]int)
+	counts := make(map[ChangeOp]int)
+	tree.OnChange(func(op ChangeOp, key int, value int) {
+		counts[op]++
+		switch op {
+		case ChangeInsert:
+			_, exists := mirror[key]
+			require.False(t, exists, "unexpected insert of existing key %d", key)
+			mirror[key] = value
+		case ChangeUpdate:
+			_, exists := mirror[key]
+			require.True(t, exists, "unexpected update of missing key %d", key)
+			mirror[key] = value
+		case ChangeDelete:
+			require.Equal(t, mirror[key], value, "unexpected value of deleted key %d", key)
+			delete(mirror, key)
+		case ChangeClear:
+			clear(mirror)
+		}
+	})
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		key := rng.Intn(100)
+		switch rng.Intn(4) {
+		case 0:
+			n, _ := tree.Search(key)
+			tree.Delete(n)
+		case 1:
+			if n, found := tree.Search(key); found {
+				tree.SetValue(n, -i)
+			}
+		default:
+			tree.Insert(key, i)
+		}
+	}
+	assert.Equal(t, contents(tree), mirror)
+	assert.Positive(t, counts[ChangeInsert])
+	assert.Positive(t, counts[ChangeUpdate])
+	assert.Positive(t, counts[ChangeDelete])
+
+	// subtrees moved out of and into the tree
+	sub, ok := tree.DetachSubtree(tree.Root().left)
+	require.True(t, ok)
+	assert.Equal(t, contents(tree), mirror)
+	require.NoError(t, tree.AttachSubtree(sub))
+	assert.Equal(t, contents(tree), mirror)
+
+	// unmarshaling replaces the contents
+	other := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	for i := 0; i < 10; i++ {
+		other.Insert(i*1000, i)
+	}
+	data, err := other.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, tree.UnmarshalBinary(data))
+	assert.Equal(t, contents(other), mirror)
+
+	tree.Clear()
+	assert.Empty(t, mirror)
+	assert.Equal(t, 2, counts[ChangeClear])
+
+	// notifications can be disabled
+	tree.OnChange(nil)
+	tree.Insert(1, 1)
+	assert.Empty(t, mirror)
+}
+
+func TestChangeOp_String(t *testing.T) {
+	assert.Equal(t, "insert", ChangeInsert.String())
+	assert.Equal(t, "update", ChangeUpdate.String())
+	assert.Equal(t, "delete", ChangeDelete.String())
+	assert.Equal(t, "clear", ChangeClear.String())
+	assert.Equal(t, "unknown", ChangeOp(42).String())
+}
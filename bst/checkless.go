@@ -0,0 +1,62 @@
+package bst
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidLessFunc is wrapped by the errors returned by CheckLess.
+var ErrInvalidLessFunc = errors.New("invalid LessFunc")
+
+// CheckLess verifies that less defines a strict weak ordering over the given sample keys,
+// as required by Tree (see LessFunc). It detects comparators that are subtly broken, such as
+// comparing float64 keys with < when NaN may occur, or comparing structs on fields that are
+// not ordered consistently.
+//
+// For all keys a, b and c in samples, CheckLess verifies:
+//   - Irreflexivity: less(a, a) is false.
+//   - Asymmetry: if less(a, b), then less(b, a) is false.
+//   - Transitivity: if less(a, b) and less(b, c), then less(a, c).
+//   - Transitivity of equivalence: if a and b are equivalent (neither is less than the other),
+//     and b and c are equivalent, then a and c are equivalent.
+//
+// CheckLess can only find violations among the samples given, so samples should include edge
+// cases (e.g. NaN, zero values, equal keys). It makes O(n³) calls to less for n samples.
+//
+// Returns:
+//   - nil if no violation was found.
+//   - An error wrapping ErrInvalidLessFunc, describing the first violation found otherwise.
+func CheckLess[K any](less LessFunc[K], samples []K) error {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s", ErrInvalidLessFunc, fmt.Sprintf(format, args...))
+	}
+	equivalent := func(a, b K) bool {
+		return !less(a, b) && !less(b, a)
+	}
+
+	for _, a := range samples {
+		if less(a, a) {
+			return invalid("not irreflexive: less(%v, %v) is true", a, a)
+		}
+	}
+	for _, a := range samples {
+		for _, b := range samples {
+			if less(a, b) && less(b, a) {
+				return invalid("not asymmetric: less(%v, %v) and less(%v, %v) are both true", a, b, b, a)
+			}
+		}
+	}
+	for _, a := range samples {
+		for _, b := range samples {
+			for _, c := range samples {
+				if less(a, b) && less(b, c) && !less(a, c) {
+					return invalid("not transitive: less(%v, %v) and less(%v, %v) are true, but less(%v, %v) is false", a, b, b, c, a, c)
+				}
+				if equivalent(a, b) && equivalent(b, c) && !equivalent(a, c) {
+					return invalid("equivalence not transitive: %v is equivalent to %v, and %v to %v, but %v is not equivalent to %v", a, b, b, c, a, c)
+				}
+			}
+		}
+	}
+	return nil
+}
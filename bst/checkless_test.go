@@ -0,0 +1,55 @@
+package bst
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestCheckLess(t *testing.T) {
+	assert.NoError(t, CheckLess(func(a, b int) bool { return a < b }, []int{3, 1, 2, 2, -5, 0}))
+	assert.NoError(t, CheckLess(func(a, b int) bool { return a < b }, nil))
+
+	// keys equivalent by their absolute value
+	abs := func(a int) int { return max(a, -a) }
+	assert.NoError(t, CheckLess(func(a, b int) bool { return abs(a) < abs(b) }, []int{-2, -1, 0, 1, 2}))
+
+	tests := map[string]struct {
+		less     func(a, b int) bool
+		samples  []int
+		expected string
+	}{
+		"reflexive": {
+			less:     func(a, b int) bool { return a <= b },
+			samples:  []int{1, 2},
+			expected: "invalid LessFunc: not irreflexive: less(1, 1) is true",
+		},
+		"symmetric": {
+			less:     func(a, b int) bool { return a != b },
+			samples:  []int{1, 2},
+			expected: "invalid LessFunc: not asymmetric: less(1, 2) and less(2, 1) are both true",
+		},
+		"rock paper scissors": {
+			less:     func(a, b int) bool { return (a+1)%3 == b },
+			samples:  []int{0, 1, 2},
+			expected: "invalid LessFunc: not transitive: less(0, 1) and less(1, 2) are true, but less(0, 2) is false",
+		},
+		"equivalence within a distance": {
+			less:     func(a, b int) bool { return a < b-1 },
+			samples:  []int{1, 2, 3},
+			expected: "invalid LessFunc: equivalence not transitive: 1 is equivalent to 2, and 2 to 3, but 1 is not equivalent to 3",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := CheckLess(tc.less, tc.samples)
+			assert.ErrorIs(t, err, ErrInvalidLessFunc)
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+
+	// NaN is equivalent to every float64 under <
+	err := CheckLess(func(a, b float64) bool { return a < b }, []float64{1, math.NaN(), 2})
+	assert.ErrorIs(t, err, ErrInvalidLessFunc)
+	assert.ErrorContains(t, err, "equivalence not transitive")
+}
@@ -0,0 +1,114 @@
+package bst
+
+import "fmt"
+
+// MarshalFunc encodes v, such as cbor.Marshal (github.com/fxamacker/cbor/v2)
+// or msgpack.Marshal (github.com/vmihailenco/msgpack/v5).
+type MarshalFunc func(v any) ([]byte, error)
+
+// UnmarshalFunc decodes data into v, such as cbor.Unmarshal (github.com/fxamacker/cbor/v2)
+// or msgpack.Unmarshal (github.com/vmihailenco/msgpack/v5).
+type UnmarshalFunc func(data []byte, v any) error
+
+// codecTree is the representation of a Tree encoded by Tree.MarshalWith.
+//
+// The blank field carries the CBOR option, and _msgpack the MessagePack option, as each library only
+// reads struct options from the field of that name.
+type codecTree[K, V, M any] struct {
+	_        struct{}             `cbor:",toarray"`
+	_msgpack struct{}             `msgpack:",as_array"`
+	Version  int                  // FormatVersion the tree was written with, or 0 if not recorded
+	Nodes    []codecNode[K, V, M] // nodes in pre-order
+}
+
+// codecNode is the representation of a single Node encoded by Tree.MarshalWith.
+//
+// Nodes are encoded as arrays rather than maps, so that field names are not repeated for every node,
+// and Flags records whether the node has a left and/or right child (see binaryFlagLeft and binaryFlagRight).
+type codecNode[K, V, M any] struct {
+	_        struct{} `cbor:",toarray"`
+	_msgpack struct{} `msgpack:",as_array"`
+	Key      K
+	Value    V
+	Metadata M
+	Flags    uint8
+}
+
+// MarshalWith encodes the tree with marshal, which is typically the Marshal function of a CBOR or
+// MessagePack library, so that trees can be stored in the same format as the rest of an application's
+// data without this package depending on that library:
+//
+//	data, err := tree.MarshalWith(msgpack.Marshal)
+//
+// As with Tree.MarshalJSON, the tree is encoded as its version (see FormatVersion) and a pre-order list of its nodes, each holding its key,
+// value, metadata and flags recording whether it has a left and/or right child. This preserves the exact
+// shape of the tree, so that it can be restored by Tree.UnmarshalWith without rebuilding it by insertion.
+// The list and its nodes are tagged to be encoded as CBOR and MessagePack arrays rather than maps,
+// which keeps the payload compact.
+//
+// Keys, values and metadata must be encodable by marshal. The LessFunc and any options are not encoded.
+//
+// Returns:
+//   - (data, nil) if the tree was encoded.
+//   - (nil, error) if marshal fails.
+func (t *Tree[K, V, M]) MarshalWith(marshal MarshalFunc) ([]byte, error) {
+	ct := codecTree[K, V, M]{
+		Version: FormatVersion,
+		Nodes:   make([]codecNode[K, V, M], 0, t.Size()),
+	}
+	t.traversePreOrder(func(n *Node[K, V, M]) {
+		cn := codecNode[K, V, M]{
+			Key:      n.key,
+			Value:    n.value,
+			Metadata: n.metadata,
+		}
+		if !t.IsNil(n.left) {
+			cn.Flags |= binaryFlagLeft
+		}
+		if !t.IsNil(n.right) {
+			cn.Flags |= binaryFlagRight
+		}
+		ct.Nodes = append(ct.Nodes, cn)
+	})
+	return marshal(ct)
+}
+
+// UnmarshalWith restores a tree encoded by Tree.MarshalWith, decoding data with unmarshal, which must
+// match the marshal function used to encode it:
+//
+//	err := tree.UnmarshalWith(data, msgpack.Unmarshal)
+//
+// The tree must have been created with New, so that it has a LessFunc. Its existing contents
+// are replaced, and any handles to its previous nodes become stale (see Node.BelongsTo).
+// Data written with an older FormatVersion is migrated first (see RegisterMigration).
+//
+// The restored tree is validated with Tree.IsTreeValid. If the data cannot be decoded
+// or describes an invalid tree, an error is returned and the tree is left unchanged.
+func (t *Tree[K, V, M]) UnmarshalWith(data []byte, unmarshal UnmarshalFunc) error {
+	if t.less == nil || t.nil == nil {
+		return fmt.Errorf("cannot unmarshal into a tree not created with New")
+	}
+
+	var ct codecTree[K, V, M]
+	if err := unmarshal(data, &ct); err != nil {
+		return err
+	}
+	if version := max(ct.Version, 1); version != FormatVersion { // data without a version is version 1
+		migrated, err := migrate(FormatCodec, version, FormatVersion, data)
+		if err != nil {
+			return err
+		}
+		ct = codecTree[K, V, M]{}
+		if err := unmarshal(migrated, &ct); err != nil {
+			return err
+		}
+	}
+
+	b := t.newBuilder(len(ct.Nodes))
+	for _, cn := range ct.Nodes {
+		if err := b.add(cn.Key, cn.Value, cn.Metadata, cn.Flags&binaryFlagLeft != 0, cn.Flags&binaryFlagRight != 0); err != nil {
+			return err
+		}
+	}
+	return b.commit()
+}
@@ -0,0 +1,76 @@
+package bst
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestTree_MarshalWith(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+
+	// build a degenerate tree, plus some branching, to check the shape is preserved
+	tree := New[int, string, int](less)
+	for _, key := range []int{50, 20, 80, 10, 30, 70, 90, 25, 35, 75, 91, 92, 93, 94, 95} {
+		n, _ := tree.Insert(key, "value")
+		tree.SetMetadata(n, -key)
+	}
+
+	// the marshal function is given the pre-order list of nodes
+	var encoded codecTree[int, string, int]
+	_, err := tree.MarshalWith(func(v any) ([]byte, error) {
+		encoded = v.(codecTree[int, string, int])
+		return nil, nil
+	})
+	require.NoError(t, err)
+	require.Len(t, encoded.Nodes, tree.Size())
+	assert.Equal(t, codecNode[int, string, int]{Key: 50, Value: "value", Metadata: -50, Flags: binaryFlagLeft | binaryFlagRight}, encoded.Nodes[0])
+
+	data, err := tree.MarshalWith(json.Marshal)
+	require.NoError(t, err)
+	restored := New[int, string, int](less)
+	old, _ := restored.Insert(1, "old")
+	require.NoError(t, restored.UnmarshalWith(data, json.Unmarshal))
+	require.NoError(t, restored.IsTreeValid(), "expected valid tree")
+	assert.True(t, tree.EqualStructure(restored, func(a, b string) bool { return a == b }), "expected identical shape")
+	assert.False(t, restored.Contains(old), "expected previous nodes to become stale")
+	restored.TraverseInOrder(restored.Root(), func(n *Node[int, string, int]) bool {
+		assert.Equal(t, -restored.Key(n), restored.Metadata(n), "expected metadata to be restored")
+		return true
+	})
+
+	// round trip of an empty tree
+	data, err = New[int, string, int](less).MarshalWith(json.Marshal)
+	require.NoError(t, err)
+	require.NoError(t, restored.UnmarshalWith(data, json.Unmarshal))
+	assert.True(t, restored.IsNil(restored.Root()), "expected empty tree")
+
+	// errors from the marshal function are returned
+	failure := errors.New("failure")
+	_, err = tree.MarshalWith(func(v any) ([]byte, error) { return nil, failure })
+	assert.ErrorIs(t, err, failure)
+}
+
+func TestTree_UnmarshalWith_errors(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	tree := New[int, string, int](less)
+	tree.Insert(1, "one")
+
+	tests := map[string]string{
+		"malformed data":    `{"Nodes":[`,
+		"out of order keys": `{"Nodes":[{"Key":2,"Flags":1},{"Key":3}]}`,
+		"missing child":     `{"Nodes":[{"Key":2,"Flags":3},{"Key":1}]}`,
+		"trailing node":     `{"Nodes":[{"Key":2},{"Key":3}]}`,
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, tree.UnmarshalWith([]byte(data), json.Unmarshal))
+			require.Equal(t, 1, tree.Size(), "expected tree to be left unchanged")
+		})
+	}
+
+	var zero Tree[int, string, int]
+	assert.Error(t, zero.UnmarshalWith([]byte(`{"Nodes":[]}`), json.Unmarshal), "expected error for a tree not created with New")
+}
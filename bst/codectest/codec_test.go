@@ -0,0 +1,59 @@
+package codectest
+
+import (
+	"github.com/fxamacker/cbor/v2"
+	"github.com/mikenye/gotrees/bst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+	"testing"
+)
+
+func TestTree_MarshalWith(t *testing.T) {
+	for name, c := range map[string]struct {
+		marshal   bst.MarshalFunc
+		unmarshal bst.UnmarshalFunc
+	}{
+		"cbor":    {cbor.Marshal, cbor.Unmarshal},
+		"msgpack": {msgpack.Marshal, msgpack.Unmarshal},
+	} {
+		t.Run(name, func(t *testing.T) {
+			less := func(a, b int) bool { return a < b }
+			tree := bst.New[int, string, int](less)
+			for _, key := range []int{50, 20, 80, 10, 30, 70, 90, 25, 35, 75, 91, 92, 93, 94, 95} {
+				n, _ := tree.Insert(key, "value")
+				tree.SetMetadata(n, -key)
+			}
+
+			data, err := tree.MarshalWith(c.marshal)
+			require.NoError(t, err)
+			restored := bst.New[int, string, int](less)
+			require.NoError(t, restored.UnmarshalWith(data, c.unmarshal))
+			require.NoError(t, restored.IsTreeValid(), "expected valid tree")
+			assert.True(t, tree.EqualStructure(restored, func(a, b string) bool { return a == b }), "expected identical shape")
+			restored.TraverseInOrder(restored.Root(), func(n *bst.Node[int, string, int]) bool {
+				assert.Equal(t, -restored.Key(n), restored.Metadata(n), "expected metadata to be restored")
+				return true
+			})
+
+			// the tree and its nodes are encoded as arrays, not maps
+			var decoded any
+			require.NoError(t, c.unmarshal(data, &decoded))
+			fields, ok := decoded.([]any)
+			require.True(t, ok, "expected the tree to be encoded as an array, got %T", decoded)
+			require.Len(t, fields, 2, "expected the version and the nodes")
+			nodes, ok := fields[1].([]any)
+			require.True(t, ok, "expected a list of nodes, got %T", fields[1])
+			require.Len(t, nodes, tree.Size())
+			node, ok := nodes[0].([]any)
+			require.True(t, ok, "expected the nodes to be encoded as arrays, got %T", nodes[0])
+			assert.Len(t, node, 4, "expected the key, value, metadata and flags")
+
+			// round trip of an empty tree
+			data, err = bst.New[int, string, int](less).MarshalWith(c.marshal)
+			require.NoError(t, err)
+			require.NoError(t, restored.UnmarshalWith(data, c.unmarshal))
+			assert.True(t, restored.IsNil(restored.Root()), "expected empty tree")
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package codectest tests the encoding of trees by bst.Tree.MarshalWith with CBOR and MessagePack
+// libraries. It is a separate module, so that the gotrees module itself does not depend on them.
+package codectest
@@ -0,0 +1,20 @@
+module github.com/mikenye/gotrees/bst/codectest
+
+go 1.24.0
+
+require (
+	github.com/fxamacker/cbor/v2 v2.7.0
+	github.com/mikenye/gotrees v0.0.0
+	github.com/stretchr/testify v1.10.0
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/mikenye/gotrees => ../..
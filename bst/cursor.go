@@ -0,0 +1,159 @@
+package bst
+
+// cursorPosition is the position of a Cursor relative to the nodes of its tree.
+type cursorPosition int
+
+const (
+	cursorStart cursorPosition = iota // before the first node
+	cursorNode                        // at a node (which may since have been deleted)
+	cursorEnd                         // after the last node
+)
+
+// Cursor is a bidirectional iterator over the nodes of a tree, in order, which remains usable
+// while the tree is modified.
+//
+// A cursor is positioned before the first node, at a node, or after the last node. Unlike a loop
+// over Tree.Successor, a cursor has well-defined behavior when the tree changes during iteration:
+//   - If the current node is deleted, Cursor.Next moves to the first node with a key greater than the
+//     deleted key, and Cursor.Prev to the last node with a key less than it. The deletion can be
+//     made with the Delete method of the tree (or of a tree extending bst.Tree, such as rbtree.Tree).
+//   - Nodes inserted after the current node (in the tree's order) are visited by later calls to
+//     Cursor.Next, and nodes inserted before it are not.
+//   - Rotations and other rebalancing performed by trees extending bst.Tree do not affect the cursor,
+//     provided they relink nodes rather than moving keys between nodes.
+//
+// In multiset mode (see WithDuplicateKeys), the nodes with a key equal to a deleted current node
+// are skipped when moving on, as the cursor cannot tell which of them it had already visited.
+//
+// Cursors are created with Tree.Cursor.
+type Cursor[K, V, M any] struct {
+	t   *Tree[K, V, M]
+	n   Handle[K, V, M] // current node, if pos is cursorNode, detecting its deletion even if it is recycled
+	key K               // key of the current node, kept in case the node is deleted
+	pos cursorPosition
+}
+
+// Cursor returns a new cursor over the tree, positioned before the first node,
+// so that the first call to Cursor.Next moves it to the node with the smallest key.
+//
+// Example Usage:
+//
+//	// delete odd keys while iterating
+//	for c := tree.Cursor(); c.Next(); {
+//		if c.Key()%2 == 1 {
+//			tree.Delete(c.Node())
+//		}
+//	}
+func (t *Tree[K, V, M]) Cursor() *Cursor[K, V, M] {
+	return &Cursor[K, V, M]{t: t, pos: cursorStart}
+}
+
+// moveTo positions the cursor at n, or at the given end of the tree if n is the sentinel nil node.
+func (c *Cursor[K, V, M]) moveTo(n *Node[K, V, M], end cursorPosition) bool {
+	if c.t.IsNil(n) {
+		var zero K
+		c.n, c.key, c.pos = Handle[K, V, M]{}, zero, end
+		return false
+	}
+	c.n, c.key, c.pos = n.Handle(), n.key, cursorNode
+	return true
+}
+
+// Next moves the cursor to the next node in order.
+//
+// If the cursor is before the first node, it moves to the first node. If the current node has been
+// deleted, the cursor moves to the first node with a key greater than the deleted key.
+//
+// Returns:
+//   - true if the cursor moved to a node.
+//   - false if there is no next node, in which case the cursor is positioned after the last node.
+func (c *Cursor[K, V, M]) Next() bool {
+	switch {
+	case c.pos == cursorStart:
+		return c.First()
+	case c.pos == cursorEnd:
+		return false
+	case c.n.BelongsTo(c.t):
+		return c.moveTo(c.t.Successor(c.n.Node()), cursorEnd)
+	}
+	n, _ := c.t.Select(c.t.rankAfter(c.key))
+	return c.moveTo(n, cursorEnd)
+}
+
+// Prev moves the cursor to the previous node in order.
+//
+// If the cursor is after the last node, it moves to the last node. If the current node has been
+// deleted, the cursor moves to the last node with a key less than the deleted key.
+//
+// Returns:
+//   - true if the cursor moved to a node.
+//   - false if there is no previous node, in which case the cursor is positioned before the first node.
+func (c *Cursor[K, V, M]) Prev() bool {
+	switch {
+	case c.pos == cursorEnd:
+		return c.Last()
+	case c.pos == cursorStart:
+		return false
+	case c.n.BelongsTo(c.t):
+		return c.moveTo(c.t.Predecessor(c.n.Node()), cursorStart)
+	}
+	n, _ := c.t.Select(c.t.Rank(c.key) - 1)
+	return c.moveTo(n, cursorStart)
+}
+
+// First moves the cursor to the first node in order.
+//
+// Returns:
+//   - true if the cursor moved to a node.
+//   - false if the tree is empty, in which case the cursor is positioned after the last node.
+func (c *Cursor[K, V, M]) First() bool {
+	return c.moveTo(c.t.Min(c.t.root), cursorEnd)
+}
+
+// Last moves the cursor to the last node in order.
+//
+// Returns:
+//   - true if the cursor moved to a node.
+//   - false if the tree is empty, in which case the cursor is positioned before the first node.
+func (c *Cursor[K, V, M]) Last() bool {
+	return c.moveTo(c.t.Max(c.t.root), cursorStart)
+}
+
+// Seek moves the cursor to the first node with a key greater than or equal to key (see Tree.Ceiling).
+//
+// If the cursor is positioned at a node, the search starts from that node (see Tree.FingerCeiling),
+// so seeking to a nearby key, as merge joins over two trees do, is cheaper than a search from the root.
+//
+// Returns:
+//   - true if the cursor moved to a node.
+//   - false if there is no such node, in which case the cursor is positioned after the last node.
+func (c *Cursor[K, V, M]) Seek(key K) bool {
+	n, _ := c.t.FingerCeiling(c.Node(), key)
+	return c.moveTo(n, cursorEnd)
+}
+
+// Valid reports whether the cursor is positioned at a node that is still in the tree.
+func (c *Cursor[K, V, M]) Valid() bool {
+	return c.pos == cursorNode && c.n.BelongsTo(c.t)
+}
+
+// Node returns the current node, or the tree's sentinel nil node if the cursor is not
+// positioned at a node that is still in the tree (see Cursor.Valid).
+func (c *Cursor[K, V, M]) Node() *Node[K, V, M] {
+	if !c.Valid() {
+		return c.t.nil
+	}
+	return c.n.Node()
+}
+
+// Key returns the key of the current node. If the current node has been deleted, its key is
+// still returned. If the cursor is not positioned at a node, the zero value is returned.
+func (c *Cursor[K, V, M]) Key() K {
+	return c.key
+}
+
+// Value returns the value of the current node, or the zero value if the cursor is not positioned
+// at a node that is still in the tree (see Cursor.Valid).
+func (c *Cursor[K, V, M]) Value() V {
+	return c.Node().value
+}
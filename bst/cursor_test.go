@@ -0,0 +1,153 @@
+package bst
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func newCursorTree(keys ...int) *Tree[int, int, struct{}] {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	for _, key := range keys {
+		tree.Insert(key, key*10)
+	}
+	return tree
+}
+
+// cursorKeys returns the keys visited by calling next until it returns false.
+func cursorKeys(c *Cursor[int, int, struct{}], next func() bool) []int {
+	var keys []int
+	for next() {
+		keys = append(keys, c.Key())
+	}
+	return keys
+}
+
+func TestCursor(t *testing.T) {
+	tree := newCursorTree(3, 1, 4, 5, 9, 2, 6)
+	c := tree.Cursor()
+	assert.False(t, c.Valid())
+	assert.True(t, tree.IsNil(c.Node()))
+	assert.False(t, c.Prev(), "expected no node before the start")
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 9}, cursorKeys(c, c.Next))
+	assert.False(t, c.Valid())
+	assert.Equal(t, []int{9, 6, 5, 4, 3, 2, 1}, cursorKeys(c, c.Prev))
+	require.True(t, c.Last())
+	assert.Equal(t, 9, c.Key())
+	require.True(t, c.First())
+	assert.Equal(t, 1, c.Key())
+	assert.False(t, c.Prev())
+
+	// seek, then move either way
+	require.True(t, c.Seek(7))
+	assert.True(t, c.Valid())
+	assert.Equal(t, 9, c.Key())
+	assert.Equal(t, 90, c.Value())
+	assert.Equal(t, 9, c.Node().Key())
+	require.True(t, c.Prev())
+	assert.Equal(t, 6, c.Key())
+	require.True(t, c.Seek(4))
+	assert.Equal(t, 4, c.Key())
+	assert.False(t, c.Seek(10))
+	require.True(t, c.Prev())
+	assert.Equal(t, 9, c.Key())
+
+	// empty tree
+	c = newCursorTree().Cursor()
+	assert.False(t, c.First())
+	assert.False(t, c.Last())
+	assert.False(t, c.Next())
+	assert.False(t, c.Prev())
+	assert.False(t, c.Seek(0))
+}
+
+func TestCursor_deleteWhileIterating(t *testing.T) {
+	tree := newCursorTree(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+	var visited []int
+	for c := tree.Cursor(); c.Next(); {
+		visited = append(visited, c.Key())
+		if c.Key()%2 == 1 {
+			_, deleted := tree.Delete(c.Node())
+			require.True(t, deleted)
+			assert.False(t, c.Valid(), "expected the cursor to be invalid once its node is deleted")
+			assert.Zero(t, c.Value())
+		}
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, visited)
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 5, tree.Size())
+
+	// backwards, deleting neighbours too
+	c := tree.Cursor()
+	visited = nil
+	for ok := c.Last(); ok; ok = c.Prev() {
+		visited = append(visited, c.Key())
+		if c.Key() == 8 {
+			n, _ := tree.Search(6)
+			tree.Delete(n)
+			tree.Delete(c.Node())
+		}
+	}
+	assert.Equal(t, []int{10, 8, 4, 2}, visited)
+}
+
+func TestCursor_insertWhileIterating(t *testing.T) {
+	tree := newCursorTree(10, 20, 30)
+	var visited []int
+	for c := tree.Cursor(); c.Next(); {
+		visited = append(visited, c.Key())
+		if c.Key() == 20 {
+			tree.Insert(5, 0)  // before the cursor: not visited
+			tree.Insert(25, 0) // after the cursor: visited
+		}
+	}
+	assert.Equal(t, []int{10, 20, 25, 30}, visited)
+
+	// a deleted key that is inserted again is not revisited
+	c := tree.Cursor()
+	require.True(t, c.Seek(20))
+	tree.Delete(c.Node())
+	tree.Insert(20, 0)
+	require.True(t, c.Next())
+	assert.Equal(t, 25, c.Key())
+}
+
+func TestCursor_random(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tree := newCursorTree()
+	model := map[int]bool{}
+	for i := 0; i < 200; i++ {
+		key := rng.Intn(1000)
+		tree.Insert(key, key)
+		model[key] = true
+	}
+
+	// randomly delete and insert keys while iterating (including the current key), checking that keys
+	// are visited in order, that deleted keys are not visited, and that keys never deleted are visited
+	deleted := map[int]bool{}
+	prev := -1
+	for c := tree.Cursor(); c.Next(); {
+		require.Greater(t, c.Key(), prev)
+		require.True(t, model[c.Key()])
+		prev = c.Key()
+		delete(model, c.Key())
+
+		for range 3 {
+			key := min(c.Key()+rng.Intn(200)-100, 999)
+			if n, found := tree.Search(key); found {
+				tree.Delete(n)
+				delete(model, key)
+				deleted[key] = true
+			} else if !deleted[key] {
+				tree.Insert(key, key)
+				if key > c.Key() {
+					model[key] = true
+				}
+			}
+		}
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Empty(t, model, "expected every key never deleted to be visited")
+}
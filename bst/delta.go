@@ -0,0 +1,198 @@
+package bst
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// ErrDeltaUnavailable is wrapped by the error returned by Tree.DeltaSince when the changes since the
+// requested version are no longer (or were never) retained, so that a full snapshot must be sent instead.
+var ErrDeltaUnavailable = errors.New("delta unavailable")
+
+// Change is a single change to a tree, as recorded in a Delta.
+type Change[K, V any] struct {
+	Op    ChangeOp // Kind of change
+	Key   K        // Key inserted, updated or deleted, or the zero value for ChangeClear
+	Value V        // New value of the key, its value when deleted, or the zero value for ChangeClear
+}
+
+// Delta is the sequence of changes made to a tree between two versions, as returned by Tree.DeltaSince.
+//
+// Its fields are exported so that it can be encoded, e.g. with encoding/gob, and sent to followers
+// replicating the tree, which apply it with Tree.ApplyDelta (or Delta.Apply).
+type Delta[K, V any] struct {
+	From    uint64         // Version of the tree before the changes
+	To      uint64         // Version of the tree after the changes
+	Changes []Change[K, V] // Changes, in the order they were made
+}
+
+// Apply replays the changes of the delta in order, calling insert for each insertion and update,
+// del for each deletion, and clear for each removal of every key. This allows a delta to be applied
+// to any container, such as a tree extending bst.Tree or a btree.Tree.
+func (d *Delta[K, V]) Apply(insert func(key K, value V), del func(key K), clear func()) {
+	for _, c := range d.Changes {
+		switch c.Op {
+		case ChangeInsert, ChangeUpdate:
+			insert(c.Key, c.Value)
+		case ChangeDelete:
+			del(c.Key)
+		case ChangeClear:
+			clear()
+		}
+	}
+}
+
+// WithDeltaLog retains the latest changes made to the tree, so that Tree.DeltaSince can return the
+// changes made since a recent version, e.g. to replicate the tree to followers with small deltas
+// rather than full snapshots (see Tree.SnapshotTo).
+//
+// At least maxChanges changes are retained. Older changes are discarded, so a follower lagging
+// further behind must be sent a new snapshot. Retained changes hold their keys and values,
+// so the memory used grows with maxChanges.
+//
+// WithDeltaLog panics if maxChanges is not positive.
+func WithDeltaLog(maxChanges int) Option {
+	if maxChanges <= 0 {
+		panic(fmt.Sprintf("bst: delta log size must be positive, got %d", maxChanges))
+	}
+	return func(o *options) {
+		o.deltaLimit = maxChanges
+	}
+}
+
+// Version returns the version of the tree, which is incremented by every change notified to the function
+// registered with Tree.OnChange, starting from 0 for a new tree. See Tree.SnapshotTo and Tree.DeltaSince.
+func (t *Tree[K, V, M]) Version() uint64 {
+	return t.version
+}
+
+// logChange appends c to the delta log, discarding the oldest changes once the log holds
+// twice the configured number of changes, so that discarding is amortized.
+func (t *Tree[K, V, M]) logChange(c Change[K, V]) {
+	if len(t.deltaLog) >= 2*t.deltaLimit {
+		dropped := len(t.deltaLog) - t.deltaLimit
+		t.deltaLog = slices.Clone(t.deltaLog[dropped:])
+		t.deltaStart += uint64(dropped)
+	}
+	if len(t.deltaLog) == 0 {
+		t.deltaStart = t.version - 1
+	}
+	t.deltaLog = append(t.deltaLog, c)
+}
+
+// DeltaSince returns the changes made to the tree since the given version, as recorded
+// by the delta log (see WithDeltaLog).
+//
+// Example Usage:
+//
+//	// leader: send a delta if the follower is recent enough, or a full snapshot otherwise
+//	delta, err := tree.DeltaSince(followerVersion)
+//	if errors.Is(err, bst.ErrDeltaUnavailable) {
+//		err = tree.SnapshotTo(conn)
+//	}
+//
+// Returns:
+//   - (*Delta[K, V], nil) holding the changes since version, which are none if version is the current version.
+//   - (nil, error wrapping ErrDeltaUnavailable) if the changes since version are no longer retained,
+//     or if the delta log is not enabled.
+//   - (nil, error) if version is ahead of the tree's version.
+func (t *Tree[K, V, M]) DeltaSince(version uint64) (*Delta[K, V], error) {
+	switch {
+	case version > t.version:
+		return nil, fmt.Errorf("version %d is ahead of the tree's version %d", version, t.version)
+	case version == t.version:
+		return &Delta[K, V]{From: version, To: version}, nil
+	case t.deltaLimit == 0:
+		return nil, fmt.Errorf("%w: delta log not enabled", ErrDeltaUnavailable)
+	case version < t.deltaStart:
+		return nil, fmt.Errorf("%w: changes since version %d discarded", ErrDeltaUnavailable, version)
+	}
+	return &Delta[K, V]{
+		From:    version,
+		To:      t.version,
+		Changes: slices.Clone(t.deltaLog[version-t.deltaStart:]),
+	}, nil
+}
+
+// ApplyDelta replays the changes of d on the tree (see Delta.Apply), with Tree.InsertWithPolicy, Tree.Delete
+// and Tree.Clear. Values of existing keys are replaced, whatever the tree's DuplicatePolicy.
+// Trees extending bst.Tree must replay deltas with their own methods (as rbtree.Tree.ApplyDelta does).
+//
+// Deltas identify keys by value, so replication assumes unique keys (see WithDuplicateKeys).
+func (t *Tree[K, V, M]) ApplyDelta(d *Delta[K, V]) {
+	d.Apply(func(key K, value V) {
+		t.InsertWithPolicy(key, value, DuplicateReplace)
+	}, func(key K) {
+		if n, found := t.Search(key); found {
+			t.Delete(n)
+		}
+	}, t.Clear)
+}
+
+// snapshotHeader precedes the binary encoding of the tree in a snapshot written by Tree.SnapshotTo.
+type snapshotHeader struct {
+	Version uint64
+}
+
+// SnapshotTo writes a full snapshot of the tree to w: its version (see Tree.Version), followed by the
+// binary encoding of the tree (see Tree.MarshalBinary), as an encoding/gob stream.
+//
+// Together with Tree.DeltaSince, this allows a tree to be replicated: followers restore a snapshot
+// occasionally with Tree.ReadSnapshot, then apply the deltas since the snapshot's version.
+//
+// Returns:
+//   - nil if the snapshot was written.
+//   - An error if the tree cannot be encoded, or writing to w fails.
+func (t *Tree[K, V, M]) SnapshotTo(w io.Writer) error {
+	data, err := t.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	enc := gob.NewEncoder(w)
+	if err := enc.Encode(snapshotHeader{Version: t.version}); err != nil {
+		return err
+	}
+	return enc.Encode(data)
+}
+
+// ReadSnapshot replaces the contents of the tree with a snapshot written by Tree.SnapshotTo,
+// decoding the tree with Tree.UnmarshalBinary. If an error is returned, the tree is left unchanged.
+//
+// The tree's own version (see Tree.Version) counts the changes made to it, including restoring the
+// snapshot, so deltas must be requested from the snapshotted tree with the returned version instead.
+//
+// Trees extending bst.Tree must read snapshots with their own UnmarshalBinary method, using
+// ReadSnapshotFunc (as rbtree.Tree.ReadSnapshot does).
+//
+// Returns:
+//   - (version, nil), where version is the version of the snapshotted tree, from which
+//     deltas can then be requested (see Tree.DeltaSince).
+//   - (0, error) if the snapshot cannot be read or decoded.
+func (t *Tree[K, V, M]) ReadSnapshot(r io.Reader) (uint64, error) {
+	return ReadSnapshotFunc(r, t.UnmarshalBinary)
+}
+
+// ReadSnapshotFunc implements Tree.ReadSnapshot for trees extending bst.Tree, decoding the tree
+// with unmarshal (such as rbtree.Tree.UnmarshalBinary) rather than with Tree.UnmarshalBinary.
+//
+// Returns:
+//   - (version, nil), where version is the version of the snapshotted tree.
+//   - (0, error) if the snapshot cannot be read, or unmarshal fails.
+func ReadSnapshotFunc(r io.Reader, unmarshal func(data []byte) error) (uint64, error) {
+	dec := gob.NewDecoder(r)
+	var header snapshotHeader
+	if err := dec.Decode(&header); err != nil {
+		return 0, err
+	}
+	var data []byte
+	if err := dec.Decode(&data); err != nil {
+		return 0, err
+	}
+	if err := unmarshal(data); err != nil {
+		return 0, err
+	}
+	return header.Version, nil
+}
@@ -0,0 +1,146 @@
+package bst
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func TestTree_Version(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	assert.Equal(t, uint64(0), tree.Version())
+
+	for i := 0; i < 10; i++ {
+		tree.Insert(i, i)
+	}
+	assert.Equal(t, uint64(10), tree.Version())
+
+	tree.Insert(5, 50)
+	n, _ := tree.Search(3)
+	tree.Delete(n)
+	assert.Equal(t, uint64(12), tree.Version())
+
+	tree.Search(7)
+	tree.Min(tree.Root())
+	assert.Equal(t, uint64(12), tree.Version(), "expected reads not to change the version")
+
+	tree.Clear()
+	assert.Equal(t, uint64(13), tree.Version())
+}
+
+func TestTree_DeltaSince(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	leader := New[int, int, struct{}](func(a, b int) bool { return a < b }, WithDeltaLog(1000))
+	follower := New[int, int, struct{}](func(a, b int) bool { return a < b })
+
+	var snapshot bytes.Buffer
+	require.NoError(t, leader.SnapshotTo(&snapshot))
+	version, err := follower.ReadSnapshot(&snapshot)
+	require.NoError(t, err)
+
+	for round := 0; round < 20; round++ {
+		for i := 0; i < 50; i++ {
+			key := rng.Intn(200)
+			if n, found := leader.Search(key); found && rng.Intn(2) == 0 {
+				leader.Delete(n)
+			} else {
+				leader.Insert(key, rng.Int())
+			}
+		}
+		if round == 10 {
+			leader.Clear()
+			leader.Insert(1, 1)
+		}
+
+		delta, err := leader.DeltaSince(version)
+		require.NoError(t, err, "round %d", round)
+		assert.Equal(t, version, delta.From)
+		assert.Equal(t, leader.Version(), delta.To)
+
+		follower.ApplyDelta(delta)
+		version = delta.To
+		require.NoError(t, follower.IsTreeValid())
+		require.Equal(t, contents(leader), contents(follower), "round %d", round)
+	}
+
+	delta, err := leader.DeltaSince(leader.Version())
+	require.NoError(t, err)
+	assert.Empty(t, delta.Changes, "expected no changes since the current version")
+
+	_, err = leader.DeltaSince(leader.Version() + 1)
+	assert.Error(t, err, "expected error for a version ahead of the tree")
+	assert.NotErrorIs(t, err, ErrDeltaUnavailable)
+}
+
+func TestTree_DeltaSince_Unavailable(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	tree.Insert(1, 1)
+	_, err := tree.DeltaSince(0)
+	assert.ErrorIs(t, err, ErrDeltaUnavailable, "expected error without a delta log")
+
+	tree = New[int, int, struct{}](func(a, b int) bool { return a < b }, WithDeltaLog(10))
+	for i := 0; i < 100; i++ {
+		tree.Insert(i, i)
+	}
+	_, err = tree.DeltaSince(0)
+	assert.ErrorIs(t, err, ErrDeltaUnavailable, "expected discarded changes to be unavailable")
+
+	delta, err := tree.DeltaSince(90)
+	require.NoError(t, err, "expected the latest changes to be retained")
+	require.Len(t, delta.Changes, 10)
+	for i, c := range delta.Changes {
+		assert.Equal(t, Change[int, int]{Op: ChangeInsert, Key: 90 + i, Value: 90 + i}, c)
+	}
+
+	assert.Panics(t, func() { WithDeltaLog(0) })
+}
+
+func TestTree_DeltaSince_Rebuild(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b }, WithDeltaLog(100))
+	for i := 0; i < 5; i++ {
+		tree.Insert(i, i)
+	}
+	version := tree.Version()
+
+	other := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	for i := 10; i < 13; i++ {
+		other.Insert(i, i)
+	}
+	data, err := other.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, tree.UnmarshalBinary(data))
+
+	delta, err := tree.DeltaSince(version)
+	require.NoError(t, err)
+	require.Len(t, delta.Changes, 4, "expected a clear and an insert per restored key")
+	assert.Equal(t, ChangeClear, delta.Changes[0].Op)
+
+	follower := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	for i := 0; i < 5; i++ {
+		follower.Insert(i, i)
+	}
+	follower.ApplyDelta(delta)
+	assert.Equal(t, contents(tree), contents(follower))
+}
+
+func TestTree_ReadSnapshot(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	for i := 0; i < 20; i++ {
+		tree.Insert(i, i*i)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, tree.SnapshotTo(&buf))
+	restored := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	restored.Insert(100, 100)
+	version, err := restored.ReadSnapshot(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, tree.Version(), version)
+	assert.Equal(t, contents(tree), contents(restored))
+
+	_, err = restored.ReadSnapshot(bytes.NewReader([]byte("not a snapshot")))
+	assert.Error(t, err)
+	assert.Equal(t, contents(tree), contents(restored), "expected tree unchanged after a failed read")
+}
@@ -0,0 +1,79 @@
+package bst
+
+// Patch holds the differences between two trees, as computed by Diff, in ascending key order.
+//
+// Its fields are exported so that it can be encoded, e.g. with encoding/gob, and sent to the
+// holder of the first tree, which brings its tree in sync with Tree.ApplyPatch (or Patch.Apply).
+type Patch[K, V any] struct {
+	Added   []Change[K, V] // Keys only in the second tree, with their value, as ChangeInsert changes
+	Removed []Change[K, V] // Keys only in the first tree, with their value, as ChangeDelete changes
+	Changed []Change[K, V] // Keys in both trees with different values, with their new value, as ChangeUpdate changes
+}
+
+// Empty reports whether the patch holds no differences, i.e. whether the trees it was computed from are equal.
+func (p *Patch[K, V]) Empty() bool {
+	return len(p.Added) == 0 && len(p.Removed) == 0 && len(p.Changed) == 0
+}
+
+// Apply applies the patch, calling del for each removed key, then insert for each added and changed key.
+// This allows a patch to be applied to any container, such as a tree extending bst.Tree or a btree.Tree.
+func (p *Patch[K, V]) Apply(insert func(key K, value V), del func(key K)) {
+	for _, c := range p.Removed {
+		del(c.Key)
+	}
+	for _, c := range p.Added {
+		insert(c.Key, c.Value)
+	}
+	for _, c := range p.Changed {
+		insert(c.Key, c.Value)
+	}
+}
+
+// Diff computes the differences between trees a and b: the keys added to and removed from a to obtain b,
+// and the keys whose value differs, according to valueEq. If valueEq is nil, values are ignored and
+// only the key sets are compared, as with Tree.Equal.
+//
+// Both trees are walked in-order in lock-step, as a sorted merge, so the comparison runs in O(n + m) time
+// and is independent of the shape of either tree. Keys are compared using a's LessFunc, so both trees
+// must be ordered the same way, and must hold unique keys (see WithDuplicateKeys).
+//
+// Example Usage:
+//
+//	patch := bst.Diff(local, remote, func(a, b string) bool { return a == b })
+//	local.ApplyPatch(patch) // local now holds the same keys and values as remote
+//
+// Returns:
+//   - A *Patch[K, V] holding the differences, which is empty if the trees are equal.
+func Diff[K, V, M any](a, b *Tree[K, V, M], valueEq func(a, b V) bool) *Patch[K, V] {
+	p := &Patch[K, V]{}
+	x, y := a.Min(a.root), b.Min(b.root)
+	for !a.IsNil(x) || !b.IsNil(y) {
+		switch {
+		case b.IsNil(y) || (!a.IsNil(x) && a.less(x.key, y.key)):
+			p.Removed = append(p.Removed, Change[K, V]{Op: ChangeDelete, Key: x.key, Value: x.value})
+			x = a.Successor(x)
+		case a.IsNil(x) || a.less(y.key, x.key):
+			p.Added = append(p.Added, Change[K, V]{Op: ChangeInsert, Key: y.key, Value: y.value})
+			y = b.Successor(y)
+		default:
+			if valueEq != nil && !valueEq(x.value, y.value) {
+				p.Changed = append(p.Changed, Change[K, V]{Op: ChangeUpdate, Key: y.key, Value: y.value})
+			}
+			x, y = a.Successor(x), b.Successor(y)
+		}
+	}
+	return p
+}
+
+// ApplyPatch applies p to the tree (see Patch.Apply), with Tree.InsertWithPolicy and Tree.Delete, so that a tree
+// equal to the first tree given to Diff becomes equal to the second, whatever the tree's DuplicatePolicy. Trees extending bst.Tree must apply patches
+// with their own methods (as rbtree.Tree.ApplyPatch does).
+func (t *Tree[K, V, M]) ApplyPatch(p *Patch[K, V]) {
+	p.Apply(func(key K, value V) {
+		t.InsertWithPolicy(key, value, DuplicateReplace)
+	}, func(key K) {
+		if n, found := t.Search(key); found {
+			t.Delete(n)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package bst
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	valueEq := func(a, b int) bool { return a == b }
+	a := New[int, int, struct{}](less)
+	b := New[int, int, struct{}](less)
+	for i := 0; i < 10; i++ {
+		a.Insert(i, i)
+	}
+	for i := 5; i < 15; i++ {
+		b.Insert(i, i)
+	}
+	n, _ := b.Search(7)
+	b.SetValue(n, 70)
+
+	p := Diff(a, b, valueEq)
+	assert.Equal(t, []Change[int, int]{
+		{ChangeInsert, 10, 10}, {ChangeInsert, 11, 11}, {ChangeInsert, 12, 12},
+		{ChangeInsert, 13, 13}, {ChangeInsert, 14, 14},
+	}, p.Added)
+	assert.Equal(t, []Change[int, int]{
+		{ChangeDelete, 0, 0}, {ChangeDelete, 1, 1}, {ChangeDelete, 2, 2},
+		{ChangeDelete, 3, 3}, {ChangeDelete, 4, 4},
+	}, p.Removed)
+	assert.Equal(t, []Change[int, int]{{ChangeUpdate, 7, 70}}, p.Changed)
+	assert.False(t, p.Empty())
+
+	p = Diff(a, b, nil)
+	assert.Empty(t, p.Changed, "expected values to be ignored with a nil valueEq")
+
+	a.ApplyPatch(Diff(a, b, valueEq))
+	require.NoError(t, a.IsTreeValid())
+	assert.True(t, a.Equal(b, valueEq), "expected trees to be equal after applying the patch")
+	assert.True(t, Diff(a, b, valueEq).Empty(), "expected no differences between equal trees")
+
+	empty := New[int, int, struct{}](less)
+	assert.Len(t, Diff(empty, b, valueEq).Added, b.Size())
+	assert.Len(t, Diff(b, empty, valueEq).Removed, b.Size())
+	assert.True(t, Diff(empty, New[int, int, struct{}](less), valueEq).Empty())
+}
+
+func TestDiff_Random(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	less := func(a, b int) bool { return a < b }
+	valueEq := func(a, b int) bool { return a == b }
+	for round := 0; round < 50; round++ {
+		a := New[int, int, struct{}](less)
+		b := New[int, int, struct{}](less)
+		for i := 0; i < rng.Intn(100); i++ {
+			a.Insert(rng.Intn(100), rng.Intn(3))
+		}
+		for i := 0; i < rng.Intn(100); i++ {
+			b.Insert(rng.Intn(100), rng.Intn(3))
+		}
+		want, got := contents(a), contents(b)
+
+		p := Diff(a, b, valueEq)
+		for _, c := range p.Added {
+			_, inA := want[c.Key]
+			assert.False(t, inA, "round %d: added key %d already in a", round, c.Key)
+		}
+		for _, c := range p.Removed {
+			_, inB := got[c.Key]
+			assert.False(t, inB, "round %d: removed key %d still in b", round, c.Key)
+		}
+		for _, c := range p.Changed {
+			assert.NotEqual(t, want[c.Key], got[c.Key], "round %d: changed key %d has equal values", round, c.Key)
+		}
+
+		a.ApplyPatch(p)
+		require.NoError(t, a.IsTreeValid())
+		require.Equal(t, got, contents(a), "round %d", round)
+	}
+}
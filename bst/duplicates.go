@@ -0,0 +1,188 @@
+package bst
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDuplicateKey is returned by Tree.TryInsert, and wrapped in the panic of Tree.Insert, when a key
+// already in the tree is inserted under the DuplicateError policy (see WithDuplicatePolicy).
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// DuplicatePolicy decides how Tree.Insert resolves the insertion of a key already in the tree
+// (see WithDuplicatePolicy).
+type DuplicatePolicy uint8
+
+const (
+	DuplicateReplace DuplicatePolicy = iota // replace the value of the existing key (the default)
+	DuplicateIgnore                         // keep the existing value, discarding the new one
+	DuplicateError                          // reject the insertion with ErrDuplicateKey
+	DuplicateAppend                         // insert a new node after equal keys (multiset mode, see WithDuplicateKeys)
+)
+
+// String returns the name of the policy, such as "replace".
+func (p DuplicatePolicy) String() string {
+	switch p {
+	case DuplicateReplace:
+		return "replace"
+	case DuplicateIgnore:
+		return "ignore"
+	case DuplicateError:
+		return "error"
+	case DuplicateAppend:
+		return "append"
+	}
+	return "unknown"
+}
+
+// DuplicatePolicy returns the policy resolving insertions of existing keys (see WithDuplicatePolicy).
+func (t *Tree[K, V, M]) DuplicatePolicy() DuplicatePolicy {
+	return t.policy
+}
+
+// TryInsert inserts a new node with the given key and value into the tree, as Tree.Insert does,
+// but returns an error rather than panicking if the key exists under the DuplicateError policy.
+//
+// Example Usage:
+//
+//	if _, _, err := tree.TryInsert(user.ID, user); errors.Is(err, bst.ErrDuplicateKey) {
+//		return fmt.Errorf("user %s already registered", user.ID)
+//	}
+//
+// Returns:
+//   - (*Node[K, V, M], true, nil) if a new node was inserted.
+//   - (*Node[K, V, M], false, nil) if the key existed, and was resolved by the tree's DuplicatePolicy.
+//   - (*Node[K, V, M], false, ErrDuplicateKey) if the key existed under the DuplicateError policy. The
+//     existing node is returned, and left unchanged.
+func (t *Tree[K, V, M]) TryInsert(key K, value V) (*Node[K, V, M], bool, error) {
+	return t.InsertWithPolicy(key, value, t.policy)
+}
+
+// InsertIfAbsent inserts a new node with the given key and value into the tree, only if the key is not
+// already in the tree, whatever the tree's DuplicatePolicy. In multiset mode, the key is not inserted
+// again either.
+//
+// Returns:
+//   - (*Node[K, V, M], true) if a new node was inserted.
+//   - (*Node[K, V, M], false) if the key existed. The existing node is returned, and left unchanged.
+func (t *Tree[K, V, M]) InsertIfAbsent(key K, value V) (*Node[K, V, M], bool) {
+	n, inserted, _ := t.InsertWithPolicy(key, value, DuplicateIgnore)
+	return n, inserted
+}
+
+// InsertWithPolicy inserts a new node with the given key and value into the tree, resolving an existing
+// equal key with policy rather than with the tree's DuplicatePolicy. This is how Tree.Insert, Tree.TryInsert
+// and Tree.InsertIfAbsent insert keys.
+//
+// In multiset mode, DuplicateReplace inserts a new node after equal keys, as DuplicateAppend does, so that
+// Tree.ApplyDelta can replay insertions with DuplicateReplace whatever the tree's DuplicatePolicy.
+//
+// Trees extending bst.Tree call InsertWithPolicy to implement these methods, rebalancing the tree after
+// a new node is inserted (as scapegoat.Tree.InsertWithPolicy does).
+//
+// InsertWithPolicy panics if policy is DuplicateAppend outside of multiset mode.
+//
+// Returns:
+//   - (*Node[K, V, M], true, nil) if a new node was inserted.
+//   - (*Node[K, V, M], false, nil) if the key existed, and was resolved by policy.
+//   - (*Node[K, V, M], false, ErrDuplicateKey) if the key existed and policy is DuplicateError.
+func (t *Tree[K, V, M]) InsertWithPolicy(key K, value V, policy DuplicatePolicy) (*Node[K, V, M], bool, error) {
+	if policy == DuplicateAppend && !t.duplicates {
+		panic(fmt.Sprintf("bst: invalid duplicate policy %v outside of multiset mode", policy))
+	}
+	if t.duplicates && policy == DuplicateReplace {
+		policy = DuplicateAppend
+	}
+
+	parent := t.nil    // trailing pointer - parent of current node
+	currNode := t.root // current node
+
+	// find nil leaf where new node will be inserted
+	for !t.IsNil(currNode) {
+
+		// update trailing pointer
+		parent = currNode
+
+		if policy != DuplicateAppend && t.keysEqual(currNode.key, key) {
+
+			// If key already exists, resolve it with the policy
+			return currNode, false, t.ResolveDuplicate(currNode, value, policy)
+
+		} else if t.less(key, currNode.key) {
+
+			// If key is smaller, go left
+			currNode = currNode.left
+
+		} else {
+
+			// If key is larger (or equal in multiset mode), go right
+			currNode = currNode.right
+		}
+	}
+
+	return t.InsertAt(parent, key, value), true, nil
+}
+
+// ResolveDuplicate resolves the insertion of value with the key of the existing node n, according to
+// policy: the value of n is replaced under DuplicateReplace, and ErrDuplicateKey is returned under
+// DuplicateError.
+//
+// This function is intended to be used only when extending bst.Tree, by trees finding the position of
+// new keys with their own descent (as rbtree does with its top-down strategy).
+//
+// Returns:
+//   - nil, unless policy is DuplicateError.
+//   - An error wrapping ErrDuplicateKey if policy is DuplicateError.
+func (t *Tree[K, V, M]) ResolveDuplicate(n *Node[K, V, M], value V, policy DuplicatePolicy) error {
+	switch policy {
+	case DuplicateReplace:
+		t.SetValue(n, value)
+	case DuplicateError:
+		return fmt.Errorf("%w: %v", ErrDuplicateKey, n.key)
+	}
+	return nil
+}
+
+// InsertReturningOld inserts a new node with the given key and value into the tree, as Tree.Insert does,
+// and returns the value the key held before, saving a Tree.Search before each insertion when the displaced
+// value is needed, e.g. for cache accounting.
+//
+// An existing key is resolved by the tree's DuplicatePolicy, as by Tree.Insert: under DuplicateIgnore, the
+// value returned is the one kept. In multiset mode, a new node is always inserted, so no value is displaced.
+//
+// Example Usage:
+//
+//	// keep track of the total size of the cached entries
+//	if old, existed := tree.InsertReturningOld(key, entry); existed {
+//		total -= old.Size
+//	}
+//	total += entry.Size
+//
+// InsertReturningOld panics with an error wrapping ErrDuplicateKey if the key exists under the DuplicateError
+// policy.
+//
+// Returns:
+//   - (old value, true) if the key existed.
+//   - (zero value, false) if a new node was inserted.
+func (t *Tree[K, V, M]) InsertReturningOld(key K, value V) (V, bool) {
+	return InsertReturningOldFunc(t, key, value, t.InsertWithPolicy)
+}
+
+// InsertReturningOldFunc implements Tree.InsertReturningOld for trees extending bst.Tree, inserting keys
+// with insert (such as rbtree.Tree.InsertWithPolicy) rather than with Tree.InsertWithPolicy.
+func InsertReturningOldFunc[K, V, M any](t *Tree[K, V, M], key K, value V, insert func(key K, value V, policy DuplicatePolicy) (*Node[K, V, M], bool, error)) (V, bool) {
+	var zero V
+	if t.duplicates {
+		insert(key, value, DuplicateAppend)
+		return zero, false
+	}
+	n, inserted, _ := insert(key, value, DuplicateIgnore)
+	if inserted {
+		return zero, false
+	}
+	old := n.value
+	if err := t.ResolveDuplicate(n, value, t.policy); err != nil {
+		panic(err)
+	}
+	return old, true
+}
@@ -0,0 +1,135 @@
+package bst
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWithDuplicatePolicy(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	for _, tc := range []struct {
+		policy DuplicatePolicy
+		values []string // values in order after inserting 1 twice
+	}{
+		{DuplicateReplace, []string{"second"}},
+		{DuplicateIgnore, []string{"first"}},
+		{DuplicateError, []string{"first"}},
+		{DuplicateAppend, []string{"first", "second"}},
+	} {
+		t.Run(tc.policy.String(), func(t *testing.T) {
+			tree := New[int, string, struct{}](less, WithDuplicatePolicy(tc.policy))
+			assert.Equal(t, tc.policy, tree.DuplicatePolicy())
+			assert.Equal(t, tc.policy == DuplicateAppend, tree.Duplicates())
+
+			n, inserted, err := tree.TryInsert(1, "first")
+			require.NoError(t, err)
+			assert.True(t, inserted)
+
+			existing, inserted, err := tree.TryInsert(1, "second")
+			assert.Equal(t, tc.policy == DuplicateAppend, inserted)
+			if tc.policy == DuplicateError {
+				assert.ErrorIs(t, err, ErrDuplicateKey)
+				assert.Equal(t, n, existing, "expected the existing node")
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.values, ToSlice(tree))
+			require.NoError(t, tree.IsTreeValid())
+		})
+	}
+
+	assert.Equal(t, DuplicateAppend, New[int, int, struct{}](less, WithDuplicateKeys()).DuplicatePolicy())
+	assert.Equal(t, DuplicateReplace, New[int, int, struct{}](less).DuplicatePolicy(), "expected values to be replaced by default")
+	assert.Panics(t, func() { WithDuplicatePolicy(DuplicateAppend + 1) })
+	assert.Equal(t, "unknown", (DuplicateAppend + 1).String())
+}
+
+func TestTree_Insert_duplicateError(t *testing.T) {
+	tree := New[int, string, struct{}](func(a, b int) bool { return a < b }, WithDuplicatePolicy(DuplicateError))
+	tree.Insert(1, "first")
+
+	defer func() {
+		err, ok := recover().(error)
+		require.True(t, ok, "expected Insert to panic with an error")
+		assert.True(t, errors.Is(err, ErrDuplicateKey))
+		value, _ := tree.Search(1)
+		assert.Equal(t, "first", tree.Value(value), "expected the value to be kept")
+	}()
+	tree.Insert(1, "second")
+	t.Error("expected Insert to panic")
+}
+
+func TestTree_InsertIfAbsent(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithDuplicateKeys()}} {
+		tree := New[int, string, struct{}](func(a, b int) bool { return a < b }, opts...)
+		n, inserted := tree.InsertIfAbsent(1, "first")
+		assert.True(t, inserted)
+		existing, inserted := tree.InsertIfAbsent(1, "second")
+		assert.False(t, inserted, "expected an existing key not to be inserted")
+		assert.Equal(t, n, existing)
+		assert.Equal(t, []string{"first"}, ToSlice(tree))
+	}
+}
+
+func TestTree_InsertWithPolicy(t *testing.T) {
+	tree := New[int, string, struct{}](func(a, b int) bool { return a < b })
+	assert.Panics(t, func() { tree.InsertWithPolicy(1, "one", DuplicateAppend) },
+		"expected DuplicateAppend to panic outside of multiset mode")
+
+	// replacing in multiset mode appends, so that deltas replay insertions
+	tree = New[int, string, struct{}](func(a, b int) bool { return a < b }, WithDuplicateKeys())
+	tree.Insert(1, "first")
+	_, inserted, err := tree.InsertWithPolicy(1, "second", DuplicateReplace)
+	require.NoError(t, err)
+	assert.True(t, inserted)
+	_, inserted, err = tree.InsertWithPolicy(1, "third", DuplicateError)
+	assert.ErrorIs(t, err, ErrDuplicateKey)
+	assert.False(t, inserted)
+	assert.Equal(t, []string{"first", "second"}, ToSlice(tree))
+}
+
+func TestTree_ApplyDelta_duplicatePolicy(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	source := New[int, string, struct{}](less, WithDeltaLog(10))
+	source.Insert(1, "first")
+	source.Insert(1, "second")
+	d, err := source.DeltaSince(0)
+	require.NoError(t, err)
+
+	replica := New[int, string, struct{}](less, WithDuplicatePolicy(DuplicateError))
+	replica.ApplyDelta(d)
+	assert.Equal(t, []string{"second"}, ToSlice(replica), "expected updates to be replayed whatever the policy")
+}
+
+func TestTree_InsertReturningOld(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	tree := New[int, string, struct{}](less)
+	old, existed := tree.InsertReturningOld(1, "first")
+	assert.False(t, existed)
+	assert.Equal(t, "", old)
+
+	old, existed = tree.InsertReturningOld(1, "second")
+	assert.True(t, existed)
+	assert.Equal(t, "first", old, "expected the displaced value")
+	assert.Equal(t, []string{"second"}, ToSlice(tree))
+
+	tree = New[int, string, struct{}](less, WithDuplicatePolicy(DuplicateIgnore))
+	tree.Insert(1, "first")
+	old, existed = tree.InsertReturningOld(1, "second")
+	assert.True(t, existed)
+	assert.Equal(t, "first", old)
+	assert.Equal(t, []string{"first"}, ToSlice(tree), "expected the existing value to be kept")
+
+	tree = New[int, string, struct{}](less, WithDuplicatePolicy(DuplicateError))
+	tree.Insert(1, "first")
+	assert.Panics(t, func() { tree.InsertReturningOld(1, "second") })
+	assert.Equal(t, []string{"first"}, ToSlice(tree))
+
+	tree = New[int, string, struct{}](less, WithDuplicateKeys())
+	tree.Insert(1, "first")
+	_, existed = tree.InsertReturningOld(1, "second")
+	assert.False(t, existed, "expected a new node in multiset mode")
+	assert.Equal(t, []string{"first", "second"}, ToSlice(tree))
+}
@@ -0,0 +1,61 @@
+package bst
+
+// FirstEntry returns the smallest key in the tree and its value, without exposing the node holding them.
+//
+// As the minimum of the tree is cached (see Tree.Min), this is usually an O(1) operation.
+//
+// Example Usage:
+//
+//	// peek at the smallest entry
+//	if key, value, ok := tree.FirstEntry(); ok {
+//		fmt.Println(key, value)
+//	}
+//
+// Returns:
+//   - (key, value, true) if the tree is not empty.
+//   - (zero key, zero value, false) if the tree is empty.
+func (t *Tree[K, V, M]) FirstEntry() (K, V, bool) {
+	return t.entry(t.Min(t.root))
+}
+
+// LastEntry returns the largest key in the tree and its value, without exposing the node holding them.
+// See Tree.FirstEntry.
+//
+// Returns:
+//   - (key, value, true) if the tree is not empty.
+//   - (zero key, zero value, false) if the tree is empty.
+func (t *Tree[K, V, M]) LastEntry() (K, V, bool) {
+	return t.entry(t.Max(t.root))
+}
+
+// FirstKey returns the smallest key in the tree. See Tree.FirstEntry.
+//
+// Returns:
+//   - (key, true) if the tree is not empty.
+//   - (zero key, false) if the tree is empty.
+func (t *Tree[K, V, M]) FirstKey() (K, bool) {
+	key, _, ok := t.FirstEntry()
+	return key, ok
+}
+
+// LastKey returns the largest key in the tree. See Tree.FirstEntry.
+//
+// Returns:
+//   - (key, true) if the tree is not empty.
+//   - (zero key, false) if the tree is empty.
+func (t *Tree[K, V, M]) LastKey() (K, bool) {
+	key, _, ok := t.LastEntry()
+	return key, ok
+}
+
+// entry returns the key and value of n, or false if n is the sentinel nil node.
+func (t *Tree[K, V, M]) entry(n *Node[K, V, M]) (K, V, bool) {
+	if t.IsNil(n) {
+		var (
+			zeroK K
+			zeroV V
+		)
+		return zeroK, zeroV, false
+	}
+	return n.key, n.value, true
+}
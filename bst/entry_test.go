@@ -0,0 +1,43 @@
+package bst
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTree_FirstLastEntry(t *testing.T) {
+	tree := New[int, string, struct{}](func(a, b int) bool { return a < b })
+	_, _, ok := tree.FirstEntry()
+	assert.False(t, ok, "expected no first entry in an empty tree")
+	_, _, ok = tree.LastEntry()
+	assert.False(t, ok, "expected no last entry in an empty tree")
+	_, ok = tree.FirstKey()
+	assert.False(t, ok)
+	_, ok = tree.LastKey()
+	assert.False(t, ok)
+
+	for _, k := range []int{5, 3, 8, 1, 9} {
+		tree.Insert(k, string(rune('a'+k)))
+	}
+	key, value, ok := tree.FirstEntry()
+	assert.True(t, ok)
+	assert.Equal(t, 1, key)
+	assert.Equal(t, "b", value)
+	key, value, ok = tree.LastEntry()
+	assert.True(t, ok)
+	assert.Equal(t, 9, key)
+	assert.Equal(t, "j", value)
+	key, _ = tree.FirstKey()
+	assert.Equal(t, 1, key)
+	key, _ = tree.LastKey()
+	assert.Equal(t, 9, key)
+
+	// the entries follow the tree's order
+	reversed := New[int, string, struct{}](func(a, b int) bool { return a < b }, WithReverseOrder())
+	reversed.Insert(1, "one")
+	reversed.Insert(2, "two")
+	key, _ = reversed.FirstKey()
+	assert.Equal(t, 2, key)
+	key, _ = reversed.LastKey()
+	assert.Equal(t, 1, key)
+}
@@ -1,9 +1,12 @@
 package bst_test
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/mikenye/gotrees/bst"
 	"github.com/mikenye/gotrees/rbtree"
+	"math"
+	"slices"
 )
 
 func ExampleTree_Delete() {
@@ -293,3 +296,309 @@ func ExampleTree_Ceiling() {
 	// Ceiling(1) = 2: two
 	// Ceiling(12) not found
 }
+
+func ExampleTree_SetNodeFormatter() {
+
+	type employee struct {
+		Name, Title, Department string
+	}
+
+	// create the tree with integer keys and struct values
+	tree := bst.New[int, employee, struct{}](func(a, b int) bool {
+		return a < b
+	})
+
+	tree.Insert(2, employee{"Bob", "Engineer", "Platform"})
+	tree.Insert(1, employee{"Alice", "Manager", "Platform"})
+	tree.Insert(3, employee{"Carol", "Designer", "Product"})
+
+	// show only the employee name, and hide the empty metadata
+	tree.SetNodeFormatter(func(id int, e employee, _ struct{}) string {
+		return fmt.Sprintf("#%d %s", id, e.Name)
+	})
+
+	// show the tree
+	fmt.Printf("Tree:\n%s", tree)
+
+	// Output:
+	// Tree:
+	//  ╭── #1 Alice
+	// #2 Bob
+	//  ╰── #3 Carol
+}
+
+func ExampleCheckLess() {
+
+	// comparing float64 keys with < is not a strict weak ordering once NaN is involved
+	less := func(a, b float64) bool { return a < b }
+	err := bst.CheckLess(less, []float64{-1, 0, 1, math.NaN()})
+	fmt.Println(err)
+
+	// ordering NaN before every other key fixes it
+	less = func(a, b float64) bool { return (math.IsNaN(a) && !math.IsNaN(b)) || a < b }
+	err = bst.CheckLess(less, []float64{-1, 0, 1, math.NaN()})
+	fmt.Println(err)
+
+	// Output:
+	// invalid LessFunc: equivalence not transitive: -1 is equivalent to NaN, and NaN to 0, but -1 is not equivalent to 0
+	// <nil>
+}
+
+func ExampleWithReverseOrder() {
+
+	// a max-ordered tree, using the natural comparator
+	tree := bst.New[int, string, struct{}](func(a, b int) bool { return a < b }, bst.WithReverseOrder())
+	for _, k := range []int{20, 40, 10, 30} {
+		tree.Insert(k, fmt.Sprint("value ", k))
+	}
+
+	fmt.Println("Min:", tree.Key(tree.Min(tree.Root())))
+
+	// Floor and Ceiling follow the tree's order: Floor moves towards larger keys
+	floor, _ := tree.Floor(25)
+	ceiling, _ := tree.Ceiling(25)
+	fmt.Println("Floor(25):", tree.Key(floor))
+	fmt.Println("Ceiling(25):", tree.Key(ceiling))
+
+	// Output:
+	// Min: 40
+	// Floor(25): 30
+	// Ceiling(25): 20
+}
+
+func ExampleTree_Cursor() {
+	tree := bst.NewOrdered[int, string, struct{}]()
+	for i := 1; i <= 6; i++ {
+		tree.Insert(i, fmt.Sprint("value ", i))
+	}
+
+	// delete odd keys while iterating, without collecting the nodes first
+	for c := tree.Cursor(); c.Next(); {
+		if c.Key()%2 == 1 {
+			tree.Delete(c.Node())
+		}
+	}
+
+	for c := tree.Cursor(); c.Next(); {
+		fmt.Println(c.Key(), c.Value())
+	}
+
+	// Output:
+	// 2 value 2
+	// 4 value 4
+	// 6 value 6
+}
+
+func ExampleTree_AscendDelete() {
+	tree := bst.NewOrdered[int, string, struct{}]()
+	for i := 1; i <= 6; i++ {
+		tree.Insert(i, fmt.Sprint("value ", i))
+	}
+
+	// delete odd keys, stopping once key 4 has been seen
+	deleted := tree.AscendDelete(func(n *bst.Node[int, string, struct{}]) bst.Action {
+		action := bst.ActionContinue
+		if n.Key()%2 == 1 {
+			action |= bst.ActionDelete
+		}
+		if n.Key() == 4 {
+			action |= bst.ActionStop
+		}
+		return action
+	})
+	fmt.Println("deleted:", deleted)
+
+	for c := tree.Cursor(); c.Next(); {
+		fmt.Println(c.Key(), c.Value())
+	}
+
+	// Output:
+	// deleted: 2
+	// 2 value 2
+	// 4 value 4
+	// 5 value 5
+	// 6 value 6
+}
+
+func ExampleTree_DetachSubtree() {
+	tree := bst.NewOrdered[int, string, struct{}]()
+	for _, key := range []int{4, 2, 6, 1, 3, 5, 7} {
+		tree.Insert(key, fmt.Sprint("value ", key))
+	}
+
+	// move the branch rooted at 2 to its own tree
+	n, _ := tree.Search(2)
+	branch, _ := tree.DetachSubtree(n)
+	fmt.Println("tree size:", tree.Size(), "branch size:", branch.Size())
+
+	// then move it back, into the gap between the remaining keys
+	if err := tree.AttachSubtree(branch); err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println("tree size:", tree.Size(), "branch size:", branch.Size())
+
+	// Output:
+	// tree size: 4 branch size: 3
+	// tree size: 7 branch size: 0
+}
+
+func ExampleCursor_Seek() {
+
+	// create two trees with integer keys
+	less := func(a, b int) bool { return a < b }
+	left := bst.New[int, struct{}, struct{}](less)
+	right := bst.New[int, struct{}, struct{}](less)
+	for i := 0; i < 100; i++ {
+		left.Insert(i*2, struct{}{})
+		right.Insert(i*3, struct{}{})
+	}
+
+	// merge join: find the keys in both trees, seeking from the current position of each cursor
+	a, b := left.Cursor(), right.Cursor()
+	for ok := a.Next() && b.Next(); ok; {
+		switch {
+		case a.Key() < b.Key():
+			ok = a.Seek(b.Key())
+		case b.Key() < a.Key():
+			ok = b.Seek(a.Key())
+		default:
+			if a.Key() > 30 {
+				return
+			}
+			fmt.Println(a.Key())
+			ok = a.Next() && b.Next()
+		}
+	}
+
+	// Output:
+	// 0
+	// 6
+	// 12
+	// 18
+	// 24
+	// 30
+}
+
+func ExampleTree_DeltaSince() {
+
+	// create a leader tree retaining its latest changes, and a follower replicating it
+	less := func(a, b int) bool { return a < b }
+	leader := bst.New[int, string, struct{}](less, bst.WithDeltaLog(100))
+	follower := bst.New[int, string, struct{}](less)
+	leader.Insert(1, "one")
+	leader.Insert(2, "two")
+
+	// send a full snapshot to the follower
+	var snapshot bytes.Buffer
+	_ = leader.SnapshotTo(&snapshot)
+	version, _ := follower.ReadSnapshot(&snapshot)
+
+	// then send the changes made since the snapshot
+	leader.Insert(3, "three")
+	leader.Insert(1, "uno")
+	n, _ := leader.Search(2)
+	leader.Delete(n)
+	delta, _ := leader.DeltaSince(version)
+	for _, c := range delta.Changes {
+		fmt.Println(c.Op, c.Key, c.Value)
+	}
+	follower.ApplyDelta(delta)
+	for n := follower.Min(follower.Root()); !follower.IsNil(n); n = follower.Successor(n) {
+		fmt.Println(n.Key(), n.Value())
+	}
+
+	// Output:
+	// insert 3 three
+	// update 1 uno
+	// delete 2 two
+	// 1 uno
+	// 3 three
+}
+
+func ExampleDiff() {
+
+	// create two versions of a configuration tree
+	less := func(a, b string) bool { return a < b }
+	local := bst.New[string, string, struct{}](less)
+	remote := bst.New[string, string, struct{}](less)
+	local.Insert("host", "localhost")
+	local.Insert("port", "8080")
+	local.Insert("debug", "true")
+	remote.Insert("host", "example.com")
+	remote.Insert("port", "8080")
+	remote.Insert("timeout", "30s")
+
+	// compute the differences, and bring the local tree in sync
+	patch := bst.Diff(local, remote, func(a, b string) bool { return a == b })
+	fmt.Println("added:", patch.Added)
+	fmt.Println("removed:", patch.Removed)
+	fmt.Println("changed:", patch.Changed)
+	local.ApplyPatch(patch)
+	fmt.Println(bst.Diff(local, remote, func(a, b string) bool { return a == b }).Empty())
+
+	// Output:
+	// added: [{insert timeout 30s}]
+	// removed: [{delete debug true}]
+	// changed: [{update host example.com}]
+	// true
+}
+
+func Example_extending() {
+	tree := bst.NewOrdered[int, string, struct{}]()
+	for _, key := range []int{3, 2, 1} {
+		tree.Insert(key, fmt.Sprint("value ", key))
+	}
+	fmt.Println("root:", tree.Key(tree.Root()), "height:", tree.Height())
+
+	// a right rotation written with the tree builder API, as a third-party tree would
+	// (Tree.RotateRight does the same), restoring every invariant before returning
+	rotateRight := func(x *bst.Node[int, string, struct{}]) {
+		y := tree.Left(x)
+		tree.SetLeft(x, tree.Right(y))
+		if !tree.IsNil(tree.Right(y)) {
+			tree.SetParent(tree.Right(y), x)
+		}
+		tree.Transplant(x, y)
+		tree.SetRight(y, x)
+		tree.SetParent(x, y)
+		tree.RefreshPath(x)
+	}
+	rotateRight(tree.Root())
+	fmt.Println("root:", tree.Key(tree.Root()), "height:", tree.Height(), "valid:", tree.IsTreeValid() == nil)
+
+	// removing a leaf by relinking it, then releasing it
+	n, _ := tree.Search(1)
+	parent := tree.Parent(n)
+	tree.Transplant(n, tree.Sentinel())
+	tree.Release(n)
+	tree.RefreshPath(parent)
+	fmt.Println("size:", tree.Size(), "valid:", tree.ValidatePath(parent) == nil && tree.IsTreeValid() == nil)
+
+	// Output:
+	// root: 3 height: 2
+	// root: 2 height: 1 valid: true
+	// size: 2 valid: true
+}
+
+func ExampleTree_All() {
+
+	tree := bst.New[string, int, struct{}](func(a, b string) bool { return a < b })
+	tree.Insert("pears", 5)
+	tree.Insert("apples", 3)
+	tree.Insert("plums", 8)
+
+	for key, value := range tree.All() {
+		fmt.Println(key, value)
+	}
+
+	// trees feed the iterator functions of the slices and maps packages
+	fmt.Println(slices.Collect(tree.Keys()))
+	fmt.Println(slices.Max(slices.Collect(tree.Values())))
+
+	// Output:
+	// apples 3
+	// pears 5
+	// plums 8
+	// [apples pears plums]
+	// 8
+}
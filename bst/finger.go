@@ -0,0 +1,98 @@
+package bst
+
+// FingerSearch looks for a node with the given key, starting from the node finger rather than
+// from the root, as Tree.Search would.
+//
+// The search climbs from finger to the lowest ancestor whose subtree spans key, then descends
+// from there. Its cost thus depends on the distance between finger and the node found, rather
+// than on the size of the tree: on a balanced tree, finding a key d positions away from finger
+// typically takes O(log d) time, rather than O(log n). This speeds up searches with locality,
+// such as merge joins over two trees, where each key searched for follows the previous one.
+//
+// If finger is nil, has been removed, or belongs to a different tree, the search starts from the root.
+// In multiset mode, the first node (in order) with an equal key is returned, as with Tree.Search.
+//
+// Example Usage:
+//
+//	// look up sorted keys, each search starting from the previous result
+//	finger := tree.Root()
+//	for _, key := range sortedKeys {
+//		if n, found := tree.FingerSearch(finger, key); found {
+//			finger = n
+//		}
+//	}
+//
+// Returns:
+//   - (*Node[K, V, M], true) if the key exists in the tree.
+//   - (t.nil, false) if the key is not found.
+func (t *Tree[K, V, M]) FingerSearch(finger *Node[K, V, M], key K) (*Node[K, V, M], bool) {
+	n, found := t.FingerCeiling(finger, key)
+	if found && !t.less(key, n.key) {
+		return n, true
+	}
+	return t.nil, false
+}
+
+// FingerCeiling finds the smallest key in the tree greater than or equal to key, as Tree.Ceiling
+// does, starting from the node finger rather than from the root. See Tree.FingerSearch.
+//
+// Returns:
+//   - (*Node[K, V, M], true) if a key ≥ key exists in the tree.
+//   - (t.nil, false) if no such key exists.
+func (t *Tree[K, V, M]) FingerCeiling(finger *Node[K, V, M], key K) (*Node[K, V, M], bool) {
+	if t.IsNil(finger) || !finger.BelongsTo(t) {
+		finger = t.root
+	}
+
+	// climb until the ceiling is known to be within the subtree of x, or to be ceiling
+	x, ceiling := finger, t.nil
+	for !t.IsNil(x) {
+		if t.less(x.key, key) {
+			// the ceiling follows x: it is in the right subtree of x, unless it is the lowest
+			// ancestor holding x in its left subtree
+			bound := t.ancestorAfter(x)
+			if t.IsNil(bound) || !t.less(bound.key, key) {
+				ceiling = bound
+				break
+			}
+			x = bound
+		} else {
+			// x is a candidate, unless a smaller one precedes it: the ceiling is x or in the left
+			// subtree of x, unless the lowest ancestor holding x in its right subtree is itself a candidate
+			bound := t.ancestorBefore(x)
+			if t.IsNil(bound) || t.less(bound.key, key) {
+				break
+			}
+			x = bound
+		}
+	}
+
+	// descend as Tree.Ceiling does, keeping the first node with a key not less than key
+	for !t.IsNil(x) {
+		if t.less(x.key, key) {
+			x = x.right
+		} else {
+			ceiling = x
+			x = x.left
+		}
+	}
+	return ceiling, !t.IsNil(ceiling)
+}
+
+// ancestorAfter returns the lowest ancestor of n holding n in its left subtree, which is the node
+// following the largest node of the subtree of n, or the sentinel nil node if there is none.
+func (t *Tree[K, V, M]) ancestorAfter(n *Node[K, V, M]) *Node[K, V, M] {
+	for !t.IsNil(n.parent) && n == n.parent.right {
+		n = n.parent
+	}
+	return n.parent
+}
+
+// ancestorBefore returns the lowest ancestor of n holding n in its right subtree, which is the node
+// preceding the smallest node of the subtree of n, or the sentinel nil node if there is none.
+func (t *Tree[K, V, M]) ancestorBefore(n *Node[K, V, M]) *Node[K, V, M] {
+	for !t.IsNil(n.parent) && n == n.parent.left {
+		n = n.parent
+	}
+	return n.parent
+}
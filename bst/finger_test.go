@@ -0,0 +1,62 @@
+package bst
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func TestTree_FingerCeiling(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"unique keys":    nil,
+		"duplicate keys": {WithDuplicateKeys()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tree := New[int, int, struct{}](func(a, b int) bool { return a < b }, opts...)
+			rng := rand.New(rand.NewSource(1))
+			var nodes []*Node[int, int, struct{}]
+			for i := 0; i < 300; i++ {
+				n, _ := tree.Insert(rng.Intn(200)*2, i)
+				nodes = append(nodes, n)
+			}
+
+			// every finger finds the same node as a search from the root
+			for i := 0; i < 2000; i++ {
+				finger := nodes[rng.Intn(len(nodes))]
+				if !finger.BelongsTo(tree) {
+					finger = tree.Root()
+				}
+				key := rng.Intn(402) - 1
+
+				expected, expectedFound := tree.Ceiling(key)
+				n, found := tree.FingerCeiling(finger, key)
+				require.Equal(t, expectedFound, found, "unexpected FingerCeiling(%d, %d) result", finger.key, key)
+				require.Same(t, expected, n, "unexpected FingerCeiling(%d, %d) node", finger.key, key)
+
+				expected, expectedFound = tree.Search(key)
+				n, found = tree.FingerSearch(finger, key)
+				require.Equal(t, expectedFound, found, "unexpected FingerSearch(%d, %d) result", finger.key, key)
+				require.Same(t, expected, n, "unexpected FingerSearch(%d, %d) node", finger.key, key)
+			}
+
+			// a stale or foreign finger searches from the root
+			stale := tree.Min(tree.Root())
+			tree.Delete(stale)
+			expected, _ := tree.Search(tree.Max(tree.Root()).key)
+			n, found := tree.FingerSearch(stale, expected.key)
+			assert.True(t, found)
+			assert.Same(t, expected, n)
+			n, found = tree.FingerSearch(tree.nil, 1)
+			assert.False(t, found)
+			assert.True(t, tree.IsNil(n))
+		})
+	}
+}
+
+func TestTree_FingerSearch_empty(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	n, found := tree.FingerSearch(tree.Root(), 1)
+	assert.False(t, found)
+	assert.True(t, tree.IsNil(n))
+}
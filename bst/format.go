@@ -0,0 +1,110 @@
+package bst
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Format identifies a serialization format of Tree, for which migrations can be registered
+// with RegisterMigration.
+type Format uint8
+
+const (
+	FormatBinary Format = iota + 1 // Tree.MarshalBinary, also used by Tree.SnapshotTo
+	FormatJSON                     // Tree.MarshalJSON
+	FormatProto                    // Tree.MarshalProto
+	FormatCodec                    // Tree.MarshalWith
+)
+
+// String returns the name of the format.
+func (f Format) String() string {
+	switch f {
+	case FormatBinary:
+		return "binary"
+	case FormatJSON:
+		return "json"
+	case FormatProto:
+		return "proto"
+	case FormatCodec:
+		return "codec"
+	default:
+		return fmt.Sprintf("Format(%d)", f)
+	}
+}
+
+// FormatVersion is the version of the node layout written by every serialization format of Tree.
+//
+// Each format records the version it was written with, and data written before versions were recorded
+// is version 1. When the layout changes, FormatVersion is incremented, and data written with an older
+// version is brought up to date by the migrations registered with RegisterMigration before being decoded,
+// so that old snapshots remain loadable.
+const FormatVersion = 1
+
+// ErrUnsupportedVersion is wrapped by the error returned when decoding data written with a
+// version of a format that is newer than FormatVersion, or older with no registered migration.
+var ErrUnsupportedVersion = errors.New("unsupported format version")
+
+// MigrationFunc converts data written with one version of a format to the following version.
+//
+// For FormatBinary, data excludes the version header. For the other formats, data is the whole
+// encoded tree, whose version field is ignored once migrated.
+type MigrationFunc func(data []byte) ([]byte, error)
+
+// migrationKey identifies the migration of a format from a version to the following one.
+type migrationKey struct {
+	format Format
+	from   int
+}
+
+// migrations holds the registered migrations.
+var migrations = struct {
+	sync.RWMutex
+	funcs map[migrationKey]MigrationFunc
+}{funcs: make(map[migrationKey]MigrationFunc)}
+
+// RegisterMigration registers m to convert data of the given format from version from to version from+1.
+// Data written with an older version than FormatVersion is converted by the migrations of every version
+// in turn, so a migration is required for each version from the oldest still to be loaded.
+//
+// Migrations are typically registered from init functions, and apply to every Tree, whatever its type.
+//
+// RegisterMigration panics if from is less than 1, or if a migration is already registered for
+// the format and version.
+func RegisterMigration(format Format, from int, m MigrationFunc) {
+	if from < 1 {
+		panic(fmt.Sprintf("bst: invalid migration version %d", from))
+	}
+	migrations.Lock()
+	defer migrations.Unlock()
+	key := migrationKey{format: format, from: from}
+	if _, exists := migrations.funcs[key]; exists {
+		panic(fmt.Sprintf("bst: migration of %s format from version %d already registered", format, from))
+	}
+	migrations.funcs[key] = m
+}
+
+// migrate converts data of format, written with the given version, to version current.
+//
+// Returns:
+//   - (data, nil) if data was converted, or is already of version current.
+//   - (nil, error wrapping ErrUnsupportedVersion) if version is newer than current, or a migration is missing.
+//   - (nil, error) if a migration fails.
+func migrate(format Format, version, current int, data []byte) ([]byte, error) {
+	if version < 1 || version > current {
+		return nil, fmt.Errorf("%w: %s format version %d (supported: 1 to %d)", ErrUnsupportedVersion, format, version, current)
+	}
+	for ; version < current; version++ {
+		migrations.RLock()
+		m := migrations.funcs[migrationKey{format: format, from: version}]
+		migrations.RUnlock()
+		if m == nil {
+			return nil, fmt.Errorf("%w: no migration of %s format from version %d", ErrUnsupportedVersion, format, version)
+		}
+		var err error
+		if data, err = m(data); err != nil {
+			return nil, fmt.Errorf("migrate %s format from version %d: %w", format, version, err)
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,87 @@
+package bst
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFormat_String(t *testing.T) {
+	assert.Equal(t, "binary", FormatBinary.String())
+	assert.Equal(t, "json", FormatJSON.String())
+	assert.Equal(t, "proto", FormatProto.String())
+	assert.Equal(t, "codec", FormatCodec.String())
+	assert.Equal(t, "Format(0)", Format(0).String())
+}
+
+func TestMigrate(t *testing.T) {
+	// use a format of its own, as migrations are registered globally
+	format := Format(100)
+	RegisterMigration(format, 1, func(data []byte) ([]byte, error) {
+		return append(data, '2'), nil
+	})
+	RegisterMigration(format, 2, func(data []byte) ([]byte, error) {
+		return append(data, '3'), nil
+	})
+	assert.Panics(t, func() { RegisterMigration(format, 1, nil) }, "expected panic for a duplicate migration")
+	assert.Panics(t, func() { RegisterMigration(format, 0, nil) }, "expected panic for an invalid version")
+
+	data, err := migrate(format, 1, 3, []byte("1"))
+	require.NoError(t, err)
+	assert.Equal(t, "123", string(data), "expected every migration to apply in turn")
+
+	data, err = migrate(format, 3, 3, []byte("3"))
+	require.NoError(t, err)
+	assert.Equal(t, "3", string(data), "expected current data to be left unchanged")
+
+	_, err = migrate(format, 4, 3, nil)
+	assert.ErrorIs(t, err, ErrUnsupportedVersion, "expected error for a newer version")
+	_, err = migrate(format, 0, 3, nil)
+	assert.ErrorIs(t, err, ErrUnsupportedVersion, "expected error for an invalid version")
+	_, err = migrate(format, 1, 4, nil)
+	assert.ErrorIs(t, err, ErrUnsupportedVersion, "expected error for a missing migration")
+
+	failure := errors.New("failure")
+	RegisterMigration(format, 3, func(data []byte) ([]byte, error) { return nil, failure })
+	_, err = migrate(format, 1, 4, nil)
+	assert.ErrorIs(t, err, failure)
+}
+
+func TestTree_UnmarshalBinary_versions(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	tree := New[int, string, struct{}](less)
+	for i := 0; i < 10; i++ {
+		tree.Insert(i, "value")
+	}
+	data, err := tree.MarshalBinary()
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(data, binaryMagic), "expected a version header")
+
+	// data written before the version header was added is version 1
+	legacy := data[len(binaryMagic)+1:]
+	restored := New[int, string, struct{}](less)
+	require.NoError(t, restored.UnmarshalBinary(legacy))
+	assert.True(t, tree.EqualStructure(restored, nil), "expected data without a header to be restored")
+
+	newer := append(append([]byte{}, binaryMagic...), FormatVersion+1)
+	newer = append(newer, legacy...)
+	assert.ErrorIs(t, restored.UnmarshalBinary(newer), ErrUnsupportedVersion)
+	assert.Error(t, restored.UnmarshalBinary(binaryMagic), "expected error for a truncated header")
+}
+
+func TestTree_Unmarshal_newerVersion(t *testing.T) {
+	tree := New[int, string, struct{}](func(a, b int) bool { return a < b })
+	tree.Insert(1, "one")
+
+	assert.ErrorIs(t, tree.UnmarshalJSON([]byte(`{"version":2,"nodes":[]}`)), ErrUnsupportedVersion)
+	assert.ErrorIs(t, tree.UnmarshalWith([]byte(`{"Version":2,"Nodes":[]}`), json.Unmarshal), ErrUnsupportedVersion)
+	assert.ErrorIs(t, tree.UnmarshalProto([]byte{0x10, 0x02}, ProtoCodec[int, string, struct{}]{}), ErrUnsupportedVersion)
+	assert.Equal(t, 1, tree.Size(), "expected tree to be left unchanged")
+
+	// data written before versions were recorded is version 1
+	require.NoError(t, tree.UnmarshalJSON([]byte(`{"nodes":[]}`)))
+	assert.Equal(t, 0, tree.Size())
+}
@@ -0,0 +1,70 @@
+package bst
+
+import "fmt"
+
+// OpKind identifies the kind of an operation decoded by DecodeOps.
+type OpKind byte
+
+const (
+	OpInsert      OpKind = iota // Tree.Insert(Key, ...)
+	OpDelete                    // Tree.Delete on the node with Key, if any
+	OpSearch                    // Tree.Search(Key)
+	OpRotateLeft                // Tree.RotateLeft on the node with Key, if any
+	OpRotateRight               // Tree.RotateRight on the node with Key, if any
+
+	numOpKinds = iota
+)
+
+// Op is a tree operation decoded from a byte stream by DecodeOps.
+type Op struct {
+	Kind OpKind
+	Key  int
+}
+
+// String returns the operation as a method call, such as "Insert(3)".
+func (o Op) String() string {
+	switch o.Kind {
+	case OpInsert:
+		return fmt.Sprintf("Insert(%d)", o.Key)
+	case OpDelete:
+		return fmt.Sprintf("Delete(%d)", o.Key)
+	case OpSearch:
+		return fmt.Sprintf("Search(%d)", o.Key)
+	case OpRotateLeft:
+		return fmt.Sprintf("RotateLeft(%d)", o.Key)
+	case OpRotateRight:
+		return fmt.Sprintf("RotateRight(%d)", o.Key)
+	}
+	return fmt.Sprintf("Op(%d)", o.Kind)
+}
+
+// DecodeOps interprets data as a sequence of tree operations, for use in fuzz targets.
+//
+// Every pair of bytes decodes to one operation: the first byte selects the kind of operation
+// (modulo the number of kinds), and the second byte is the key, in the range [0, 256).
+// A trailing odd byte is ignored, so that every byte stream decodes to a valid sequence,
+// and small keys make the fuzzer likely to operate on keys already in the tree.
+//
+// Returns:
+//   - The decoded operations, in order.
+func DecodeOps(data []byte) []Op {
+	ops := make([]Op, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		ops = append(ops, Op{Kind: OpKind(data[i] % numOpKinds), Key: int(data[i+1])})
+	}
+	return ops
+}
+
+// EncodeOps is the inverse of DecodeOps, for building seed corpora from readable operations.
+//
+// Keys outside [0, 256) are truncated to their low byte.
+//
+// Returns:
+//   - A byte stream decoding to ops.
+func EncodeOps(ops []Op) []byte {
+	data := make([]byte, 0, 2*len(ops))
+	for _, op := range ops {
+		data = append(data, byte(op.Kind), byte(op.Key))
+	}
+	return data
+}
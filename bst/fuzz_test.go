@@ -0,0 +1,94 @@
+package bst
+
+import (
+	"github.com/stretchr/testify/assert"
+	"slices"
+	"testing"
+)
+
+func FuzzTree(f *testing.F) {
+	f.Add(EncodeOps([]Op{
+		{OpInsert, 50}, {OpInsert, 25}, {OpInsert, 75}, {OpInsert, 10}, {OpInsert, 30},
+		{OpRotateRight, 50}, {OpRotateLeft, 25}, {OpSearch, 30}, {OpDelete, 25}, {OpDelete, 50},
+	}))
+	f.Add(EncodeOps([]Op{
+		{OpInsert, 1}, {OpInsert, 2}, {OpInsert, 3}, {OpRotateLeft, 1}, {OpRotateLeft, 2},
+		{OpInsert, 2}, {OpDelete, 2}, {OpRotateRight, 3}, {OpSearch, 2}, {OpDelete, 4},
+	}))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		tree := New[int, int, struct{}](func(a, b int) bool {
+			return a < b
+		})
+		model := map[int]int{}
+
+		for i, op := range DecodeOps(data) {
+			n, found := tree.Search(op.Key)
+			value, exists := model[op.Key]
+			if found != exists || (found && tree.Value(n) != value) {
+				t.Fatalf("op %d (%v): Search returned (%d, %v), expected (%d, %v)", i, op, tree.Value(n), found, value, exists)
+			}
+
+			switch op.Kind {
+			case OpInsert:
+				if _, inserted := tree.Insert(op.Key, i); inserted == exists {
+					t.Fatalf("op %d (%v): Insert returned %v, expected %v", i, op, inserted, !exists)
+				}
+				model[op.Key] = i
+			case OpDelete:
+				if _, deleted := tree.Delete(n); deleted != exists {
+					t.Fatalf("op %d (%v): Delete returned %v, expected %v", i, op, deleted, exists)
+				}
+				delete(model, op.Key)
+			case OpRotateLeft:
+				tree.RotateLeft(n)
+			case OpRotateRight:
+				tree.RotateRight(n)
+			}
+
+			if err := tree.IsTreeValid(); err != nil {
+				t.Fatalf("op %d (%v): %v\n%s", i, op, err, tree)
+			}
+			checkOrder(t, tree, model)
+		}
+	})
+}
+
+// checkOrder checks that an in-order walk of tree visits exactly the keys and values of model, in ascending order.
+func checkOrder(t *testing.T, tree *Tree[int, int, struct{}], model map[int]int) {
+	t.Helper()
+	expected := make([]int, 0, len(model))
+	for key := range model {
+		expected = append(expected, key)
+	}
+	slices.Sort(expected)
+
+	keys := make([]int, 0, tree.Size())
+	for n := tree.Min(tree.Root()); !tree.IsNil(n); n = tree.Successor(n) {
+		keys = append(keys, tree.Key(n))
+		if tree.Value(n) != model[tree.Key(n)] {
+			t.Fatalf("node %d has value %d, expected %d", tree.Key(n), tree.Value(n), model[tree.Key(n)])
+		}
+	}
+	if !slices.Equal(keys, expected) {
+		t.Fatalf("in-order walk visited keys %v, expected %v", keys, expected)
+	}
+	if tree.Size() != len(model) {
+		t.Fatalf("Size() returned %d, expected %d", tree.Size(), len(model))
+	}
+}
+
+func TestDecodeOps(t *testing.T) {
+	ops := []Op{{OpInsert, 3}, {OpDelete, 255}, {OpSearch, 0}, {OpRotateLeft, 7}, {OpRotateRight, 9}}
+	assert.Equal(t, ops, DecodeOps(EncodeOps(ops)))
+
+	// kinds wrap around, and a trailing odd byte is ignored
+	assert.Equal(t, []Op{{OpDelete, 4}, {OpInsert, 200}}, DecodeOps([]byte{6, 4, 5, 200, 1}))
+	assert.Empty(t, DecodeOps(nil))
+
+	assert.Equal(t, "Insert(3)", ops[0].String())
+	assert.Equal(t, "Delete(255)", ops[1].String())
+	assert.Equal(t, "Search(0)", ops[2].String())
+	assert.Equal(t, "RotateLeft(7)", ops[3].String())
+	assert.Equal(t, "RotateRight(9)", ops[4].String())
+	assert.Equal(t, "Op(9)", Op{Kind: 9}.String())
+}
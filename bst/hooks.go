@@ -0,0 +1,54 @@
+package bst
+
+// Hooks are functions called by Tree.Insert, Tree.Delete and Tree.Transplant at the points where
+// self-balancing trees restore their invariants, so that a tree extending bst.Tree can reuse the
+// descent and relinking of bst.Tree, and implement only its fixups. See Tree.SetHooks.
+//
+// Any hook may be nil. Hooks may restructure the tree with the tree builder API (see the package
+// documentation, "Extending bst.Tree"), such as Tree.RotateLeft and Tree.RotateRight, but must not
+// insert or delete nodes themselves.
+type Hooks[K, V, M any] struct {
+
+	// AfterInsert is called with each new node, once it is linked into the tree as a leaf by
+	// Tree.Insert or Tree.InsertAt, and the subtree sizes of its ancestors are up to date, e.g. to set
+	// its metadata and rebalance the tree. It is not called when Tree.Insert updates an existing key.
+	AfterInsert func(n *Node[K, V, M])
+
+	// BeforeDelete is called with each node about to be deleted by Tree.Delete, or by the methods
+	// deleting with Tree.Delete, such as Tree.RangeDelete and Tree.PopMin, while the node is still
+	// linked into the tree, e.g. to rotate it down to a leaf.
+	BeforeDelete func(n *Node[K, V, M])
+
+	// AfterTransplant is called once Tree.Transplant has replaced old with replacement, which may be
+	// the sentinel nil node, including the transplants made by Tree.Delete to unlink a node, e.g. to
+	// record where a fixup must begin. Subtree sizes may not yet be up to date.
+	AfterTransplant func(old, replacement *Node[K, V, M])
+}
+
+// SetHooks registers hooks called when nodes are inserted, deleted and transplanted, replacing any
+// previously registered hooks. Passing a zero Hooks disables them.
+//
+// Hooks let a tree extending bst.Tree keep the insertion and deletion logic of bst.Tree, rather than
+// reimplementing it: for example, a treap can rotate each new node up by priority in AfterInsert, and
+// rotate each node down to a leaf in BeforeDelete, leaving bst.Tree to link and unlink it.
+//
+// This function is intended to be used only when extending bst.Tree.
+//
+// Example Usage:
+//
+//	// keep a treap ordered by the priorities held in node metadata
+//	tree.SetHooks(bst.Hooks[int, string, uint32]{
+//		AfterInsert: func(n *bst.Node[int, string, uint32]) {
+//			tree.SetMetadata(n, rand.Uint32())
+//			for p := tree.Parent(n); !tree.IsNil(p) && tree.Metadata(p) < tree.Metadata(n); p = tree.Parent(n) {
+//				if n == tree.Left(p) {
+//					tree.RotateRight(p)
+//				} else {
+//					tree.RotateLeft(p)
+//				}
+//			}
+//		},
+//	})
+func (t *Tree[K, V, M]) SetHooks(hooks Hooks[K, V, M]) {
+	t.hooks = hooks
+}
@@ -0,0 +1,109 @@
+package bst
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+// newTreap returns a treap built on Tree with hooks, keeping node priorities in metadata.
+func newTreap(rng *rand.Rand) *Tree[int, int, uint32] {
+	tree := New[int, int, uint32](func(a, b int) bool { return a < b })
+	tree.SetHooks(Hooks[int, int, uint32]{
+		AfterInsert: func(n *Node[int, int, uint32]) {
+			tree.SetMetadata(n, rng.Uint32())
+			for p := tree.Parent(n); !tree.IsNil(p) && tree.Metadata(p) < tree.Metadata(n); p = tree.Parent(n) {
+				if n == tree.Left(p) {
+					tree.RotateRight(p)
+				} else {
+					tree.RotateLeft(p)
+				}
+			}
+		},
+		BeforeDelete: func(n *Node[int, int, uint32]) {
+			for {
+				l, r := tree.Left(n), tree.Right(n)
+				switch {
+				case tree.IsNil(l) && tree.IsNil(r):
+					return
+				case tree.IsNil(r) || (!tree.IsNil(l) && tree.Metadata(l) > tree.Metadata(r)):
+					tree.RotateRight(n)
+				default:
+					tree.RotateLeft(n)
+				}
+			}
+		},
+	})
+	return tree
+}
+
+// checkHeap returns false if any node has a greater priority than its parent.
+func checkHeap(tree *Tree[int, int, uint32]) bool {
+	ok := true
+	tree.TraverseInOrder(tree.Root(), func(n *Node[int, int, uint32]) bool {
+		if p := tree.Parent(n); !tree.IsNil(p) && tree.Metadata(p) < tree.Metadata(n) {
+			ok = false
+		}
+		return ok
+	})
+	return ok
+}
+
+func TestTree_SetHooks(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tree := newTreap(rng)
+
+	for _, key := range rng.Perm(1000) {
+		tree.Insert(key, key)
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.True(t, checkHeap(tree), "expected priorities to be heap ordered after insertion")
+	assert.Less(t, tree.Height(), 40, "expected the treap to be balanced")
+
+	for _, key := range rng.Perm(1000)[:500] {
+		n, found := tree.Search(key)
+		require.True(t, found)
+		tree.Delete(n)
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 500, tree.Size())
+	assert.True(t, checkHeap(tree), "expected priorities to be heap ordered after deletion")
+
+	// updating an existing key does not insert a node
+	calls := 0
+	tree.SetHooks(Hooks[int, int, uint32]{
+		AfterInsert: func(n *Node[int, int, uint32]) { calls++ },
+	})
+	tree.Insert(tree.Key(tree.Min(tree.Root())), 0)
+	assert.Equal(t, 0, calls, "expected AfterInsert not to be called when updating a key")
+
+	tree.SetHooks(Hooks[int, int, uint32]{})
+	tree.Insert(-1, -1)
+	require.NoError(t, tree.IsTreeValid())
+}
+
+func TestTree_SetHooks_AfterTransplant(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	for _, key := range []int{2, 1, 3} {
+		tree.Insert(key, key)
+	}
+
+	var old, replacement []int
+	tree.SetHooks(Hooks[int, int, struct{}]{
+		AfterTransplant: func(o, r *Node[int, int, struct{}]) {
+			old = append(old, tree.Key(o))
+			if tree.IsNil(r) {
+				replacement = append(replacement, 0)
+			} else {
+				replacement = append(replacement, tree.Key(r))
+			}
+		},
+	})
+
+	n, _ := tree.Search(1)
+	tree.Delete(n)
+	assert.Equal(t, []int{1}, old, "expected the deleted leaf to be transplanted")
+	assert.Equal(t, []int{0}, replacement, "expected the leaf to be replaced by the sentinel")
+	require.NoError(t, tree.IsTreeValid())
+}
@@ -0,0 +1,106 @@
+package bst
+
+import (
+	"iter"
+	"slices"
+)
+
+// KV is a key and its value, as returned by Collect.
+type KV[K, V any] struct {
+	Key   K
+	Value V
+}
+
+// All returns an iterator over the keys and values of the tree, in ascending key order, for use with
+// range-over-func loops and the iterator functions of the standard library, such as maps.Collect.
+//
+// The tree must not be modified while iterating (see Tree.AscendDelete to delete nodes while iterating).
+//
+// Example Usage:
+//
+//	for key, value := range tree.All() {
+//		fmt.Println(key, value)
+//	}
+func (t *Tree[K, V, M]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for n := t.Min(t.root); !t.IsNil(n); n = t.Successor(n) {
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the keys and values of the tree, in descending key order.
+// See Tree.All.
+func (t *Tree[K, V, M]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for n := t.Max(t.root); !t.IsNil(n); n = t.Predecessor(n) {
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns an iterator over the keys of the tree, in ascending order, e.g. to pass to slices.Collect
+// or slices.AppendSeq. See Tree.All.
+//
+// Example Usage:
+//
+//	// the keys, then the keys of a second tree merged in order
+//	keys := slices.Collect(tree.Keys())
+//	merged := slices.Sorted(slices.Values(slices.AppendSeq(keys, other.Keys())))
+func (t *Tree[K, V, M]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range t.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the values of the tree, in ascending key order. See Tree.All.
+func (t *Tree[K, V, M]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, value := range t.All() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Collect returns the keys and values of the tree, in ascending key order.
+//
+// Example Usage:
+//
+//	for _, kv := range bst.Collect(tree) {
+//		fmt.Println(kv.Key, kv.Value)
+//	}
+//
+// Returns:
+//   - A slice of t.Size() key-value pairs, or nil if the tree is empty.
+func Collect[K, V, M any](t *Tree[K, V, M]) []KV[K, V] {
+	if t.Size() == 0 {
+		return nil
+	}
+	kvs := make([]KV[K, V], 0, t.Size())
+	for key, value := range t.All() {
+		kvs = append(kvs, KV[K, V]{Key: key, Value: value})
+	}
+	return kvs
+}
+
+// AppendKeys appends the keys of the tree to dst, in ascending order, growing dst at most once.
+//
+// Returns:
+//   - The extended slice.
+func AppendKeys[K, V, M any](dst []K, t *Tree[K, V, M]) []K {
+	dst = slices.Grow(dst, t.Size())
+	for key := range t.All() {
+		dst = append(dst, key)
+	}
+	return dst
+}
@@ -0,0 +1,69 @@
+package bst
+
+import (
+	"github.com/stretchr/testify/assert"
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestTree_All(t *testing.T) {
+	tree := New[int, string, struct{}](func(a, b int) bool { return a < b })
+	for range tree.All() {
+		t.Error("expected nothing to be yielded by an empty tree")
+	}
+	assert.Nil(t, Collect(tree), "expected nil for an empty tree")
+
+	perm := rand.New(rand.NewSource(1)).Perm(100)
+	for _, key := range perm {
+		tree.Insert(key, string(rune('a'+key%26)))
+	}
+	sorted := slices.Sorted(slices.Values(perm))
+
+	assert.Equal(t, sorted, slices.Collect(tree.Keys()))
+	reversed := slices.Clone(sorted)
+	slices.Reverse(reversed)
+	var backward []int
+	for key := range tree.Backward() {
+		backward = append(backward, key)
+	}
+	assert.Equal(t, reversed, backward)
+
+	m := maps.Collect(tree.All())
+	assert.Len(t, m, 100)
+	for key, value := range m {
+		assert.Equal(t, string(rune('a'+key%26)), value)
+	}
+	values := slices.Collect(tree.Values())
+	assert.Equal(t, "a", values[0])
+	assert.Equal(t, "v", values[99])
+
+	// breaking out of the loop stops the iteration
+	var keys []int
+	for key := range tree.Keys() {
+		if key == 3 {
+			break
+		}
+		keys = append(keys, key)
+	}
+	assert.Equal(t, []int{0, 1, 2}, keys)
+}
+
+func TestCollect(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	for _, key := range []int{3, 1, 2} {
+		tree.Insert(key, key*10)
+	}
+	assert.Equal(t, []KV[int, int]{{1, 10}, {2, 20}, {3, 30}}, Collect(tree))
+}
+
+func TestAppendKeys(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	assert.Equal(t, []int{7}, AppendKeys([]int{7}, tree))
+	for _, key := range []int{3, 1, 2} {
+		tree.Insert(key, key)
+	}
+	assert.Equal(t, []int{7, 1, 2, 3}, AppendKeys([]int{7}, tree))
+	assert.Equal(t, []int{1, 2, 3}, AppendKeys(nil, tree))
+}
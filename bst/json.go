@@ -0,0 +1,88 @@
+package bst
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// jsonTree is the JSON representation of a Tree.
+type jsonTree[K, V, M any] struct {
+	Version int                 `json:"version"` // FormatVersion the tree was written with, or 0 if not recorded
+	Nodes   []jsonNode[K, V, M] `json:"nodes"`   // nodes in pre-order
+}
+
+// jsonNode is the JSON representation of a single Node.
+//
+// Children are not nested. Instead, Left and Right indicate whether the node has a left and/or right child,
+// which is enough to reconstruct the exact shape of the tree from a pre-order sequence of nodes.
+type jsonNode[K, V, M any] struct {
+	Key      K    `json:"key"`
+	Value    V    `json:"value"`
+	Metadata M    `json:"metadata"`
+	Left     bool `json:"left,omitempty"`
+	Right    bool `json:"right,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+//
+// The tree is encoded as an object holding its version (see FormatVersion) and a pre-order list of its nodes, including
+// each node's key, value and metadata, and whether it has a left and/or right child.
+// This preserves the exact shape of the tree, so that it can be restored by Tree.UnmarshalJSON
+// without rebuilding it by insertion.
+//
+// Keys, values and metadata are encoded using encoding/json.
+// The LessFunc and any options are not encoded.
+func (t *Tree[K, V, M]) MarshalJSON() ([]byte, error) {
+	jt := jsonTree[K, V, M]{
+		Version: FormatVersion,
+		Nodes:   make([]jsonNode[K, V, M], 0, t.Size()),
+	}
+	t.traversePreOrder(func(n *Node[K, V, M]) {
+		jt.Nodes = append(jt.Nodes, jsonNode[K, V, M]{
+			Key:      n.key,
+			Value:    n.value,
+			Metadata: n.metadata,
+			Left:     !t.IsNil(n.left),
+			Right:    !t.IsNil(n.right),
+		})
+	})
+	return json.Marshal(jt)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, restoring a tree encoded by Tree.MarshalJSON.
+//
+// The tree must have been created with New, so that it has a LessFunc. Its existing contents
+// are replaced, and any handles to its previous nodes become stale (see Node.BelongsTo).
+// Data written with an older FormatVersion is migrated first (see RegisterMigration).
+//
+// The restored tree is validated with Tree.IsTreeValid. If the data cannot be decoded
+// or describes an invalid tree, an error is returned and the tree is left unchanged.
+func (t *Tree[K, V, M]) UnmarshalJSON(data []byte) error {
+	if t.less == nil || t.nil == nil {
+		return fmt.Errorf("cannot unmarshal into a tree not created with New")
+	}
+
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return err
+	}
+	data, err := migrate(FormatJSON, max(header.Version, 1), FormatVersion, data)
+	if err != nil {
+		return err
+	}
+
+	var jt jsonTree[K, V, M]
+	if err := json.Unmarshal(data, &jt); err != nil {
+		return err
+	}
+
+	b := t.newBuilder(len(jt.Nodes))
+	for _, jn := range jt.Nodes {
+		if err := b.add(jn.Key, jn.Value, jn.Metadata, jn.Left, jn.Right); err != nil {
+			return err
+		}
+	}
+	return b.commit()
+}
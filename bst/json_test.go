@@ -0,0 +1,94 @@
+package bst
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestTree_MarshalJSON(t *testing.T) {
+	tree := New[int, string, int](func(a, b int) bool {
+		return a < b
+	})
+	for _, key := range []int{2, 1, 3} {
+		n, _ := tree.Insert(key, "v")
+		tree.SetMetadata(n, key*10)
+	}
+
+	data, err := json.Marshal(tree)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"version":1,"nodes":[
+		{"key":2,"value":"v","metadata":20,"left":true,"right":true},
+		{"key":1,"value":"v","metadata":10},
+		{"key":3,"value":"v","metadata":30}
+	]}`, string(data))
+
+	// empty tree
+	empty := New[int, string, int](func(a, b int) bool {
+		return a < b
+	})
+	data, err = json.Marshal(empty)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"version":1,"nodes":[]}`, string(data))
+}
+
+func TestTree_UnmarshalJSON(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+
+	// build a degenerate tree, plus some branching, to check the shape is preserved
+	tree := New[int, string, int](less)
+	for _, key := range []int{50, 20, 80, 10, 30, 70, 90, 25, 35, 75, 91, 92, 93, 94, 95} {
+		n, _ := tree.Insert(key, "value")
+		tree.SetMetadata(n, -key)
+	}
+	data, err := json.Marshal(tree)
+	require.NoError(t, err)
+
+	restored := New[int, string, int](less)
+	old, _ := restored.Insert(1, "old")
+	require.NoError(t, json.Unmarshal(data, restored))
+	require.NoError(t, restored.IsTreeValid(), "expected valid tree")
+	assert.True(t, tree.EqualStructure(restored, func(a, b string) bool { return a == b }), "expected identical shape")
+	assert.Equal(t, tree.Size(), restored.Size(), "unexpected size")
+	assert.False(t, restored.Contains(old), "expected previous nodes to become stale")
+	restored.TraverseInOrder(restored.Root(), func(n *Node[int, string, int]) bool {
+		assert.Equal(t, -restored.Key(n), restored.Metadata(n), "expected metadata to be restored")
+		assert.True(t, restored.Contains(n), "expected restored node to belong to tree")
+		return true
+	})
+
+	// round trip of an empty tree
+	empty := New[int, string, int](less)
+	data, err = json.Marshal(empty)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, restored))
+	assert.True(t, restored.IsNil(restored.Root()), "expected empty tree")
+}
+
+func TestTree_UnmarshalJSON_errors(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+
+	tests := map[string]string{
+		"malformed json":    `{"nodes":[`,
+		"out of order keys": `{"nodes":[{"key":2,"value":"","metadata":0,"left":true},{"key":3,"value":"","metadata":0}]}`,
+		"missing child":     `{"nodes":[{"key":2,"value":"","metadata":0,"left":true,"right":true},{"key":1,"value":"","metadata":0}]}`,
+		"trailing node":     `{"nodes":[{"key":2,"value":"","metadata":0},{"key":3,"value":"","metadata":0}]}`,
+		"wrong key type":    `{"nodes":[{"key":"two","value":"","metadata":0}]}`,
+		"duplicate keys":    `{"nodes":[{"key":2,"value":"","metadata":0,"right":true},{"key":2,"value":"","metadata":0}]}`,
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			tree := New[int, string, int](less)
+			n, _ := tree.Insert(1, "one")
+			assert.Error(t, json.Unmarshal([]byte(data), tree), "expected error")
+			require.NoError(t, tree.IsTreeValid(), "expected tree to remain valid")
+			assert.Equal(t, 1, tree.Size(), "expected tree to be unchanged")
+			assert.True(t, tree.Contains(n), "expected existing node to remain")
+		})
+	}
+
+	// tree not created with New
+	var tree Tree[int, string, int]
+	assert.Error(t, json.Unmarshal([]byte(`{"nodes":[]}`), &tree), "expected error unmarshaling into zero tree")
+}
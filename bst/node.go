@@ -18,6 +18,93 @@ type Node[K, V, M any] struct {
 	value               V
 	parent, left, right *Node[K, V, M]
 	metadata            M
+	gen                 uint32         // generation, incremented whenever the node is removed (see Handle)
+	size                int            // number of nodes in the subtree rooted at this node
+	tree                *Tree[K, V, M] // tree the node belongs to, nil once removed
+}
+
+// BelongsTo reports whether the node is currently part of tree t.
+//
+// A node belongs to the tree that created it (via Tree.Insert) until it is removed
+// (via Tree.Delete, or Tree.Release when extending bst.Tree). Nodes from other trees,
+// removed (stale) nodes and sentinel nil nodes do not belong to any tree.
+//
+// A removed node may be recycled for a new entry of the tree (see WithNodePool and WithAllocator),
+// after which it belongs to the tree again: keep a Handle rather than the node to detect this.
+//
+// This is an O(1) check.
+func (n *Node[K, V, M]) BelongsTo(t *Tree[K, V, M]) bool {
+	return n != nil && t != nil && n.tree == t
+}
+
+// Handle is a reference to a node which, unlike a *Node, detects that the node has been removed from
+// its tree even if the node has since been recycled for another entry (see WithNodePool and WithAllocator).
+//
+// Every node has a generation, incremented whenever it is removed, and which a handle records: once
+// the node is removed, the handle no longer refers to it, even if the node is reused by a later
+// insertion. Where nodes are recycled, handles should be kept rather than nodes.
+//
+// The zero Handle refers to no node. Handles are created with Node.Handle.
+//
+// Example Usage:
+//
+//	n, _ := tree.Insert(2, "two")
+//	h := n.Handle()
+//	tree.Delete(n)
+//	tree.Insert(3, "three") // may reuse n
+//	tree.Delete(h.Node())   // no-op: h.Node() is nil, as the entry it referred to was deleted
+type Handle[K, V, M any] struct {
+	n   *Node[K, V, M]
+	gen uint32
+}
+
+// Handle returns a handle to the node, as it is now.
+func (n *Node[K, V, M]) Handle() Handle[K, V, M] {
+	if n == nil {
+		return Handle[K, V, M]{}
+	}
+	return Handle[K, V, M]{n: n, gen: n.gen}
+}
+
+// Node returns the node referred to by the handle, or nil if the node has been removed from its tree
+// since the handle was created. Passing nil to the methods of a tree is a no-op, so the result can
+// be passed on without being checked.
+func (h Handle[K, V, M]) Node() *Node[K, V, M] {
+	if h.n == nil || h.n.gen != h.gen {
+		return nil
+	}
+	return h.n
+}
+
+// BelongsTo reports whether the node referred to by the handle is still part of tree t, and has not been
+// removed from it since the handle was created, even if it was later recycled (see Node.BelongsTo).
+//
+// This is an O(1) check.
+func (h Handle[K, V, M]) BelongsTo(t *Tree[K, V, M]) bool {
+	return h.Node().BelongsTo(t)
+}
+
+// Key returns the key of the node.
+//
+// This is equivalent to Tree.Key, for use where the tree is not at hand, such as in a TraversalFunc.
+// The sentinel nil node holds the zero value.
+func (n *Node[K, V, M]) Key() K {
+	return n.key
+}
+
+// Value returns the value of the node.
+//
+// This is equivalent to Tree.Value, for use where the tree is not at hand, such as in a TraversalFunc.
+// The sentinel nil node holds the zero value.
+func (n *Node[K, V, M]) Value() V {
+	return n.value
+}
+
+// Metadata returns the metadata of the node.
+//
+// This is equivalent to Tree.Metadata, for use where the tree is not at hand, such as in a TraversalFunc.
+func (n *Node[K, V, M]) Metadata() M {
+	return n.metadata
 }
 
 func (n *Node[K, V, M]) IsValueNil() bool {
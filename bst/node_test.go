@@ -30,3 +30,45 @@ func TestNode_String_nil(t *testing.T) {
 		"1: <nil> [{}]",
 		n.String())
 }
+
+func TestNode_BelongsTo(t *testing.T) {
+	tree := New[int, struct{}, struct{}](func(a, b int) bool { return a < b })
+	other := New[int, struct{}, struct{}](func(a, b int) bool { return a < b })
+	n, _ := tree.Insert(1, struct{}{})
+	o, _ := other.Insert(1, struct{}{})
+
+	assert.True(t, n.BelongsTo(tree), "expected node to belong to its tree")
+	assert.False(t, n.BelongsTo(other), "expected node not to belong to another tree")
+	assert.False(t, o.BelongsTo(tree), "expected foreign node not to belong to tree")
+	assert.False(t, tree.Sentinel().BelongsTo(tree), "expected sentinel not to belong to tree")
+	assert.False(t, n.BelongsTo(nil), "expected node not to belong to nil tree")
+
+	var nilNode *Node[int, struct{}, struct{}]
+	assert.False(t, nilNode.BelongsTo(tree), "expected nil node not to belong to tree")
+
+	tree.Delete(n)
+	assert.False(t, n.BelongsTo(tree), "expected deleted node not to belong to tree")
+}
+
+func TestNode_accessors(t *testing.T) {
+	tree := New[int, string, int](func(a, b int) bool { return a < b })
+	n, _ := tree.Insert(1, "one")
+	tree.SetMetadata(n, 7)
+
+	assert.Equal(t, 1, n.Key())
+	assert.Equal(t, "one", n.Value())
+	assert.Equal(t, 7, n.Metadata())
+	assert.Equal(t, tree.Key(n), n.Key())
+	assert.Equal(t, tree.Value(n), n.Value())
+	assert.Equal(t, tree.Metadata(n), n.Metadata())
+
+	// the accessors can be used without the tree, e.g. in a traversal
+	var keys []int
+	tree.Insert(0, "zero")
+	tree.TraverseInOrder(tree.Root(), func(n *Node[int, string, int]) bool {
+		keys = append(keys, n.Key())
+		return true
+	})
+	assert.Equal(t, []int{0, 1}, keys)
+	assert.Equal(t, 0, tree.Sentinel().Key())
+}
@@ -0,0 +1,124 @@
+package bst
+
+import "fmt"
+
+// Option configures optional behavior of a Tree.
+//
+// Options are passed to New when the tree is created, for example:
+//
+//	tree := New[int, string, struct{}](less, WithDuplicateKeys())
+type Option func(*options)
+
+// options holds the optional behaviors that can be enabled on a Tree.
+type options struct {
+	duplicates         bool            // allow multiple nodes with equal keys
+	policy             DuplicatePolicy // resolution of insertions of existing keys (see WithDuplicatePolicy)
+	reverse            bool            // order keys by the reverse of the LessFunc
+	degradedThreshold  float64         // depth to log2(size) ratio above which degradedFunc is called
+	degradedFunc       DegradationFunc // function called when the tree is degraded, if any
+	rebalanceThreshold float64         // depth to log2(size) ratio above which the tree is rebalanced
+	pooled             bool            // recycle released nodes (see WithNodePool)
+	allocator          any             // Allocator[K, V, M] allocating nodes (see WithAllocator), if any
+	deltaLimit         int             // number of changes retained for Tree.DeltaSince (see WithDeltaLog)
+}
+
+// WithDuplicateKeys enables multiset mode, allowing several nodes with equal keys to coexist.
+//
+// In multiset mode:
+//   - Tree.Insert always creates a new node. Nodes with equal keys are ordered by insertion,
+//     so an in-order traversal visits them from the first inserted to the last inserted.
+//   - Tree.Search and Tree.Ceiling return the first node (in order) with an equal key.
+//   - Tree.Floor returns the last node (in order) with an equal key.
+//   - Tree.Count returns the number of nodes with a given key.
+//
+// This is equivalent to WithDuplicatePolicy(DuplicateAppend).
+func WithDuplicateKeys() Option {
+	return WithDuplicatePolicy(DuplicateAppend)
+}
+
+// WithDuplicatePolicy sets how Tree.Insert resolves the insertion of a key already in the tree, rather
+// than silently replacing its value (DuplicateReplace, the default). DuplicateAppend enables multiset
+// mode, as WithDuplicateKeys does.
+//
+// Example Usage:
+//
+//	// reject duplicate keys: Tree.TryInsert returns ErrDuplicateKey, and Tree.Insert panics
+//	tree := New[string, User, struct{}](less, WithDuplicatePolicy(DuplicateError))
+//
+// WithDuplicatePolicy panics if policy is not one of the DuplicatePolicy constants.
+func WithDuplicatePolicy(policy DuplicatePolicy) Option {
+	if policy > DuplicateAppend {
+		panic(fmt.Sprintf("bst: invalid duplicate policy %d", policy))
+	}
+	return func(o *options) {
+		o.policy = policy
+		o.duplicates = policy == DuplicateAppend
+	}
+}
+
+// WithReverseOrder orders the tree by the reverse of its LessFunc, so that a tree with a natural
+// comparator, such as func(a, b int) bool { return a < b }, is ordered from the largest key to
+// the smallest. This avoids writing an inverted comparator by hand (see also comparators.Reverse).
+//
+// Every method follows the order of the tree, rather than the natural order of the keys:
+//   - Tree.Min returns the node with the largest key, and Tree.Max the node with the smallest key.
+//   - Tree.Successor moves towards smaller keys, so an in-order traversal visits keys in descending order.
+//   - Tree.Floor returns the node with the smallest key greater than or equal to the given key,
+//     and Tree.Ceiling the node with the largest key less than or equal to it.
+//   - Tree.Rank returns the number of keys greater than the given key, and Tree.CountRange(lo, hi)
+//     counts keys k such that hi < k <= lo.
+func WithReverseOrder() Option {
+	return func(o *options) {
+		o.reverse = true
+	}
+}
+
+// WithDegradationFunc registers f to be called whenever an insertion leaves the tree degraded, that is,
+// when the new node's depth exceeds threshold times log2 of the number of nodes in the tree.
+// This allows degradation to be reported (e.g., logged or counted in a metric) or handled by the caller.
+//
+// The depth of the new node is found by walking up from it after each insertion, which is O(h),
+// the same order as the insertion itself. A balanced tree has a height of about log2(n), and a tree
+// built by random insertions a height of about 3·log2(n), so a threshold of 4 or more detects
+// degradation without reacting to random insertion orders.
+//
+// This option is intended for plain bst.Tree trees: trees extending bst.Tree, such as rbtree.Tree,
+// balance themselves.
+//
+// WithDegradationFunc panics if threshold is not greater than 1.
+func WithDegradationFunc(threshold float64, f DegradationFunc) Option {
+	checkThreshold(threshold)
+	return func(o *options) {
+		o.degradedThreshold = threshold
+		o.degradedFunc = f
+	}
+}
+
+// WithAutoRebalance rebalances the tree (see Tree.Rebalance) whenever an insertion leaves it degraded,
+// that is, when the new node's depth exceeds threshold times log2 of the number of nodes in the tree.
+// This is a safety net against adversarial insertion orders, at a much lower per-operation cost than
+// a self-balancing tree such as rbtree.Tree.
+//
+// Each rebalance takes O(n) time, and leaves the tree with a height of about log2(n). Under a sustained
+// sorted insertion order, the tree is rebalanced every (threshold-1)·log2(n) insertions or so, so higher
+// thresholds trade search performance for fewer rebalances. If a DegradationFunc is also registered
+// (see WithDegradationFunc), it is called first.
+//
+// This option is intended for plain bst.Tree trees: trees extending bst.Tree which keep balancing
+// information in node metadata, such as rbtree.Tree and ziptree.Tree, balance themselves, and rebalancing
+// would discard that information, so their constructors panic if given this option (see Tree.AutoRebalance).
+//
+// WithAutoRebalance panics if threshold is not greater than 1.
+func WithAutoRebalance(threshold float64) Option {
+	checkThreshold(threshold)
+	return func(o *options) {
+		o.rebalanceThreshold = threshold
+	}
+}
+
+// checkThreshold panics if threshold is not a valid degradation threshold.
+func checkThreshold(threshold float64) {
+	if !(threshold > 1) {
+		panic(fmt.Sprintf("bst: degradation threshold must be greater than 1, got %v", threshold))
+	}
+}
@@ -0,0 +1,45 @@
+package bst
+
+import "sync"
+
+// WithNodePool enables node recycling: nodes released by Tree.Delete (or Tree.Release, in trees
+// extending bst.Tree) are kept in a pool and reused by later insertions, rather than left for the
+// garbage collector. This reduces allocations and garbage collection work for workloads that churn
+// through many short-lived entries.
+//
+// The pool is a sync.Pool, so idle nodes are still reclaimed by the garbage collector over time.
+// Released nodes are cleared before being pooled, so their keys and values are not retained.
+// WithNodePool has no effect if an Allocator is set with WithAllocator.
+//
+// ⚠️ Warning: A recycled node may be returned by a later Tree.Insert, so deleted nodes must not be
+// kept: Node.BelongsTo, Node.Key and Node.Value report on whichever entry the node holds now, rather
+// than on the deleted entry. Keep a Handle instead (see Node.Handle), which records the generation of
+// the node, incremented whenever it is removed: Handle.Node and Handle.BelongsTo detect that the entry
+// was deleted, even once the node is recycled. A Cursor does so too, so the current node of a cursor
+// can be deleted while iterating.
+func WithNodePool() Option {
+	return func(o *options) {
+		o.pooled = true
+	}
+}
+
+// poolAllocator is the Allocator used by trees created with WithNodePool.
+type poolAllocator[K, V, M any] struct {
+	pool sync.Pool
+}
+
+// New returns a node from the pool, or a newly allocated node if the pool is empty.
+func (a *poolAllocator[K, V, M]) New() *Node[K, V, M] {
+	if n, ok := a.pool.Get().(*Node[K, V, M]); ok {
+		return n
+	}
+	return &Node[K, V, M]{}
+}
+
+// Free returns n to the pool.
+func (a *poolAllocator[K, V, M]) Free(n *Node[K, V, M]) {
+	a.pool.Put(n)
+}
+
+// Reset does nothing: pooled nodes are reclaimed by the garbage collector once idle.
+func (a *poolAllocator[K, V, M]) Reset() {}
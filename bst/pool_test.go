@@ -0,0 +1,111 @@
+package bst
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func TestWithNodePool(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tree := New[int, []int, struct{}](func(a, b int) bool { return a < b }, WithNodePool())
+	expected := make(map[int]int)
+	for i := 0; i < 10000; i++ {
+		key := rng.Intn(200)
+		if n, found := tree.Search(key); found {
+			_, deleted := tree.Delete(n)
+			require.True(t, deleted)
+			delete(expected, key)
+
+			// released nodes are cleared before being recycled
+			assert.False(t, n.BelongsTo(tree))
+			assert.Nil(t, n.Value(), "expected released node to be cleared")
+		} else {
+			tree.Insert(key, []int{i})
+			expected[key] = i
+		}
+	}
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	assert.Equal(t, len(expected), tree.Size())
+	for key, value := range expected {
+		n, found := tree.Search(key)
+		require.True(t, found)
+		assert.Equal(t, []int{value}, tree.Value(n))
+	}
+}
+
+func TestWithNodePool_subtrees(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b }, WithNodePool())
+	for _, key := range []int{4, 2, 6, 1, 3, 5, 7} {
+		tree.Insert(key, key)
+	}
+	n, _ := tree.Search(2)
+	part, ok := tree.CopySubtree(n)
+	require.True(t, ok)
+	branch, ok := tree.DetachSubtree(n)
+	require.True(t, ok)
+
+	// nodes released by trees created from a pooled tree are recycled too
+	for _, tr := range []*Tree[int, int, struct{}]{part, branch} {
+		m, _ := tr.Search(1)
+		_, deleted := tr.Delete(m)
+		require.True(t, deleted)
+		assert.Zero(t, m.Value(), "expected released node to be cleared")
+		tr.Insert(0, 0)
+		require.NoError(t, tr.IsTreeValid(), "expected valid tree")
+		assert.Equal(t, []int{0, 2, 3}, keysOf(tr))
+	}
+}
+
+func TestHandle_recycled(t *testing.T) {
+	for name, opt := range map[string]Option{
+		"pool":  WithNodePool(),
+		"arena": WithAllocator(NewArena[int, string, struct{}](0)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			tree := New[int, string, struct{}](func(a, b int) bool { return a < b }, opt)
+			a, _ := tree.Insert(2, "two")
+			h := a.Handle()
+			assert.True(t, h.BelongsTo(tree))
+			assert.Same(t, a, h.Node())
+
+			tree.Delete(a)
+			assert.False(t, h.BelongsTo(tree), "expected the handle to a deleted node not to belong to the tree")
+			assert.Nil(t, h.Node())
+
+			// recycling the node does not revive the handle
+			for i := 0; i < 100; i++ {
+				tree.Insert(i+3, "other")
+			}
+			assert.False(t, h.BelongsTo(tree), "expected the handle to a recycled node not to belong to the tree")
+			_, deleted := tree.Delete(h.Node())
+			assert.False(t, deleted, "expected deleting through a stale handle to be a no-op")
+			assert.Equal(t, 100, tree.Size())
+			require.NoError(t, tree.IsTreeValid())
+		})
+	}
+
+	var zero Handle[int, string, struct{}]
+	assert.Nil(t, zero.Node())
+	assert.False(t, zero.BelongsTo(New[int, string, struct{}](func(a, b int) bool { return a < b })))
+}
+
+func TestCursor_recycled(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b }, WithAllocator(NewArena[int, int, struct{}](0)))
+	for i := 0; i < 10; i++ {
+		tree.Insert(i*10, i)
+	}
+	var visited []int
+	for c := tree.Cursor(); c.Next(); {
+		visited = append(visited, c.Key())
+		if c.Key() == 30 {
+			n := c.Node()
+			tree.Delete(n)
+			m, _ := tree.Insert(5, 0) // reuses the current node of the cursor, before it
+			require.Same(t, n, m)
+			assert.False(t, c.Valid(), "expected the cursor to detect the deletion of its recycled node")
+		}
+	}
+	assert.Equal(t, []int{0, 10, 20, 30, 40, 50, 60, 70, 80, 90}, visited)
+}
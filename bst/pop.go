@@ -0,0 +1,61 @@
+package bst
+
+// PopMin removes the node with the smallest key from the tree, and returns its key and value.
+//
+// As the minimum of the tree is cached (see Tree.Min), the node is found in O(1) time, so the cost
+// of PopMin is that of Tree.Delete alone. This suits priority-queue style consumers.
+//
+// Nodes are deleted with Tree.Delete. Trees extending bst.Tree must delete nodes with their own
+// Delete method, using PopMinFunc (as rbtree.Tree.PopMin does).
+//
+// Returns:
+//   - (key, value, true) if the tree was not empty.
+//   - (zero key, zero value, false) if the tree is empty.
+func (t *Tree[K, V, M]) PopMin() (K, V, bool) {
+	return PopMinFunc(t, t.deleteNode)
+}
+
+// PopMax removes the node with the largest key from the tree, and returns its key and value.
+// See Tree.PopMin.
+//
+// Returns:
+//   - (key, value, true) if the tree was not empty.
+//   - (zero key, zero value, false) if the tree is empty.
+func (t *Tree[K, V, M]) PopMax() (K, V, bool) {
+	return PopMaxFunc(t, t.deleteNode)
+}
+
+// deleteNode deletes n with Tree.Delete, and returns whether it was deleted.
+func (t *Tree[K, V, M]) deleteNode(n *Node[K, V, M]) bool {
+	_, deleted := t.Delete(n)
+	return deleted
+}
+
+// PopMinFunc implements Tree.PopMin for trees extending bst.Tree, deleting the node
+// with del (such as rbtree.Tree.Delete) rather than with Tree.Delete.
+//
+// Returns:
+//   - (key, value, true) if the tree was not empty.
+//   - (zero key, zero value, false) if the tree is empty.
+func PopMinFunc[K, V, M any](t *Tree[K, V, M], del func(n *Node[K, V, M]) bool) (K, V, bool) {
+	return t.pop(t.Min(t.root), del)
+}
+
+// PopMaxFunc implements Tree.PopMax for trees extending bst.Tree, deleting the node
+// with del (such as rbtree.Tree.Delete) rather than with Tree.Delete.
+//
+// Returns:
+//   - (key, value, true) if the tree was not empty.
+//   - (zero key, zero value, false) if the tree is empty.
+func PopMaxFunc[K, V, M any](t *Tree[K, V, M], del func(n *Node[K, V, M]) bool) (K, V, bool) {
+	return t.pop(t.Max(t.root), del)
+}
+
+// pop deletes n with del, and returns its key and value, or false if n is the sentinel nil node.
+func (t *Tree[K, V, M]) pop(n *Node[K, V, M], del func(n *Node[K, V, M]) bool) (K, V, bool) {
+	key, value, ok := t.entry(n)
+	if ok {
+		del(n)
+	}
+	return key, value, ok
+}
@@ -0,0 +1,74 @@
+package bst
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+// requireBounds checks the cached minimum and maximum of tree match its leftmost and rightmost nodes.
+func requireBounds(t *testing.T, tree *Tree[int, int, struct{}]) {
+	t.Helper()
+	require.Equal(t, tree.min(tree.Root()), tree.Min(tree.Root()), "unexpected cached minimum")
+	require.Equal(t, tree.max(tree.Root()), tree.Max(tree.Root()), "unexpected cached maximum")
+}
+
+func TestTree_MinMax_cached(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	requireBounds(t, tree)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		key := rng.Intn(200)
+		if rng.Intn(3) == 0 {
+			n, _ := tree.Search(key)
+			tree.Delete(n)
+		} else {
+			tree.Insert(key, i)
+		}
+		requireBounds(t, tree)
+	}
+
+	// the cache survives rotations and rebalancing
+	tree.Rebalance()
+	requireBounds(t, tree)
+
+	// and is discarded when subtrees move between trees
+	sub, ok := tree.DetachSubtree(tree.Root().left)
+	require.True(t, ok)
+	requireBounds(t, tree)
+	require.NoError(t, tree.AttachSubtree(sub))
+	requireBounds(t, tree)
+	requireBounds(t, sub)
+
+	tree.Clear()
+	requireBounds(t, tree)
+	tree.Insert(1, 1)
+	requireBounds(t, tree)
+}
+
+func TestTree_PopMin(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	_, _, ok := tree.PopMin()
+	assert.False(t, ok, "expected empty tree to have no minimum")
+	_, _, ok = tree.PopMax()
+	assert.False(t, ok, "expected empty tree to have no maximum")
+
+	rng := rand.New(rand.NewSource(1))
+	for _, key := range rng.Perm(100) {
+		tree.Insert(key, key*10)
+	}
+	for i := 0; i < 50; i++ {
+		key, value, ok := tree.PopMin()
+		require.True(t, ok)
+		assert.Equal(t, i, key)
+		assert.Equal(t, i*10, value)
+
+		key, _, ok = tree.PopMax()
+		require.True(t, ok)
+		assert.Equal(t, 99-i, key)
+		requireBounds(t, tree)
+	}
+	assert.Equal(t, 0, tree.Size())
+	require.NoError(t, tree.IsTreeValid())
+}
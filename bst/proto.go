@@ -0,0 +1,256 @@
+package bst
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/mikenye/gotrees/codec"
+)
+
+// ProtoCodec holds the codecs encoding and decoding keys, values and metadata for
+// Tree.MarshalProto and Tree.UnmarshalProto. As these are generic, the protobuf schema
+// of a tree (see tree.proto) holds them as bytes, in whatever format these codecs use.
+//
+// Metadata is optional. If it is nil, metadata is not encoded, and decoded nodes hold the zero value of M.
+type ProtoCodec[K, V, M any] struct {
+	Key      codec.Codec[K]
+	Value    codec.Codec[V]
+	Metadata codec.Codec[M]
+}
+
+// Field numbers of the Tree and Node messages of tree.proto.
+const (
+	protoTreeNodes    = 1
+	protoTreeVersion  = 2
+	protoNodeKey      = 1
+	protoNodeValue    = 2
+	protoNodeMetadata = 3
+	protoNodeLeft     = 4
+	protoNodeRight    = 5
+)
+
+// Wire types of the protobuf encoding.
+const (
+	protoVarint = 0
+	protoI64    = 1
+	protoLen    = 2
+	protoI32    = 5
+)
+
+// errProtoTruncated is returned when protobuf data ends in the middle of a field.
+var errProtoTruncated = errors.New("truncated protobuf data")
+
+// MarshalProto encodes the tree as a gotrees.bst.Tree protobuf message (see tree.proto), so that it can be
+// embedded in existing protobuf and gRPC messages. Keys, values and metadata are encoded with the codecs of c.
+//
+// As with Tree.MarshalJSON, the message holds its version (see FormatVersion) and the nodes in pre-order, each with flags recording whether it has
+// a left and/or right child. This preserves the exact shape of the tree, so that it can be restored by
+// Tree.UnmarshalProto without rebuilding it by insertion. The LessFunc and any options are not encoded.
+//
+// Returns:
+//   - (data, nil) if the tree was encoded.
+//   - (nil, error) if a codec of c fails.
+func (t *Tree[K, V, M]) MarshalProto(c ProtoCodec[K, V, M]) ([]byte, error) {
+	data := binary.AppendUvarint(nil, protoTreeVersion<<3|protoVarint)
+	data = binary.AppendUvarint(data, FormatVersion)
+	var node []byte
+	var err error
+	t.traversePreOrder(func(n *Node[K, V, M]) {
+		if err != nil {
+			return
+		}
+		node, err = appendProtoNode(node[:0], c, n.key, n.value, n.metadata, !t.IsNil(n.left), !t.IsNil(n.right))
+		data = appendProtoBytes(data, protoTreeNodes, node)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// appendProtoNode appends the encoding of a Node message to data.
+func appendProtoNode[K, V, M any](data []byte, c ProtoCodec[K, V, M], key K, value V, metadata M, left, right bool) ([]byte, error) {
+	b, err := c.Key.Encode(key)
+	if err != nil {
+		return nil, fmt.Errorf("marshal key: %w", err)
+	}
+	data = appendProtoBytes(data, protoNodeKey, b)
+	if b, err = c.Value.Encode(value); err != nil {
+		return nil, fmt.Errorf("marshal value: %w", err)
+	}
+	data = appendProtoBytes(data, protoNodeValue, b)
+	if c.Metadata != nil {
+		if b, err = c.Metadata.Encode(metadata); err != nil {
+			return nil, fmt.Errorf("marshal metadata: %w", err)
+		}
+		data = appendProtoBytes(data, protoNodeMetadata, b)
+	}
+	if left {
+		data = appendProtoBool(data, protoNodeLeft)
+	}
+	if right {
+		data = appendProtoBool(data, protoNodeRight)
+	}
+	return data, nil
+}
+
+// appendProtoBytes appends a length-delimited field to data, omitting it if b is empty, as proto3 does.
+func appendProtoBytes(data []byte, field int, b []byte) []byte {
+	if len(b) == 0 {
+		return data
+	}
+	data = binary.AppendUvarint(data, uint64(field<<3|protoLen))
+	data = binary.AppendUvarint(data, uint64(len(b)))
+	return append(data, b...)
+}
+
+// appendProtoBool appends a bool field set to true to data.
+func appendProtoBool(data []byte, field int) []byte {
+	data = binary.AppendUvarint(data, uint64(field<<3|protoVarint))
+	return append(data, 1)
+}
+
+// UnmarshalProto restores a tree encoded by Tree.MarshalProto, decoding keys, values and metadata
+// with the codecs of c, which must match those used to encode it. Fields unknown to tree.proto
+// are skipped, as protobuf decoders do.
+//
+// The tree must have been created with New, so that it has a LessFunc. Its existing contents
+// are replaced, and any handles to its previous nodes become stale (see Node.BelongsTo).
+// Data written with an older FormatVersion is migrated first (see RegisterMigration).
+//
+// The restored tree is validated with Tree.IsTreeValid. If the data cannot be decoded
+// or describes an invalid tree, an error is returned and the tree is left unchanged.
+func (t *Tree[K, V, M]) UnmarshalProto(data []byte, c ProtoCodec[K, V, M]) error {
+	if t.less == nil || t.nil == nil {
+		return fmt.Errorf("cannot unmarshal into a tree not created with New")
+	}
+
+	version, nodes, err := parseProtoTree(data)
+	if err != nil {
+		return err
+	}
+	if version != FormatVersion {
+		if data, err = migrate(FormatProto, version, FormatVersion, data); err != nil {
+			return err
+		}
+		if _, nodes, err = parseProtoTree(data); err != nil {
+			return err
+		}
+	}
+
+	b := t.newBuilder(len(nodes))
+	for _, node := range nodes {
+		if err := unmarshalProtoNode(b, node, c); err != nil {
+			return err
+		}
+	}
+	return b.commit()
+}
+
+// parseProtoTree decodes the fields of a Tree message.
+//
+// Returns:
+//   - The version of the message, which is 1 if not recorded, and the encoded Node messages.
+//   - An error if data is not a valid protobuf message.
+func parseProtoTree(data []byte) (version int, nodes [][]byte, err error) {
+	version = 1
+	for len(data) > 0 {
+		field, wireType, payload, rest, err := consumeProtoField(data)
+		if err != nil {
+			return 0, nil, err
+		}
+		data = rest
+		switch {
+		case field == protoTreeNodes && wireType == protoLen:
+			nodes = append(nodes, payload)
+		case field == protoTreeVersion && wireType == protoVarint:
+			v, _ := binary.Uvarint(payload)
+			version = int(v)
+		}
+	}
+	return version, nodes, nil
+}
+
+// unmarshalProtoNode decodes a Node message, and adds the node to b.
+func unmarshalProtoNode[K, V, M any](b *builder[K, V, M], data []byte, c ProtoCodec[K, V, M]) error {
+	var (
+		keyData, valueData, metadataData []byte
+		left, right                      bool
+	)
+	for len(data) > 0 {
+		field, wireType, payload, rest, err := consumeProtoField(data)
+		if err != nil {
+			return err
+		}
+		data = rest
+		switch {
+		case field == protoNodeKey && wireType == protoLen:
+			keyData = payload
+		case field == protoNodeValue && wireType == protoLen:
+			valueData = payload
+		case field == protoNodeMetadata && wireType == protoLen:
+			metadataData = payload
+		case field == protoNodeLeft && wireType == protoVarint:
+			v, _ := binary.Uvarint(payload)
+			left = v != 0
+		case field == protoNodeRight && wireType == protoVarint:
+			v, _ := binary.Uvarint(payload)
+			right = v != 0
+		}
+	}
+
+	key, err := c.Key.Decode(keyData)
+	if err != nil {
+		return fmt.Errorf("unmarshal key: %w", err)
+	}
+	value, err := c.Value.Decode(valueData)
+	if err != nil {
+		return fmt.Errorf("unmarshal value: %w", err)
+	}
+	var metadata M
+	if c.Metadata != nil {
+		if metadata, err = c.Metadata.Decode(metadataData); err != nil {
+			return fmt.Errorf("unmarshal metadata: %w", err)
+		}
+	}
+	return b.add(key, value, metadata, left, right)
+}
+
+// consumeProtoField decodes the field at the start of data.
+//
+// Returns:
+//   - The field number, its wire type, its payload (the bytes of a varint or fixed-size field, or the
+//     contents of a length-delimited field), and the remaining data.
+//   - An error if data is truncated or holds an unsupported wire type.
+func consumeProtoField(data []byte) (field int, wireType int, payload, rest []byte, err error) {
+	tag, n := binary.Uvarint(data)
+	if n <= 0 {
+		return 0, 0, nil, nil, errProtoTruncated
+	}
+	data = data[n:]
+	field, wireType = int(tag>>3), int(tag&7)
+
+	size := 0
+	switch wireType {
+	case protoVarint:
+		if _, size = binary.Uvarint(data); size <= 0 {
+			return 0, 0, nil, nil, errProtoTruncated
+		}
+	case protoI64:
+		size = 8
+	case protoI32:
+		size = 4
+	case protoLen:
+		length, n := binary.Uvarint(data)
+		if n <= 0 || length > uint64(len(data)-n) {
+			return 0, 0, nil, nil, errProtoTruncated
+		}
+		data, size = data[n:], int(length)
+	default:
+		return 0, 0, nil, nil, fmt.Errorf("unsupported protobuf wire type %d", wireType)
+	}
+	if size > len(data) {
+		return 0, 0, nil, nil, errProtoTruncated
+	}
+	return field, wireType, data[:size], data[size:], nil
+}
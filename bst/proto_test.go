@@ -0,0 +1,114 @@
+package bst
+
+import (
+	"errors"
+	"github.com/mikenye/gotrees/codec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"testing"
+)
+
+// decimalCodec encodes integers as decimal strings.
+var decimalCodec = codec.Func(func(v int) ([]byte, error) {
+	return []byte(strconv.Itoa(v)), nil
+}, func(data []byte) (int, error) {
+	return strconv.Atoi(string(data))
+})
+
+// stringCodec encodes keys and metadata as decimal strings, and values as is.
+var stringCodec = ProtoCodec[int, string, int]{
+	Key:      decimalCodec,
+	Value:    codec.String(),
+	Metadata: decimalCodec,
+}
+
+func TestTree_MarshalProto(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	c := stringCodec
+	c.Metadata = nil
+
+	tree := New[int, string, int](less)
+	tree.Insert(2, "b")
+	tree.Insert(1, "a")
+	data, err := tree.MarshalProto(c)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{
+		0x10, 0x01, // version
+		0x0a, 0x08, // nodes: 8 bytes
+		0x0a, 0x01, '2', // key
+		0x12, 0x01, 'b', // value
+		0x20, 0x01, // left
+		0x0a, 0x06, // nodes: 6 bytes
+		0x0a, 0x01, '1', // key
+		0x12, 0x01, 'a', // value
+	}, data)
+
+	data, err = New[int, string, int](less).MarshalProto(c)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x10, 0x01}, data, "expected an empty tree to encode as its version only")
+
+	failure := errors.New("failure")
+	c.Value = codec.Func(func(value string) ([]byte, error) { return nil, failure }, codec.String().Decode)
+	_, err = tree.MarshalProto(c)
+	assert.ErrorIs(t, err, failure)
+}
+
+func TestTree_UnmarshalProto(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+
+	// build a degenerate tree, plus some branching, to check the shape is preserved
+	tree := New[int, string, int](less)
+	for _, key := range []int{50, 20, 80, 10, 30, 70, 90, 25, 35, 75, 91, 92, 93, 94, 95} {
+		n, _ := tree.Insert(key, "value")
+		tree.SetMetadata(n, -key)
+	}
+	data, err := tree.MarshalProto(stringCodec)
+	require.NoError(t, err)
+
+	restored := New[int, string, int](less)
+	old, _ := restored.Insert(1, "old")
+	require.NoError(t, restored.UnmarshalProto(data, stringCodec))
+	require.NoError(t, restored.IsTreeValid(), "expected valid tree")
+	assert.True(t, tree.EqualStructure(restored, func(a, b string) bool { return a == b }), "expected identical shape")
+	assert.False(t, restored.Contains(old), "expected previous nodes to become stale")
+	restored.TraverseInOrder(restored.Root(), func(n *Node[int, string, int]) bool {
+		assert.Equal(t, -restored.Key(n), restored.Metadata(n), "expected metadata to be restored")
+		return true
+	})
+
+	// fields unknown to tree.proto are skipped
+	withUnknown := append([]byte{0x10, 0x96, 0x01, 0x1d, 1, 2, 3, 4, 0x22, 0x02, 'x', 'y'}, data...)
+	require.NoError(t, restored.UnmarshalProto(withUnknown, stringCodec))
+	assert.True(t, tree.EqualStructure(restored, nil), "expected unknown fields to be skipped")
+
+	// round trip of an empty tree
+	require.NoError(t, restored.UnmarshalProto(nil, stringCodec))
+	assert.True(t, restored.IsNil(restored.Root()), "expected empty tree")
+}
+
+func TestTree_UnmarshalProto_errors(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	tree := New[int, string, int](less)
+	tree.Insert(1, "one")
+
+	tests := map[string][]byte{
+		"truncated tag":     {0x80},
+		"truncated length":  {0x0a, 0x05, 0x0a},
+		"truncated varint":  {0x20, 0x80},
+		"unsupported wire":  {0x0b},
+		"invalid key":       {0x0a, 0x03, 0x0a, 0x01, 'x'},
+		"out of order keys": {0x0a, 0x05, 0x0a, 0x01, '2', 0x20, 0x01, 0x0a, 0x03, 0x0a, 0x01, '3'},
+		"missing child":     {0x0a, 0x05, 0x0a, 0x01, '2', 0x20, 0x01},
+		"trailing node":     {0x0a, 0x03, 0x0a, 0x01, '2', 0x0a, 0x03, 0x0a, 0x01, '3'},
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, tree.UnmarshalProto(data, stringCodec))
+			require.Equal(t, 1, tree.Size(), "expected tree to be left unchanged")
+		})
+	}
+
+	var zero Tree[int, string, int]
+	assert.Error(t, zero.UnmarshalProto(nil, stringCodec), "expected error for a tree not created with New")
+}
@@ -0,0 +1,76 @@
+package bst
+
+// AscendAt calls f for each node in ascending key order, starting from the node with zero-based rank i
+// (see Tree.Select), until f returns false or every following node has been visited.
+//
+// Finding the first node takes O(h) time, using the subtree sizes maintained by the tree, and each
+// subsequent node O(1) amortized time, so paginating over a sorted index costs O(h + m) time per page
+// of m nodes, rather than O(i) time to walk to the page. Nothing is visited if i is out of range.
+// f must not modify the tree.
+//
+// Example Usage:
+//
+//	// visit results 1000 to 1049
+//	count := 0
+//	tree.AscendAt(1000, func(n *bst.Node[int, string, struct{}]) bool {
+//		fmt.Println(tree.Key(n))
+//		count++
+//		return count < 50
+//	})
+func (t *Tree[K, V, M]) AscendAt(i int, f TraversalFunc[K, V, M]) {
+	n, found := t.Select(i)
+	for found && f(n) {
+		n = t.Successor(n)
+		found = !t.IsNil(n)
+	}
+}
+
+// DeleteAt removes the node with zero-based rank i (see Tree.Select) from the tree, and returns its
+// key and value, in O(h) time. Together with Tree.Select, this allows evicting nodes by position,
+// e.g. the oldest of a sliding window, or the median.
+//
+// Nodes are deleted with Tree.Delete. Trees extending bst.Tree must delete nodes with their own
+// Delete method, using DeleteAtFunc (as rbtree.Tree.DeleteAt does).
+//
+// Returns:
+//   - (key, value, true) if 0 ≤ i < Tree.Size.
+//   - (zero key, zero value, false) if i is out of range.
+func (t *Tree[K, V, M]) DeleteAt(i int) (K, V, bool) {
+	return DeleteAtFunc(t, i, t.deleteNode)
+}
+
+// DeleteAtFunc implements Tree.DeleteAt for trees extending bst.Tree, deleting the node
+// with del (such as rbtree.Tree.Delete) rather than with Tree.Delete.
+//
+// Returns:
+//   - (key, value, true) if 0 ≤ i < Tree.Size.
+//   - (zero key, zero value, false) if i is out of range.
+func DeleteAtFunc[K, V, M any](t *Tree[K, V, M], i int, del func(n *Node[K, V, M]) bool) (K, V, bool) {
+	n, _ := t.Select(i)
+	return t.pop(n, del)
+}
+
+// SliceByRank returns the nodes with zero-based ranks in the half-open interval [i, j), in ascending
+// key order, as with Go slices. The bounds are clamped to [0, Tree.Size).
+//
+// This runs in O(h + m) time for m nodes (see Tree.AscendAt).
+//
+// Example Usage:
+//
+//	// results 1000 to 1049
+//	page := tree.SliceByRank(1000, 1050)
+//
+// Returns:
+//   - The nodes with ranks in [i, j), or nil if there are none.
+func (t *Tree[K, V, M]) SliceByRank(i, j int) []*Node[K, V, M] {
+	i, j = max(i, 0), min(j, t.Size())
+	if i >= j {
+		return nil
+	}
+	nodes := make([]*Node[K, V, M], 0, j-i)
+	t.AscendAt(i, func(n *Node[K, V, M]) bool {
+		nodes = append(nodes, n)
+		return len(nodes) < j-i
+	})
+	return nodes
+}
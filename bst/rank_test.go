@@ -0,0 +1,81 @@
+package bst
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestTree_AscendAt(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	tree.AscendAt(0, func(n *Node[int, int, struct{}]) bool {
+		t.Error("expected nothing to be visited in an empty tree")
+		return true
+	})
+	for _, key := range rand.New(rand.NewSource(1)).Perm(100) {
+		tree.Insert(key*10, key)
+	}
+
+	var keys []int
+	tree.AscendAt(95, func(n *Node[int, int, struct{}]) bool {
+		keys = append(keys, tree.Key(n))
+		return true
+	})
+	assert.Equal(t, []int{950, 960, 970, 980, 990}, keys, "expected every node from rank 95")
+
+	keys = nil
+	tree.AscendAt(10, func(n *Node[int, int, struct{}]) bool {
+		keys = append(keys, tree.Key(n))
+		return len(keys) < 3
+	})
+	assert.Equal(t, []int{100, 110, 120}, keys, "expected iteration to stop when f returns false")
+
+	for _, i := range []int{-1, 100} {
+		tree.AscendAt(i, func(n *Node[int, int, struct{}]) bool {
+			t.Errorf("expected nothing to be visited from out of range rank %d", i)
+			return true
+		})
+	}
+}
+
+func TestTree_DeleteAt(t *testing.T) {
+	tree := New[int, string, struct{}](func(a, b int) bool { return a < b })
+	for i, s := range []string{"a", "b", "c", "d", "e"} {
+		tree.Insert(i, s)
+	}
+
+	key, value, ok := tree.DeleteAt(2)
+	assert.True(t, ok)
+	assert.Equal(t, 2, key)
+	assert.Equal(t, "c", value)
+	key, _, _ = tree.DeleteAt(2)
+	assert.Equal(t, 3, key, "expected ranks to shift after a deletion")
+	for _, i := range []int{-1, 3} {
+		_, _, ok = tree.DeleteAt(i)
+		assert.False(t, ok, "expected out of range rank %d to delete nothing", i)
+	}
+	assert.Equal(t, 3, tree.Size())
+	assert.NoError(t, tree.IsTreeValid())
+}
+
+func TestTree_SliceByRank(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b }, WithDuplicateKeys())
+	for i := 0; i < 50; i++ {
+		tree.Insert(i/2, i)
+	}
+
+	keys := func(nodes []*Node[int, int, struct{}]) []int {
+		var keys []int
+		for _, n := range nodes {
+			keys = append(keys, tree.Key(n))
+		}
+		return keys
+	}
+	assert.Equal(t, []int{5, 5, 6, 6, 7}, keys(tree.SliceByRank(10, 15)))
+	assert.Equal(t, []int{0, 0, 1}, keys(tree.SliceByRank(-5, 3)), "expected the lower bound to be clamped")
+	assert.Equal(t, []int{24, 24}, keys(tree.SliceByRank(48, 100)), "expected the upper bound to be clamped")
+	assert.Len(t, tree.SliceByRank(0, 100), 50)
+	assert.Nil(t, tree.SliceByRank(10, 10), "expected an empty range to return nil")
+	assert.Nil(t, tree.SliceByRank(20, 10), "expected an inverted range to return nil")
+	assert.Nil(t, tree.SliceByRank(50, 60), "expected a range past the end to return nil")
+}
@@ -0,0 +1,93 @@
+package bst
+
+import (
+	"math"
+	"math/bits"
+)
+
+// DegradationFunc defines a function type called when an insertion leaves the tree degraded.
+// See WithDegradationFunc.
+//
+// Parameters:
+//   - depth: The depth of the node just inserted, a lower bound on the height of the tree.
+//   - size: The number of nodes in the tree.
+type DegradationFunc func(depth, size int)
+
+// Rebalance restructures the tree into a balanced shape using the Day–Stout–Warren algorithm,
+// restoring O(log n) operations on a tree that has degraded (e.g., after keys were inserted in sorted order).
+//
+// The tree is first flattened into a "vine" (a right-leaning linked list) by right rotations,
+// then folded into a balanced tree by successive passes of left rotations. Once rebalanced,
+// every level of the tree is full, except possibly the deepest, so its height is ⌊log2(n)⌋.
+//
+// Rebalancing is performed in O(n) time and O(1) extra space. Nodes are relinked by Tree.RotateLeft
+// and Tree.RotateRight, so existing node handles remain valid, and subtree sizes and augmented data
+// are kept up to date.
+//
+// ⚠️ Warning: As with Tree.RebuildSubtree, rebalancing discards any balancing information stored in
+// node metadata (e.g., Red-Black colors), so should not be used on trees that rely on it.
+//
+// Example Usage:
+//
+//	// sorted insertion degrades the tree into a linked list
+//	for i := 0; i < 1000; i++ {
+//		tree.Insert(i, "value")
+//	}
+//	tree.Rebalance()
+func (t *Tree[K, V, M]) Rebalance() {
+	// flatten the tree into a vine, rotating right until no node on the right spine has a left child
+	for n := t.root; !t.IsNil(n); {
+		if t.IsNil(n.left) {
+			n = n.right
+		} else {
+			l := n.left
+			t.RotateRight(n)
+			n = l
+		}
+	}
+
+	// fold the vine: the first pass leaves a perfect vine of 2^k-1 nodes, each later pass halves it
+	size := t.Size()
+	perfect := 1<<(bits.Len(uint(size+1))-1) - 1
+	t.compress(size - perfect)
+	for perfect > 1 {
+		perfect /= 2
+		t.compress(perfect)
+	}
+}
+
+// compress performs count left rotations down the right spine of the tree, on every other node
+// starting from the root, moving each rotated node's right child up in its place.
+func (t *Tree[K, V, M]) compress(count int) {
+	n := t.root
+	for i := 0; i < count; i++ {
+		t.RotateLeft(n)
+		n = n.parent.right
+	}
+}
+
+// AutoRebalance returns the threshold above which an insertion rebalances the tree (see WithAutoRebalance),
+// or 0 if automatic rebalancing is disabled.
+//
+// Trees extending bst.Tree which balance themselves should reject a tree with automatic rebalancing
+// enabled, as rebalancing would discard their balancing information.
+func (t *Tree[K, V, M]) AutoRebalance() float64 {
+	return t.rebalanceThreshold
+}
+
+// checkDegraded calls the registered DegradationFunc and rebalances the tree, as configured
+// with WithDegradationFunc and WithAutoRebalance, if the depth of the newly inserted node n
+// exceeds the configured ratio to log2 of the tree size.
+func (t *Tree[K, V, M]) checkDegraded(n *Node[K, V, M]) {
+	if t.degradedFunc == nil && t.rebalanceThreshold == 0 {
+		return
+	}
+	depth, size := t.Depth(n), t.Size()
+	limit := math.Log2(float64(size))
+	if t.degradedFunc != nil && float64(depth) > t.degradedThreshold*limit {
+		t.degradedFunc(depth, size)
+	}
+	if t.rebalanceThreshold != 0 && float64(depth) > t.rebalanceThreshold*limit {
+		t.Rebalance()
+	}
+}
@@ -0,0 +1,120 @@
+package bst
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"math/bits"
+	"math/rand"
+	"testing"
+)
+
+// maxDepth returns the depth of the deepest node of tree, or -1 if the tree is empty.
+func maxDepth[K, V, M any](tree *Tree[K, V, M]) int {
+	depth := -1
+	for n := tree.Min(tree.Root()); !tree.IsNil(n); n = tree.Successor(n) {
+		depth = max(depth, tree.Depth(n))
+	}
+	return depth
+}
+
+func TestTree_Rebalance(t *testing.T) {
+	for _, size := range []int{0, 1, 2, 3, 7, 8, 100, 1000, 1023, 1024} {
+		tree := New[int, int, int](func(a, b int) bool { return a < b })
+		tree.SetAugmentFunc(func(n *Node[int, int, int]) {
+			n.metadata = n.value + n.left.metadata + n.right.metadata
+		})
+		var nodes []*Node[int, int, int]
+		for i := 0; i < size; i++ {
+			n, _ := tree.Insert(i, i)
+			nodes = append(nodes, n)
+		}
+
+		tree.Rebalance()
+		require.NoError(t, tree.IsTreeValid(), "expected valid tree with %d nodes", size)
+		assert.Equal(t, size, tree.Size())
+		assert.Equal(t, bits.Len(uint(size))-1, maxDepth(tree), "unexpected height with %d nodes", size)
+		assert.Equal(t, size*(size-1)/2, tree.Metadata(tree.Root()), "expected augmented data to be maintained")
+		for i, n := range nodes {
+			require.True(t, n.BelongsTo(tree), "expected node handle to remain valid")
+			m, found := tree.Select(i)
+			require.True(t, found)
+			assert.Same(t, n, m)
+		}
+	}
+}
+
+func TestTree_Rebalance_random(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tree := New[int, struct{}, struct{}](func(a, b int) bool { return a < b }, WithDuplicateKeys())
+	for i := 0; i < 500; i++ {
+		tree.Insert(rng.Intn(100), struct{}{})
+	}
+	before := keysOf(tree)
+
+	tree.Rebalance()
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	assert.Equal(t, before, keysOf(tree), "expected keys to remain in the same order")
+	assert.Equal(t, 8, maxDepth(tree))
+
+	// rebalancing a balanced tree leaves it balanced
+	tree.Rebalance()
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	assert.Equal(t, 8, maxDepth(tree))
+}
+
+func TestWithDegradationFunc(t *testing.T) {
+	type report struct{ depth, size int }
+	var reports []report
+	tree := New[int, struct{}, struct{}](func(a, b int) bool { return a < b }, WithDegradationFunc(4, func(depth, size int) {
+		reports = append(reports, report{depth, size})
+	}))
+
+	// random insertion order does not degrade the tree
+	rng := rand.New(rand.NewSource(1))
+	for _, key := range rng.Perm(1000) {
+		tree.Insert(key, struct{}{})
+	}
+	assert.Empty(t, reports)
+
+	// sorted insertion does, and the tree is not rebalanced
+	for i := 1000; i < 1100; i++ {
+		tree.Insert(i, struct{}{})
+	}
+	require.NotEmpty(t, reports)
+	for _, r := range reports {
+		assert.Greater(t, float64(r.depth), 4*math.Log2(float64(r.size)))
+	}
+	assert.Equal(t, reports[len(reports)-1].depth, maxDepth(tree))
+
+	assert.Panics(t, func() { WithDegradationFunc(1, func(depth, size int) {}) })
+}
+
+func TestWithAutoRebalance(t *testing.T) {
+	var reported int
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b },
+		WithAutoRebalance(3),
+		WithDegradationFunc(3, func(depth, size int) { reported++ }),
+	)
+	var nodes []*Node[int, int, struct{}]
+	for i := 0; i < 10000; i++ {
+		n, _ := tree.Insert(i, i)
+		nodes = append(nodes, n)
+		if i%500 == 0 {
+			require.LessOrEqual(t, float64(maxDepth(tree)), 3*math.Log2(float64(tree.Size())), "expected tree to be rebalanced")
+		}
+	}
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	assert.Greater(t, reported, 0)
+	assert.LessOrEqual(t, maxDepth(tree), int(3*math.Log2(10000)))
+	for i, n := range nodes {
+		require.True(t, n.BelongsTo(tree), "expected node handle to remain valid")
+		require.Equal(t, i, tree.Value(n))
+	}
+
+	assert.Equal(t, 3.0, tree.AutoRebalance())
+	assert.Zero(t, New[int, int, struct{}](func(a, b int) bool { return a < b }).AutoRebalance())
+
+	assert.Panics(t, func() { WithAutoRebalance(0.5) })
+	assert.Panics(t, func() { WithAutoRebalance(math.NaN()) })
+}
@@ -0,0 +1,164 @@
+package bst
+
+import (
+	"strings"
+)
+
+// defaultIndent is the default width of each tree level drawn by Tree.Render, in characters.
+const defaultIndent = 5
+
+// minIndent is the minimum width of each tree level drawn by Tree.Render, in characters.
+const minIndent = 3
+
+// RenderOptions controls how Tree.Render draws the tree.
+//
+// The zero value draws the tree exactly as Tree.String does.
+type RenderOptions struct {
+	// ASCII draws connectors using only ASCII characters ("/--", "\--" and "|"), for terminals and
+	// log systems that cannot display box-drawing characters.
+	ASCII bool
+
+	// Indent is the width of each tree level, in characters. If zero, a width of 5 is used.
+	// Widths less than 3 are increased to 3.
+	Indent int
+
+	// MaxDepth, if greater than zero, omits nodes deeper than MaxDepth (the root has depth 0).
+	// Nodes at MaxDepth with omitted children are followed by an ellipsis.
+	MaxDepth int
+}
+
+// connectors holds the strings used by Tree.Render to draw the tree structure.
+type connectors struct {
+	left, right, vertical, space, ellipsis string
+}
+
+// connectors returns the connectors described by the options.
+//
+// For the default options, these are " ╭── ", " ╰── ", " │   " and "     ".
+func (o RenderOptions) connectors() connectors {
+	width := o.Indent
+	if width == 0 {
+		width = defaultIndent
+	}
+	width = max(width, minIndent)
+
+	corner := [2]string{"╭", "╰"}
+	horizontal, vertical, ellipsis := "─", "│", " …"
+	if o.ASCII {
+		corner = [2]string{"/", "\\"}
+		horizontal, vertical, ellipsis = "-", "|", " ..."
+	}
+
+	line := strings.Repeat(horizontal, width-3) + " "
+	return connectors{
+		left:     " " + corner[0] + line,
+		right:    " " + corner[1] + line,
+		vertical: " " + vertical + strings.Repeat(" ", width-2),
+		space:    strings.Repeat(" ", width),
+		ellipsis: ellipsis,
+	}
+}
+
+// Render returns a visual representation of the binary search tree (BST), drawn according to opts.
+//
+// The layout is the same as Tree.String: the tree is ordered in ascending order, with the minimum
+// node on the first line, and nodes are connected to their parents by connectors. opts allows
+// ASCII-only connectors, a different indent width, and limiting the depth of the drawing.
+//
+// The nodes are printed using the function registered with Tree.SetNodeFormatter,
+// or the Node.String method if no function is registered.
+//
+// If the tree is empty, the function returns "Empty Tree".
+//
+// Parameters:
+//   - opts: The options controlling the drawing.
+//
+// Returns:
+//   - A formatted string representing the BST structure.
+//
+// This function uses an in-order iterator to traverse the tree and builds
+// the output using a string builder. It tracks vertical lines dynamically
+// to create a structured visualization of the BST.
+func (t *Tree[K, V, M]) Render(opts RenderOptions) string {
+
+	// if tree is empty, return early
+	if t.root == t.nil {
+		return "Empty Tree"
+	}
+
+	// prepare connectors
+	c := opts.connectors()
+
+	// prepare string builder
+	builder := strings.Builder{}
+
+	// prepare map to hold which levels to draw vertical lines
+	verticalLineHeights := make(map[int]bool)
+
+	// ascend the tree. for each node:
+	t.TraverseInOrder(t.root, func(node *Node[K, V, M]) bool {
+		// get height of node
+		h := t.Depth(node)
+
+		// skip nodes beyond the maximum depth.
+		// the vertical lines they turn on/off are only used by deeper nodes, so can be ignored.
+		if opts.MaxDepth > 0 && h > opts.MaxDepth {
+			return true
+		}
+
+		// if we are at a height that needs a vertical line, draw it,
+		// otherwise draw a space
+		for j := 0; j < h-1; j++ {
+			if verticalLineHeights[j+1] {
+				builder.WriteString(c.vertical)
+			} else {
+				builder.WriteString(c.space)
+			}
+		}
+
+		// draw "connector" based on node orientation
+		if node.parent != t.nil && node.parent.left == node {
+			builder.WriteString(c.left)
+		} else if node.parent != t.nil && node.parent.right == node {
+			builder.WriteString(c.right)
+		}
+
+		// print node key
+		if t.formatter != nil {
+			builder.WriteString(t.formatter(node.key, node.value, node.metadata))
+		} else {
+			builder.WriteString(node.String())
+		}
+
+		// mark omitted children
+		if opts.MaxDepth > 0 && h == opts.MaxDepth && (node.left != t.nil || node.right != t.nil) {
+			builder.WriteString(c.ellipsis)
+		}
+		builder.WriteString("\n")
+
+		// turn on/off vertical lines
+
+		// if node parent is in the "right" direction ("down" in this representation),
+		// turn on vertical lines for this height.
+		if node.parent != t.nil && node.parent.left == node {
+			verticalLineHeights[h] = true
+		}
+		// if node parent is in "left" direction ("up" in this representation),
+		// turn off vertical lines for this height.
+		if node.parent != t.nil && node.parent.right == node {
+			verticalLineHeights[h] = false
+		}
+		// if node has right child ("down in this representation),
+		// turn on vertical lines for the next height (h+1).
+		if node.right != t.nil {
+			verticalLineHeights[h+1] = true
+		} else {
+			verticalLineHeights[h+1] = false
+		}
+
+		return true
+	})
+
+	// return the tree
+	return builder.String()
+}
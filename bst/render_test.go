@@ -0,0 +1,96 @@
+package bst
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTree_Render(t *testing.T) {
+	tree := New[int, struct{}, struct{}](func(a, b int) bool { return a < b })
+	for _, key := range []int{4, 2, 6, 1, 3, 5, 7, 8} {
+		tree.Insert(key, struct{}{})
+	}
+	tree.SetNodeFormatter(func(k int, _ struct{}, _ struct{}) string {
+		return string(rune('0' + k))
+	})
+
+	tests := map[string]struct {
+		opts     RenderOptions
+		expected string
+	}{
+		"default": {
+			opts: RenderOptions{},
+			expected: "" +
+				"      ╭── 1\n" +
+				" ╭── 2\n" +
+				" │    ╰── 3\n" +
+				"4\n" +
+				" │    ╭── 5\n" +
+				" ╰── 6\n" +
+				"      ╰── 7\n" +
+				"           ╰── 8\n",
+		},
+		"ascii": {
+			opts: RenderOptions{ASCII: true},
+			expected: "" +
+				"      /-- 1\n" +
+				" /-- 2\n" +
+				" |    \\-- 3\n" +
+				"4\n" +
+				" |    /-- 5\n" +
+				" \\-- 6\n" +
+				"      \\-- 7\n" +
+				"           \\-- 8\n",
+		},
+		"narrow indent": {
+			opts: RenderOptions{Indent: 3},
+			expected: "" +
+				"    ╭ 1\n" +
+				" ╭ 2\n" +
+				" │  ╰ 3\n" +
+				"4\n" +
+				" │  ╭ 5\n" +
+				" ╰ 6\n" +
+				"    ╰ 7\n" +
+				"       ╰ 8\n",
+		},
+		"indent below minimum": {
+			opts: RenderOptions{Indent: 1, ASCII: true},
+			expected: "" +
+				"    / 1\n" +
+				" / 2\n" +
+				" |  \\ 3\n" +
+				"4\n" +
+				" |  / 5\n" +
+				" \\ 6\n" +
+				"    \\ 7\n" +
+				"       \\ 8\n",
+		},
+		"wide indent": {
+			opts: RenderOptions{Indent: 7, MaxDepth: 1},
+			expected: "" +
+				" ╭──── 2 …\n" +
+				"4\n" +
+				" ╰──── 6 …\n",
+		},
+		"max depth": {
+			opts: RenderOptions{MaxDepth: 2, ASCII: true},
+			expected: "" +
+				"      /-- 1\n" +
+				" /-- 2\n" +
+				" |    \\-- 3\n" +
+				"4\n" +
+				" |    /-- 5\n" +
+				" \\-- 6\n" +
+				"      \\-- 7 ...\n",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tree.Render(tc.opts))
+		})
+	}
+
+	assert.Equal(t, tree.String(), tree.Render(RenderOptions{}), "expected String to use default options")
+	assert.Equal(t, "Empty Tree", New[int, int, int](func(a, b int) bool { return a < b }).Render(RenderOptions{ASCII: true}))
+}
@@ -0,0 +1,82 @@
+package bst
+
+import "slices"
+
+// FromSlice creates a tree holding the given items as values, keyed by keyFn, sorting the items and
+// bulk-building a perfectly balanced tree with Tree.LoadSorted, in O(n log n) time.
+//
+// If duplicate keys are not enabled (see WithDuplicateKeys), the last item with a given key wins,
+// as if the items were inserted in order. Otherwise, items with equal keys keep their order.
+//
+// Parameters:
+//   - less: A function that defines the ordering of keys.
+//   - items: The items to hold. items is not modified.
+//   - keyFn: A function returning the key of an item.
+//   - opts: Options, as for New.
+//
+// Returns:
+//   - A pointer to a newly created Tree[K, V, M] instance.
+//
+// Example Usage:
+//
+//	// index people by name, then back to a slice sorted by name
+//	tree := bst.FromSlice[string, Person, struct{}](func(a, b string) bool { return a < b }, people,
+//		func(p Person) string { return p.Name })
+//	sorted := bst.ToSlice(tree)
+func FromSlice[K, V, M any](less LessFunc[K], items []V, keyFn func(item V) K, opts ...Option) *Tree[K, V, M] {
+	t := New[K, V, M](less, opts...)
+	keys, values := SortItems(t.Less, t.Duplicates(), items, keyFn)
+	if err := t.LoadSorted(keys, values, 0); err != nil {
+		panic(err) // unreachable: SortItems returns sorted keys
+	}
+	return t
+}
+
+// SortItems sorts items by the keys keyFn returns, for Tree.LoadSorted, as FromSlice does. Trees
+// extending bst.Tree use it to implement their own FromSlice (as rbtree.FromSlice does).
+//
+// The sort is stable. If duplicates is false, only the last item with a given key is kept.
+//
+// Returns:
+//   - The keys in ascending order, and the items in the same order. items is not modified.
+func SortItems[K, V any](less LessFunc[K], duplicates bool, items []V, keyFn func(item V) K) ([]K, []V) {
+	type item struct {
+		key   K
+		value V
+	}
+	sorted := make([]item, len(items))
+	for i, v := range items {
+		sorted[i] = item{key: keyFn(v), value: v}
+	}
+	slices.SortStableFunc(sorted, func(a, b item) int {
+		switch {
+		case less(a.key, b.key):
+			return -1
+		case less(b.key, a.key):
+			return 1
+		}
+		return 0
+	})
+
+	keys := make([]K, 0, len(sorted))
+	values := make([]V, 0, len(sorted))
+	for i, it := range sorted {
+		if !duplicates && i+1 < len(sorted) && !less(it.key, sorted[i+1].key) {
+			continue // superseded by a later item with the same key
+		}
+		keys = append(keys, it.key)
+		values = append(values, it.value)
+	}
+	return keys, values
+}
+
+// ToSlice returns the values of the tree in ascending key order, the inverse of FromSlice.
+//
+// Returns:
+//   - A slice of t.Size() values, or nil if the tree is empty.
+func ToSlice[K, V, M any](t *Tree[K, V, M]) []V {
+	if t.Size() == 0 {
+		return nil
+	}
+	return slices.AppendSeq(make([]V, 0, t.Size()), t.Values())
+}
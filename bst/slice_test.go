@@ -0,0 +1,47 @@
+package bst
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+type person struct {
+	name string
+	age  int
+}
+
+func TestFromSlice(t *testing.T) {
+	byName := func(p person) string { return p.name }
+	less := func(a, b string) bool { return a < b }
+
+	tree := FromSlice[string, person, struct{}](less, nil, byName)
+	assert.Equal(t, 0, tree.Size())
+	assert.Nil(t, ToSlice(tree), "expected nil for an empty tree")
+
+	people := []person{{"carol", 35}, {"alice", 30}, {"bob", 25}, {"alice", 31}}
+	original := slices.Clone(people)
+	tree = FromSlice[string, person, struct{}](less, people, byName)
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, original, people, "expected the items not to be modified")
+	assert.Equal(t, []person{{"alice", 31}, {"bob", 25}, {"carol", 35}}, ToSlice(tree),
+		"expected the last item with a key to win")
+
+	tree = FromSlice[string, person, struct{}](less, people, byName, WithDuplicateKeys())
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, []person{{"alice", 30}, {"alice", 31}, {"bob", 25}, {"carol", 35}}, ToSlice(tree),
+		"expected items with equal keys to keep their order")
+
+	tree = FromSlice[string, person, struct{}](less, people, byName, WithReverseOrder())
+	assert.Equal(t, []person{{"carol", 35}, {"bob", 25}, {"alice", 31}}, ToSlice(tree))
+}
+
+func TestFromSlice_roundTrip(t *testing.T) {
+	items := rand.New(rand.NewSource(1)).Perm(1000)
+	tree := FromSlice[int, int, struct{}](func(a, b int) bool { return a < b }, items, func(v int) int { return v })
+	require.NoError(t, tree.IsTreeValid())
+	assert.LessOrEqual(t, tree.Height(), 9, "expected a perfectly balanced tree")
+	assert.Equal(t, slices.Sorted(slices.Values(items)), ToSlice(tree))
+}
@@ -0,0 +1,386 @@
+package bst
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Snapshot is an immutable, point-in-time view of the keys and values of a Tree.
+//
+// A Snapshot is a version of a persistent tree of entries maintained alongside the tree it was taken
+// from (see Tree.Snapshot), which is never modified once taken. It can thus be read and iterated by
+// any number of goroutines, without locking, while the tree continues to receive insertions and
+// deletions. Lookups by key and by rank take O(log n) time.
+//
+// Snapshots are created with Tree.Snapshot.
+type Snapshot[K, V any] struct {
+	root *snapNode[K, V]
+	less LessFunc[K]
+}
+
+// snapNode is a node of the persistent tree from which snapshots are taken.
+//
+// Unlike Node, it has no parent pointer, so that a write can copy the nodes on the path from the root
+// to the change, and share every other node with the snapshots already taken.
+type snapNode[K, V any] struct {
+	key         K
+	value       V
+	id          any // node of the tree holding the entry, telling equal keys apart
+	left, right *snapNode[K, V]
+	size        int    // number of nodes in the subtree rooted at this node
+	epoch       uint64 // epoch the node was created in: nodes of earlier epochs are shared, and never modified
+}
+
+// snapshots maintains the persistent tree of the entries of a Tree, from which snapshots are taken.
+//
+// Every snapshot ends an epoch: the nodes created before it belong to the snapshot, so writes copy
+// them, whereas the nodes created since the last snapshot are modified in place.
+type snapshots[K, V any] struct {
+	mu     sync.Mutex      // Serializes concurrent calls to Tree.Snapshot, which only readers make.
+	root   *snapNode[K, V] // Root of the persistent tree, if active.
+	epoch  uint64          // Current epoch, incremented by every snapshot.
+	active bool            // True while the persistent tree mirrors the tree, once a snapshot has been taken.
+}
+
+// Snapshot returns an immutable view of the keys and values in the tree, as they are now.
+//
+// The tree shares the entries of the snapshot rather than copying them: once a snapshot has been
+// taken, the tree maintains a persistent copy of its entries, without parent pointers, alongside its
+// nodes. Each insertion, deletion or update then copies the O(log n) entries on the path to the change,
+// leaving the entries held by snapshots unchanged, so taking a snapshot is an O(1) operation. The first
+// snapshot builds the persistent copy in O(n) time, as does the first snapshot following a change
+// replacing the entries at once (Tree.Clear, unmarshaling, loading sorted keys, attaching or detaching
+// a subtree). In multiset mode (see WithDuplicateKeys), an update or deletion also takes O(d log n)
+// time to find the entry among the d entries with an equal key.
+//
+// The tree must not be modified while the snapshot is being taken: where the tree is shared between
+// goroutines, hold the read (or write) lock only for the duration of this call, then release it and
+// iterate the snapshot at leisure.
+//
+// Node metadata is not included in the snapshot. Values are shared as-is, so if V is a pointer
+// or reference type, the pointed-to data is shared with the tree.
+func (t *Tree[K, V, M]) Snapshot() *Snapshot[K, V] {
+	s := &t.snaps
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.active {
+		nodes := make([]*Node[K, V, M], 0, t.Size())
+		for n := t.Min(t.root); !t.IsNil(n); n = t.Successor(n) {
+			nodes = append(nodes, n)
+		}
+		s.root = buildSnapNodes(nodes, s.epoch)
+		s.active = true
+	}
+	s.epoch++
+	return &Snapshot[K, V]{root: s.root, less: t.less}
+}
+
+// buildSnapNodes returns the root of a perfectly balanced persistent tree holding the entries of
+// nodes, in order, created in the given epoch.
+func buildSnapNodes[K, V, M any](nodes []*Node[K, V, M], epoch uint64) *snapNode[K, V] {
+	if len(nodes) == 0 {
+		return nil
+	}
+	mid := len(nodes) / 2
+	n := nodes[mid]
+	return &snapNode[K, V]{
+		key:   n.key,
+		value: n.value,
+		id:    n,
+		left:  buildSnapNodes(nodes[:mid], epoch),
+		right: buildSnapNodes(nodes[mid+1:], epoch),
+		size:  len(nodes),
+		epoch: epoch,
+	}
+}
+
+// snapInsert adds the entry of node n, newly linked into the tree, to the persistent tree, if active.
+func (t *Tree[K, V, M]) snapInsert(n *Node[K, V, M]) {
+	if t.snaps.active {
+		t.snaps.root = t.snaps.insert(t.snaps.root, n.key, n.value, n, t.less)
+	}
+}
+
+// snapDelete removes the entry of node n, which held key, from the persistent tree, if active.
+func (t *Tree[K, V, M]) snapDelete(key K, n *Node[K, V, M]) {
+	if !t.snaps.active {
+		return
+	}
+	if i, found := t.snaps.find(key, n, t.less); found {
+		t.snaps.root = t.snaps.deleteAt(t.snaps.root, i)
+	}
+}
+
+// snapUpdate copies the key and value of node n, which held key, to its entry in the persistent tree,
+// if active. The new key of n must keep n between its predecessor and successor.
+func (t *Tree[K, V, M]) snapUpdate(key K, n *Node[K, V, M]) {
+	if !t.snaps.active {
+		return
+	}
+	if i, found := t.snaps.find(key, n, t.less); found {
+		t.snaps.root = t.snaps.setAt(t.snaps.root, i, n.key, n.value)
+	}
+}
+
+// reset discards the persistent tree, once the entries of the tree have been replaced at once.
+// The next snapshot builds it again.
+func (s *snapshots[K, V]) reset() {
+	s.root, s.active = nil, false
+}
+
+// mutable returns x if it was created in the current epoch, or a copy of x created in the current epoch otherwise.
+func (s *snapshots[K, V]) mutable(x *snapNode[K, V]) *snapNode[K, V] {
+	if x.epoch == s.epoch {
+		return x
+	}
+	copied := *x
+	copied.epoch = s.epoch
+	return &copied
+}
+
+// insert adds an entry after any equal keys in the subtree rooted at x, and returns the new root of the subtree.
+func (s *snapshots[K, V]) insert(x *snapNode[K, V], key K, value V, id any, less LessFunc[K]) *snapNode[K, V] {
+	if x == nil {
+		return &snapNode[K, V]{key: key, value: value, id: id, size: 1, epoch: s.epoch}
+	}
+	x = s.mutable(x)
+	if less(key, x.key) {
+		x.left = s.insert(x.left, key, value, id, less)
+	} else {
+		x.right = s.insert(x.right, key, value, id, less)
+	}
+	x.size++
+	return s.balance(x)
+}
+
+// deleteAt removes the entry with zero-based rank i from the subtree rooted at x, and returns the new root of the subtree.
+func (s *snapshots[K, V]) deleteAt(x *snapNode[K, V], i int) *snapNode[K, V] {
+	x = s.mutable(x)
+	switch left := snapSize(x.left); {
+	case i < left:
+		x.left = s.deleteAt(x.left, i)
+	case i > left:
+		x.right = s.deleteAt(x.right, i-left-1)
+	case x.left == nil:
+		return x.right
+	case x.right == nil:
+		return x.left
+	default:
+		// replace the entry with its successor
+		successor := x.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+		x.key, x.value, x.id = successor.key, successor.value, successor.id
+		x.right = s.deleteAt(x.right, 0)
+	}
+	x.size--
+	return s.balance(x)
+}
+
+// setAt sets the key and value of the entry with zero-based rank i in the subtree rooted at x, and
+// returns the new root of the subtree.
+func (s *snapshots[K, V]) setAt(x *snapNode[K, V], i int, key K, value V) *snapNode[K, V] {
+	x = s.mutable(x)
+	switch left := snapSize(x.left); {
+	case i < left:
+		x.left = s.setAt(x.left, i, key, value)
+	case i > left:
+		x.right = s.setAt(x.right, i-left-1, key, value)
+	default:
+		x.key, x.value = key, value
+	}
+	return x
+}
+
+// find returns the zero-based rank of the entry of key held by node id.
+func (s *snapshots[K, V]) find(key K, id any, less LessFunc[K]) (int, bool) {
+	for i := snapRank(s.root, key, less); i < snapSize(s.root); i++ {
+		x := snapAt(s.root, i)
+		if less(key, x.key) {
+			break
+		}
+		if x.id == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Weight balance parameters: the weight (size + 1) of a subtree is at most snapDelta times that of
+// its sibling, and a single rotation restores the balance if the inner grandchild weighs less than
+// snapRatio times the outer grandchild (see Hirai and Yamamoto, "Balancing weight-balanced trees").
+const (
+	snapDelta = 3
+	snapRatio = 2
+)
+
+// balance restores the weight balance of x, which is mutable, following a single insertion or
+// deletion in one of its subtrees, and returns the new root of the subtree.
+func (s *snapshots[K, V]) balance(x *snapNode[K, V]) *snapNode[K, V] {
+	left, right := snapSize(x.left)+1, snapSize(x.right)+1
+	switch {
+	case right > snapDelta*left:
+		if snapSize(x.right.left)+1 >= snapRatio*(snapSize(x.right.right)+1) {
+			x.right = s.rotateRight(s.mutable(x.right))
+		}
+		return s.rotateLeft(x)
+	case left > snapDelta*right:
+		if snapSize(x.left.right)+1 >= snapRatio*(snapSize(x.left.left)+1) {
+			x.left = s.rotateLeft(s.mutable(x.left))
+		}
+		return s.rotateRight(x)
+	}
+	return x
+}
+
+// rotateLeft promotes the right child of x, which is mutable, and returns it.
+func (s *snapshots[K, V]) rotateLeft(x *snapNode[K, V]) *snapNode[K, V] {
+	y := s.mutable(x.right)
+	x.right, y.left = y.left, x
+	y.size = x.size
+	x.size = snapSize(x.left) + snapSize(x.right) + 1
+	return y
+}
+
+// rotateRight promotes the left child of x, which is mutable, and returns it.
+func (s *snapshots[K, V]) rotateRight(x *snapNode[K, V]) *snapNode[K, V] {
+	y := s.mutable(x.left)
+	x.left, y.right = y.right, x
+	y.size = x.size
+	x.size = snapSize(x.left) + snapSize(x.right) + 1
+	return y
+}
+
+// snapSize returns the number of entries in the subtree rooted at x.
+func snapSize[K, V any](x *snapNode[K, V]) int {
+	if x == nil {
+		return 0
+	}
+	return x.size
+}
+
+// snapAt returns the entry with zero-based rank i in the subtree rooted at x, which must hold it.
+func snapAt[K, V any](x *snapNode[K, V], i int) *snapNode[K, V] {
+	for {
+		switch left := snapSize(x.left); {
+		case i < left:
+			x = x.left
+		case i > left:
+			i -= left + 1
+			x = x.right
+		default:
+			return x
+		}
+	}
+}
+
+// snapRank returns the number of entries in the subtree rooted at x with a key less than key,
+// which is the rank of the first entry with a key not less than key.
+func snapRank[K, V any](x *snapNode[K, V], key K, less LessFunc[K]) int {
+	rank := 0
+	for x != nil {
+		if less(x.key, key) {
+			rank += snapSize(x.left) + 1
+			x = x.right
+		} else {
+			x = x.left
+		}
+	}
+	return rank
+}
+
+// Len returns the number of entries in the snapshot.
+func (s *Snapshot[K, V]) Len() int {
+	return snapSize(s.root)
+}
+
+// At returns the key and value of the entry with zero-based rank i (the i-th smallest key).
+//
+// At panics if i is out of range [0, Len()).
+func (s *Snapshot[K, V]) At(i int) (K, V) {
+	if i < 0 || i >= s.Len() {
+		panic(fmt.Sprintf("bst: snapshot index %d out of range [0, %d)", i, s.Len()))
+	}
+	x := snapAt(s.root, i)
+	return x.key, x.value
+}
+
+// Get returns the value associated with key.
+//
+// If the snapshot holds duplicate keys (see WithDuplicateKeys), the value of the first
+// (earliest inserted) entry with the key is returned.
+//
+// Returns:
+//   - (value, true) if the key exists in the snapshot.
+//   - (zero value, false) if the key is not found.
+func (s *Snapshot[K, V]) Get(key K) (V, bool) {
+	var ceiling *snapNode[K, V]
+	for x := s.root; x != nil; {
+		if s.less(x.key, key) {
+			x = x.right
+		} else {
+			ceiling, x = x, x.left
+		}
+	}
+	if ceiling != nil && !s.less(key, ceiling.key) {
+		return ceiling.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Ascend calls f for each entry in ascending key order, until f returns false.
+func (s *Snapshot[K, V]) Ascend(f func(key K, value V) bool) {
+	s.ascend(s.root, nil, f)
+}
+
+// AscendRange calls f for each entry with a key in the half-open interval [lo, hi),
+// in ascending key order, until f returns false.
+func (s *Snapshot[K, V]) AscendRange(lo, hi K, f func(key K, value V) bool) {
+	// stack the entries not less than lo on the path to lo, which are next in order
+	var stack []*snapNode[K, V]
+	for x := s.root; x != nil; {
+		if s.less(x.key, lo) {
+			x = x.right
+		} else {
+			stack = append(stack, x)
+			x = x.left
+		}
+	}
+	s.ascend(nil, stack, func(key K, value V) bool {
+		return s.less(key, hi) && f(key, value)
+	})
+}
+
+// ascend calls f for each entry of the stacked nodes and their right subtrees, followed by the
+// subtree rooted at x, in ascending key order, until f returns false, without recursion.
+func (s *Snapshot[K, V]) ascend(x *snapNode[K, V], stack []*snapNode[K, V], f func(key K, value V) bool) {
+	for x != nil || len(stack) > 0 {
+		for ; x != nil; x = x.left {
+			stack = append(stack, x)
+		}
+		x = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !f(x.key, x.value) {
+			return
+		}
+		x = x.right
+	}
+}
+
+// Descend calls f for each entry in descending key order, until f returns false.
+func (s *Snapshot[K, V]) Descend(f func(key K, value V) bool) {
+	var stack []*snapNode[K, V]
+	for x := s.root; x != nil || len(stack) > 0; {
+		for ; x != nil; x = x.right {
+			stack = append(stack, x)
+		}
+		x = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !f(x.key, x.value) {
+			return
+		}
+		x = x.left
+	}
+}
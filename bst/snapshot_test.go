@@ -0,0 +1,224 @@
+package bst
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestTree_Snapshot(t *testing.T) {
+	tree := New[int, string, struct{}](func(a, b int) bool { return a < b })
+	for _, key := range []int{50, 20, 80, 10, 30, 70, 90} {
+		tree.Insert(key, "v")
+	}
+
+	s := tree.Snapshot()
+
+	// modifying the tree does not affect the snapshot
+	tree.Insert(40, "v")
+	n, _ := tree.Search(20)
+	tree.Delete(n)
+	n, _ = tree.Search(50)
+	tree.SetValue(n, "changed")
+
+	require.Equal(t, 7, s.Len(), "unexpected snapshot length")
+	var keys []int
+	s.Ascend(func(k int, v string) bool {
+		keys = append(keys, k)
+		assert.Equal(t, "v", v, "expected value at time of snapshot")
+		return true
+	})
+	assert.Equal(t, []int{10, 20, 30, 50, 70, 80, 90}, keys)
+
+	keys = nil
+	s.Descend(func(k int, _ string) bool {
+		keys = append(keys, k)
+		return len(keys) < 3
+	})
+	assert.Equal(t, []int{90, 80, 70}, keys, "expected descending order, stopping early")
+
+	keys = nil
+	s.AscendRange(20, 70, func(k int, _ string) bool {
+		keys = append(keys, k)
+		return true
+	})
+	assert.Equal(t, []int{20, 30, 50}, keys, "expected keys in [20, 70)")
+
+	k, v := s.At(2)
+	assert.Equal(t, 30, k)
+	assert.Equal(t, "v", v)
+
+	v, found := s.Get(20)
+	assert.True(t, found, "expected deleted key to remain in snapshot")
+	assert.Equal(t, "v", v)
+	_, found = s.Get(40)
+	assert.False(t, found, "expected key inserted after snapshot to be absent")
+	_, found = s.Get(100)
+	assert.False(t, found)
+
+	assert.Equal(t, 0, New[int, int, int](func(a, b int) bool { return a < b }).Snapshot().Len())
+}
+
+func TestTree_Snapshot_duplicateKeys(t *testing.T) {
+	tree := New[int, string, struct{}](func(a, b int) bool { return a < b }, WithDuplicateKeys())
+	tree.Insert(1, "first")
+	tree.Insert(1, "second")
+	tree.Insert(0, "zero")
+
+	s := tree.Snapshot()
+	assert.Equal(t, 3, s.Len())
+	v, found := s.Get(1)
+	assert.True(t, found)
+	assert.Equal(t, "first", v, "expected earliest inserted value")
+}
+
+// TestTree_Snapshot_concurrent is intended to be run with the race detector.
+func TestTree_Snapshot_concurrent(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	for i := 0; i < 1000; i++ {
+		tree.Insert(i, i)
+	}
+
+	var mu sync.Mutex
+	mu.Lock()
+	s := tree.Snapshot()
+	mu.Unlock()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1000; i < 2000; i++ {
+			mu.Lock()
+			tree.Insert(i, i)
+			if n, found := tree.Search(i - 1000); found {
+				tree.Delete(n)
+			}
+			mu.Unlock()
+		}
+	}()
+
+	sum := 0
+	s.Ascend(func(k, v int) bool {
+		sum += v
+		return true
+	})
+	wg.Wait()
+	assert.Equal(t, 999*1000/2, sum, "expected snapshot to be unaffected by concurrent writes")
+}
+
+// snapshotEntries returns the keys and values of s, in ascending order, as "key=value" strings.
+func snapshotEntries[K, V any](s *Snapshot[K, V]) []string {
+	var entries []string
+	s.Ascend(func(k K, v V) bool {
+		entries = append(entries, fmt.Sprintf("%v=%v", k, v))
+		return true
+	})
+	return entries
+}
+
+// treeEntries returns the keys and values of tree, in ascending order, as "key=value" strings.
+func treeEntries[K, V, M any](tree *Tree[K, V, M]) []string {
+	var entries []string
+	for n := tree.Min(tree.Root()); !tree.IsNil(n); n = tree.Successor(n) {
+		entries = append(entries, fmt.Sprintf("%v=%v", n.Key(), n.Value()))
+	}
+	return entries
+}
+
+// checkSnapNodes checks the sizes and weight balance of the persistent tree rooted at x.
+func checkSnapNodes[K, V any](t *testing.T, x *snapNode[K, V]) {
+	if x == nil {
+		return
+	}
+	left, right := snapSize(x.left), snapSize(x.right)
+	require.Equal(t, left+right+1, x.size, "unexpected size at %v", x.key)
+	require.LessOrEqual(t, right+1, snapDelta*(left+1), "right subtree too heavy at %v", x.key)
+	require.LessOrEqual(t, left+1, snapDelta*(right+1), "left subtree too heavy at %v", x.key)
+	checkSnapNodes(t, x.left)
+	checkSnapNodes(t, x.right)
+}
+
+func TestTree_Snapshot_copyOnWrite(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"unique":     nil,
+		"duplicates": {WithDuplicateKeys()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tree := New[int, int, struct{}](func(a, b int) bool { return a < b }, opts...)
+			rng := rand.New(rand.NewSource(1))
+			type taken struct {
+				snapshot *Snapshot[int, int]
+				entries  []string
+			}
+			var snapshots []taken
+			for i := 0; i < 3000; i++ {
+				switch op := rng.Intn(10); {
+				case op < 5 || tree.Size() == 0:
+					tree.Insert(rng.Intn(200), i)
+				case op < 7:
+					n, _ := tree.Select(rng.Intn(tree.Size()))
+					tree.Delete(n)
+				case op < 8:
+					n, _ := tree.Select(rng.Intn(tree.Size()))
+					tree.SetValue(n, -i)
+				case op < 9:
+					n, _ := tree.Select(rng.Intn(tree.Size()))
+					tree.UpdateKey(n, rng.Intn(200))
+				default:
+					snapshots = append(snapshots, taken{tree.Snapshot(), treeEntries(tree)})
+				}
+			}
+			require.NotEmpty(t, snapshots)
+
+			// every snapshot holds the entries of the tree at the time it was taken
+			for i, s := range snapshots {
+				assert.Equal(t, s.entries, snapshotEntries(s.snapshot), "unexpected entries in snapshot %d", i)
+				checkSnapNodes(t, s.snapshot.root)
+			}
+			assert.Equal(t, treeEntries(tree), snapshotEntries(tree.Snapshot()))
+		})
+	}
+}
+
+func TestTree_Snapshot_shared(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	for i := 0; i < 100; i++ {
+		tree.Insert(i, i)
+	}
+	s := tree.Snapshot()
+	assert.Same(t, s.root, tree.Snapshot().root, "expected snapshots of an unchanged tree to share their entries")
+
+	// a write copies only the path to the change
+	n, _ := tree.Search(50)
+	tree.SetValue(n, -1)
+	copied, shared := 0, 0
+	var count func(a, b *snapNode[int, int])
+	count = func(a, b *snapNode[int, int]) {
+		switch {
+		case a == nil:
+			return
+		case a == b:
+			shared++
+			return
+		}
+		copied++
+		count(a.left, b.left)
+		count(a.right, b.right)
+	}
+	count(tree.Snapshot().root, s.root)
+	assert.LessOrEqual(t, copied, 8, "expected only the path to the change to be copied")
+	assert.Positive(t, shared)
+	v, _ := s.Get(50)
+	assert.Equal(t, 50, v, "expected the earlier snapshot to be unchanged")
+
+	// replacing the entries at once starts afresh
+	require.NoError(t, tree.LoadSorted([]int{1, 2, 3}, nil, 1))
+	assert.Equal(t, []string{"1=0", "2=0", "3=0"}, snapshotEntries(tree.Snapshot()))
+	tree.Clear()
+	assert.Equal(t, 0, tree.Snapshot().Len())
+	assert.Equal(t, 100, s.Len())
+}
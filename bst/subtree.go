@@ -0,0 +1,228 @@
+package bst
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSubtreeOverlap is wrapped by the error returned by Tree.AttachSubtree when the keys of the
+// subtree do not fit between two adjacent keys of the tree.
+var ErrSubtreeOverlap = errors.New("subtree keys overlap tree keys")
+
+// DetachSubtree removes the subtree rooted at node n from the tree, and returns it as a new
+// standalone tree, without copying or reinserting any node.
+//
+// The new tree has the same LessFunc, options, AugmentFunc and NodeFormatter as the tree.
+// The detached nodes keep their keys, values and metadata, and belong to the new tree from
+// then on (see Node.BelongsTo), so existing node handles remain valid with the new tree.
+//
+// Detaching is performed in O(h + m) time, where h is the height of the tree and m the number of
+// nodes in the subtree, as each detached node is moved over to the new tree's sentinel nil node.
+//
+// Example Usage:
+//
+//	// move the branch rooted at n to its own tree
+//	branch, ok := tree.DetachSubtree(n)
+//
+// Returns:
+//   - (*Tree[K, V, M], true) if the subtree was detached.
+//   - (nil, false) if n is nil, has been removed, or belongs to a different tree.
+func (t *Tree[K, V, M]) DetachSubtree(n *Node[K, V, M]) (*Tree[K, V, M], bool) {
+	if t.IsNil(n) || !n.BelongsTo(t) {
+		return nil, false
+	}
+
+	// unlink the subtree, and update the sizes of its former ancestors
+	parent := n.parent
+	switch {
+	case t.IsNil(parent):
+		t.root = t.nil
+	case parent.left == n:
+		parent.left = t.nil
+	default:
+		parent.right = t.nil
+	}
+	t.RefreshPath(parent)
+	t.refreshBounds()
+
+	detached := t.newEmpty()
+	detached.adoptSubtree(t, n, detached.nil)
+	detached.root = n
+	detached.refreshBounds()
+	t.notifySubtree(ChangeDelete, detached, n)
+	return detached, true
+}
+
+// AttachSubtree moves every node of tree sub into the tree, linking the root of sub as a child of
+// the node between whose key and the next key all the keys of sub fall, without copying or
+// reinserting any node. It is the counterpart of Tree.DetachSubtree.
+//
+// sub must be ordered by the same LessFunc as the tree. Once attached, sub is left empty and its
+// nodes belong to the tree (see Node.BelongsTo), so existing node handles remain valid with the tree.
+// In multiset mode (see WithDuplicateKeys), keys of sub equal to keys of the tree are ordered after
+// them, as if they had been inserted.
+//
+// Attaching is performed in O(h + m) time, where h is the height of the tree and m the number of nodes in sub.
+// The tree is left unchanged if an error is returned.
+//
+// Returns:
+//   - nil if sub was attached, or is empty.
+//   - An error wrapping ErrSubtreeOverlap if the keys of sub do not all fall between two adjacent keys of the tree.
+//   - An error describing the problem if sub is nil or is the tree itself, if sub is not a valid tree
+//     (see Tree.IsTreeValid), or if sub holds equal keys while the tree does not allow duplicate keys.
+func (t *Tree[K, V, M]) AttachSubtree(sub *Tree[K, V, M]) error {
+	switch {
+	case sub == nil:
+		return fmt.Errorf("cannot attach nil tree")
+	case sub == t:
+		return fmt.Errorf("cannot attach a tree to itself")
+	case sub.IsNil(sub.root):
+		return nil
+	}
+	if err := sub.IsTreeValid(); err != nil {
+		return fmt.Errorf("invalid subtree: %w", err)
+	}
+
+	// check the keys of sub are ordered as the tree requires
+	lo, hi := sub.Min(sub.root), sub.Max(sub.root)
+	for n := lo; n != hi; {
+		next := sub.Successor(n)
+		if !t.less(n.key, next.key) && (!t.duplicates || t.less(next.key, n.key)) {
+			return fmt.Errorf("invalid subtree: %w at node: %v", ErrOrderViolation, next.key)
+		}
+		n = next
+	}
+
+	// find the empty child position between the keys adjacent to lo and hi
+	parent, left := t.nil, false
+	for x := t.root; !t.IsNil(x); {
+		switch {
+		case t.less(hi.key, x.key):
+			parent, left, x = x, true, x.left
+		case t.less(x.key, lo.key) || (t.duplicates && !t.less(lo.key, x.key)):
+			parent, left, x = x, false, x.right
+		default:
+			return fmt.Errorf("%w: key %v is within [%v, %v]", ErrSubtreeOverlap, x.key, lo.key, hi.key)
+		}
+	}
+
+	root := sub.root
+	t.adoptSubtree(sub, root, parent)
+	switch {
+	case t.IsNil(parent):
+		t.root = root
+	case left:
+		parent.left = root
+	default:
+		parent.right = root
+	}
+	t.RefreshPath(parent)
+	t.refreshBounds()
+	sub.root = sub.nil
+	sub.refreshBounds()
+	t.notifySubtree(ChangeInsert, t, root)
+	return nil
+}
+
+// CopySubtree returns a new standalone tree holding a copy of the subtree rooted at node n,
+// with the same shape, keys, values and metadata. The tree itself is left unchanged.
+//
+// The new tree has the same LessFunc, options, AugmentFunc and NodeFormatter as the tree, and is
+// independent of it: modifying either tree does not affect the other. Keys, values and metadata
+// are copied by assignment, so any pointers, slices or maps they hold are shared between the trees.
+//
+// Copying is performed in O(m) time, where m is the number of nodes in the subtree.
+//
+// Example Usage:
+//
+//	// copy the branch rooted at key 20, to ship it elsewhere
+//	n, _ := tree.Search(20)
+//	part, ok := tree.CopySubtree(n)
+//
+// Returns:
+//   - (*Tree[K, V, M], true) if the subtree was copied.
+//   - (nil, false) if n is nil, has been removed, or belongs to a different tree.
+func (t *Tree[K, V, M]) CopySubtree(n *Node[K, V, M]) (*Tree[K, V, M], bool) {
+	if t.IsNil(n) || !n.BelongsTo(t) {
+		return nil, false
+	}
+	copied := t.newEmpty()
+	copied.root = copied.copyNode(n, copied.nil)
+
+	// copy the children of each copied node, without recursion
+	type pair struct{ src, dst *Node[K, V, M] }
+	stack := []pair{{n, copied.root}}
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !t.IsNil(p.src.left) {
+			p.dst.left = copied.copyNode(p.src.left, p.dst)
+			stack = append(stack, pair{p.src.left, p.dst.left})
+		}
+		if !t.IsNil(p.src.right) {
+			p.dst.right = copied.copyNode(p.src.right, p.dst)
+			stack = append(stack, pair{p.src.right, p.dst.right})
+		}
+	}
+	copied.refreshBounds()
+	return copied, true
+}
+
+// copyNode returns a new node of t with the key, value, metadata and subtree size of n,
+// the given parent, and no children.
+func (t *Tree[K, V, M]) copyNode(n, parent *Node[K, V, M]) *Node[K, V, M] {
+	copied := t.newNode()
+	*copied = Node[K, V, M]{
+		key:      n.key,
+		value:    n.value,
+		metadata: n.metadata,
+		size:     n.size,
+		parent:   parent,
+		left:     t.nil,
+		right:    t.nil,
+		gen:      copied.gen,
+		tree:     t,
+	}
+	return copied
+}
+
+// newEmpty returns a new empty tree with the same LessFunc, options, AugmentFunc and NodeFormatter as t.
+func (t *Tree[K, V, M]) newEmpty() *Tree[K, V, M] {
+	empty := &Tree[K, V, M]{
+		less:        t.less,
+		nil:         &Node[K, V, M]{},
+		augmentFunc: t.augmentFunc,
+		formatter:   t.formatter,
+		options:     t.options,
+		alloc:       t.alloc,
+	}
+	empty.nil.parent = empty.nil
+	empty.root = empty.nil
+	empty.first, empty.last = empty.nil, empty.nil
+	return empty
+}
+
+// adoptSubtree moves the subtree rooted at n from tree src into t, without recursion: its nodes are
+// marked as belonging to t, their links to the sentinel nil node of src are replaced with links to
+// the sentinel nil node of t, and the parent of n is set to parent.
+//
+// The caller is responsible for linking n to parent, and for unlinking it from src.
+func (t *Tree[K, V, M]) adoptSubtree(src *Tree[K, V, M], n, parent *Node[K, V, M]) {
+	n.parent = parent
+	stack := []*Node[K, V, M]{n}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		n.tree = t
+		if src.IsNil(n.left) {
+			n.left = t.nil
+		} else {
+			stack = append(stack, n.left)
+		}
+		if src.IsNil(n.right) {
+			n.right = t.nil
+		} else {
+			stack = append(stack, n.right)
+		}
+	}
+}
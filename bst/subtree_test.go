@@ -0,0 +1,248 @@
+package bst
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// keysOf returns the keys of tree in order.
+func keysOf[V, M any](tree *Tree[int, V, M]) []int {
+	var keys []int
+	for n := tree.Min(tree.Root()); !tree.IsNil(n); n = tree.Successor(n) {
+		keys = append(keys, tree.Key(n))
+	}
+	return keys
+}
+
+func TestTree_DetachSubtree(t *testing.T) {
+	tree := New[int, string, struct{}](func(a, b int) bool { return a < b })
+	for _, key := range []int{5, 2, 8, 0, 3, 7, 9, 1, 4, 6} {
+		tree.Insert(key, "value")
+	}
+	n, _ := tree.Search(2)
+
+	branch, ok := tree.DetachSubtree(n)
+	require.True(t, ok)
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	require.NoError(t, branch.IsTreeValid(), "expected valid detached tree")
+	assert.Equal(t, []int{5, 6, 7, 8, 9}, keysOf(tree))
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, keysOf(branch))
+	assert.Equal(t, 5, tree.Size())
+	assert.Equal(t, 5, branch.Size())
+
+	// node handles move over to the detached tree
+	assert.True(t, n.BelongsTo(branch))
+	assert.False(t, n.BelongsTo(tree))
+	assert.Equal(t, n, branch.Root())
+	m, found := branch.Search(4)
+	require.True(t, found)
+	_, deleted := tree.Delete(m)
+	assert.False(t, deleted, "expected detached node not to be deleted from the original tree")
+	_, deleted = branch.Delete(m)
+	assert.True(t, deleted)
+	require.NoError(t, branch.IsTreeValid(), "expected valid detached tree")
+
+	// the detached tree is independent
+	branch.Insert(10, "ten")
+	assert.Equal(t, []int{0, 1, 2, 3, 10}, keysOf(branch))
+	assert.Equal(t, []int{5, 6, 7, 8, 9}, keysOf(tree))
+
+	// detaching the root empties the tree
+	all, ok := tree.DetachSubtree(tree.Root())
+	require.True(t, ok)
+	assert.True(t, tree.IsNil(tree.Root()))
+	assert.Equal(t, 0, tree.Size())
+	assert.Equal(t, []int{5, 6, 7, 8, 9}, keysOf(all))
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	require.NoError(t, all.IsTreeValid(), "expected valid detached tree")
+
+	// invalid nodes
+	_, ok = tree.DetachSubtree(tree.Root())
+	assert.False(t, ok, "expected sentinel nil node not to be detached")
+	_, ok = tree.DetachSubtree(n)
+	assert.False(t, ok, "expected foreign node not to be detached")
+	_, ok = tree.DetachSubtree(nil)
+	assert.False(t, ok, "expected nil node not to be detached")
+}
+
+func TestTree_DetachSubtree_options(t *testing.T) {
+	tree := New[int, int, int](func(a, b int) bool { return a < b }, WithReverseOrder(), WithDuplicateKeys())
+	tree.SetAugmentFunc(func(n *Node[int, int, int]) {
+		n.metadata = n.value + n.left.metadata + n.right.metadata
+	})
+	for i := 0; i < 20; i++ {
+		tree.Insert(i%10, i)
+	}
+	n, _ := tree.Search(5)
+	branch, ok := tree.DetachSubtree(n)
+	require.True(t, ok)
+
+	// order, duplicates and augmentation carry over to the detached tree
+	assert.Equal(t, 9, branch.Key(branch.Min(branch.Root())))
+	branch.Insert(5, 100)
+	assert.Equal(t, 3, branch.Count(5))
+	sum := 0
+	for m := branch.Min(branch.Root()); !branch.IsNil(m); m = branch.Successor(m) {
+		sum += branch.Value(m)
+	}
+	assert.Equal(t, sum, branch.Metadata(branch.Root()))
+	require.NoError(t, branch.IsTreeValid(), "expected valid detached tree")
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+}
+
+func TestTree_AttachSubtree(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	tree := New[int, string, struct{}](less)
+	for _, key := range []int{5, 2, 8, 0, 3, 7, 9, 1, 4, 6} {
+		tree.Insert(key, "value")
+	}
+
+	// detach then reattach a branch
+	n, _ := tree.Search(8)
+	branch, ok := tree.DetachSubtree(n)
+	require.True(t, ok)
+	require.NoError(t, tree.AttachSubtree(branch))
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, keysOf(tree))
+	assert.Equal(t, 10, tree.Size())
+	assert.True(t, n.BelongsTo(tree))
+	assert.Equal(t, 0, branch.Size(), "expected attached tree to be empty")
+	require.NoError(t, branch.IsTreeValid(), "expected valid empty tree")
+
+	// attach a separately built tree into a gap
+	other := New[int, string, struct{}](less)
+	for _, key := range []int{12, 11, 13} {
+		other.Insert(key, "other")
+	}
+	handle, _ := other.Search(11)
+	require.NoError(t, tree.AttachSubtree(other))
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 11, 12, 13}, keysOf(tree))
+	assert.True(t, handle.BelongsTo(tree))
+	_, deleted := tree.Delete(handle)
+	assert.True(t, deleted)
+
+	// attach into an empty tree
+	empty := New[int, string, struct{}](less)
+	all, _ := tree.DetachSubtree(tree.Root())
+	require.NoError(t, empty.AttachSubtree(all))
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 12, 13}, keysOf(empty))
+	require.NoError(t, empty.IsTreeValid(), "expected valid tree")
+}
+
+func TestTree_AttachSubtree_errors(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	tree := New[int, struct{}, struct{}](less)
+	for _, key := range []int{10, 20, 30} {
+		tree.Insert(key, struct{}{})
+	}
+
+	assert.Error(t, tree.AttachSubtree(nil))
+	assert.Error(t, tree.AttachSubtree(tree))
+	assert.NoError(t, tree.AttachSubtree(New[int, struct{}, struct{}](less)), "expected empty tree to be attached")
+
+	for name, keys := range map[string][]int{
+		"overlapping":    {15, 25},
+		"equal key":      {20},
+		"equal boundary": {5, 10},
+	} {
+		t.Run(name, func(t *testing.T) {
+			sub := New[int, struct{}, struct{}](less)
+			for _, key := range keys {
+				sub.Insert(key, struct{}{})
+			}
+			assert.ErrorIs(t, tree.AttachSubtree(sub), ErrSubtreeOverlap)
+			assert.Equal(t, keys, keysOf(sub), "expected subtree to be left unchanged")
+			assert.Equal(t, []int{10, 20, 30}, keysOf(tree), "expected tree to be left unchanged")
+			require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+		})
+	}
+
+	// duplicate keys in a tree that does not allow them
+	dup := New[int, struct{}, struct{}](less, WithDuplicateKeys())
+	dup.Insert(25, struct{}{})
+	dup.Insert(25, struct{}{})
+	assert.ErrorIs(t, tree.AttachSubtree(dup), ErrOrderViolation)
+
+	// corrupted subtree
+	bad := New[int, struct{}, struct{}](less)
+	bad.Insert(21, struct{}{})
+	n, _ := bad.Insert(22, struct{}{})
+	n.size = 5
+	assert.ErrorIs(t, tree.AttachSubtree(bad), ErrSubtreeSizeMismatch)
+	assert.Equal(t, 3, tree.Size())
+}
+
+func TestTree_AttachSubtree_duplicates(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	tree := New[int, int, struct{}](less, WithDuplicateKeys())
+	for i := 0; i < 3; i++ {
+		tree.Insert(1, i)
+		tree.Insert(3, i)
+	}
+	sub := New[int, int, struct{}](less, WithDuplicateKeys())
+	sub.Insert(1, 10)
+	sub.Insert(2, 10)
+	require.NoError(t, tree.AttachSubtree(sub))
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	assert.Equal(t, []int{1, 1, 1, 1, 2, 3, 3, 3}, keysOf(tree))
+
+	// attached keys come after equal keys already in the tree
+	var values []int
+	for n, _ := tree.Search(1); !tree.IsNil(n) && tree.Key(n) == 1; n = tree.Successor(n) {
+		values = append(values, tree.Value(n))
+	}
+	assert.Equal(t, []int{0, 1, 2, 10}, values)
+}
+
+func TestTree_CopySubtree(t *testing.T) {
+	tree := New[int, []int, int](func(a, b int) bool { return a < b })
+	tree.SetAugmentFunc(func(n *Node[int, []int, int]) {
+		n.metadata = n.key + n.left.metadata + n.right.metadata
+	})
+	for _, key := range []int{5, 2, 8, 0, 3, 7, 9, 1, 4, 6} {
+		tree.Insert(key, []int{key})
+	}
+	n, _ := tree.Search(2)
+
+	part, ok := tree.CopySubtree(n)
+	require.True(t, ok)
+	require.NoError(t, part.IsTreeValid(), "expected valid copy")
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, keysOf(part))
+	assert.Equal(t, 10, part.Metadata(part.Root()), "expected metadata to be copied")
+	assert.Equal(t, 2, part.Key(part.Root()))
+	assert.Equal(t, 0, part.Key(part.Left(part.Root())), "expected shape to be preserved")
+
+	// the tree is left unchanged, and its nodes do not belong to the copy
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	assert.Equal(t, 10, tree.Size())
+	assert.True(t, n.BelongsTo(tree))
+	assert.False(t, part.Root().BelongsTo(tree))
+	assert.True(t, part.Root().BelongsTo(part))
+	assert.NotSame(t, n, part.Root())
+
+	// the trees are independent
+	part.Insert(10, []int{10})
+	m, _ := part.Search(0)
+	part.Delete(m)
+	assert.Equal(t, []int{1, 2, 3, 4, 10}, keysOf(part))
+	assert.Equal(t, 20, part.Metadata(part.Root()))
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, keysOf(tree))
+	assert.Equal(t, 45, tree.Metadata(tree.Root()))
+	require.NoError(t, part.IsTreeValid(), "expected valid copy")
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+
+	// copying the root copies the whole tree
+	all, ok := tree.CopySubtree(tree.Root())
+	require.True(t, ok)
+	assert.True(t, tree.EqualStructure(all, nil))
+
+	// invalid nodes
+	_, ok = tree.CopySubtree(tree.Sentinel())
+	assert.False(t, ok, "expected sentinel nil node not to be copied")
+	_, ok = tree.CopySubtree(m)
+	assert.False(t, ok, "expected deleted node not to be copied")
+	_, ok = tree.CopySubtree(nil)
+	assert.False(t, ok, "expected nil node not to be copied")
+}
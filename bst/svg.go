@@ -0,0 +1,148 @@
+package bst
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// These constants control the layout used by Tree.WriteSVG.
+const (
+	svgCharWidth  = 8  // approximate width of a label character, in pixels
+	svgNodeHeight = 28 // height of a node box, in pixels
+	svgPadding    = 10 // horizontal padding inside a node box, and margin around the drawing, in pixels
+	svgLevelGap   = 60 // vertical distance between tree levels, in pixels
+)
+
+// SVGNodeStyle describes how a single node is drawn by Tree.WriteSVG.
+//
+// Colors are any valid SVG/CSS color (e.g., "white", "#c00").
+// Empty fields fall back to the default style (a white box with black text).
+type SVGNodeStyle struct {
+	Fill   string // Fill color of the node box
+	Stroke string // Border color of the node box
+	Text   string // Color of the node label
+}
+
+// WriteSVG writes a standalone SVG drawing of the tree to w.
+//
+// Unlike Tree.String, which is limited by the width of a terminal, the drawing lays the tree out
+// in two dimensions: each node is placed in its own column according to its in-order position,
+// and in a row according to its depth, with edges drawn between parents and children.
+// The result can be viewed in any web browser, or embedded in HTML documents.
+//
+// Each node is labelled using the function registered with Tree.SetNodeFormatter. If no function
+// is registered, nodes are labelled "key: value", where keys and values implementing fmt.Stringer
+// are formatted with their String method.
+//
+// Parameters:
+//   - w: The writer to write the SVG document to.
+//   - style: An optional function returning the style of each node (e.g., to color nodes by metadata).
+//     If nil, all nodes use the default style.
+//
+// Returns:
+//   - Any error returned by w.
+//
+// If the tree is empty, a drawing containing the text "Empty Tree" is written.
+func (t *Tree[K, V, M]) WriteSVG(w io.Writer, style func(n *Node[K, V, M]) SVGNodeStyle) error {
+	builder := new(strings.Builder)
+
+	// if tree is empty, draw a placeholder
+	if t.root == t.nil {
+		builder.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" width="120" height="48">`)
+		builder.WriteString(`<text x="60" y="24" text-anchor="middle" dominant-baseline="middle" font-family="monospace" font-size="14">Empty Tree</text>`)
+		builder.WriteString("</svg>\n")
+		_, err := io.WriteString(w, builder.String())
+		return err
+	}
+
+	// lay out nodes: one column per node in order, one row per level.
+	// the column width is set by the widest label, so that labels never overlap.
+	type layout struct {
+		label string
+		x, y  int
+	}
+	nodes := make(map[*Node[K, V, M]]*layout, t.Size())
+	order := make([]*Node[K, V, M], 0, t.Size())
+	maxChars, maxDepth := 0, 0
+	for n := t.Min(t.root); n != t.nil; n = t.Successor(n) {
+		l := &layout{label: t.svgLabel(n), y: t.Depth(n)}
+		maxChars = max(maxChars, utf8.RuneCountInString(l.label))
+		maxDepth = max(maxDepth, l.y)
+		nodes[n] = l
+		order = append(order, n)
+	}
+	boxWidth := maxChars*svgCharWidth + 2*svgPadding
+	colWidth := boxWidth + svgPadding
+	for i, n := range order {
+		l := nodes[n]
+		l.x = svgPadding + i*colWidth + boxWidth/2
+		l.y = svgPadding + l.y*svgLevelGap + svgNodeHeight/2
+	}
+	width := len(order)*colWidth + svgPadding
+	height := maxDepth*svgLevelGap + svgNodeHeight + 2*svgPadding
+
+	fmt.Fprintf(builder, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		width, height, width, height)
+	builder.WriteString("\n")
+
+	// draw edges first, so that node boxes are drawn over them
+	for _, n := range order {
+		if n.parent == t.nil {
+			continue
+		}
+		c, p := nodes[n], nodes[n.parent]
+		fmt.Fprintf(builder, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="gray"/>`, p.x, p.y, c.x, c.y)
+		builder.WriteString("\n")
+	}
+
+	// draw nodes
+	for _, n := range order {
+		l := nodes[n]
+		s := SVGNodeStyle{Fill: "white", Stroke: "black", Text: "black"}
+		if style != nil {
+			custom := style(n)
+			if custom.Fill != "" {
+				s.Fill = custom.Fill
+			}
+			if custom.Stroke != "" {
+				s.Stroke = custom.Stroke
+			}
+			if custom.Text != "" {
+				s.Text = custom.Text
+			}
+		}
+		fmt.Fprintf(builder, `<rect x="%d" y="%d" width="%d" height="%d" rx="6" fill="%s" stroke="%s"/>`,
+			l.x-boxWidth/2, l.y-svgNodeHeight/2, boxWidth, svgNodeHeight,
+			html.EscapeString(s.Fill), html.EscapeString(s.Stroke))
+		fmt.Fprintf(builder, `<text x="%d" y="%d" text-anchor="middle" dominant-baseline="middle" font-family="monospace" font-size="13" fill="%s">%s</text>`,
+			l.x, l.y, html.EscapeString(s.Text), html.EscapeString(l.label))
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString("</svg>\n")
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// svgLabel returns the label of node n in an SVG drawing.
+func (t *Tree[K, V, M]) svgLabel(n *Node[K, V, M]) string {
+	if t.formatter != nil {
+		return t.formatter(n.key, n.value, n.metadata)
+	}
+	value := "<nil>"
+	if !n.IsValueNil() {
+		value = svgString(n.value)
+	}
+	return svgString(n.key) + ": " + value
+}
+
+// svgString formats v for a node label, using its String method if it implements fmt.Stringer.
+func svgString(v any) string {
+	if s, ok := v.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -9,6 +9,7 @@
 // This implementation does not balance itself. If self-balancing behavior is required,
 // consider using an AVL Tree or Red-Black Tree (see rbtree.Tree), which can be implemented by extending bst.Tree
 // (e.g., for a Red-Black Tree, the node color can be stored in the node metadata).
+// Alternatively, [bst.Tree.Rebalance] restores balance on demand, in O(n) time.
 //
 // Keys must have strict weak ordering. If keys do not have a strict weak ordering, the behavior is undefined.
 // Strict weak ordering means that the LessFunc function must define a consistent and transitive ordering.
@@ -17,6 +18,12 @@
 //   - If a < b and b < c, then a < c must be true.
 //   - If a < b is true, then b < a must be false.
 //
+// # Duplicate Keys
+//
+// By default, keys are unique: inserting an existing key updates the value of the existing node.
+// Passing WithDuplicateKeys to New enables multiset mode, where equal keys coexist in insertion order
+// and Tree.Count reports how many nodes share a key.
+//
 // # Metadata: Node-Persistent Data
 //
 // Each node in the BST contains an optional metadata field, which is intended to be used when extending the base Tree type.
@@ -29,6 +36,26 @@
 //
 // If metadata is not needed, struct{} can be used as the metadata type, ensuring zero memory overhead.
 //
+// # Augmentation
+//
+// Metadata can hold aggregates over a node's subtree (e.g., sums, maximum interval endpoints).
+// Register an AugmentFunc with [bst.Tree.SetAugmentFunc] and the tree keeps these aggregates up to date
+// through insertions, deletions and rotations, invoking the function bottom-up on every affected node.
+//
+// # Order Statistics
+//
+// Every node records the size of the subtree rooted at it. Sizes are maintained automatically by
+// Insert, Delete, RotateLeft and RotateRight, which allows the following queries in O(h) time:
+//
+//   - [bst.Tree.Size] – The number of nodes in the tree (O(1)).
+//   - [bst.Tree.Rank] – The number of keys less than a given key.
+//   - [bst.Tree.Select] – The node with a given zero-based rank.
+//   - [bst.Tree.KthSmallest] and [bst.Tree.KthLargest] – The k-th smallest or largest node (one-based).
+//   - [bst.Tree.CountRange] – The number of keys within a half-open interval.
+//   - [bst.Tree.DeleteAt] – Removes the node with a given zero-based rank.
+//   - [bst.Tree.AscendAt] and [bst.Tree.SliceByRank] – The nodes from a given rank, or within a range of
+//     ranks, in O(h + m) time for m nodes.
+//
 // # Unsafe Methods
 //
 // The following methods should generally not be used, as they can violate
@@ -41,6 +68,9 @@
 // The following methods directly modify tree structure and should only be used in extensions:
 //
 //   - [bst.Tree.MustSetMetadata] – Forcefully sets metadata (use with caution).
+//   - [bst.Tree.RebuildSubtree] – Rebuilds a subtree into a perfectly balanced shape (discards balancing metadata).
+//   - [bst.Tree.RefreshPath] – Recomputes subtree sizes after manual relinking.
+//   - [bst.Tree.Release] – Marks a manually unlinked node as removed from the tree.
 //   - [bst.Tree.SetKey] – Changes a node’s key without restructuring the tree (unsafe).
 //   - [bst.Tree.SetLeft] – Directly modifies a node’s left child (violates ordering).
 //   - [bst.Tree.SetMetadata] – Modifies node metadata (safe if used correctly).
@@ -49,6 +79,37 @@
 //   - [bst.Tree.SetRoot] – Changes the root without ensuring valid tree properties.
 //   - [bst.Tree.Transplant] – Replaces a node without fixup (violates ordering)
 //
+// # Extending bst.Tree
+//
+// Balanced trees, such as [rbtree.Tree], [scapegoat.Tree] and [ziptree.Tree], are built on bst.Tree
+// with its exported API only, so that third-party trees can be written outside this module the same
+// way. The tree builder API consists of:
+//
+//   - [bst.Tree.Sentinel] – The sentinel nil node, standing for every absent child and the root's parent.
+//   - [bst.Tree.InsertAt] – Links a new leaf below a given parent, as found by a search.
+//   - [bst.Tree.SetKey] and [bst.Tree.SetValue] – Change the key or value of a node in place.
+//   - [bst.Tree.SetLeft], [bst.Tree.SetRight] and [bst.Tree.SetParent] – Set a single link of a node.
+//   - [bst.Tree.SetRoot] – Sets the root of the tree.
+//   - [bst.Tree.Transplant] – Replaces a subtree with another in its parent (or as the root).
+//   - [bst.Tree.RotateLeft] and [bst.Tree.RotateRight] – Rotations, maintaining every invariant.
+//   - [bst.Tree.RefreshPath] – Recomputes subtree sizes and augmented data after relinking.
+//   - [bst.Tree.Release] – Marks a node unlinked by the extension as removed from the tree.
+//
+// The setters change exactly one field each, so an operation may leave the tree inconsistent between
+// calls. Before returning control to the caller, an extension must restore the following invariants,
+// which Tree.IsTreeValid and Tree.ValidatePath check:
+//
+//  1. Links are mutual: if c is the left or right child of p, c's parent is p. Absent children and the
+//     root's parent are the sentinel nil node, whose own parent is itself. Links are never nil.
+//  2. Keys are in order: an in-order traversal visits keys in ascending order (see WithDuplicateKeys).
+//  3. Subtree sizes are correct: call RefreshPath on the lowest node whose subtree changed.
+//  4. Membership is recorded: new nodes are created with Insert or InsertAt, and nodes removed by
+//     relinking are passed to Release, never to be used again.
+//
+// Metadata, set with SetMetadata, is reserved for the extension (e.g., a node's color), and is never
+// read by bst.Tree itself. Trees embedding bst.Tree should shadow the methods that would bypass their
+// balancing, such as Insert and Delete, with their own.
+//
 // # Development Notes
 //
 // This package was developed as part of a learning exercise to explore data structures
@@ -57,16 +118,9 @@
 package bst
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
-	"strings"
-)
-
-// These "connectors" are used for the Tree.String method when drawing the BST.
-const (
-	connectorLeft     = " ╭── "
-	connectorRight    = " ╰── "
-	connectorVertical = " │   "
-	connectorSpace    = "     "
 )
 
 // LessFunc is a comparison function used to define the ordering of keys in the BST.
@@ -78,8 +132,30 @@ const (
 //	lessFunc := func(a, b int) bool { return a < b }
 //
 // This function must define a consistent and transitive ordering to ensure correct BST behavior.
+// CheckLess can be used to verify this over a set of sample keys.
 type LessFunc[K any] func(a, b K) bool
 
+// AugmentFunc defines a function type used to maintain augmented data on a node.
+//
+// It is invoked on a node after the node's subtree has changed, once the augmented data of
+// its children is up to date. See Tree.SetAugmentFunc.
+//
+// Parameters:
+//   - node: A pointer to the node whose augmented data should be recomputed.
+type AugmentFunc[K, V, M any] func(node *Node[K, V, M])
+
+// NodeFormatter defines a function type used to format a node's contents when drawing the tree.
+// See Tree.SetNodeFormatter.
+//
+// Parameters:
+//   - key: The key of the node.
+//   - value: The value of the node.
+//   - metadata: The metadata of the node.
+//
+// Returns:
+//   - The text to display for the node.
+type NodeFormatter[K, V, M any] func(key K, value V, metadata M) string
+
 // TraversalFunc defines a function type used for processing nodes during a tree traversal.
 //
 // This function receives each node in the tree and performs an operation on it.
@@ -93,6 +169,24 @@ type LessFunc[K any] func(a, b K) bool
 //   - A boolean indicating whether to continue traversal (true) or stop early (false).
 type TraversalFunc[K, V, M any] func(node *Node[K, V, M]) bool
 
+// TraversalErrFunc defines a function type used for processing nodes during a tree traversal
+// where processing may fail.
+//
+// The traversal continues as long as the function returns nil. If the function returns
+// ErrStopTraversal, the traversal stops early without error. Any other error stops the
+// traversal and is returned to the caller.
+//
+// Parameters:
+//   - node: A pointer to the current node being processed.
+//
+// Returns:
+//   - nil to continue traversal, ErrStopTraversal to stop early, or an error to abort the traversal.
+type TraversalErrFunc[K, V, M any] func(node *Node[K, V, M]) error
+
+// ErrStopTraversal can be returned by a TraversalErrFunc to stop a traversal early without
+// the traversal itself returning an error.
+var ErrStopTraversal = errors.New("stop traversal")
+
 // Tree represents a generic binary search tree (BST).
 //
 // It stores Nodes containing key-value pairs and maintains order based on the provided
@@ -100,11 +194,24 @@ type TraversalFunc[K, V, M any] func(node *Node[K, V, M]) bool
 //
 // ⚠️Important: This implementation does not perform automatic re-balancing.
 // If the tree becomes skewed (e.g., inserting keys in sorted order),
-// operations will degrade to O(n) complexity.
+// operations will degrade to O(n) complexity, until Tree.Rebalance is called.
 type Tree[K, V, M any] struct {
-	root *Node[K, V, M] // Root node of the tree.
-	less LessFunc[K]    // Function to compare keys and maintain order.
-	nil  *Node[K, V, M]
+	root        *Node[K, V, M]         // Root node of the tree.
+	less        LessFunc[K]            // Function to compare keys and maintain order.
+	nil         *Node[K, V, M]         // Sentinel nil node.
+	augmentFunc AugmentFunc[K, V, M]   // Function maintaining user-defined augmented data.
+	augmenting  bool                   // True while augmentFunc is running.
+	formatter   NodeFormatter[K, V, M] // Function formatting nodes when drawing the tree.
+	alloc       Allocator[K, V, M]     // Allocator of the tree's nodes, if any.
+	first, last *Node[K, V, M]         // Cached Min and Max of the root, kept up to date by every change.
+	rotations   uint64                 // Number of rotations performed by RotateLeft and RotateRight.
+	onChange    ChangeFunc[K, V]       // Function notified of changes to the tree, if any.
+	version     uint64                 // Incremented on every change to the tree (see Tree.Version).
+	deltaLog    []Change[K, V]         // Latest changes to the tree, if enabled with WithDeltaLog.
+	deltaStart  uint64                 // Version of the tree before the first change in deltaLog.
+	hooks       Hooks[K, V, M]         // Functions called on insertion, deletion and transplant (see Tree.SetHooks).
+	snaps       snapshots[K, V]        // Persistent copy of the entries, once a snapshot has been taken (see Tree.Snapshot).
+	options
 }
 
 // New creates and returns a new empty binary search tree (BST).
@@ -118,6 +225,7 @@ type Tree[K, V, M any] struct {
 //
 // Parameters:
 //   - less: A comparison function that determines the ordering of keys.
+//   - opts: Optional behaviors to enable on the tree (see Option).
 //
 // Returns:
 //   - A pointer to an empty Tree.
@@ -127,34 +235,107 @@ type Tree[K, V, M any] struct {
 //	// Creating a BST with integer keys and string values.
 //	tree := New[int, string, struct{}](func(a, b int) bool { return a < b })
 //	tree.Insert(10, "ten")
-func New[K, V, M any](less LessFunc[K]) *Tree[K, V, M] {
+func New[K, V, M any](less LessFunc[K], opts ...Option) *Tree[K, V, M] {
 	t := &Tree[K, V, M]{
 		less: less,
 		nil:  &Node[K, V, M]{},
 	}
+	for _, opt := range opts {
+		opt(&t.options)
+	}
+	t.alloc = newAllocator[K, V, M](t.options)
+	if t.reverse {
+		t.less = func(a, b K) bool { return less(b, a) }
+	}
 	t.SetRoot(t.nil)
 	t.SetParent(t.root, t.Sentinel())
+	t.first, t.last = t.nil, t.nil
 	return t
 }
 
+// NewOrdered creates and returns a new empty binary search tree (BST) whose keys are ordered
+// with cmp.Less, for keys of an ordered type such as int, string or float64.
+//
+// It is equivalent to New with cmp.Less[K] as the LessFunc, which orders NaN keys before every
+// other key. Use New for keys of other types, or to order keys differently.
+//
+// Parameters:
+//   - opts: Optional behaviors to enable on the tree (see Option).
+//
+// Returns:
+//   - A pointer to an empty Tree.
+//
+// Example Usage:
+//
+//	tree := NewOrdered[int, string, struct{}]()
+//	tree.Insert(10, "ten")
+func NewOrdered[K cmp.Ordered, V, M any](opts ...Option) *Tree[K, V, M] {
+	return New[K, V, M](cmp.Less[K], opts...)
+}
+
+// Clear removes every node from the tree, leaving it empty.
+//
+// Every node is released (see Tree.Release), so that existing node handles no longer belong to the
+// tree, then the tree's Allocator (see WithAllocator) is reset, which frees an Arena's blocks at once.
+// Clear runs in O(n) time.
+func (t *Tree[K, V, M]) Clear() {
+	root, alloc := t.root, t.alloc
+	t.root = t.nil
+	t.refreshBounds()
+
+	// release the nodes without freeing them one by one, as the allocator is reset
+	t.alloc = nil
+	t.releaseSubtree(root)
+	t.alloc = alloc
+	if alloc != nil {
+		alloc.Reset()
+	}
+
+	var (
+		zeroK K
+		zeroV V
+	)
+	t.notify(ChangeClear, zeroK, zeroV)
+}
+
 // Contains checks whether the given node n is present in the tree.
 //
-// The function searches for n's key in the tree and verifies that the
-// returned node is the same as n. This ensures that the node belongs
-// to this specific tree instance and is not an external or detached node.
+// This ensures that the node belongs to this specific tree instance and is
+// not an external, removed (stale) or detached node. See Node.BelongsTo.
 //
 // Returns:
 //   - true if n is in the tree.
-//   - false if n is not found or belongs to a different tree.
+//   - false if n has been removed or belongs to a different tree.
 func (t *Tree[K, V, M]) Contains(n *Node[K, V, M]) bool {
-	n2, found := t.Search(n.key)
-	return found && n == n2
+	return n.BelongsTo(t)
+}
+
+// Count returns the number of nodes in the tree with a key equal to key.
+//
+// Unless multiset mode is enabled (see WithDuplicateKeys), the result is either 0 or 1.
+// Count runs in O(h) time, where h is the tree height.
+func (t *Tree[K, V, M]) Count(key K) int {
+	return t.rankAfter(key) - t.Rank(key)
+}
+
+// CountRange returns the number of nodes whose key falls within the half-open interval [lo, hi).
+//
+// The count is derived from the subtree sizes maintained by the tree, so it is
+// performed in O(h) time, where h is the tree height. If hi is not greater than lo, 0 is returned.
+func (t *Tree[K, V, M]) CountRange(lo, hi K) int {
+	if !t.less(lo, hi) {
+		return 0
+	}
+	return t.Rank(hi) - t.Rank(lo)
 }
 
 // Delete removes the specified node n from the tree.
 //
 // If the deletion is successful, it returns the replacement node (if any) and true.
-// If the node does not exist or is nil, it returns the tree's sentinel nil node and false.
+// If the node is nil, has already been removed, or belongs to a different tree,
+// the tree is left unchanged and the tree's sentinel nil node and false are returned.
+//
+// Once deleted, n is released (see Tree.Release) and must no longer be used with the tree.
 //
 // The deletion process follows standard BST deletion rules:
 //   - If n has no left child, it is replaced by its right child.
@@ -166,25 +347,38 @@ func (t *Tree[K, V, M]) Contains(n *Node[K, V, M]) bool {
 //   - (t.nil, false) if the node was not found or nil.
 func (t *Tree[K, V, M]) Delete(n *Node[K, V, M]) (*Node[K, V, M], bool) {
 
-	// if nil input, don't delete anything and give nil output
-	if t.IsNil(n) || n == nil {
+	// if nil, stale or foreign input, don't delete anything and give nil output
+	if t.IsNil(n) || !n.BelongsTo(t) {
 		return t.nil, false
 	}
+	if t.hooks.BeforeDelete != nil {
+		t.hooks.BeforeDelete(n)
+	}
+	replacement := t.unlink(n)
+	t.Release(n)
+	return replacement, true
+}
 
+// unlink implements Tree.Delete, relinking the neighbours of node n so that it is no longer part of the
+// tree's structure, without releasing it, and returns the node taking its place.
+func (t *Tree[K, V, M]) unlink(n *Node[K, V, M]) *Node[K, V, M] {
 	if t.IsNil(n.left) {
 		replacement := n.right
 		t.Transplant(n, n.right)
-		return replacement, true
+		t.RefreshPath(n.parent)
+		return replacement
 
 	} else if t.IsNil(n.right) {
 		replacement := n.left
 		t.Transplant(n, n.left)
-		return replacement, true
+		t.RefreshPath(n.parent)
+		return replacement
 
 	} else {
 		successor := t.Min(n.right)
-		replacement := successor
+		refreshFrom := successor // lowest node whose subtree has changed
 		if t.Parent(successor) != n {
+			refreshFrom = successor.parent
 			t.Transplant(successor, successor.right)
 			successor.right = n.right
 			successor.right.parent = successor
@@ -192,7 +386,8 @@ func (t *Tree[K, V, M]) Delete(n *Node[K, V, M]) (*Node[K, V, M], bool) {
 		t.Transplant(n, successor)
 		successor.left = n.left
 		successor.left.parent = successor
-		return replacement, true
+		t.RefreshPath(refreshFrom)
+		return successor
 	}
 }
 
@@ -212,77 +407,168 @@ func (t *Tree[K, V, M]) Depth(n *Node[K, V, M]) int {
 	return h
 }
 
+// Equal reports whether the tree and other contain the same keys with the same values.
+//
+// Both trees are walked in-order in lock-step, so the comparison runs in O(n) time and
+// is independent of the shape of either tree. Keys are compared using this tree's LessFunc.
+// If valueEq is nil, values are ignored and only the key sets are compared.
+//
+// Returns:
+//   - true if both trees hold the same keys in the same order, with equal values.
+//   - false otherwise.
+func (t *Tree[K, V, M]) Equal(other *Tree[K, V, M], valueEq func(a, b V) bool) bool {
+	a, b := t.Min(t.root), other.Min(other.root)
+	for !t.IsNil(a) && !other.IsNil(b) {
+		if !t.keysEqual(a.key, b.key) {
+			return false
+		}
+		if valueEq != nil && !valueEq(a.value, b.value) {
+			return false
+		}
+		a, b = t.Successor(a), other.Successor(b)
+	}
+	return t.IsNil(a) && other.IsNil(b)
+}
+
+// EqualStructure reports whether the tree and other are equal (see Tree.Equal) and also have
+// an identical shape, that is, every node has the same key and value in the same position.
+//
+// If valueEq is nil, values are ignored and only keys and shape are compared.
+// Metadata is not compared.
+func (t *Tree[K, V, M]) EqualStructure(other *Tree[K, V, M], valueEq func(a, b V) bool) bool {
+	return t.equalStructure(other, t.root, other.root, valueEq)
+}
+
+// equalStructure compares the subtree rooted at a (in t) with the subtree rooted at b (in other).
+func (t *Tree[K, V, M]) equalStructure(other *Tree[K, V, M], a, b *Node[K, V, M], valueEq func(a, b V) bool) bool {
+	if t.IsNil(a) || other.IsNil(b) {
+		return t.IsNil(a) && other.IsNil(b)
+	}
+	if !t.keysEqual(a.key, b.key) {
+		return false
+	}
+	if valueEq != nil && !valueEq(a.value, b.value) {
+		return false
+	}
+	return t.equalStructure(other, a.left, b.left, valueEq) &&
+		t.equalStructure(other, a.right, b.right, valueEq)
+}
+
+// Height returns the number of edges on the longest path from the root to a leaf,
+// or -1 if the tree is empty.
+//
+// Height visits every node, without recursion, so takes O(n) time.
+func (t *Tree[K, V, M]) Height() int {
+	height := -1
+	level := []*Node[K, V, M]{t.root}
+	for {
+		var next []*Node[K, V, M]
+		for _, n := range level {
+			if !t.IsNil(n) {
+				next = append(next, n.left, n.right)
+			}
+		}
+		if len(next) == 0 {
+			return height
+		}
+		height++
+		level = next
+	}
+}
+
 // Insert inserts a new node with the given key and value into the tree.
 //
-// If a node with the same key already exists, its value is updated,
-// and the existing node is returned with true.
+// If a node with the same key already exists, it is resolved by the tree's DuplicatePolicy (see
+// WithDuplicatePolicy): by default, its value is updated, and the existing node is returned with false.
+// Use Tree.InsertIfAbsent to never update an existing key, whatever the policy.
 //
 // Otherwise, a new node is created, inserted at the appropriate position,
-// and returned with false.
+// and returned with true.
 //
 // The function maintains BST ordering:
 //   - If key is less than the current node's key, it is inserted in the left subtree.
 //   - If key is greater, it is inserted in the right subtree.
 //   - If key already exists, its value is updated instead of creating a duplicate.
+//     In multiset mode (see WithDuplicateKeys), a new node is inserted after all equal keys instead.
+//
+// Insert panics with an error wrapping ErrDuplicateKey if the key exists under the DuplicateError
+// policy. Use Tree.TryInsert to handle the error instead.
 //
 // Returns:
-//   - (*Node[K, V, M], false) if the key existed and the value was updated.
+//   - (*Node[K, V, M], false) if the key existed and the value was updated (or kept, under DuplicateIgnore).
 //   - (*Node[K, V, M], true) if a new node was inserted.
 func (t *Tree[K, V, M]) Insert(key K, value V) (*Node[K, V, M], bool) {
-
-	parent := t.nil    // trailing pointer - parent of current node
-	currNode := t.root // current node
-
-	// find nil leaf where new node will be inserted
-	for !t.IsNil(currNode) {
-
-		// update trailing pointer
-		parent = currNode
-
-		if t.keysEqual(currNode.key, key) {
-
-			// If key already exists, update the value
-			currNode.value = value
-			return currNode, false
-
-		} else if t.less(key, currNode.key) {
-
-			// If key is smaller, go left
-			currNode = currNode.left
-
-		} else {
-
-			// If key is larger, go right
-			currNode = currNode.right
-		}
+	n, inserted, err := t.InsertWithPolicy(key, value, t.policy)
+	if err != nil {
+		panic(err)
 	}
+	return n, inserted
+}
 
-	// Create a new node to insert
-	newNode := &Node[K, V, M]{
-		key:    key,
-		value:  value,
-		parent: parent,
-		left:   t.nil,
-		right:  t.nil,
+// InsertAt creates a new node with the given key and value, and links it into the tree as a leaf
+// child of parent, as Tree.Insert links a new node at the end of its search path.
+//
+// parent must be the last node on the search path of key, as found by Tree.Insert, or the sentinel
+// nil node if the tree is empty. No search is made, so trees extending bst.Tree can find the position
+// of a new node with their own descent, restructuring the tree on the way down (as rbtree does with
+// its top-down strategy, see rbtree.Tree.SetStrategy). In unique mode, parent must not hold key.
+//
+// The augmented data of the new node and its ancestors is refreshed, and the insertion is notified
+// (see Tree.OnChange).
+//
+// This function is intended to be used only when extending bst.Tree.
+//
+// Returns:
+//   - The new node.
+func (t *Tree[K, V, M]) InsertAt(parent *Node[K, V, M], key K, value V) *Node[K, V, M] {
+	newNode := t.newNode()
+	*newNode = Node[K, V, M]{
+		key:   key,
+		value: value,
+		gen:   newNode.gen,
+		tree:  t,
+	}
+	t.link(newNode, parent)
+	if t.hooks.AfterInsert != nil {
+		t.hooks.AfterInsert(newNode)
 	}
 
+	t.checkDegraded(newNode)
+	t.snapInsert(newNode)
+	t.notify(ChangeInsert, key, value)
+	return newNode
+}
+
+// link links node n, whose key is set, into the tree as a leaf child of parent, as found by Tree.Insert
+// (the sentinel nil node if the tree is empty), and updates the augmented data of n and its ancestors.
+func (t *Tree[K, V, M]) link(n, parent *Node[K, V, M]) {
+	n.parent, n.left, n.right, n.size = parent, t.nil, t.nil, 1
+
 	if t.IsNil(parent) {
 
 		// If the tree was empty, set root
-		t.root = newNode
+		t.root = n
+		t.first, t.last = n, n
 
-	} else if t.less(key, parent.key) {
+	} else if t.less(n.key, parent.key) {
 
 		// if the key is less than the parent key, insert new node as left child
-		parent.left = newNode
+		parent.left = n
+		if parent == t.first {
+			t.first = n
+		}
 
 	} else {
 
 		// if the key is greater than the parent key, insert new node as right child
-		parent.right = newNode
+		parent.right = n
+		if parent == t.last {
+			t.last = n
+		}
 	}
 
-	return newNode, true
+	// update augmented data of the new node and its ancestors
+	t.RefreshPath(n)
 }
 
 // IsFull returns true if the given node n has both left and right children.
@@ -330,9 +616,16 @@ func (t *Tree[K, V, M]) IsUnary(n *Node[K, V, M]) bool {
 //   - The root node’s parent is correctly set to the sentinel nil node.
 //   - The tree maintains proper in-order traversal order (keys are correctly sorted).
 //   - Parent-child relationships are correctly maintained.
+//   - Each node's subtree size matches the sizes of its children.
 //
 // The validation is performed using an in-order traversal to ensure that
-// all nodes follow the correct key ordering and structural constraints.
+// all nodes follow the correct key ordering and structural constraints. The traversal
+// is iterative, so that degenerate trees of any height can be validated without
+// overflowing the stack, and stops once more nodes are reached than the root's
+// subtree size, so that trees with cycles are reported rather than walked forever.
+//
+// The error returned wraps one of ErrSentinelAltered, ErrParentChildMismatch, ErrOrderViolation
+// or ErrSubtreeSizeMismatch, so that the kind of violation can be tested with errors.Is.
 //
 // Returns:
 //   - nil if the tree is valid.
@@ -340,23 +633,20 @@ func (t *Tree[K, V, M]) IsUnary(n *Node[K, V, M]) bool {
 func (t *Tree[K, V, M]) IsTreeValid() error {
 	// check sentinel has not been changed
 	if t.nil.parent != t.nil {
-		return fmt.Errorf("sentinel nil node parent not sentinel nil node")
+		return ErrSentinelAltered
 	}
 
 	// check root node has nil parent
 	if t.root.parent != t.nil {
-		return fmt.Errorf("root node parent not sentinel nil node")
+		return fmt.Errorf("root node parent not sentinel nil node: %w", ErrParentChildMismatch)
 	}
 
 	// Recurse the tree in order. Check:
 	//  - node keys are in order
 	//  - node parent/child relationships are correct
-	var (
-		err              error
-		currKey, prevKey K
-	)
+	var currKey, prevKey K
 	first := true
-	t.TraverseInOrder(t.root, func(node *Node[K, V, M]) bool {
+	return t.walkInOrder(func(node *Node[K, V, M]) error {
 		prevKey = currKey
 		currKey = node.key
 
@@ -365,9 +655,9 @@ func (t *Tree[K, V, M]) IsTreeValid() error {
 		} else {
 
 			// if not first node, currKey should be greater than prevKey
-			if !t.less(prevKey, currKey) {
-				err = fmt.Errorf("traversal error: out of order keys at node: %v", node.key)
-				return false
+			// (or equal to prevKey in multiset mode)
+			if (!t.duplicates && !t.less(prevKey, currKey)) || t.less(currKey, prevKey) {
+				return fmt.Errorf("traversal error: %w at node: %v", ErrOrderViolation, node.key)
 			}
 		}
 
@@ -376,16 +666,16 @@ func (t *Tree[K, V, M]) IsTreeValid() error {
 		leftChild := node == node.parent.left && node != node.parent.right
 		rightChild := node != node.parent.left && node == node.parent.right
 		if !parentNil && !(leftChild || rightChild) {
-			err = fmt.Errorf("traversal error: parent/child mismatch for node: %v", node.key)
-			return false
+			return fmt.Errorf("traversal error: %w for node: %v", ErrParentChildMismatch, node.key)
 		}
 
-		return true
+		// check subtree size
+		if !t.IsNil(node) && node.size != t.SubtreeSize(node.left)+t.SubtreeSize(node.right)+1 {
+			return fmt.Errorf("traversal error: %w for node: %v", ErrSubtreeSizeMismatch, node.key)
+		}
+
+		return nil
 	})
-	if err != nil {
-		return err
-	}
-	return nil
 }
 
 // Key returns the key of the given node n.
@@ -393,6 +683,36 @@ func (t *Tree[K, V, M]) Key(n *Node[K, V, M]) K {
 	return n.key
 }
 
+// KthLargest returns the node holding the k-th largest key in the tree, where k is one-based
+// (KthLargest(1) is the node with the maximum key).
+//
+// This is equivalent to Tree.Select(Tree.Size() - k), and runs in O(h) time.
+//
+// Returns:
+//   - (*Node[K, V, M], true) if 1 ≤ k ≤ Tree.Size.
+//   - (t.nil, false) if k is out of range.
+func (t *Tree[K, V, M]) KthLargest(k int) (*Node[K, V, M], bool) {
+	if k < 1 {
+		return t.nil, false
+	}
+	return t.Select(t.Size() - k)
+}
+
+// KthSmallest returns the node holding the k-th smallest key in the tree, where k is one-based
+// (KthSmallest(1) is the node with the minimum key).
+//
+// This is equivalent to Tree.Select(k - 1), and runs in O(h) time.
+//
+// Returns:
+//   - (*Node[K, V, M], true) if 1 ≤ k ≤ Tree.Size.
+//   - (t.nil, false) if k is out of range.
+func (t *Tree[K, V, M]) KthSmallest(k int) (*Node[K, V, M], bool) {
+	if k < 1 {
+		return t.nil, false
+	}
+	return t.Select(k - 1)
+}
+
 // Left returns the left child of the given node n.
 //
 // If the node has no left child, it returns the tree's sentinel nil node.
@@ -400,11 +720,68 @@ func (t *Tree[K, V, M]) Left(n *Node[K, V, M]) *Node[K, V, M] {
 	return n.left
 }
 
+// Less reports whether key a is ordered before key b in the tree.
+//
+// This is the tree's LessFunc, unless the tree was created with WithReverseOrder, in which case
+// the order is reversed. Trees extending bst.Tree should compare keys with Less rather than with
+// the LessFunc they passed to New, so that they honor the tree's options.
+func (t *Tree[K, V, M]) Less(a, b K) bool {
+	return t.less(a, b)
+}
+
+// Duplicates reports whether the tree is in multiset mode, allowing several nodes with equal keys
+// (see WithDuplicateKeys).
+func (t *Tree[K, V, M]) Duplicates() bool {
+	return t.duplicates
+}
+
+// LowestCommonAncestor returns the deepest node that has both a and b as descendants,
+// where a node is considered a descendant of itself.
+//
+// The ancestor is found by walking parent pointers, in O(h) time.
+//
+// If either node is the sentinel nil node, the sentinel nil node is returned.
+//
+// ⚠️ Important: This function does not validate whether a and b actually belong to the tree.
+// Calling it on an arbitrary node could lead to undefined behavior. See Tree.Contains.
+func (t *Tree[K, V, M]) LowestCommonAncestor(a, b *Node[K, V, M]) *Node[K, V, M] {
+	if t.IsNil(a) || t.IsNil(b) {
+		return t.nil
+	}
+
+	// bring both nodes to the same depth
+	da, db := t.Depth(a), t.Depth(b)
+	for ; da > db; da-- {
+		a = a.parent
+	}
+	for ; db > da; db-- {
+		b = b.parent
+	}
+
+	// walk up in lock-step until the paths meet
+	for a != b {
+		a, b = a.parent, b.parent
+	}
+	return a
+}
+
 // Max returns the node with the maximum key in the subtree rooted at n.
 //
 // This function traverses to the rightmost node of the subtree.
 // If n is nil or the subtree is empty, it returns n.
+//
+// The maximum of the whole tree is cached, so Max(Root()) is an O(1) operation. The cache is kept
+// up to date by the methods changing the tree, and never written by Max, so Max is safe for concurrent
+// use by readers.
 func (t *Tree[K, V, M]) Max(n *Node[K, V, M]) *Node[K, V, M] {
+	if n == t.root && t.last != nil {
+		return t.last
+	}
+	return t.max(n)
+}
+
+// max returns the rightmost node of the subtree rooted at n, without using the cache.
+func (t *Tree[K, V, M]) max(n *Node[K, V, M]) *Node[K, V, M] {
 	for !t.IsNil(n.right) {
 		n = n.right
 	}
@@ -424,7 +801,19 @@ func (t *Tree[K, V, M]) Metadata(n *Node[K, V, M]) M {
 //
 // This function traverses to the leftmost node of the subtree.
 // If n is nil or the subtree is empty, it returns n.
+//
+// The minimum of the whole tree is cached, so Min(Root()) is an O(1) operation. The cache is kept
+// up to date by the methods changing the tree, and never written by Min, so Min is safe for concurrent
+// use by readers.
 func (t *Tree[K, V, M]) Min(n *Node[K, V, M]) *Node[K, V, M] {
+	if n == t.root && t.first != nil {
+		return t.first
+	}
+	return t.min(n)
+}
+
+// min returns the leftmost node of the subtree rooted at n, without using the cache.
+func (t *Tree[K, V, M]) min(n *Node[K, V, M]) *Node[K, V, M] {
 	for !t.IsNil(n.left) {
 		n = n.left
 	}
@@ -444,6 +833,32 @@ func (t *Tree[K, V, M]) MustSetMetadata(n *Node[K, V, M], metadata M) {
 	n.metadata = metadata
 }
 
+// Nearest finds the node whose key is closest to key.
+//
+// The candidates are the floor (largest key ≤ key) and ceiling (smallest key ≥ key) of key.
+// The distance function is used to decide between them; it must return a non-negative
+// distance between its two arguments. If both candidates are equally distant, the floor is returned.
+//
+// Returns:
+//   - (*Node[K, V, M], true) if the tree is not empty.
+//   - (t.nil, false) if the tree is empty.
+func (t *Tree[K, V, M]) Nearest(key K, distance func(a, b K) int) (*Node[K, V, M], bool) {
+	floor, hasFloor := t.Floor(key)
+	ceiling, hasCeiling := t.Ceiling(key)
+	switch {
+	case hasFloor && hasCeiling:
+		if distance(ceiling.key, key) < distance(floor.key, key) {
+			return ceiling, true
+		}
+		return floor, true
+	case hasFloor:
+		return floor, true
+	case hasCeiling:
+		return ceiling, true
+	}
+	return t.nil, false
+}
+
 // Parent returns the parent of the given node n.
 //
 // If n is the root, it returns the tree's sentinel nil node.
@@ -451,6 +866,25 @@ func (t *Tree[K, V, M]) Parent(n *Node[K, V, M]) *Node[K, V, M] {
 	return n.parent
 }
 
+// Path returns the nodes on the path from the root to n, inclusive of both.
+//
+// The first element is the root and the last element is n. The length of the path is
+// Tree.Depth(n) + 1. If n is the sentinel nil node, nil is returned.
+//
+// ⚠️ Important: This function does not validate whether node actually belongs to the tree.
+// Calling it on an arbitrary node could lead to undefined behavior. See Tree.Contains.
+func (t *Tree[K, V, M]) Path(n *Node[K, V, M]) []*Node[K, V, M] {
+	if t.IsNil(n) {
+		return nil
+	}
+	path := make([]*Node[K, V, M], t.Depth(n)+1)
+	for i := len(path) - 1; i >= 0; i-- {
+		path[i] = n
+		n = n.parent
+	}
+	return path
+}
+
 // Predecessor returns the in-order predecessor of the given node n.
 //
 // The predecessor is the largest node in n's left subtree.
@@ -468,6 +902,141 @@ func (t *Tree[K, V, M]) Predecessor(n *Node[K, V, M]) *Node[K, V, M] {
 	return p
 }
 
+// RangeDelete removes every node whose key falls within the half-open interval [lo, hi).
+//
+// Nodes are removed in ascending key order using Tree.Delete.
+// If hi is not greater than lo, no nodes are removed.
+//
+// Returns:
+//   - The number of nodes removed from the tree.
+func (t *Tree[K, V, M]) RangeDelete(lo, hi K) int {
+	count := 0
+	n, found := t.Ceiling(lo)
+	for found && t.less(n.key, hi) {
+		next := t.Successor(n)
+		t.Delete(n)
+		count++
+		n, found = next, !t.IsNil(next)
+	}
+	return count
+}
+
+// Rank returns the number of nodes in the tree with a key strictly less than key.
+//
+// The key does not need to be present in the tree. If it is, Rank returns its
+// zero-based position in the in-order sequence of keys.
+//
+// Rank uses the subtree sizes maintained by the tree and runs in O(h) time,
+// where h is the tree height.
+func (t *Tree[K, V, M]) Rank(key K) int {
+	rank := 0
+	n := t.root
+	for !t.IsNil(n) {
+		if t.less(n.key, key) {
+			// n and its entire left subtree are less than key
+			rank += n.left.size + 1
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	return rank
+}
+
+// RebuildSubtree rebuilds the subtree rooted at n into a perfectly balanced shape, in O(m) time
+// where m is the size of the subtree, and returns the new root of the subtree.
+//
+// The in-order sequence of nodes is unchanged, and nodes are relinked rather than copied,
+// so existing node handles remain valid. The augmented data of the rebuilt subtree and of
+// its ancestors is recomputed.
+//
+// This is the partial rebuild primitive used by weight-balanced trees such as scapegoat trees
+// (see scapegoat.Tree). It can also be used to rebalance a degenerate bst.Tree by rebuilding
+// from the root.
+//
+// ⚠️ Warning: Rebuilding discards any balancing information stored in node metadata
+// (e.g., Red-Black colors), so should only be used by extensions that do not rely on it.
+//
+// If n is nil, has been removed, or belongs to a different tree, it is returned unchanged.
+func (t *Tree[K, V, M]) RebuildSubtree(n *Node[K, V, M]) *Node[K, V, M] {
+	if !n.BelongsTo(t) {
+		return n
+	}
+
+	// collect the subtree's nodes in order
+	nodes := make([]*Node[K, V, M], 0, n.size)
+	for m := t.Min(n); len(nodes) < cap(nodes); m = t.Successor(m) {
+		nodes = append(nodes, m)
+	}
+
+	// rebuild, then attach the new subtree root in place of n
+	parent := n.parent
+	wasLeft := parent != t.nil && parent.left == n
+	root := t.buildBalanced(nodes, parent)
+	switch {
+	case parent == t.nil:
+		t.root = root
+	case wasLeft:
+		parent.left = root
+	default:
+		parent.right = root
+	}
+	t.RefreshPath(parent)
+	return root
+}
+
+// RefreshPath recomputes the augmented data of node n and each of its ancestors, up to the root.
+//
+// Augmented data consists of the subtree size, and anything maintained by the
+// function registered with Tree.SetAugmentFunc.
+//
+// Tree.Insert, Tree.Delete, Tree.RotateLeft and Tree.RotateRight keep augmented data up to date
+// automatically. Extensions that relink nodes manually (via Tree.SetLeft, Tree.SetRight,
+// Tree.SetParent, Tree.SetRoot or Tree.Transplant) must call RefreshPath on the lowest
+// node whose subtree has changed, once the relinking is complete.
+//
+// This function is intended to be used only when extending bst.Tree.
+func (t *Tree[K, V, M]) RefreshPath(n *Node[K, V, M]) {
+	for !t.IsNil(n) {
+		t.augment(n)
+		n = n.parent
+	}
+}
+
+// Release marks node n as removed from the tree, so that it no longer belongs to it (see Node.BelongsTo).
+//
+// The node's links are cleared, so that a stale handle cannot be used to reach into the tree.
+// Tree.Delete releases deleted nodes automatically. Extensions that remove nodes by relinking them
+// manually must call Release once the node has been unlinked, and must not use the node afterwards,
+// as it may be recycled (see WithNodePool and WithAllocator). Releasing the node with the
+// minimum or maximum key recomputes the cached minimum and maximum (see Tree.Min and Tree.Max),
+// so the rest of the tree must be fully relinked by then.
+//
+// This function is intended to be used only when extending bst.Tree.
+func (t *Tree[K, V, M]) Release(n *Node[K, V, M]) {
+	if !n.BelongsTo(t) {
+		return
+	}
+	key, value := n.key, n.value
+	t.snapDelete(key, n)
+	t.release(n)
+	t.notify(ChangeDelete, key, value)
+}
+
+// release implements Tree.Release for a node of the tree, without notifying the ChangeFunc.
+//
+// n must already be unlinked, so that the cached minimum and maximum can be recomputed if n was one of them.
+func (t *Tree[K, V, M]) release(n *Node[K, V, M]) {
+	n.parent, n.left, n.right = nil, nil, nil
+	if n == t.first || n == t.last {
+		t.refreshBounds()
+	}
+	n.size = 0
+	n.tree = nil
+	n.gen++ // invalidate the handles to n, which may be recycled
+	t.recycle(n)
+}
+
 // Right returns the right child of the given node n.
 //
 // If the node has no right child, it returns the tree's sentinel nil node.
@@ -497,11 +1066,10 @@ func (t *Tree[K, V, M]) Root() *Node[K, V, M] {
 // Preconditions:
 //   - The given node must have a non-nil right child (node.right != nil).
 //
-// ⚠️ Important: This function does not validate whether node actually belongs to the tree.
-// Calling it on an arbitrary node could lead to undefined behavior.
+// If node is nil, has no right child, has been removed, or belongs to a different tree, no rotation is performed.
 func (t *Tree[K, V, M]) RotateLeft(node *Node[K, V, M]) {
-	if node == nil || node == t.nil || node.right == t.nil {
-		return // No rotation possible if node is nil or has no right child
+	if !node.BelongsTo(t) || node.right == t.nil {
+		return // No rotation possible if node is nil, stale, foreign or has no right child
 	}
 
 	rightSubtree := node.right
@@ -520,6 +1088,11 @@ func (t *Tree[K, V, M]) RotateLeft(node *Node[K, V, M]) {
 	}
 
 	rightSubtree.left, node.parent = node, rightSubtree
+	t.rotations++
+
+	// node is now the child of rightSubtree, so update augmented data bottom-up
+	t.augment(node)
+	t.augment(rightSubtree)
 }
 
 // RotateRight performs a right rotation on the given node within the tree.
@@ -537,11 +1110,10 @@ func (t *Tree[K, V, M]) RotateLeft(node *Node[K, V, M]) {
 // Preconditions:
 //   - The given node must have a non-nil left child (node.left != nil).
 //
-// ⚠️ Important: This function does not validate whether node actually belongs to the tree.
-// Calling it on an arbitrary node could lead to undefined behavior.
+// If node is nil, has no left child, has been removed, or belongs to a different tree, no rotation is performed.
 func (t *Tree[K, V, M]) RotateRight(node *Node[K, V, M]) {
-	if node == nil || node == t.nil || node.left == t.nil {
-		return // No rotation possible if node is nil or has no left child
+	if !node.BelongsTo(t) || node.left == t.nil {
+		return // No rotation possible if node is nil, stale, foreign or has no left child
 	}
 
 	leftSubtree := node.left
@@ -560,6 +1132,20 @@ func (t *Tree[K, V, M]) RotateRight(node *Node[K, V, M]) {
 	}
 
 	leftSubtree.right, node.parent = node, leftSubtree
+	t.rotations++
+
+	// node is now the child of leftSubtree, so update augmented data bottom-up
+	t.augment(node)
+	t.augment(leftSubtree)
+}
+
+// Rotations returns the number of rotations performed on the tree since it was created, by
+// Tree.RotateLeft and Tree.RotateRight, whether directly or while rebalancing the tree.
+//
+// The count can be used to monitor the rebalancing work of self-balancing trees (e.g., rbtree.Tree).
+// This is an O(1) operation.
+func (t *Tree[K, V, M]) Rotations() uint64 {
+	return t.rotations
 }
 
 // Search looks for a node with the given key in the tree.
@@ -571,11 +1157,20 @@ func (t *Tree[K, V, M]) RotateRight(node *Node[K, V, M]) {
 //
 // If the key is found, the corresponding node is returned along with true.
 // If the key is not found, the tree's sentinel nil node is returned with false.
+// In multiset mode, the first node (in order) with an equal key is returned.
 //
 // Returns:
 //   - (*Node[K, V, M], true) if the key exists in the tree.
 //   - (*Node[K, V, M], false) if the key is not found.
 func (t *Tree[K, V, M]) Search(key K) (*Node[K, V, M], bool) {
+	if t.duplicates {
+		n, found := t.Ceiling(key)
+		if found && t.keysEqual(n.key, key) {
+			return n, true
+		}
+		return t.nil, false
+	}
+
 	currNode := t.root
 
 	// if we arrive at a nil node, then node is not in tree
@@ -596,29 +1191,116 @@ func (t *Tree[K, V, M]) Search(key K) (*Node[K, V, M], bool) {
 	return t.nil, false
 }
 
-// Sentinel return the sentinel nil node.
+// Select returns the node with the given zero-based rank i, that is, the (i+1)-th smallest key in the tree.
+//
+// Select uses the subtree sizes maintained by the tree and runs in O(h) time,
+// where h is the tree height.
+//
+// Returns:
+//   - (*Node[K, V, M], true) if 0 ≤ i < Tree.Size.
+//   - (t.nil, false) if i is out of range.
+func (t *Tree[K, V, M]) Select(i int) (*Node[K, V, M], bool) {
+	if i < 0 || i >= t.root.size {
+		return t.nil, false
+	}
+	n := t.root
+	for {
+		leftSize := n.left.size
+		if i < leftSize {
+			n = n.left
+		} else if i == leftSize {
+			return n, true
+		} else {
+			i -= leftSize + 1
+			n = n.right
+		}
+	}
+}
+
+// Sentinel returns the sentinel nil node.
+//
+// The sentinel stands for every absent child, and is the parent of the root. It is shared by the whole
+// tree, so its links must never be changed: its parent is always itself, and it is never passed to
+// Tree.SetLeft, Tree.SetRight or Tree.SetParent as the node to modify. It may be passed as the new
+// child or parent, to unlink a node.
 func (t *Tree[K, V, M]) Sentinel() *Node[K, V, M] {
 	return t.nil
 }
 
+// SetAugmentFunc registers f to maintain user-defined augmented data, such as subtree sums,
+// maximum interval endpoints or other aggregates, typically stored in node metadata.
+//
+// f is invoked on a node whenever its subtree changes, after the node's children (and their
+// augmented data) are up to date. This happens bottom-up along the affected path after
+// Tree.Insert, Tree.Delete and Tree.SetValue, and on both nodes involved in Tree.RotateLeft and
+// Tree.RotateRight. f should therefore recompute the node's aggregate from its own key, value
+// and the aggregates of its children (which may be the sentinel nil node).
+//
+// Registering f immediately recomputes the augmented data of every node in the tree, in O(n) time.
+// Passing nil removes the registered function.
+//
+// Example, maintaining the sum of all keys in each subtree as metadata:
+//
+//	tree := New[int, struct{}, int](func(a, b int) bool { return a < b })
+//	tree.SetAugmentFunc(func(n *Node[int, struct{}, int]) {
+//		tree.SetMetadata(n, tree.Key(n)+tree.Metadata(tree.Left(n))+tree.Metadata(tree.Right(n)))
+//	})
+func (t *Tree[K, V, M]) SetAugmentFunc(f AugmentFunc[K, V, M]) {
+	t.augmentFunc = f
+	t.augmentSubtree(t.root)
+}
+
+// SetNodeFormatter registers f to format each node when drawing the tree with Tree.String
+// and Tree.WriteSVG, instead of the default layout (see Node.String).
+//
+// This is useful to show only the relevant fields of large values, or to hide metadata
+// that carries no information (such as struct{}). Passing nil restores the default layout.
+//
+// Example, showing only the keys and values of a tree without metadata:
+//
+//	tree := New[int, string, struct{}](func(a, b int) bool { return a < b })
+//	tree.SetNodeFormatter(func(k int, v string, _ struct{}) string {
+//		return fmt.Sprintf("%d=%s", k, v)
+//	})
+func (t *Tree[K, V, M]) SetNodeFormatter(f NodeFormatter[K, V, M]) {
+	t.formatter = f
+}
+
 // SetKey updates the key of the given node **without repositioning it within the tree**.
 //
 // ⚠️ Warning: Changing a node’s key does not update its position, which can violate
 // the BST ordering properties. Use with caution and only when extending bst.Tree.
 //
+// The new key must fall between the keys of the node's in-order predecessor and successor, or the
+// node must be relinked before control returns to the caller. Augmented data is not recomputed: call
+// Tree.RefreshPath if the AugmentFunc depends on keys. To move a node to any key, use Tree.UpdateKey.
+//
+// If n is nil, has been removed, or belongs to a different tree, SetKey does nothing.
+//
 // This function is intended for use in specialized cases, such as custom tree extensions.
 func (t *Tree[K, V, M]) SetKey(n *Node[K, V, M], key K) {
+	if !n.BelongsTo(t) {
+		return
+	}
 	n.key = key
+	t.snaps.reset() // n may yet be relinked elsewhere, so the next snapshot rebuilds its entries
 }
 
-// SetLeft updates the left child of the given node.
+// SetLeft updates the left child of the given node n to l, which may be the sentinel nil node.
 //
 // ⚠️ Warning: Changing a node’s child manually can violate the BST ordering properties
-// and break tree invariants. Use with caution** and only when extending bst.Tree.
+// and break tree invariants. Use with caution and only when extending bst.Tree.
+//
+// Only n's link is changed: l's parent must be set to n with Tree.SetParent, and subtree sizes
+// recomputed with Tree.RefreshPath, once the relinking is complete. Every key of l's subtree must
+// precede n's key. If either node has been removed or belongs to a different tree, SetLeft does nothing.
 //
 // This function is intended for specialized use cases, such as custom tree extensions
 // or self-balancing tree implementations.
 func (t *Tree[K, V, M]) SetLeft(n, l *Node[K, V, M]) {
+	if n == nil || !t.owns(n) || !t.owns(l) {
+		return
+	}
 	n.left = l
 }
 
@@ -633,42 +1315,99 @@ func (t *Tree[K, V, M]) SetMetadata(n *Node[K, V, M], metadata M) {
 	}
 }
 
-// SetParent updates the left child of the given node.
+// SetParent updates the parent of the given node n to p, which is the sentinel nil node if n becomes the root.
 //
 // ⚠️ Warning: Changing a node’s parent manually can violate the BST ordering properties
 // and break tree invariants. Use with caution and only when extending bst.Tree.
 //
+// Only n's link is changed: n must also be made a child of p with Tree.SetLeft or Tree.SetRight
+// (or the root with Tree.SetRoot). n must not be the sentinel nil node, whose parent is always itself.
+// If either node has been removed or belongs to a different tree, SetParent does nothing.
+//
 // This function is intended for specialized use cases, such as custom tree extensions
 // or self-balancing tree implementations.
 func (t *Tree[K, V, M]) SetParent(n, p *Node[K, V, M]) {
+	if n == nil || !t.owns(n) || !t.owns(p) {
+		return
+	}
 	n.parent = p
 }
 
-// SetRight updates the left child of the given node.
+// SetRight updates the right child of the given node n to r, which may be the sentinel nil node.
 //
 // ⚠️ Warning: Changing a node’s child manually can violate the BST ordering properties
 // and break tree invariants. Use with caution and only when extending bst.Tree.
 //
+// Only n's link is changed: r's parent must be set to n with Tree.SetParent, and subtree sizes
+// recomputed with Tree.RefreshPath, once the relinking is complete. Every key of r's subtree must
+// follow n's key. If either node has been removed or belongs to a different tree, SetRight does nothing.
+//
 // This function is intended for specialized use cases, such as custom tree extensions
 // or self-balancing tree implementations.
 func (t *Tree[K, V, M]) SetRight(n, r *Node[K, V, M]) {
+	if n == nil || !t.owns(n) || !t.owns(r) {
+		return
+	}
 	n.right = r
 }
 
+// owns reports whether n is a node of the tree, or nil or its sentinel nil node, so that the relinking
+// methods may link it.
+func (t *Tree[K, V, M]) owns(n *Node[K, V, M]) bool {
+	return t.IsNil(n) || n.BelongsTo(t)
+}
+
 // SetRoot updates the root of the tree to the given node.
 //
 // ⚠️ Warning: Changing the tree's root manually can violate the BST ordering properties,
 // potentially cause data loss, and break tree invariants. Use with caution and only when extending bst.Tree.
 //
+// The parent of n must be the sentinel nil node (see Tree.SetParent), and every node of the tree must be
+// reachable from n. Nodes no longer reachable must be passed to Tree.Release. The cached minimum and
+// maximum (see Tree.Min) are recomputed from n, in O(h) time, so SetRoot may also be used, once the
+// tree is fully linked, when nodes have been added or removed below the root by relinking.
+//
 // This function is intended for specialized use cases, such as custom tree extensions
 // or self-balancing tree implementations.
 func (t *Tree[K, V, M]) SetRoot(n *Node[K, V, M]) {
 	t.root = n
+	t.refreshBounds()
+}
+
+// refreshBounds recomputes the cached minimum and maximum nodes of the tree from its root, in O(h) time.
+//
+// It must be called whenever nodes are added to or removed from the tree other than by Tree.Insert
+// and Tree.Release, once the tree is fully linked. Relinking nodes, as rotations, rebuilds and extensions
+// using Tree.SetLeft, Tree.SetRight and Tree.SetRoot do, does not change the in-order sequence of nodes,
+// so leaves the cache valid. The cache is only ever written by methods changing the tree, so that
+// Tree.Min and Tree.Max can be called concurrently by readers.
+func (t *Tree[K, V, M]) refreshBounds() {
+	t.first, t.last = t.min(t.root), t.max(t.root)
 }
 
 // SetValue updates the value of the given node n.
+//
+// If an AugmentFunc has been registered (see Tree.SetAugmentFunc), the augmented data
+// of n and its ancestors is recomputed, as it may depend on the value. This does not happen
+// when SetValue is called from within the AugmentFunc itself, allowing aggregates to be stored in values.
+//
+// The function registered with Tree.OnChange, if any, is notified of the update, unless SetValue is
+// called from within the AugmentFunc.
+//
+// If n is nil, has been removed, or belongs to a different tree, SetValue does nothing.
 func (t *Tree[K, V, M]) SetValue(n *Node[K, V, M], value V) {
+	if !n.BelongsTo(t) {
+		return
+	}
 	n.value = value
+	t.snapUpdate(n.key, n)
+	if t.augmenting {
+		return
+	}
+	if t.augmentFunc != nil {
+		t.RefreshPath(n)
+	}
+	t.notify(ChangeUpdate, n.key, value)
 }
 
 // Sibling returns the sibling of the given node n.
@@ -692,6 +1431,13 @@ func (t *Tree[K, V, M]) Sibling(n *Node[K, V, M]) *Node[K, V, M] {
 	return n.parent.left
 }
 
+// Size returns the total number of nodes in the tree.
+//
+// This is an O(1) operation, as subtree sizes are maintained during insertions and deletions.
+func (t *Tree[K, V, M]) Size() int {
+	return t.root.size
+}
+
 // String returns a visual representation of the binary search tree (BST).
 //
 // The tree is displayed in a structured format, resembling its actual shape.
@@ -700,80 +1446,27 @@ func (t *Tree[K, V, M]) Sibling(n *Node[K, V, M]) *Node[K, V, M] {
 //
 // The tree is ordered in ascending order, with the minimum node on the first line.
 //
-// The nodes are printed using the Node.String method.
+// The nodes are printed using the function registered with Tree.SetNodeFormatter,
+// or the Node.String method if no function is registered.
 //
 // If the tree is empty, the function returns "Empty Tree".
 //
 // Returns:
 //   - A formatted string representing the BST structure.
 //
-// This function uses an in-order iterator to traverse the tree and builds
-// the output using a string builder. It tracks vertical lines dynamically
-// to create a structured visualization of the BST.
+// String is equivalent to calling Tree.Render with the default RenderOptions.
 func (t *Tree[K, V, M]) String() string {
+	return t.Render(RenderOptions{})
+}
 
-	// if tree is empty, return early
-	if t.root == t.nil {
-		return "Empty Tree"
+// SubtreeSize returns the number of nodes in the subtree rooted at n, including n itself.
+//
+// If n is the sentinel nil node, 0 is returned.
+func (t *Tree[K, V, M]) SubtreeSize(n *Node[K, V, M]) int {
+	if t.IsNil(n) {
+		return 0
 	}
-
-	// prepare string builder
-	builder := strings.Builder{}
-
-	// prepare map to hold which levels to draw vertical lines
-	verticalLineHeights := make(map[int]bool)
-
-	// ascend the tree. for each node:
-	t.TraverseInOrder(t.root, func(node *Node[K, V, M]) bool {
-		// get height of node
-		h := t.Depth(node)
-
-		// if we are at a height that needs a vertical line, draw it,
-		// otherwise draw a space
-		for j := 0; j < h-1; j++ {
-			if verticalLineHeights[j+1] {
-				builder.WriteString(connectorVertical)
-			} else {
-				builder.WriteString(connectorSpace)
-			}
-		}
-
-		// draw "connector" based on node orientation
-		if node.parent != t.nil && node.parent.left == node {
-			builder.WriteString(connectorLeft)
-		} else if node.parent != t.nil && node.parent.right == node {
-			builder.WriteString(connectorRight)
-		}
-
-		// print node key
-		builder.WriteString(node.String())
-		builder.WriteString("\n")
-
-		// turn on/off vertical lines
-
-		// if node parent is in the "right" direction ("down" in this representation),
-		// turn on vertical lines for this height.
-		if node.parent != t.nil && node.parent.left == node {
-			verticalLineHeights[h] = true
-		}
-		// if node parent is in "left" direction ("up" in this representation),
-		// turn off vertical lines for this height.
-		if node.parent != t.nil && node.parent.right == node {
-			verticalLineHeights[h] = false
-		}
-		// if node has right child ("down in this representation),
-		// turn on vertical lines for the next height (h+1).
-		if node.right != t.nil {
-			verticalLineHeights[h+1] = true
-		} else {
-			verticalLineHeights[h+1] = false
-		}
-
-		return true
-	})
-
-	// return the tree
-	return builder.String()
+	return n.size
 }
 
 // Successor returns the in-order successor of the given node n.
@@ -806,8 +1499,17 @@ func (t *Tree[K, V, M]) Successor(n *Node[K, V, M]) *Node[K, V, M] {
 // structure by replacing one subtree with another. Use with caution, and only when
 // implementing advanced tree operations (e.g., deletion, balancing).
 //
+// The link from toReplace's parent (or the root) is set to replacement, and replacement's parent to
+// toReplace's parent, unless replacement is the sentinel nil node. toReplace's own links are left
+// unchanged, so it must then be relinked elsewhere or passed to Tree.Release, and subtree sizes
+// recomputed with Tree.RefreshPath from toReplace's former parent. If toReplace is not a node of the
+// tree, or replacement is neither a node of the tree nor the sentinel nil node, Transplant does nothing.
+//
 // This function is intended for specialized use cases, such as Red-Black Tree fixup operations.
 func (t *Tree[K, V, M]) Transplant(toReplace, replacement *Node[K, V, M]) {
+	if !toReplace.BelongsTo(t) || !t.owns(replacement) {
+		return
+	}
 
 	// perform transplant
 	if t.IsNil(toReplace.parent) {
@@ -831,6 +1533,10 @@ func (t *Tree[K, V, M]) Transplant(toReplace, replacement *Node[K, V, M]) {
 	if !t.IsNil(replacement) {
 		replacement.parent = toReplace.parent
 	}
+
+	if t.hooks.AfterTransplant != nil {
+		t.hooks.AfterTransplant(toReplace, replacement)
+	}
 }
 
 // TraverseInOrder performs an in-order traversal of the tree starting from node n.
@@ -870,6 +1576,48 @@ func (t *Tree[K, V, M]) TraverseInOrder(n *Node[K, V, M], f TraversalFunc[K, V,
 	return true
 }
 
+// TraverseInOrderErr performs an in-order traversal of the tree starting from node n,
+// stopping at the first error returned by f.
+//
+// TraverseInOrderErr uses recursion, with the same caveats as Tree.TraverseInOrder.
+//
+// The function applies the user-provided function f to each visited node.
+// If f returns ErrStopTraversal, the traversal stops early and nil is returned.
+//
+// Returns:
+//   - nil if the traversal completes successfully, or is stopped with ErrStopTraversal.
+//   - The first other error returned by f.
+func (t *Tree[K, V, M]) TraverseInOrderErr(n *Node[K, V, M], f TraversalErrFunc[K, V, M]) error {
+	err := t.traverseInOrderErr(n, f)
+	if errors.Is(err, ErrStopTraversal) {
+		return nil
+	}
+	return err
+}
+
+// traverseInOrderErr recursively applies f to the subtree rooted at n, in order,
+// returning the first error from f unchanged.
+func (t *Tree[K, V, M]) traverseInOrderErr(n *Node[K, V, M], f TraversalErrFunc[K, V, M]) error {
+
+	// Recurse the left children of n
+	if n.left != nil && n.left != t.nil {
+		if err := t.traverseInOrderErr(n.left, f); err != nil {
+			return err
+		}
+	}
+
+	// Process n
+	if err := f(n); err != nil {
+		return err
+	}
+
+	// Recurse the right children of n
+	if n.right != nil && n.right != t.nil {
+		return t.traverseInOrderErr(n.right, f)
+	}
+	return nil
+}
+
 // Value returns the value associated with the given node n.
 //
 // This function retrieves the stored value for the node's key.
@@ -877,6 +1625,98 @@ func (t *Tree[K, V, M]) Value(n *Node[K, V, M]) V {
 	return n.value
 }
 
+// rankAfter returns the number of nodes in the tree with a key less than or equal to key.
+func (t *Tree[K, V, M]) rankAfter(key K) int {
+	rank := 0
+	n := t.root
+	for !t.IsNil(n) {
+		if !t.less(key, n.key) {
+			// n and its entire left subtree are less than or equal to key
+			rank += n.left.size + 1
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	return rank
+}
+
+// traversePreOrder applies f to every node in the tree in pre-order, without recursion.
+func (t *Tree[K, V, M]) traversePreOrder(f func(n *Node[K, V, M])) {
+	if t.IsNil(t.root) {
+		return
+	}
+	stack := []*Node[K, V, M]{t.root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		f(n)
+		if !t.IsNil(n.right) {
+			stack = append(stack, n.right)
+		}
+		if !t.IsNil(n.left) {
+			stack = append(stack, n.left)
+		}
+	}
+}
+
+// releaseSubtree releases every node in the subtree rooted at n (see Tree.Release), without recursion.
+func (t *Tree[K, V, M]) releaseSubtree(n *Node[K, V, M]) {
+	if t.IsNil(n) {
+		return
+	}
+	stack := []*Node[K, V, M]{n}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !t.IsNil(n.left) {
+			stack = append(stack, n.left)
+		}
+		if !t.IsNil(n.right) {
+			stack = append(stack, n.right)
+		}
+		t.release(n)
+	}
+}
+
+// buildBalanced links the in-order sequence nodes into a perfectly balanced subtree with the given parent,
+// recomputing augmented data bottom-up, and returns the root of the subtree.
+//
+// The recursion depth is O(log m), where m is the number of nodes.
+func (t *Tree[K, V, M]) buildBalanced(nodes []*Node[K, V, M], parent *Node[K, V, M]) *Node[K, V, M] {
+	if len(nodes) == 0 {
+		return t.nil
+	}
+	mid := len(nodes) / 2
+	n := nodes[mid]
+	n.parent = parent
+	n.left = t.buildBalanced(nodes[:mid], n)
+	n.right = t.buildBalanced(nodes[mid+1:], n)
+	t.augment(n)
+	return n
+}
+
+// augmentSubtree recomputes the augmented data of every node in the subtree rooted at n, in post-order.
+func (t *Tree[K, V, M]) augmentSubtree(n *Node[K, V, M]) {
+	if t.IsNil(n) {
+		return
+	}
+	t.augmentSubtree(n.left)
+	t.augmentSubtree(n.right)
+	t.augment(n)
+}
+
+// augment recomputes the subtree size of n from the sizes of its children,
+// then invokes the registered AugmentFunc (if any) on n.
+func (t *Tree[K, V, M]) augment(n *Node[K, V, M]) {
+	n.size = n.left.size + n.right.size + 1
+	if t.augmentFunc != nil {
+		t.augmenting = true
+		t.augmentFunc(n)
+		t.augmenting = false
+	}
+}
+
 // keysEqual determines if two keys are equal by using the less function.
 //
 // Two keys are considered equal if neither is less than the other.
@@ -901,7 +1741,8 @@ func (t *Tree[K, V, M]) Floor(key K) (*Node[K, V, M], bool) {
 
 	for !t.IsNil(current) {
 		// If current key equals the search key, we found an exact match
-		if t.keysEqual(current.key, key) {
+		// (in multiset mode, keep searching right for the last equal key)
+		if !t.duplicates && t.keysEqual(current.key, key) {
 			return current, true
 		}
 
@@ -939,7 +1780,8 @@ func (t *Tree[K, V, M]) Ceiling(key K) (*Node[K, V, M], bool) {
 
 	for !t.IsNil(current) {
 		// If current key equals the search key, we found an exact match
-		if t.keysEqual(current.key, key) {
+		// (in multiset mode, keep searching left for the first equal key)
+		if !t.duplicates && t.keysEqual(current.key, key) {
 			return current, true
 		}
 
@@ -961,3 +1803,57 @@ func (t *Tree[K, V, M]) Ceiling(key K) (*Node[K, V, M], bool) {
 
 	return t.nil, false
 }
+
+// Lower finds the largest key in the tree strictly less than key, as NavigableMap.lowerKey does in Java.
+//
+// Unlike Tree.Floor, a node holding key itself is never returned, so exclusive bounds need no adjustment
+// of the key. In multiset mode, the last of several nodes holding the lower key is returned.
+//
+// Example Usage:
+//
+//	// the last node before the half-open interval [lo, hi)
+//	n, found := tree.Lower(lo)
+//
+// Returns:
+//   - (*Node[K, V, M], true) if a key < key exists in the tree.
+//   - (nil, false) if no such key exists.
+func (t *Tree[K, V, M]) Lower(key K) (*Node[K, V, M], bool) {
+	lower := t.nil
+	for current := t.root; !t.IsNil(current); {
+		if t.less(current.key, key) {
+			// a potential lower node: continue searching right for larger keys
+			lower = current
+			current = current.right
+		} else {
+			current = current.left
+		}
+	}
+	return lower, !t.IsNil(lower)
+}
+
+// Higher finds the smallest key in the tree strictly greater than key, as NavigableMap.higherKey does in Java.
+//
+// Unlike Tree.Ceiling, a node holding key itself is never returned, so exclusive bounds need no adjustment
+// of the key. In multiset mode, the first of several nodes holding the higher key is returned.
+//
+// Example Usage:
+//
+//	// the first node after the closed interval [lo, hi]
+//	n, found := tree.Higher(hi)
+//
+// Returns:
+//   - (*Node[K, V, M], true) if a key > key exists in the tree.
+//   - (nil, false) if no such key exists.
+func (t *Tree[K, V, M]) Higher(key K) (*Node[K, V, M], bool) {
+	higher := t.nil
+	for current := t.root; !t.IsNil(current); {
+		if t.less(key, current.key) {
+			// a potential higher node: continue searching left for smaller keys
+			higher = current
+			current = current.left
+		} else {
+			current = current.right
+		}
+	}
+	return higher, !t.IsNil(higher)
+}
@@ -1,8 +1,15 @@
 package bst
 
 import (
+	"errors"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -15,6 +22,25 @@ func TestNew(t *testing.T) {
 	assert.True(t, tree.IsNil(tree.Parent(tree.Root())), "expected tree root to have nil parent")
 }
 
+func TestNewOrdered(t *testing.T) {
+	tree := NewOrdered[string, int, struct{}]()
+	for i, key := range []string{"b", "c", "a"} {
+		tree.Insert(key, i)
+	}
+	assert.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	assert.Equal(t, "a", tree.Key(tree.Min(tree.Root())))
+	assert.Equal(t, "c", tree.Key(tree.Max(tree.Root())))
+
+	// options are passed on, and NaN keys are ordered first
+	floats := NewOrdered[float64, struct{}, struct{}](WithDuplicateKeys())
+	for _, key := range []float64{1, math.NaN(), 1, -1} {
+		floats.Insert(key, struct{}{})
+	}
+	assert.NoError(t, floats.IsTreeValid(), "expected valid tree")
+	assert.Equal(t, 2, floats.Count(1))
+	assert.True(t, math.IsNaN(floats.Key(floats.Min(floats.Root()))), "expected NaN to be the smallest key")
+}
+
 func TestTree_Insert(t *testing.T) {
 	tree := New[int, int, int](func(a, b int) bool {
 		return a < b
@@ -202,6 +228,96 @@ func TestTree_Max(t *testing.T) {
 	assert.Equal(t, 65, tree.Key(n), "unexpected minimum from node 50")
 }
 
+// checkBounds checks that the cached minimum and maximum of the tree are up to date.
+func checkBounds[K, V, M any](t *testing.T, tree *Tree[K, V, M], op string) {
+	t.Helper()
+	assert.Equal(t, tree.min(tree.root), tree.first, "%s: stale cached minimum", op)
+	assert.Equal(t, tree.max(tree.root), tree.last, "%s: stale cached maximum", op)
+}
+
+func TestTree_MinMax_cache(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	tree := New[int, int, struct{}](less)
+	checkBounds(t, tree, "New")
+	for _, key := range []int{50, 30, 70, 20, 40, 60, 80, 10, 90} {
+		tree.Insert(key, key)
+		checkBounds(t, tree, "Insert")
+	}
+	tree.PopMin()
+	checkBounds(t, tree, "PopMin")
+	tree.PopMax()
+	checkBounds(t, tree, "PopMax")
+	tree.RotateLeft(tree.Root())
+	checkBounds(t, tree, "RotateLeft")
+	tree.Rebalance()
+	checkBounds(t, tree, "Rebalance")
+	n, _ := tree.Search(20)
+	tree.UpdateKey(n, 95)
+	checkBounds(t, tree, "UpdateKey")
+
+	n, _ = tree.Search(30)
+	detached, ok := tree.DetachSubtree(n)
+	require.True(t, ok)
+	checkBounds(t, tree, "DetachSubtree")
+	checkBounds(t, detached, "DetachSubtree (detached)")
+	copied, ok := tree.CopySubtree(tree.Root())
+	require.True(t, ok)
+	checkBounds(t, copied, "CopySubtree")
+	require.NoError(t, tree.AttachSubtree(detached))
+	checkBounds(t, tree, "AttachSubtree")
+
+	data, err := tree.MarshalBinary()
+	require.NoError(t, err)
+	restored := New[int, int, struct{}](less)
+	require.NoError(t, restored.UnmarshalBinary(data))
+	checkBounds(t, restored, "UnmarshalBinary")
+
+	require.NoError(t, tree.LoadSorted([]int{1, 2, 3}, nil, 1))
+	checkBounds(t, tree, "LoadSorted")
+	for !tree.IsNil(tree.Root()) {
+		tree.Delete(tree.Root())
+		checkBounds(t, tree, "Delete")
+	}
+	tree.Insert(1, 1)
+	tree.Clear()
+	checkBounds(t, tree, "Clear")
+
+	// an extension relinking nodes out of the tree, then setting its root
+	for _, key := range []int{2, 1, 3} {
+		tree.Insert(key, key)
+	}
+	root := tree.Root()
+	tree.SetLeft(root, tree.Sentinel())
+	tree.SetRight(root, tree.Sentinel())
+	tree.SetRoot(root)
+	checkBounds(t, tree, "SetRoot")
+	assert.Equal(t, 2, tree.Key(tree.Min(tree.Root())))
+}
+
+func TestTree_MinMax_concurrentReaders(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	for i := range 1000 {
+		tree.Insert(i, i)
+	}
+	tree.PopMin()
+	tree.PopMax()
+
+	// readers must not write to the tree, which the race detector reports
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				assert.Equal(t, 1, tree.Key(tree.Min(tree.Root())))
+				assert.Equal(t, 998, tree.Key(tree.Max(tree.Root())))
+			}
+			tree.Snapshot()
+		}()
+	}
+	wg.Wait()
+}
+
 func TestTree_Delete(t *testing.T) {
 	tests := map[string]struct {
 		creation func() *Tree[int, string, struct{}]
@@ -672,25 +788,31 @@ func TestTree_IsTreeValid(t *testing.T) {
 	// break sentinel node
 	tree := createTree()
 	tree.SetParent(tree.Sentinel(), nil)
-	require.Error(t, tree.IsTreeValid(), "expected sentinel nil parent to return error")
+	require.ErrorIs(t, tree.IsTreeValid(), ErrSentinelAltered, "expected sentinel nil parent to return error")
 
 	// break root node
 	tree = createTree()
 	tree.SetParent(tree.Root(), nil)
-	require.Error(t, tree.IsTreeValid(), "expected root nil parent to return error")
+	require.ErrorIs(t, tree.IsTreeValid(), ErrParentChildMismatch, "expected root nil parent to return error")
 
 	// break tree: out of order node
 	tree = createTree()
 	minNode := tree.Min(tree.Root())
 	tree.SetKey(minNode, 51)
-	require.Error(t, tree.IsTreeValid(), "expected out of order node key to return error")
+	require.ErrorIs(t, tree.IsTreeValid(), ErrOrderViolation, "expected out of order node key to return error")
 
 	// break tree: broken parent/child relationship
 	tree = createTree()
 	brokenNode, _ := tree.Search(75)
 	tree.SetParent(brokenNode, tree.Root())
-	require.Error(t, tree.IsTreeValid(), "expected parent/child mismatch to return error")
+	require.ErrorIs(t, tree.IsTreeValid(), ErrParentChildMismatch, "expected parent/child mismatch to return error")
 
+	// break tree: subtree size
+	tree = createTree()
+	tree.SetRight(tree.Root(), tree.Sentinel())
+	err := tree.IsTreeValid()
+	require.ErrorIs(t, err, ErrSubtreeSizeMismatch, "expected subtree size mismatch to return error")
+	assert.EqualError(t, err, "traversal error: subtree size mismatch for node: 100")
 }
 
 func TestTree_Predecessor(t *testing.T) {
@@ -835,6 +957,14 @@ func TestTree_Height(t *testing.T) {
 	assert.Equal(t, 0, tree.Depth(tree.Root()))
 	assert.Equal(t, 1, tree.Depth(n50))
 	assert.Equal(t, 2, tree.Depth(n25))
+	assert.Equal(t, 2, tree.Height())
+	tree.Insert(75, struct{}{})
+	tree.Insert(60, struct{}{})
+	tree.Insert(65, struct{}{})
+	assert.Equal(t, 4, tree.Height())
+
+	empty := New[int, struct{}, struct{}](func(a, b int) bool { return a < b })
+	assert.Equal(t, -1, empty.Height(), "expected empty tree to have height -1")
 }
 
 func TestTree_Contains(t *testing.T) {
@@ -943,6 +1073,69 @@ func TestTree_Ceiling(t *testing.T) {
 	assert.True(t, tree.IsNil(n), "Ceiling(20) should return nil node")
 }
 
+func TestTree_LowerHigher(t *testing.T) {
+	tree := New[int, string, struct{}](func(a, b int) bool {
+		return a < b
+	})
+
+	// Test with empty tree
+	n, found := tree.Lower(5)
+	assert.False(t, found, "Lower in empty tree should return not found")
+	assert.True(t, tree.IsNil(n), "Lower in empty tree should return nil node")
+	n, found = tree.Higher(5)
+	assert.False(t, found, "Higher in empty tree should return not found")
+	assert.True(t, tree.IsNil(n), "Higher in empty tree should return nil node")
+
+	for _, k := range []int{10, 5, 15, 3, 7, 12, 17} {
+		tree.Insert(k, strconv.Itoa(k))
+	}
+	for _, tc := range []struct {
+		key           int
+		lower, higher int // -1 if not found
+	}{
+		{key: 1, lower: -1, higher: 3},
+		{key: 3, lower: -1, higher: 5},
+		{key: 6, lower: 5, higher: 7},
+		{key: 10, lower: 7, higher: 12},
+		{key: 17, lower: 15, higher: -1},
+		{key: 20, lower: 17, higher: -1},
+	} {
+		n, found := tree.Lower(tc.key)
+		assert.Equal(t, tc.lower != -1, found, "unexpected Lower(%d) result", tc.key)
+		if found {
+			assert.Equal(t, tc.lower, tree.Key(n), "unexpected Lower(%d) key", tc.key)
+		}
+		n, found = tree.Higher(tc.key)
+		assert.Equal(t, tc.higher != -1, found, "unexpected Higher(%d) result", tc.key)
+		if found {
+			assert.Equal(t, tc.higher, tree.Key(n), "unexpected Higher(%d) key", tc.key)
+		}
+	}
+}
+
+func TestTree_LowerHigher_duplicates(t *testing.T) {
+	tree := New[int, string, struct{}](func(a, b int) bool {
+		return a < b
+	}, WithDuplicateKeys())
+	for _, kv := range []struct {
+		key   int
+		value string
+	}{{1, "a"}, {2, "b"}, {2, "c"}, {3, "d"}, {3, "e"}, {4, "f"}} {
+		tree.Insert(kv.key, kv.value)
+	}
+
+	// the last of the lower keys, and the first of the higher keys
+	n, found := tree.Lower(3)
+	require.True(t, found)
+	assert.Equal(t, "c", tree.Value(n))
+	n, found = tree.Higher(1)
+	require.True(t, found)
+	assert.Equal(t, "b", tree.Value(n))
+	n, found = tree.Higher(2)
+	require.True(t, found)
+	assert.Equal(t, "d", tree.Value(n))
+}
+
 // TestTree_UncoveredSetMethods tests the uncovered set methods
 func TestTree_UncoveredSetMethods(t *testing.T) {
 	tree := New[int, string, struct{}](func(a, b int) bool {
@@ -976,3 +1169,779 @@ func TestTree_UncoveredSetMethods(t *testing.T) {
 	tree.SetLeft(node, originalLeft)
 	tree.SetRight(node, originalRight)
 }
+
+func TestTree_RangeDelete(t *testing.T) {
+	tests := map[string]struct {
+		lo, hi        int
+		expectedCount int
+		expectedKeys  []int
+	}{
+		"middle range": {
+			lo: 3, hi: 7,
+			expectedCount: 4,
+			expectedKeys:  []int{0, 1, 2, 7, 8, 9},
+		},
+		"whole tree": {
+			lo: 0, hi: 10,
+			expectedCount: 10,
+			expectedKeys:  nil,
+		},
+		"bounds outside tree": {
+			lo: -5, hi: 2,
+			expectedCount: 2,
+			expectedKeys:  []int{2, 3, 4, 5, 6, 7, 8, 9},
+		},
+		"empty range": {
+			lo: 5, hi: 5,
+			expectedCount: 0,
+			expectedKeys:  []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		},
+		"inverted range": {
+			lo: 7, hi: 3,
+			expectedCount: 0,
+			expectedKeys:  []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tree := New[int, struct{}, struct{}](func(a, b int) bool {
+				return a < b
+			})
+			for _, key := range []int{5, 2, 8, 0, 3, 7, 9, 1, 4, 6} {
+				tree.Insert(key, struct{}{})
+			}
+
+			count := tree.RangeDelete(tc.lo, tc.hi)
+			assert.Equal(t, tc.expectedCount, count, "unexpected number of deleted nodes")
+			require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+
+			var keys []int
+			if !tree.IsNil(tree.Root()) {
+				tree.TraverseInOrder(tree.Root(), func(n *Node[int, struct{}, struct{}]) bool {
+					keys = append(keys, tree.Key(n))
+					return true
+				})
+			}
+			assert.Equal(t, tc.expectedKeys, keys, "unexpected keys remaining after RangeDelete")
+		})
+	}
+}
+
+func TestTree_CountRange(t *testing.T) {
+	tree := New[int, struct{}, struct{}](func(a, b int) bool {
+		return a < b
+	})
+	assert.Equal(t, 0, tree.CountRange(0, 100), "expected empty tree to have no keys in range")
+
+	for _, key := range []int{50, 20, 80, 10, 30, 70, 90, 25, 35, 75} {
+		tree.Insert(key, struct{}{})
+	}
+
+	tests := map[string]struct {
+		lo, hi   int
+		expected int
+	}{
+		"whole tree":           {lo: 0, hi: 100, expected: 10},
+		"inclusive lower":      {lo: 20, hi: 31, expected: 3},
+		"exclusive upper":      {lo: 20, hi: 30, expected: 2},
+		"between keys":         {lo: 36, hi: 69, expected: 1},
+		"no keys in range":     {lo: 51, hi: 70, expected: 0},
+		"single key":           {lo: 75, hi: 76, expected: 1},
+		"empty interval":       {lo: 50, hi: 50, expected: 0},
+		"inverted interval":    {lo: 80, hi: 20, expected: 0},
+		"below all keys":       {lo: -10, hi: 10, expected: 0},
+		"above all keys":       {lo: 91, hi: 1000, expected: 0},
+		"upper bound past max": {lo: 80, hi: 1000, expected: 2},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tree.CountRange(tc.lo, tc.hi))
+		})
+	}
+}
+
+func TestTree_Equal(t *testing.T) {
+	newTree := func(keys ...int) *Tree[int, string, struct{}] {
+		tree := New[int, string, struct{}](func(a, b int) bool {
+			return a < b
+		})
+		for _, key := range keys {
+			tree.Insert(key, fmt.Sprintf("v%d", key))
+		}
+		return tree
+	}
+	valueEq := func(a, b string) bool { return a == b }
+
+	// same keys, different shape
+	a := newTree(4, 2, 6, 1, 3, 5, 7)
+	b := newTree(1, 2, 3, 4, 5, 6, 7)
+	assert.True(t, a.Equal(b, valueEq), "expected trees with same contents to be equal")
+	assert.True(t, b.Equal(a, valueEq), "expected Equal to be symmetric")
+	assert.False(t, a.EqualStructure(b, valueEq), "expected trees with different shapes not to be structurally equal")
+
+	// same keys, same shape
+	c := newTree(4, 2, 6, 1, 3, 5, 7)
+	assert.True(t, a.EqualStructure(c, valueEq), "expected trees with same shape to be structurally equal")
+
+	// different values
+	n, _ := c.Search(5)
+	c.SetValue(n, "five")
+	assert.False(t, a.Equal(c, valueEq), "expected trees with different values not to be equal")
+	assert.False(t, a.EqualStructure(c, valueEq), "expected trees with different values not to be structurally equal")
+	assert.True(t, a.Equal(c, nil), "expected nil valueEq to ignore values")
+	assert.True(t, a.EqualStructure(c, nil), "expected nil valueEq to ignore values")
+
+	// different key sets
+	assert.False(t, a.Equal(newTree(4, 2, 6, 1, 3, 5), valueEq), "expected trees with missing key not to be equal")
+	assert.False(t, a.Equal(newTree(4, 2, 6, 1, 3, 5, 8), valueEq), "expected trees with different key not to be equal")
+	assert.False(t, newTree(4, 2, 6, 1, 3, 5).EqualStructure(a, valueEq), "expected trees with missing key not to be structurally equal")
+
+	// empty trees
+	assert.True(t, newTree().Equal(newTree(), valueEq), "expected empty trees to be equal")
+	assert.True(t, newTree().EqualStructure(newTree(), valueEq), "expected empty trees to be structurally equal")
+	assert.False(t, newTree().Equal(a, valueEq), "expected empty tree not to equal non-empty tree")
+}
+
+func TestTree_Rank_Select(t *testing.T) {
+	tree := New[int, struct{}, struct{}](func(a, b int) bool {
+		return a < b
+	})
+
+	// empty tree
+	assert.Equal(t, 0, tree.Size(), "expected empty tree")
+	assert.Equal(t, 0, tree.Rank(10), "expected rank 0 in empty tree")
+	n, found := tree.Select(0)
+	assert.False(t, found, "expected Select to fail on empty tree")
+	assert.True(t, tree.IsNil(n), "expected sentinel from Select on empty tree")
+
+	keys := []int{50, 20, 80, 10, 30, 70, 90, 25, 35, 75}
+	for _, key := range keys {
+		tree.Insert(key, struct{}{})
+	}
+	assert.Equal(t, len(keys), tree.Size(), "unexpected tree size")
+	assert.Equal(t, len(keys), tree.SubtreeSize(tree.Root()), "unexpected root subtree size")
+	assert.Equal(t, 0, tree.SubtreeSize(tree.Sentinel()), "expected sentinel subtree size of 0")
+
+	sorted := []int{10, 20, 25, 30, 35, 50, 70, 75, 80, 90}
+	for i, key := range sorted {
+		assert.Equal(t, i, tree.Rank(key), "unexpected rank for key %d", key)
+		n, found := tree.Select(i)
+		require.True(t, found, "expected Select(%d) to succeed", i)
+		assert.Equal(t, key, tree.Key(n), "unexpected key from Select(%d)", i)
+	}
+
+	// keys not present in tree
+	assert.Equal(t, 0, tree.Rank(5), "unexpected rank for key below minimum")
+	assert.Equal(t, 3, tree.Rank(26), "unexpected rank for absent key")
+	assert.Equal(t, 10, tree.Rank(100), "unexpected rank for key above maximum")
+
+	// out of range
+	_, found = tree.Select(-1)
+	assert.False(t, found, "expected Select(-1) to fail")
+	_, found = tree.Select(len(keys))
+	assert.False(t, found, "expected Select(size) to fail")
+}
+
+func TestTree_Size_maintained(t *testing.T) {
+	tree := New[int, struct{}, struct{}](func(a, b int) bool {
+		return a < b
+	})
+	for _, key := range []int{50, 20, 80, 10, 30, 70, 90, 25, 35, 75} {
+		tree.Insert(key, struct{}{})
+	}
+
+	// updating an existing key does not change sizes
+	tree.Insert(30, struct{}{})
+	assert.Equal(t, 10, tree.Size(), "expected size to be unchanged after update")
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+
+	// rotations
+	tree.RotateLeft(tree.Root())
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree after left rotation")
+	assert.Equal(t, 10, tree.Size(), "expected size to be unchanged after rotation")
+	tree.RotateRight(tree.Root())
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree after right rotation")
+
+	// deletions covering leaf, unary and full nodes, including successor that is not a direct child
+	for i, key := range []int{25, 90, 20, 50, 10, 80, 30, 35, 75, 70} {
+		n, found := tree.Search(key)
+		require.True(t, found, "expected key %d to be found", key)
+		tree.Delete(n)
+		require.NoError(t, tree.IsTreeValid(), "expected valid tree after deleting %d", key)
+		assert.Equal(t, 9-i, tree.Size(), "unexpected size after deleting %d", key)
+	}
+}
+
+func TestTree_RefreshPath(t *testing.T) {
+	tree := New[int, struct{}, struct{}](func(a, b int) bool {
+		return a < b
+	})
+	n2, _ := tree.Insert(2, struct{}{})
+	n1, _ := tree.Insert(1, struct{}{})
+	tree.Insert(3, struct{}{})
+
+	// manually detach node 1, leaving sizes stale
+	tree.SetLeft(n2, tree.Sentinel())
+	tree.SetParent(n1, tree.Sentinel())
+	assert.Error(t, tree.IsTreeValid(), "expected stale subtree sizes to be detected")
+
+	tree.RefreshPath(n2)
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree after RefreshPath")
+	assert.Equal(t, 2, tree.Size(), "unexpected size after RefreshPath")
+}
+
+func TestTree_KthSmallest_KthLargest(t *testing.T) {
+	tree := New[int, struct{}, struct{}](func(a, b int) bool {
+		return a < b
+	})
+	_, found := tree.KthSmallest(1)
+	assert.False(t, found, "expected KthSmallest to fail on empty tree")
+	_, found = tree.KthLargest(1)
+	assert.False(t, found, "expected KthLargest to fail on empty tree")
+
+	for _, key := range []int{4, 2, 6, 1, 3, 5, 7} {
+		tree.Insert(key, struct{}{})
+	}
+	for k := 1; k <= 7; k++ {
+		n, found := tree.KthSmallest(k)
+		require.True(t, found, "expected KthSmallest(%d) to succeed", k)
+		assert.Equal(t, k, tree.Key(n), "unexpected KthSmallest(%d)", k)
+
+		n, found = tree.KthLargest(k)
+		require.True(t, found, "expected KthLargest(%d) to succeed", k)
+		assert.Equal(t, 8-k, tree.Key(n), "unexpected KthLargest(%d)", k)
+	}
+
+	for _, k := range []int{-1, 0, 8} {
+		n, found := tree.KthSmallest(k)
+		assert.False(t, found, "expected KthSmallest(%d) to fail", k)
+		assert.True(t, tree.IsNil(n), "expected sentinel from KthSmallest(%d)", k)
+		n, found = tree.KthLargest(k)
+		assert.False(t, found, "expected KthLargest(%d) to fail", k)
+		assert.True(t, tree.IsNil(n), "expected sentinel from KthLargest(%d)", k)
+	}
+}
+
+func TestTree_Nearest(t *testing.T) {
+	tree := New[int, struct{}, struct{}](func(a, b int) bool {
+		return a < b
+	})
+	distance := func(a, b int) int {
+		if a > b {
+			return a - b
+		}
+		return b - a
+	}
+
+	n, found := tree.Nearest(10, distance)
+	assert.False(t, found, "expected Nearest to fail on empty tree")
+	assert.True(t, tree.IsNil(n), "expected sentinel from Nearest on empty tree")
+
+	for _, key := range []int{10, 20, 30, 40} {
+		tree.Insert(key, struct{}{})
+	}
+
+	tests := map[string]struct {
+		key, expected int
+	}{
+		"exact match":        {key: 20, expected: 20},
+		"closer to floor":    {key: 22, expected: 20},
+		"closer to ceiling":  {key: 28, expected: 30},
+		"tie prefers floor":  {key: 25, expected: 20},
+		"below minimum":      {key: -5, expected: 10},
+		"above maximum":      {key: 100, expected: 40},
+		"just below a key":   {key: 39, expected: 40},
+		"just above minimum": {key: 11, expected: 10},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			n, found := tree.Nearest(tc.key, distance)
+			require.True(t, found, "expected Nearest to succeed")
+			assert.Equal(t, tc.expected, tree.Key(n))
+		})
+	}
+}
+
+func TestTree_LowestCommonAncestor(t *testing.T) {
+	tree := New[int, struct{}, struct{}](func(a, b int) bool {
+		return a < b
+	})
+	nodes := make(map[int]*Node[int, struct{}, struct{}])
+	for _, key := range []int{50, 20, 80, 10, 30, 70, 90, 25, 35, 75} {
+		nodes[key], _ = tree.Insert(key, struct{}{})
+	}
+
+	tests := map[string]struct {
+		a, b, expected int
+	}{
+		"siblings":             {a: 10, b: 30, expected: 20},
+		"across root":          {a: 25, b: 75, expected: 50},
+		"deep in one subtree":  {a: 25, b: 35, expected: 30},
+		"ancestor and its own": {a: 20, b: 35, expected: 20},
+		"same node":            {a: 70, b: 70, expected: 70},
+		"with root":            {a: 50, b: 90, expected: 50},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			lca := tree.LowestCommonAncestor(nodes[tc.a], nodes[tc.b])
+			assert.Equal(t, tc.expected, tree.Key(lca))
+			lca = tree.LowestCommonAncestor(nodes[tc.b], nodes[tc.a])
+			assert.Equal(t, tc.expected, tree.Key(lca), "expected LowestCommonAncestor to be symmetric")
+		})
+	}
+
+	// nil nodes
+	assert.True(t, tree.IsNil(tree.LowestCommonAncestor(nodes[10], tree.Sentinel())))
+	assert.True(t, tree.IsNil(tree.LowestCommonAncestor(nil, nodes[10])))
+}
+
+func TestTree_Path(t *testing.T) {
+	tree := New[int, struct{}, struct{}](func(a, b int) bool {
+		return a < b
+	})
+	nodes := make(map[int]*Node[int, struct{}, struct{}])
+	for _, key := range []int{50, 20, 80, 10, 30, 70, 90, 25, 35, 75} {
+		nodes[key], _ = tree.Insert(key, struct{}{})
+	}
+
+	pathKeys := func(path []*Node[int, struct{}, struct{}]) []int {
+		var keys []int
+		for _, n := range path {
+			keys = append(keys, tree.Key(n))
+		}
+		return keys
+	}
+
+	assert.Equal(t, []int{50}, pathKeys(tree.Path(nodes[50])), "unexpected path to root")
+	assert.Equal(t, []int{50, 20, 30, 25}, pathKeys(tree.Path(nodes[25])), "unexpected path to node 25")
+	assert.Equal(t, []int{50, 80, 70, 75}, pathKeys(tree.Path(nodes[75])), "unexpected path to node 75")
+	assert.Nil(t, tree.Path(tree.Sentinel()), "expected nil path for sentinel")
+}
+
+func TestTree_WithDuplicateKeys(t *testing.T) {
+	tree := New[int, string, struct{}](func(a, b int) bool {
+		return a < b
+	}, WithDuplicateKeys())
+
+	inserts := []struct {
+		key   int
+		value string
+	}{
+		{5, "a"}, {3, "b"}, {5, "c"}, {8, "d"}, {5, "e"}, {3, "f"}, {1, "g"},
+	}
+	nodes := make([]*Node[int, string, struct{}], 0, len(inserts))
+	for _, ins := range inserts {
+		n, inserted := tree.Insert(ins.key, ins.value)
+		require.True(t, inserted, "expected duplicate key %d to be inserted", ins.key)
+		nodes = append(nodes, n)
+		require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	}
+	assert.Equal(t, len(inserts), tree.Size(), "unexpected size")
+
+	// in-order traversal keeps insertion order among equal keys
+	var values []string
+	tree.TraverseInOrder(tree.Root(), func(n *Node[int, string, struct{}]) bool {
+		values = append(values, tree.Value(n))
+		return true
+	})
+	assert.Equal(t, []string{"g", "b", "f", "a", "c", "e", "d"}, values, "unexpected in-order values")
+
+	// counts
+	assert.Equal(t, 3, tree.Count(5), "unexpected count for key 5")
+	assert.Equal(t, 2, tree.Count(3), "unexpected count for key 3")
+	assert.Equal(t, 1, tree.Count(8), "unexpected count for key 8")
+	assert.Equal(t, 0, tree.Count(4), "unexpected count for absent key")
+
+	// search, floor and ceiling
+	n, found := tree.Search(5)
+	require.True(t, found)
+	assert.Equal(t, "a", tree.Value(n), "expected Search to return first inserted equal key")
+	n, found = tree.Ceiling(5)
+	require.True(t, found)
+	assert.Equal(t, "a", tree.Value(n), "expected Ceiling to return first inserted equal key")
+	n, found = tree.Floor(5)
+	require.True(t, found)
+	assert.Equal(t, "e", tree.Value(n), "expected Floor to return last inserted equal key")
+	_, found = tree.Search(4)
+	assert.False(t, found, "expected absent key not to be found")
+
+	// every node is contained, including those not returned by Search
+	for _, n := range nodes {
+		assert.True(t, tree.Contains(n), "expected node %s to be contained", n)
+	}
+
+	// deleting the first equal key exposes the next one
+	tree.Delete(nodes[0])
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	assert.False(t, tree.Contains(nodes[0]), "expected deleted node not to be contained")
+	assert.Equal(t, 2, tree.Count(5), "unexpected count for key 5 after delete")
+	n, _ = tree.Search(5)
+	assert.Equal(t, "c", tree.Value(n), "expected Search to return next inserted equal key")
+
+	// ranges include every equal key
+	assert.Equal(t, 4, tree.CountRange(3, 6), "unexpected CountRange")
+	assert.Equal(t, 4, tree.RangeDelete(3, 6), "unexpected RangeDelete count")
+	assert.Equal(t, 2, tree.Size(), "unexpected size after RangeDelete")
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+}
+
+func TestTree_WithReverseOrder(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b }, WithReverseOrder())
+	for i := 2; i <= 10; i += 2 {
+		tree.Insert(i, i*i)
+	}
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	assert.True(t, tree.Less(2, 1), "expected Less to follow the reverse order")
+
+	// traversal, Min and Max follow the tree's (descending) order
+	var keys []int
+	for n := tree.Min(tree.Root()); !tree.IsNil(n); n = tree.Successor(n) {
+		keys = append(keys, tree.Key(n))
+	}
+	assert.Equal(t, []int{10, 8, 6, 4, 2}, keys)
+	assert.Equal(t, 2, tree.Key(tree.Max(tree.Root())))
+
+	// Floor is the smallest key >= 5, Ceiling the largest key <= 5
+	n, found := tree.Floor(5)
+	assert.True(t, found)
+	assert.Equal(t, 6, tree.Key(n))
+	n, found = tree.Ceiling(5)
+	assert.True(t, found)
+	assert.Equal(t, 4, tree.Key(n))
+	_, found = tree.Ceiling(1)
+	assert.False(t, found)
+
+	// order statistics count keys in the tree's order
+	assert.Equal(t, 3, tree.Rank(5))
+	assert.Equal(t, 3, tree.CountRange(8, 3))
+	n, _ = tree.Select(0)
+	assert.Equal(t, 10, tree.Key(n))
+}
+
+func TestTree_Count(t *testing.T) {
+	tree := New[int, struct{}, struct{}](func(a, b int) bool {
+		return a < b
+	})
+	tree.Insert(1, struct{}{})
+	tree.Insert(1, struct{}{})
+	assert.Equal(t, 1, tree.Count(1), "expected unique keys without multiset mode")
+	assert.Equal(t, 0, tree.Count(2), "unexpected count for absent key")
+}
+
+func TestTree_staleAndForeignNodes(t *testing.T) {
+	tree := New[int, struct{}, struct{}](func(a, b int) bool {
+		return a < b
+	})
+	other := New[int, struct{}, struct{}](func(a, b int) bool {
+		return a < b
+	})
+	for _, key := range []int{50, 20, 80, 10, 30} {
+		tree.Insert(key, struct{}{})
+		other.Insert(key, struct{}{})
+	}
+	foreign, _ := other.Search(20)
+	n20, _ := tree.Search(20)
+
+	// foreign nodes are ignored by mutating methods
+	replacement, deleted := tree.Delete(foreign)
+	assert.False(t, deleted, "expected foreign node not to be deleted")
+	assert.True(t, tree.IsNil(replacement), "expected sentinel replacement for foreign node")
+	tree.RotateLeft(foreign)
+	tree.RotateRight(foreign)
+	changes := 0
+	tree.OnChange(func(ChangeOp, int, struct{}) { changes++ })
+	tree.SetValue(foreign, struct{}{})
+	tree.SetKey(foreign, 99)
+	tree.SetLeft(foreign, tree.Sentinel())
+	tree.SetRight(foreign, tree.Sentinel())
+	tree.SetParent(foreign, tree.Sentinel())
+	tree.SetLeft(tree.Root(), foreign)
+	tree.Transplant(foreign, tree.Sentinel())
+	tree.Transplant(tree.Left(tree.Root()), foreign)
+	assert.Zero(t, changes, "expected no change to be notified for foreign node")
+	assert.Equal(t, 20, other.Key(foreign), "expected foreign node key to be unchanged")
+	assert.Equal(t, 5, tree.Size(), "expected tree to be unchanged")
+	assert.Equal(t, 5, other.Size(), "expected other tree to be unchanged")
+	assert.Equal(t, 20, tree.Key(tree.Left(tree.Root())), "expected tree structure to be unchanged")
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	require.NoError(t, other.IsTreeValid(), "expected valid tree")
+	assert.False(t, tree.Contains(foreign), "expected foreign node not to be contained")
+
+	// stale nodes are ignored by mutating methods
+	_, deleted = tree.Delete(n20)
+	require.True(t, deleted, "expected node to be deleted")
+	assert.False(t, tree.Contains(n20), "expected deleted node not to be contained")
+	_, deleted = tree.Delete(n20)
+	assert.False(t, deleted, "expected stale node not to be deleted twice")
+	tree.RotateLeft(n20)
+	tree.RotateRight(n20)
+	tree.SetValue(n20, struct{}{})
+	tree.SetKey(n20, 99)
+	tree.SetLeft(tree.Root(), n20)
+	tree.SetParent(n20, tree.Root())
+	tree.Transplant(n20, tree.Sentinel())
+	assert.Equal(t, 1, changes, "expected only the deletion to be notified")
+	assert.Equal(t, 4, tree.Size(), "expected tree to be unchanged by stale node")
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	assert.Nil(t, tree.Parent(n20), "expected stale node links to be cleared")
+
+	// Release ignores nodes that do not belong to the tree
+	tree.Release(foreign)
+	assert.True(t, other.Contains(foreign), "expected Release to ignore foreign node")
+}
+
+func TestTree_SetAugmentFunc(t *testing.T) {
+	tree := New[int, int, int](func(a, b int) bool {
+		return a < b
+	})
+
+	// insert some nodes before registering, to check existing nodes are augmented on registration
+	for _, key := range []int{50, 20, 80} {
+		tree.Insert(key, 1)
+	}
+
+	// maintain the sum of values in each subtree as metadata
+	calls := 0
+	tree.SetAugmentFunc(func(n *Node[int, int, int]) {
+		calls++
+		tree.SetMetadata(n, tree.Value(n)+tree.Metadata(tree.Left(n))+tree.Metadata(tree.Right(n)))
+	})
+	assert.Equal(t, 3, calls, "expected every existing node to be augmented on registration")
+
+	// checkSums verifies the metadata of every node against a full recomputation
+	var sum func(n *Node[int, int, int]) int
+	sum = func(n *Node[int, int, int]) int {
+		if tree.IsNil(n) {
+			return 0
+		}
+		return tree.Value(n) + sum(tree.Left(n)) + sum(tree.Right(n))
+	}
+	checkSums := func(msg string) {
+		tree.TraverseInOrder(tree.Root(), func(n *Node[int, int, int]) bool {
+			assert.Equal(t, sum(n), tree.Metadata(n), "unexpected aggregate at node %d %s", tree.Key(n), msg)
+			return true
+		})
+	}
+	checkSums("after registration")
+
+	for _, key := range []int{10, 30, 70, 90, 25, 35, 75, 5, 1} {
+		tree.Insert(key, key)
+		checkSums(fmt.Sprintf("after inserting %d", key))
+	}
+	assert.Equal(t, 3+10+30+70+90+25+35+75+5+1, tree.Metadata(tree.Root()), "unexpected root aggregate")
+
+	// updating a value via Insert or SetValue
+	tree.Insert(25, 1000)
+	checkSums("after updating value via Insert")
+	n, _ := tree.Search(75)
+	tree.SetValue(n, 2000)
+	checkSums("after updating value via SetValue")
+
+	// rotations
+	tree.RotateLeft(tree.Root())
+	checkSums("after left rotation")
+	tree.RotateRight(tree.Root())
+	checkSums("after right rotation")
+
+	// deletions
+	for _, key := range []int{20, 50, 1, 90, 30} {
+		n, _ := tree.Search(key)
+		tree.Delete(n)
+		checkSums(fmt.Sprintf("after deleting %d", key))
+	}
+
+	// removing the function stops augmentation
+	tree.SetAugmentFunc(nil)
+	root := tree.Metadata(tree.Root())
+	tree.Insert(1000, 1000)
+	assert.Equal(t, root, tree.Metadata(tree.Root()), "expected aggregate not to change without AugmentFunc")
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+}
+
+func TestTree_TraverseInOrderErr(t *testing.T) {
+	tree := New[int, struct{}, struct{}](func(a, b int) bool {
+		return a < b
+	})
+	for _, key := range []int{4, 2, 6, 1, 3, 5, 7} {
+		tree.Insert(key, struct{}{})
+	}
+
+	// complete traversal
+	var keys []int
+	err := tree.TraverseInOrderErr(tree.Root(), func(n *Node[int, struct{}, struct{}]) error {
+		keys = append(keys, tree.Key(n))
+		return nil
+	})
+	require.NoError(t, err, "expected traversal to complete without error")
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7}, keys, "unexpected traversal order")
+
+	// stop early without error
+	keys = nil
+	err = tree.TraverseInOrderErr(tree.Root(), func(n *Node[int, struct{}, struct{}]) error {
+		keys = append(keys, tree.Key(n))
+		if tree.Key(n) == 3 {
+			return ErrStopTraversal
+		}
+		return nil
+	})
+	require.NoError(t, err, "expected ErrStopTraversal not to be returned")
+	assert.Equal(t, []int{1, 2, 3}, keys, "expected traversal to stop at node 3")
+
+	// abort with error, including wrapped errors
+	errTest := errors.New("test error")
+	for _, key := range []int{1, 4, 7} {
+		keys = nil
+		err = tree.TraverseInOrderErr(tree.Root(), func(n *Node[int, struct{}, struct{}]) error {
+			keys = append(keys, tree.Key(n))
+			if tree.Key(n) == key {
+				return fmt.Errorf("node %d: %w", key, errTest)
+			}
+			return nil
+		})
+		require.ErrorIs(t, err, errTest, "expected error from callback to be returned")
+		assert.Len(t, keys, key, "expected traversal to stop at node %d", key)
+	}
+}
+
+func TestTree_WriteSVG(t *testing.T) {
+	tree := New[int, string, struct{}](func(a, b int) bool { return a < b })
+
+	// empty tree
+	var buf strings.Builder
+	require.NoError(t, tree.WriteSVG(&buf, nil))
+	assert.Contains(t, buf.String(), "Empty Tree")
+
+	tree.Insert(2, "<two>")
+	tree.Insert(1, "one")
+	tree.Insert(3, "three")
+
+	buf.Reset()
+	require.NoError(t, tree.WriteSVG(&buf, func(n *Node[int, string, struct{}]) SVGNodeStyle {
+		if tree.Key(n) == 3 {
+			return SVGNodeStyle{Fill: "yellow"}
+		}
+		return SVGNodeStyle{}
+	}))
+	svg := buf.String()
+	assert.True(t, strings.HasPrefix(svg, "<svg "), "expected svg document")
+	assert.True(t, strings.HasSuffix(svg, "</svg>\n"), "expected svg document to be closed")
+	assert.Equal(t, 3, strings.Count(svg, "<rect "), "expected one box per node")
+	assert.Equal(t, 2, strings.Count(svg, "<line "), "expected one edge per non-root node")
+	assert.Contains(t, svg, "2: &lt;two&gt;", "expected escaped label")
+	assert.Contains(t, svg, "1: one")
+	assert.Equal(t, 1, strings.Count(svg, `fill="yellow"`), "expected custom style")
+	assert.Equal(t, 2, strings.Count(svg, `rx="6" fill="white"`), "expected default style")
+
+	// the root is drawn at the top, between its children
+	var xs, ys [4]int
+	re := regexp.MustCompile(`<rect x="(\d+)" y="(\d+)".*>(\d+): `)
+	for _, m := range re.FindAllStringSubmatch(svg, -1) {
+		k, _ := strconv.Atoi(m[3])
+		xs[k], _ = strconv.Atoi(m[1])
+		ys[k], _ = strconv.Atoi(m[2])
+	}
+	assert.Less(t, xs[1], xs[2])
+	assert.Less(t, xs[2], xs[3])
+	assert.Less(t, ys[2], ys[1])
+	assert.Equal(t, ys[1], ys[3])
+}
+
+func TestTree_SetNodeFormatter(t *testing.T) {
+	tree := New[int, string, struct{}](func(a, b int) bool { return a < b })
+	tree.Insert(2, "two")
+	tree.Insert(1, "one")
+
+	tree.SetNodeFormatter(func(k int, v string, _ struct{}) string {
+		return fmt.Sprintf("<%d=%s>", k, v)
+	})
+	assert.Equal(t, " ╭── <1=one>\n<2=two>\n", tree.String())
+
+	var buf strings.Builder
+	require.NoError(t, tree.WriteSVG(&buf, nil))
+	assert.Contains(t, buf.String(), "&lt;1=one&gt;", "expected formatter to label SVG nodes")
+
+	// removing the formatter restores the default layout
+	tree.SetNodeFormatter(nil)
+	assert.Equal(t, " ╭── 1: one [{}]\n2: two [{}]\n", tree.String())
+}
+
+// treeHeight returns the number of levels in the tree.
+func treeHeight[K, V, M any](tree *Tree[K, V, M]) int {
+	h := 0
+	tree.TraverseInOrder(tree.Root(), func(n *Node[K, V, M]) bool {
+		h = max(h, tree.Depth(n)+1)
+		return true
+	})
+	return h
+}
+
+func TestTree_RebuildSubtree(t *testing.T) {
+	tree := New[int, string, int](func(a, b int) bool { return a < b })
+	tree.SetAugmentFunc(func(n *Node[int, string, int]) {
+		tree.SetMetadata(n, tree.Key(n)+tree.Metadata(tree.Left(n))+tree.Metadata(tree.Right(n)))
+	})
+
+	// build a degenerate tree, with a balanced left subtree above the chain
+	tree.Insert(-5, "v")
+	tree.Insert(-10, "v")
+	tree.Insert(-1, "v")
+	handles := make(map[int]*Node[int, string, int])
+	for i := 0; i < 15; i++ {
+		n, _ := tree.Insert(i, "v")
+		handles[i] = n
+	}
+	require.Equal(t, 17, treeHeight(tree), "expected degenerate chain")
+
+	// rebuild the chain below the root
+	chain := tree.Right(tree.Root())
+	root := tree.RebuildSubtree(chain)
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 7, tree.Key(root), "expected median as new subtree root")
+	assert.Equal(t, tree.Right(tree.Root()), root, "expected new subtree root to be attached to parent")
+	assert.Equal(t, tree.Root(), tree.Parent(root))
+	assert.Equal(t, 16, tree.SubtreeSize(root))
+	assert.Equal(t, 6, treeHeight(tree), "expected balanced subtree")
+	assert.Equal(t, 105-16, tree.Metadata(tree.Root()), "expected augmented data of ancestors to be refreshed")
+	for key, n := range handles {
+		assert.True(t, tree.Contains(n), "expected handle to remain valid")
+		assert.Equal(t, key, tree.Key(n))
+	}
+
+	// rebuild from the root
+	root = tree.RebuildSubtree(tree.Root())
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, root, tree.Root())
+	assert.True(t, tree.IsNil(tree.Parent(root)))
+	assert.Equal(t, 5, treeHeight(tree))
+	assert.Equal(t, 18, tree.Size())
+
+	// rebuild a left subtree
+	left := tree.Left(tree.Root())
+	assert.Equal(t, left, tree.RebuildSubtree(left), "expected already balanced subtree to keep its root")
+	require.NoError(t, tree.IsTreeValid())
+
+	// foreign, stale and nil nodes are returned unchanged
+	other := New[int, string, int](func(a, b int) bool { return a < b })
+	o, _ := other.Insert(1, "v")
+	assert.Equal(t, o, tree.RebuildSubtree(o))
+	tree.Delete(handles[3])
+	assert.Equal(t, handles[3], tree.RebuildSubtree(handles[3]))
+	assert.Equal(t, tree.Sentinel(), tree.RebuildSubtree(tree.Sentinel()))
+	require.NoError(t, tree.IsTreeValid())
+}
+
+func TestTree_Rotations(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	for i := 0; i < 3; i++ {
+		tree.Insert(i, 0)
+	}
+	assert.Zero(t, tree.Rotations())
+	tree.RotateLeft(tree.Root())
+	tree.RotateRight(tree.Root())
+	assert.Equal(t, uint64(2), tree.Rotations())
+
+	// rotations that cannot be performed are not counted
+	tree.RotateRight(tree.Min(tree.Root()))
+	assert.Equal(t, uint64(2), tree.Rotations())
+}
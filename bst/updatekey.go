@@ -0,0 +1,100 @@
+package bst
+
+// UpdateKey changes the key of node n to key, preserving its value and metadata. n remains a valid
+// handle to the same entry, which is moved to the position of its new key, so call sites need not
+// delete and reinsert the entry, nor update the handles they hold.
+//
+// If key keeps n between its predecessor and successor, the key is updated in place, and only the
+// augmented data of n and its ancestors is refreshed. Otherwise, n is unlinked as Tree.Delete would,
+// and linked back as Tree.Insert would link a new node. Either way, UpdateKey runs in O(h) time, where
+// h is the tree height, and notifies the deletion of the old key followed by the insertion of the new
+// key (see Tree.OnChange).
+//
+// Trees extending bst.Tree must move nodes with their own balancing, using UpdateKeyFunc
+// (as rbtree.Tree.UpdateKey does).
+//
+// Example Usage:
+//
+//	// reschedule a job, keeping the node handle held by the job
+//	tree.UpdateKey(job.node, job.next)
+//
+// Returns:
+//   - true if the key of n was updated.
+//   - false if n is nil, has been removed, or belongs to a different tree, or if another node already
+//     holds key and duplicate keys are not enabled (see WithDuplicateKeys).
+func (t *Tree[K, V, M]) UpdateKey(n *Node[K, V, M], key K) bool {
+	return UpdateKeyFunc(t, n, key, t.move)
+}
+
+// move implements Tree.UpdateKey for a node whose new key does not keep it in place.
+func (t *Tree[K, V, M]) move(n *Node[K, V, M], key K) {
+	t.unlink(n)
+	t.Relink(n, key)
+	t.checkDegraded(n)
+}
+
+// UpdateKeyFunc implements Tree.UpdateKey for trees extending bst.Tree, moving n with move when
+// its new key does not keep it in place.
+//
+// move must unlink n from the tree by relinking its neighbours, with the tree's own balancing,
+// as its Delete method would without releasing n, then link n back with Tree.Relink, and restore
+// the balance of the tree as its Insert method would for a new node.
+//
+// Returns:
+//   - true if the key of n was updated.
+//   - false if n is nil, has been removed, or belongs to a different tree, or if another node already
+//     holds key and duplicate keys are not enabled (see WithDuplicateKeys).
+func UpdateKeyFunc[K, V, M any](t *Tree[K, V, M], n *Node[K, V, M], key K, move func(n *Node[K, V, M], key K)) bool {
+	if t.IsNil(n) || !n.BelongsTo(t) {
+		return false
+	}
+	if !t.duplicates {
+		if found, ok := t.Search(key); ok {
+			return found == n
+		}
+	}
+
+	// update the key in place if it keeps n between its predecessor and successor
+	pred, succ := t.Predecessor(n), t.Successor(n)
+	if (t.IsNil(pred) || !t.less(key, pred.key)) && (t.IsNil(succ) || !t.less(succ.key, key)) {
+		oldKey := n.key
+		n.key = key
+		t.RefreshPath(n)
+		t.snapUpdate(oldKey, n)
+		t.notify(ChangeDelete, oldKey, n.value)
+		t.notify(ChangeInsert, key, n.value)
+		return true
+	}
+	move(n, key)
+	return true
+}
+
+// Relink links node n, which has been unlinked from the tree by relinking its neighbours, back into
+// the tree with the given key, as a leaf at the end of its search path, as Tree.Insert links a new node.
+// The augmented data of n and its ancestors is refreshed, and the deletion of the old key followed by
+// the insertion of the new key is notified (see Tree.OnChange).
+//
+// This function is intended to be used only when extending bst.Tree, to implement UpdateKeyFunc.
+func (t *Tree[K, V, M]) Relink(n *Node[K, V, M], key K) {
+	if n == t.first || n == t.last {
+		t.refreshBounds()
+	}
+	oldKey := n.key
+	n.key = key
+
+	// find the nil leaf where n will be linked, after any equal keys
+	parent := t.nil
+	for x := t.root; !t.IsNil(x); {
+		parent = x
+		if t.less(key, x.key) {
+			x = x.left
+		} else {
+			x = x.right
+		}
+	}
+	t.link(n, parent)
+	t.snapDelete(oldKey, n)
+	t.snapInsert(n)
+	t.notify(ChangeDelete, oldKey, n.value)
+	t.notify(ChangeInsert, key, n.value)
+}
@@ -0,0 +1,89 @@
+package bst
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func TestTree_UpdateKey(t *testing.T) {
+	tree := New[int, int, struct{}](func(a, b int) bool { return a < b })
+	rng := rand.New(rand.NewSource(1))
+	handles := make(map[int]*Node[int, int, struct{}])
+	for len(handles) < 200 {
+		key := rng.Intn(1000)
+		n, _ := tree.Insert(key, key*10)
+		handles[key] = n
+	}
+
+	var changes []Change[int, int]
+	tree.OnChange(func(op ChangeOp, key int, value int) {
+		changes = append(changes, Change[int, int]{Op: op, Key: key, Value: value})
+	})
+	for i := 0; i < 2000; i++ {
+		var oldKey int
+		for oldKey = range handles {
+			break
+		}
+		n, newKey := handles[oldKey], rng.Intn(1000)
+		value := n.Value()
+		_, taken := handles[newKey]
+		changes = changes[:0]
+
+		updated := tree.UpdateKey(n, newKey)
+		switch {
+		case newKey == oldKey:
+			require.True(t, updated, "expected updating to the same key to succeed")
+		case taken:
+			require.False(t, updated, "expected key %d to be rejected as taken", newKey)
+		default:
+			require.True(t, updated, "expected key %d to be updated to %d", oldKey, newKey)
+			require.Equal(t, []Change[int, int]{
+				{Op: ChangeDelete, Key: oldKey, Value: value},
+				{Op: ChangeInsert, Key: newKey, Value: value},
+			}, changes, "unexpected changes")
+			delete(handles, oldKey)
+			handles[newKey] = n
+		}
+		require.True(t, tree.Contains(n), "expected node to remain in the tree")
+		require.Equal(t, value, n.Value(), "expected value to be preserved")
+		require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+		requireBounds(t, tree)
+	}
+
+	// every handle still holds its key, and its original value
+	require.Equal(t, len(handles), tree.Size())
+	for key, n := range handles {
+		found, ok := tree.Search(key)
+		require.True(t, ok, "expected key %d to be found", key)
+		assert.Same(t, n, found, "expected key %d to be held by the same node", key)
+	}
+
+	// stale and foreign nodes are not updated
+	n := handles[tree.Key(tree.Root())]
+	tree.Delete(n)
+	assert.False(t, tree.UpdateKey(n, 5000), "expected stale node not to be updated")
+	assert.False(t, tree.UpdateKey(nil, 5000), "expected nil node not to be updated")
+	assert.False(t, tree.UpdateKey(tree.Sentinel(), 5000), "expected sentinel not to be updated")
+}
+
+func TestTree_UpdateKey_duplicateKeys(t *testing.T) {
+	tree := New[int, string, struct{}](func(a, b int) bool { return a < b }, WithDuplicateKeys())
+	a, _ := tree.Insert(1, "a")
+	b, _ := tree.Insert(2, "b")
+	tree.Insert(3, "c")
+
+	// keys may be shared in multiset mode: a moved node follows any equal keys,
+	// while b keeps its place, as its new key still precedes or equals its successor's
+	require.True(t, tree.UpdateKey(a, 3))
+	require.True(t, tree.UpdateKey(b, 3))
+	require.NoError(t, tree.IsTreeValid())
+	var values []string
+	tree.TraverseInOrder(tree.Root(), func(n *Node[int, string, struct{}]) bool {
+		values = append(values, n.Value())
+		return true
+	})
+	assert.Equal(t, []string{"b", "c", "a"}, values)
+	assert.Equal(t, 3, tree.Count(3))
+}
@@ -0,0 +1,239 @@
+package bst
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Errors wrapped by the errors returned by Tree.IsTreeValid, identifying the kind of violation found.
+var (
+	ErrSentinelAltered     = errors.New("sentinel nil node parent not sentinel nil node")
+	ErrParentChildMismatch = errors.New("parent/child mismatch")
+	ErrOrderViolation      = errors.New("out of order keys")
+	ErrSubtreeSizeMismatch = errors.New("subtree size mismatch")
+)
+
+// walkInOrder applies f to every node reachable from the root, in order, returning the first error from f.
+//
+// Unlike Tree.TraverseInOrderErr, walkInOrder keeps the path to the current node on an explicit stack
+// rather than recursing, and follows child pointers only, so that it is safe to use on corrupted trees:
+// it stops with an error wrapping ErrSubtreeSizeMismatch once more nodes are reached than the root's
+// subtree size, which is the case if child pointers form a cycle.
+func (t *Tree[K, V, M]) walkInOrder(f func(n *Node[K, V, M]) error) error {
+	var stack []*Node[K, V, M]
+	reached := 0
+	n := t.root
+	for !t.IsNil(n) || len(stack) > 0 {
+
+		// descend to the left-most node of the subtree rooted at n
+		for ; !t.IsNil(n); n = n.left {
+			if reached++; reached > t.root.size {
+				return fmt.Errorf("traversal error: %w: more than %d nodes reachable from the root", ErrSubtreeSizeMismatch, t.root.size)
+			}
+			stack = append(stack, n)
+		}
+		n = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if err := f(n); err != nil {
+			return err
+		}
+		n = n.right
+	}
+	return nil
+}
+
+// ViolationKind identifies the kind of a Violation.
+//
+// Trees extending bst.Tree can define further kinds for their own invariants
+// (see rbtree.Tree.ValidateAll).
+type ViolationKind string
+
+const (
+	ViolationSentinel    ViolationKind = "sentinel altered"      // the sentinel nil node has a parent other than itself
+	ViolationRootParent  ViolationKind = "root has parent"       // the root's parent is not the sentinel nil node
+	ViolationOutOfOrder  ViolationKind = "out-of-order key"      // the node's key is not greater than its in-order predecessor's
+	ViolationParentChild ViolationKind = "parent/child mismatch" // the node's parent pointer does not point to the node it is a child of
+	ViolationSize        ViolationKind = "subtree size mismatch" // the node's subtree size does not match the sizes of its children
+	ViolationCycle       ViolationKind = "cycle"                 // the node is reachable from the root by more than one path
+)
+
+// Violation describes a single broken invariant found by Tree.ValidateAll.
+type Violation[K any] struct {
+	Kind ViolationKind
+	Key  K   // key of the offending node, or the zero value for violations not tied to a node
+	Path []K // keys on the path from the root to the offending node, inclusive of both, or nil
+}
+
+// Error describes the violation, such as "out-of-order key at node 5 (path: 10 → 3 → 5)".
+func (v Violation[K]) Error() string {
+	if v.Path == nil {
+		return string(v.Kind)
+	}
+	keys := make([]string, len(v.Path))
+	for i, key := range v.Path {
+		keys[i] = fmt.Sprint(key)
+	}
+	return fmt.Sprintf("%s at node %v (path: %s)", v.Kind, v.Key, strings.Join(keys, " → "))
+}
+
+// ValidateAll performs the same structural validation as Tree.IsTreeValid, but reports every
+// violation found rather than only the first, so that a corrupted tree can be diagnosed.
+//
+// The tree is walked from the root by following child pointers, without recursion, and each node's path
+// is made of the keys leading to it, so that violations can be located even if parent pointers are broken.
+// Nodes reachable by more than one path are reported once, as ViolationCycle, and not walked again.
+//
+// Returns:
+//   - nil if the tree is valid.
+//   - The violations found otherwise, in order of the walk (sentinel and root checks first, then in order).
+func (t *Tree[K, V, M]) ValidateAll() []Violation[K] {
+	var violations []Violation[K]
+	if t.nil.parent != t.nil {
+		violations = append(violations, Violation[K]{Kind: ViolationSentinel})
+	}
+	if t.root.parent != t.nil {
+		violations = append(violations, Violation[K]{Kind: ViolationRootParent})
+	}
+
+	// walk the tree in order, keeping the path to the current node on an explicit stack rather than
+	// recursing, so that degenerate trees of any height can be diagnosed
+	type frame struct {
+		n       *Node[K, V, M]
+		up      *frame // frame of n's parent, or nil for the root
+		entered bool   // whether n has been checked on the way down, and its left subtree walked
+	}
+	var prev *Node[K, V, M]
+	visited := make(map[*Node[K, V, M]]bool)
+	var stack []*frame
+	if !t.IsNil(t.root) {
+		stack = append(stack, &frame{n: t.root})
+	}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		n := f.n
+		report := func(kind ViolationKind) {
+			var path []K
+			for p := f; p != nil; p = p.up {
+				path = append(path, p.n.key)
+			}
+			slices.Reverse(path)
+			violations = append(violations, Violation[K]{Kind: kind, Key: n.key, Path: path})
+		}
+
+		// on the way down: check the parent pointer, then walk the left subtree
+		if !f.entered {
+			f.entered = true
+			if visited[n] {
+				report(ViolationCycle)
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			visited[n] = true
+			if f.up != nil && n.parent != f.up.n {
+				report(ViolationParentChild)
+			}
+			if !t.IsNil(n.left) {
+				stack = append(stack, &frame{n: n.left, up: f})
+			}
+			continue
+		}
+
+		// on the way up from the left subtree: check the order and size, then walk the right subtree
+		stack = stack[:len(stack)-1]
+		if prev != nil && ((!t.duplicates && !t.less(prev.key, n.key)) || t.less(n.key, prev.key)) {
+			report(ViolationOutOfOrder)
+		}
+		prev = n
+		if n.size != t.SubtreeSize(n.left)+t.SubtreeSize(n.right)+1 {
+			report(ViolationSize)
+		}
+		if !t.IsNil(n.right) {
+			stack = append(stack, &frame{n: n.right, up: f})
+		}
+	}
+	return violations
+}
+
+// ValidatePath performs the structural validation of Tree.IsTreeValid on the path from node n up to
+// the root only, in O(h) time rather than O(n) time, e.g. to check the path touched by the last
+// operation after every operation of a debug build.
+//
+// For each node on the path, ValidatePath checks that:
+//   - Its children's parent pointers point back to it, and it is a child of its parent.
+//   - Its children's keys are ordered with respect to its own key.
+//   - Its subtree size matches the sizes of its children.
+//   - The keys of the path below it lie on the side of it they descend from.
+//
+// The path must end at the root, whose parent must be the sentinel nil node. Nodes off the path are not
+// checked, so a valid path does not imply a valid tree: Tree.IsTreeValid remains the full check.
+//
+// Example Usage:
+//
+//	n, _ := tree.Insert(key, value)
+//	if err := tree.ValidatePath(n); err != nil {
+//		panic(err)
+//	}
+//
+// Returns:
+//   - nil if the path is valid, or n is the sentinel nil node.
+//   - An error wrapping one of ErrSentinelAltered, ErrParentChildMismatch, ErrOrderViolation or
+//     ErrSubtreeSizeMismatch otherwise.
+func (t *Tree[K, V, M]) ValidatePath(n *Node[K, V, M]) error {
+	if t.nil.parent != t.nil {
+		return ErrSentinelAltered
+	}
+	if t.IsNil(n) {
+		return nil
+	}
+
+	// inOrder reports whether a may precede b in key order
+	inOrder := func(a, b K) bool {
+		return t.less(a, b) || (t.duplicates && !t.less(b, a))
+	}
+
+	// lo and hi are the smallest and largest keys on the path below the current node
+	lo, hi := n.key, n.key
+	for steps := 0; ; steps++ {
+		if steps > t.root.size {
+			return fmt.Errorf("%w: path from node %v does not reach the root", ErrParentChildMismatch, n.key)
+		}
+		if (!t.IsNil(n.left) && n.left.parent != n) || (!t.IsNil(n.right) && n.right.parent != n) {
+			return fmt.Errorf("%w for a child of node: %v", ErrParentChildMismatch, n.key)
+		}
+		if (!t.IsNil(n.left) && !inOrder(n.left.key, n.key)) || (!t.IsNil(n.right) && !inOrder(n.key, n.right.key)) {
+			return fmt.Errorf("%w at a child of node: %v", ErrOrderViolation, n.key)
+		}
+		if n.size != n.left.size+n.right.size+1 {
+			return fmt.Errorf("%w for node: %v", ErrSubtreeSizeMismatch, n.key)
+		}
+
+		p := n.parent
+		if t.IsNil(p) {
+			if n != t.root {
+				return fmt.Errorf("%w: path from node %v does not reach the root", ErrParentChildMismatch, n.key)
+			}
+			return nil
+		}
+		switch {
+		case n == p.left && n != p.right:
+			if !inOrder(hi, p.key) {
+				return fmt.Errorf("%w in the left subtree of node: %v", ErrOrderViolation, p.key)
+			}
+		case n == p.right && n != p.left:
+			if !inOrder(p.key, lo) {
+				return fmt.Errorf("%w in the right subtree of node: %v", ErrOrderViolation, p.key)
+			}
+		default:
+			return fmt.Errorf("%w for node: %v", ErrParentChildMismatch, n.key)
+		}
+		if t.less(p.key, lo) {
+			lo = p.key
+		}
+		if t.less(hi, p.key) {
+			hi = p.key
+		}
+		n = p
+	}
+}
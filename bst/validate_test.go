@@ -0,0 +1,125 @@
+package bst
+
+import (
+	"github.com/stretchr/testify/assert"
+	"runtime/debug"
+	"testing"
+)
+
+func TestTree_ValidateAll(t *testing.T) {
+	newTree := func() (*Tree[int, string, struct{}], map[int]*Node[int, string, struct{}]) {
+		tree := New[int, string, struct{}](func(a, b int) bool { return a < b })
+		nodes := make(map[int]*Node[int, string, struct{}])
+		for _, key := range []int{10, 5, 15, 3, 7} {
+			nodes[key], _ = tree.Insert(key, "")
+		}
+		return tree, nodes
+	}
+
+	tree, _ := newTree()
+	assert.Nil(t, tree.ValidateAll())
+
+	// several violations are reported at once, each with its path
+	tree, nodes := newTree()
+	tree.SetKey(nodes[3], 12)
+	tree.SetParent(nodes[7], nodes[15])
+	violations := tree.ValidateAll()
+	assert.Equal(t, []Violation[int]{
+		{Kind: ViolationOutOfOrder, Key: 5, Path: []int{10, 5}},
+		{Kind: ViolationParentChild, Key: 7, Path: []int{10, 5, 7}},
+	}, violations)
+	assert.EqualError(t, violations[1], "parent/child mismatch at node 7 (path: 10 → 5 → 7)")
+	assert.Error(t, tree.IsTreeValid())
+
+	// sizes are checked against the stored sizes of the children, and the root's parent
+	tree, nodes = newTree()
+	tree.SetRight(nodes[5], tree.Sentinel())
+	tree.SetParent(nodes[10], nodes[3])
+	violations = tree.ValidateAll()
+	assert.Equal(t, []Violation[int]{
+		{Kind: ViolationRootParent},
+		{Kind: ViolationSize, Key: 5, Path: []int{10, 5}},
+	}, violations)
+	assert.EqualError(t, violations[0], "root has parent")
+
+	// cycles are reported without walking them again
+	tree, nodes = newTree()
+	tree.SetRight(nodes[15], nodes[5])
+	assert.Contains(t, tree.ValidateAll(), Violation[int]{Kind: ViolationCycle, Key: 5, Path: []int{10, 15, 5}})
+
+	// the sentinel
+	tree, _ = newTree()
+	tree.SetParent(tree.Sentinel(), tree.Root())
+	assert.Equal(t, []Violation[int]{{Kind: ViolationSentinel}}, tree.ValidateAll())
+}
+
+func TestTree_ValidatePath(t *testing.T) {
+	newTree := func() (*Tree[int, string, struct{}], map[int]*Node[int, string, struct{}]) {
+		tree := New[int, string, struct{}](func(a, b int) bool { return a < b })
+		nodes := make(map[int]*Node[int, string, struct{}])
+		for _, key := range []int{10, 5, 15, 3, 7, 12} {
+			nodes[key], _ = tree.Insert(key, "")
+		}
+		return tree, nodes
+	}
+
+	tree, nodes := newTree()
+	for key, n := range nodes {
+		assert.NoError(t, tree.ValidatePath(n), "expected valid path from node %d", key)
+	}
+	assert.NoError(t, tree.ValidatePath(tree.Sentinel()))
+
+	// a key out of order with a distant ancestor is found from below it
+	tree, nodes = newTree()
+	tree.SetKey(nodes[7], 11)
+	assert.ErrorIs(t, tree.ValidatePath(nodes[7]), ErrOrderViolation)
+	assert.NoError(t, tree.ValidatePath(nodes[12]), "expected nodes off the path not to be checked")
+	tree.SetKey(nodes[3], 6)
+	assert.ErrorIs(t, tree.ValidatePath(nodes[5]), ErrOrderViolation, "expected the children of the path to be checked")
+
+	// parent pointers, sizes and the root
+	tree, nodes = newTree()
+	tree.SetParent(nodes[12], nodes[5])
+	assert.ErrorIs(t, tree.ValidatePath(nodes[12]), ErrParentChildMismatch)
+	assert.ErrorIs(t, tree.ValidatePath(nodes[15]), ErrParentChildMismatch)
+
+	tree, nodes = newTree()
+	tree.SetRight(nodes[5], tree.Sentinel())
+	assert.ErrorIs(t, tree.ValidatePath(nodes[3]), ErrSubtreeSizeMismatch)
+
+	tree, nodes = newTree()
+	tree.SetParent(nodes[10], nodes[3])
+	assert.ErrorIs(t, tree.ValidatePath(nodes[7]), ErrParentChildMismatch, "expected a cycle to be detected")
+
+	// a path from a detached node does not reach the root
+	tree, nodes = newTree()
+	tree.SetParent(nodes[5], tree.Sentinel())
+	assert.ErrorIs(t, tree.ValidatePath(nodes[3]), ErrParentChildMismatch)
+}
+
+func TestTree_IsTreeValid_degenerate(t *testing.T) {
+	// link a chain of right children directly, as inserting sorted keys would, in O(n) time
+	const n = 100_000
+	tree := New[int, string, struct{}](func(a, b int) bool { return a < b })
+	parent := tree.nil
+	for i := 0; i < n; i++ {
+		node := &Node[int, string, struct{}]{key: i, parent: parent, left: tree.nil, right: tree.nil, size: n - i, tree: tree}
+		if tree.IsNil(parent) {
+			tree.root = node
+		} else {
+			parent.right = node
+		}
+		parent = node
+	}
+	tree.refreshBounds()
+
+	// validation must not recurse, which would overflow a small stack
+	defer debug.SetMaxStack(debug.SetMaxStack(1 << 20))
+	assert.NoError(t, tree.IsTreeValid())
+	assert.Nil(t, tree.ValidateAll())
+	assert.Equal(t, n, tree.Height()+1)
+
+	// a cycle is reported rather than walked forever
+	tree.Max(tree.root).right = tree.root
+	assert.ErrorIs(t, tree.IsTreeValid(), ErrSubtreeSizeMismatch)
+}
@@ -0,0 +1,39 @@
+package btree
+
+import (
+	"testing"
+)
+
+// BenchmarkTree_SearchDelete creates a very large tree (10M keys),
+// then deletes items from said tree in the benchmarking loop.
+func BenchmarkTree_SearchDelete(b *testing.B) {
+	tree := New[int, struct{}](func(a, b int) bool {
+		return a < b
+	}, DefaultDegree)
+
+	// create large tree to delete from
+	for i := 0; i <= 10_000_000; i++ {
+		tree.Insert(i, struct{}{})
+	}
+
+	i := 0
+	b.ResetTimer()
+	for b.Loop() {
+		tree.Search(i)
+		tree.Delete(i)
+		i++
+	}
+}
+
+// BenchmarkTree_Insert inserts items into a tree in the benchmarking loop.
+func BenchmarkTree_Insert(b *testing.B) {
+	tree := New[int, struct{}](func(a, b int) bool {
+		return a < b
+	}, DefaultDegree)
+	i := 0
+	b.ResetTimer()
+	for b.Loop() {
+		tree.Insert(i, struct{}{})
+		i++
+	}
+}
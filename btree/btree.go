@@ -0,0 +1,622 @@
+// Package btree provides a generic, in-memory B-Tree implementation.
+//
+// A B-Tree stores many keys per node, so a lookup touches far fewer nodes (and cache lines)
+// than in a binary search tree holding the same data. This makes it well suited to large,
+// in-memory ordered indexes, where pointer chasing dominates the cost of bst.Tree and rbtree.Tree.
+//
+// Keys are ordered with the same LessFunc used by the bst and rbtree packages.
+//
+// # Degree
+//
+// The shape of the tree is controlled by its minimum degree t (see New):
+//   - Every node other than the root holds between t-1 and 2t-1 keys.
+//   - Every internal node with k keys has k+1 children.
+//   - All leaves are at the same depth, so the tree is always balanced.
+//
+// Larger degrees give shallower trees and better cache locality, at the cost of more key
+// comparisons and copying within each node. DefaultDegree is a reasonable starting point.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/btree"
+//
+//	tree := btree.New[int, string](func(a, b int) bool { return a < b }, btree.DefaultDegree)
+//	tree.Insert(10, "ten")
+//	tree.Insert(20, "twenty")
+//	value, found := tree.Search(10)
+//
+//	if found {
+//		tree.Delete(10)
+//	}
+//
+// # Limitations
+//
+// Unlike bst.Tree, nodes are not exposed: keys and values move between nodes as the tree is
+// rebalanced, so entries are accessed by key rather than by node handle.
+// Keys are unique, and the tree is not safe for concurrent use.
+package btree
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/bst"
+	"slices"
+	"sort"
+)
+
+// DefaultDegree is a general-purpose minimum degree for New, giving nodes of up to 63 keys.
+const DefaultDegree = 32
+
+// Tree represents a B-Tree of key-value pairs, ordered by a LessFunc.
+//
+// Trees must be created with New.
+type Tree[K, V any] struct {
+	root   *node[K, V]     // Root node of the tree, nil if the tree is empty.
+	less   bst.LessFunc[K] // Function to compare keys and maintain order.
+	degree int             // Minimum degree of the tree.
+	size   int             // Number of keys in the tree.
+}
+
+// node represents a single node of the B-Tree.
+//
+// values[i] is the value of keys[i]. Leaves have no children. Internal nodes have
+// len(keys)+1 children, where children[i] holds keys between keys[i-1] and keys[i].
+type node[K, V any] struct {
+	keys     []K
+	values   []V
+	children []*node[K, V]
+}
+
+// leaf returns true if the node has no children.
+func (n *node[K, V]) leaf() bool {
+	return len(n.children) == 0
+}
+
+// New creates and returns a new empty B-Tree.
+//
+// Parameters:
+//   - less: A function that defines the ordering of keys.
+//   - degree: The minimum degree of the tree, which must be at least 2. Nodes hold up to 2*degree-1 keys.
+//
+// Returns:
+//   - A pointer to a newly created Tree[K, V] instance.
+//
+// New panics if degree is less than 2.
+func New[K, V any](less bst.LessFunc[K], degree int) *Tree[K, V] {
+	if degree < 2 {
+		panic(fmt.Sprintf("btree: invalid degree %d, must be at least 2", degree))
+	}
+	return &Tree[K, V]{
+		less:   less,
+		degree: degree,
+	}
+}
+
+// Degree returns the minimum degree of the tree.
+func (t *Tree[K, V]) Degree() int {
+	return t.degree
+}
+
+// Size returns the number of keys in the tree.
+//
+// This is an O(1) operation.
+func (t *Tree[K, V]) Size() int {
+	return t.size
+}
+
+// Height returns the number of levels in the tree, or 0 if the tree is empty.
+func (t *Tree[K, V]) Height() int {
+	h := 0
+	for n := t.root; n != nil; h++ {
+		if n.leaf() {
+			return h + 1
+		}
+		n = n.children[0]
+	}
+	return h
+}
+
+// maxKeys returns the maximum number of keys in a node.
+func (t *Tree[K, V]) maxKeys() int {
+	return 2*t.degree - 1
+}
+
+// find returns the index of the first key in n that is not less than key,
+// and whether that key is equal to key.
+func (t *Tree[K, V]) find(n *node[K, V], key K) (int, bool) {
+	i := sort.Search(len(n.keys), func(i int) bool {
+		return !t.less(n.keys[i], key)
+	})
+	return i, i < len(n.keys) && !t.less(key, n.keys[i])
+}
+
+// Search returns the value associated with key.
+//
+// Returns:
+//   - (value, true) if the key exists in the tree.
+//   - (zero value, false) if the key is not found.
+func (t *Tree[K, V]) Search(key K) (V, bool) {
+	for n := t.root; n != nil; {
+		i, found := t.find(n, key)
+		if found {
+			return n.values[i], true
+		}
+		if n.leaf() {
+			break
+		}
+		n = n.children[i]
+	}
+	var zero V
+	return zero, false
+}
+
+// Insert inserts the given key and value into the tree.
+//
+// If the key already exists, its value is updated.
+//
+// Full nodes are split on the way down, so insertion is a single pass from the root
+// to a leaf, in O(t log_t n) time.
+//
+// Returns:
+//   - true if a new key was inserted.
+//   - false if the key existed and its value was updated.
+func (t *Tree[K, V]) Insert(key K, value V) bool {
+	if t.root == nil {
+		t.root = &node[K, V]{keys: []K{key}, values: []V{value}}
+		t.size++
+		return true
+	}
+
+	// grow the tree at the root, so that there is always room for a key split from a child
+	if len(t.root.keys) == t.maxKeys() {
+		t.root = &node[K, V]{children: []*node[K, V]{t.root}}
+		t.splitChild(t.root, 0)
+	}
+
+	n := t.root
+	for {
+		i, found := t.find(n, key)
+		if found {
+			n.values[i] = value
+			return false
+		}
+		if n.leaf() {
+			n.keys = slices.Insert(n.keys, i, key)
+			n.values = slices.Insert(n.values, i, value)
+			t.size++
+			return true
+		}
+		if len(n.children[i].keys) == t.maxKeys() {
+			t.splitChild(n, i)
+
+			// the median of the child is now n.keys[i]
+			switch {
+			case t.less(n.keys[i], key):
+				i++
+			case !t.less(key, n.keys[i]):
+				n.values[i] = value
+				return false
+			}
+		}
+		n = n.children[i]
+	}
+}
+
+// splitChild splits the full child n.children[i] in two, moving its median key up into n.
+func (t *Tree[K, V]) splitChild(n *node[K, V], i int) {
+	left := n.children[i]
+	mid := t.degree - 1
+
+	right := &node[K, V]{
+		keys:   slices.Clone(left.keys[mid+1:]),
+		values: slices.Clone(left.values[mid+1:]),
+	}
+	if !left.leaf() {
+		right.children = slices.Clone(left.children[mid+1:])
+	}
+
+	n.keys = slices.Insert(n.keys, i, left.keys[mid])
+	n.values = slices.Insert(n.values, i, left.values[mid])
+	n.children = slices.Insert(n.children, i+1, right)
+
+	// truncate the left node, clearing the tail so that moved entries can be garbage collected
+	clear(left.keys[mid:])
+	clear(left.values[mid:])
+	left.keys = left.keys[:mid]
+	left.values = left.values[:mid]
+	if !left.leaf() {
+		clear(left.children[mid+1:])
+		left.children = left.children[:mid+1]
+	}
+}
+
+// Delete removes key from the tree.
+//
+// Nodes on the way down are topped up (by borrowing from a sibling, or merging with one) so
+// that they can lose a key, so deletion is a single pass from the root, in O(t log_t n) time.
+//
+// Returns:
+//   - true if the key was found and removed.
+//   - false if the key was not found.
+func (t *Tree[K, V]) Delete(key K) bool {
+	if t.root == nil {
+		return false
+	}
+
+	deleted := t.delete(t.root, key)
+
+	// shrink the tree at the root, if the root has been emptied by a merge or deletion
+	if len(t.root.keys) == 0 {
+		if t.root.leaf() {
+			t.root = nil
+		} else {
+			t.root = t.root.children[0]
+		}
+	}
+
+	if deleted {
+		t.size--
+	}
+	return deleted
+}
+
+// delete removes key from the subtree rooted at n.
+//
+// n must be the root, or hold at least t keys, so that it can lose a key without underflowing.
+func (t *Tree[K, V]) delete(n *node[K, V], key K) bool {
+	for {
+		i, found := t.find(n, key)
+
+		if n.leaf() {
+			if !found {
+				return false
+			}
+			n.keys = slices.Delete(n.keys, i, i+1)
+			n.values = slices.Delete(n.values, i, i+1)
+			return true
+		}
+
+		if found {
+			switch {
+			case len(n.children[i].keys) >= t.degree:
+				// replace the key with its predecessor, then delete the predecessor from the left subtree
+				pred := n.children[i]
+				for !pred.leaf() {
+					pred = pred.children[len(pred.children)-1]
+				}
+				last := len(pred.keys) - 1
+				n.keys[i], n.values[i] = pred.keys[last], pred.values[last]
+				key = pred.keys[last]
+				n = n.children[i]
+
+			case len(n.children[i+1].keys) >= t.degree:
+				// replace the key with its successor, then delete the successor from the right subtree
+				succ := n.children[i+1]
+				for !succ.leaf() {
+					succ = succ.children[0]
+				}
+				n.keys[i], n.values[i] = succ.keys[0], succ.values[0]
+				key = succ.keys[0]
+				n = n.children[i+1]
+
+			default:
+				// both children are minimal: merge them around the key, then delete it from the merged node
+				t.merge(n, i)
+				n = n.children[i]
+			}
+			continue
+		}
+
+		// ensure the child we descend into can lose a key
+		if len(n.children[i].keys) < t.degree {
+			i = t.fill(n, i)
+		}
+		n = n.children[i]
+	}
+}
+
+// fill tops up the minimal child n.children[i], by borrowing a key from a sibling if one can
+// spare it, or merging with a sibling otherwise.
+//
+// Returns the index of the child now holding the keys of n.children[i].
+func (t *Tree[K, V]) fill(n *node[K, V], i int) int {
+	switch {
+	case i > 0 && len(n.children[i-1].keys) >= t.degree:
+		t.borrowFromLeft(n, i)
+		return i
+	case i < len(n.keys) && len(n.children[i+1].keys) >= t.degree:
+		t.borrowFromRight(n, i)
+		return i
+	case i < len(n.keys):
+		t.merge(n, i)
+		return i
+	default:
+		t.merge(n, i-1)
+		return i - 1
+	}
+}
+
+// borrowFromLeft moves the separating key n.keys[i-1] down into n.children[i],
+// and the largest key of n.children[i-1] up to replace it.
+func (t *Tree[K, V]) borrowFromLeft(n *node[K, V], i int) {
+	child, sibling := n.children[i], n.children[i-1]
+	last := len(sibling.keys) - 1
+
+	child.keys = slices.Insert(child.keys, 0, n.keys[i-1])
+	child.values = slices.Insert(child.values, 0, n.values[i-1])
+	n.keys[i-1], n.values[i-1] = sibling.keys[last], sibling.values[last]
+	sibling.keys = slices.Delete(sibling.keys, last, last+1)
+	sibling.values = slices.Delete(sibling.values, last, last+1)
+
+	if !sibling.leaf() {
+		child.children = slices.Insert(child.children, 0, sibling.children[last+1])
+		sibling.children = slices.Delete(sibling.children, last+1, last+2)
+	}
+}
+
+// borrowFromRight moves the separating key n.keys[i] down into n.children[i],
+// and the smallest key of n.children[i+1] up to replace it.
+func (t *Tree[K, V]) borrowFromRight(n *node[K, V], i int) {
+	child, sibling := n.children[i], n.children[i+1]
+
+	child.keys = append(child.keys, n.keys[i])
+	child.values = append(child.values, n.values[i])
+	n.keys[i], n.values[i] = sibling.keys[0], sibling.values[0]
+	sibling.keys = slices.Delete(sibling.keys, 0, 1)
+	sibling.values = slices.Delete(sibling.values, 0, 1)
+
+	if !sibling.leaf() {
+		child.children = append(child.children, sibling.children[0])
+		sibling.children = slices.Delete(sibling.children, 0, 1)
+	}
+}
+
+// merge merges n.children[i+1] and the separating key n.keys[i] into n.children[i].
+func (t *Tree[K, V]) merge(n *node[K, V], i int) {
+	left, right := n.children[i], n.children[i+1]
+
+	left.keys = append(append(left.keys, n.keys[i]), right.keys...)
+	left.values = append(append(left.values, n.values[i]), right.values...)
+	left.children = append(left.children, right.children...)
+
+	n.keys = slices.Delete(n.keys, i, i+1)
+	n.values = slices.Delete(n.values, i, i+1)
+	n.children = slices.Delete(n.children, i+1, i+2)
+}
+
+// Min returns the smallest key in the tree and its value.
+//
+// Returns:
+//   - (key, value, true) if the tree is not empty.
+//   - (zero key, zero value, false) if the tree is empty.
+func (t *Tree[K, V]) Min() (K, V, bool) {
+	if t.root == nil {
+		var k K
+		var v V
+		return k, v, false
+	}
+	n := t.root
+	for !n.leaf() {
+		n = n.children[0]
+	}
+	return n.keys[0], n.values[0], true
+}
+
+// Max returns the largest key in the tree and its value.
+//
+// Returns:
+//   - (key, value, true) if the tree is not empty.
+//   - (zero key, zero value, false) if the tree is empty.
+func (t *Tree[K, V]) Max() (K, V, bool) {
+	if t.root == nil {
+		var k K
+		var v V
+		return k, v, false
+	}
+	n := t.root
+	for !n.leaf() {
+		n = n.children[len(n.children)-1]
+	}
+	last := len(n.keys) - 1
+	return n.keys[last], n.values[last], true
+}
+
+// Floor returns the largest key in the tree less than or equal to key, and its value.
+//
+// Returns:
+//   - (key, value, true) if such a key exists.
+//   - (zero key, zero value, false) otherwise.
+func (t *Tree[K, V]) Floor(key K) (K, V, bool) {
+	var floorKey K
+	var floorValue V
+	ok := false
+	for n := t.root; n != nil; {
+		i, found := t.find(n, key)
+		if found {
+			return n.keys[i], n.values[i], true
+		}
+		// keys[i-1] is the largest key in this node less than key
+		if i > 0 {
+			floorKey, floorValue, ok = n.keys[i-1], n.values[i-1], true
+		}
+		if n.leaf() {
+			break
+		}
+		n = n.children[i]
+	}
+	return floorKey, floorValue, ok
+}
+
+// Ceiling returns the smallest key in the tree greater than or equal to key, and its value.
+//
+// Returns:
+//   - (key, value, true) if such a key exists.
+//   - (zero key, zero value, false) otherwise.
+func (t *Tree[K, V]) Ceiling(key K) (K, V, bool) {
+	var ceilingKey K
+	var ceilingValue V
+	ok := false
+	for n := t.root; n != nil; {
+		i, found := t.find(n, key)
+		if found {
+			return n.keys[i], n.values[i], true
+		}
+		// keys[i] is the smallest key in this node greater than key
+		if i < len(n.keys) {
+			ceilingKey, ceilingValue, ok = n.keys[i], n.values[i], true
+		}
+		if n.leaf() {
+			break
+		}
+		n = n.children[i]
+	}
+	return ceilingKey, ceilingValue, ok
+}
+
+// Ascend calls f for each key and value in ascending key order, until f returns false.
+//
+// The tree must not be modified during iteration.
+func (t *Tree[K, V]) Ascend(f func(key K, value V) bool) {
+	if t.root != nil {
+		t.ascend(t.root, nil, nil, f)
+	}
+}
+
+// AscendRange calls f for each key and value with a key in the half-open interval [lo, hi),
+// in ascending key order, until f returns false.
+//
+// Only the nodes overlapping the interval are visited.
+// The tree must not be modified during iteration.
+func (t *Tree[K, V]) AscendRange(lo, hi K, f func(key K, value V) bool) {
+	if t.root != nil {
+		t.ascend(t.root, &lo, &hi, f)
+	}
+}
+
+// ascend calls f for each key in the subtree rooted at n within [lo, hi) in ascending order,
+// where a nil bound is unbounded. It returns false if iteration was stopped by f.
+//
+// The recursion depth is the height of the tree, which is O(log_t n).
+func (t *Tree[K, V]) ascend(n *node[K, V], lo, hi *K, f func(key K, value V) bool) bool {
+	i := 0
+	if lo != nil {
+		i, _ = t.find(n, *lo)
+	}
+	for ; i <= len(n.keys); i++ {
+		if !n.leaf() && !t.ascend(n.children[i], lo, hi, f) {
+			return false
+		}
+		if i == len(n.keys) {
+			break
+		}
+		if hi != nil && !t.less(n.keys[i], *hi) {
+			return false
+		}
+		if !f(n.keys[i], n.values[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Descend calls f for each key and value in descending key order, until f returns false.
+//
+// The tree must not be modified during iteration.
+func (t *Tree[K, V]) Descend(f func(key K, value V) bool) {
+	if t.root != nil {
+		t.descend(t.root, f)
+	}
+}
+
+// descend calls f for each key in the subtree rooted at n in descending order.
+// It returns false if iteration was stopped by f.
+func (t *Tree[K, V]) descend(n *node[K, V], f func(key K, value V) bool) bool {
+	for i := len(n.keys); i >= 0; i-- {
+		if !n.leaf() && !t.descend(n.children[i], f) {
+			return false
+		}
+		if i == 0 {
+			break
+		}
+		if !f(n.keys[i-1], n.values[i-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsTreeValid checks whether the tree satisfies the B-Tree properties:
+//   - Keys within each node are in strictly ascending order, and lie between the keys
+//     separating the node from its siblings.
+//   - Every node other than the root holds between t-1 and 2t-1 keys, and the root holds at least one key.
+//   - Every internal node with k keys has k+1 children.
+//   - All leaves are at the same depth.
+//   - The number of keys matches Tree.Size.
+//
+// Returns:
+//   - nil if the tree is valid.
+//   - An error describing the first violation found otherwise.
+func (t *Tree[K, V]) IsTreeValid() error {
+	if t.root == nil {
+		if t.size != 0 {
+			return fmt.Errorf("empty tree has size %d", t.size)
+		}
+		return nil
+	}
+	count, leafDepth := 0, -1
+	if err := t.validate(t.root, nil, nil, 0, &leafDepth, &count); err != nil {
+		return err
+	}
+	if count != t.size {
+		return fmt.Errorf("tree holds %d keys, but has size %d", count, t.size)
+	}
+	return nil
+}
+
+// validate checks the subtree rooted at n, whose keys must lie within (lo, hi) where a nil bound is unbounded.
+func (t *Tree[K, V]) validate(n *node[K, V], lo, hi *K, depth int, leafDepth, count *int) error {
+	switch {
+	case len(n.keys) == 0:
+		return fmt.Errorf("node at depth %d has no keys", depth)
+	case len(n.keys) > t.maxKeys():
+		return fmt.Errorf("node at depth %d has %d keys, more than the maximum of %d", depth, len(n.keys), t.maxKeys())
+	case n != t.root && len(n.keys) < t.degree-1:
+		return fmt.Errorf("node at depth %d has %d keys, less than the minimum of %d", depth, len(n.keys), t.degree-1)
+	case len(n.values) != len(n.keys):
+		return fmt.Errorf("node at depth %d has %d keys but %d values", depth, len(n.keys), len(n.values))
+	case !n.leaf() && len(n.children) != len(n.keys)+1:
+		return fmt.Errorf("node at depth %d has %d keys but %d children", depth, len(n.keys), len(n.children))
+	}
+
+	for i, key := range n.keys {
+		if i > 0 && !t.less(n.keys[i-1], key) {
+			return fmt.Errorf("keys %v and %v at depth %d are out of order", n.keys[i-1], key, depth)
+		}
+		if (lo != nil && !t.less(*lo, key)) || (hi != nil && !t.less(key, *hi)) {
+			return fmt.Errorf("key %v at depth %d is outside the range of its parent", key, depth)
+		}
+	}
+	*count += len(n.keys)
+
+	if n.leaf() {
+		if *leafDepth == -1 {
+			*leafDepth = depth
+		} else if *leafDepth != depth {
+			return fmt.Errorf("leaves found at depths %d and %d", *leafDepth, depth)
+		}
+		return nil
+	}
+
+	for i, child := range n.children {
+		childLo, childHi := lo, hi
+		if i > 0 {
+			childLo = &n.keys[i-1]
+		}
+		if i < len(n.keys) {
+			childHi = &n.keys[i]
+		}
+		if err := t.validate(child, childLo, childHi, depth+1, leafDepth, count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+package btree
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+func TestNew_invalidDegree(t *testing.T) {
+	assert.Panics(t, func() { New[int, int](intLess, 1) }, "expected panic for degree < 2")
+	assert.NotPanics(t, func() { New[int, int](intLess, 2) })
+}
+
+func TestTree_InsertSearchDelete(t *testing.T) {
+	for _, degree := range []int{2, 3, 4, DefaultDegree} {
+		tree := New[int, int](intLess, degree)
+		rng := rand.New(rand.NewSource(int64(degree)))
+		expected := make(map[int]int)
+
+		for i := 0; i < 5000; i++ {
+			key := rng.Intn(1000)
+			if rng.Intn(3) == 0 {
+				_, exists := expected[key]
+				assert.Equal(t, exists, tree.Delete(key), "unexpected result deleting %d", key)
+				delete(expected, key)
+			} else {
+				_, exists := expected[key]
+				assert.Equal(t, !exists, tree.Insert(key, i), "unexpected result inserting %d", key)
+				expected[key] = i
+			}
+			if i%100 == 0 {
+				require.NoError(t, tree.IsTreeValid(), "degree %d, iteration %d", degree, i)
+			}
+		}
+		require.NoError(t, tree.IsTreeValid(), "degree %d", degree)
+		assert.Equal(t, len(expected), tree.Size(), "degree %d: unexpected size", degree)
+
+		for key, value := range expected {
+			v, found := tree.Search(key)
+			assert.True(t, found, "expected to find %d", key)
+			assert.Equal(t, value, v, "unexpected value for %d", key)
+		}
+		_, found := tree.Search(-1)
+		assert.False(t, found)
+
+		// delete everything
+		for key := range expected {
+			require.True(t, tree.Delete(key))
+		}
+		require.NoError(t, tree.IsTreeValid())
+		assert.Equal(t, 0, tree.Size())
+		assert.Equal(t, 0, tree.Height())
+		assert.False(t, tree.Delete(1), "expected delete from empty tree to fail")
+	}
+}
+
+func TestTree_Height(t *testing.T) {
+	tree := New[int, struct{}](intLess, 2)
+	assert.Equal(t, 0, tree.Height())
+	for i := 0; i < 3; i++ {
+		tree.Insert(i, struct{}{})
+	}
+	assert.Equal(t, 1, tree.Height(), "expected 3 keys to fit in the root")
+	tree.Insert(3, struct{}{})
+	assert.Equal(t, 2, tree.Height(), "expected root to split")
+	assert.Equal(t, 2, tree.Degree())
+
+	for i := 4; i < 10000; i++ {
+		tree.Insert(i, struct{}{})
+	}
+	assert.LessOrEqual(t, tree.Height(), 14, "expected height of at most log2(n)+1")
+}
+
+func TestTree_MinMaxFloorCeiling(t *testing.T) {
+	tree := New[int, string](intLess, 2)
+
+	_, _, found := tree.Min()
+	assert.False(t, found)
+	_, _, found = tree.Max()
+	assert.False(t, found)
+	_, _, found = tree.Floor(1)
+	assert.False(t, found)
+	_, _, found = tree.Ceiling(1)
+	assert.False(t, found)
+
+	for i := 10; i <= 100; i += 10 {
+		tree.Insert(i, "v")
+	}
+
+	k, _, found := tree.Min()
+	assert.True(t, found)
+	assert.Equal(t, 10, k)
+	k, _, found = tree.Max()
+	assert.True(t, found)
+	assert.Equal(t, 100, k)
+
+	tests := map[string]struct {
+		key                int
+		floor, ceiling     int
+		floorOK, ceilingOK bool
+	}{
+		"below min":  {key: 5, ceiling: 10, ceilingOK: true},
+		"exact":      {key: 40, floor: 40, ceiling: 40, floorOK: true, ceilingOK: true},
+		"between":    {key: 45, floor: 40, ceiling: 50, floorOK: true, ceilingOK: true},
+		"above max":  {key: 105, floor: 100, floorOK: true},
+		"equals min": {key: 10, floor: 10, ceiling: 10, floorOK: true, ceilingOK: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			k, _, ok := tree.Floor(tc.key)
+			assert.Equal(t, tc.floorOK, ok)
+			if ok {
+				assert.Equal(t, tc.floor, k)
+			}
+			k, _, ok = tree.Ceiling(tc.key)
+			assert.Equal(t, tc.ceilingOK, ok)
+			if ok {
+				assert.Equal(t, tc.ceiling, k)
+			}
+		})
+	}
+}
+
+func TestTree_Ascend_Descend(t *testing.T) {
+	tree := New[int, int](intLess, 3)
+	rng := rand.New(rand.NewSource(1))
+	keys := rng.Perm(500)
+	for _, key := range keys {
+		tree.Insert(key, key*2)
+	}
+	sort.Ints(keys)
+
+	var got []int
+	tree.Ascend(func(k, v int) bool {
+		assert.Equal(t, k*2, v)
+		got = append(got, k)
+		return true
+	})
+	assert.Equal(t, keys, got)
+
+	got = nil
+	tree.Descend(func(k, _ int) bool {
+		got = append(got, k)
+		return true
+	})
+	for i, j := 0, len(got)-1; i < j; i, j = i+1, j-1 {
+		got[i], got[j] = got[j], got[i]
+	}
+	assert.Equal(t, keys, got)
+
+	// stopping early
+	got = nil
+	tree.Ascend(func(k, _ int) bool {
+		got = append(got, k)
+		return len(got) < 5
+	})
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, got)
+	got = nil
+	tree.Descend(func(k, _ int) bool {
+		got = append(got, k)
+		return len(got) < 3
+	})
+	assert.Equal(t, []int{499, 498, 497}, got)
+
+	// ranges
+	tests := map[string]struct {
+		lo, hi   int
+		expected []int
+	}{
+		"middle":       {lo: 100, hi: 105, expected: []int{100, 101, 102, 103, 104}},
+		"empty":        {lo: 10, hi: 10, expected: nil},
+		"below":        {lo: -10, hi: 2, expected: []int{0, 1}},
+		"above":        {lo: 498, hi: 1000, expected: []int{498, 499}},
+		"out of range": {lo: 600, hi: 700, expected: nil},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []int
+			tree.AscendRange(tc.lo, tc.hi, func(k, _ int) bool {
+				got = append(got, k)
+				return true
+			})
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+
+	got = nil
+	tree.AscendRange(0, 500, func(k, _ int) bool {
+		got = append(got, k)
+		return len(got) < 2
+	})
+	assert.Equal(t, []int{0, 1}, got, "expected range iteration to stop early")
+}
+
+func TestTree_IsTreeValid(t *testing.T) {
+	tree := New[int, int](intLess, 3)
+	for i := 0; i < 20; i++ {
+		tree.Insert(i, i)
+	}
+	require.NoError(t, tree.IsTreeValid())
+
+	tests := map[string]func(tree *Tree[int, int]){
+		"wrong size": func(tree *Tree[int, int]) { tree.size++ },
+		"out of order": func(tree *Tree[int, int]) {
+			n := tree.root.children[0]
+			n.keys[0], n.keys[1] = n.keys[1], n.keys[0]
+		},
+		"out of range": func(tree *Tree[int, int]) { tree.root.children[0].keys[0] = 1000 },
+		"overfull": func(tree *Tree[int, int]) {
+			n := tree.root.children[0]
+			for len(n.keys) <= tree.maxKeys() {
+				n.keys = append(n.keys, -1)
+				n.values = append(n.values, -1)
+			}
+		},
+		"underfull": func(tree *Tree[int, int]) {
+			n := tree.root.children[len(tree.root.children)-1]
+			for !n.leaf() {
+				n = n.children[0]
+			}
+			n.keys, n.values = nil, nil
+		},
+		"missing child": func(tree *Tree[int, int]) {
+			tree.root.children = tree.root.children[:len(tree.root.children)-1]
+		},
+	}
+	for name, corrupt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tree := New[int, int](intLess, 3)
+			for i := 0; i < 20; i++ {
+				tree.Insert(i, i)
+			}
+			corrupt(tree)
+			assert.Error(t, tree.IsTreeValid())
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package btree_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/btree"
+)
+
+func ExampleTree_AscendRange() {
+
+	// create the tree with integer keys and string values
+	tree := btree.New[int, string](func(a, b int) bool {
+		return a < b
+	}, btree.DefaultDegree)
+
+	// insert some keys in the tree
+	tree.Insert(2, "two")
+	tree.Insert(4, "four")
+	tree.Insert(6, "six")
+	tree.Insert(8, "eight")
+	tree.Insert(10, "ten")
+
+	// find the keys in [4, 10)
+	tree.AscendRange(4, 10, func(key int, value string) bool {
+		fmt.Printf("%d: %s\n", key, value)
+		return true
+	})
+
+	// Output:
+	// 4: four
+	// 6: six
+	// 8: eight
+}
@@ -0,0 +1,195 @@
+// Package codec provides Codec, an interface encoding generic keys, values and metadata as bytes,
+// with ready-made codecs for common types.
+//
+// Codec is used by the serialization features of this module which leave the encoding of keys, values
+// and metadata to the caller, currently bst.Tree.MarshalProto and bst.Tree.UnmarshalProto (and those of
+// the trees extending bst.Tree). The other serialization features rely on the encoder of their format
+// instead: encoding/json for MarshalJSON, encoding/gob for MarshalBinary and the features built on it
+// (SnapshotTo and the trees/treesql package), and the given MarshalFunc for MarshalWith. Deltas (see
+// bst.Tree.DeltaSince) are encoded by the caller, with the encoder of their choice.
+//
+// The codecs for ordered types are order-preserving: for keys a and b, bytes.Compare of their
+// encodings agrees with cmp.Compare(a, b), so that encoded keys can be stored in any byte-ordered store
+// (such as a file of sorted records or a key-value database) without decoding them to compare them.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/codec"
+//
+//	data, err := tree.MarshalProto(bst.ProtoCodec[int64, string, struct{}]{
+//		Key:   codec.Ordered[int64](),
+//		Value: codec.String(),
+//	})
+package codec
+
+import (
+	"cmp"
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Codec encodes values of type T as bytes, and decodes them back.
+//
+// Implementations must be safe for concurrent use, and Decode must not retain data.
+type Codec[T any] interface {
+	// Encode returns the encoding of v.
+	Encode(v T) ([]byte, error)
+
+	// Decode returns the value encoded by data, or an error if data is not a valid encoding.
+	Decode(data []byte) (T, error)
+}
+
+// funcCodec is a Codec implemented by a pair of functions.
+type funcCodec[T any] struct {
+	encode func(v T) ([]byte, error)
+	decode func(data []byte) (T, error)
+}
+
+func (c funcCodec[T]) Encode(v T) ([]byte, error) {
+	return c.encode(v)
+}
+
+func (c funcCodec[T]) Decode(data []byte) (T, error) {
+	return c.decode(data)
+}
+
+// Func returns a Codec encoding values with encode, and decoding them with decode.
+//
+// Example Usage:
+//
+//	c := codec.Func(json.Marshal, func(data []byte) (config, error) {
+//		var cfg config
+//		err := json.Unmarshal(data, &cfg)
+//		return cfg, err
+//	})
+func Func[T any](encode func(v T) ([]byte, error), decode func(data []byte) (T, error)) Codec[T] {
+	return funcCodec[T]{encode: encode, decode: decode}
+}
+
+// String returns a Codec encoding strings as their bytes, which preserves their order.
+func String() Codec[string] {
+	return Ordered[string]()
+}
+
+// Ordered returns an order-preserving Codec for an ordered type (see the package documentation):
+//   - Integers are encoded big-endian in their full width, with the sign bit of signed integers flipped,
+//     so that negative integers sort before positive ones.
+//   - Floats are encoded big-endian in their full width, with the sign bit flipped for positive floats,
+//     and every bit flipped for negative floats. -0 sorts before +0, and every NaN is encoded as zero bits,
+//     sorting before -Inf as with cmp.Compare.
+//   - Strings are encoded as their bytes.
+//
+// Types whose underlying type is ordered, such as time.Duration, are supported.
+func Ordered[T cmp.Ordered]() Codec[T] {
+	var zero T
+	kind := reflect.TypeOf(zero).Kind()
+	size := int(reflect.TypeOf(zero).Size())
+	return funcCodec[T]{
+		encode: func(v T) ([]byte, error) {
+			return encodeOrdered(reflect.ValueOf(v), kind, size), nil
+		},
+		decode: func(data []byte) (T, error) {
+			var v T
+			if kind != reflect.String && len(data) != size {
+				return v, fmt.Errorf("invalid %s encoding: got %d bytes, want %d", kind, len(data), size)
+			}
+			decodeOrdered(reflect.ValueOf(&v).Elem(), kind, data)
+			return v, nil
+		},
+	}
+}
+
+// encodeOrdered returns the order-preserving encoding of v, of the given kind and size in bytes.
+func encodeOrdered(v reflect.Value, kind reflect.Kind, size int) []byte {
+	var u uint64
+	switch kind {
+	case reflect.String:
+		return []byte(v.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		u = uint64(v.Int()) ^ 1<<(size*8-1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u = v.Uint()
+	case reflect.Float32:
+		if f := v.Float(); !math.IsNaN(f) { // NaN is left as zero bits, sorting first
+			u = orderedFloatBits(uint64(math.Float32bits(float32(f))), 32)
+		}
+	case reflect.Float64:
+		if f := v.Float(); !math.IsNaN(f) {
+			u = orderedFloatBits(math.Float64bits(f), 64)
+		}
+	}
+	data := binary.BigEndian.AppendUint64(nil, u)
+	return data[8-size:]
+}
+
+// decodeOrdered sets v, of the given kind, to the value encoded by data (see encodeOrdered).
+func decodeOrdered(v reflect.Value, kind reflect.Kind, data []byte) {
+	if kind == reflect.String {
+		v.SetString(string(data))
+		return
+	}
+	var u uint64
+	for _, b := range data {
+		u = u<<8 | uint64(b)
+	}
+	size := len(data)
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		u ^= 1 << (size*8 - 1)
+		v.SetInt(int64(u<<(64-size*8)) >> (64 - size*8)) // sign-extend
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v.SetUint(u)
+	case reflect.Float32:
+		v.SetFloat(float64(math.Float32frombits(uint32(floatFromOrderedBits(u, 32)))))
+	case reflect.Float64:
+		v.SetFloat(math.Float64frombits(floatFromOrderedBits(u, 64)))
+	}
+}
+
+// orderedFloatBits maps the IEEE 754 bits of a float of the given width to bits that sort in
+// the float's order: the sign bit is flipped for positive floats, and every bit for negative ones.
+func orderedFloatBits(bits uint64, width int) uint64 {
+	sign := uint64(1) << (width - 1)
+	if bits&sign != 0 {
+		return ^bits & (sign<<1 - 1)
+	}
+	return bits | sign
+}
+
+// floatFromOrderedBits reverses orderedFloatBits.
+func floatFromOrderedBits(u uint64, width int) uint64 {
+	sign := uint64(1) << (width - 1)
+	if u&sign != 0 {
+		return u &^ sign
+	}
+	return ^u & (sign<<1 - 1)
+}
+
+// binaryUnmarshaler constrains PT to be a pointer to T implementing encoding.BinaryUnmarshaler,
+// so that Binary can decode into a value of type T.
+type binaryUnmarshaler[T any] interface {
+	*T
+	encoding.BinaryUnmarshaler
+}
+
+// Binary returns a Codec for a type implementing encoding.BinaryMarshaler, and whose pointer
+// implements encoding.BinaryUnmarshaler, such as time.Time or netip.Addr:
+//
+//	c := codec.Binary[time.Time]()
+//
+// The encoding is that of MarshalBinary, which generally does not preserve order.
+func Binary[T encoding.BinaryMarshaler, PT binaryUnmarshaler[T]]() Codec[T] {
+	return funcCodec[T]{
+		encode: func(v T) ([]byte, error) {
+			return v.MarshalBinary()
+		},
+		decode: func(data []byte) (T, error) {
+			var v T
+			err := PT(&v).UnmarshalBinary(data)
+			return v, err
+		},
+	}
+}
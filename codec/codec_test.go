@@ -0,0 +1,110 @@
+package codec
+
+import (
+	"bytes"
+	"cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"math/rand"
+	"net/netip"
+	"slices"
+	"testing"
+	"time"
+)
+
+// checkOrdered checks that the codec round-trips every value, and that the encodings sort in the values' order.
+func checkOrdered[T cmp.Ordered](t *testing.T, values []T) {
+	t.Helper()
+	c := Ordered[T]()
+	slices.SortFunc(values, cmp.Compare[T])
+	var prev []byte
+	for i, v := range values {
+		data, err := c.Encode(v)
+		require.NoError(t, err)
+		got, err := c.Decode(data)
+		require.NoError(t, err)
+		if v == v { // NaN is not equal to itself
+			assert.Equal(t, v, got, "unexpected round trip of %v", v)
+		} else {
+			assert.NotEqual(t, got, got, "expected NaN to round trip")
+		}
+		if i > 0 {
+			assert.Equal(t, cmp.Compare(values[i-1], v), bytes.Compare(prev, data),
+				"encodings of %v and %v do not sort in order", values[i-1], v)
+		}
+		prev = data
+	}
+}
+
+func TestOrdered(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	ints := []int64{math.MinInt64, -1 << 40, -256, -255, -1, 0, 1, 255, 256, 1 << 40, math.MaxInt64}
+	for i := 0; i < 100; i++ {
+		ints = append(ints, rng.Int63()-rng.Int63())
+	}
+	checkOrdered(t, ints)
+	checkOrdered(t, []int8{math.MinInt8, -1, 0, 1, math.MaxInt8})
+	checkOrdered(t, []int{math.MinInt, -1, 0, 1, math.MaxInt})
+	checkOrdered(t, []uint16{0, 1, 255, 256, math.MaxUint16})
+	checkOrdered(t, []uint64{0, 1, 1 << 63, math.MaxUint64})
+
+	floats := []float64{math.Inf(-1), -math.MaxFloat64, -1.5, -math.SmallestNonzeroFloat64, 0,
+		math.SmallestNonzeroFloat64, 1.5, math.MaxFloat64, math.Inf(1), math.NaN()}
+	for i := 0; i < 100; i++ {
+		floats = append(floats, rng.NormFloat64()*1e6)
+	}
+	checkOrdered(t, floats)
+	checkOrdered(t, []float32{float32(math.Inf(-1)), -1.5, 0, 1.5, float32(math.Inf(1))})
+
+	checkOrdered(t, []string{"", "a", "ab", "b", "日本"})
+	checkOrdered(t, []time.Duration{-time.Hour, 0, time.Second, time.Hour})
+
+	// negative zero sorts before positive zero
+	c := Ordered[float64]()
+	neg, _ := c.Encode(math.Copysign(0, -1))
+	pos, _ := c.Encode(0)
+	assert.Equal(t, -1, bytes.Compare(neg, pos))
+
+	_, err := Ordered[int32]().Decode([]byte{1, 2})
+	assert.Error(t, err, "expected error for an encoding of the wrong size")
+}
+
+func TestString(t *testing.T) {
+	c := String()
+	data, err := c.Encode("hello")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello"), data)
+	s, err := c.Decode(data)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", s)
+}
+
+func TestBinary(t *testing.T) {
+	c := Binary[netip.Addr]()
+	addr := netip.MustParseAddr("192.0.2.1")
+	data, err := c.Encode(addr)
+	require.NoError(t, err)
+	got, err := c.Decode(data)
+	require.NoError(t, err)
+	assert.Equal(t, addr, got)
+
+	_, err = Binary[time.Time]().Decode([]byte("garbage"))
+	assert.Error(t, err)
+}
+
+func TestFunc(t *testing.T) {
+	c := Func(func(v bool) ([]byte, error) {
+		if v {
+			return []byte{1}, nil
+		}
+		return []byte{0}, nil
+	}, func(data []byte) (bool, error) {
+		return data[0] == 1, nil
+	})
+	data, err := c.Encode(true)
+	require.NoError(t, err)
+	v, err := c.Decode(data)
+	require.NoError(t, err)
+	assert.True(t, v)
+}
@@ -0,0 +1,33 @@
+package codec_test
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/mikenye/gotrees/codec"
+	"slices"
+)
+
+func ExampleOrdered() {
+
+	// encode some integers, including negative ones
+	c := codec.Ordered[int16]()
+	var encoded [][]byte
+	for _, v := range []int16{300, -2, 7, -300, 0} {
+		data, _ := c.Encode(v)
+		encoded = append(encoded, data)
+	}
+
+	// the encodings sort in the same order as the integers
+	slices.SortFunc(encoded, bytes.Compare)
+	for _, data := range encoded {
+		v, _ := c.Decode(data)
+		fmt.Printf("% x => %d\n", data, v)
+	}
+
+	// Output:
+	// 7e d4 => -300
+	// 7f fe => -2
+	// 80 00 => 0
+	// 80 07 => 7
+	// 81 2c => 300
+}
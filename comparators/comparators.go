@@ -0,0 +1,153 @@
+// Package comparators provides ready-made comparison functions (bst.LessFunc) for common key types,
+// and combinators to build comparators for multi-field keys.
+//
+// Every comparator defines a strict weak ordering, as required by the trees of this module
+// (see bst.CheckLess), including for the cases that hand-written comparators commonly get wrong:
+//   - Float orders NaN keys before or after every other key, rather than making them equivalent to all keys.
+//   - FoldString compares strings case-insensitively, consistently with strings.EqualFold.
+//   - Composite compares keys field by field, moving on to the next field only for equivalent keys.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/comparators"
+//
+//	tree := rbtree.New[float64, string](comparators.Float[float64](comparators.NaNLast))
+//
+//	type name struct{ last, first string }
+//	byName := comparators.Composite(
+//		comparators.By(func(n name) string { return n.last }, comparators.FoldString),
+//		comparators.By(func(n name) string { return n.first }, comparators.FoldString),
+//	)
+package comparators
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"github.com/mikenye/gotrees/bst"
+	"math"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NaNPolicy determines how Float orders NaN keys.
+type NaNPolicy int
+
+const (
+	NaNFirst NaNPolicy = iota // NaN keys are equivalent to each other, and less than every other key
+	NaNLast                   // NaN keys are equivalent to each other, and greater than every other key
+	NaNPanic                  // comparing a NaN key panics
+)
+
+// Float returns a comparator ordering floating-point keys numerically, with NaN keys ordered
+// according to nan. As with the < operator, -0 and +0 are equivalent.
+//
+// Float panics if nan is not a valid NaNPolicy.
+//
+// Returns:
+//   - A bst.LessFunc defining a total order over all floating-point values (up to the sign of zero).
+func Float[F ~float32 | ~float64](nan NaNPolicy) bst.LessFunc[F] {
+	switch nan {
+	case NaNFirst:
+		return func(a, b F) bool {
+			return a < b || (isNaN(a) && !isNaN(b))
+		}
+	case NaNLast:
+		return func(a, b F) bool {
+			return a < b || (!isNaN(a) && isNaN(b))
+		}
+	case NaNPanic:
+		return func(a, b F) bool {
+			if isNaN(a) || isNaN(b) {
+				panic(fmt.Sprintf("comparators: NaN key compared with %v and %v", a, b))
+			}
+			return a < b
+		}
+	}
+	panic(fmt.Sprintf("comparators: invalid NaN policy: %d", nan))
+}
+
+// isNaN reports whether f is NaN.
+func isNaN[F ~float32 | ~float64](f F) bool {
+	return math.IsNaN(float64(f))
+}
+
+// Ordered orders keys of any ordered type with cmp.Less, so that NaN keys are ordered before
+// every other key. It can be used wherever a bst.LessFunc is expected.
+func Ordered[T cmp.Ordered](a, b T) bool {
+	return cmp.Less(a, b)
+}
+
+// FoldString orders strings case-insensitively, comparing them rune by rune under Unicode simple
+// case folding, so that strings equal under strings.EqualFold are equivalent. Strings are not
+// normalized, and invalid UTF-8 sequences are compared as utf8.RuneError.
+//
+// Each rune is compared by the smallest code point it folds to, so for instance "a" and "A"
+// (which fold to 'A') sort before "_".
+func FoldString(a, b string) bool {
+	for a != "" && b != "" {
+		ra, na := utf8.DecodeRuneInString(a)
+		rb, nb := utf8.DecodeRuneInString(b)
+		if fa, fb := foldRune(ra), foldRune(rb); fa != fb {
+			return fa < fb
+		}
+		a, b = a[na:], b[nb:]
+	}
+	return a == "" && b != ""
+}
+
+// foldRune returns the smallest code point equivalent to r under simple case folding.
+func foldRune(r rune) rune {
+	smallest := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		smallest = min(smallest, f)
+	}
+	return smallest
+}
+
+// Time orders instants chronologically with time.Time.Before, regardless of their location,
+// so that the same instant in different time zones is equivalent.
+func Time(a, b time.Time) bool {
+	return a.Before(b)
+}
+
+// Bytes orders byte slices lexicographically with bytes.Compare. A nil slice is equivalent to an empty slice.
+func Bytes(a, b []byte) bool {
+	return bytes.Compare(a, b) < 0
+}
+
+// Reverse returns a comparator ordering keys in the opposite order to less.
+func Reverse[K any](less bst.LessFunc[K]) bst.LessFunc[K] {
+	return func(a, b K) bool {
+		return less(b, a)
+	}
+}
+
+// By returns a comparator ordering keys by one of their fields (or any value derived from them),
+// extracted by field and compared with less.
+func By[K, F any](field func(key K) F, less bst.LessFunc[F]) bst.LessFunc[K] {
+	return func(a, b K) bool {
+		return less(field(a), field(b))
+	}
+}
+
+// Composite returns a comparator ordering keys lexicographically by a sequence of comparators:
+// keys are ordered by the first comparator under which they are not equivalent, and are
+// equivalent if they are equivalent under all of them.
+//
+// Composite is typically used with By, to order multi-field keys. If every comparator defines
+// a strict weak ordering, so does the comparator returned.
+func Composite[K any](less ...bst.LessFunc[K]) bst.LessFunc[K] {
+	return func(a, b K) bool {
+		for _, l := range less {
+			if l(a, b) {
+				return true
+			}
+			if l(b, a) {
+				return false
+			}
+		}
+		return false
+	}
+}
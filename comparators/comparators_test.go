@@ -0,0 +1,133 @@
+package comparators
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var floats = []float64{math.NaN(), math.Inf(-1), -1.5, math.Copysign(0, -1), 0, 1, math.Inf(1), math.NaN()}
+
+func TestFloat(t *testing.T) {
+	for _, nan := range []NaNPolicy{NaNFirst, NaNLast} {
+		less := Float[float64](nan)
+		assert.NoError(t, bst.CheckLess(less, floats))
+
+		sorted := slices.Clone(floats)
+		slices.SortFunc(sorted, func(a, b float64) int {
+			if less(a, b) {
+				return -1
+			}
+			if less(b, a) {
+				return 1
+			}
+			return 0
+		})
+		finite := sorted[:6]
+		if nan == NaNFirst {
+			assert.True(t, math.IsNaN(sorted[0]) && math.IsNaN(sorted[1]))
+			finite = sorted[2:]
+		} else {
+			assert.True(t, math.IsNaN(sorted[6]) && math.IsNaN(sorted[7]))
+		}
+		assert.True(t, slices.IsSorted(finite), "%v", finite)
+	}
+
+	type celsius float32
+	less := Float[celsius](NaNLast)
+	assert.True(t, less(-40, celsius(math.NaN())))
+	assert.False(t, less(celsius(math.NaN()), -40))
+
+	less = Float[celsius](NaNPanic)
+	assert.True(t, less(-40, 0))
+	assert.Panics(t, func() { less(0, celsius(math.NaN())) })
+	assert.Panics(t, func() { Float[float64](NaNPolicy(3)) })
+}
+
+func TestOrdered(t *testing.T) {
+	assert.NoError(t, bst.CheckLess(Ordered[float64], floats))
+	assert.NoError(t, bst.CheckLess(Ordered[string], []string{"", "a", "B", "b", "ab"}))
+	assert.True(t, Ordered(math.NaN(), math.Inf(-1)))
+}
+
+func TestFoldString(t *testing.T) {
+	samples := []string{"", "a", "A", "b", "B", "ab", "AB", "aB", "_", "é", "É", "straße", "STRASSE", "K", "K", "\xff"}
+	assert.NoError(t, bst.CheckLess(FoldString, samples))
+
+	// equivalence agrees with strings.EqualFold
+	for _, a := range samples {
+		for _, b := range samples {
+			equivalent := !FoldString(a, b) && !FoldString(b, a)
+			assert.Equal(t, strings.EqualFold(a, b), equivalent, "%q and %q", a, b)
+		}
+	}
+	assert.True(t, FoldString("apple", "Banana"))
+	assert.True(t, FoldString("a", "_"))
+	assert.True(t, FoldString("ab", "ABC"))
+	assert.False(t, FoldString("ABC", "ab"))
+}
+
+func TestTime(t *testing.T) {
+	utc := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tokyo := utc.In(time.FixedZone("JST", 9*60*60))
+	samples := []time.Time{{}, utc, tokyo, utc.Add(-time.Nanosecond), utc.Add(time.Hour)}
+	assert.NoError(t, bst.CheckLess(Time, samples))
+	assert.False(t, Time(utc, tokyo) || Time(tokyo, utc), "the same instant in two locations should be equivalent")
+	assert.True(t, Time(utc, utc.Add(time.Nanosecond)))
+}
+
+func TestBytes(t *testing.T) {
+	samples := [][]byte{nil, {}, {0}, {0, 0}, {1}, {0xff}, []byte("ab")}
+	assert.NoError(t, bst.CheckLess(Bytes, samples))
+	assert.False(t, Bytes(nil, []byte{}))
+	assert.True(t, Bytes([]byte{0}, []byte{0, 0}))
+}
+
+func TestReverse(t *testing.T) {
+	less := Reverse(Ordered[int])
+	assert.True(t, less(2, 1))
+	assert.False(t, less(1, 2))
+	assert.False(t, less(1, 1))
+}
+
+func TestComposite(t *testing.T) {
+	type name struct {
+		last, first string
+		age         int
+	}
+	less := Composite(
+		By(func(n name) string { return n.last }, FoldString),
+		By(func(n name) string { return n.first }, FoldString),
+		By(func(n name) int { return n.age }, Reverse(Ordered[int])),
+	)
+	names := []name{
+		{"smith", "john", 30},
+		{"Smith", "Jane", 40},
+		{"Jones", "Zoe", 20},
+		{"smith", "JOHN", 50},
+		{"Smith", "John", 30},
+	}
+	assert.NoError(t, bst.CheckLess(less, names))
+
+	tree := bst.New[name, struct{}, struct{}](less, bst.WithDuplicateKeys())
+	for _, n := range names {
+		tree.Insert(n, struct{}{})
+	}
+	var sorted []name
+	for n := tree.Min(tree.Root()); !tree.IsNil(n); n = tree.Successor(n) {
+		sorted = append(sorted, tree.Key(n))
+	}
+	assert.Equal(t, []name{
+		{"Jones", "Zoe", 20},
+		{"Smith", "Jane", 40},
+		{"smith", "JOHN", 50},
+		{"smith", "john", 30},
+		{"Smith", "John", 30},
+	}, sorted)
+
+	assert.False(t, Composite[int]()(1, 2), "no comparators should make all keys equivalent")
+}
@@ -0,0 +1,49 @@
+package comparators_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/comparators"
+	"github.com/mikenye/gotrees/rbtree"
+	"math"
+)
+
+func ExampleFloat() {
+	tree := rbtree.New[float64, string](comparators.Float[float64](comparators.NaNLast))
+	tree.Insert(math.NaN(), "unknown")
+	tree.Insert(2.5, "b")
+	tree.Insert(-1, "a")
+
+	for n := tree.Min(tree.Root()); !tree.IsNil(n); n = tree.Successor(n) {
+		fmt.Println(tree.Key(n), tree.Value(n))
+	}
+
+	// Output:
+	// -1 a
+	// 2.5 b
+	// NaN unknown
+}
+
+func ExampleComposite() {
+	type name struct {
+		last, first string
+	}
+	less := comparators.Composite(
+		comparators.By(func(n name) string { return n.last }, comparators.FoldString),
+		comparators.By(func(n name) string { return n.first }, comparators.FoldString),
+	)
+
+	tree := rbtree.New[name, int](less)
+	tree.Insert(name{"smith", "john"}, 1)
+	tree.Insert(name{"Jones", "Zoe"}, 2)
+	tree.Insert(name{"Smith", "Jane"}, 3)
+	tree.Insert(name{"SMITH", "JOHN"}, 4) // equivalent to the first name, so updates its value
+
+	for n := tree.Min(tree.Root()); !tree.IsNil(n); n = tree.Successor(n) {
+		fmt.Println(tree.Key(n), tree.Value(n))
+	}
+
+	// Output:
+	// {Jones Zoe} 2
+	// {Smith Jane} 3
+	// {smith john} 4
+}
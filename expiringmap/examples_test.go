@@ -0,0 +1,37 @@
+package expiringmap_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/expiringmap"
+	"time"
+)
+
+func ExampleMap_ExpireBefore() {
+
+	// create a map of session tokens to user names
+	sessions := expiringmap.New[string, string](func(a, b string) bool {
+		return a < b
+	})
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	sessions.Set("token-a", "alice", start.Add(15*time.Minute))
+	sessions.Set("token-b", "bob", start.Add(30*time.Minute))
+	sessions.Set("token-c", "carol", start.Add(45*time.Minute))
+
+	// bob logs in again, extending his session
+	sessions.Set("token-b", "bob", start.Add(60*time.Minute))
+
+	// remove the sessions that expired by 9:45
+	removed := sessions.ExpireBefore(start.Add(45 * time.Minute))
+	fmt.Println("removed", removed, "sessions")
+
+	for _, token := range []string{"token-a", "token-b", "token-c"} {
+		expires, ok := sessions.Expiry(token)
+		fmt.Println(token, ok, expires.Format("15:04"))
+	}
+
+	// Output:
+	// removed 2 sessions
+	// token-a false 00:00
+	// token-b true 10:00
+	// token-c false 00:00
+}
@@ -0,0 +1,219 @@
+// Package expiringmap provides a generic ordered map whose entries expire at a given time.
+//
+// Map keeps two Red-Black Trees in step: a primary tree ordered by key, and a secondary tree
+// ordered by expiry time. Each entry holds handles to its nodes in both trees, so Set, Delete and
+// expiry update both trees together in O(log n) time, and expired entries are found in order
+// without scanning the map:
+//   - Map.ExpireBefore removes every entry expiring at or before a given time.
+//   - Map.StartSweeper removes expired entries periodically, from a background goroutine.
+//
+// Entries that have expired but not yet been removed are not returned by Map.Get.
+//
+// Unlike the other packages of this module, Map is safe for concurrent use, as the
+// sweeper runs concurrently with its callers.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/expiringmap"
+//
+//	sessions := expiringmap.New[string, int](func(a, b string) bool { return a < b })
+//	sessions.Set("alice", 1, time.Now().Add(30*time.Minute))
+//	stop := sessions.StartSweeper(time.Minute)
+//	defer stop()
+//
+//	id, ok := sessions.Get("alice")
+package expiringmap
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"github.com/mikenye/gotrees/rbtree"
+	"sync"
+	"time"
+)
+
+// expiryKey orders the nodes of the secondary tree by expiry time, then by insertion.
+type expiryKey struct {
+	at  time.Time
+	seq uint64
+}
+
+// entry is the value held by the nodes of an entry in both trees.
+type entry[K, V any] struct {
+	value   V
+	expires time.Time
+	key     *bst.Node[K, *entry[K, V], rbtree.Color]         // node of the entry in the primary tree
+	expiry  *bst.Node[expiryKey, *entry[K, V], rbtree.Color] // node of the entry in the secondary tree
+}
+
+// Map represents an ordered map from keys of type K to values of type V, whose entries expire.
+//
+// Maps must be created with New.
+type Map[K, V any] struct {
+	mu     sync.Mutex                            // Guards every field below
+	keys   *rbtree.Tree[K, *entry[K, V]]         // Primary tree, ordered by key
+	expiry *rbtree.Tree[expiryKey, *entry[K, V]] // Secondary tree, ordered by expiry
+	seq    uint64                                // Insertion order of the next expiry
+	now    func() time.Time                      // Current time, replaced in tests
+}
+
+// New creates and returns a new empty map.
+//
+// Parameters:
+//   - less: A function that defines the ordering of keys.
+//
+// Returns:
+//   - A pointer to a newly created Map[K, V] instance.
+func New[K, V any](less bst.LessFunc[K]) *Map[K, V] {
+	return &Map[K, V]{
+		keys: rbtree.New[K, *entry[K, V]](less),
+		expiry: rbtree.New[expiryKey, *entry[K, V]](func(a, b expiryKey) bool {
+			if !a.at.Equal(b.at) {
+				return a.at.Before(b.at)
+			}
+			return a.seq < b.seq
+		}),
+		now: time.Now,
+	}
+}
+
+// Len returns the number of entries in the map, including expired entries not yet removed.
+//
+// This is an O(1) operation.
+func (m *Map[K, V]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.keys.Size()
+}
+
+// Set associates value with key until the given expiry time, replacing any existing value and expiry.
+func (m *Map[K, V]) Set(key K, value V, expires time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var e *entry[K, V]
+	if n, found := m.keys.Search(key); found {
+		e = m.keys.Value(n)
+		m.expiry.Delete(e.expiry)
+	} else {
+		e = &entry[K, V]{}
+		e.key, _ = m.keys.Insert(key, e)
+	}
+	e.value, e.expires = value, expires
+	e.expiry, _ = m.expiry.Insert(expiryKey{at: expires, seq: m.seq}, e)
+	m.seq++
+}
+
+// Get returns the value associated with key.
+//
+// Returns:
+//   - (value, true) if the key exists in the map and has not expired.
+//   - (zero value, false) otherwise.
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if n, found := m.keys.Search(key); found {
+		if e := m.keys.Value(n); m.now().Before(e.expires) {
+			return e.value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Expiry returns the time at which key expires.
+//
+// Returns:
+//   - (expiry time, true) if the key exists in the map, even if it has expired.
+//   - (zero time, false) if the key is not found.
+func (m *Map[K, V]) Expiry(key K) (time.Time, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if n, found := m.keys.Search(key); found {
+		return m.keys.Value(n).expires, true
+	}
+	return time.Time{}, false
+}
+
+// Delete removes key from the map.
+//
+// Returns:
+//   - true if the key was found and removed.
+//   - false if the key was not found.
+func (m *Map[K, V]) Delete(key K) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, found := m.keys.Search(key)
+	if !found {
+		return false
+	}
+	m.remove(m.keys.Value(n))
+	return true
+}
+
+// remove unlinks e from both trees.
+func (m *Map[K, V]) remove(e *entry[K, V]) {
+	m.expiry.Delete(e.expiry)
+	m.keys.Delete(e.key)
+}
+
+// ExpireBefore removes every entry expiring at or before t, in O(k log n) time for k removed entries.
+//
+// Returns:
+//   - The number of entries removed.
+func (m *Map[K, V]) ExpireBefore(t time.Time) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	count := 0
+	for {
+		x := m.expiry.Min(m.expiry.Root())
+		if m.expiry.IsNil(x) || m.expiry.Key(x).at.After(t) {
+			return count
+		}
+		m.remove(m.expiry.Value(x))
+		count++
+	}
+}
+
+// Range calls f for each unexpired key and value in ascending key order, until f returns false.
+//
+// The map is locked during iteration, so f must not call other methods of the map.
+func (m *Map[K, V]) Range(f func(key K, value V) bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	for n := m.keys.Min(m.keys.Root()); !m.keys.IsNil(n); n = m.keys.Successor(n) {
+		if e := m.keys.Value(n); now.Before(e.expires) && !f(m.keys.Key(n), e.value) {
+			return
+		}
+	}
+}
+
+// StartSweeper starts a goroutine removing expired entries every interval, as if by calling
+// ExpireBefore with the current time.
+//
+// Returns:
+//   - A function stopping the sweeper. It waits for a sweep in progress to finish,
+//     and may be called more than once.
+func (m *Map[K, V]) StartSweeper(interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.ExpireBefore(m.now())
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+		<-stopped
+	}
+}
@@ -0,0 +1,180 @@
+package expiringmap
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+// checkMap validates both trees of m, and that they hold the same entries.
+func checkMap[K, V any](t *testing.T, m *Map[K, V]) {
+	t.Helper()
+	require.NoError(t, m.keys.IsTreeValid())
+	require.NoError(t, m.expiry.IsTreeValid())
+	require.Equal(t, m.keys.Size(), m.expiry.Size())
+	for n := m.keys.Min(m.keys.Root()); !m.keys.IsNil(n); n = m.keys.Successor(n) {
+		e := m.keys.Value(n)
+		require.Same(t, n, e.key)
+		require.True(t, m.expiry.Contains(e.expiry))
+		require.Same(t, e, m.expiry.Value(e.expiry))
+		require.True(t, m.expiry.Key(e.expiry).at.Equal(e.expires))
+	}
+}
+
+func TestMap(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := base
+	m := New[int, int](intLess)
+	m.now = func() time.Time { return now }
+
+	type entry struct {
+		value   int
+		expires time.Time
+	}
+	model := make(map[int]entry)
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 10000; i++ {
+		key := rng.Intn(200)
+		switch rng.Intn(6) {
+		case 0, 1, 2:
+			expires := now.Add(time.Duration(rng.Intn(100)) * time.Second)
+			m.Set(key, i, expires)
+			model[key] = entry{i, expires}
+		case 3:
+			_, found := model[key]
+			assert.Equal(t, found, m.Delete(key))
+			delete(model, key)
+		case 4:
+			now = now.Add(time.Duration(rng.Intn(10)) * time.Second)
+			expected := 0
+			for k, e := range model {
+				if !e.expires.After(now) {
+					delete(model, k)
+					expected++
+				}
+			}
+			assert.Equal(t, expected, m.ExpireBefore(now))
+		case 5:
+			value, ok := m.Get(key)
+			e, found := model[key]
+			if found && now.Before(e.expires) {
+				assert.True(t, ok)
+				assert.Equal(t, e.value, value)
+			} else {
+				assert.False(t, ok)
+			}
+			expires, ok := m.Expiry(key)
+			assert.Equal(t, found, ok)
+			assert.True(t, e.expires.Equal(expires))
+		}
+		require.Equal(t, len(model), m.Len())
+		if i%100 == 0 {
+			checkMap(t, m)
+		}
+	}
+	checkMap(t, m)
+
+	// Range visits unexpired entries in key order
+	var expected, keys []int
+	for k, e := range model {
+		if now.Before(e.expires) {
+			expected = append(expected, k)
+		}
+	}
+	sort.Ints(expected)
+	m.Range(func(key, value int) bool {
+		keys = append(keys, key)
+		assert.Equal(t, model[key].value, value)
+		return true
+	})
+	assert.Equal(t, expected, keys)
+}
+
+func TestMap_ExpireBeforeEqualTimes(t *testing.T) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := New[int, string](intLess)
+	m.Set(3, "c", at)
+	m.Set(1, "a", at)
+	m.Set(2, "b", at.Add(time.Second))
+
+	assert.Equal(t, 0, m.ExpireBefore(at.Add(-time.Nanosecond)))
+	assert.Equal(t, 2, m.ExpireBefore(at))
+	assert.Equal(t, 1, m.Len())
+	checkMap(t, m)
+
+	// updating an entry moves its expiry
+	m.Set(2, "b", at.Add(time.Hour))
+	assert.Equal(t, 0, m.ExpireBefore(at.Add(time.Minute)))
+	expires, ok := m.Expiry(2)
+	assert.True(t, ok)
+	assert.Equal(t, at.Add(time.Hour), expires)
+	checkMap(t, m)
+}
+
+func TestMap_Range_Stop(t *testing.T) {
+	m := New[int, int](intLess)
+	m.Range(func(key, value int) bool {
+		t.Fatal("Range called f on an empty map")
+		return true
+	})
+
+	future := time.Now().Add(time.Hour)
+	for i := 0; i < 5; i++ {
+		m.Set(i, i*i, future)
+	}
+	var keys []int
+	m.Range(func(key, value int) bool {
+		keys = append(keys, key)
+		return key < 2
+	})
+	assert.Equal(t, []int{0, 1, 2}, keys)
+}
+
+func TestMap_StartSweeper(t *testing.T) {
+	m := New[int, int](intLess)
+	m.Set(1, 1, time.Now().Add(-time.Second))
+	m.Set(2, 2, time.Now().Add(time.Hour))
+
+	stop := m.StartSweeper(time.Millisecond)
+	defer stop()
+	require.Eventually(t, func() bool {
+		return m.Len() == 1
+	}, 5*time.Second, time.Millisecond)
+
+	_, ok := m.Get(2)
+	assert.True(t, ok)
+	stop()
+	stop()
+}
+
+func TestMap_Concurrent(t *testing.T) {
+	m := New[int, int](intLess)
+	stop := m.StartSweeper(time.Microsecond)
+	defer stop()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := g*1000 + i
+				m.Set(key, i, time.Now().Add(time.Duration(i%3)*time.Millisecond))
+				m.Get(key)
+				if i%5 == 0 {
+					m.Delete(key)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	stop()
+	checkMap(t, m)
+}
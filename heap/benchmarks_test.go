@@ -0,0 +1,35 @@
+package heap
+
+import (
+	"testing"
+)
+
+// BenchmarkBinary_PushPop pushes and pops items on a heap (1K items) in the benchmarking loop.
+func BenchmarkBinary_PushPop(b *testing.B) {
+	h := NewBinary[int](intLess)
+	for i := 0; i < 1000; i++ {
+		h.Push(i)
+	}
+	i := 0
+	b.ResetTimer()
+	for b.Loop() {
+		h.Push(i % 2000)
+		h.Pop()
+		i++
+	}
+}
+
+// BenchmarkPairing_PushPop pushes and pops items on a heap (1K items) in the benchmarking loop.
+func BenchmarkPairing_PushPop(b *testing.B) {
+	h := NewPairing[int](intLess)
+	for i := 0; i < 1000; i++ {
+		h.Push(i)
+	}
+	i := 0
+	b.ResetTimer()
+	for b.Loop() {
+		h.Push(i % 2000)
+		h.Pop()
+		i++
+	}
+}
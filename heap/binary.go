@@ -0,0 +1,131 @@
+// Package heap provides generic priority queues: a binary heap and a pairing heap.
+//
+// Both heaps order their elements with the same LessFunc used by the bst and rbtree packages,
+// and pop the smallest element first. For a max-heap, reverse the comparison.
+//
+// Two heaps are provided:
+//   - Binary is an array-based binary heap, with O(log n) pushes and pops and good cache locality.
+//   - Pairing is a pointer-based pairing heap, with O(1) pushes, O(log n) amortized pops, and
+//     O(1) melding of two heaps with Pairing.Meld.
+//
+// Unlike container/heap, no interface needs to be implemented: elements are stored directly.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/heap"
+//
+//	h := heap.NewBinary[int](func(a, b int) bool { return a < b })
+//	h.Push(5)
+//	h.Push(2)
+//	h.Push(8)
+//	smallest, ok := h.Pop() // 2, true
+//
+// # Limitations
+//
+// The heaps are not safe for concurrent use.
+package heap
+
+import "github.com/mikenye/gotrees/bst"
+
+// Binary represents a binary min-heap of elements ordered by a LessFunc.
+//
+// It is stored as a slice, where the children of element i are elements 2i+1 and 2i+2.
+// Heaps must be created with NewBinary.
+type Binary[T any] struct {
+	items []T             // elements, in heap order
+	less  bst.LessFunc[T] // Function to compare elements and maintain order.
+}
+
+// NewBinary creates and returns a new binary heap holding values.
+//
+// Parameters:
+//   - less: A function that defines the ordering of elements.
+//   - values: Optional initial elements. The heap is built from a copy in O(n) time.
+//
+// Returns:
+//   - A pointer to a newly created Binary[T] instance.
+func NewBinary[T any](less bst.LessFunc[T], values ...T) *Binary[T] {
+	h := &Binary[T]{
+		items: append([]T(nil), values...),
+		less:  less,
+	}
+	for i := len(h.items)/2 - 1; i >= 0; i-- {
+		h.down(i)
+	}
+	return h
+}
+
+// Size returns the number of elements in the heap.
+//
+// This is an O(1) operation.
+func (h *Binary[T]) Size() int {
+	return len(h.items)
+}
+
+// Push adds value to the heap, in O(log n) time.
+func (h *Binary[T]) Push(value T) {
+	h.items = append(h.items, value)
+	h.up(len(h.items) - 1)
+}
+
+// Peek returns the smallest element in the heap without removing it.
+//
+// Returns:
+//   - (value, true) if the heap is not empty.
+//   - (zero value, false) if the heap is empty.
+func (h *Binary[T]) Peek() (T, bool) {
+	if len(h.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return h.items[0], true
+}
+
+// Pop removes and returns the smallest element in the heap, in O(log n) time.
+//
+// Returns:
+//   - (value, true) if the heap is not empty.
+//   - (zero value, false) if the heap is empty.
+func (h *Binary[T]) Pop() (T, bool) {
+	var zero T
+	n := len(h.items) - 1
+	if n < 0 {
+		return zero, false
+	}
+	value := h.items[0]
+	h.items[0] = h.items[n]
+	h.items[n] = zero // allow the element to be garbage collected
+	h.items = h.items[:n]
+	h.down(0)
+	return value, true
+}
+
+// up moves the element at index i up until its parent is not greater than it.
+func (h *Binary[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(h.items[i], h.items[parent]) {
+			return
+		}
+		h.items[i], h.items[parent] = h.items[parent], h.items[i]
+		i = parent
+	}
+}
+
+// down moves the element at index i down until neither of its children is less than it.
+func (h *Binary[T]) down(i int) {
+	n := len(h.items)
+	for {
+		smallest := i
+		for _, child := range [2]int{2*i + 1, 2*i + 2} {
+			if child < n && h.less(h.items[child], h.items[smallest]) {
+				smallest = child
+			}
+		}
+		if smallest == i {
+			return
+		}
+		h.items[i], h.items[smallest] = h.items[smallest], h.items[i]
+		i = smallest
+	}
+}
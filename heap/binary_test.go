@@ -0,0 +1,72 @@
+package heap
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+// checkBinary verifies that no element of the heap is less than its parent.
+func checkBinary[T any](t *testing.T, h *Binary[T]) {
+	t.Helper()
+	for i := 1; i < len(h.items); i++ {
+		require.False(t, h.less(h.items[i], h.items[(i-1)/2]), "element %d is less than its parent", i)
+	}
+}
+
+func TestBinary_PushPop(t *testing.T) {
+	h := NewBinary[int](intLess)
+	_, ok := h.Pop()
+	assert.False(t, ok)
+	_, ok = h.Peek()
+	assert.False(t, ok)
+
+	rng := rand.New(rand.NewSource(1))
+	var expected []int
+	for i := 0; i < 10000; i++ {
+		if rng.Intn(3) == 0 && len(expected) > 0 {
+			sort.Ints(expected)
+			v, ok := h.Pop()
+			assert.True(t, ok)
+			assert.Equal(t, expected[0], v)
+			expected = expected[1:]
+		} else {
+			v := rng.Intn(1000)
+			h.Push(v)
+			expected = append(expected, v)
+		}
+		if i%500 == 0 {
+			checkBinary(t, h)
+		}
+	}
+	checkBinary(t, h)
+	assert.Equal(t, len(expected), h.Size())
+
+	sort.Ints(expected)
+	if v, ok := h.Peek(); assert.True(t, ok) {
+		assert.Equal(t, expected[0], v)
+	}
+	for _, want := range expected {
+		v, _ := h.Pop()
+		assert.Equal(t, want, v)
+	}
+	assert.Equal(t, 0, h.Size())
+}
+
+func TestNewBinary_values(t *testing.T) {
+	values := rand.New(rand.NewSource(1)).Perm(1000)
+	h := NewBinary(intLess, values...)
+	checkBinary(t, h)
+	assert.Equal(t, 1000, h.Size())
+
+	// the initial values are copied
+	values[0] = -1
+	for i := 0; i < 1000; i++ {
+		v, _ := h.Pop()
+		assert.Equal(t, i, v)
+	}
+}
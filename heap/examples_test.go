@@ -0,0 +1,33 @@
+package heap_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/heap"
+)
+
+func ExamplePairing_Meld() {
+
+	// a max-heap of task priorities, by reversing the comparison
+	greater := func(a, b int) bool { return a > b }
+	urgent := heap.NewPairing[int](greater)
+	urgent.Push(7)
+	urgent.Push(9)
+
+	routine := heap.NewPairing[int](greater)
+	routine.Push(3)
+	routine.Push(8)
+
+	// combine the queues
+	urgent.Meld(routine)
+
+	for urgent.Size() > 0 {
+		priority, _ := urgent.Pop()
+		fmt.Println(priority)
+	}
+
+	// Output:
+	// 9
+	// 8
+	// 7
+	// 3
+}
@@ -0,0 +1,135 @@
+package heap
+
+import "github.com/mikenye/gotrees/bst"
+
+// Pairing represents a pairing min-heap of elements ordered by a LessFunc.
+//
+// A pairing heap is a heap-ordered multiway tree. Pushes and melds link trees in O(1) time,
+// and pops restructure the children of the root in two passes, in O(log n) amortized time.
+// Heaps must be created with NewPairing.
+type Pairing[T any] struct {
+	root *pairingNode[T] // Root node, holding the smallest element. nil if the heap is empty.
+	less bst.LessFunc[T] // Function to compare elements and maintain order.
+	size int             // Number of elements in the heap.
+}
+
+// pairingNode represents a single element of a pairing heap.
+//
+// The children of a node form a singly linked list, starting at child and linked by sibling.
+type pairingNode[T any] struct {
+	value   T
+	child   *pairingNode[T]
+	sibling *pairingNode[T]
+}
+
+// NewPairing creates and returns a new empty pairing heap.
+//
+// Parameters:
+//   - less: A function that defines the ordering of elements.
+//
+// Returns:
+//   - A pointer to a newly created Pairing[T] instance.
+func NewPairing[T any](less bst.LessFunc[T]) *Pairing[T] {
+	return &Pairing[T]{less: less}
+}
+
+// Size returns the number of elements in the heap.
+//
+// This is an O(1) operation.
+func (h *Pairing[T]) Size() int {
+	return h.size
+}
+
+// Push adds value to the heap, in O(1) time.
+func (h *Pairing[T]) Push(value T) {
+	h.root = h.link(h.root, &pairingNode[T]{value: value})
+	h.size++
+}
+
+// Peek returns the smallest element in the heap without removing it.
+//
+// Returns:
+//   - (value, true) if the heap is not empty.
+//   - (zero value, false) if the heap is empty.
+func (h *Pairing[T]) Peek() (T, bool) {
+	if h.root == nil {
+		var zero T
+		return zero, false
+	}
+	return h.root.value, true
+}
+
+// Pop removes and returns the smallest element in the heap, in O(log n) amortized time.
+//
+// Returns:
+//   - (value, true) if the heap is not empty.
+//   - (zero value, false) if the heap is empty.
+func (h *Pairing[T]) Pop() (T, bool) {
+	if h.root == nil {
+		var zero T
+		return zero, false
+	}
+	value := h.root.value
+	h.root = h.mergePairs(h.root.child)
+	h.size--
+	return value, true
+}
+
+// Meld moves every element of other into h, in O(1) time, leaving other empty.
+//
+// Both heaps must use the same ordering. Melding a heap with itself has no effect.
+func (h *Pairing[T]) Meld(other *Pairing[T]) {
+	if other == h {
+		return
+	}
+	h.root = h.link(h.root, other.root)
+	h.size += other.size
+	other.root, other.size = nil, 0
+}
+
+// link combines the heaps rooted at a and b, making the root with the greater element
+// the first child of the other, and returns the new root.
+func (h *Pairing[T]) link(a, b *pairingNode[T]) *pairingNode[T] {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case h.less(b.value, a.value):
+		a, b = b, a
+	}
+	b.sibling = a.child
+	a.child = b
+	return a
+}
+
+// mergePairs combines the list of heaps starting at first into a single heap and returns its root.
+//
+// The heaps are linked in pairs from left to right, then the pairs are linked from right to left.
+func (h *Pairing[T]) mergePairs(first *pairingNode[T]) *pairingNode[T] {
+	// first pass: link pairs, collecting them in reverse order
+	var pairs *pairingNode[T]
+	for first != nil {
+		a, b := first, first.sibling
+		if b == nil {
+			first = nil
+		} else {
+			first = b.sibling
+			b.sibling = nil
+		}
+		a.sibling = nil
+		pair := h.link(a, b)
+		pair.sibling = pairs
+		pairs = pair
+	}
+
+	// second pass: link the pairs from right to left
+	var root *pairingNode[T]
+	for pairs != nil {
+		next := pairs.sibling
+		pairs.sibling = nil
+		root = h.link(root, pairs)
+		pairs = next
+	}
+	return root
+}
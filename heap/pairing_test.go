@@ -0,0 +1,102 @@
+package heap
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// checkPairing verifies that no element of the heap is less than its parent, and that the size is consistent.
+func checkPairing[T any](t *testing.T, h *Pairing[T]) {
+	t.Helper()
+	count := 0
+	var check func(n *pairingNode[T])
+	check = func(n *pairingNode[T]) {
+		count++
+		for c := n.child; c != nil; c = c.sibling {
+			require.False(t, h.less(c.value, n.value), "child is less than its parent")
+			check(c)
+		}
+	}
+	if h.root != nil {
+		require.Nil(t, h.root.sibling, "root has a sibling")
+		check(h.root)
+	}
+	require.Equal(t, h.size, count, "inconsistent size")
+}
+
+func TestPairing_PushPop(t *testing.T) {
+	h := NewPairing[int](intLess)
+	_, ok := h.Pop()
+	assert.False(t, ok)
+	_, ok = h.Peek()
+	assert.False(t, ok)
+
+	rng := rand.New(rand.NewSource(1))
+	var expected []int
+	for i := 0; i < 10000; i++ {
+		if rng.Intn(3) == 0 && len(expected) > 0 {
+			sort.Ints(expected)
+			v, ok := h.Pop()
+			assert.True(t, ok)
+			assert.Equal(t, expected[0], v)
+			expected = expected[1:]
+		} else {
+			v := rng.Intn(1000)
+			h.Push(v)
+			expected = append(expected, v)
+		}
+		if i%500 == 0 {
+			checkPairing(t, h)
+		}
+	}
+	checkPairing(t, h)
+	assert.Equal(t, len(expected), h.Size())
+
+	sort.Ints(expected)
+	if v, ok := h.Peek(); assert.True(t, ok) {
+		assert.Equal(t, expected[0], v)
+	}
+	for _, want := range expected {
+		v, _ := h.Pop()
+		assert.Equal(t, want, v)
+	}
+	assert.Equal(t, 0, h.Size())
+}
+
+func TestPairing_Meld(t *testing.T) {
+	a, b := NewPairing[int](intLess), NewPairing[int](intLess)
+	for i := 0; i < 100; i++ {
+		if i%3 == 0 {
+			a.Push(i)
+		} else {
+			b.Push(i)
+		}
+	}
+	a.Pop() // restructure a before melding
+
+	a.Meld(b)
+	checkPairing(t, a)
+	assert.Equal(t, 99, a.Size())
+	assert.Equal(t, 0, b.Size())
+	_, ok := b.Pop()
+	assert.False(t, ok)
+
+	a.Meld(a)
+	assert.Equal(t, 99, a.Size())
+	a.Meld(NewPairing[int](intLess))
+	assert.Equal(t, 99, a.Size())
+
+	for i := 1; i < 100; i++ {
+		v, _ := a.Pop()
+		assert.Equal(t, i, v)
+	}
+
+	// an empty heap takes the elements of the other heap
+	b.Push(5)
+	a.Meld(b)
+	v, _ := a.Peek()
+	assert.Equal(t, 5, v)
+}
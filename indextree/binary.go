@@ -0,0 +1,88 @@
+package indextree
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+)
+
+// binaryTree is the binary representation of a Tree: its root index, and its nodes
+// other than the sentinel nil node, in slice order.
+type binaryTree[K, V any] struct {
+	Root  int32
+	Nodes []binaryNode[K, V]
+}
+
+// binaryNode is the binary representation of a single node.
+type binaryNode[K, V any] struct {
+	Key                 K
+	Value               V
+	Parent, Left, Right int32
+	Red                 bool
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+//
+// As nodes are linked by indices, the node slice is encoded as is with encoding/gob, along with the
+// index of the root. This preserves the exact shape of the tree, so that it can be restored by
+// Tree.UnmarshalBinary in linear time without rebalancing.
+//
+// Keys and values must be encodable by encoding/gob. The LessFunc is not encoded.
+func (t *Tree[K, V]) MarshalBinary() ([]byte, error) {
+	bt := binaryTree[K, V]{Root: t.root, Nodes: make([]binaryNode[K, V], 0, t.Size())}
+	for _, n := range t.nodes[1:] {
+		bt.Nodes = append(bt.Nodes, binaryNode[K, V]{
+			Key:    n.key,
+			Value:  n.value,
+			Parent: n.parent,
+			Left:   n.left,
+			Right:  n.right,
+			Red:    n.red,
+		})
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&bt); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, restoring a tree encoded by Tree.MarshalBinary.
+//
+// The tree must have been created with New, so that it has a LessFunc. Its existing contents are replaced.
+//
+// The restored tree is validated with Tree.IsTreeValid. If the data cannot be decoded
+// or describes an invalid tree, an error is returned and the tree is left unchanged.
+func (t *Tree[K, V]) UnmarshalBinary(data []byte) error {
+	if t.less == nil {
+		return fmt.Errorf("cannot unmarshal into a tree not created with New")
+	}
+	var bt binaryTree[K, V]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&bt); err != nil {
+		return err
+	}
+	if bt.Root < 0 || int(bt.Root) > len(bt.Nodes) {
+		return fmt.Errorf("invalid tree: root index %d out of range", bt.Root)
+	}
+
+	restored := &Tree[K, V]{
+		nodes: make([]node[K, V], 1, len(bt.Nodes)+1),
+		root:  bt.Root,
+		less:  t.less,
+	}
+	for _, bn := range bt.Nodes {
+		restored.nodes = append(restored.nodes, node[K, V]{
+			key:    bn.Key,
+			value:  bn.Value,
+			parent: bn.Parent,
+			left:   bn.Left,
+			right:  bn.Right,
+			red:    bn.Red,
+		})
+	}
+	if err := restored.IsTreeValid(); err != nil {
+		return fmt.Errorf("invalid tree: %w", err)
+	}
+	*t = *restored
+	return nil
+}
@@ -0,0 +1,57 @@
+package indextree_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/indextree"
+)
+
+func ExampleTree_AscendRange() {
+
+	// create the tree with integer keys and string values
+	tree := indextree.New[int, string](func(a, b int) bool {
+		return a < b
+	})
+
+	// insert some keys in the tree
+	tree.Insert(8, "eight")
+	tree.Insert(2, "two")
+	tree.Insert(6, "six")
+	tree.Insert(4, "four")
+	tree.Insert(10, "ten")
+
+	// find the keys in [4, 10)
+	tree.AscendRange(4, 10, func(key int, value string) bool {
+		fmt.Printf("%d: %s\n", key, value)
+		return true
+	})
+
+	// Output:
+	// 4: four
+	// 6: six
+	// 8: eight
+}
+
+func ExampleTree_MarshalBinary() {
+	less := func(a, b int) bool { return a < b }
+	tree := indextree.New[int, string](less)
+	tree.Insert(1, "one")
+	tree.Insert(2, "two")
+
+	data, err := tree.MarshalBinary()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// the LessFunc is not encoded, so is given to New
+	restored := indextree.New[int, string](less)
+	if err := restored.UnmarshalBinary(data); err != nil {
+		fmt.Println(err)
+		return
+	}
+	value, _ := restored.Search(2)
+	fmt.Println(restored.Size(), value)
+
+	// Output:
+	// 2 two
+}
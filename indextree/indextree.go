@@ -0,0 +1,566 @@
+// Package indextree provides a generic Red-Black Tree whose nodes are stored in a single slice,
+// linked by int32 indices rather than pointers.
+//
+// Compared with rbtree.Tree, whose nodes are individually allocated and linked by pointers,
+// an index-based tree:
+//   - Uses less memory: the three links of a node take 12 bytes rather than 24 on 64-bit platforms.
+//   - Improves cache locality, as nodes are packed contiguously.
+//   - Reduces garbage collection work, as the tree holds a single slice rather than one object per node.
+//   - Is trivially serializable, as links are plain integers (see Tree.MarshalBinary).
+//
+// Nodes are kept packed: deleting a node moves the last node of the slice into its slot.
+// As node positions change, the API is key-based rather than node-based, mirroring the API of
+// btree.Tree and skiplist.List (Insert, Search, Delete, Min, Max, Floor, Ceiling, Ascend,
+// AscendRange and Descend), so that the tree implements trees.Sorted. Keys are ordered with the
+// same LessFunc used by the bst and rbtree packages.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/indextree"
+//
+//	tree := indextree.New[int, string](func(a, b int) bool { return a < b })
+//	tree.Insert(10, "ten")
+//	tree.Insert(20, "twenty")
+//	value, found := tree.Search(10)
+//
+//	if found {
+//		tree.Delete(10)
+//	}
+//
+// # Limitations
+//
+// Keys are unique, a tree holds at most math.MaxInt32 entries, and the tree is not safe for concurrent use.
+package indextree
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/bst"
+	"math"
+	"slices"
+)
+
+// sentinel is the index of the sentinel nil node, which stands for every missing child and the parent of the root.
+const sentinel int32 = 0
+
+// node represents a single entry of the tree. Links are indices into Tree.nodes.
+type node[K, V any] struct {
+	key                 K
+	value               V
+	parent, left, right int32
+	red                 bool
+}
+
+// Tree represents a Red-Black Tree of key-value pairs, ordered by a LessFunc,
+// with its nodes stored in a slice.
+//
+// Trees must be created with New.
+type Tree[K, V any] struct {
+	nodes []node[K, V]    // Nodes of the tree. nodes[0] is the black sentinel nil node.
+	root  int32           // Index of the root node, or sentinel if the tree is empty.
+	less  bst.LessFunc[K] // Function to compare keys and maintain order.
+}
+
+// New creates and returns a new empty tree.
+//
+// Parameters:
+//   - less: A function that defines the ordering of keys.
+//
+// Returns:
+//   - A pointer to a newly created Tree[K, V] instance.
+func New[K, V any](less bst.LessFunc[K]) *Tree[K, V] {
+	return &Tree[K, V]{
+		nodes: make([]node[K, V], 1),
+		less:  less,
+	}
+}
+
+// Size returns the number of entries in the tree.
+//
+// This is an O(1) operation.
+func (t *Tree[K, V]) Size() int {
+	return len(t.nodes) - 1
+}
+
+// Grow ensures the tree can hold n more entries without reallocating its node slice,
+// avoiding repeated reallocations when the number of entries to insert is known.
+func (t *Tree[K, V]) Grow(n int) {
+	if n > 0 {
+		t.nodes = slices.Grow(t.nodes, n)
+	}
+}
+
+// find returns the index of the node with the given key, or sentinel if there is none.
+func (t *Tree[K, V]) find(key K) int32 {
+	x := t.root
+	for x != sentinel {
+		switch n := &t.nodes[x]; {
+		case t.less(key, n.key):
+			x = n.left
+		case t.less(n.key, key):
+			x = n.right
+		default:
+			return x
+		}
+	}
+	return sentinel
+}
+
+// Search looks up the value associated with key.
+//
+// Returns:
+//   - (value, true) if the key is found.
+//   - (zero value, false) if the key is not in the tree.
+func (t *Tree[K, V]) Search(key K) (V, bool) {
+	x := t.find(key)
+	return t.nodes[x].value, x != sentinel
+}
+
+// Insert adds a key-value pair to the tree, or updates the value if the key already exists.
+//
+// Insert panics if the tree already holds math.MaxInt32 entries.
+//
+// Returns:
+//   - true if a new key was inserted.
+//   - false if an existing key's value was updated.
+func (t *Tree[K, V]) Insert(key K, value V) bool {
+	parent, x := sentinel, t.root
+	for x != sentinel {
+		parent = x
+		switch n := &t.nodes[x]; {
+		case t.less(key, n.key):
+			x = n.left
+		case t.less(n.key, key):
+			x = n.right
+		default:
+			n.value = value
+			return false
+		}
+	}
+
+	if len(t.nodes) > math.MaxInt32 {
+		panic(fmt.Sprintf("indextree: cannot hold more than %d entries", math.MaxInt32))
+	}
+	z := int32(len(t.nodes))
+	t.nodes = append(t.nodes, node[K, V]{key: key, value: value, parent: parent, red: true})
+	switch {
+	case parent == sentinel:
+		t.root = z
+	case t.less(key, t.nodes[parent].key):
+		t.nodes[parent].left = z
+	default:
+		t.nodes[parent].right = z
+	}
+	t.insertFixup(z)
+	return true
+}
+
+// insertFixup restores the Red-Black properties after node z was inserted.
+func (t *Tree[K, V]) insertFixup(z int32) {
+	n := t.nodes
+	for n[n[z].parent].red {
+		p := n[z].parent
+		g := n[p].parent
+		if p == n[g].left {
+			if u := n[g].right; n[u].red {
+				n[p].red, n[u].red, n[g].red = false, false, true
+				z = g
+				continue
+			}
+			if z == n[p].right {
+				z = p
+				t.rotateLeft(z)
+				p = n[z].parent
+			}
+			n[p].red, n[g].red = false, true
+			t.rotateRight(g)
+		} else {
+			if u := n[g].left; n[u].red {
+				n[p].red, n[u].red, n[g].red = false, false, true
+				z = g
+				continue
+			}
+			if z == n[p].left {
+				z = p
+				t.rotateRight(z)
+				p = n[z].parent
+			}
+			n[p].red, n[g].red = false, true
+			t.rotateLeft(g)
+		}
+	}
+	n[t.root].red = false
+}
+
+// Delete removes key from the tree.
+//
+// The last node of the node slice is moved into the slot of the deleted node, so that nodes stay packed.
+//
+// Returns:
+//   - true if the key was found and removed.
+//   - false if the key was not in the tree.
+func (t *Tree[K, V]) Delete(key K) bool {
+	z := t.find(key)
+	if z == sentinel {
+		return false
+	}
+
+	n := t.nodes
+	var x int32
+	y, yRed := z, n[z].red
+	switch {
+	case n[z].left == sentinel:
+		x = n[z].right
+		t.transplant(z, x)
+	case n[z].right == sentinel:
+		x = n[z].left
+		t.transplant(z, x)
+	default:
+		// replace z with its successor y
+		y = t.min(n[z].right)
+		yRed = n[y].red
+		x = n[y].right
+		if n[y].parent == z {
+			n[x].parent = y
+		} else {
+			t.transplant(y, x)
+			n[y].right = n[z].right
+			n[n[y].right].parent = y
+		}
+		t.transplant(z, y)
+		n[y].left = n[z].left
+		n[n[y].left].parent = y
+		n[y].red = n[z].red
+	}
+	if !yRed {
+		t.deleteFixup(x)
+	}
+	t.remove(z)
+	return true
+}
+
+// deleteFixup restores the Red-Black properties after a black node was removed above node x.
+func (t *Tree[K, V]) deleteFixup(x int32) {
+	n := t.nodes
+	for x != t.root && !n[x].red {
+		p := n[x].parent
+		if x == n[p].left {
+			w := n[p].right
+			if n[w].red {
+				n[w].red, n[p].red = false, true
+				t.rotateLeft(p)
+				w = n[p].right
+			}
+			if !n[n[w].left].red && !n[n[w].right].red {
+				n[w].red = true
+				x = p
+				continue
+			}
+			if !n[n[w].right].red {
+				n[n[w].left].red, n[w].red = false, true
+				t.rotateRight(w)
+				w = n[p].right
+			}
+			n[w].red, n[p].red, n[n[w].right].red = n[p].red, false, false
+			t.rotateLeft(p)
+		} else {
+			w := n[p].left
+			if n[w].red {
+				n[w].red, n[p].red = false, true
+				t.rotateRight(p)
+				w = n[p].left
+			}
+			if !n[n[w].left].red && !n[n[w].right].red {
+				n[w].red = true
+				x = p
+				continue
+			}
+			if !n[n[w].left].red {
+				n[n[w].right].red, n[w].red = false, true
+				t.rotateLeft(w)
+				w = n[p].left
+			}
+			n[w].red, n[p].red, n[n[w].left].red = n[p].red, false, false
+			t.rotateRight(p)
+		}
+		x = t.root
+	}
+	n[x].red = false
+}
+
+// remove frees the slot of node z, which has been unlinked from the tree, by moving the last node
+// of the node slice into it. The sentinel nil node, whose parent may have been set by the deletion,
+// is reset.
+func (t *Tree[K, V]) remove(z int32) {
+	n := t.nodes
+	last := int32(len(n) - 1)
+	if z != last {
+		n[z] = n[last]
+		switch p := n[z].parent; {
+		case t.root == last:
+			t.root = z
+		case n[p].left == last:
+			n[p].left = z
+		default:
+			n[p].right = z
+		}
+		if n[z].left != sentinel {
+			n[n[z].left].parent = z
+		}
+		if n[z].right != sentinel {
+			n[n[z].right].parent = z
+		}
+	}
+	n[last] = node[K, V]{} // release the key and value
+	n[sentinel] = node[K, V]{}
+	t.nodes = n[:last]
+}
+
+// transplant replaces the subtree rooted at u with the subtree rooted at v.
+// The parent of v is set even if v is the sentinel nil node, as required by deleteFixup.
+func (t *Tree[K, V]) transplant(u, v int32) {
+	n := t.nodes
+	switch p := n[u].parent; {
+	case p == sentinel:
+		t.root = v
+	case u == n[p].left:
+		n[p].left = v
+	default:
+		n[p].right = v
+	}
+	n[v].parent = n[u].parent
+}
+
+// rotateLeft moves node x down to the left, promoting its right child.
+func (t *Tree[K, V]) rotateLeft(x int32) {
+	n := t.nodes
+	y := n[x].right
+	n[x].right = n[y].left
+	if n[y].left != sentinel {
+		n[n[y].left].parent = x
+	}
+	t.transplant(x, y)
+	n[y].left, n[x].parent = x, y
+}
+
+// rotateRight moves node x down to the right, promoting its left child.
+func (t *Tree[K, V]) rotateRight(x int32) {
+	n := t.nodes
+	y := n[x].left
+	n[x].left = n[y].right
+	if n[y].right != sentinel {
+		n[n[y].right].parent = x
+	}
+	t.transplant(x, y)
+	n[y].right, n[x].parent = x, y
+}
+
+// min returns the index of the node with the smallest key in the subtree rooted at x.
+func (t *Tree[K, V]) min(x int32) int32 {
+	for x != sentinel && t.nodes[x].left != sentinel {
+		x = t.nodes[x].left
+	}
+	return x
+}
+
+// max returns the index of the node with the largest key in the subtree rooted at x.
+func (t *Tree[K, V]) max(x int32) int32 {
+	for x != sentinel && t.nodes[x].right != sentinel {
+		x = t.nodes[x].right
+	}
+	return x
+}
+
+// successor returns the index of the node following x in key order, or sentinel if there is none.
+func (t *Tree[K, V]) successor(x int32) int32 {
+	n := t.nodes
+	if n[x].right != sentinel {
+		return t.min(n[x].right)
+	}
+	p := n[x].parent
+	for p != sentinel && x == n[p].right {
+		x, p = p, n[p].parent
+	}
+	return p
+}
+
+// predecessor returns the index of the node preceding x in key order, or sentinel if there is none.
+func (t *Tree[K, V]) predecessor(x int32) int32 {
+	n := t.nodes
+	if n[x].left != sentinel {
+		return t.max(n[x].left)
+	}
+	p := n[x].parent
+	for p != sentinel && x == n[p].left {
+		x, p = p, n[p].parent
+	}
+	return p
+}
+
+// entry returns the key and value of node x, and false if x is the sentinel nil node.
+func (t *Tree[K, V]) entry(x int32) (K, V, bool) {
+	return t.nodes[x].key, t.nodes[x].value, x != sentinel
+}
+
+// Min returns the smallest key in the tree and its value.
+//
+// Returns:
+//   - (key, value, true) if the tree is not empty.
+//   - (zero, zero, false) if the tree is empty.
+func (t *Tree[K, V]) Min() (K, V, bool) {
+	return t.entry(t.min(t.root))
+}
+
+// Max returns the largest key in the tree and its value.
+//
+// Returns:
+//   - (key, value, true) if the tree is not empty.
+//   - (zero, zero, false) if the tree is empty.
+func (t *Tree[K, V]) Max() (K, V, bool) {
+	return t.entry(t.max(t.root))
+}
+
+// Floor returns the largest key less than or equal to key, and its value.
+//
+// Returns:
+//   - (key, value, true) if such a key exists.
+//   - (zero, zero, false) otherwise.
+func (t *Tree[K, V]) Floor(key K) (K, V, bool) {
+	floor := sentinel
+	for x := t.root; x != sentinel; {
+		if t.less(key, t.nodes[x].key) {
+			x = t.nodes[x].left
+		} else {
+			floor, x = x, t.nodes[x].right
+		}
+	}
+	return t.entry(floor)
+}
+
+// Ceiling returns the smallest key greater than or equal to key, and its value.
+//
+// Returns:
+//   - (key, value, true) if such a key exists.
+//   - (zero, zero, false) otherwise.
+func (t *Tree[K, V]) Ceiling(key K) (K, V, bool) {
+	return t.entry(t.ceiling(key))
+}
+
+// ceiling returns the index of the node with the smallest key greater than or equal to key,
+// or sentinel if there is none.
+func (t *Tree[K, V]) ceiling(key K) int32 {
+	ceiling := sentinel
+	for x := t.root; x != sentinel; {
+		if t.less(t.nodes[x].key, key) {
+			x = t.nodes[x].right
+		} else {
+			ceiling, x = x, t.nodes[x].left
+		}
+	}
+	return ceiling
+}
+
+// Ascend calls f for each key-value pair in ascending key order, until f returns false.
+//
+// The tree must not be modified during iteration.
+func (t *Tree[K, V]) Ascend(f func(key K, value V) bool) {
+	for x := t.min(t.root); x != sentinel; x = t.successor(x) {
+		if !f(t.nodes[x].key, t.nodes[x].value) {
+			return
+		}
+	}
+}
+
+// AscendRange calls f for each key-value pair with a key in the half-open interval [lo, hi),
+// in ascending key order, until f returns false.
+//
+// The tree must not be modified during iteration.
+func (t *Tree[K, V]) AscendRange(lo, hi K, f func(key K, value V) bool) {
+	for x := t.ceiling(lo); x != sentinel && t.less(t.nodes[x].key, hi); x = t.successor(x) {
+		if !f(t.nodes[x].key, t.nodes[x].value) {
+			return
+		}
+	}
+}
+
+// Descend calls f for each key-value pair in descending key order, until f returns false.
+//
+// The tree must not be modified during iteration.
+func (t *Tree[K, V]) Descend(f func(key K, value V) bool) {
+	for x := t.max(t.root); x != sentinel; x = t.predecessor(x) {
+		if !f(t.nodes[x].key, t.nodes[x].value) {
+			return
+		}
+	}
+}
+
+// IsTreeValid checks whether the tree is a valid Red-Black Tree, with every node reachable from the root:
+//   - The sentinel nil node and the root are black.
+//   - Parent and child links are consistent, and keys are in order.
+//   - No red node has a red child.
+//   - Every path from the root to a leaf has the same number of black nodes.
+//   - Every node in the node slice is reachable from the root.
+//
+// Returns:
+//   - nil if the tree is valid.
+//   - An error describing the first violation found otherwise.
+func (t *Tree[K, V]) IsTreeValid() error {
+	switch {
+	case len(t.nodes) == 0:
+		return fmt.Errorf("tree not created with New")
+	case t.nodes[sentinel].red:
+		return fmt.Errorf("sentinel nil node is red")
+	case t.nodes[t.root].red:
+		return fmt.Errorf("root node is red")
+	case t.root != sentinel && t.nodes[t.root].parent != sentinel:
+		return fmt.Errorf("root node %v has a parent", t.nodes[t.root].key)
+	}
+	count := 0
+	if _, err := t.validate(t.root, nil, nil, &count); err != nil {
+		return err
+	}
+	if count != t.Size() {
+		return fmt.Errorf("%d nodes reachable from the root, but the tree holds %d", count, t.Size())
+	}
+	return nil
+}
+
+// validate checks the subtree rooted at x, whose keys must lie within (lo, hi) where a nil bound is unbounded,
+// and returns its black height.
+func (t *Tree[K, V]) validate(x int32, lo, hi *K, count *int) (int, error) {
+	if x == sentinel {
+		return 1, nil
+	}
+	if x < 0 || int(x) >= len(t.nodes) || *count >= t.Size() {
+		return 0, fmt.Errorf("invalid or repeated node index %d", x)
+	}
+	*count++
+	n := &t.nodes[x]
+	if (lo != nil && !t.less(*lo, n.key)) || (hi != nil && !t.less(n.key, *hi)) {
+		return 0, fmt.Errorf("key %v is out of order", n.key)
+	}
+	for _, child := range []int32{n.left, n.right} {
+		switch {
+		case child < 0 || int(child) >= len(t.nodes):
+			return 0, fmt.Errorf("node %v has invalid child index %d", n.key, child)
+		case child != sentinel && t.nodes[child].parent != x:
+			return 0, fmt.Errorf("parent/child mismatch for node %v", t.nodes[child].key)
+		case n.red && t.nodes[child].red:
+			return 0, fmt.Errorf("red node %v has a red child", n.key)
+		}
+	}
+	left, err := t.validate(n.left, lo, &n.key, count)
+	if err != nil {
+		return 0, err
+	}
+	right, err := t.validate(n.right, &n.key, hi, count)
+	if err != nil {
+		return 0, err
+	}
+	if left != right {
+		return 0, fmt.Errorf("black height mismatch at node %v: %d left, %d right", n.key, left, right)
+	}
+	if !n.red {
+		left++
+	}
+	return left, nil
+}
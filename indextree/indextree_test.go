@@ -0,0 +1,161 @@
+package indextree
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"sort"
+	"testing"
+	"unsafe"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+func TestTree_InsertSearchDelete(t *testing.T) {
+	tree := New[int, int](intLess)
+	rng := rand.New(rand.NewSource(1))
+	expected := make(map[int]int)
+
+	for i := 0; i < 20000; i++ {
+		key := rng.Intn(1000)
+		_, exists := expected[key]
+		if rng.Intn(3) == 0 {
+			assert.Equal(t, exists, tree.Delete(key), "unexpected Delete(%d) result", key)
+			delete(expected, key)
+		} else {
+			assert.Equal(t, !exists, tree.Insert(key, i), "unexpected Insert(%d) result", key)
+			expected[key] = i
+		}
+		if i%500 == 0 {
+			require.NoError(t, tree.IsTreeValid())
+		}
+	}
+	require.NoError(t, tree.IsTreeValid())
+
+	// nodes stay packed
+	assert.Equal(t, len(expected), tree.Size())
+	assert.Len(t, tree.nodes, len(expected)+1)
+
+	for key, value := range expected {
+		v, found := tree.Search(key)
+		require.True(t, found, "expected key %d to be found", key)
+		assert.Equal(t, value, v)
+	}
+	var keys []int
+	for key := range expected {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	var visited []int
+	tree.Ascend(func(key, value int) bool {
+		visited = append(visited, key)
+		return true
+	})
+	assert.Equal(t, keys, visited)
+
+	// delete everything
+	for _, key := range keys {
+		require.True(t, tree.Delete(key))
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 0, tree.Size())
+	_, _, found := tree.Min()
+	assert.False(t, found)
+}
+
+func TestTree_sequential(t *testing.T) {
+	tree := New[int, struct{}](intLess)
+	tree.Grow(10000)
+	for i := 0; i < 10000; i++ {
+		tree.Insert(i, struct{}{})
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 10000, tree.Size())
+	for i := 0; i < 10000; i += 2 {
+		require.True(t, tree.Delete(i))
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 5000, tree.Size())
+	key, _, found := tree.Floor(100)
+	assert.True(t, found)
+	assert.Equal(t, 99, key)
+	key, _, found = tree.Ceiling(100)
+	assert.True(t, found)
+	assert.Equal(t, 101, key)
+}
+
+func TestTree_nodeSize(t *testing.T) {
+	// three int32 links and a color fit in 16 bytes, where three pointers take 24 on 64-bit platforms
+	assert.Equal(t, uintptr(16), unsafe.Sizeof(node[struct{}, struct{}]{}))
+}
+
+func TestTree_Descend(t *testing.T) {
+	tree := New[int, string](intLess)
+	for _, key := range []int{5, 1, 4, 2, 3} {
+		tree.Insert(key, "value")
+	}
+	var keys []int
+	tree.Descend(func(key int, value string) bool {
+		keys = append(keys, key)
+		return key > 3
+	})
+	assert.Equal(t, []int{5, 4, 3}, keys)
+}
+
+func TestTree_MarshalBinary(t *testing.T) {
+	tree := New[int, string](intLess)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		tree.Insert(rng.Intn(1000), "value")
+	}
+	data, err := tree.MarshalBinary()
+	require.NoError(t, err)
+
+	restored := New[int, string](intLess)
+	require.NoError(t, restored.UnmarshalBinary(data))
+	require.NoError(t, restored.IsTreeValid())
+	assert.Equal(t, tree.nodes, restored.nodes, "expected node slice to be restored as is")
+	assert.Equal(t, tree.root, restored.root)
+	restored.Insert(1000, "new")
+	assert.Equal(t, tree.Size()+1, restored.Size())
+
+	// empty tree
+	data, err = New[int, string](intLess).MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, restored.UnmarshalBinary(data))
+	assert.Equal(t, 0, restored.Size())
+}
+
+func TestTree_UnmarshalBinary_invalid(t *testing.T) {
+	tree := New[int, int](intLess)
+	for i := 0; i < 10; i++ {
+		tree.Insert(i, i)
+	}
+
+	for name, corrupt := range map[string]func(tree *Tree[int, int]){
+		"key order":      func(tree *Tree[int, int]) { tree.nodes[1].key = 100 },
+		"child index":    func(tree *Tree[int, int]) { tree.nodes[tree.root].left = 99 },
+		"root index":     func(tree *Tree[int, int]) { tree.root = 42 },
+		"red root":       func(tree *Tree[int, int]) { tree.nodes[tree.root].red = true },
+		"orphaned node":  func(tree *Tree[int, int]) { tree.nodes = append(tree.nodes, node[int, int]{key: 20}) },
+		"parent pointer": func(tree *Tree[int, int]) { tree.nodes[tree.nodes[tree.root].left].parent = 0 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			corrupted := New[int, int](intLess)
+			corrupted.nodes = append(corrupted.nodes[:0], tree.nodes...)
+			corrupted.root = tree.root
+			corrupt(corrupted)
+			data, err := corrupted.MarshalBinary()
+			require.NoError(t, err)
+
+			restored := New[int, int](intLess)
+			restored.Insert(1, 1)
+			assert.Error(t, restored.UnmarshalBinary(data))
+			assert.Equal(t, 1, restored.Size(), "expected tree to be left unchanged")
+		})
+	}
+
+	assert.Error(t, New[int, int](intLess).UnmarshalBinary([]byte("garbage")))
+	var uninitialized Tree[int, int]
+	assert.Error(t, uninitialized.UnmarshalBinary(nil))
+}
@@ -0,0 +1,33 @@
+package kdtree_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/kdtree"
+)
+
+func ExampleTree_NearestNeighbor() {
+
+	// create a tree of named 2D points
+	tree := kdtree.New[float64, string](2)
+	tree.Insert([]float64{2, 3}, "a")
+	tree.Insert([]float64{5, 4}, "b")
+	tree.Insert([]float64{9, 6}, "c")
+	tree.Insert([]float64{4, 7}, "d")
+	tree.Insert([]float64{8, 1}, "e")
+
+	// find the point closest to (9, 2)
+	point, name, _ := tree.NearestNeighbor([]float64{9, 2})
+	fmt.Println("Nearest:", name, point)
+
+	// count the points within the box [3, 9) x [0, 8)
+	count := 0
+	tree.RangeSearch([]float64{3, 0}, []float64{9, 8}, func(point []float64, name string) bool {
+		count++
+		return true
+	})
+	fmt.Println("Points in box:", count)
+
+	// Output:
+	// Nearest: e [8 1]
+	// Points in box: 3
+}
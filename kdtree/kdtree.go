@@ -0,0 +1,316 @@
+// Package kdtree provides a generic k-d tree, a binary space partitioning tree for
+// k-dimensional point data.
+//
+// Each level of the tree splits space along one axis, cycling through the axes with depth:
+// the root splits on axis 0, its children on axis 1, and so on. Points are slices of k
+// numeric coordinates, and are compared one coordinate at a time, much as bst compares
+// whole keys with a LessFunc.
+//
+// Besides exact lookups, the tree supports spatial queries:
+//   - Tree.NearestNeighbor finds the point closest to a query point (by Euclidean distance).
+//   - Tree.RangeSearch visits every point within an axis-aligned box.
+//
+// On randomly distributed points, lookups and nearest neighbor queries take O(log n)
+// expected time.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/kdtree"
+//
+//	tree := kdtree.New[float64, string](2)
+//	tree.Insert([]float64{2, 3}, "a")
+//	tree.Insert([]float64{5, 4}, "b")
+//	point, value, found := tree.NearestNeighbor([]float64{4, 4}) // [5 4], "b", true
+//
+// # Limitations
+//
+// The tree does not rebalance itself, so inserting points in sorted order degrades
+// performance to O(n). Points are unique, and the tree is not safe for concurrent use.
+package kdtree
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Number is the set of types that can be used as point coordinates.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Tree represents a k-d tree mapping k-dimensional points to values of type V.
+//
+// Trees must be created with New.
+type Tree[T Number, V any] struct {
+	root *node[T, V] // Root node of the tree, nil if the tree is empty.
+	k    int         // Number of dimensions.
+	size int         // Number of points in the tree.
+}
+
+// node represents a single point of the tree.
+//
+// A node at depth d splits space on axis d%k: points in its left subtree have a smaller
+// coordinate on that axis, and points in its right subtree have a greater or equal one.
+type node[T Number, V any] struct {
+	point       []T
+	value       V
+	left, right *node[T, V]
+}
+
+// New creates and returns a new empty k-d tree.
+//
+// Parameters:
+//   - k: The number of dimensions of the points in the tree. Must be at least 1.
+//
+// Returns:
+//   - A pointer to a newly created Tree[T, V] instance.
+func New[T Number, V any](k int) *Tree[T, V] {
+	if k < 1 {
+		panic(fmt.Sprintf("kdtree: invalid number of dimensions %d", k))
+	}
+	return &Tree[T, V]{k: k}
+}
+
+// Dimensions returns the number of dimensions of the points in the tree.
+func (t *Tree[T, V]) Dimensions() int {
+	return t.k
+}
+
+// Size returns the number of points in the tree.
+//
+// This is an O(1) operation.
+func (t *Tree[T, V]) Size() int {
+	return t.size
+}
+
+// checkPoint panics if p does not have k dimensions.
+func (t *Tree[T, V]) checkPoint(p []T) {
+	if len(p) != t.k {
+		panic(fmt.Sprintf("kdtree: point has %d dimensions, expected %d", len(p), t.k))
+	}
+}
+
+// find returns the link to the node holding p, or to the nil child where p would be inserted.
+func (t *Tree[T, V]) find(p []T) **node[T, V] {
+	link := &t.root
+	for depth := 0; *link != nil; depth++ {
+		n := *link
+		if slices.Equal(n.point, p) {
+			break
+		}
+		if axis := depth % t.k; p[axis] < n.point[axis] {
+			link = &n.left
+		} else {
+			link = &n.right
+		}
+	}
+	return link
+}
+
+// Insert inserts the given point and value into the tree.
+//
+// If the point already exists, its value is updated. The point slice is not retained.
+// Panics if the point does not have k dimensions.
+//
+// Returns:
+//   - true if a new point was inserted.
+//   - false if the point existed and its value was updated.
+func (t *Tree[T, V]) Insert(point []T, value V) bool {
+	t.checkPoint(point)
+	link := t.find(point)
+	if *link != nil {
+		(*link).value = value
+		return false
+	}
+	*link = &node[T, V]{point: slices.Clone(point), value: value}
+	t.size++
+	return true
+}
+
+// Search returns the value associated with point.
+//
+// Returns:
+//   - (value, true) if the point exists in the tree.
+//   - (zero value, false) if the point is not found.
+func (t *Tree[T, V]) Search(point []T) (V, bool) {
+	t.checkPoint(point)
+	if n := *t.find(point); n != nil {
+		return n.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete removes point from the tree.
+//
+// Returns:
+//   - true if the point was found and removed.
+//   - false if the point was not found.
+func (t *Tree[T, V]) Delete(point []T) bool {
+	t.checkPoint(point)
+	found := false
+	t.root = t.delete(t.root, point, 0, &found)
+	if found {
+		t.size--
+	}
+	return found
+}
+
+// delete removes p from the subtree rooted at n, at the given depth, and returns the new
+// root of the subtree. found is set if p was removed.
+func (t *Tree[T, V]) delete(n *node[T, V], p []T, depth int, found *bool) *node[T, V] {
+	if n == nil {
+		return nil
+	}
+	axis := depth % t.k
+	if !slices.Equal(n.point, p) {
+		if p[axis] < n.point[axis] {
+			n.left = t.delete(n.left, p, depth+1, found)
+		} else {
+			n.right = t.delete(n.right, p, depth+1, found)
+		}
+		return n
+	}
+
+	*found = true
+	switch {
+	case n.right != nil:
+		// replace n with the minimum of its right subtree on this axis
+		m := t.findMin(n.right, axis, depth+1)
+		n.point, n.value = m.point, m.value
+		n.right = t.delete(n.right, m.point, depth+1, new(bool))
+	case n.left != nil:
+		// replace n with the minimum of its left subtree, which then becomes the right subtree,
+		// so that points equal to the minimum on this axis stay on the right
+		m := t.findMin(n.left, axis, depth+1)
+		n.point, n.value = m.point, m.value
+		n.right = t.delete(n.left, m.point, depth+1, new(bool))
+		n.left = nil
+	default:
+		return nil
+	}
+	return n
+}
+
+// findMin returns the node with the smallest coordinate on axis in the subtree rooted at n,
+// at the given depth.
+func (t *Tree[T, V]) findMin(n *node[T, V], axis, depth int) *node[T, V] {
+	if n == nil {
+		return nil
+	}
+	if depth%t.k == axis {
+		// the minimum is in the left subtree, if there is one
+		if n.left == nil {
+			return n
+		}
+		return t.findMin(n.left, axis, depth+1)
+	}
+	m := n
+	for _, c := range []*node[T, V]{t.findMin(n.left, axis, depth+1), t.findMin(n.right, axis, depth+1)} {
+		if c != nil && c.point[axis] < m.point[axis] {
+			m = c
+		}
+	}
+	return m
+}
+
+// distance returns the squared Euclidean distance between a and b.
+func distance[T Number](a, b []T) float64 {
+	d := 0.0
+	for i := range a {
+		diff := float64(a[i]) - float64(b[i])
+		d += diff * diff
+	}
+	return d
+}
+
+// NearestNeighbor returns the point in the tree closest to query by Euclidean distance,
+// and its value. If several points are equally close, any one of them may be returned.
+//
+// The returned point is a new slice owned by the caller.
+// Panics if the query does not have k dimensions.
+//
+// Returns:
+//   - (point, value, true) if the tree is not empty.
+//   - (nil, zero value, false) if the tree is empty.
+func (t *Tree[T, V]) NearestNeighbor(query []T) ([]T, V, bool) {
+	t.checkPoint(query)
+	var best *node[T, V]
+	bestDist := math.Inf(1)
+	t.nearest(t.root, query, 0, &best, &bestDist)
+	if best == nil {
+		var zero V
+		return nil, zero, false
+	}
+	return slices.Clone(best.point), best.value, true
+}
+
+// nearest searches the subtree rooted at n, at the given depth, for a point closer to query
+// than best, updating best and its squared distance bestDist.
+func (t *Tree[T, V]) nearest(n *node[T, V], query []T, depth int, best **node[T, V], bestDist *float64) {
+	if n == nil {
+		return
+	}
+	if d := distance(n.point, query); d < *bestDist {
+		*best, *bestDist = n, d
+	}
+
+	// search the side of the splitting plane containing query first, then the other side
+	// only if the plane is closer than the best point found so far
+	axis := depth % t.k
+	near, far := n.right, n.left
+	if query[axis] < n.point[axis] {
+		near, far = n.left, n.right
+	}
+	t.nearest(near, query, depth+1, best, bestDist)
+	if diff := float64(query[axis]) - float64(n.point[axis]); diff*diff < *bestDist {
+		t.nearest(far, query, depth+1, best, bestDist)
+	}
+}
+
+// RangeSearch calls f for each point within the axis-aligned box [lo, hi) and its value,
+// until f returns false. A point p is within the box if lo[i] <= p[i] < hi[i] on every axis i.
+//
+// Points are visited in an unspecified order, and each point passed to f is a new slice owned
+// by the caller. The tree must not be modified during iteration.
+// Panics if lo or hi does not have k dimensions.
+func (t *Tree[T, V]) RangeSearch(lo, hi []T, f func(point []T, value V) bool) {
+	t.checkPoint(lo)
+	t.checkPoint(hi)
+	t.rangeSearch(t.root, lo, hi, 0, f)
+}
+
+// rangeSearch calls f for each point within [lo, hi) in the subtree rooted at n, at the given depth.
+// It returns false if iteration was stopped by f.
+func (t *Tree[T, V]) rangeSearch(n *node[T, V], lo, hi []T, depth int, f func(point []T, value V) bool) bool {
+	if n == nil {
+		return true
+	}
+	axis := depth % t.k
+
+	// points on the left have a smaller coordinate on axis, and points on the right
+	// a greater or equal one, so each side can be skipped if it lies outside the box
+	if lo[axis] < n.point[axis] && !t.rangeSearch(n.left, lo, hi, depth+1, f) {
+		return false
+	}
+	if inBox(n.point, lo, hi) && !f(slices.Clone(n.point), n.value) {
+		return false
+	}
+	if n.point[axis] < hi[axis] {
+		return t.rangeSearch(n.right, lo, hi, depth+1, f)
+	}
+	return true
+}
+
+// inBox returns true if p is within the box [lo, hi).
+func inBox[T Number](p, lo, hi []T) bool {
+	for i := range p {
+		if p[i] < lo[i] || p[i] >= hi[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,172 @@
+package kdtree
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// checkTree verifies that every point is on the correct side of each of its ancestors'
+// splitting planes, and that the size is consistent.
+func checkTree[T Number, V any](t *testing.T, tr *Tree[T, V]) {
+	t.Helper()
+	count := 0
+	var check func(n *node[T, V], depth int, ancestors []*node[T, V], depths []int)
+	check = func(n *node[T, V], depth int, ancestors []*node[T, V], depths []int) {
+		if n == nil {
+			return
+		}
+		count++
+		for i, a := range ancestors {
+			axis := depths[i] % tr.k
+			if a.left != nil && isDescendant(a.left, n) {
+				require.Less(t, n.point[axis], a.point[axis], "point %v is on the wrong side of %v", n.point, a.point)
+			} else {
+				require.GreaterOrEqual(t, n.point[axis], a.point[axis], "point %v is on the wrong side of %v", n.point, a.point)
+			}
+		}
+		ancestors, depths = append(ancestors, n), append(depths, depth)
+		check(n.left, depth+1, ancestors, depths)
+		check(n.right, depth+1, ancestors, depths)
+	}
+	check(tr.root, 0, nil, nil)
+	require.Equal(t, tr.size, count, "inconsistent size")
+}
+
+// isDescendant returns true if d is in the subtree rooted at n.
+func isDescendant[T Number, V any](n, d *node[T, V]) bool {
+	if n == nil {
+		return false
+	}
+	return n == d || isDescendant(n.left, d) || isDescendant(n.right, d)
+}
+
+func randomPoint(rng *rand.Rand, k int) []int {
+	p := make([]int, k)
+	for i := range p {
+		p[i] = rng.Intn(20)
+	}
+	return p
+}
+
+func TestTree_InsertSearchDelete(t *testing.T) {
+	tr := New[int, int](3)
+	rng := rand.New(rand.NewSource(1))
+	expected := make(map[string]int)
+
+	for i := 0; i < 5000; i++ {
+		p := randomPoint(rng, 3)
+		key := fmt.Sprint(p)
+		_, exists := expected[key]
+		if rng.Intn(3) == 0 {
+			assert.Equal(t, exists, tr.Delete(p), "unexpected result deleting %v", p)
+			delete(expected, key)
+		} else {
+			assert.Equal(t, !exists, tr.Insert(p, i), "unexpected result inserting %v", p)
+			expected[key] = i
+		}
+		if i%250 == 0 {
+			checkTree(t, tr)
+		}
+	}
+	checkTree(t, tr)
+	assert.Equal(t, len(expected), tr.Size())
+
+	count := 0
+	tr.RangeSearch([]int{0, 0, 0}, []int{20, 20, 20}, func(point []int, value int) bool {
+		assert.Equal(t, expected[fmt.Sprint(point)], value)
+		v, found := tr.Search(point)
+		assert.True(t, found)
+		assert.Equal(t, value, v)
+		count++
+		return true
+	})
+	assert.Equal(t, len(expected), count)
+
+	_, found := tr.Search([]int{-1, 0, 0})
+	assert.False(t, found)
+	assert.False(t, tr.Delete([]int{-1, 0, 0}))
+}
+
+func TestTree_NearestNeighbor(t *testing.T) {
+	tr := New[float64, int](2)
+	_, _, found := tr.NearestNeighbor([]float64{0, 0})
+	assert.False(t, found)
+
+	rng := rand.New(rand.NewSource(1))
+	var points [][]float64
+	for i := 0; i < 1000; i++ {
+		p := []float64{rng.Float64() * 100, rng.Float64() * 100}
+		points = append(points, p)
+		tr.Insert(p, i)
+	}
+
+	for i := 0; i < 200; i++ {
+		q := []float64{rng.Float64()*120 - 10, rng.Float64()*120 - 10}
+		want := slices.MinFunc(points, func(a, b []float64) int {
+			da, db := distance(a, q), distance(b, q)
+			switch {
+			case da < db:
+				return -1
+			case da > db:
+				return 1
+			}
+			return 0
+		})
+		got, value, found := tr.NearestNeighbor(q)
+		require.True(t, found)
+		assert.Equal(t, want, got, "unexpected nearest neighbor of %v", q)
+		assert.Equal(t, want, points[value])
+	}
+}
+
+func TestTree_RangeSearch(t *testing.T) {
+	tr := New[int, struct{}](2)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		tr.Insert(randomPoint(rng, 2), struct{}{})
+	}
+
+	for i := 0; i < 100; i++ {
+		lo, hi := randomPoint(rng, 2), randomPoint(rng, 2)
+		expected := 0
+		tr.RangeSearch([]int{0, 0}, []int{20, 20}, func(point []int, _ struct{}) bool {
+			if inBox(point, lo, hi) {
+				expected++
+			}
+			return true
+		})
+		count := 0
+		tr.RangeSearch(lo, hi, func(point []int, _ struct{}) bool {
+			assert.True(t, inBox(point, lo, hi), "point %v outside [%v, %v)", point, lo, hi)
+			count++
+			return true
+		})
+		assert.Equal(t, expected, count, "unexpected count in [%v, %v)", lo, hi)
+	}
+
+	count := 0
+	tr.RangeSearch([]int{0, 0}, []int{20, 20}, func([]int, struct{}) bool {
+		count++
+		return false
+	})
+	assert.Equal(t, 1, count, "expected search to stop early")
+}
+
+func TestTree_dimensions(t *testing.T) {
+	assert.Panics(t, func() { New[int, int](0) })
+	tr := New[int, int](2)
+	assert.Equal(t, 2, tr.Dimensions())
+	assert.Panics(t, func() { tr.Insert([]int{1}, 1) })
+	assert.Panics(t, func() { tr.Search([]int{1, 2, 3}) })
+
+	// the inserted point is not retained
+	p := []int{1, 2}
+	tr.Insert(p, 1)
+	p[0] = 5
+	_, found := tr.Search([]int{1, 2})
+	assert.True(t, found)
+}
@@ -0,0 +1,33 @@
+package leaderboard_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/leaderboard"
+)
+
+func ExampleBoard_Top() {
+
+	// create a leaderboard of players, highest score first
+	board := leaderboard.New[string, int](func(a, b int) bool {
+		return a < b
+	})
+	board.SetScore("alice", 120)
+	board.SetScore("bob", 300)
+	board.SetScore("carol", 150)
+	board.SetScore("dave", 150)
+
+	// alice has a good round
+	board.SetScore("alice", 310)
+
+	for _, e := range board.Top(3) {
+		fmt.Println(e.Rank+1, e.Member, e.Score)
+	}
+	rank, _ := board.RankOf("dave")
+	fmt.Println("dave is ranked", rank+1)
+
+	// Output:
+	// 1 alice 310
+	// 2 bob 300
+	// 3 carol 150
+	// dave is ranked 4
+}
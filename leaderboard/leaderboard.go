@@ -0,0 +1,178 @@
+// Package leaderboard provides a generic leaderboard, ranking members by score.
+//
+// Board is backed by an ostree.Tree (an order-statistic Red-Black Tree) keyed by (score, arrival),
+// together with a map from members to their nodes, so:
+//   - Board.SetScore and Board.Remove take O(log n) time.
+//   - Board.RankOf returns the rank of a member in O(log n) time, from the subtree sizes kept by the tree.
+//   - Board.Top and Board.RangeByRank return members by rank in O(log n + k) time, for k members.
+//
+// Members with higher scores rank first. Members with equal scores are ranked by the order in
+// which they reached their score, earliest first. Ranks are zero-based, so the leader has rank 0.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/leaderboard"
+//
+//	board := leaderboard.New[string, int](func(a, b int) bool { return a < b })
+//	board.SetScore("alice", 120)
+//	board.SetScore("bob", 300)
+//	board.SetScore("carol", 150)
+//	rank, _ := board.RankOf("alice") // 2
+//	top := board.Top(2)              // bob, carol
+//
+// # Limitations
+//
+// The board is not safe for concurrent use.
+package leaderboard
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"github.com/mikenye/gotrees/rbtree"
+	"github.com/mikenye/gotrees/rbtree/ostree"
+)
+
+// key orders the members of a board by descending score, then by arrival.
+type key[S any] struct {
+	score S
+	seq   uint64 // arrival order, increasing with each score change
+}
+
+// Entry represents a member of a board, with its score and rank.
+type Entry[M, S any] struct {
+	Rank   int // Zero-based rank, 0 for the leader
+	Member M
+	Score  S
+}
+
+// Board represents a leaderboard of members of type M, with scores of type S.
+//
+// Boards must be created with New.
+type Board[M comparable, S any] struct {
+	tree    *ostree.Tree[key[S], M]                  // Underlying order-statistic tree
+	members map[M]*bst.Node[key[S], M, rbtree.Color] // Node of each member
+	less    bst.LessFunc[S]                          // Ordering of scores
+	seq     uint64                                   // Arrival order of the next score change
+}
+
+// New creates and returns a new empty leaderboard.
+//
+// Parameters:
+//   - less: A function that defines the ordering of scores. Members with greater scores rank first.
+//
+// Returns:
+//   - A pointer to a newly created Board[M, S] instance.
+func New[M comparable, S any](less bst.LessFunc[S]) *Board[M, S] {
+	return &Board[M, S]{
+		tree: ostree.New[key[S], M](func(a, b key[S]) bool {
+			if less(b.score, a.score) {
+				return true
+			}
+			if less(a.score, b.score) {
+				return false
+			}
+			return a.seq < b.seq
+		}),
+		members: make(map[M]*bst.Node[key[S], M, rbtree.Color]),
+		less:    less,
+	}
+}
+
+// Len returns the number of members on the board.
+//
+// This is an O(1) operation.
+func (b *Board[M, S]) Len() int {
+	return b.tree.Size()
+}
+
+// SetScore sets the score of member, adding it to the board if needed.
+//
+// If the member's score changes, it ranks after the other members with the same score.
+// Setting a member's current score again leaves its rank unchanged.
+//
+// Returns:
+//   - true if the member was added.
+//   - false if the member was already on the board.
+func (b *Board[M, S]) SetScore(member M, score S) bool {
+	n, found := b.members[member]
+	if found {
+		if current := b.tree.Key(n).score; !b.less(current, score) && !b.less(score, current) {
+			return false
+		}
+		b.tree.Delete(n)
+	}
+	b.members[member], _ = b.tree.Insert(key[S]{score, b.seq}, member)
+	b.seq++
+	return !found
+}
+
+// Score returns the score of member.
+//
+// Returns:
+//   - (score, true) if the member is on the board.
+//   - (zero value, false) otherwise.
+func (b *Board[M, S]) Score(member M) (S, bool) {
+	if n, found := b.members[member]; found {
+		return b.tree.Key(n).score, true
+	}
+	var zero S
+	return zero, false
+}
+
+// Remove removes member from the board.
+//
+// Returns:
+//   - true if the member was found and removed.
+//   - false if the member was not on the board.
+func (b *Board[M, S]) Remove(member M) bool {
+	n, found := b.members[member]
+	if !found {
+		return false
+	}
+	b.tree.Delete(n)
+	delete(b.members, member)
+	return true
+}
+
+// RankOf returns the zero-based rank of member, in O(log n) time.
+//
+// Returns:
+//   - (rank, true) if the member is on the board.
+//   - (-1, false) otherwise.
+func (b *Board[M, S]) RankOf(member M) (int, bool) {
+	n, found := b.members[member]
+	if !found {
+		return -1, false
+	}
+	return b.tree.IndexOf(n), true
+}
+
+// At returns the entry at the given zero-based rank.
+//
+// Returns:
+//   - (entry, true) if 0 ≤ rank < Board.Len.
+//   - (zero entry, false) if rank is out of range.
+func (b *Board[M, S]) At(rank int) (Entry[M, S], bool) {
+	k, member, found := b.tree.At(rank)
+	if !found {
+		return Entry[M, S]{}, false
+	}
+	return Entry[M, S]{Rank: rank, Member: member, Score: k.score}, true
+}
+
+// Top returns the entries of the n highest-ranked members, in rank order.
+// If the board has fewer than n members, all members are returned.
+func (b *Board[M, S]) Top(n int) []Entry[M, S] {
+	return b.RangeByRank(0, n)
+}
+
+// RangeByRank returns the entries with ranks in the half-open interval [lo, hi), in rank order.
+// The bounds are clamped to [0, Board.Len).
+func (b *Board[M, S]) RangeByRank(lo, hi int) []Entry[M, S] {
+	lo, hi = max(lo, 0), min(hi, b.Len())
+	entries := make([]Entry[M, S], 0, max(hi-lo, 0))
+	b.tree.AscendIndex(lo, hi, func(i int, n *bst.Node[key[S], M, rbtree.Color]) bool {
+		entries = append(entries, Entry[M, S]{Rank: i, Member: b.tree.Value(n), Score: b.tree.Key(n).score})
+		return true
+	})
+	return entries
+}
@@ -0,0 +1,111 @@
+package leaderboard
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+func TestBoard(t *testing.T) {
+	b := New[int, int](intLess)
+	assert.Equal(t, []Entry[int, int]{}, b.Top(3))
+	_, ok := b.At(0)
+	assert.False(t, ok)
+	rank, ok := b.RankOf(1)
+	assert.False(t, ok)
+	assert.Equal(t, -1, rank)
+
+	// model of the board: score and arrival order of each member
+	type score struct {
+		score, seq int
+	}
+	model := make(map[int]score)
+	ranking := func() []Entry[int, int] {
+		entries := []Entry[int, int]{}
+		for m, s := range model {
+			entries = append(entries, Entry[int, int]{Member: m, Score: s.score})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			a, b := model[entries[i].Member], model[entries[j].Member]
+			if a.score != b.score {
+				return a.score > b.score
+			}
+			return a.seq < b.seq
+		})
+		for i := range entries {
+			entries[i].Rank = i
+		}
+		return entries
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		member := rng.Intn(100)
+		switch rng.Intn(4) {
+		case 0, 1:
+			s := rng.Intn(50)
+			current, found := model[member]
+			assert.Equal(t, !found, b.SetScore(member, s))
+			if !found || current.score != s {
+				model[member] = score{s, i}
+			}
+		case 2:
+			_, found := model[member]
+			assert.Equal(t, found, b.Remove(member))
+			delete(model, member)
+		case 3:
+			s, found := b.Score(member)
+			assert.Equal(t, model[member].score, s)
+			_, inModel := model[member]
+			assert.Equal(t, inModel, found)
+		}
+		require.Equal(t, len(model), b.Len())
+
+		if i%50 == 0 {
+			require.NoError(t, b.tree.IsTreeValid())
+			expected := ranking()
+			for _, e := range expected {
+				rank, ok := b.RankOf(e.Member)
+				require.True(t, ok)
+				require.Equal(t, e.Rank, rank)
+				entry, ok := b.At(e.Rank)
+				require.True(t, ok)
+				require.Equal(t, e, entry)
+			}
+			n := rng.Intn(len(expected) + 5)
+			assert.Equal(t, expected[:min(n, len(expected))], b.Top(n))
+			lo := rng.Intn(len(expected)+2) - 1
+			hi := lo + rng.Intn(10)
+			assert.Equal(t, expected[max(lo, 0):max(min(hi, len(expected)), max(lo, 0))], b.RangeByRank(lo, hi))
+		}
+	}
+}
+
+func TestBoard_Ties(t *testing.T) {
+	b := New[string, int](intLess)
+	b.SetScore("alice", 10)
+	b.SetScore("bob", 10)
+	b.SetScore("carol", 10)
+
+	// members with equal scores rank by the order in which they reached their score
+	rank, _ := b.RankOf("alice")
+	assert.Equal(t, 0, rank)
+
+	// setting the same score again keeps the rank, changing it moves the member after its ties
+	assert.False(t, b.SetScore("alice", 10))
+	rank, _ = b.RankOf("alice")
+	assert.Equal(t, 0, rank)
+	b.SetScore("alice", 5)
+	b.SetScore("alice", 10)
+	rank, _ = b.RankOf("alice")
+	assert.Equal(t, 2, rank)
+	assert.Equal(t, []Entry[string, int]{
+		{Rank: 0, Member: "bob", Score: 10},
+		{Rank: 1, Member: "carol", Score: 10},
+		{Rank: 2, Member: "alice", Score: 10},
+	}, b.Top(5))
+}
@@ -0,0 +1,88 @@
+package llrb
+
+import (
+	"sync"
+	"testing"
+)
+
+// BenchmarkTree_SearchDelete creates a very large tree (10M nodes),
+// then deletes items from said tree in the benchmarking loop.
+func BenchmarkTree_SearchDelete(b *testing.B) {
+
+	// create a tree with integer key & no value,
+	tree := New[int, struct{}](func(a, b int) bool {
+		return a < b
+	})
+
+	// create large tree to delete from
+	for i := 0; i <= 10_000_000; i++ {
+		tree.Insert(i, struct{}{})
+	}
+
+	i := 0
+	b.ResetTimer()
+	for b.Loop() {
+		tree.Search(i)
+		tree.Delete(i)
+		i++
+	}
+}
+
+// BenchmarkTree_Insert inserts items into a tree in the benchmarking loop.
+func BenchmarkTree_Insert(b *testing.B) {
+	tree := New[int, struct{}](func(a, b int) bool {
+		return a < b
+	})
+	i := 0
+	b.ResetTimer()
+	for b.Loop() {
+		tree.Insert(i, struct{}{})
+		i++
+	}
+}
+
+// BenchmarkConcurrent compares parallel searches of a 100k node Concurrent tree with searches of a Tree
+// guarded by a sync.RWMutex, and inserts into both.
+func BenchmarkConcurrent(b *testing.B) {
+	const size = 100_000
+	tree := New[int, struct{}](func(a, b int) bool {
+		return a < b
+	})
+	concurrent := NewConcurrent[int, struct{}](func(a, b int) bool {
+		return a < b
+	})
+	for i := 0; i < size; i++ {
+		tree.Insert(i, struct{}{})
+		concurrent.Insert(i, struct{}{})
+	}
+	var mu sync.RWMutex
+
+	b.Run("Search/RWMutex", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				mu.RLock()
+				tree.Search(i % size)
+				mu.RUnlock()
+			}
+		})
+	})
+	b.Run("Search/Concurrent", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				concurrent.Search(i % size)
+			}
+		})
+	})
+	b.Run("Insert/RWMutex", func(b *testing.B) {
+		for i := 0; b.Loop(); i++ {
+			mu.Lock()
+			tree.Insert(size+i, struct{}{})
+			mu.Unlock()
+		}
+	})
+	b.Run("Insert/Concurrent", func(b *testing.B) {
+		for i := 0; b.Loop(); i++ {
+			concurrent.Insert(size+i, struct{}{})
+		}
+	})
+}
@@ -0,0 +1,192 @@
+package llrb
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"sync"
+	"sync/atomic"
+)
+
+// Concurrent is a Left-Leaning Red-Black Tree safe for concurrent use, whose readers never wait,
+// for read-mostly workloads where even the read lock of a sync.RWMutex is measurable.
+//
+// Writers copy on write: rather than modifying nodes, an insertion or deletion copies the nodes it
+// would modify, i.e. the O(log n) nodes on the path from the root to the change and their rebalanced
+// neighbours, building a new version of the tree that shares every other node with the previous
+// version. The new version is then published by atomically swapping the root. Readers load the
+// current version, without locking, and see a consistent tree, which is never modified once published,
+// however long they take. Writers are serialized by a mutex, so only one new version is built at a time.
+//
+// This is why Concurrent is built on a left-leaning Red-Black Tree: as nodes have no parent pointer,
+// a version can share all but a path of nodes with the previous version, which the nodes of rbtree.Tree,
+// linked to their parent, cannot do.
+//
+// Each write allocates O(log n) nodes, leaving the replaced nodes to the garbage collector once no
+// reader holds the previous version, so writes are slower than on a Tree (see BenchmarkConcurrent).
+// Several changes can be published at once with Concurrent.Update.
+//
+// Concurrent implements trees.Sorted. Trees must be created with NewConcurrent.
+type Concurrent[K, V any] struct {
+	mu      sync.Mutex                 // Serializes writers
+	current atomic.Pointer[Tree[K, V]] // Published version of the tree, never modified once published
+}
+
+// NewConcurrent creates and returns a new empty concurrent tree.
+//
+// Parameters:
+//   - less: A function that defines the ordering of keys.
+//
+// Returns:
+//   - A pointer to a newly created Concurrent[K, V] instance.
+func NewConcurrent[K, V any](less bst.LessFunc[K]) *Concurrent[K, V] {
+	c := &Concurrent[K, V]{}
+	c.current.Store(&Tree[K, V]{less: less, shared: true})
+	return c
+}
+
+// Snapshot returns the current version of the tree, as a Tree unaffected by later writes.
+//
+// The snapshot shares its nodes with the concurrent tree, and copies them on write, so it can be read
+// with the whole API of Tree, including Iter, and even modified, by a single goroutine, without affecting
+// the concurrent tree. Taking a snapshot is an O(1) operation.
+//
+// Example Usage:
+//
+//	// iterate over a consistent version of the tree, while writers go on
+//	for it := tree.Snapshot().Iter(); it.Next(); {
+//		fmt.Println(it.Key(), it.Value())
+//	}
+func (c *Concurrent[K, V]) Snapshot() *Tree[K, V] {
+	snapshot := *c.current.Load()
+	return &snapshot
+}
+
+// Update calls f with a private copy of the current version of the tree, and publishes the modified
+// copy atomically once f returns, so that readers see either none or all of the changes made by f.
+// Writers are serialized: other writes wait until f returns.
+//
+// The copy shares its nodes with the published version, and copies them on write. It must not be used
+// once f returns.
+//
+// Example Usage:
+//
+//	tree.Update(func(t *llrb.Tree[string, int]) {
+//		t.Delete("old")
+//		t.Insert("new", 1)
+//	})
+func (c *Concurrent[K, V]) Update(f func(t *Tree[K, V])) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	next := *c.current.Load()
+	f(&next)
+	c.current.Store(&next)
+}
+
+// Insert adds a key-value pair to the tree, or updates the value if the key already exists,
+// and publishes the new version of the tree.
+//
+// Returns:
+//   - true if a new key was inserted.
+//   - false if an existing key's value was updated.
+func (c *Concurrent[K, V]) Insert(key K, value V) bool {
+	var inserted bool
+	c.Update(func(t *Tree[K, V]) {
+		inserted = t.Insert(key, value)
+	})
+	return inserted
+}
+
+// Delete removes key from the tree, and publishes the new version of the tree if the key was found.
+//
+// Returns:
+//   - true if the key was found and removed.
+//   - false if the key was not in the tree.
+func (c *Concurrent[K, V]) Delete(key K) bool {
+	if c.current.Load().find(key) == nil {
+		return false
+	}
+	var deleted bool
+	c.Update(func(t *Tree[K, V]) {
+		deleted = t.Delete(key)
+	})
+	return deleted
+}
+
+// Size returns the number of entries in the current version of the tree.
+//
+// This is an O(1) operation.
+func (c *Concurrent[K, V]) Size() int {
+	return c.current.Load().Size()
+}
+
+// Search looks up the value associated with key in the current version of the tree.
+//
+// Returns:
+//   - (value, true) if the key is found.
+//   - (zero value, false) if the key is not in the tree.
+func (c *Concurrent[K, V]) Search(key K) (V, bool) {
+	return c.current.Load().Search(key)
+}
+
+// Min returns the smallest key in the current version of the tree, and its value.
+//
+// Returns:
+//   - (key, value, true) if the tree is not empty.
+//   - (zero key, zero value, false) if the tree is empty.
+func (c *Concurrent[K, V]) Min() (K, V, bool) {
+	return c.current.Load().Min()
+}
+
+// Max returns the largest key in the current version of the tree, and its value.
+//
+// Returns:
+//   - (key, value, true) if the tree is not empty.
+//   - (zero key, zero value, false) if the tree is empty.
+func (c *Concurrent[K, V]) Max() (K, V, bool) {
+	return c.current.Load().Max()
+}
+
+// Floor returns the largest key less than or equal to key in the current version of the tree, and its value.
+//
+// Returns:
+//   - (key, value, true) if such a key exists.
+//   - (zero key, zero value, false) otherwise.
+func (c *Concurrent[K, V]) Floor(key K) (K, V, bool) {
+	return c.current.Load().Floor(key)
+}
+
+// Ceiling returns the smallest key greater than or equal to key in the current version of the tree, and its value.
+//
+// Returns:
+//   - (key, value, true) if such a key exists.
+//   - (zero key, zero value, false) otherwise.
+func (c *Concurrent[K, V]) Ceiling(key K) (K, V, bool) {
+	return c.current.Load().Ceiling(key)
+}
+
+// Ascend calls f for each key and value of the current version of the tree in ascending key order,
+// until f returns false. Writes made meanwhile are not seen.
+func (c *Concurrent[K, V]) Ascend(f func(key K, value V) bool) {
+	c.current.Load().Ascend(f)
+}
+
+// AscendRange calls f for each key of the current version of the tree in the half-open interval [lo, hi)
+// and its value, in ascending key order, until f returns false. Writes made meanwhile are not seen.
+func (c *Concurrent[K, V]) AscendRange(lo, hi K, f func(key K, value V) bool) {
+	c.current.Load().AscendRange(lo, hi, f)
+}
+
+// Descend calls f for each key and value of the current version of the tree in descending key order,
+// until f returns false. Writes made meanwhile are not seen.
+func (c *Concurrent[K, V]) Descend(f func(key K, value V) bool) {
+	c.current.Load().Descend(f)
+}
+
+// IsTreeValid checks whether the current version of the tree is a valid Left-Leaning Red-Black Tree
+// (see Tree.IsTreeValid).
+//
+// Returns:
+//   - nil if the tree is valid.
+//   - An error describing the first violation found otherwise.
+func (c *Concurrent[K, V]) IsTreeValid() error {
+	return c.current.Load().IsTreeValid()
+}
@@ -0,0 +1,174 @@
+package llrb
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestConcurrent_InsertSearchDelete(t *testing.T) {
+	tree := NewConcurrent[int, int](intLess)
+	rng := rand.New(rand.NewSource(1))
+	expected := make(map[int]int)
+
+	for i := 0; i < 20000; i++ {
+		key := rng.Intn(1000)
+		_, exists := expected[key]
+		if rng.Intn(3) == 0 {
+			assert.Equal(t, exists, tree.Delete(key), "unexpected Delete(%d) result", key)
+			delete(expected, key)
+		} else {
+			assert.Equal(t, !exists, tree.Insert(key, i), "unexpected Insert(%d) result", key)
+			expected[key] = i
+		}
+		if i%500 == 0 {
+			require.NoError(t, tree.IsTreeValid())
+		}
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, len(expected), tree.Size())
+
+	for key, value := range expected {
+		v, found := tree.Search(key)
+		require.True(t, found, "expected key %d to be found", key)
+		assert.Equal(t, value, v)
+	}
+}
+
+func TestConcurrent_Snapshot(t *testing.T) {
+	tree := NewConcurrent[int, int](intLess)
+	rng := rand.New(rand.NewSource(1))
+
+	// each snapshot keeps the contents the tree had when it was taken
+	type version struct {
+		snapshot *Tree[int, int]
+		contents map[int]int
+	}
+	var versions []version
+	contents := make(map[int]int)
+	for i := 0; i < 3000; i++ {
+		key := rng.Intn(300)
+		if rng.Intn(3) == 0 {
+			tree.Delete(key)
+			delete(contents, key)
+		} else {
+			tree.Insert(key, i)
+			contents[key] = i
+		}
+		if i%100 == 0 {
+			copied := make(map[int]int, len(contents))
+			for k, v := range contents {
+				copied[k] = v
+			}
+			versions = append(versions, version{tree.Snapshot(), copied})
+		}
+	}
+
+	for i, v := range versions {
+		require.NoError(t, v.snapshot.IsTreeValid(), "expected snapshot %d to be valid", i)
+		got := make(map[int]int)
+		v.snapshot.Ascend(func(key, value int) bool {
+			got[key] = value
+			return true
+		})
+		assert.Equal(t, v.contents, got, "expected snapshot %d to be unaffected by later writes", i)
+	}
+
+	// modifying a snapshot does not affect the tree
+	size := tree.Size()
+	snapshot := tree.Snapshot()
+	for key := 0; key < 300; key++ {
+		snapshot.Delete(key)
+	}
+	snapshot.Insert(1000, 1000)
+	assert.Equal(t, 1, snapshot.Size())
+	assert.Equal(t, size, tree.Size())
+	_, found := tree.Search(1000)
+	assert.False(t, found)
+	require.NoError(t, tree.IsTreeValid())
+}
+
+func TestConcurrent_Update(t *testing.T) {
+	tree := NewConcurrent[int, int](intLess)
+	for i := 0; i < 100; i++ {
+		tree.Insert(i, i)
+	}
+
+	// readers see either none or all of the changes of an update
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				sum := 0
+				tree.Ascend(func(key, value int) bool {
+					sum += value
+					return true
+				})
+				assert.Equal(t, 4950, sum, "expected the values to keep their sum")
+				assert.Equal(t, 100, tree.Size())
+			}
+		}()
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		from, to, amount := rng.Intn(100), rng.Intn(100), rng.Intn(10)
+		tree.Update(func(t *Tree[int, int]) {
+			v, _ := t.Search(from)
+			t.Insert(from, v-amount)
+			v, _ = t.Search(to)
+			t.Insert(to, v+amount)
+		})
+	}
+	close(stop)
+	wg.Wait()
+	require.NoError(t, tree.IsTreeValid())
+}
+
+func TestConcurrent_readersAndWriters(t *testing.T) {
+	tree := NewConcurrent[int, int](intLess)
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				key := w*1000 + i
+				tree.Insert(key, key)
+				if i%3 == 0 {
+					tree.Delete(key)
+				}
+			}
+		}(w)
+	}
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				prev := -1
+				tree.Ascend(func(key, value int) bool {
+					assert.Less(t, prev, key, "expected keys in ascending order")
+					assert.Equal(t, key, value)
+					prev = key
+					return true
+				})
+				tree.Search(i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 4*(500-167), tree.Size())
+}
@@ -0,0 +1,33 @@
+package llrb_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/llrb"
+)
+
+func ExampleTree_Iter() {
+
+	// create the tree with integer keys and string values
+	tree := llrb.New[int, string](func(a, b int) bool {
+		return a < b
+	})
+
+	// insert some keys in the tree
+	tree.Insert(8, "eight")
+	tree.Insert(2, "two")
+	tree.Insert(6, "six")
+	tree.Insert(4, "four")
+	tree.Insert(10, "ten")
+
+	// iterate from key 5 onwards
+	it := tree.Iter()
+	it.Seek(5)
+	for it.Next() {
+		fmt.Printf("%d: %s\n", it.Key(), it.Value())
+	}
+
+	// Output:
+	// 6: six
+	// 8: eight
+	// 10: ten
+}
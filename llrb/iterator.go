@@ -0,0 +1,78 @@
+package llrb
+
+// Iterator visits the entries of a tree in ascending key order. As nodes have no parent pointer,
+// it keeps an explicit stack of the ancestors of the current node that are still to be visited,
+// which holds at most the height of the tree, i.e. O(log n) nodes.
+//
+// Moving to the next entry takes O(1) amortized time, and O(log n) in the worst case.
+// The tree must not be modified while an iterator is in use.
+//
+// Iterators are created with Tree.Iter.
+type Iterator[K, V any] struct {
+	t       *Tree[K, V]
+	stack   []*node[K, V] // nodes still to be visited, the next one on top
+	current *node[K, V]   // current node, or nil before the first call to Next and once exhausted
+}
+
+// Iter returns a new iterator over the tree, positioned before the smallest key,
+// so that the first call to Iterator.Next moves it to the smallest key.
+//
+// Example Usage:
+//
+//	for it := tree.Iter(); it.Next(); {
+//		fmt.Println(it.Key(), it.Value())
+//	}
+func (t *Tree[K, V]) Iter() *Iterator[K, V] {
+	it := &Iterator[K, V]{t: t}
+	it.pushLeft(t.root)
+	return it
+}
+
+// pushLeft pushes x and its chain of left descendants on the stack.
+func (it *Iterator[K, V]) pushLeft(x *node[K, V]) {
+	for ; x != nil; x = x.left {
+		it.stack = append(it.stack, x)
+	}
+}
+
+// Seek positions the iterator before the smallest key greater than or equal to key,
+// so that the next call to Iterator.Next moves it to that key.
+//
+// This is an O(log n) operation.
+func (it *Iterator[K, V]) Seek(key K) {
+	it.stack, it.current = it.stack[:0], nil
+	for x := it.t.root; x != nil; {
+		if it.t.less(x.key, key) {
+			x = x.right
+		} else {
+			it.stack = append(it.stack, x)
+			x = x.left
+		}
+	}
+}
+
+// Next moves the iterator to the next key.
+//
+// Returns:
+//   - true if the iterator moved to a key.
+//   - false if there are no more keys.
+func (it *Iterator[K, V]) Next() bool {
+	if len(it.stack) == 0 {
+		it.current = nil
+		return false
+	}
+	it.current = it.stack[len(it.stack)-1]
+	it.stack = it.stack[:len(it.stack)-1]
+	it.pushLeft(it.current.right)
+	return true
+}
+
+// Key returns the current key. It panics if the iterator is not positioned at a key.
+func (it *Iterator[K, V]) Key() K {
+	return it.current.key
+}
+
+// Value returns the value associated with the current key. It panics if the iterator is not positioned at a key.
+func (it *Iterator[K, V]) Value() V {
+	return it.current.value
+}
@@ -0,0 +1,454 @@
+// Package llrb provides a generic Left-Leaning Red-Black Tree whose nodes have no parent pointer.
+//
+// The nodes of rbtree.Tree, and of every tree extending bst.Tree, link to their parent, so that
+// Successor and Predecessor can walk the tree from any node. A left-leaning Red-Black Tree
+// rebalances on the way back up from its recursive insertions and deletions, so never needs to
+// walk up from a node, and its nodes can do without the parent pointer: one of their three links,
+// i.e. 8 of 24 bytes of links on 64-bit platforms.
+//
+// The tree suits write-once, read-many workloads, where the parent pointer is pure overhead.
+// In exchange, ordered traversal keeps an explicit stack of the ancestors of the current node
+// (see Iterator), rather than following parent pointers, and as there is no parent pointer to
+// find its way back from a node, the API is key-based rather than node-based. It mirrors the API
+// of btree.Tree and indextree.Tree (Insert, Search, Delete, Min, Max, Floor, Ceiling, Ascend,
+// AscendRange and Descend), so that the tree implements trees.Sorted. Keys are ordered with the
+// same LessFunc used by the bst and rbtree packages.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/llrb"
+//
+//	tree := llrb.New[int, string](func(a, b int) bool { return a < b })
+//	tree.Insert(10, "ten")
+//	tree.Insert(20, "twenty")
+//	value, found := tree.Search(10)
+//
+//	for it := tree.Iter(); it.Next(); {
+//		fmt.Println(it.Key(), it.Value())
+//	}
+//
+// Concurrent is a variant safe for concurrent use, whose readers never wait: writers copy the nodes
+// they modify, and publish each new version of the tree by atomically swapping its root.
+//
+// # Limitations
+//
+// Keys are unique, and Tree is not safe for concurrent use.
+package llrb
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/bst"
+)
+
+// node represents a single entry of the tree. It has no parent pointer.
+type node[K, V any] struct {
+	key         K
+	value       V
+	left, right *node[K, V]
+	red         bool
+}
+
+// Tree represents a Left-Leaning Red-Black Tree of key-value pairs, ordered by a LessFunc.
+//
+// Trees must be created with New.
+type Tree[K, V any] struct {
+	root   *node[K, V]     // Root node, or nil if the tree is empty.
+	size   int             // Number of entries in the tree.
+	less   bst.LessFunc[K] // Function to compare keys and maintain order.
+	shared bool            // Nodes may be shared with other trees, and are copied before being modified (see Concurrent).
+}
+
+// New creates and returns a new empty tree.
+//
+// Parameters:
+//   - less: A function that defines the ordering of keys.
+//
+// Returns:
+//   - A pointer to a newly created Tree[K, V] instance.
+func New[K, V any](less bst.LessFunc[K]) *Tree[K, V] {
+	return &Tree[K, V]{less: less}
+}
+
+// Size returns the number of entries in the tree.
+//
+// This is an O(1) operation.
+func (t *Tree[K, V]) Size() int {
+	return t.size
+}
+
+// find returns the node with the given key, or nil if there is none.
+func (t *Tree[K, V]) find(key K) *node[K, V] {
+	x := t.root
+	for x != nil {
+		switch {
+		case t.less(key, x.key):
+			x = x.left
+		case t.less(x.key, key):
+			x = x.right
+		default:
+			return x
+		}
+	}
+	return nil
+}
+
+// Search looks up the value associated with key.
+//
+// Returns:
+//   - (value, true) if the key is found.
+//   - (zero value, false) if the key is not in the tree.
+func (t *Tree[K, V]) Search(key K) (V, bool) {
+	if x := t.find(key); x != nil {
+		return x.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Insert adds a key-value pair to the tree, or updates the value if the key already exists.
+//
+// Returns:
+//   - true if a new key was inserted.
+//   - false if an existing key's value was updated.
+func (t *Tree[K, V]) Insert(key K, value V) bool {
+	size := t.size
+	t.root = t.insert(t.root, key, value)
+	t.root.red = false
+	return t.size != size
+}
+
+// insert inserts key and value into the subtree rooted at h, and returns the new root of the subtree.
+func (t *Tree[K, V]) insert(h *node[K, V], key K, value V) *node[K, V] {
+	if h == nil {
+		t.size++
+		return &node[K, V]{key: key, value: value, red: true}
+	}
+	h = t.mutable(h)
+	switch {
+	case t.less(key, h.key):
+		h.left = t.insert(h.left, key, value)
+	case t.less(h.key, key):
+		h.right = t.insert(h.right, key, value)
+	default:
+		h.value = value
+		return h
+	}
+	return t.balance(h)
+}
+
+// Delete removes key from the tree.
+//
+// Returns:
+//   - true if the key was found and removed.
+//   - false if the key was not in the tree.
+func (t *Tree[K, V]) Delete(key K) bool {
+	if t.find(key) == nil {
+		return false
+	}
+	if !isRed(t.root.left) && !isRed(t.root.right) {
+		t.root = t.mutable(t.root)
+		t.root.red = true
+	}
+	t.root = t.delete(t.root, key)
+	if t.root != nil {
+		t.root.red = false
+	}
+	t.size--
+	return true
+}
+
+// delete removes key, which must be in the subtree rooted at h, and returns the new root of the subtree.
+// On the way down, it keeps the current node or one of its children red, so that the node removed at the
+// bottom is red.
+func (t *Tree[K, V]) delete(h *node[K, V], key K) *node[K, V] {
+	h = t.mutable(h)
+	if t.less(key, h.key) {
+		if !isRed(h.left) && !isRed(h.left.left) {
+			h = t.moveRedLeft(h)
+		}
+		h.left = t.delete(h.left, key)
+		return t.balance(h)
+	}
+	if isRed(h.left) {
+		h = t.rotateRight(h)
+	}
+	if !t.less(h.key, key) && h.right == nil {
+		return nil
+	}
+	if !isRed(h.right) && !isRed(h.right.left) {
+		h = t.moveRedRight(h)
+	}
+	if !t.less(h.key, key) {
+		// replace the entry of h with its successor's, and remove the successor instead
+		successor := h.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+		h.key, h.value = successor.key, successor.value
+		h.right = t.deleteMin(h.right)
+	} else {
+		h.right = t.delete(h.right, key)
+	}
+	return t.balance(h)
+}
+
+// deleteMin removes the node with the smallest key from the subtree rooted at h,
+// and returns the new root of the subtree.
+func (t *Tree[K, V]) deleteMin(h *node[K, V]) *node[K, V] {
+	if h.left == nil {
+		return nil
+	}
+	h = t.mutable(h)
+	if !isRed(h.left) && !isRed(h.left.left) {
+		h = t.moveRedLeft(h)
+	}
+	h.left = t.deleteMin(h.left)
+	return t.balance(h)
+}
+
+// mutable returns x, or a copy of x to be modified in its place if the tree shares its nodes
+// with other trees (see Concurrent). Nodes are only modified once returned by mutable, or
+// created by the tree.
+func (t *Tree[K, V]) mutable(x *node[K, V]) *node[K, V] {
+	if !t.shared {
+		return x
+	}
+	c := *x
+	return &c
+}
+
+// isRed reports whether x is a red node. Missing children are black.
+func isRed[K, V any](x *node[K, V]) bool {
+	return x != nil && x.red
+}
+
+// rotateLeft makes the right child of h, which must be red, the root of the subtree, and returns it.
+// h must be mutable.
+func (t *Tree[K, V]) rotateLeft(h *node[K, V]) *node[K, V] {
+	x := t.mutable(h.right)
+	h.right = x.left
+	x.left = h
+	x.red, h.red = h.red, true
+	return x
+}
+
+// rotateRight makes the left child of h, which must be red, the root of the subtree, and returns it.
+// h must be mutable.
+func (t *Tree[K, V]) rotateRight(h *node[K, V]) *node[K, V] {
+	x := t.mutable(h.left)
+	h.left = x.right
+	x.right = h
+	x.red, h.red = h.red, true
+	return x
+}
+
+// flipColors flips the colors of h and its two children. h must be mutable.
+func (t *Tree[K, V]) flipColors(h *node[K, V]) {
+	h.left, h.right = t.mutable(h.left), t.mutable(h.right)
+	h.red = !h.red
+	h.left.red = !h.left.red
+	h.right.red = !h.right.red
+}
+
+// moveRedLeft makes the left child of h, or one of its children, red, assuming h is red and
+// both h.left and h.left.left are black. h must be mutable.
+func (t *Tree[K, V]) moveRedLeft(h *node[K, V]) *node[K, V] {
+	t.flipColors(h)
+	if isRed(h.right.left) {
+		h.right = t.rotateRight(h.right)
+		h = t.rotateLeft(h)
+		t.flipColors(h)
+	}
+	return h
+}
+
+// moveRedRight makes the right child of h, or one of its children, red, assuming h is red and
+// both h.right and h.right.left are black. h must be mutable.
+func (t *Tree[K, V]) moveRedRight(h *node[K, V]) *node[K, V] {
+	t.flipColors(h)
+	if isRed(h.left.left) {
+		h = t.rotateRight(h)
+		t.flipColors(h)
+	}
+	return h
+}
+
+// balance restores the left-leaning Red-Black properties at h, on the way back up from an
+// insertion or deletion, and returns the new root of the subtree. h must be mutable.
+func (t *Tree[K, V]) balance(h *node[K, V]) *node[K, V] {
+	if isRed(h.right) && !isRed(h.left) {
+		h = t.rotateLeft(h)
+	}
+	if isRed(h.left) && isRed(h.left.left) {
+		h = t.rotateRight(h)
+	}
+	if isRed(h.left) && isRed(h.right) {
+		t.flipColors(h)
+	}
+	return h
+}
+
+// Min returns the smallest key in the tree, and its value.
+//
+// Returns:
+//   - (key, value, true) if the tree is not empty.
+//   - (zero key, zero value, false) if the tree is empty.
+func (t *Tree[K, V]) Min() (K, V, bool) {
+	x := t.root
+	for x != nil && x.left != nil {
+		x = x.left
+	}
+	return entry(x)
+}
+
+// Max returns the largest key in the tree, and its value.
+//
+// Returns:
+//   - (key, value, true) if the tree is not empty.
+//   - (zero key, zero value, false) if the tree is empty.
+func (t *Tree[K, V]) Max() (K, V, bool) {
+	x := t.root
+	for x != nil && x.right != nil {
+		x = x.right
+	}
+	return entry(x)
+}
+
+// Floor returns the largest key less than or equal to key, and its value.
+//
+// Returns:
+//   - (key, value, true) if such a key exists.
+//   - (zero key, zero value, false) otherwise.
+func (t *Tree[K, V]) Floor(key K) (K, V, bool) {
+	var floor *node[K, V]
+	for x := t.root; x != nil; {
+		if t.less(key, x.key) {
+			x = x.left
+		} else {
+			floor, x = x, x.right
+		}
+	}
+	return entry(floor)
+}
+
+// Ceiling returns the smallest key greater than or equal to key, and its value.
+//
+// Returns:
+//   - (key, value, true) if such a key exists.
+//   - (zero key, zero value, false) otherwise.
+func (t *Tree[K, V]) Ceiling(key K) (K, V, bool) {
+	var ceiling *node[K, V]
+	for x := t.root; x != nil; {
+		if t.less(x.key, key) {
+			x = x.right
+		} else {
+			ceiling, x = x, x.left
+		}
+	}
+	return entry(ceiling)
+}
+
+// entry returns the key and value of x, and false if x is nil.
+func entry[K, V any](x *node[K, V]) (K, V, bool) {
+	if x == nil {
+		var (
+			zeroK K
+			zeroV V
+		)
+		return zeroK, zeroV, false
+	}
+	return x.key, x.value, true
+}
+
+// Ascend calls f for each key and value in ascending key order, until f returns false.
+func (t *Tree[K, V]) Ascend(f func(key K, value V) bool) {
+	for it := t.Iter(); it.Next(); {
+		if !f(it.Key(), it.Value()) {
+			return
+		}
+	}
+}
+
+// AscendRange calls f for each key in the half-open interval [lo, hi) and its value,
+// in ascending key order, until f returns false.
+func (t *Tree[K, V]) AscendRange(lo, hi K, f func(key K, value V) bool) {
+	it := t.Iter()
+	it.Seek(lo)
+	for it.Next() && t.less(it.Key(), hi) {
+		if !f(it.Key(), it.Value()) {
+			return
+		}
+	}
+}
+
+// Descend calls f for each key and value in descending key order, until f returns false.
+func (t *Tree[K, V]) Descend(f func(key K, value V) bool) {
+	var stack []*node[K, V]
+	for x := t.root; x != nil || len(stack) > 0; {
+		for ; x != nil; x = x.right {
+			stack = append(stack, x)
+		}
+		x = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !f(x.key, x.value) {
+			return
+		}
+		x = x.left
+	}
+}
+
+// IsTreeValid checks whether the tree is a valid Left-Leaning Red-Black Tree:
+//   - The root is black.
+//   - Keys are in order.
+//   - No node has a red right child, and no red node has a red child.
+//   - Every path from the root to a leaf has the same number of black nodes.
+//   - The number of nodes matches the size of the tree.
+//
+// Returns:
+//   - nil if the tree is valid.
+//   - An error describing the first violation found otherwise.
+func (t *Tree[K, V]) IsTreeValid() error {
+	if isRed(t.root) {
+		return fmt.Errorf("root node is red")
+	}
+	count := 0
+	if _, err := t.validate(t.root, nil, nil, &count); err != nil {
+		return err
+	}
+	if count != t.size {
+		return fmt.Errorf("%d nodes reachable from the root, but the tree holds %d", count, t.size)
+	}
+	return nil
+}
+
+// validate checks the subtree rooted at x, whose keys must lie within (lo, hi) where a nil bound is unbounded,
+// and returns its black height.
+func (t *Tree[K, V]) validate(x *node[K, V], lo, hi *K, count *int) (int, error) {
+	if x == nil {
+		return 1, nil
+	}
+	*count++
+	switch {
+	case (lo != nil && !t.less(*lo, x.key)) || (hi != nil && !t.less(x.key, *hi)):
+		return 0, fmt.Errorf("key %v is out of order", x.key)
+	case isRed(x.right):
+		return 0, fmt.Errorf("node %v has a red right child", x.key)
+	case x.red && isRed(x.left):
+		return 0, fmt.Errorf("red node %v has a red child", x.key)
+	}
+	left, err := t.validate(x.left, lo, &x.key, count)
+	if err != nil {
+		return 0, err
+	}
+	right, err := t.validate(x.right, &x.key, hi, count)
+	if err != nil {
+		return 0, err
+	}
+	if left != right {
+		return 0, fmt.Errorf("black height mismatch at node %v: %d left, %d right", x.key, left, right)
+	}
+	if !x.red {
+		left++
+	}
+	return left, nil
+}
@@ -0,0 +1,136 @@
+package llrb
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"sort"
+	"testing"
+	"unsafe"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+func TestTree_InsertSearchDelete(t *testing.T) {
+	tree := New[int, int](intLess)
+	rng := rand.New(rand.NewSource(1))
+	expected := make(map[int]int)
+
+	for i := 0; i < 20000; i++ {
+		key := rng.Intn(1000)
+		_, exists := expected[key]
+		if rng.Intn(3) == 0 {
+			assert.Equal(t, exists, tree.Delete(key), "unexpected Delete(%d) result", key)
+			delete(expected, key)
+		} else {
+			assert.Equal(t, !exists, tree.Insert(key, i), "unexpected Insert(%d) result", key)
+			expected[key] = i
+		}
+		if i%500 == 0 {
+			require.NoError(t, tree.IsTreeValid())
+		}
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, len(expected), tree.Size())
+
+	for key, value := range expected {
+		v, found := tree.Search(key)
+		require.True(t, found, "expected key %d to be found", key)
+		assert.Equal(t, value, v)
+	}
+	var keys []int
+	for key := range expected {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	var visited []int
+	tree.Ascend(func(key, value int) bool {
+		visited = append(visited, key)
+		return true
+	})
+	assert.Equal(t, keys, visited)
+
+	// delete everything
+	for _, key := range keys {
+		require.True(t, tree.Delete(key))
+		require.False(t, tree.Delete(key))
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 0, tree.Size())
+	_, _, found := tree.Min()
+	assert.False(t, found)
+}
+
+func TestTree_sequential(t *testing.T) {
+	tree := New[int, struct{}](intLess)
+	for i := 0; i < 10000; i++ {
+		tree.Insert(i, struct{}{})
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 10000, tree.Size())
+	for i := 0; i < 10000; i += 2 {
+		require.True(t, tree.Delete(i))
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 5000, tree.Size())
+	key, _, found := tree.Floor(100)
+	assert.True(t, found)
+	assert.Equal(t, 99, key)
+	key, _, found = tree.Ceiling(100)
+	assert.True(t, found)
+	assert.Equal(t, 101, key)
+	key, _, _ = tree.Max()
+	assert.Equal(t, 9999, key)
+}
+
+func TestTree_nodeSize(t *testing.T) {
+	// two pointers and a color fit in 24 bytes, where three pointers and a color take 32 on 64-bit platforms
+	assert.Equal(t, uintptr(24), unsafe.Sizeof(node[struct{}, struct{}]{}))
+}
+
+func TestTree_Descend(t *testing.T) {
+	tree := New[int, string](intLess)
+	for _, key := range []int{5, 1, 4, 2, 3} {
+		tree.Insert(key, "value")
+	}
+	var keys []int
+	tree.Descend(func(key int, value string) bool {
+		keys = append(keys, key)
+		return key > 3
+	})
+	assert.Equal(t, []int{5, 4, 3}, keys)
+}
+
+func TestIterator(t *testing.T) {
+	tree := New[int, int](intLess)
+	it := tree.Iter()
+	assert.False(t, it.Next(), "expected empty tree to have no keys")
+
+	for i := 0; i < 100; i += 10 {
+		tree.Insert(i, i*2)
+	}
+
+	it = tree.Iter()
+	var keys []int
+	for it.Next() {
+		assert.Equal(t, it.Key()*2, it.Value())
+		keys = append(keys, it.Key())
+	}
+	assert.Equal(t, []int{0, 10, 20, 30, 40, 50, 60, 70, 80, 90}, keys)
+	assert.Panics(t, func() { it.Key() }, "expected Key to panic once exhausted")
+
+	for key, expected := range map[int][]int{
+		-5: {0, 10, 20, 30, 40, 50, 60, 70, 80, 90},
+		35: {40, 50, 60, 70, 80, 90},
+		40: {40, 50, 60, 70, 80, 90},
+		90: {90},
+		95: nil,
+	} {
+		it.Seek(key)
+		keys = nil
+		for it.Next() {
+			keys = append(keys, it.Key())
+		}
+		assert.Equal(t, expected, keys, "unexpected keys after Seek(%d)", key)
+	}
+}
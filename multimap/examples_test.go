@@ -0,0 +1,31 @@
+package multimap_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/multimap"
+)
+
+func ExampleMap_DeleteValue() {
+
+	// create a map of course enrolments
+	courses := multimap.New[string, string](func(a, b string) bool {
+		return a < b
+	})
+	courses.Append("maths", "ann")
+	courses.Append("physics", "bob")
+	courses.Append("maths", "cal")
+	courses.Append("maths", "dee")
+
+	// a student withdraws
+	courses.DeleteValue("maths", "cal")
+
+	courses.Range(func(course, student string) bool {
+		fmt.Println(course, student)
+		return true
+	})
+
+	// Output:
+	// maths ann
+	// maths dee
+	// physics bob
+}
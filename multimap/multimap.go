@@ -0,0 +1,151 @@
+// Package multimap provides a generic ordered multimap, where each key maps to an ordered
+// collection of values.
+//
+// Map wraps an rbtree.Tree in multiset mode (see bst.WithDuplicateKeys): each value is held in its
+// own node, and nodes with equal keys are kept in the order their values were appended.
+// No slices of values need to be maintained by hand, and operations take O(log n + m) time,
+// where m is the number of values under the key.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/multimap"
+//
+//	m := multimap.New[string, int](func(a, b string) bool { return a < b })
+//	m.Append("a", 1)
+//	m.Append("a", 2)
+//	m.Append("b", 3)
+//	values := m.Get("a") // [1 2]
+//	m.DeleteValue("a", 1)
+//
+// # Limitations
+//
+// The map is not safe for concurrent use.
+package multimap
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"github.com/mikenye/gotrees/rbtree"
+)
+
+// Map represents an ordered multimap from keys of type K to values of type V, ordered by a LessFunc.
+//
+// Maps must be created with New.
+type Map[K any, V comparable] struct {
+	tree *rbtree.Tree[K, V] // Underlying Red-Black Tree, in multiset mode
+	less bst.LessFunc[K]    // Function to compare keys and maintain order
+}
+
+// New creates and returns a new empty multimap.
+//
+// Parameters:
+//   - less: A function that defines the ordering of keys.
+//
+// Returns:
+//   - A pointer to a newly created Map[K, V] instance.
+func New[K any, V comparable](less bst.LessFunc[K]) *Map[K, V] {
+	return &Map[K, V]{
+		tree: rbtree.New[K, V](less, bst.WithDuplicateKeys()),
+		less: less,
+	}
+}
+
+// Len returns the total number of values in the map, across all keys.
+//
+// This is an O(1) operation.
+func (m *Map[K, V]) Len() int {
+	return m.tree.Size()
+}
+
+// Count returns the number of values under key, in O(log n) time.
+func (m *Map[K, V]) Count(key K) int {
+	return m.tree.Count(key)
+}
+
+// Append adds value after any existing values under key.
+func (m *Map[K, V]) Append(key K, value V) {
+	m.tree.Insert(key, value)
+}
+
+// Get returns the values under key, in the order they were appended.
+//
+// Returns:
+//   - A new slice holding the values, or nil if the key is not in the map.
+func (m *Map[K, V]) Get(key K) []V {
+	var values []V
+	m.each(key, func(n *bst.Node[K, V, rbtree.Color]) bool {
+		values = append(values, m.tree.Value(n))
+		return true
+	})
+	return values
+}
+
+// DeleteValue removes the first occurrence of value under key.
+//
+// Returns:
+//   - true if the value was found and removed.
+//   - false if the value is not under key.
+func (m *Map[K, V]) DeleteValue(key K, value V) bool {
+	var found *bst.Node[K, V, rbtree.Color]
+	m.each(key, func(n *bst.Node[K, V, rbtree.Color]) bool {
+		if m.tree.Value(n) == value {
+			found = n
+			return false
+		}
+		return true
+	})
+	if found == nil {
+		return false
+	}
+	_, deleted := m.tree.Delete(found)
+	return deleted
+}
+
+// DeleteKey removes every value under key.
+//
+// Returns:
+//   - The number of values removed.
+func (m *Map[K, V]) DeleteKey(key K) int {
+	count := 0
+	for n, found := m.tree.Search(key); found; n, found = m.tree.Search(key) {
+		m.tree.Delete(n)
+		count++
+	}
+	return count
+}
+
+// each calls f for each node under key, in order, until f returns false.
+func (m *Map[K, V]) each(key K, f func(n *bst.Node[K, V, rbtree.Color]) bool) {
+	n, found := m.tree.Search(key)
+	if !found {
+		return
+	}
+	for ; !m.tree.IsNil(n) && !m.less(key, m.tree.Key(n)); n = m.tree.Successor(n) {
+		if !f(n) {
+			return
+		}
+	}
+}
+
+// Keys returns the distinct keys of the map, in ascending order.
+func (m *Map[K, V]) Keys() []K {
+	var keys []K
+	m.Range(func(key K, _ V) bool {
+		if len(keys) == 0 || m.less(keys[len(keys)-1], key) {
+			keys = append(keys, key)
+		}
+		return true
+	})
+	return keys
+}
+
+// Range calls f for each key and value in ascending key order, until f returns false.
+// Values under the same key are visited in the order they were appended.
+//
+// The map must not be modified during iteration.
+func (m *Map[K, V]) Range(f func(key K, value V) bool) {
+	for n := m.tree.Min(m.tree.Root()); !m.tree.IsNil(n); n = m.tree.Successor(n) {
+		if !f(m.tree.Key(n), m.tree.Value(n)) {
+			return
+		}
+	}
+}
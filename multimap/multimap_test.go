@@ -0,0 +1,62 @@
+package multimap
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+func TestMap(t *testing.T) {
+	m := New[int, int](intLess)
+	rng := rand.New(rand.NewSource(1))
+	expected := make(map[int][]int)
+
+	for i := 0; i < 10000; i++ {
+		key := rng.Intn(50)
+		switch rng.Intn(10) {
+		case 0:
+			assert.Equal(t, len(expected[key]), m.DeleteKey(key))
+			delete(expected, key)
+		case 1, 2, 3:
+			value := rng.Intn(20)
+			j := slices.Index(expected[key], value)
+			assert.Equal(t, j >= 0, m.DeleteValue(key, value), "unexpected result deleting %d from %d", value, key)
+			if j >= 0 {
+				expected[key] = slices.Delete(expected[key], j, j+1)
+				if len(expected[key]) == 0 {
+					delete(expected, key)
+				}
+			}
+		default:
+			value := rng.Intn(20)
+			m.Append(key, value)
+			expected[key] = append(expected[key], value)
+		}
+	}
+	assert.NoError(t, m.tree.IsTreeValid())
+
+	total := 0
+	var keys []int
+	for key, values := range expected {
+		assert.Equal(t, values, m.Get(key), "unexpected values under %d", key)
+		assert.Equal(t, len(values), m.Count(key))
+		total += len(values)
+		keys = append(keys, key)
+	}
+	assert.Equal(t, total, m.Len())
+	sort.Ints(keys)
+	assert.Equal(t, keys, m.Keys())
+	assert.Nil(t, m.Get(-1))
+	assert.False(t, m.DeleteValue(-1, 0))
+
+	count := 0
+	m.Range(func(key, value int) bool {
+		count++
+		return count < 5
+	})
+	assert.Equal(t, 5, count, "expected iteration to stop early")
+}
@@ -0,0 +1,208 @@
+package pq
+
+import (
+	"context"
+	"github.com/mikenye/gotrees/bst"
+	"sync"
+	"time"
+)
+
+// Blocking represents a priority queue safe for concurrent use, whose consumers can wait for an item
+// with Blocking.PopMinCtx, for use as a work queue.
+//
+// Created with NewDelay, it is a delay queue: each item's priority is the time it becomes due, and
+// items are only popped once due, so that consumers act as a scheduler.
+//
+// Items returned by Blocking.Push remain valid handles for Blocking.Update and Blocking.Remove, but their
+// Priority method must not be called concurrently with Blocking.Update.
+//
+// Blocking queues must be created with NewBlocking or NewDelay.
+type Blocking[T, P any] struct {
+	mu    sync.Mutex
+	queue *Queue[T, P]        // Underlying queue, guarded by mu
+	wake  chan struct{}       // Closed and replaced when the next item to be popped may change
+	due   func(p P) time.Time // Time an item of priority p becomes due, or nil if items are due at once
+}
+
+// NewBlocking creates and returns a new empty blocking priority queue.
+//
+// Parameters:
+//   - less: A function that defines the ordering of priorities. Items with smaller priorities are popped first.
+//
+// Returns:
+//   - A pointer to a newly created Blocking[T, P] instance.
+func NewBlocking[T, P any](less bst.LessFunc[P]) *Blocking[T, P] {
+	return &Blocking[T, P]{
+		queue: New[T, P](less),
+		wake:  make(chan struct{}),
+	}
+}
+
+// NewDelay creates and returns a new empty delay queue, whose items are prioritized by the time they
+// become due, and only popped once due.
+//
+// Example Usage:
+//
+//	q := pq.NewDelay[string]()
+//	q.Push("send reminder", time.Now().Add(time.Hour))
+//	task, due, err := q.PopMinCtx(ctx) // waits for an hour
+//
+// Returns:
+//   - A pointer to a newly created Blocking[T, time.Time] instance.
+func NewDelay[T any]() *Blocking[T, time.Time] {
+	q := NewBlocking[T, time.Time](time.Time.Before)
+	q.due = func(p time.Time) time.Time {
+		return p
+	}
+	return q
+}
+
+// notify wakes every consumer waiting in PopMinCtx, to check the next item again. mu must be held.
+func (q *Blocking[T, P]) notify() {
+	close(q.wake)
+	q.wake = make(chan struct{})
+}
+
+// Len returns the number of items in the queue, including items not yet due.
+//
+// This is an O(1) operation.
+func (q *Blocking[T, P]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queue.Len()
+}
+
+// Push adds value to the queue with the given priority, after any items of equal priority,
+// waking the consumers waiting in PopMinCtx.
+//
+// Returns:
+//   - The newly added item.
+func (q *Blocking[T, P]) Push(value T, priority P) *Item[T, P] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	item := q.queue.Push(value, priority)
+	q.notify()
+	return item
+}
+
+// Peek returns the value and priority of the next item to be popped, without removing it,
+// even if it is not yet due.
+//
+// Returns:
+//   - (value, priority, true) if the queue is not empty.
+//   - (zero value, zero priority, false) if the queue is empty.
+func (q *Blocking[T, P]) Peek() (T, P, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queue.Peek()
+}
+
+// PopMin removes and returns the value and priority of the item with the smallest priority, without
+// waiting. Among items of equal priority, the earliest pushed (or updated) is popped first.
+//
+// Returns:
+//   - (value, priority, true) if the queue holds an item that is due.
+//   - (zero value, zero priority, false) if the queue is empty, or its next item is not yet due.
+func (q *Blocking[T, P]) PopMin() (T, P, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	value, priority, _, ok := q.pop()
+	return value, priority, ok
+}
+
+// pop removes and returns the next item if it is due, or returns how long until it is due otherwise,
+// or -1 if the queue is empty. mu must be held.
+func (q *Blocking[T, P]) pop() (T, P, time.Duration, bool) {
+	value, priority, ok := q.queue.Peek()
+	if !ok {
+		return value, priority, -1, false
+	}
+	if q.due != nil {
+		if wait := time.Until(q.due(priority)); wait > 0 {
+			var zeroT T
+			var zeroP P
+			return zeroT, zeroP, wait, false
+		}
+	}
+	q.queue.Pop()
+	return value, priority, 0, true
+}
+
+// PopMinCtx removes and returns the value and priority of the item with the smallest priority, waiting
+// until the queue holds an item that is due, or ctx is done.
+//
+// Consumers waiting in PopMinCtx are woken when an item is pushed or updated, and, for a delay
+// queue, when the next item becomes due. Each item is popped by a single consumer.
+//
+// Example Usage:
+//
+//	// consume the queue until ctx is cancelled
+//	for {
+//		task, _, err := q.PopMinCtx(ctx)
+//		if err != nil {
+//			return err
+//		}
+//		task.Run()
+//	}
+//
+// Returns:
+//   - (value, priority, nil) once an item is popped.
+//   - (zero value, zero priority, ctx.Err()) if ctx is done first.
+func (q *Blocking[T, P]) PopMinCtx(ctx context.Context) (T, P, error) {
+	var err error
+	for {
+		q.mu.Lock()
+		value, priority, wait, ok := q.pop()
+		wake := q.wake
+		q.mu.Unlock()
+		if ok {
+			return value, priority, nil
+		}
+
+		// wait for a change to the queue, for the next item to become due, or for ctx to be done
+		var timer *time.Timer
+		var due <-chan time.Time
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			due = timer.C
+		}
+		select {
+		case <-wake:
+		case <-due:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if err != nil {
+			return value, priority, err
+		}
+	}
+}
+
+// Update changes the priority of item, as with Queue.Update, waking consumers waiting in PopMinCtx.
+//
+// Returns:
+//   - true if the priority was updated.
+//   - false if the item is no longer in the queue, or belongs to a different queue.
+func (q *Blocking[T, P]) Update(item *Item[T, P], priority P) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.queue.Update(item, priority) {
+		return false
+	}
+	q.notify()
+	return true
+}
+
+// Remove removes item from the queue, e.g. to cancel a scheduled task.
+//
+// Returns:
+//   - true if the item was removed.
+//   - false if the item is no longer in the queue, or belongs to a different queue.
+func (q *Blocking[T, P]) Remove(item *Item[T, P]) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queue.Remove(item)
+}
@@ -0,0 +1,146 @@
+package pq
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBlocking(t *testing.T) {
+	q := NewBlocking[string, int](intLess)
+	_, _, ok := q.PopMin()
+	assert.False(t, ok)
+
+	q.Push("b", 2)
+	a := q.Push("a", 3)
+	q.Push("c", 2)
+	assert.True(t, q.Update(a, 1))
+	value, priority, ok := q.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, "a", value)
+	assert.Equal(t, 1, priority)
+	assert.Equal(t, 3, q.Len())
+
+	var popped []string
+	for q.Len() > 0 {
+		value, _, err := q.PopMinCtx(context.Background())
+		require.NoError(t, err)
+		popped = append(popped, value)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, popped)
+	assert.False(t, q.Remove(a), "expected a popped item not to be removed")
+}
+
+func TestBlocking_PopMinCtx(t *testing.T) {
+	q := NewBlocking[string, int](intLess)
+
+	// a consumer waits for an item to be pushed
+	done := make(chan string)
+	go func() {
+		value, _, err := q.PopMinCtx(context.Background())
+		assert.NoError(t, err)
+		done <- value
+	}()
+	time.Sleep(10 * time.Millisecond)
+	q.Push("work", 1)
+	assert.Equal(t, "work", <-done)
+
+	// a consumer gives up when its context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err := q.PopMinCtx(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestBlocking_concurrent(t *testing.T) {
+	q := NewBlocking[int, int](intLess)
+	ctx, cancel := context.WithCancel(context.Background())
+	var (
+		mu     sync.Mutex
+		seen   = make(map[int]int)
+		wg     sync.WaitGroup
+		popped sync.WaitGroup
+	)
+	const items = 2000
+	popped.Add(items)
+	for c := 0; c < 4; c++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				value, _, err := q.PopMinCtx(ctx)
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				seen[value]++
+				mu.Unlock()
+				popped.Done()
+			}
+		}()
+	}
+	for p := 0; p < 4; p++ {
+		go func(p int) {
+			for i := 0; i < items/4; i++ {
+				q.Push(p*items+i, i)
+			}
+		}(p)
+	}
+	popped.Wait()
+	cancel()
+	wg.Wait()
+
+	assert.Len(t, seen, items)
+	for value, count := range seen {
+		assert.Equal(t, 1, count, "expected %d to be popped once", value)
+	}
+}
+
+func TestDelay(t *testing.T) {
+	q := NewDelay[string]()
+	start := time.Now()
+	q.Push("later", start.Add(60*time.Millisecond))
+	q.Push("soon", start.Add(30*time.Millisecond))
+	past := q.Push("past", start.Add(-time.Second))
+	cancelled := q.Push("cancelled", start.Add(40*time.Millisecond))
+	assert.True(t, q.Remove(cancelled))
+
+	value, _, ok := q.PopMin()
+	assert.True(t, ok)
+	assert.Equal(t, "past", value, "expected a due item to be popped")
+	_, _, ok = q.PopMin()
+	assert.False(t, ok, "expected items not yet due to stay in the queue")
+	assert.False(t, q.Update(past, start), "expected a popped item not to be updated")
+
+	for _, expected := range []string{"soon", "later"} {
+		value, due, err := q.PopMinCtx(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, expected, value)
+		assert.False(t, time.Now().Before(due), "expected %s to be popped once due", value)
+	}
+	assert.Equal(t, 0, q.Len())
+}
+
+func TestDelay_Update(t *testing.T) {
+	q := NewDelay[string]()
+	item := q.Push("task", time.Now().Add(time.Hour))
+
+	// a consumer waiting for the task is woken when it is brought forward
+	done := make(chan string)
+	go func() {
+		value, _, err := q.PopMinCtx(context.Background())
+		assert.NoError(t, err)
+		done <- value
+	}()
+	time.Sleep(10 * time.Millisecond)
+	q.Update(item, time.Now())
+	select {
+	case value := <-done:
+		assert.Equal(t, "task", value)
+	case <-time.After(time.Second):
+		t.Fatal("expected the consumer to be woken")
+	}
+}
@@ -0,0 +1,59 @@
+package pq_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/mikenye/gotrees/pq"
+	"time"
+)
+
+func ExampleQueue_Update() {
+
+	// create a queue of tasks, lowest priority value first
+	tasks := pq.New[string, int](func(a, b int) bool {
+		return a < b
+	})
+	tasks.Push("write docs", 2)
+	tasks.Push("review PR", 2)
+	deploy := tasks.Push("deploy", 3)
+	tasks.Push("reply to email", 1)
+
+	// the deploy becomes urgent
+	tasks.Update(deploy, 1)
+
+	for tasks.Len() > 0 {
+		task, priority, _ := tasks.Pop()
+		fmt.Println(priority, task)
+	}
+
+	// Output:
+	// 1 reply to email
+	// 1 deploy
+	// 2 write docs
+	// 2 review PR
+}
+
+func ExampleNewDelay() {
+
+	// schedule tasks, each due after a delay
+	start := time.Now()
+	tasks := pq.NewDelay[string]()
+	tasks.Push("second", start.Add(20*time.Millisecond))
+	tasks.Push("first", start.Add(10*time.Millisecond))
+
+	// consume the tasks as they become due
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for tasks.Len() > 0 {
+		task, _, err := tasks.PopMinCtx(ctx)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(task)
+	}
+
+	// Output:
+	// first
+	// second
+}
@@ -0,0 +1,187 @@
+// Package pq provides a generic priority queue with priority updates and stable ordering.
+//
+// Queue is backed by an rbtree.Tree keyed by (priority, arrival order), so:
+//   - Items with equal priorities are popped in first-in, first-out order.
+//   - Push, Pop, Update and Remove take O(log n) time, and Peek O(log n) time.
+//
+// Push returns an Item handle, which is used to change the item's priority (Queue.Update)
+// or to remove it (Queue.Remove) without searching the queue, the operations that
+// container/heap makes painful.
+//
+// Items are popped in ascending priority order, as defined by a LessFunc.
+// For a max-priority queue, reverse the comparison.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/pq"
+//
+//	q := pq.New[string, int](func(a, b int) bool { return a < b })
+//	q.Push("write docs", 3)
+//	fix := q.Push("fix bug", 2)
+//	q.Update(fix, 1)
+//	task, priority, ok := q.Pop() // "fix bug", 1, true
+//
+// Blocking is a variant safe for concurrent use, whose consumers wait for items with
+// Blocking.PopMinCtx, for use as a work queue, or, created with NewDelay, as a delay queue
+// popping items once they are due.
+//
+// # Limitations
+//
+// Queue is not safe for concurrent use: use Blocking instead.
+package pq
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"github.com/mikenye/gotrees/rbtree"
+)
+
+// key orders the items of a queue by priority, then by arrival.
+type key[P any] struct {
+	priority P
+	seq      uint64 // arrival order, increasing with each push or update
+}
+
+// Item represents an item held in a queue.
+//
+// Items are created by Queue.Push, and remain valid until they are popped or removed.
+type Item[T, P any] struct {
+	value    T
+	priority P
+	node     *bst.Node[key[P], *Item[T, P], rbtree.Color] // node holding the item, nil once it leaves the queue
+}
+
+// Value returns the value of the item.
+func (i *Item[T, P]) Value() T {
+	return i.value
+}
+
+// Priority returns the priority of the item.
+func (i *Item[T, P]) Priority() P {
+	return i.priority
+}
+
+// Queue represents a priority queue of values of type T, with priorities of type P.
+//
+// Queues must be created with New.
+type Queue[T, P any] struct {
+	tree *rbtree.Tree[key[P], *Item[T, P]] // Underlying Red-Black Tree
+	seq  uint64                            // Arrival order of the next item
+}
+
+// New creates and returns a new empty priority queue.
+//
+// Parameters:
+//   - less: A function that defines the ordering of priorities. Items with smaller priorities are popped first.
+//
+// Returns:
+//   - A pointer to a newly created Queue[T, P] instance.
+func New[T, P any](less bst.LessFunc[P]) *Queue[T, P] {
+	return &Queue[T, P]{
+		tree: rbtree.New[key[P], *Item[T, P]](func(a, b key[P]) bool {
+			if less(a.priority, b.priority) {
+				return true
+			}
+			if less(b.priority, a.priority) {
+				return false
+			}
+			return a.seq < b.seq
+		}),
+	}
+}
+
+// Len returns the number of items in the queue.
+//
+// This is an O(1) operation.
+func (q *Queue[T, P]) Len() int {
+	return q.tree.Size()
+}
+
+// Push adds value to the queue with the given priority, after any items of equal priority.
+//
+// Returns:
+//   - The newly added item.
+func (q *Queue[T, P]) Push(value T, priority P) *Item[T, P] {
+	item := &Item[T, P]{value: value}
+	q.insert(item, priority)
+	return item
+}
+
+// insert links item into the tree with the given priority, as the latest arrival.
+func (q *Queue[T, P]) insert(item *Item[T, P], priority P) {
+	item.priority = priority
+	item.node, _ = q.tree.Insert(key[P]{priority: priority, seq: q.seq}, item)
+	q.seq++
+}
+
+// Peek returns the value and priority of the next item to be popped, without removing it.
+//
+// Returns:
+//   - (value, priority, true) if the queue is not empty.
+//   - (zero value, zero priority, false) if the queue is empty.
+func (q *Queue[T, P]) Peek() (T, P, bool) {
+	n := q.tree.Min(q.tree.Root())
+	if q.tree.IsNil(n) {
+		var value T
+		var priority P
+		return value, priority, false
+	}
+	item := q.tree.Value(n)
+	return item.value, item.priority, true
+}
+
+// Pop removes and returns the value and priority of the item with the smallest priority.
+// Among items of equal priority, the earliest pushed (or updated) is popped first.
+//
+// Returns:
+//   - (value, priority, true) if the queue is not empty.
+//   - (zero value, zero priority, false) if the queue is empty.
+func (q *Queue[T, P]) Pop() (T, P, bool) {
+	n := q.tree.Min(q.tree.Root())
+	if q.tree.IsNil(n) {
+		var value T
+		var priority P
+		return value, priority, false
+	}
+	item := q.tree.Value(n)
+	q.remove(item)
+	return item.value, item.priority, true
+}
+
+// Update changes the priority of item. The item is placed after any items of equal priority,
+// as if it had just been pushed.
+//
+// Returns:
+//   - true if the priority was updated.
+//   - false if the item is no longer in the queue, or belongs to a different queue.
+func (q *Queue[T, P]) Update(item *Item[T, P], priority P) bool {
+	if !q.contains(item) {
+		return false
+	}
+	q.remove(item)
+	q.insert(item, priority)
+	return true
+}
+
+// Remove removes item from the queue.
+//
+// Returns:
+//   - true if the item was removed.
+//   - false if the item is no longer in the queue, or belongs to a different queue.
+func (q *Queue[T, P]) Remove(item *Item[T, P]) bool {
+	if !q.contains(item) {
+		return false
+	}
+	q.remove(item)
+	return true
+}
+
+// contains returns true if item is in the queue.
+func (q *Queue[T, P]) contains(item *Item[T, P]) bool {
+	return item != nil && q.tree.Contains(item.node)
+}
+
+// remove unlinks item from the tree.
+func (q *Queue[T, P]) remove(item *Item[T, P]) {
+	q.tree.Delete(item.node)
+	item.node = nil
+}
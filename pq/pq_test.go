@@ -0,0 +1,85 @@
+package pq
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+func TestQueue(t *testing.T) {
+	q := New[int, int](intLess)
+	_, _, ok := q.Pop()
+	assert.False(t, ok)
+	_, _, ok = q.Peek()
+	assert.False(t, ok)
+
+	// model of the queue: items in arrival order
+	type entry struct {
+		item     *Item[int, int]
+		priority int
+		seq      int
+	}
+	rng := rand.New(rand.NewSource(1))
+	var entries []*entry
+	seq := 0
+	sortEntries := func() {
+		sort.Slice(entries, func(i, j int) bool {
+			if entries[i].priority != entries[j].priority {
+				return entries[i].priority < entries[j].priority
+			}
+			return entries[i].seq < entries[j].seq
+		})
+	}
+
+	for i := 0; i < 10000; i++ {
+		switch op := rng.Intn(8); {
+		case op == 0 && len(entries) > 0:
+			sortEntries()
+			value, priority, ok := q.Pop()
+			require.True(t, ok)
+			assert.Equal(t, entries[0].item.Value(), value, "expected FIFO order among equal priorities")
+			assert.Equal(t, entries[0].priority, priority)
+			assert.False(t, q.Update(entries[0].item, 0), "expected popped item to be stale")
+			entries = entries[1:]
+		case op == 1 && len(entries) > 0:
+			e := entries[rng.Intn(len(entries))]
+			e.priority, e.seq = rng.Intn(10), seq
+			seq++
+			assert.True(t, q.Update(e.item, e.priority))
+			assert.Equal(t, e.priority, e.item.Priority())
+		case op == 2 && len(entries) > 0:
+			j := rng.Intn(len(entries))
+			assert.True(t, q.Remove(entries[j].item))
+			assert.False(t, q.Remove(entries[j].item))
+			entries = append(entries[:j], entries[j+1:]...)
+		default:
+			priority := rng.Intn(10)
+			entries = append(entries, &entry{q.Push(i, priority), priority, seq})
+			seq++
+		}
+	}
+	require.NotEmpty(t, entries)
+	assert.Equal(t, len(entries), q.Len())
+
+	sortEntries()
+	if value, _, ok := q.Peek(); assert.True(t, ok) {
+		assert.Equal(t, entries[0].item.Value(), value)
+	}
+	for _, e := range entries {
+		value, priority, _ := q.Pop()
+		assert.Equal(t, e.item.Value(), value)
+		assert.Equal(t, e.priority, priority)
+	}
+	assert.Equal(t, 0, q.Len())
+
+	// items of other queues are rejected
+	other := New[int, int](intLess)
+	item := other.Push(1, 1)
+	assert.False(t, q.Update(item, 2))
+	assert.False(t, q.Remove(item))
+	assert.False(t, q.Remove(nil))
+}
@@ -0,0 +1,38 @@
+package quadtree_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/quadtree"
+)
+
+func ExampleTree_Query() {
+
+	// create a tree covering a 100x100 area
+	tree := quadtree.New[string](quadtree.Rect{MaxX: 100, MaxY: 100}, 4)
+
+	// insert some objects by their bounding boxes
+	player := tree.Insert(quadtree.Rect{MinX: 10, MinY: 10, MaxX: 12, MaxY: 12}, "player")
+	tree.Insert(quadtree.Rect{MinX: 11, MinY: 5, MaxX: 13, MaxY: 20}, "wall")
+	tree.Insert(quadtree.Rect{MinX: 50, MinY: 50, MaxX: 51, MaxY: 51}, "coin")
+
+	// find the objects colliding with the player
+	tree.Query(player.Box(), func(item *quadtree.Item[string]) bool {
+		if item != player {
+			fmt.Println("player collides with", item.Value())
+		}
+		return true
+	})
+
+	// move the player onto the coin
+	tree.Move(player, quadtree.Rect{MinX: 50, MinY: 50, MaxX: 52, MaxY: 52})
+	tree.Query(player.Box(), func(item *quadtree.Item[string]) bool {
+		if item != player {
+			fmt.Println("player collides with", item.Value())
+		}
+		return true
+	})
+
+	// Output:
+	// player collides with wall
+	// player collides with coin
+}
@@ -0,0 +1,398 @@
+// Package quadtree provides a generic region quadtree, indexing items by their bounding
+// boxes for fast region and collision queries in two dimensions.
+//
+// The tree covers a fixed rectangular area. Each node covers a rectangle and, once it holds
+// more items than the tree's capacity, is split into four quadrants. Every item is stored in
+// the smallest node whose rectangle contains its bounding box, so items straddling a
+// quadrant boundary stay in the parent node.
+//
+// Insertions return an Item handle, which is used to move or delete the item later,
+// much as bst.Tree returns node handles:
+//   - Tree.Insert adds an item with a bounding box.
+//   - Tree.Move changes the bounding box of an item, e.g. as a game object moves.
+//   - Tree.Delete removes an item.
+//   - Tree.Query visits every item whose bounding box intersects a region.
+//
+// As with the other packages of this module, Tree.IsTreeValid checks the structure of the tree,
+// and Tree.String draws it.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/quadtree"
+//
+//	tree := quadtree.New[string](quadtree.Rect{MaxX: 100, MaxY: 100}, 8)
+//	player := tree.Insert(quadtree.Rect{MinX: 10, MinY: 10, MaxX: 12, MaxY: 12}, "player")
+//	tree.Insert(quadtree.Rect{MinX: 11, MinY: 11, MaxX: 15, MaxY: 13}, "wall")
+//
+//	tree.Query(player.Box(), func(item *quadtree.Item[string]) bool {
+//		if item != player {
+//			fmt.Println("player collides with", item.Value())
+//		}
+//		return true
+//	})
+//
+// # Limitations
+//
+// Items must lie within the area covered by the tree, and the tree is not safe for concurrent use.
+package quadtree
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxDepth is the depth below which nodes are never split, bounding the height of the tree
+// when many items share the same location.
+const maxDepth = 24
+
+// Tree represents a quadtree of items with bounding boxes and values of type V.
+//
+// Trees must be created with New.
+type Tree[V any] struct {
+	root     *node[V] // Root node, covering the whole area of the tree.
+	capacity int      // Number of items a node can hold before it is split.
+}
+
+// node represents a single node of the quadtree.
+//
+// A node either is a leaf, or has exactly four children covering the quadrants of its bounds.
+// Items held by an internal node do not fit within any single quadrant.
+type node[V any] struct {
+	bounds   Rect
+	items    []*Item[V]
+	children *[4]*node[V] // nil for leaves
+	parent   *node[V]
+	depth    int
+	count    int // number of items in the subtree rooted at this node
+}
+
+// Item represents an item stored in a quadtree.
+//
+// Items are created by Tree.Insert, and remain valid until they are deleted.
+type Item[V any] struct {
+	box   Rect
+	value V
+	tree  *Tree[V]
+	node  *node[V] // node holding the item, nil once the item is deleted
+}
+
+// Box returns the bounding box of the item.
+func (i *Item[V]) Box() Rect {
+	return i.box
+}
+
+// Value returns the value of the item.
+func (i *Item[V]) Value() V {
+	return i.value
+}
+
+// SetValue sets the value of the item.
+func (i *Item[V]) SetValue(value V) {
+	i.value = value
+}
+
+// New creates and returns a new empty quadtree.
+//
+// Parameters:
+//   - bounds: The area covered by the tree. Every item must lie within it.
+//   - capacity: The number of items a node can hold before it is split into quadrants. Must be at least 1.
+//
+// Returns:
+//   - A pointer to a newly created Tree[V] instance.
+func New[V any](bounds Rect, capacity int) *Tree[V] {
+	if !bounds.IsValid() {
+		panic(fmt.Sprintf("quadtree: invalid bounds %v", bounds))
+	}
+	if capacity < 1 {
+		panic(fmt.Sprintf("quadtree: invalid capacity %d", capacity))
+	}
+	return &Tree[V]{
+		root:     &node[V]{bounds: bounds},
+		capacity: capacity,
+	}
+}
+
+// Bounds returns the area covered by the tree.
+func (t *Tree[V]) Bounds() Rect {
+	return t.root.bounds
+}
+
+// Capacity returns the number of items a node can hold before it is split.
+func (t *Tree[V]) Capacity() int {
+	return t.capacity
+}
+
+// Size returns the number of items in the tree.
+//
+// This is an O(1) operation.
+func (t *Tree[V]) Size() int {
+	return t.root.count
+}
+
+// checkBox panics if box is not a valid rectangle within the bounds of the tree.
+func (t *Tree[V]) checkBox(box Rect) {
+	if !box.IsValid() || !t.root.bounds.Contains(box) {
+		panic(fmt.Sprintf("quadtree: box %v outside bounds %v", box, t.root.bounds))
+	}
+}
+
+// Insert inserts an item with the given bounding box and value into the tree.
+//
+// Panics if box is not a valid rectangle within the bounds of the tree.
+//
+// Returns:
+//   - The newly inserted item.
+func (t *Tree[V]) Insert(box Rect, value V) *Item[V] {
+	t.checkBox(box)
+	item := &Item[V]{box: box, value: value, tree: t}
+	t.insert(t.root, item)
+	return item
+}
+
+// insert adds item to the subtree rooted at n, splitting the leaf it lands in if it is full.
+func (t *Tree[V]) insert(n *node[V], item *Item[V]) {
+	for {
+		n.count++
+		c := n.childContaining(item.box)
+		if c == nil {
+			break
+		}
+		n = c
+	}
+	item.node = n
+	n.items = append(n.items, item)
+	if n.children == nil && len(n.items) > t.capacity && n.depth < maxDepth {
+		t.split(n)
+	}
+}
+
+// childContaining returns the child of n whose bounds contain box, or nil if n is a leaf
+// or box does not fit within a single child.
+func (n *node[V]) childContaining(box Rect) *node[V] {
+	if n.children == nil {
+		return nil
+	}
+	for _, c := range n.children {
+		if c.bounds.Contains(box) {
+			return c
+		}
+	}
+	return nil
+}
+
+// split divides the leaf n into four quadrants, moving down the items that fit within one.
+func (t *Tree[V]) split(n *node[V]) {
+	n.children = new([4]*node[V])
+	for i, bounds := range n.bounds.quadrants() {
+		n.children[i] = &node[V]{bounds: bounds, parent: n, depth: n.depth + 1}
+	}
+	items := n.items
+	n.items = nil
+	for _, item := range items {
+		if c := n.childContaining(item.box); c != nil {
+			t.insert(c, item)
+		} else {
+			n.items = append(n.items, item)
+		}
+	}
+}
+
+// Delete removes item from the tree, merging quadrants that no longer hold enough items to be split.
+//
+// Returns:
+//   - true if the item was found and removed.
+//   - false if the item was already deleted, or belongs to another tree.
+func (t *Tree[V]) Delete(item *Item[V]) bool {
+	if item == nil || item.tree != t || item.node == nil {
+		return false
+	}
+	t.remove(item)
+	return true
+}
+
+// remove detaches item from its node, and merges the highest ancestor left with too few
+// items to be split.
+func (t *Tree[V]) remove(item *Item[V]) {
+	n := item.node
+	for i, it := range n.items {
+		if it == item {
+			n.items = append(n.items[:i], n.items[i+1:]...)
+			break
+		}
+	}
+	item.node = nil
+
+	var merge *node[V]
+	for ; n != nil; n = n.parent {
+		n.count--
+		if n.children != nil && n.count <= t.capacity {
+			merge = n
+		}
+	}
+	if merge != nil {
+		merge.collect(merge)
+		merge.children = nil
+	}
+}
+
+// collect moves the items of the subtree rooted at n into dst.
+func (n *node[V]) collect(dst *node[V]) {
+	if n.children == nil {
+		return
+	}
+	for _, c := range n.children {
+		for _, item := range c.items {
+			item.node = dst
+			dst.items = append(dst.items, item)
+		}
+		c.collect(dst)
+	}
+}
+
+// Move changes the bounding box of item, relocating it within the tree.
+//
+// Panics if box is not a valid rectangle within the bounds of the tree.
+//
+// Returns:
+//   - true if the item was moved.
+//   - false if the item was already deleted, or belongs to another tree.
+func (t *Tree[V]) Move(item *Item[V], box Rect) bool {
+	t.checkBox(box)
+	if item == nil || item.tree != t || item.node == nil {
+		return false
+	}
+	t.remove(item)
+	item.box = box
+	t.insert(t.root, item)
+	return true
+}
+
+// Query calls f for each item whose bounding box intersects region, until f returns false.
+//
+// Items are visited in an unspecified order. The tree must not be modified during iteration.
+func (t *Tree[V]) Query(region Rect, f func(item *Item[V]) bool) {
+	t.root.query(region, f)
+}
+
+// query calls f for each item intersecting region in the subtree rooted at n.
+// It returns false if iteration was stopped by f.
+func (n *node[V]) query(region Rect, f func(item *Item[V]) bool) bool {
+	if !n.bounds.Intersects(region) {
+		return true
+	}
+	for _, item := range n.items {
+		if item.box.Intersects(region) && !f(item) {
+			return false
+		}
+	}
+	if n.children != nil {
+		for _, c := range n.children {
+			if !c.query(region, f) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IsTreeValid checks whether the tree satisfies the quadtree properties:
+//   - The children of every internal node cover the four quadrants of its bounds.
+//   - Every item lies within the bounds of its node, and items held by internal nodes
+//     do not fit within any single child.
+//   - Leaves hold no more items than the capacity, unless they are at the maximum depth.
+//   - Internal nodes hold more items in their subtree than the capacity.
+//   - Parent links, item links and the number of items in each subtree are consistent.
+//
+// Returns:
+//   - nil if the tree is valid.
+//   - An error describing the first violation found otherwise.
+func (t *Tree[V]) IsTreeValid() error {
+	if t.root.parent != nil || t.root.depth != 0 {
+		return fmt.Errorf("root node has a parent or non-zero depth")
+	}
+	return t.validate(t.root)
+}
+
+// validate checks the subtree rooted at n.
+func (t *Tree[V]) validate(n *node[V]) error {
+	count := len(n.items)
+	for _, item := range n.items {
+		switch {
+		case item.node != n || item.tree != t:
+			return fmt.Errorf("item %v is not linked to its node %v", item.box, n.bounds)
+		case !n.bounds.Contains(item.box):
+			return fmt.Errorf("item %v lies outside its node %v", item.box, n.bounds)
+		case n.childContaining(item.box) != nil:
+			return fmt.Errorf("item %v in node %v fits within a child", item.box, n.bounds)
+		}
+	}
+
+	if n.children == nil {
+		if len(n.items) > t.capacity && n.depth < maxDepth {
+			return fmt.Errorf("leaf %v holds %d items, more than the capacity of %d", n.bounds, len(n.items), t.capacity)
+		}
+	} else {
+		for i, bounds := range n.bounds.quadrants() {
+			c := n.children[i]
+			switch {
+			case c.bounds != bounds:
+				return fmt.Errorf("child %d of node %v has bounds %v, expected %v", i, n.bounds, c.bounds, bounds)
+			case c.parent != n || c.depth != n.depth+1:
+				return fmt.Errorf("child %d of node %v has an inconsistent parent or depth", i, n.bounds)
+			}
+			if err := t.validate(c); err != nil {
+				return err
+			}
+			count += c.count
+		}
+		if n.count <= t.capacity {
+			return fmt.Errorf("internal node %v holds %d items, which should have been merged", n.bounds, n.count)
+		}
+	}
+
+	if count != n.count {
+		return fmt.Errorf("node %v holds %d items, but has count %d", n.bounds, count, n.count)
+	}
+	return nil
+}
+
+// String returns a visual representation of the quadtree.
+//
+// Each node is printed on its own line as its bounds, followed by the values of the items it holds.
+// Children are listed below their parent in quadrant order (minimum x and y first), indented and
+// connected to the parent by connectors.
+//
+// Returns:
+//   - A formatted string representing the quadtree structure.
+func (t *Tree[V]) String() string {
+	builder := strings.Builder{}
+	t.root.write(&builder, "", "")
+	return builder.String()
+}
+
+// write draws the subtree rooted at n to builder. connector is drawn before n,
+// and prefix before each line of its children.
+func (n *node[V]) write(builder *strings.Builder, connector, prefix string) {
+	builder.WriteString(connector)
+	builder.WriteString(n.bounds.String())
+	for i, item := range n.items {
+		if i == 0 {
+			builder.WriteString(": ")
+		} else {
+			builder.WriteString(", ")
+		}
+		fmt.Fprint(builder, item.value)
+	}
+	builder.WriteString("\n")
+
+	if n.children == nil {
+		return
+	}
+	for i, c := range n.children {
+		if i < len(n.children)-1 {
+			c.write(builder, prefix+" ├── ", prefix+" │   ")
+		} else {
+			c.write(builder, prefix+" ╰── ", prefix+"     ")
+		}
+	}
+}
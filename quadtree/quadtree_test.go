@@ -0,0 +1,118 @@
+package quadtree
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func randomBox(rng *rand.Rand) Rect {
+	x, y := rng.Float64()*90, rng.Float64()*90
+	return Rect{x, y, x + rng.Float64()*10, y + rng.Float64()*10}
+}
+
+func TestTree_InsertQueryDelete(t *testing.T) {
+	tr := New[int](Rect{MaxX: 100, MaxY: 100}, 4)
+	rng := rand.New(rand.NewSource(1))
+	var items []*Item[int]
+
+	for i := 0; i < 5000; i++ {
+		switch op := rng.Intn(4); {
+		case op == 0 && len(items) > 0:
+			j := rng.Intn(len(items))
+			assert.True(t, tr.Delete(items[j]))
+			assert.False(t, tr.Delete(items[j]), "expected deleted item to be stale")
+			items = append(items[:j], items[j+1:]...)
+		case op == 1 && len(items) > 0:
+			item := items[rng.Intn(len(items))]
+			box := randomBox(rng)
+			assert.True(t, tr.Move(item, box))
+			assert.Equal(t, box, item.Box())
+		default:
+			items = append(items, tr.Insert(randomBox(rng), i))
+		}
+		if i%250 == 0 {
+			require.NoError(t, tr.IsTreeValid())
+		}
+	}
+	require.NoError(t, tr.IsTreeValid())
+	assert.Equal(t, len(items), tr.Size())
+
+	// compare queries against a linear scan
+	for i := 0; i < 200; i++ {
+		region := randomBox(rng)
+		expected := make(map[*Item[int]]bool)
+		for _, item := range items {
+			if item.Box().Intersects(region) {
+				expected[item] = true
+			}
+		}
+		got := make(map[*Item[int]]bool)
+		tr.Query(region, func(item *Item[int]) bool {
+			assert.False(t, got[item], "item visited twice")
+			got[item] = true
+			return true
+		})
+		assert.Equal(t, expected, got, "unexpected items intersecting %v", region)
+	}
+
+	count := 0
+	tr.Query(tr.Bounds(), func(*Item[int]) bool {
+		count++
+		return false
+	})
+	assert.Equal(t, 1, count, "expected query to stop early")
+
+	for _, item := range items {
+		assert.True(t, tr.Delete(item))
+	}
+	require.NoError(t, tr.IsTreeValid())
+	assert.Equal(t, 0, tr.Size())
+	assert.Nil(t, tr.root.children, "expected empty tree to be merged")
+}
+
+func TestTree_samePoint(t *testing.T) {
+	tr := New[int](Rect{MaxX: 1, MaxY: 1}, 1)
+	for i := 0; i < 100; i++ {
+		tr.Insert(Rect{0.3, 0.3, 0.3, 0.3}, i)
+	}
+	require.NoError(t, tr.IsTreeValid())
+	count := 0
+	tr.Query(Rect{0.3, 0.3, 0.3, 0.3}, func(*Item[int]) bool {
+		count++
+		return true
+	})
+	assert.Equal(t, 100, count)
+}
+
+func TestTree_invalid(t *testing.T) {
+	assert.Panics(t, func() { New[int](Rect{MinX: 1}, 4) })
+	assert.Panics(t, func() { New[int](Rect{MaxX: 1, MaxY: 1}, 0) })
+
+	tr := New[int](Rect{MaxX: 10, MaxY: 10}, 4)
+	assert.Equal(t, 4, tr.Capacity())
+	assert.Panics(t, func() { tr.Insert(Rect{5, 5, 11, 6}, 1) })
+	assert.Panics(t, func() { tr.Insert(Rect{5, 5, 4, 6}, 1) })
+
+	other := New[int](Rect{MaxX: 10, MaxY: 10}, 4)
+	item := other.Insert(Rect{1, 1, 2, 2}, 1)
+	assert.False(t, tr.Delete(item), "expected item of another tree to be rejected")
+	assert.False(t, tr.Move(item, Rect{2, 2, 3, 3}))
+	assert.False(t, tr.Delete(nil))
+}
+
+func TestTree_String(t *testing.T) {
+	tr := New[string](Rect{MaxX: 4, MaxY: 4}, 1)
+	assert.Equal(t, "[(0, 0), (4, 4)]\n", tr.String())
+
+	tr.Insert(Rect{1, 1, 3, 3}, "a")
+	tr.Insert(Rect{0, 0, 1, 1}, "b")
+	tr.Insert(Rect{3, 3, 4, 4}, "c")
+	expected := "[(0, 0), (4, 4)]: a\n" +
+		" ├── [(0, 0), (2, 2)]: b\n" +
+		" ├── [(2, 0), (4, 2)]\n" +
+		" ├── [(0, 2), (2, 4)]\n" +
+		" ╰── [(2, 2), (4, 4)]: c\n"
+	assert.Equal(t, expected, tr.String())
+}
@@ -0,0 +1,44 @@
+package quadtree
+
+import "fmt"
+
+// Rect is an axis-aligned rectangle, including its edges: a point (x, y) is within the
+// rectangle if MinX <= x <= MaxX and MinY <= y <= MaxY.
+//
+// A rectangle with MinX == MaxX and MinY == MaxY represents a single point.
+type Rect struct {
+	MinX, MinY float64 // Minimum corner of the rectangle.
+	MaxX, MaxY float64 // Maximum corner of the rectangle.
+}
+
+// Contains returns true if o lies entirely within r.
+func (r Rect) Contains(o Rect) bool {
+	return r.MinX <= o.MinX && o.MaxX <= r.MaxX && r.MinY <= o.MinY && o.MaxY <= r.MaxY
+}
+
+// Intersects returns true if r and o overlap. Rectangles that only touch along an edge
+// or at a corner are considered to overlap.
+func (r Rect) Intersects(o Rect) bool {
+	return r.MinX <= o.MaxX && o.MinX <= r.MaxX && r.MinY <= o.MaxY && o.MinY <= r.MaxY
+}
+
+// IsValid returns true if the minimum corner of r is not greater than its maximum corner.
+func (r Rect) IsValid() bool {
+	return r.MinX <= r.MaxX && r.MinY <= r.MaxY
+}
+
+// String returns the rectangle as "[(MinX, MinY), (MaxX, MaxY)]".
+func (r Rect) String() string {
+	return fmt.Sprintf("[(%g, %g), (%g, %g)]", r.MinX, r.MinY, r.MaxX, r.MaxY)
+}
+
+// quadrants returns the four quadrants of r, split at its center.
+func (r Rect) quadrants() [4]Rect {
+	midX, midY := r.MinX+(r.MaxX-r.MinX)/2, r.MinY+(r.MaxY-r.MinY)/2
+	return [4]Rect{
+		{r.MinX, r.MinY, midX, midY},
+		{midX, r.MinY, r.MaxX, midY},
+		{r.MinX, midY, midX, r.MaxY},
+		{midX, midY, r.MaxX, r.MaxY},
+	}
+}
@@ -0,0 +1,29 @@
+package quantile_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/quantile"
+)
+
+func ExampleEstimator_Quantile() {
+
+	// track request latencies (in ms) over the last 5 requests
+	latencies := quantile.NewWindow[int](func(a, b int) bool {
+		return a < b
+	}, 5)
+	for _, ms := range []int{120, 12, 15, 11, 14, 13} {
+		latencies.Insert(ms)
+	}
+
+	// the 120 ms request has left the window
+	for _, q := range []float64{0.5, 0.99} {
+		ms, _ := latencies.Quantile(q)
+		fmt.Printf("p%v: %d ms\n", q*100, ms)
+	}
+	fmt.Println(latencies.Rank(14), "requests faster than 14 ms")
+
+	// Output:
+	// p50: 13 ms
+	// p99: 15 ms
+	// 3 requests faster than 14 ms
+}
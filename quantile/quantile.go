@@ -0,0 +1,134 @@
+// Package quantile provides exact running quantiles (percentiles) over a stream of samples,
+// optionally restricted to a sliding window of the most recent samples.
+//
+// Estimator keeps its samples in an ostree.Tree (an order-statistic Red-Black Tree) in multiset
+// mode, so that equal samples are kept, and uses the subtree sizes of the tree to select samples
+// by rank:
+//   - Estimator.Insert adds a sample in O(log n) time, evicting the oldest sample if the
+//     window is full.
+//   - Estimator.Quantile returns the sample at a given quantile in O(log n) time.
+//   - Estimator.Rank counts the samples less than a value in O(log n) time.
+//
+// Quantiles use the nearest-rank definition: the q-quantile of n samples is the smallest sample
+// such that at least q·n samples are less than or equal to it. It is always one of the samples,
+// so no interpolation (or arithmetic on samples) is needed.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/quantile"
+//
+//	latencies := quantile.NewWindow[int](func(a, b int) bool { return a < b }, 1000)
+//	for _, ms := range []int{12, 15, 11, 80, 14} {
+//		latencies.Insert(ms)
+//	}
+//	p50, _ := latencies.Quantile(0.5) // 14
+//	p99, _ := latencies.Quantile(0.99) // 80
+//
+// # Limitations
+//
+// The estimator is not safe for concurrent use.
+package quantile
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/bst"
+	"github.com/mikenye/gotrees/rbtree"
+	"github.com/mikenye/gotrees/rbtree/ostree"
+	"math"
+)
+
+// Estimator computes exact quantiles of samples of type T.
+//
+// Estimators must be created with New or NewWindow.
+type Estimator[T any] struct {
+	tree   *ostree.Tree[T, struct{}]              // Samples, in multiset mode
+	less   bst.LessFunc[T]                        // Ordering of samples
+	window []*bst.Node[T, struct{}, rbtree.Color] // Nodes of the samples in arrival order, for windowed estimators
+	oldest int                                    // Position of the oldest sample in window, once it is full
+	size   int                                    // Maximum number of samples, 0 if unbounded
+}
+
+// New creates and returns a new estimator over every inserted sample.
+//
+// Parameters:
+//   - less: A function that defines the ordering of samples.
+//
+// Returns:
+//   - A pointer to a newly created Estimator[T] instance.
+func New[T any](less bst.LessFunc[T]) *Estimator[T] {
+	return &Estimator[T]{
+		tree: ostree.New[T, struct{}](less, bst.WithDuplicateKeys()),
+		less: less,
+	}
+}
+
+// NewWindow creates and returns a new estimator over the most recent samples.
+// Once the window is full, each insertion evicts the oldest sample.
+//
+// Parameters:
+//   - less: A function that defines the ordering of samples.
+//   - size: The number of samples in the window. Must be at least 1.
+//
+// Returns:
+//   - A pointer to a newly created Estimator[T] instance.
+func NewWindow[T any](less bst.LessFunc[T], size int) *Estimator[T] {
+	if size < 1 {
+		panic(fmt.Sprintf("quantile: invalid window size %d", size))
+	}
+	e := New[T](less)
+	e.window = make([]*bst.Node[T, struct{}, rbtree.Color], 0, size)
+	e.size = size
+	return e
+}
+
+// Len returns the number of samples held by the estimator.
+//
+// This is an O(1) operation.
+func (e *Estimator[T]) Len() int {
+	return e.tree.Size()
+}
+
+// Insert adds a sample, evicting the oldest sample first if the estimator's window is full.
+func (e *Estimator[T]) Insert(sample T) {
+	n, _ := e.tree.Insert(sample, struct{}{})
+	switch {
+	case e.size == 0:
+	case len(e.window) < e.size:
+		e.window = append(e.window, n)
+	default:
+		e.tree.Delete(e.window[e.oldest])
+		e.window[e.oldest] = n
+		e.oldest = (e.oldest + 1) % e.size
+	}
+}
+
+// Quantile returns the q-quantile of the samples, by the nearest-rank definition.
+//
+// Quantile(0) returns the smallest sample, Quantile(0.5) the median and Quantile(1) the largest sample.
+// Panics if q is not within [0, 1].
+//
+// Returns:
+//   - (sample, true) if the estimator holds samples.
+//   - (zero value, false) if it is empty.
+func (e *Estimator[T]) Quantile(q float64) (T, bool) {
+	if !(q >= 0 && q <= 1) {
+		panic(fmt.Sprintf("quantile: invalid quantile %v", q))
+	}
+	i := max(int(math.Ceil(q*float64(e.Len())))-1, 0)
+	sample, _, found := e.tree.At(i)
+	return sample, found
+}
+
+// Rank returns the number of samples less than value, in O(log n) time.
+//
+// Dividing the rank by Estimator.Len gives the fraction of samples below value.
+func (e *Estimator[T]) Rank(value T) int {
+	return e.tree.Rank(value)
+}
+
+// Reset removes every sample from the estimator.
+func (e *Estimator[T]) Reset() {
+	e.tree = ostree.New[T, struct{}](e.less, bst.WithDuplicateKeys())
+	e.window = e.window[:0]
+	e.oldest = 0
+}
@@ -0,0 +1,99 @@
+package quantile
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+// checkQuantiles compares the quantiles and ranks of e with those of samples.
+func checkQuantiles(t *testing.T, e *Estimator[int], samples []int, rng *rand.Rand) {
+	t.Helper()
+	require.NoError(t, e.tree.IsTreeValid())
+	require.Equal(t, len(samples), e.Len())
+	sorted := slices.Sorted(slices.Values(samples))
+
+	for _, q := range []float64{0, 0.01, 0.25, 0.5, 0.9, 0.99, 1, rng.Float64()} {
+		sample, found := e.Quantile(q)
+		if len(sorted) == 0 {
+			require.False(t, found)
+			continue
+		}
+		require.True(t, found)
+
+		// the smallest sample with at least q·n samples less than or equal to it
+		expected := sorted[len(sorted)-1]
+		for i, s := range sorted {
+			if float64(i+1) >= q*float64(len(sorted)) {
+				expected = s
+				break
+			}
+		}
+		require.Equal(t, expected, sample, "quantile %v", q)
+	}
+
+	value := rng.Intn(120) - 10
+	require.Equal(t, sort.SearchInts(sorted, value), e.Rank(value))
+}
+
+func TestEstimator(t *testing.T) {
+	e := New[int](intLess)
+	rng := rand.New(rand.NewSource(1))
+	var samples []int
+	for i := 0; i < 2000; i++ {
+		if i%50 == 0 {
+			checkQuantiles(t, e, samples, rng)
+		}
+		sample := rng.Intn(100)
+		e.Insert(sample)
+		samples = append(samples, sample)
+	}
+	checkQuantiles(t, e, samples, rng)
+
+	e.Reset()
+	checkQuantiles(t, e, nil, rng)
+}
+
+func TestEstimator_Window(t *testing.T) {
+	for _, size := range []int{1, 7, 100} {
+		e := NewWindow[int](intLess, size)
+		rng := rand.New(rand.NewSource(1))
+		var samples []int
+		for i := 0; i < 1000; i++ {
+			sample := rng.Intn(100)
+			e.Insert(sample)
+			samples = append(samples, sample)
+			if len(samples) > size {
+				samples = samples[1:]
+			}
+			if i%10 == 0 {
+				checkQuantiles(t, e, samples, rng)
+			}
+		}
+
+		// the window keeps working after a reset
+		e.Reset()
+		samples = samples[:0]
+		for i := 0; i < 2*size; i++ {
+			e.Insert(i)
+			if i >= size {
+				samples = append(samples, i)
+			}
+		}
+		checkQuantiles(t, e, samples, rng)
+	}
+}
+
+func TestEstimator_InvalidArguments(t *testing.T) {
+	assert.Panics(t, func() { NewWindow[int](intLess, 0) })
+	e := New[int](intLess)
+	for _, q := range []float64{-0.1, 1.1, math.NaN()} {
+		assert.Panics(t, func() { e.Quantile(q) })
+	}
+}
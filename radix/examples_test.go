@@ -0,0 +1,45 @@
+package radix_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/radix"
+)
+
+func ExampleTree_LongestPrefix() {
+
+	// create a routing table
+	routes := radix.New[string]()
+	routes.Insert("/", "index")
+	routes.Insert("/api", "api")
+	routes.Insert("/api/users", "users")
+
+	// find the most specific route for each path
+	for _, path := range []string{"/api/users/42", "/api/orders", "/about"} {
+		route, handler, _ := routes.LongestPrefix(path)
+		fmt.Printf("%s -> %s (%s)\n", path, route, handler)
+	}
+
+	// Output:
+	// /api/users/42 -> /api/users (users)
+	// /api/orders -> /api (api)
+	// /about -> / (index)
+}
+
+func ExampleTree_WalkPrefix() {
+
+	// create a dictionary of words
+	words := radix.New[struct{}]()
+	for _, word := range []string{"tree", "trie", "trip", "treat", "try"} {
+		words.Insert(word, struct{}{})
+	}
+
+	// find the words starting with "tri"
+	words.WalkPrefix("tri", func(word string, _ struct{}) bool {
+		fmt.Println(word)
+		return true
+	})
+
+	// Output:
+	// trie
+	// trip
+}
@@ -0,0 +1,287 @@
+// Package radix provides a generic radix tree (PATRICIA trie) keyed by strings.
+//
+// A radix tree stores keys by their bytes rather than by comparison: each edge is labelled
+// with a run of bytes, and chains of nodes with a single child are merged into one edge.
+// Lookups take O(k) time for a key of length k, independent of the number of keys, and
+// keys sharing a prefix share the nodes for that prefix.
+//
+// Besides exact lookups, this makes prefix queries cheap:
+//   - Tree.LongestPrefix finds the longest key that is a prefix of a given string,
+//     as used by routing tables and URL routers.
+//   - Tree.WalkPrefix visits every key starting with a given prefix, in lexicographic order,
+//     as used for autocompletion.
+//
+// Byte slice keys can be used by converting them to strings, e.g. string(b).
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/radix"
+//
+//	tree := radix.New[string]()
+//	tree.Insert("/api", "api")
+//	tree.Insert("/api/users", "users")
+//	prefix, value, found := tree.LongestPrefix("/api/users/42") // "/api/users", "users", true
+//
+// # Limitations
+//
+// The tree is not safe for concurrent use.
+package radix
+
+import (
+	"sort"
+	"strings"
+)
+
+// Tree represents a radix tree mapping string keys to values of type V.
+//
+// The zero value is not usable; trees must be created with New.
+type Tree[V any] struct {
+	root *node[V] // Root node, holding the empty prefix.
+	size int      // Number of keys in the tree.
+}
+
+// node represents a single node of the radix tree.
+//
+// The key of a node is the concatenation of the prefixes on the path from the root.
+// Only nodes with leaf set hold a value. Edges are sorted by the first byte of their prefix,
+// which is unique among siblings.
+type node[V any] struct {
+	prefix string
+	leaf   bool
+	value  V
+	edges  []*node[V]
+}
+
+// edge returns the index of the edge of n whose prefix starts with b, and the edge itself,
+// or the index at which such an edge would be inserted and nil.
+func (n *node[V]) edge(b byte) (int, *node[V]) {
+	i := sort.Search(len(n.edges), func(i int) bool {
+		return n.edges[i].prefix[0] >= b
+	})
+	if i < len(n.edges) && n.edges[i].prefix[0] == b {
+		return i, n.edges[i]
+	}
+	return i, nil
+}
+
+// addEdge inserts child as an edge of n at index i.
+func (n *node[V]) addEdge(i int, child *node[V]) {
+	n.edges = append(n.edges, nil)
+	copy(n.edges[i+1:], n.edges[i:])
+	n.edges[i] = child
+}
+
+// mergeChild merges the only child of n into n, when n holds no value.
+func (n *node[V]) mergeChild() {
+	child := n.edges[0]
+	n.prefix += child.prefix
+	n.leaf = child.leaf
+	n.value = child.value
+	n.edges = child.edges
+}
+
+// New creates and returns a new empty radix tree.
+func New[V any]() *Tree[V] {
+	return &Tree[V]{root: &node[V]{}}
+}
+
+// Size returns the number of keys in the tree.
+//
+// This is an O(1) operation.
+func (t *Tree[V]) Size() int {
+	return t.size
+}
+
+// commonPrefixLength returns the length of the longest common prefix of a and b.
+func commonPrefixLength(a, b string) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
+
+// Insert inserts the given key and value into the tree.
+//
+// If the key already exists, its value is updated.
+//
+// Returns:
+//   - true if a new key was inserted.
+//   - false if the key existed and its value was updated.
+func (t *Tree[V]) Insert(key string, value V) bool {
+	n, search := t.root, key
+	for {
+		if len(search) == 0 {
+			inserted := !n.leaf
+			n.leaf, n.value = true, value
+			if inserted {
+				t.size++
+			}
+			return inserted
+		}
+
+		i, child := n.edge(search[0])
+		if child == nil {
+			n.addEdge(i, &node[V]{prefix: search, leaf: true, value: value})
+			t.size++
+			return true
+		}
+
+		common := commonPrefixLength(search, child.prefix)
+		if common == len(child.prefix) {
+			n, search = child, search[common:]
+			continue
+		}
+
+		// the key diverges part way along the edge: split the edge at the divergence
+		split := &node[V]{prefix: search[:common], edges: []*node[V]{child}}
+		child.prefix = child.prefix[common:]
+		n.edges[i] = split
+		n, search = split, search[common:]
+	}
+}
+
+// find returns the node holding key, or nil if there is none, along with its parent.
+func (t *Tree[V]) find(key string) (n, parent *node[V]) {
+	n, search := t.root, key
+	for len(search) > 0 {
+		_, child := n.edge(search[0])
+		if child == nil || !strings.HasPrefix(search, child.prefix) {
+			return nil, nil
+		}
+		n, parent, search = child, n, search[len(child.prefix):]
+	}
+	if !n.leaf {
+		return nil, nil
+	}
+	return n, parent
+}
+
+// Search returns the value associated with key.
+//
+// Returns:
+//   - (value, true) if the key exists in the tree.
+//   - (zero value, false) if the key is not found.
+func (t *Tree[V]) Search(key string) (V, bool) {
+	if n, _ := t.find(key); n != nil {
+		return n.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete removes key from the tree.
+//
+// Nodes left without a value and with at most one child are removed or merged,
+// so the tree remains fully compressed.
+//
+// Returns:
+//   - true if the key was found and removed.
+//   - false if the key was not found.
+func (t *Tree[V]) Delete(key string) bool {
+	n, parent := t.find(key)
+	if n == nil {
+		return false
+	}
+
+	var zero V
+	n.leaf, n.value = false, zero
+	t.size--
+
+	if n == t.root {
+		return true
+	}
+	switch len(n.edges) {
+	case 0:
+		// remove the node, then merge the parent with its remaining child if it is now redundant
+		i, _ := parent.edge(n.prefix[0])
+		parent.edges = append(parent.edges[:i], parent.edges[i+1:]...)
+		if parent != t.root && !parent.leaf && len(parent.edges) == 1 {
+			parent.mergeChild()
+		}
+	case 1:
+		n.mergeChild()
+	}
+	return true
+}
+
+// LongestPrefix returns the longest key in the tree that is a prefix of s, and its value.
+//
+// Returns:
+//   - (key, value, true) if such a key exists.
+//   - ("", zero value, false) otherwise.
+func (t *Tree[V]) LongestPrefix(s string) (string, V, bool) {
+	var match *node[V]
+	matchLength := 0
+
+	n, consumed := t.root, 0
+	for {
+		if n.leaf {
+			match, matchLength = n, consumed
+		}
+		if consumed == len(s) {
+			break
+		}
+		_, child := n.edge(s[consumed])
+		if child == nil || !strings.HasPrefix(s[consumed:], child.prefix) {
+			break
+		}
+		n, consumed = child, consumed+len(child.prefix)
+	}
+
+	if match == nil {
+		var zero V
+		return "", zero, false
+	}
+	return s[:matchLength], match.value, true
+}
+
+// Walk calls f for each key and value in the tree in lexicographic (byte-wise) order,
+// until f returns false.
+//
+// The tree must not be modified during iteration.
+func (t *Tree[V]) Walk(f func(key string, value V) bool) {
+	walk(t.root, "", f)
+}
+
+// WalkPrefix calls f for each key starting with prefix and its value, in lexicographic (byte-wise) order,
+// until f returns false.
+//
+// The tree must not be modified during iteration.
+func (t *Tree[V]) WalkPrefix(prefix string, f func(key string, value V) bool) {
+	n, search := t.root, prefix
+	for len(search) > 0 {
+		_, child := n.edge(search[0])
+		switch {
+		case child == nil:
+			return
+		case strings.HasPrefix(search, child.prefix):
+			// the edge is part of the prefix
+			search = search[len(child.prefix):]
+			n = child
+		case strings.HasPrefix(child.prefix, search):
+			// the prefix ends part way along the edge, so every key below it matches
+			walk(child, prefix[:len(prefix)-len(search)]+child.prefix, f)
+			return
+		default:
+			return
+		}
+	}
+	walk(n, prefix, f)
+}
+
+// walk calls f for each key in the subtree rooted at n, whose key is key, in lexicographic order.
+// It returns false if iteration was stopped by f.
+func walk[V any](n *node[V], key string, f func(key string, value V) bool) bool {
+	if n.leaf && !f(key, n.value) {
+		return false
+	}
+	for _, child := range n.edges {
+		if !walk(child, key+child.prefix, f) {
+			return false
+		}
+	}
+	return true
+}
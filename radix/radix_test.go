@@ -0,0 +1,185 @@
+package radix
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// checkTree verifies that the tree is fully compressed: every node other than the root
+// has a non-empty prefix, edges are sorted by unique first bytes, and nodes without a value
+// have at least two children.
+func checkTree[V any](t *testing.T, tree *Tree[V]) {
+	t.Helper()
+	count := 0
+	var check func(n *node[V])
+	check = func(n *node[V]) {
+		if n.leaf {
+			count++
+		}
+		if n != tree.root {
+			require.NotEmpty(t, n.prefix, "expected non-root node to have a prefix")
+			require.True(t, n.leaf || len(n.edges) >= 2, "expected node %q without value to have at least two children", n.prefix)
+		}
+		for i, child := range n.edges {
+			if i > 0 {
+				require.Less(t, n.edges[i-1].prefix[0], child.prefix[0], "expected edges to be sorted")
+			}
+			check(child)
+		}
+	}
+	check(tree.root)
+	require.Equal(t, tree.size, count, "inconsistent size")
+}
+
+func randomKey(rng *rand.Rand) string {
+	b := make([]byte, rng.Intn(6))
+	for i := range b {
+		b[i] = "abc"[rng.Intn(3)]
+	}
+	return string(b)
+}
+
+func TestTree_InsertSearchDelete(t *testing.T) {
+	tree := New[int]()
+	rng := rand.New(rand.NewSource(1))
+	expected := make(map[string]int)
+
+	for i := 0; i < 20000; i++ {
+		key := randomKey(rng)
+		_, exists := expected[key]
+		if rng.Intn(3) == 0 {
+			assert.Equal(t, exists, tree.Delete(key), "unexpected result deleting %q", key)
+			delete(expected, key)
+		} else {
+			assert.Equal(t, !exists, tree.Insert(key, i), "unexpected result inserting %q", key)
+			expected[key] = i
+		}
+		if i%500 == 0 {
+			checkTree(t, tree)
+		}
+	}
+	checkTree(t, tree)
+	assert.Equal(t, len(expected), tree.Size())
+
+	for key, value := range expected {
+		v, found := tree.Search(key)
+		assert.True(t, found, "expected to find %q", key)
+		assert.Equal(t, value, v)
+	}
+	_, found := tree.Search("abcabc")
+	assert.False(t, found)
+
+	// walk visits keys in sorted order
+	var keys, walked []string
+	for key := range expected {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	tree.Walk(func(key string, value int) bool {
+		assert.Equal(t, expected[key], value)
+		walked = append(walked, key)
+		return true
+	})
+	assert.Equal(t, keys, walked)
+
+	for key := range expected {
+		require.True(t, tree.Delete(key))
+		checkTree(t, tree)
+	}
+	assert.Equal(t, 0, tree.Size())
+	assert.Empty(t, tree.root.edges)
+}
+
+func TestTree_emptyKey(t *testing.T) {
+	tree := New[string]()
+	assert.True(t, tree.Insert("", "root"))
+	assert.False(t, tree.Insert("", "updated"))
+	v, found := tree.Search("")
+	assert.True(t, found)
+	assert.Equal(t, "updated", v)
+
+	prefix, v, found := tree.LongestPrefix("anything")
+	assert.True(t, found, "expected empty key to prefix everything")
+	assert.Equal(t, "", prefix)
+	assert.Equal(t, "updated", v)
+
+	assert.True(t, tree.Delete(""))
+	assert.False(t, tree.Delete(""))
+	_, _, found = tree.LongestPrefix("anything")
+	assert.False(t, found)
+}
+
+func TestTree_LongestPrefix(t *testing.T) {
+	tree := New[string]()
+	for _, route := range []string{"/", "/api", "/api/users", "/api/users/admin", "/static"} {
+		tree.Insert(route, "handler:"+route)
+	}
+
+	tests := map[string]struct {
+		path     string
+		expected string
+		found    bool
+	}{
+		"exact":          {path: "/api/users", expected: "/api/users", found: true},
+		"longer":         {path: "/api/users/42", expected: "/api/users", found: true},
+		"deepest":        {path: "/api/users/admin/x", expected: "/api/users/admin", found: true},
+		"mid-edge":       {path: "/api/us", expected: "/api", found: true},
+		"diverging edge": {path: "/statix", expected: "/", found: true},
+		"root only":      {path: "/other", expected: "/", found: true},
+		"no match":       {path: "api", found: false},
+		"empty":          {path: "", found: false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			prefix, v, found := tree.LongestPrefix(tc.path)
+			assert.Equal(t, tc.found, found)
+			if found {
+				assert.Equal(t, tc.expected, prefix)
+				assert.Equal(t, "handler:"+tc.expected, v)
+			}
+		})
+	}
+}
+
+func TestTree_WalkPrefix(t *testing.T) {
+	tree := New[struct{}]()
+	for _, word := range []string{"romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus", "apple"} {
+		tree.Insert(word, struct{}{})
+	}
+
+	tests := map[string]struct {
+		prefix   string
+		expected []string
+	}{
+		"empty prefix":     {prefix: "", expected: []string{"apple", "romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus"}},
+		"at node boundary": {prefix: "rom", expected: []string{"romane", "romanus", "romulus"}},
+		"mid-edge":         {prefix: "rubi", expected: []string{"rubicon", "rubicundus"}},
+		"mid-edge single":  {prefix: "ap", expected: []string{"apple"}},
+		"exact key":        {prefix: "ruber", expected: []string{"ruber"}},
+		"diverging":        {prefix: "rubx", expected: nil},
+		"longer than keys": {prefix: "rubiconx", expected: nil},
+		"no match":         {prefix: "z", expected: nil},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			tree.WalkPrefix(tc.prefix, func(key string, _ struct{}) bool {
+				assert.True(t, strings.HasPrefix(key, tc.prefix))
+				got = append(got, key)
+				return true
+			})
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+
+	var got []string
+	tree.WalkPrefix("r", func(key string, _ struct{}) bool {
+		got = append(got, key)
+		return len(got) < 2
+	})
+	assert.Equal(t, []string{"romane", "romanus"}, got, "expected walk to stop early")
+}
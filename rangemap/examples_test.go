@@ -0,0 +1,32 @@
+package rangemap_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/rangemap"
+)
+
+func ExampleMap_Insert() {
+
+	// map blocks of ports to services
+	ports := rangemap.New[int, string](func(a, b int) bool {
+		return a < b
+	})
+	ports.Insert(0, 1024, "system")
+	ports.Insert(1024, 49152, "registered")
+	ports.Insert(8000, 8100, "web") // splits the registered block
+
+	ports.Ascend(func(lo, hi int, service string) bool {
+		fmt.Printf("[%d, %d) %s\n", lo, hi, service)
+		return true
+	})
+
+	service, _ := ports.Lookup(8080)
+	fmt.Println("8080:", service)
+
+	// Output:
+	// [0, 1024) system
+	// [1024, 8000) registered
+	// [8000, 8100) web
+	// [8100, 49152) registered
+	// 8080: web
+}
@@ -0,0 +1,219 @@
+// Package rangemap provides a generic map from disjoint key ranges to values, such as
+// IP address blocks or time windows to configuration.
+//
+// Ranges are half-open intervals [lo, hi). Map is backed by an rbtree.Tree keyed by the lower
+// bound of each range, so a point is looked up with a single floor search instead of manual
+// overlap checks:
+//   - Map.Insert maps a range to a value, trimming or splitting the ranges it overlaps,
+//     and merging it with adjacent ranges mapped to the same value.
+//   - Map.Delete unmaps a range, trimming or splitting the ranges it overlaps.
+//   - Map.Lookup returns the value of the range containing a point.
+//   - Map.Ascend iterates over the ranges in order.
+//
+// Lookups take O(log n) time, and insertions and deletions O((k+1) log n) time,
+// where k is the number of ranges they overlap.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/rangemap"
+//
+//	m := rangemap.New[int, string](func(a, b int) bool { return a < b })
+//	m.Insert(0, 100, "low")
+//	m.Insert(50, 60, "mid") // splits [0, 100) into [0, 50) and [60, 100)
+//	value, found := m.Lookup(55) // "mid", true
+//
+// # Limitations
+//
+// The map is not safe for concurrent use.
+package rangemap
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/bst"
+	"github.com/mikenye/gotrees/rbtree"
+)
+
+// span holds the upper bound and value of a range, keyed by its lower bound in the tree.
+type span[K any, V comparable] struct {
+	hi    K
+	value V
+}
+
+// Map represents a map from disjoint ranges of keys of type K to values of type V.
+//
+// Maps must be created with New.
+type Map[K any, V comparable] struct {
+	tree *rbtree.Tree[K, *span[K, V]] // Underlying Red-Black Tree, keyed by lower bound
+	less bst.LessFunc[K]              // Function to compare keys and maintain order
+}
+
+// New creates and returns a new empty range map.
+//
+// Parameters:
+//   - less: A function that defines the ordering of keys.
+//
+// Returns:
+//   - A pointer to a newly created Map[K, V] instance.
+func New[K any, V comparable](less bst.LessFunc[K]) *Map[K, V] {
+	return &Map[K, V]{
+		tree: rbtree.New[K, *span[K, V]](less),
+		less: less,
+	}
+}
+
+// Len returns the number of disjoint ranges in the map.
+//
+// This is an O(1) operation.
+func (m *Map[K, V]) Len() int {
+	return m.tree.Size()
+}
+
+// equal returns true if keys a and b are equal under the map's ordering.
+func (m *Map[K, V]) equal(a, b K) bool {
+	return !m.less(a, b) && !m.less(b, a)
+}
+
+// checkRange panics if [lo, hi) is empty.
+func (m *Map[K, V]) checkRange(lo, hi K) {
+	if !m.less(lo, hi) {
+		panic(fmt.Sprintf("rangemap: empty range [%v, %v)", lo, hi))
+	}
+}
+
+// Insert maps every key in the range [lo, hi) to value.
+//
+// Ranges overlapping [lo, hi) are trimmed, or split in two if they contain it, so that only the new
+// mapping remains over [lo, hi). The new range is then merged with the ranges directly before and
+// after it if they are mapped to the same value. Panics if lo is not less than hi.
+func (m *Map[K, V]) Insert(lo, hi K, value V) {
+	m.checkRange(lo, hi)
+	m.clear(lo, hi)
+
+	// merge with adjacent ranges mapped to the same value
+	if n, found := m.tree.Floor(lo); found {
+		if s := m.tree.Value(n); s.value == value && m.equal(s.hi, lo) {
+			lo = m.tree.Key(n)
+			m.tree.Delete(n)
+		}
+	}
+	if n, found := m.tree.Search(hi); found {
+		if s := m.tree.Value(n); s.value == value {
+			hi = s.hi
+			m.tree.Delete(n)
+		}
+	}
+	m.tree.Insert(lo, &span[K, V]{hi: hi, value: value})
+}
+
+// Delete unmaps every key in the range [lo, hi), trimming or splitting the ranges it overlaps.
+// Panics if lo is not less than hi.
+//
+// Returns:
+//   - true if any key was unmapped.
+//   - false if no range overlapped [lo, hi).
+func (m *Map[K, V]) Delete(lo, hi K) bool {
+	m.checkRange(lo, hi)
+	return m.clear(lo, hi)
+}
+
+// clear unmaps every key in [lo, hi), and returns true if any key was unmapped.
+func (m *Map[K, V]) clear(lo, hi K) bool {
+	cleared := false
+
+	// a range starting before lo keeps its part before lo, and its part after hi if it contains [lo, hi)
+	if n, found := m.tree.Floor(lo); found && m.less(m.tree.Key(n), lo) {
+		s := m.tree.Value(n)
+		if m.less(lo, s.hi) {
+			if m.less(hi, s.hi) {
+				m.tree.Insert(hi, &span[K, V]{hi: s.hi, value: s.value})
+			}
+			s.hi = lo
+			cleared = true
+		}
+	}
+
+	// ranges starting within [lo, hi) are removed, keeping the part after hi of the last one
+	for {
+		n, found := m.tree.Ceiling(lo)
+		if !found || !m.less(m.tree.Key(n), hi) {
+			return cleared
+		}
+		s := m.tree.Value(n)
+		m.tree.Delete(n)
+		cleared = true
+		if m.less(hi, s.hi) {
+			m.tree.Insert(hi, s)
+			return cleared
+		}
+	}
+}
+
+// Lookup returns the value of the range containing point, in O(log n) time.
+//
+// Returns:
+//   - (value, true) if a range contains point.
+//   - (zero value, false) otherwise.
+func (m *Map[K, V]) Lookup(point K) (V, bool) {
+	if n, found := m.tree.Floor(point); found {
+		if s := m.tree.Value(n); m.less(point, s.hi) {
+			return s.value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Range returns the range containing point and its value, in O(log n) time.
+//
+// Returns:
+//   - (lo, hi, value, true) if the range [lo, hi) contains point.
+//   - (zero key, zero key, zero value, false) otherwise.
+func (m *Map[K, V]) Range(point K) (K, K, V, bool) {
+	if n, found := m.tree.Floor(point); found {
+		if s := m.tree.Value(n); m.less(point, s.hi) {
+			return m.tree.Key(n), s.hi, s.value, true
+		}
+	}
+	var k K
+	var v V
+	return k, k, v, false
+}
+
+// Ascend calls f for each range [lo, hi) and its value in ascending order, until f returns false.
+//
+// The map must not be modified during iteration.
+func (m *Map[K, V]) Ascend(f func(lo, hi K, value V) bool) {
+	for n := m.tree.Min(m.tree.Root()); !m.tree.IsNil(n); n = m.tree.Successor(n) {
+		if s := m.tree.Value(n); !f(m.tree.Key(n), s.hi, s.value) {
+			return
+		}
+	}
+}
+
+// IsTreeValid checks whether the map satisfies its properties:
+//   - The underlying Red-Black Tree is valid.
+//   - Every range is non-empty, and ends at or before the start of the next range.
+//   - Adjacent ranges are not mapped to the same value.
+//
+// Returns:
+//   - nil if the map is valid.
+//   - An error describing the first violation found otherwise.
+func (m *Map[K, V]) IsTreeValid() error {
+	if err := m.tree.IsTreeValid(); err != nil {
+		return err
+	}
+	var prev *span[K, V]
+	for n := m.tree.Min(m.tree.Root()); !m.tree.IsNil(n); n = m.tree.Successor(n) {
+		lo, s := m.tree.Key(n), m.tree.Value(n)
+		switch {
+		case !m.less(lo, s.hi):
+			return fmt.Errorf("range [%v, %v) is empty", lo, s.hi)
+		case prev != nil && m.less(lo, prev.hi):
+			return fmt.Errorf("range [%v, %v) overlaps the previous range", lo, s.hi)
+		case prev != nil && m.equal(lo, prev.hi) && prev.value == s.value:
+			return fmt.Errorf("range [%v, %v) should have been merged with the previous range", lo, s.hi)
+		}
+		prev = s
+	}
+	return nil
+}
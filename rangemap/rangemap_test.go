@@ -0,0 +1,125 @@
+package rangemap
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+type testRange struct {
+	lo, hi, value int
+}
+
+// runs returns the maximal runs of equal, mapped values in model, where 0 means unmapped.
+func runs(model []int) []testRange {
+	var result []testRange
+	for i := 0; i < len(model); {
+		j := i + 1
+		for j < len(model) && model[j] == model[i] {
+			j++
+		}
+		if model[i] != 0 {
+			result = append(result, testRange{i, j, model[i]})
+		}
+		i = j
+	}
+	return result
+}
+
+func ranges(m *Map[int, int]) []testRange {
+	var result []testRange
+	m.Ascend(func(lo, hi, value int) bool {
+		result = append(result, testRange{lo, hi, value})
+		return true
+	})
+	return result
+}
+
+func TestMap(t *testing.T) {
+	m := New[int, int](intLess)
+	_, found := m.Lookup(0)
+	assert.False(t, found)
+	assert.Nil(t, ranges(m))
+
+	// model of the map: the value of each point, 0 if unmapped
+	model := make([]int, 200)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		lo := rng.Intn(len(model) - 1)
+		hi := lo + 1 + rng.Intn(min(30, len(model)-lo-1))
+		if rng.Intn(3) == 0 {
+			expected := false
+			for p := lo; p < hi; p++ {
+				expected = expected || model[p] != 0
+				model[p] = 0
+			}
+			assert.Equal(t, expected, m.Delete(lo, hi))
+		} else {
+			value := 1 + rng.Intn(3)
+			m.Insert(lo, hi, value)
+			for p := lo; p < hi; p++ {
+				model[p] = value
+			}
+		}
+
+		require.NoError(t, m.IsTreeValid())
+		expected := runs(model)
+		require.Equal(t, expected, ranges(m))
+		require.Equal(t, len(expected), m.Len())
+		if i%20 == 0 {
+			for p := -1; p <= len(model); p++ {
+				value, found := m.Lookup(p)
+				if p >= 0 && p < len(model) && model[p] != 0 {
+					require.True(t, found)
+					require.Equal(t, model[p], value)
+				} else {
+					require.False(t, found)
+				}
+			}
+		}
+	}
+}
+
+func TestMap_Range(t *testing.T) {
+	m := New[int, string](intLess)
+	m.Insert(0, 100, "low")
+	m.Insert(50, 60, "mid")
+
+	lo, hi, value, found := m.Range(55)
+	assert.True(t, found)
+	assert.Equal(t, []any{50, 60, "mid"}, []any{lo, hi, value})
+	lo, hi, value, found = m.Range(60)
+	assert.True(t, found)
+	assert.Equal(t, []any{60, 100, "low"}, []any{lo, hi, value})
+	_, _, _, found = m.Range(100)
+	assert.False(t, found)
+
+	// inserting the surrounding value again merges the three ranges back into one
+	m.Insert(50, 60, "low")
+	assert.Equal(t, 1, m.Len())
+	lo, hi, _, _ = m.Range(10)
+	assert.Equal(t, []int{0, 100}, []int{lo, hi})
+	assert.NoError(t, m.IsTreeValid())
+}
+
+func TestMap_EmptyRange(t *testing.T) {
+	m := New[int, int](intLess)
+	assert.Panics(t, func() { m.Insert(5, 5, 1) })
+	assert.Panics(t, func() { m.Delete(6, 5) })
+}
+
+func TestMap_Ascend_Stop(t *testing.T) {
+	m := New[int, int](intLess)
+	for i := 0; i < 10; i++ {
+		m.Insert(i*10, i*10+5, i)
+	}
+	var los []int
+	m.Ascend(func(lo, hi, value int) bool {
+		los = append(los, lo)
+		return len(los) < 3
+	})
+	assert.Equal(t, []int{0, 10, 20}, los)
+}
@@ -0,0 +1,305 @@
+// Package aggtree provides an aggregate tree: a Red-Black Tree maintaining a user-defined aggregate,
+// such as a sum, minimum or maximum of the values, over every subtree.
+//
+// The aggregate is defined by a Monoid: an identity element, an associative function combining
+// two aggregates, and a function extracting the aggregate of a single key and value. Each node
+// is augmented with the aggregate of its subtree, in key order, which is maintained through
+// insertions, deletions, value updates and the rotations performed by the red-black fixups
+// (see bst.Tree.SetAugmentFunc), so that the aggregate of the whole tree is available in O(1) time.
+//
+// This generalizes the subtree sizes of rbtree/ostree and the subtree maximums of rbtree/interval
+// to any aggregate. Sum, Min and Max provide the most common monoids.
+//
+// Queries:
+//   - Tree.Aggregate returns the aggregate of the whole tree, in O(1) time.
+//   - Tree.RangeReduce returns the aggregate of the keys within a key range, in O(log n) time,
+//     e.g. a rolling sum over time-keyed entries.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/rbtree/aggtree"
+//
+//	tree := aggtree.New(func(a, b string) bool { return a < b }, aggtree.Sum[string, int]())
+//	tree.Insert("apples", 3)
+//	tree.Insert("pears", 5)
+//	total := tree.Aggregate() // 8
+//
+// # Limitations
+//
+// Keys are unique: inserting an existing key updates its value. The tree is not safe for concurrent use.
+package aggtree
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/mikenye/gotrees/bst"
+	"github.com/mikenye/gotrees/rbtree"
+	"reflect"
+)
+
+// Monoid defines the aggregate maintained by a Tree.
+//
+// Combine must be associative, with Identity as its identity element, i.e. Combine(Identity, a) and
+// Combine(a, Identity) must both equal a. Combine need not be commutative: aggregates are always
+// combined in ascending key order.
+//
+// Equal is only used by Tree.IsTreeValid, to check the aggregate held by each node. If it is nil,
+// aggregates are compared with reflect.DeepEqual, which never considers a NaN equal to itself.
+type Monoid[K, V, A any] struct {
+	Identity A                      // Aggregate of an empty tree
+	Combine  func(a, b A) A         // Combines the aggregates of adjacent key ranges, a before b
+	Extract  func(key K, value V) A // Returns the aggregate of a single key and value
+	Equal    func(a, b A) bool      // Reports whether two aggregates are equal, or nil to use reflect.DeepEqual
+}
+
+// Number is a constraint permitting any integer or floating-point type, for Sum.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Sum returns a monoid aggregating the sum of the values.
+func Sum[K any, V Number]() Monoid[K, V, V] {
+	return Monoid[K, V, V]{
+		Combine: func(a, b V) V {
+			return a + b
+		},
+		Extract: func(_ K, value V) V {
+			return value
+		},
+		Equal: equal[V],
+	}
+}
+
+// Min returns a monoid aggregating the minimum of the values.
+//
+// Parameters:
+//   - identity: A value no less than any value, returned for an empty tree, e.g. math.MaxInt or math.Inf(1).
+func Min[K any, V cmp.Ordered](identity V) Monoid[K, V, V] {
+	return Monoid[K, V, V]{
+		Identity: identity,
+		Combine: func(a, b V) V {
+			return min(a, b)
+		},
+		Extract: func(_ K, value V) V {
+			return value
+		},
+		Equal: equal[V],
+	}
+}
+
+// Max returns a monoid aggregating the maximum of the values.
+//
+// Parameters:
+//   - identity: A value no greater than any value, returned for an empty tree, e.g. math.MinInt or math.Inf(-1).
+func Max[K any, V cmp.Ordered](identity V) Monoid[K, V, V] {
+	return Monoid[K, V, V]{
+		Identity: identity,
+		Combine: func(a, b V) V {
+			return max(a, b)
+		},
+		Extract: func(_ K, value V) V {
+			return value
+		},
+		Equal: equal[V],
+	}
+}
+
+// equal reports whether a and b are equal, considering NaN equal to itself, as Sum, Min and Max may
+// aggregate NaN values.
+func equal[V comparable](a, b V) bool {
+	return a == b || a != a && b != b
+}
+
+// node is a node of the underlying tree.
+type node[K, V, A any] = bst.Node[K, V, rbtree.Aux[A]]
+
+// Tree represents a Red-Black Tree mapping keys of type K to values of type V, maintaining an
+// aggregate of type A over every subtree.
+//
+// Trees must be created with New.
+type Tree[K, V, A any] struct {
+	tree   *rbtree.AuxTree[K, V, A] // Underlying Red-Black Tree, augmented with subtree aggregates
+	monoid Monoid[K, V, A]          // Aggregate maintained over every subtree
+}
+
+// New creates and returns a new empty aggregate tree.
+//
+// Parameters:
+//   - less: A function that defines the ordering of keys.
+//   - monoid: The aggregate to maintain, such as Sum, Min or Max.
+//
+// Returns:
+//   - A pointer to a newly created Tree[K, V, A] instance.
+func New[K, V, A any](less bst.LessFunc[K], monoid Monoid[K, V, A]) *Tree[K, V, A] {
+	t := &Tree[K, V, A]{tree: rbtree.NewAux[K, V, A](less), monoid: monoid}
+	t.tree.SetAugmentFunc(t.augment)
+	return t
+}
+
+// subtree returns the aggregate of the subtree rooted at n, which is the identity if n is the sentinel nil node.
+func (t *Tree[K, V, A]) subtree(n *node[K, V, A]) A {
+	if t.tree.IsNil(n) {
+		return t.monoid.Identity
+	}
+	return t.tree.Aux(n)
+}
+
+// augment recomputes the aggregate of the subtree rooted at n, from the aggregates of its children.
+func (t *Tree[K, V, A]) augment(n *node[K, V, A]) {
+	t.tree.SetAux(n, t.aggregate(n))
+}
+
+// aggregate combines the aggregates of n's left subtree, n itself, and n's right subtree, in that order.
+func (t *Tree[K, V, A]) aggregate(n *node[K, V, A]) A {
+	a := t.monoid.Extract(t.tree.Key(n), t.tree.Value(n))
+	if l := t.tree.Left(n); !t.tree.IsNil(l) {
+		a = t.monoid.Combine(t.tree.Aux(l), a)
+	}
+	if r := t.tree.Right(n); !t.tree.IsNil(r) {
+		a = t.monoid.Combine(a, t.tree.Aux(r))
+	}
+	return a
+}
+
+// Size returns the number of keys in the tree.
+//
+// This is an O(1) operation.
+func (t *Tree[K, V, A]) Size() int {
+	return t.tree.Size()
+}
+
+// Insert inserts the given key and value into the tree, updating the aggregates of its ancestors.
+//
+// If the key already exists, its value is updated.
+//
+// Returns:
+//   - true if a new key was inserted.
+//   - false if the key existed and its value was updated.
+func (t *Tree[K, V, A]) Insert(key K, value V) bool {
+	_, inserted := t.tree.Insert(key, value)
+	return inserted
+}
+
+// Search returns the value associated with key.
+//
+// Returns:
+//   - (value, true) if the key exists in the tree.
+//   - (zero value, false) if the key is not found.
+func (t *Tree[K, V, A]) Search(key K) (V, bool) {
+	if n, found := t.tree.Search(key); found {
+		return t.tree.Value(n), true
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete removes key from the tree, updating the aggregates of its ancestors.
+//
+// Returns:
+//   - true if the key was found and removed.
+//   - false if the key was not found.
+func (t *Tree[K, V, A]) Delete(key K) bool {
+	n, found := t.tree.Search(key)
+	if !found {
+		return false
+	}
+	_, deleted := t.tree.Delete(n)
+	return deleted
+}
+
+// Aggregate returns the aggregate of every key and value in the tree, combined in ascending key order,
+// or the identity of the monoid if the tree is empty.
+//
+// This is an O(1) operation.
+func (t *Tree[K, V, A]) Aggregate() A {
+	return t.subtree(t.tree.Root())
+}
+
+// RangeReduce returns the aggregate of the keys within the half-open interval [lo, hi), and their values,
+// combined in ascending key order, or the identity of the monoid if there are no such keys.
+//
+// The aggregates of the subtrees lying entirely within the interval are combined without visiting their
+// nodes, so this is an O(log n) operation however many keys fall within the interval.
+//
+// Example Usage:
+//
+//	// sum of the values keyed by the last minute, e.g. to compute a rolling sum
+//	now := time.Now()
+//	total := tree.RangeReduce(now.Add(-time.Minute), now)
+func (t *Tree[K, V, A]) RangeReduce(lo, hi K) A {
+	return t.rangeReduce(t.tree.Root(), lo, hi, true, true)
+}
+
+// rangeReduce returns the aggregate of the keys of the subtree rooted at n within [lo, hi), where bounds
+// not checked (hasLo or hasHi false) are known to hold for every key of the subtree.
+//
+// Once the search paths for lo and hi diverge, each side follows a single path, on which the subtrees
+// known to lie within the interval are combined in O(1) time.
+func (t *Tree[K, V, A]) rangeReduce(n *node[K, V, A], lo, hi K, hasLo, hasHi bool) A {
+	if t.tree.IsNil(n) {
+		return t.monoid.Identity
+	}
+	if !hasLo && !hasHi {
+		return t.tree.Aux(n)
+	}
+	key := t.tree.Key(n)
+	if hasLo && t.tree.Less(key, lo) {
+		return t.rangeReduce(t.tree.Right(n), lo, hi, hasLo, hasHi)
+	}
+	if hasHi && !t.tree.Less(key, hi) {
+		return t.rangeReduce(t.tree.Left(n), lo, hi, hasLo, hasHi)
+	}
+	a := t.monoid.Extract(key, t.tree.Value(n))
+	if l := t.tree.Left(n); !t.tree.IsNil(l) {
+		a = t.monoid.Combine(t.rangeReduce(l, lo, hi, hasLo, false), a)
+	}
+	if r := t.tree.Right(n); !t.tree.IsNil(r) {
+		a = t.monoid.Combine(a, t.rangeReduce(r, lo, hi, false, hasHi))
+	}
+	return a
+}
+
+// Ascend calls f for each key and value in ascending key order, until f returns false.
+//
+// f must not modify the tree.
+func (t *Tree[K, V, A]) Ascend(f func(key K, value V) bool) {
+	if t.Size() == 0 {
+		return
+	}
+	t.tree.TraverseInOrder(t.tree.Root(), func(n *node[K, V, A]) bool {
+		return f(t.tree.Key(n), t.tree.Value(n))
+	})
+}
+
+// IsTreeValid checks whether the underlying tree is a valid Red-Black Tree (see rbtree.Tree.IsTreeValid),
+// and whether every node holds the aggregate of its subtree.
+//
+// Aggregates are compared with the Equal function of the monoid, or with reflect.DeepEqual if it is nil.
+//
+// Returns:
+//   - nil if the tree is valid.
+//   - An error describing the first violation found otherwise.
+func (t *Tree[K, V, A]) IsTreeValid() error {
+	if err := t.tree.IsTreeValid(); err != nil {
+		return err
+	}
+	if t.Size() == 0 {
+		return nil
+	}
+	return t.tree.TraverseInOrderErr(t.tree.Root(), func(n *node[K, V, A]) error {
+		if got, expected := t.tree.Aux(n), t.aggregate(n); !t.equal(got, expected) {
+			return fmt.Errorf("node %v has subtree aggregate %v, expected %v", t.tree.Key(n), got, expected)
+		}
+		return nil
+	})
+}
+
+// equal reports whether aggregates a and b are equal, with the Equal function of the monoid if any.
+func (t *Tree[K, V, A]) equal(a, b A) bool {
+	if t.monoid.Equal != nil {
+		return t.monoid.Equal(a, b)
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,195 @@
+package aggtree
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func intLess(a, b int) bool {
+	return a < b
+}
+
+func TestTree_InsertSearchDelete(t *testing.T) {
+	tree := New(intLess, Sum[int, int]())
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 0, tree.Aggregate(), "expected empty tree to aggregate to the identity")
+
+	rng := rand.New(rand.NewSource(1))
+	expected := make(map[int]int)
+
+	for i := 0; i < 10000; i++ {
+		key := rng.Intn(1000)
+		_, exists := expected[key]
+		if rng.Intn(3) == 0 {
+			assert.Equal(t, exists, tree.Delete(key), "unexpected result deleting %d", key)
+			delete(expected, key)
+		} else {
+			value := rng.Intn(100)
+			assert.Equal(t, !exists, tree.Insert(key, value), "unexpected result inserting %d", key)
+			expected[key] = value
+		}
+		if i%500 == 0 {
+			require.NoError(t, tree.IsTreeValid())
+		}
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, len(expected), tree.Size())
+
+	sum := 0
+	for key, value := range expected {
+		v, found := tree.Search(key)
+		assert.True(t, found)
+		assert.Equal(t, value, v)
+		sum += value
+	}
+	assert.Equal(t, sum, tree.Aggregate())
+	_, found := tree.Search(1000)
+	assert.False(t, found)
+
+	prev := -1
+	tree.Ascend(func(key, value int) bool {
+		assert.Less(t, prev, key, "expected keys in ascending order")
+		assert.Equal(t, expected[key], value)
+		prev = key
+		return true
+	})
+}
+
+func TestTree_MinMax(t *testing.T) {
+	lowest := New(intLess, Min[int](math.MaxInt))
+	highest := New(intLess, Max[int](math.MinInt))
+	assert.Equal(t, math.MaxInt, lowest.Aggregate())
+	assert.Equal(t, math.MinInt, highest.Aggregate())
+
+	rng := rand.New(rand.NewSource(1))
+	expected := make(map[int]int)
+	for i := 0; i < 2000; i++ {
+		key, value := rng.Intn(200), rng.Intn(1000)-500
+		if rng.Intn(3) == 0 {
+			lowest.Delete(key)
+			highest.Delete(key)
+			delete(expected, key)
+		} else {
+			lowest.Insert(key, value)
+			highest.Insert(key, value)
+			expected[key] = value
+		}
+
+		lo, hi := math.MaxInt, math.MinInt
+		for _, v := range expected {
+			lo, hi = min(lo, v), max(hi, v)
+		}
+		require.Equal(t, lo, lowest.Aggregate(), "unexpected minimum after operation %d", i)
+		require.Equal(t, hi, highest.Aggregate(), "unexpected maximum after operation %d", i)
+	}
+	require.NoError(t, lowest.IsTreeValid())
+	require.NoError(t, highest.IsTreeValid())
+}
+
+func TestTree_nonCommutative(t *testing.T) {
+	// concatenation is not commutative, so the aggregate reveals the order keys are combined in
+	tree := New(intLess, Monoid[int, string, string]{
+		Combine: func(a, b string) string {
+			return a + b
+		},
+		Extract: func(_ int, value string) string {
+			return value
+		},
+	})
+	letters := "abcdefghijklmnopqrstuvwxyz"
+	for _, i := range rand.New(rand.NewSource(1)).Perm(len(letters)) {
+		tree.Insert(i, letters[i:i+1])
+	}
+	assert.Equal(t, letters, tree.Aggregate())
+
+	tree.Insert(0, "A")
+	tree.Delete(25)
+	assert.Equal(t, "A"+letters[1:25], tree.Aggregate())
+	require.NoError(t, tree.IsTreeValid())
+}
+
+func TestTree_IsTreeValid_uncomparable(t *testing.T) {
+	// a slice-valued aggregate, compared with reflect.DeepEqual
+	tree := New(intLess, Monoid[int, int, []int]{
+		Combine: func(a, b []int) []int {
+			return append(append([]int(nil), a...), b...)
+		},
+		Extract: func(key int, _ int) []int {
+			return []int{key}
+		},
+	})
+	for _, i := range rand.New(rand.NewSource(1)).Perm(50) {
+		tree.Insert(i, i)
+		require.NoError(t, tree.IsTreeValid())
+	}
+	assert.Equal(t, 50, len(tree.Aggregate()))
+	tree.Delete(10)
+	require.NoError(t, tree.IsTreeValid())
+}
+
+func TestTree_IsTreeValid_NaN(t *testing.T) {
+	tree := New(intLess, Sum[int, float64]())
+	tree.Insert(1, 1)
+	tree.Insert(2, math.NaN())
+	tree.Insert(3, 3)
+	assert.True(t, math.IsNaN(tree.Aggregate()))
+	require.NoError(t, tree.IsTreeValid(), "expected NaN aggregates to compare equal")
+}
+
+func TestTree_extractKey(t *testing.T) {
+	// the aggregate may depend on keys as well as values
+	tree := New(intLess, Monoid[int, string, int]{
+		Combine: func(a, b int) int {
+			return a + b
+		},
+		Extract: func(key int, value string) int {
+			return key * len(value)
+		},
+	})
+	tree.Insert(2, "ab")
+	tree.Insert(3, "abc")
+	tree.Insert(5, strings.Repeat("a", 10))
+	assert.Equal(t, 2*2+3*3+5*10, tree.Aggregate())
+	require.NoError(t, tree.IsTreeValid())
+}
+
+func TestTree_RangeReduce(t *testing.T) {
+	sums := New(intLess, Sum[int, int]())
+	concat := New(intLess, Monoid[int, int, string]{
+		Combine: func(a, b string) string {
+			return a + b
+		},
+		Extract: func(key int, _ int) string {
+			return strconv.Itoa(key) + ","
+		},
+	})
+	assert.Equal(t, 0, sums.RangeReduce(0, 100), "expected empty tree to aggregate to the identity")
+
+	rng := rand.New(rand.NewSource(1))
+	expected := make(map[int]int)
+	for i := 0; i < 500; i++ {
+		key, value := rng.Intn(300), rng.Intn(100)
+		sums.Insert(key, value)
+		concat.Insert(key, value)
+		expected[key] = value
+	}
+
+	for i := 0; i < 2000; i++ {
+		lo, hi := rng.Intn(320)-10, rng.Intn(320)-10
+		sum, keys := 0, ""
+		for key := lo; key < hi; key++ {
+			if value, ok := expected[key]; ok {
+				sum += value
+				keys += strconv.Itoa(key) + ","
+			}
+		}
+		require.Equal(t, sum, sums.RangeReduce(lo, hi), "unexpected sum of [%d, %d)", lo, hi)
+		require.Equal(t, keys, concat.RangeReduce(lo, hi), "unexpected keys in [%d, %d)", lo, hi)
+	}
+	assert.Equal(t, sums.Aggregate(), sums.RangeReduce(math.MinInt, math.MaxInt))
+}
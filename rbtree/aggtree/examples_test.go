@@ -0,0 +1,51 @@
+package aggtree_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/rbtree/aggtree"
+	"math"
+)
+
+func ExampleTree_Aggregate() {
+
+	// track the stock of each product, and the lowest stock of any product
+	less := func(a, b string) bool { return a < b }
+	stock := aggtree.New(less, aggtree.Sum[string, int]())
+	lowest := aggtree.New(less, aggtree.Min[string](math.MaxInt))
+	for product, count := range map[string]int{"apples": 3, "pears": 5, "plums": 12} {
+		stock.Insert(product, count)
+		lowest.Insert(product, count)
+	}
+	fmt.Println("total:", stock.Aggregate(), "lowest:", lowest.Aggregate())
+
+	// restock apples, and sell out of plums
+	stock.Insert("apples", 10)
+	lowest.Insert("apples", 10)
+	stock.Delete("plums")
+	lowest.Delete("plums")
+	fmt.Println("total:", stock.Aggregate(), "lowest:", lowest.Aggregate())
+
+	// Output:
+	// total: 20 lowest: 3
+	// total: 15 lowest: 5
+}
+
+func ExampleTree_RangeReduce() {
+
+	// record the bytes sent each second
+	sent := aggtree.New(func(a, b int) bool { return a < b }, aggtree.Sum[int, int]())
+	for second, bytes := range []int{100, 250, 0, 75, 300, 125} {
+		sent.Insert(second, bytes)
+	}
+
+	// compute a rolling sum over the last 3 seconds, without rescanning them
+	for now := 3; now <= 6; now++ {
+		fmt.Printf("bytes sent in [%d, %d): %d\n", now-3, now, sent.RangeReduce(now-3, now))
+	}
+
+	// Output:
+	// bytes sent in [0, 3): 350
+	// bytes sent in [1, 4): 325
+	// bytes sent in [2, 5): 375
+	// bytes sent in [3, 6): 500
+}
@@ -0,0 +1,70 @@
+package rbtree
+
+import "github.com/mikenye/gotrees/bst"
+
+// Aux is the node metadata of an AuxTree: the node's Color, with user data of type A alongside it.
+type Aux[A any] struct {
+	Color Color `json:"color"` // Color of the node, maintained by the tree
+	Value A     `json:"aux"`   // User data, set with AuxTree.SetAux
+}
+
+// NodeColor returns the color of the node (see ColorMetadata).
+func (m Aux[A]) NodeColor() Color {
+	return m.Color
+}
+
+// WithNodeColor returns a copy of m with the color of the node set to color, keeping the user data (see ColorMetadata).
+func (m Aux[A]) WithNodeColor(color Color) Aux[A] {
+	m.Color = color
+	return m
+}
+
+// AuxTree represents a Red-Black Tree whose nodes hold user data of type A alongside their color,
+// as rbtree otherwise uses node metadata for colors. The user data of a node is preserved as the tree
+// is rebalanced, as nodes are relinked rather than having their contents copied.
+//
+// This allows augmented Red-Black Trees to be built by keeping aggregates, such as subtree sums,
+// in the user data of each node and maintaining them with bst.Tree.SetAugmentFunc:
+//
+//	tree := rbtree.NewAux[int, int, int](less)
+//	tree.SetAugmentFunc(func(n *bst.Node[int, int, rbtree.Aux[int]]) {
+//		sum := n.Value()
+//		for _, c := range []*bst.Node[int, int, rbtree.Aux[int]]{tree.Left(n), tree.Right(n)} {
+//			if !tree.IsNil(c) {
+//				sum += tree.Aux(c)
+//			}
+//		}
+//		tree.SetAux(n, sum)
+//	})
+//
+// Trees must be created with NewAux.
+type AuxTree[K, V, A any] struct {
+	*Base[K, V, Aux[A]]
+}
+
+// NewAux creates and returns a new empty Red-Black Tree whose nodes hold user data of type A (see New).
+//
+// Returns:
+//   - A pointer to a newly created AuxTree[K, V, A] instance.
+func NewAux[K, V, A any](less bst.LessFunc[K], opts ...bst.Option) *AuxTree[K, V, A] {
+	return &AuxTree[K, V, A]{Base: newBase[K, V, Aux[A]](less, opts...)}
+}
+
+// Aux returns the user data of node n, which is the zero value of A until set with AuxTree.SetAux.
+func (t *AuxTree[K, V, A]) Aux(n *bst.Node[K, V, Aux[A]]) A {
+	return t.Metadata(n).Value
+}
+
+// SetAux sets the user data of node n, leaving its color unchanged. Setting the user data of the
+// sentinel nil node has no effect.
+//
+// If a function registered with bst.Tree.SetAugmentFunc depends on the user data of n, the aggregates
+// of n's ancestors must then be refreshed with bst.Tree.RefreshPath.
+func (t *AuxTree[K, V, A]) SetAux(n *bst.Node[K, V, Aux[A]], aux A) {
+	if t.IsNil(n) {
+		return
+	}
+	m := t.Metadata(n)
+	m.Value = aux
+	t.tree.SetMetadata(n, m)
+}
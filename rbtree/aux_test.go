@@ -0,0 +1,107 @@
+package rbtree
+
+import (
+	"encoding/json"
+	"github.com/mikenye/gotrees/bst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func TestAuxTree(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tree := NewAux[int, int, string](func(a, b int) bool { return a < b })
+	for i := 0; i < 1000; i++ {
+		key := rng.Intn(500)
+		if n, found := tree.Search(key); found {
+			_, deleted := tree.Delete(n)
+			require.True(t, deleted)
+			continue
+		}
+		n, _ := tree.Insert(key, key)
+		red := tree.IsRed(n)
+		tree.SetAux(n, "aux")
+		require.Equal(t, red, tree.IsRed(n), "expected SetAux to leave the color unchanged")
+	}
+	require.NoError(t, tree.IsTreeValid())
+
+	// user data survives recoloring and rotations
+	tree.TraverseInOrder(tree.Root(), func(n *bst.Node[int, int, Aux[string]]) bool {
+		assert.Equal(t, "aux", tree.Aux(n), "unexpected user data at node %d", n.Key())
+		assert.Equal(t, tree.IsBlack(n), n.Metadata().Color == Black)
+		return true
+	})
+
+	tree.SetAux(tree.Sentinel(), "sentinel")
+	assert.Equal(t, "", tree.Aux(tree.Sentinel()), "expected the sentinel's user data to be left unchanged")
+}
+
+func TestAuxTree_augmented(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tree := NewAux[int, int, int](func(a, b int) bool { return a < b })
+	tree.SetAugmentFunc(func(n *bst.Node[int, int, Aux[int]]) {
+		sum := n.Value()
+		for _, c := range []*bst.Node[int, int, Aux[int]]{tree.Left(n), tree.Right(n)} {
+			if !tree.IsNil(c) {
+				sum += tree.Aux(c)
+			}
+		}
+		tree.SetAux(n, sum)
+	})
+
+	want := 0
+	for i := 0; i < 1000; i++ {
+		key := rng.Intn(200)
+		if n, found := tree.Search(key); found {
+			want -= n.Value()
+			_, deleted := tree.Delete(n)
+			require.True(t, deleted)
+		} else {
+			tree.Insert(key, key)
+			want += key
+		}
+		require.Equal(t, want, tree.Aux(tree.Root()), "unexpected subtree sum at the root after %d operations", i+1)
+	}
+	require.NoError(t, tree.IsTreeValid())
+}
+
+func TestAuxTree_JSON(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	tree := NewAux[int, string, int](less)
+	for i := 0; i < 50; i++ {
+		n, _ := tree.Insert(i, "value")
+		tree.SetAux(n, -i)
+	}
+
+	data, err := json.Marshal(tree)
+	require.NoError(t, err)
+	restored := NewAux[int, string, int](less)
+	require.NoError(t, json.Unmarshal(data, restored))
+	require.NoError(t, restored.IsTreeValid())
+	assert.True(t, tree.EqualStructure(restored.Base, func(a, b string) bool { return a == b }))
+	restored.TraverseInOrder(restored.Root(), func(n *bst.Node[int, string, Aux[int]]) bool {
+		assert.Equal(t, -n.Key(), restored.Aux(n), "expected user data to be restored")
+		return true
+	})
+}
+
+func TestAuxTree_UpdateKey(t *testing.T) {
+	tree := NewAux[int, string, int](func(a, b int) bool { return a < b })
+	nodes := make([]*bst.Node[int, string, Aux[int]], 0, 100)
+	for i := 0; i < 100; i++ {
+		n, _ := tree.Insert(i, "value")
+		tree.SetAux(n, i*i)
+		nodes = append(nodes, n)
+	}
+
+	// moving nodes across the tree preserves their user data
+	for i, n := range nodes {
+		require.True(t, tree.UpdateKey(n, 1000-i))
+	}
+	require.NoError(t, tree.IsTreeValid())
+	for i, n := range nodes {
+		assert.Equal(t, 1000-i, tree.Key(n))
+		assert.Equal(t, i*i, tree.Aux(n))
+	}
+}
@@ -1,7 +1,9 @@
 package rbtree
 
 import (
+	"fmt"
 	"github.com/mikenye/gotrees/bst"
+	"math/rand"
 	"testing"
 )
 
@@ -41,3 +43,170 @@ func BenchmarkTree_Insert(b *testing.B) {
 		i++
 	}
 }
+
+// BenchmarkTree_Churn inserts and deletes short-lived items in the benchmarking loop,
+// with the default, pooled and arena node allocation.
+func BenchmarkTree_Churn(b *testing.B) {
+	for name, opts := range map[string][]bst.Option{
+		"default": nil,
+		"pooled":  {bst.WithNodePool()},
+		"arena":   {bst.WithAllocator(bst.NewArena[int, struct{}, Color](0))},
+	} {
+		b.Run(name, func(b *testing.B) {
+			tree := New[int, struct{}](func(a, b int) bool {
+				return a < b
+			}, opts...)
+			for i := 0; i < 100_000; i++ {
+				tree.Insert(i, struct{}{})
+			}
+			i := 0
+			b.ReportAllocs()
+			b.ResetTimer()
+			for b.Loop() {
+				n, _ := tree.Search(i)
+				tree.Delete(n)
+				tree.Insert(i+100_000, struct{}{})
+				i++
+			}
+		})
+	}
+}
+
+// BenchmarkTree_DeleteWhere purges a share of a 100k node tree in the benchmarking loop,
+// comparing Tree.DeleteWhere with collecting the matching keys, then searching for and deleting each.
+func BenchmarkTree_DeleteWhere(b *testing.B) {
+	const size = 100_000
+	keys := rand.New(rand.NewSource(1)).Perm(size)
+	for _, percent := range []int{10, 50, 90} {
+		setup := func() *Tree[int, struct{}] {
+			tree := NewOrdered[int, struct{}]()
+			for _, k := range keys {
+				tree.Insert(k, struct{}{})
+			}
+			return tree
+		}
+		match := func(k int) bool { return k*7919%100 < percent }
+		b.Run(fmt.Sprintf("DeleteWhere/%d%%", percent), func(b *testing.B) {
+			for b.Loop() {
+				b.StopTimer()
+				tree := setup()
+				b.StartTimer()
+				tree.DeleteWhere(func(k int, _ struct{}) bool { return match(k) })
+			}
+		})
+		b.Run(fmt.Sprintf("SearchDelete/%d%%", percent), func(b *testing.B) {
+			for b.Loop() {
+				b.StopTimer()
+				tree := setup()
+				b.StartTimer()
+				var matched []int
+				for n := tree.Min(tree.Root()); !tree.IsNil(n); n = tree.Successor(n) {
+					if match(n.Key()) {
+						matched = append(matched, n.Key())
+					}
+				}
+				for _, k := range matched {
+					n, _ := tree.Search(k)
+					tree.Delete(n)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkTree_Strategy compares the BottomUp and TopDown strategies, inserting random keys into
+// a tree, and deleting random nodes from a 100k node tree while inserting others to keep its size.
+func BenchmarkTree_Strategy(b *testing.B) {
+	const size = 100_000
+	for _, strategy := range []Strategy{BottomUp, TopDown} {
+		b.Run("Insert/"+strategy.String(), func(b *testing.B) {
+			tree := New[int, struct{}](func(a, b int) bool {
+				return a < b
+			})
+			tree.SetStrategy(strategy)
+			rng := rand.New(rand.NewSource(1))
+			b.ResetTimer()
+			for b.Loop() {
+				tree.Insert(rng.Int(), struct{}{})
+			}
+		})
+		b.Run("Delete/"+strategy.String(), func(b *testing.B) {
+			tree := New[int, struct{}](func(a, b int) bool {
+				return a < b
+			})
+			tree.SetStrategy(strategy)
+			rng := rand.New(rand.NewSource(1))
+			for tree.Size() < size {
+				tree.Insert(rng.Int(), struct{}{})
+			}
+			b.ResetTimer()
+			for b.Loop() {
+				n, _ := tree.Select(rng.Intn(size))
+				tree.Delete(n)
+				b.StopTimer()
+				tree.Insert(rng.Int(), struct{}{})
+				b.StartTimer()
+			}
+		})
+	}
+}
+
+// BenchmarkTree_LazyDeletion deletes a batch of 1000 keys from a 100k node tree, then reinserts them,
+// in the benchmarking loop, with eager and lazy deletion.
+func BenchmarkTree_LazyDeletion(b *testing.B) {
+	const size, batch = 100_000, 1000
+	for name, ratio := range map[string]float64{"eager": 0, "lazy": 0.25} {
+		b.Run(name, func(b *testing.B) {
+			tree := New[int, struct{}](func(a, b int) bool {
+				return a < b
+			})
+			tree.SetLazyDeletion(ratio)
+			for i := 0; i < size; i++ {
+				tree.Insert(i, struct{}{})
+			}
+			keys := rand.New(rand.NewSource(1)).Perm(size)
+			i := 0
+			b.ResetTimer()
+			for b.Loop() {
+				storm := keys[i%(size/batch)*batch:][:batch]
+				for _, k := range storm {
+					n, _ := tree.Search(k)
+					tree.Delete(n)
+				}
+				for _, k := range storm {
+					tree.Insert(k, struct{}{})
+				}
+				i++
+			}
+		})
+	}
+}
+
+// BenchmarkTree_LoadSorted compares building a tree from sorted keys by insertion, and by LoadSorted
+// on one goroutine and on every CPU.
+func BenchmarkTree_LoadSorted(b *testing.B) {
+	const size = 1_000_000
+	keys := make([]int, size)
+	for i := range keys {
+		keys[i] = i
+	}
+	less := func(a, b int) bool { return a < b }
+	b.Run("insert", func(b *testing.B) {
+		for b.Loop() {
+			tree := New[int, struct{}](less)
+			for _, k := range keys {
+				tree.Insert(k, struct{}{})
+			}
+		}
+	})
+	for name, parallelism := range map[string]int{"sequential": 1, "parallel": 0} {
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				tree := New[int, struct{}](less)
+				if err := tree.LoadSorted(keys, nil, parallelism); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package rbtree
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/bst"
+	"io"
+)
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, restoring a tree encoded by bst.Tree.MarshalBinary
+// (which rbtree.Tree exposes), including node colors.
+//
+// The tree must have been created with New. Its existing contents are replaced, and any handles
+// to its previous nodes become stale. The restored tree is validated with Tree.IsTreeValid, so that
+// both BST ordering and Red-Black properties hold. If the data cannot be decoded or describes an
+// invalid tree, an error is returned and the tree is left unchanged.
+//
+// ⚠️ Important: The restored tree replaces the underlying bst.Tree, so any functions registered
+// with bst.Tree.SetAugmentFunc, bst.Tree.SetNodeFormatter or bst.Tree.OnChange must be registered again.
+func (t *Base[K, V, M]) UnmarshalBinary(data []byte) error {
+	return t.restore(func(b *bst.Tree[K, V, M]) error {
+		return b.UnmarshalBinary(data)
+	})
+}
+
+// UnmarshalWith restores a tree encoded by bst.Tree.MarshalWith (which rbtree.Tree exposes),
+// including node colors, decoding data with unmarshal (see bst.Tree.UnmarshalWith).
+//
+// The tree must have been created with New. Its existing contents are replaced, and any handles
+// to its previous nodes become stale. The restored tree is validated with Tree.IsTreeValid, so that
+// both BST ordering and Red-Black properties hold. If the data cannot be decoded or describes an
+// invalid tree, an error is returned and the tree is left unchanged.
+//
+// ⚠️ Important: The restored tree replaces the underlying bst.Tree, so any functions registered
+// with bst.Tree.SetAugmentFunc, bst.Tree.SetNodeFormatter or bst.Tree.OnChange must be registered again.
+func (t *Base[K, V, M]) UnmarshalWith(data []byte, unmarshal bst.UnmarshalFunc) error {
+	return t.restore(func(b *bst.Tree[K, V, M]) error {
+		return b.UnmarshalWith(data, unmarshal)
+	})
+}
+
+// UnmarshalProto restores a tree encoded by bst.Tree.MarshalProto (which rbtree.Tree exposes),
+// decoding keys, values and colors with the functions of c (see bst.Tree.UnmarshalProto), which
+// must therefore include MarshalMetadata and UnmarshalMetadata.
+//
+// The tree must have been created with New. Its existing contents are replaced, and any handles
+// to its previous nodes become stale. The restored tree is validated with Tree.IsTreeValid, so that
+// both BST ordering and Red-Black properties hold. If the data cannot be decoded or describes an
+// invalid tree, an error is returned and the tree is left unchanged.
+//
+// ⚠️ Important: The restored tree replaces the underlying bst.Tree, so any functions registered
+// with bst.Tree.SetAugmentFunc, bst.Tree.SetNodeFormatter or bst.Tree.OnChange must be registered again.
+func (t *Base[K, V, M]) UnmarshalProto(data []byte, c bst.ProtoCodec[K, V, M]) error {
+	return t.restore(func(b *bst.Tree[K, V, M]) error {
+		return b.UnmarshalProto(data, c)
+	})
+}
+
+// ReadSnapshot replaces the contents of the tree with a snapshot written by bst.Tree.SnapshotTo
+// (which rbtree.Tree exposes), decoding the tree with Tree.UnmarshalBinary. If an error is returned,
+// the tree is left unchanged.
+//
+// ⚠️ Important: As with Tree.UnmarshalBinary, functions registered with bst.Tree.OnChange must be
+// registered again.
+//
+// Returns:
+//   - (version, nil), where version is the version of the snapshotted tree.
+//   - (0, error) if the snapshot cannot be read or decoded.
+func (t *Base[K, V, M]) ReadSnapshot(r io.Reader) (uint64, error) {
+	return bst.ReadSnapshotFunc(r, t.UnmarshalBinary)
+}
+
+// restore replaces the contents of the tree with a tree restored by decode, provided the restored tree
+// is a valid Red-Black Tree. Otherwise, the tree is left unchanged and an error is returned.
+//
+// decode is given an empty bst.Tree created with the same LessFunc and options as this tree.
+func (t *Base[K, V, M]) restore(decode func(b *bst.Tree[K, V, M]) error) error {
+	if t.tree == nil {
+		return fmt.Errorf("cannot unmarshal into a tree not created with New")
+	}
+	restored := newBase[K, V, M](t.less, t.opts...)
+	if err := decode(restored.tree); err != nil {
+		return err
+	}
+	if err := restored.IsTreeValid(); err != nil {
+		return fmt.Errorf("invalid tree: %w", err)
+	}
+	t.tree = restored.tree
+	clear(t.tombstones)
+	return nil
+}
@@ -0,0 +1,113 @@
+package rbtree
+
+import (
+	"encoding/json"
+	"github.com/mikenye/gotrees/bst"
+	"github.com/mikenye/gotrees/codec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestTree_Binary(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	tree := New[int, string](less)
+	for i := 0; i < 1000; i++ {
+		tree.Insert(i, "value")
+	}
+
+	data, err := tree.MarshalBinary()
+	require.NoError(t, err)
+
+	restored := New[int, string](less)
+	old, _ := restored.Insert(5000, "old")
+	require.NoError(t, restored.UnmarshalBinary(data))
+	require.NoError(t, restored.IsTreeValid(), "expected valid tree")
+	assert.True(t, tree.EqualStructure(restored, func(a, b string) bool { return a == b }), "expected identical shape")
+	assert.False(t, restored.Contains(old), "expected previous nodes to become stale")
+
+	// colors are restored
+	a, b := tree.Min(tree.Root()), restored.Min(restored.Root())
+	for !tree.IsNil(a) {
+		assert.Equal(t, tree.Metadata(a), restored.Metadata(b), "unexpected color at node %d", tree.Key(a))
+		a, b = tree.Successor(a), restored.Successor(b)
+	}
+}
+
+func TestTree_UnmarshalBinary_invalid(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+
+	// a valid BST that is not a valid Red-Black Tree
+	invalid := New[int, string](less)
+	for i := 0; i < 10; i++ {
+		invalid.tree.Insert(i, "value") // bypass rbtree balancing
+	}
+	data, err := invalid.MarshalBinary()
+	require.NoError(t, err)
+
+	tree := New[int, string](less)
+	n, _ := tree.Insert(1, "one")
+	assert.Error(t, tree.UnmarshalBinary(data), "expected error for invalid Red-Black Tree")
+	assert.True(t, tree.Contains(n), "expected tree to be unchanged")
+	assert.Error(t, tree.UnmarshalBinary([]byte("garbage")), "expected error for garbage data")
+	assert.True(t, tree.Contains(n), "expected tree to be unchanged")
+
+	var zero Tree[int, string]
+	assert.Error(t, zero.UnmarshalBinary(data), "expected error unmarshaling into zero tree")
+}
+
+func TestTree_UnmarshalWith(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	tree := New[int, string](less)
+	for i := 0; i < 100; i++ {
+		tree.Insert(i, "value")
+	}
+
+	data, err := tree.MarshalWith(json.Marshal)
+	require.NoError(t, err)
+	restored := New[int, string](less)
+	require.NoError(t, restored.UnmarshalWith(data, json.Unmarshal))
+	require.NoError(t, restored.IsTreeValid(), "expected valid tree")
+	assert.True(t, tree.EqualStructure(restored, func(a, b string) bool { return a == b }), "expected identical shape")
+
+	// a valid BST that is not a valid Red-Black Tree
+	invalid := New[int, string](less)
+	for i := 0; i < 10; i++ {
+		invalid.tree.Insert(i, "value") // bypass rbtree balancing
+	}
+	data, err = invalid.MarshalWith(json.Marshal)
+	require.NoError(t, err)
+	assert.Error(t, restored.UnmarshalWith(data, json.Unmarshal), "expected error for invalid Red-Black Tree")
+	assert.Equal(t, 100, restored.Size(), "expected tree to be unchanged")
+}
+
+func TestTree_UnmarshalProto(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	c := bst.ProtoCodec[int, string, Color]{
+		Key:   codec.Ordered[int](),
+		Value: codec.String(),
+		Metadata: codec.Func(Color.MarshalJSON, func(data []byte) (Color, error) {
+			var c Color
+			err := c.UnmarshalJSON(data)
+			return c, err
+		}),
+	}
+	tree := New[int, string](less)
+	for i := 0; i < 100; i++ {
+		tree.Insert(i, "value")
+	}
+
+	data, err := tree.MarshalProto(c)
+	require.NoError(t, err)
+	restored := New[int, string](less)
+	require.NoError(t, restored.UnmarshalProto(data, c))
+	require.NoError(t, restored.IsTreeValid(), "expected valid tree")
+	assert.True(t, tree.EqualStructure(restored, func(a, b string) bool { return a == b }), "expected identical shape")
+
+	// without colors, the restored tree is not a valid Red-Black Tree
+	c.Metadata = nil
+	data, err = tree.MarshalProto(c)
+	require.NoError(t, err)
+	assert.Error(t, restored.UnmarshalProto(data, c), "expected error for invalid Red-Black Tree")
+	assert.Equal(t, 100, restored.Size(), "expected tree to be unchanged")
+}
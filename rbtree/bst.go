@@ -0,0 +1,672 @@
+package rbtree
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"io"
+	"iter"
+)
+
+// The methods below are those of bst.Tree that cannot break Red-Black Tree properties.
+// The underlying bst.Tree is not embedded, so that the methods relinking or recoloring nodes
+// (such as bst.Tree.RotateLeft, bst.Tree.SetLeft or bst.Tree.SetMetadata) are not part of
+// the API of Base at all, and misusing them is a compile-time error.
+
+// All returns an iterator over the keys and values of the tree, in ascending key order.
+//
+// See bst.Tree.All. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for n := t.Min(t.Root()); !t.IsNil(n); n = t.Successor(n) {
+			if !yield(t.Key(n), t.Value(n)) {
+				return
+			}
+		}
+	}
+}
+
+// AscendAt calls f for each node in ascending key order, starting from the node with zero-based rank i, until f returns false.
+//
+// See bst.Tree.AscendAt. The tree is compacted first, if any nodes have been deleted lazily (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) AscendAt(i int, f bst.TraversalFunc[K, V, M]) {
+	t.Compact()
+	t.tree.AscendAt(i, f)
+}
+
+// Backward returns an iterator over the keys and values of the tree, in descending key order.
+//
+// See bst.Tree.Backward. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for n := t.Max(t.Root()); !t.IsNil(n); n = t.Predecessor(n) {
+			if !yield(t.Key(n), t.Value(n)) {
+				return
+			}
+		}
+	}
+}
+
+// Ceiling finds the smallest key in the tree greater than or equal to key.
+//
+// See bst.Tree.Ceiling. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) Ceiling(key K) (*bst.Node[K, V, M], bool) {
+	n, found := t.tree.Ceiling(key)
+	if found && t.IsTombstone(n) {
+		n = t.Successor(n)
+		found = !t.IsNil(n)
+	}
+	return n, found
+}
+
+// Clear removes every node from the tree, leaving it empty.
+//
+// See bst.Tree.Clear.
+func (t *Base[K, V, M]) Clear() {
+	t.tree.Clear()
+	clear(t.tombstones)
+}
+
+// Contains checks whether the given node n is present in the tree.
+//
+// See bst.Tree.Contains. Nodes deleted lazily are not present (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) Contains(n *bst.Node[K, V, M]) bool {
+	return t.tree.Contains(n) && !t.IsTombstone(n)
+}
+
+// CopySubtree returns a new standalone tree holding a copy of the subtree rooted at node n, with the same shape, keys, values and metadata.
+//
+// See bst.Tree.CopySubtree.
+func (t *Base[K, V, M]) CopySubtree(n *bst.Node[K, V, M]) (*bst.Tree[K, V, M], bool) {
+	return t.tree.CopySubtree(n)
+}
+
+// Count returns the number of nodes in the tree with a key equal to key.
+//
+// See bst.Tree.Count. Nodes deleted lazily are not counted (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) Count(key K) int {
+	count := t.tree.Count(key)
+	if count == 0 {
+		return 0
+	}
+	return count - t.countTombstones(func(k K) bool {
+		return !t.tree.Less(k, key) && !t.tree.Less(key, k)
+	})
+}
+
+// CountRange returns the number of nodes whose key falls within the half-open interval [lo, hi).
+//
+// See bst.Tree.CountRange. Nodes deleted lazily are not counted (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) CountRange(lo, hi K) int {
+	count := t.tree.CountRange(lo, hi)
+	if count == 0 {
+		return 0
+	}
+	return count - t.countTombstones(func(k K) bool {
+		return !t.tree.Less(k, lo) && t.tree.Less(k, hi)
+	})
+}
+
+// Cursor returns a new cursor over the tree, positioned before the first node, so that the first call to Cursor.Next moves it to the node with the smallest key.
+//
+// See bst.Tree.Cursor. The tree is compacted first, so that the cursor does not visit nodes deleted lazily (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) Cursor() *bst.Cursor[K, V, M] {
+	t.Compact()
+	return t.tree.Cursor()
+}
+
+// DeltaSince returns the changes made to the tree since the given version, as recorded by the delta log (see WithDeltaLog).
+//
+// See bst.Tree.DeltaSince.
+func (t *Base[K, V, M]) DeltaSince(version uint64) (*bst.Delta[K, V], error) {
+	return t.tree.DeltaSince(version)
+}
+
+// Depth returns the depth of node n.
+//
+// See bst.Tree.Depth.
+func (t *Base[K, V, M]) Depth(n *bst.Node[K, V, M]) int {
+	return t.tree.Depth(n)
+}
+
+// DuplicatePolicy returns the policy resolving insertions of existing keys.
+//
+// See bst.Tree.DuplicatePolicy.
+func (t *Base[K, V, M]) DuplicatePolicy() bst.DuplicatePolicy {
+	return t.tree.DuplicatePolicy()
+}
+
+// Duplicates reports whether the tree is in multiset mode, allowing several nodes with equal keys.
+//
+// See bst.Tree.Duplicates.
+func (t *Base[K, V, M]) Duplicates() bool {
+	return t.tree.Duplicates()
+}
+
+// FingerCeiling finds the smallest key in the tree greater than or equal to key, as Tree.Ceiling does, starting from the node finger rather than from the root.
+//
+// See bst.Tree.FingerCeiling. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) FingerCeiling(finger *bst.Node[K, V, M], key K) (*bst.Node[K, V, M], bool) {
+	n, found := t.tree.FingerCeiling(finger, key)
+	if found && t.IsTombstone(n) {
+		n = t.Successor(n)
+		found = !t.IsNil(n)
+	}
+	return n, found
+}
+
+// FingerSearch looks for a node with the given key, starting from the node finger rather than from the root, as Tree.Search would.
+//
+// See bst.Tree.FingerSearch. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) FingerSearch(finger *bst.Node[K, V, M], key K) (*bst.Node[K, V, M], bool) {
+	n, found := t.tree.FingerSearch(finger, key)
+	for found && t.IsTombstone(n) {
+		// in multiset mode, a later node may hold an equal key
+		n = t.tree.Successor(n)
+		found = !t.IsNil(n) && !t.tree.Less(key, t.Key(n))
+	}
+	return n, found
+}
+
+// FirstEntry returns the smallest key in the tree and its value, without exposing the node holding them.
+//
+// See bst.Tree.FirstEntry. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) FirstEntry() (K, V, bool) {
+	return t.entry(t.Min(t.Root()))
+}
+
+// FirstKey returns the smallest key in the tree.
+//
+// See bst.Tree.FirstKey. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) FirstKey() (K, bool) {
+	key, _, ok := t.FirstEntry()
+	return key, ok
+}
+
+// Floor finds the largest key in the tree less than or equal to key.
+//
+// See bst.Tree.Floor. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) Floor(key K) (*bst.Node[K, V, M], bool) {
+	n, found := t.tree.Floor(key)
+	if found && t.IsTombstone(n) {
+		n = t.Predecessor(n)
+		found = !t.IsNil(n)
+	}
+	return n, found
+}
+
+// Higher finds the smallest key in the tree strictly greater than key.
+//
+// See bst.Tree.Higher. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) Higher(key K) (*bst.Node[K, V, M], bool) {
+	n, found := t.tree.Higher(key)
+	if found && t.IsTombstone(n) {
+		n = t.Successor(n)
+		found = !t.IsNil(n)
+	}
+	return n, found
+}
+
+// Height returns the number of edges on the longest path from the root to a leaf, or -1 if the tree is empty.
+//
+// See bst.Tree.Height.
+func (t *Base[K, V, M]) Height() int {
+	return t.tree.Height()
+}
+
+// IsFull returns true if the given node n has both left and right children.
+//
+// See bst.Tree.IsFull.
+func (t *Base[K, V, M]) IsFull(n *bst.Node[K, V, M]) bool {
+	return t.tree.IsFull(n)
+}
+
+// IsInternal returns true if the given node n is an internal node, meaning it has at least one child (left or right).
+//
+// See bst.Tree.IsInternal.
+func (t *Base[K, V, M]) IsInternal(n *bst.Node[K, V, M]) bool {
+	return t.tree.IsInternal(n)
+}
+
+// IsLeaf returns true if the given node n has no children, meaning both its left and right pointers are nil.
+//
+// See bst.Tree.IsLeaf.
+func (t *Base[K, V, M]) IsLeaf(n *bst.Node[K, V, M]) bool {
+	return t.tree.IsLeaf(n)
+}
+
+// IsNil returns true if the given node n is the tree's sentinel nil node.
+//
+// See bst.Tree.IsNil.
+func (t *Base[K, V, M]) IsNil(n *bst.Node[K, V, M]) bool {
+	return t.tree.IsNil(n)
+}
+
+// IsUnary returns true if the given node n has exactly one child (either left or right, but not both).
+//
+// See bst.Tree.IsUnary.
+func (t *Base[K, V, M]) IsUnary(n *bst.Node[K, V, M]) bool {
+	return t.tree.IsUnary(n)
+}
+
+// Key returns the key of the given node n.
+//
+// See bst.Tree.Key.
+func (t *Base[K, V, M]) Key(n *bst.Node[K, V, M]) K {
+	return t.tree.Key(n)
+}
+
+// Keys returns an iterator over the keys of the tree, in ascending order.
+//
+// See bst.Tree.Keys. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range t.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// KthLargest returns the node holding the k-th largest key in the tree, where k is one-based (KthLargest(1) is the node with the maximum key).
+//
+// See bst.Tree.KthLargest. The tree is compacted first, if any nodes have been deleted lazily (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) KthLargest(k int) (*bst.Node[K, V, M], bool) {
+	t.Compact()
+	return t.tree.KthLargest(k)
+}
+
+// KthSmallest returns the node holding the k-th smallest key in the tree, where k is one-based (KthSmallest(1) is the node with the minimum key).
+//
+// See bst.Tree.KthSmallest. The tree is compacted first, if any nodes have been deleted lazily (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) KthSmallest(k int) (*bst.Node[K, V, M], bool) {
+	t.Compact()
+	return t.tree.KthSmallest(k)
+}
+
+// LastEntry returns the largest key in the tree and its value, without exposing the node holding them.
+//
+// See bst.Tree.LastEntry. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) LastEntry() (K, V, bool) {
+	return t.entry(t.Max(t.Root()))
+}
+
+// LastKey returns the largest key in the tree.
+//
+// See bst.Tree.LastKey. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) LastKey() (K, bool) {
+	key, _, ok := t.LastEntry()
+	return key, ok
+}
+
+// entry returns the key and value of n, or false if n is the sentinel nil node.
+func (t *Base[K, V, M]) entry(n *bst.Node[K, V, M]) (K, V, bool) {
+	if t.IsNil(n) {
+		var (
+			zeroK K
+			zeroV V
+		)
+		return zeroK, zeroV, false
+	}
+	return t.Key(n), t.Value(n), true
+}
+
+// Left returns the left child of the given node n.
+//
+// See bst.Tree.Left.
+func (t *Base[K, V, M]) Left(n *bst.Node[K, V, M]) *bst.Node[K, V, M] {
+	return t.tree.Left(n)
+}
+
+// Less reports whether key a is ordered before key b in the tree.
+//
+// See bst.Tree.Less.
+func (t *Base[K, V, M]) Less(a, b K) bool {
+	return t.tree.Less(a, b)
+}
+
+// Lower finds the largest key in the tree strictly less than key.
+//
+// See bst.Tree.Lower. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) Lower(key K) (*bst.Node[K, V, M], bool) {
+	n, found := t.tree.Lower(key)
+	if found && t.IsTombstone(n) {
+		n = t.Predecessor(n)
+		found = !t.IsNil(n)
+	}
+	return n, found
+}
+
+// LowestCommonAncestor returns the deepest node that has both a and b as descendants, where a node is considered a descendant of itself.
+//
+// See bst.Tree.LowestCommonAncestor.
+func (t *Base[K, V, M]) LowestCommonAncestor(a, b *bst.Node[K, V, M]) *bst.Node[K, V, M] {
+	return t.tree.LowestCommonAncestor(a, b)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+//
+// See bst.Tree.MarshalBinary. The tree is compacted first, if any nodes have been deleted lazily (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) MarshalBinary() ([]byte, error) {
+	t.Compact()
+	return t.tree.MarshalBinary()
+}
+
+// MarshalJSON implements json.Marshaler.
+//
+// See bst.Tree.MarshalJSON. The tree is compacted first, if any nodes have been deleted lazily (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) MarshalJSON() ([]byte, error) {
+	t.Compact()
+	return t.tree.MarshalJSON()
+}
+
+// MarshalProto encodes the tree as a gotrees.bst.Tree protobuf message (see tree.proto), so that it can be embedded in existing protobuf and gRPC messages.
+//
+// See bst.Tree.MarshalProto. The tree is compacted first, if any nodes have been deleted lazily (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) MarshalProto(c bst.ProtoCodec[K, V, M]) ([]byte, error) {
+	t.Compact()
+	return t.tree.MarshalProto(c)
+}
+
+// MarshalWith encodes the tree with marshal, typically the Marshal function of a CBOR or MessagePack library.
+//
+// See bst.Tree.MarshalWith. The tree is compacted first, if any nodes have been deleted lazily (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) MarshalWith(marshal bst.MarshalFunc) ([]byte, error) {
+	t.Compact()
+	return t.tree.MarshalWith(marshal)
+}
+
+// Max returns the node with the maximum key in the subtree rooted at n.
+//
+// See bst.Tree.Max. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) Max(n *bst.Node[K, V, M]) *bst.Node[K, V, M] {
+	x := t.tree.Max(n)
+	if len(t.tombstones) == 0 || t.IsNil(x) {
+		return x
+	}
+	for lo := t.tree.Min(n); t.IsTombstone(x); x = t.tree.Predecessor(x) {
+		if x == lo {
+			return t.Sentinel()
+		}
+	}
+	return x
+}
+
+// Metadata returns the metadata associated with the given node n.
+//
+// See bst.Tree.Metadata.
+func (t *Base[K, V, M]) Metadata(n *bst.Node[K, V, M]) M {
+	return t.tree.Metadata(n)
+}
+
+// Min returns the node with the minimum key in the subtree rooted at n.
+//
+// See bst.Tree.Min. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) Min(n *bst.Node[K, V, M]) *bst.Node[K, V, M] {
+	x := t.tree.Min(n)
+	if len(t.tombstones) == 0 || t.IsNil(x) {
+		return x
+	}
+	for hi := t.tree.Max(n); t.IsTombstone(x); x = t.tree.Successor(x) {
+		if x == hi {
+			return t.Sentinel()
+		}
+	}
+	return x
+}
+
+// Nearest finds the node whose key is closest to key, as measured by distance.
+//
+// See bst.Tree.Nearest. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) Nearest(key K, distance func(a, b K) int) (*bst.Node[K, V, M], bool) {
+	if len(t.tombstones) == 0 {
+		return t.tree.Nearest(key, distance)
+	}
+	floor, hasFloor := t.Floor(key)
+	ceiling, hasCeiling := t.Ceiling(key)
+	switch {
+	case hasFloor && hasCeiling:
+		if distance(t.Key(ceiling), key) < distance(t.Key(floor), key) {
+			return ceiling, true
+		}
+		return floor, true
+	case hasFloor:
+		return floor, true
+	case hasCeiling:
+		return ceiling, true
+	}
+	return t.Sentinel(), false
+}
+
+// OnChange registers a function notified after each successful change to the tree, so that applications can mirror the tree (e.g., into a cache) without funneling every change through a wrapper.
+//
+// See bst.Tree.OnChange.
+func (t *Base[K, V, M]) OnChange(f bst.ChangeFunc[K, V]) {
+	t.tree.OnChange(f)
+}
+
+// Parent returns the parent of the given node n.
+//
+// See bst.Tree.Parent.
+func (t *Base[K, V, M]) Parent(n *bst.Node[K, V, M]) *bst.Node[K, V, M] {
+	return t.tree.Parent(n)
+}
+
+// Path returns the nodes on the path from the root to n, inclusive of both.
+//
+// See bst.Tree.Path.
+func (t *Base[K, V, M]) Path(n *bst.Node[K, V, M]) []*bst.Node[K, V, M] {
+	return t.tree.Path(n)
+}
+
+// Predecessor returns the in-order predecessor of the given node n.
+//
+// See bst.Tree.Predecessor. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) Predecessor(n *bst.Node[K, V, M]) *bst.Node[K, V, M] {
+	n = t.tree.Predecessor(n)
+	for t.IsTombstone(n) {
+		n = t.tree.Predecessor(n)
+	}
+	return n
+}
+
+// Rank returns the number of nodes in the tree with a key strictly less than key.
+//
+// See bst.Tree.Rank. The tree is compacted first, if any nodes have been deleted lazily (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) Rank(key K) int {
+	t.Compact()
+	return t.tree.Rank(key)
+}
+
+// Render returns a visual representation of the binary search tree (BST), drawn according to opts.
+//
+// See bst.Tree.Render.
+func (t *Base[K, V, M]) Render(opts bst.RenderOptions) string {
+	return t.tree.Render(opts)
+}
+
+// Right returns the right child of the given node n.
+//
+// See bst.Tree.Right.
+func (t *Base[K, V, M]) Right(n *bst.Node[K, V, M]) *bst.Node[K, V, M] {
+	return t.tree.Right(n)
+}
+
+// Root returns the root node of the tree.
+//
+// See bst.Tree.Root.
+func (t *Base[K, V, M]) Root() *bst.Node[K, V, M] {
+	return t.tree.Root()
+}
+
+// Rotations returns the number of rotations performed on the tree since it was created, by Tree.RotateLeft and Tree.RotateRight, whether directly or while rebalancing the tree.
+//
+// See bst.Tree.Rotations.
+func (t *Base[K, V, M]) Rotations() uint64 {
+	return t.tree.Rotations()
+}
+
+// Search looks for a node with the given key in the tree.
+//
+// See bst.Tree.Search. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) Search(key K) (*bst.Node[K, V, M], bool) {
+	n, found := t.tree.Search(key)
+	for found && t.IsTombstone(n) {
+		// in multiset mode, a later node may hold an equal key
+		n = t.tree.Successor(n)
+		found = !t.IsNil(n) && !t.tree.Less(key, t.Key(n))
+	}
+	return n, found
+}
+
+// Select returns the node with the given zero-based rank i, that is, the (i+1)-th smallest key in the tree.
+//
+// See bst.Tree.Select. The tree is compacted first, if any nodes have been deleted lazily (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) Select(i int) (*bst.Node[K, V, M], bool) {
+	t.Compact()
+	return t.tree.Select(i)
+}
+
+// Sentinel returns the sentinel nil node.
+//
+// See bst.Tree.Sentinel.
+func (t *Base[K, V, M]) Sentinel() *bst.Node[K, V, M] {
+	return t.tree.Sentinel()
+}
+
+// SetAugmentFunc registers f to maintain user-defined augmented data, such as subtree sums, maximum interval endpoints or other aggregates, typically stored in node metadata.
+//
+// See bst.Tree.SetAugmentFunc.
+func (t *Base[K, V, M]) SetAugmentFunc(f bst.AugmentFunc[K, V, M]) {
+	t.tree.SetAugmentFunc(f)
+}
+
+// SetNodeFormatter registers f to format each node when drawing the tree with Tree.String and Tree.WriteSVG, instead of the default layout (see Node.String).
+//
+// See bst.Tree.SetNodeFormatter.
+func (t *Base[K, V, M]) SetNodeFormatter(f bst.NodeFormatter[K, V, M]) {
+	t.tree.SetNodeFormatter(f)
+}
+
+// SetValue updates the value of the given node n.
+//
+// See bst.Tree.SetValue.
+func (t *Base[K, V, M]) SetValue(n *bst.Node[K, V, M], value V) {
+	t.tree.SetValue(n, value)
+}
+
+// Sibling returns the sibling of the given node n.
+//
+// See bst.Tree.Sibling.
+func (t *Base[K, V, M]) Sibling(n *bst.Node[K, V, M]) *bst.Node[K, V, M] {
+	return t.tree.Sibling(n)
+}
+
+// Size returns the total number of nodes in the tree.
+//
+// See bst.Tree.Size. Nodes deleted lazily are not counted (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) Size() int {
+	return t.tree.Size() - len(t.tombstones)
+}
+
+// SliceByRank returns the nodes with zero-based ranks in the half-open interval [i, j), in ascending key order.
+//
+// See bst.Tree.SliceByRank. The tree is compacted first, if any nodes have been deleted lazily (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) SliceByRank(i, j int) []*bst.Node[K, V, M] {
+	t.Compact()
+	return t.tree.SliceByRank(i, j)
+}
+
+// Snapshot returns an immutable view of the keys and values in the tree, as they are now.
+//
+// See bst.Tree.Snapshot. The tree is compacted first, if any nodes have been deleted lazily (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) Snapshot() *bst.Snapshot[K, V] {
+	t.Compact()
+	return t.tree.Snapshot()
+}
+
+// SnapshotTo writes a full snapshot of the tree to w: its version (see Tree.Version), followed by the binary encoding of the tree (see Tree.MarshalBinary), as an encoding/gob stream.
+//
+// See bst.Tree.SnapshotTo. The tree is compacted first, if any nodes have been deleted lazily (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) SnapshotTo(w io.Writer) error {
+	t.Compact()
+	return t.tree.SnapshotTo(w)
+}
+
+// String returns a visual representation of the binary search tree (BST).
+//
+// See bst.Tree.String.
+func (t *Base[K, V, M]) String() string {
+	return t.tree.String()
+}
+
+// SubtreeSize returns the number of nodes in the subtree rooted at n, including n itself.
+//
+// See bst.Tree.SubtreeSize.
+func (t *Base[K, V, M]) SubtreeSize(n *bst.Node[K, V, M]) int {
+	return t.tree.SubtreeSize(n)
+}
+
+// Successor returns the in-order successor of the given node n.
+//
+// See bst.Tree.Successor. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) Successor(n *bst.Node[K, V, M]) *bst.Node[K, V, M] {
+	n = t.tree.Successor(n)
+	for t.IsTombstone(n) {
+		n = t.tree.Successor(n)
+	}
+	return n
+}
+
+// TraverseInOrder performs an in-order traversal of the tree starting from node n.
+//
+// See bst.Tree.TraverseInOrder. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) TraverseInOrder(n *bst.Node[K, V, M], f bst.TraversalFunc[K, V, M]) bool {
+	if len(t.tombstones) == 0 {
+		return t.tree.TraverseInOrder(n, f)
+	}
+	return t.tree.TraverseInOrder(n, func(n *bst.Node[K, V, M]) bool {
+		return t.IsTombstone(n) || f(n)
+	})
+}
+
+// TraverseInOrderErr performs an in-order traversal of the tree starting from node n, stopping at the first error returned by f.
+//
+// See bst.Tree.TraverseInOrderErr. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) TraverseInOrderErr(n *bst.Node[K, V, M], f bst.TraversalErrFunc[K, V, M]) error {
+	if len(t.tombstones) == 0 {
+		return t.tree.TraverseInOrderErr(n, f)
+	}
+	return t.tree.TraverseInOrderErr(n, func(n *bst.Node[K, V, M]) error {
+		if t.IsTombstone(n) {
+			return nil
+		}
+		return f(n)
+	})
+}
+
+// Value returns the value associated with the given node n.
+//
+// See bst.Tree.Value.
+func (t *Base[K, V, M]) Value(n *bst.Node[K, V, M]) V {
+	return t.tree.Value(n)
+}
+
+// Values returns an iterator over the values of the tree, in ascending key order.
+//
+// See bst.Tree.Values. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, value := range t.All() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Version returns the version of the tree, which is incremented by every change notified to the function registered with Tree.OnChange, starting from 0 for a new tree.
+//
+// See bst.Tree.Version.
+func (t *Base[K, V, M]) Version() uint64 {
+	return t.tree.Version()
+}
@@ -0,0 +1,79 @@
+package rbtree
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"math/bits"
+	"slices"
+)
+
+// LoadSorted replaces the contents of the tree with the given keys and values, building a valid
+// Red-Black Tree in O(n) time on up to parallelism goroutines, or runtime.GOMAXPROCS(0) goroutines
+// if parallelism is 0, rather than inserting the keys one by one.
+//
+// The tree is built perfectly balanced, with the nodes of the deepest level colored red unless that
+// level is full, and every other node black, so no rotation or recoloring takes place (see
+// bst.Tree.LoadSortedFunc). This suits building very large trees at startup, from sorted keys.
+//
+// Parameters:
+//   - keys: The keys, in ascending order, without duplicates unless duplicate keys are enabled
+//     (see bst.WithDuplicateKeys). keys is not modified.
+//   - values: The values of the keys, or nil for zero values.
+//   - parallelism: The maximum number of goroutines building the tree, or 0 for runtime.GOMAXPROCS(0).
+//
+// Returns:
+//   - nil if the tree was loaded.
+//   - An error if the keys are out of order, or values and keys differ in length. The tree is then
+//     left unchanged.
+//
+// Example Usage:
+//
+//	keys, values := loadSnapshot() // sorted by key
+//	if err := tree.LoadSorted(keys, values, 0); err != nil {
+//		return err
+//	}
+func (t *Base[K, V, M]) LoadSorted(keys []K, values []V, parallelism int) error {
+	// the deepest level is at depth ⌊log2(n)⌋, and is full if n+1 is a power of two
+	n := uint(len(keys))
+	deepest := bits.Len(n) - 1
+	full := n&(n+1) == 0
+	err := t.tree.LoadSortedFunc(keys, values, parallelism, func(node *bst.Node[K, V, M], depth int) {
+		color := Black
+		if depth == deepest && !full {
+			color = Red
+		}
+		t.tree.MustSetMetadata(node, t.tree.Metadata(node).WithNodeColor(color))
+	})
+	if err != nil {
+		return err
+	}
+	clear(t.tombstones)
+	return nil
+}
+
+// FromSlice creates a Red-Black Tree holding the given items as values, keyed by keyFn, sorting the
+// items and bulk-building the tree with Tree.LoadSorted, in O(n log n) time.
+//
+// See bst.FromSlice.
+//
+// Example Usage:
+//
+//	tree := rbtree.FromSlice(func(a, b string) bool { return a < b }, people,
+//		func(p Person) string { return p.Name })
+func FromSlice[K, V any](less bst.LessFunc[K], items []V, keyFn func(item V) K, opts ...bst.Option) *Tree[K, V] {
+	t := New[K, V](less, opts...)
+	keys, values := bst.SortItems(t.Less, t.Duplicates(), items, keyFn)
+	if err := t.LoadSorted(keys, values, 0); err != nil {
+		panic(err) // unreachable: SortItems returns sorted keys
+	}
+	return t
+}
+
+// ToSlice returns the values of the tree in ascending key order, the inverse of FromSlice.
+//
+// See bst.ToSlice. Nodes deleted lazily are skipped (see Tree.SetLazyDeletion).
+func ToSlice[K, V any, M ColorMetadata[M]](t *Base[K, V, M]) []V {
+	if t.Size() == 0 {
+		return nil
+	}
+	return slices.AppendSeq(make([]V, 0, t.Size()), t.Values())
+}
@@ -0,0 +1,63 @@
+package rbtree
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestTree_LoadSorted(t *testing.T) {
+	for _, size := range []int{0, 1, 2, 3, 4, 7, 15, 16, 1000, 100000} {
+		tree := New[int, int](func(a, b int) bool { return a < b })
+		tree.SetLazyDeletion(1)
+		n, _ := tree.Insert(-1, -1)
+		tree.Delete(n) // a tombstone, replaced by the loaded keys
+		keys, values := make([]int, size), make([]int, size)
+		for i := range keys {
+			keys[i], values[i] = i*2, i
+		}
+
+		require.NoError(t, tree.LoadSorted(keys, values, 4))
+		require.NoError(t, tree.IsTreeValid(), "expected valid Red-Black Tree with %d nodes", size)
+		assert.Equal(t, size, tree.Size())
+		assert.Equal(t, 0, tree.Tombstones())
+		assert.Equal(t, uint64(0), tree.Rotations(), "expected no rotation")
+
+		// the loaded tree is balanced as usual by later insertions and deletions
+		for i := 0; i < size; i += 3 {
+			n, _ := tree.Search(i * 2)
+			tree.Delete(n)
+			tree.Insert(i*2+1, i)
+		}
+		require.NoError(t, tree.IsTreeValid(), "expected valid Red-Black Tree with %d nodes after updates", size)
+	}
+}
+
+func TestTree_LoadSorted_errors(t *testing.T) {
+	tree := New[int, int](func(a, b int) bool { return a < b })
+	tree.Insert(1, 1)
+	assert.Error(t, tree.LoadSorted([]int{2, 1}, nil, 0))
+	assert.Equal(t, 1, tree.Size(), "expected the tree to be unchanged")
+
+	multiset := New[int, int](func(a, b int) bool { return a < b }, bst.WithDuplicateKeys())
+	require.NoError(t, multiset.LoadSorted([]int{1, 1, 1, 2}, nil, 0))
+	require.NoError(t, multiset.IsTreeValid())
+	assert.Equal(t, 4, multiset.Size())
+}
+
+func TestFromSlice(t *testing.T) {
+	words := []string{"pear", "fig", "apple", "kiwi", "plum", "date"}
+	tree := FromSlice(func(a, b int) bool { return a < b }, words, func(w string) int { return len(w) })
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, []string{"fig", "date", "apple"}, ToSlice(tree), "expected the last word of each length")
+
+	tree.SetLazyDeletion(1)
+	n, _ := tree.Search(4)
+	tree.Delete(n)
+	assert.Equal(t, []string{"fig", "apple"}, ToSlice(tree), "expected tombstones to be skipped")
+
+	tree = FromSlice(func(a, b int) bool { return a < b }, words, func(w string) int { return len(w) }, bst.WithDuplicateKeys())
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, []string{"fig", "pear", "kiwi", "plum", "date", "apple"}, ToSlice(tree))
+}
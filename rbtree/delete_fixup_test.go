@@ -25,7 +25,7 @@ func TestDeleteFixupCases(t *testing.T) {
 			n, found := tree.Search(i)
 			assert.True(t, found)
 
-			deleted := tree.Delete(n)
+			_, deleted := tree.Delete(n)
 			assert.True(t, deleted)
 
 			// Tree should remain valid after each deletion
@@ -57,7 +57,7 @@ func TestDeleteFixupComprehensive(t *testing.T) {
 				key := ((i * 3) + seed) % 500
 				n, found := tree.Search(key)
 				if found {
-					deleted := tree.Delete(n)
+					_, deleted := tree.Delete(n)
 					assert.True(t, deleted)
 
 					// Tree should remain valid after each deletion
@@ -102,7 +102,7 @@ func TestIsTreeValidRedRoot(t *testing.T) {
 	assert.NoError(t, tree.IsTreeValid())
 
 	// Directly set the root node to red, violating RB property #2
-	tree.Tree.MustSetMetadata(tree.Root(), Red)
+	tree.tree.MustSetMetadata(tree.Root(), Red)
 
 	// Now tree validation should fail
 	err := tree.IsTreeValid()
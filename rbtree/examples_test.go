@@ -2,7 +2,9 @@ package rbtree_test
 
 import (
 	"fmt"
+	"github.com/mikenye/gotrees/bst"
 	"github.com/mikenye/gotrees/rbtree"
+	"strconv"
 )
 
 func ExampleTree_Insert() {
@@ -68,12 +70,14 @@ func ExampleTree_Delete() {
 	tree.Delete(node3)
 	tree.Delete(node5)
 	tree.Delete(node7)
-	tree.Delete(node9)
+	value, _ := tree.Delete(node9)
+	fmt.Println("Deleted:", value)
 
 	// show the tree
 	fmt.Printf("Red-Black Tree:\n%s", tree)
 
 	// Output:
+	// Deleted: nine
 	// Red-Black Tree:
 	//       ╭── 0: zero [⬛]
 	//  ╭── 2: two [🟥]
@@ -124,3 +128,129 @@ func ExampleTree_Floor_and_Ceiling() {
 	// Key in range [3,7]: 4
 	// Key in range [3,7]: 6
 }
+
+func ExampleTree_Lower_and_Higher() {
+	// Create a red-black tree with even numbers
+	tree := rbtree.New[int, string](func(a, b int) bool {
+		return a < b
+	})
+	for i := 2; i <= 10; i += 2 {
+		tree.Insert(i, strconv.Itoa(i))
+	}
+
+	// Floor and Ceiling include the key itself, Lower and Higher exclude it
+	floor, _ := tree.Floor(6)
+	lower, _ := tree.Lower(6)
+	ceiling, _ := tree.Ceiling(6)
+	higher, _ := tree.Higher(6)
+	fmt.Printf("Floor(6) = %d, Lower(6) = %d\n", tree.Key(floor), tree.Key(lower))
+	fmt.Printf("Ceiling(6) = %d, Higher(6) = %d\n", tree.Key(ceiling), tree.Key(higher))
+
+	// Using Higher to iterate over the open interval (4, 10), without adjusting the bounds
+	for node, found := tree.Higher(4); found && tree.Key(node) < 10; node = tree.Successor(node) {
+		fmt.Printf("Key in range (4,10): %d\n", tree.Key(node))
+	}
+
+	// Output:
+	// Floor(6) = 6, Lower(6) = 4
+	// Ceiling(6) = 6, Higher(6) = 8
+	// Key in range (4,10): 6
+	// Key in range (4,10): 8
+}
+
+func ExampleTree_SubMap() {
+	// Create a red-black tree of hourly readings
+	tree := rbtree.New[int, string](func(a, b int) bool {
+		return a < b
+	})
+	for hour := 0; hour < 24; hour += 3 {
+		tree.Insert(hour, "reading "+strconv.Itoa(hour))
+	}
+
+	// A view of the working hours [9, 17), without copying the tree
+	working := tree.SubMap(9, 17)
+	working.Ascend(func(n *bst.Node[int, string, rbtree.Color]) bool {
+		fmt.Println(tree.Key(n), tree.Value(n))
+		return true
+	})
+
+	// Writes through the view are range checked
+	if _, _, err := working.Insert(20, "late reading"); err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// 9 reading 9
+	// 12 reading 12
+	// 15 reading 15
+	// key out of range: 20 not in [9, 17)
+}
+
+func ExampleTree_Select() {
+
+	// create the tree with integer keys and string values
+	tree := rbtree.New[int, string](func(a, b int) bool {
+		return a < b
+	})
+
+	// insert some nodes in the tree
+	tree.Insert(40, "forty")
+	tree.Insert(10, "ten")
+	tree.Insert(30, "thirty")
+	tree.Insert(20, "twenty")
+	tree.Insert(50, "fifty")
+
+	// find the median
+	median, _ := tree.Select(tree.Size() / 2)
+	fmt.Printf("Median: %d: %s\n", tree.Key(median), tree.Value(median))
+
+	// find the rank of a key
+	fmt.Printf("Rank of 40: %d\n", tree.Rank(40))
+
+	// Output:
+	// Median: 30: thirty
+	// Rank of 40: 3
+}
+
+func ExampleTree_SetTracer() {
+	tree := rbtree.New[int, string](func(a, b int) bool {
+		return a < b
+	})
+
+	// print each rotation and recoloring; a step also holds the tree before and after it,
+	// and encodes to JSON, e.g. to generate an animation
+	tree.SetTracer(func(step rbtree.Step[int]) {
+		fmt.Println(step)
+	})
+
+	for i := 1; i <= 4; i++ {
+		tree.Insert(i, strconv.Itoa(i))
+	}
+
+	// Output:
+	// insert: recolor 1 black
+	// insert case 3 (mirrored): recolor 2 black
+	// insert case 3 (mirrored): recolor 1 red
+	// insert case 3 (mirrored): rotate-left 1
+	// insert case 1 (mirrored): recolor 3 black
+	// insert case 1 (mirrored): recolor 1 black
+	// insert case 1 (mirrored): recolor 2 red
+	// insert: recolor 2 black
+}
+
+func ExampleNewOrdered() {
+	// keys of an ordered type need no comparison function
+	tree := rbtree.NewOrdered[string, int]()
+	tree.Insert("banana", 2)
+	tree.Insert("apple", 1)
+	tree.Insert("cherry", 3)
+
+	for n := tree.Min(tree.Root()); !tree.IsNil(n); n = tree.Successor(n) {
+		fmt.Println(tree.Key(n), tree.Value(n))
+	}
+
+	// Output:
+	// apple 1
+	// banana 2
+	// cherry 3
+}
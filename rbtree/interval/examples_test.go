@@ -0,0 +1,57 @@
+package interval_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/rbtree/interval"
+)
+
+func ExampleTree_AllOverlaps() {
+
+	// create a calendar of meetings, by hour
+	calendar := interval.New[int, string]()
+	calendar.Insert(interval.Interval[int]{Lo: 9, Hi: 10}, "standup")
+	calendar.Insert(interval.Interval[int]{Lo: 11, Hi: 13}, "review")
+	calendar.Insert(interval.Interval[int]{Lo: 12, Hi: 14}, "lunch")
+	calendar.Insert(interval.Interval[int]{Lo: 15, Hi: 17}, "planning")
+
+	// find the meetings clashing with a new meeting from 10 to 12
+	calendar.AllOverlaps(interval.Interval[int]{Lo: 10, Hi: 12}, func(iv interval.Interval[int], name string) bool {
+		fmt.Println("clashes with", name, iv)
+		return true
+	})
+
+	// find the meetings in progress at 12
+	calendar.Stab(12, func(iv interval.Interval[int], name string) bool {
+		fmt.Println("at 12:", name)
+		return true
+	})
+
+	// Output:
+	// clashes with review [11, 13)
+	// at 12: review
+	// at 12: lunch
+}
+
+func ExampleScheduler_FindFirstFreeSlot() {
+
+	// book a meeting room, by hour
+	room := interval.NewScheduler[int, string]()
+	room.ReserveIfFree(9, 10, "standup")
+	room.ReserveIfFree(11, 13, "review")
+	fmt.Println(room.ReserveIfFree(12, 14, "lunch"))
+
+	// find the first free two-hour slot from 9 onwards, and book it
+	slot := room.FindFirstFreeSlot(2, 9)
+	room.ReserveIfFree(slot.Lo, slot.Hi, "planning")
+
+	room.Ascend(func(iv interval.Interval[int], name string) bool {
+		fmt.Println(iv, name)
+		return true
+	})
+
+	// Output:
+	// false
+	// [9, 10) standup
+	// [11, 13) review
+	// [13, 15) planning
+}
@@ -0,0 +1,264 @@
+// Package interval provides an interval tree: a Red-Black Tree keyed by half-open intervals
+// [Lo, Hi), answering overlap and stabbing queries in O(log n + m) time for m results.
+//
+// The tree extends rbtree.Tree, ordering intervals by their lower bound (then upper bound).
+// Each node is augmented with the maximum upper bound in its subtree, which is maintained
+// through insertions, deletions and the rotations performed by the red-black fixups
+// (see bst.Tree.SetAugmentFunc). Subtrees ending before a query begins are skipped.
+//
+// Queries:
+//   - Tree.AnyOverlap finds one interval overlapping a query interval, in O(log n) time.
+//   - Tree.AllOverlaps visits every interval overlapping a query interval.
+//   - Tree.Stab visits every interval containing a point.
+//
+// Scheduler builds on the tree to book non-overlapping reservations of a single resource,
+// such as a meeting room, and to find the first free slot of a given duration.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/rbtree/interval"
+//
+//	tree := interval.New[int, string]()
+//	tree.Insert(interval.Interval[int]{Lo: 9, Hi: 12}, "standup")
+//	tree.Insert(interval.Interval[int]{Lo: 11, Hi: 13}, "review")
+//	tree.Stab(11, func(iv interval.Interval[int], name string) bool {
+//		fmt.Println(name) // standup, review
+//		return true
+//	})
+//
+// # Limitations
+//
+// Intervals are unique: inserting an existing interval updates its value. The tree is not safe
+// for concurrent use.
+package interval
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/mikenye/gotrees/bst"
+	"github.com/mikenye/gotrees/rbtree"
+)
+
+// Interval is a half-open interval [Lo, Hi), containing every point p with Lo <= p < Hi.
+//
+// An interval with Lo >= Hi is empty, and overlaps nothing. Empty intervals can be used as queries,
+// but cannot be inserted into a Tree.
+type Interval[T cmp.Ordered] struct {
+	Lo, Hi T
+}
+
+// Overlaps returns true if i and o have at least one point in common.
+func (i Interval[T]) Overlaps(o Interval[T]) bool {
+	return !i.IsEmpty() && !o.IsEmpty() && i.Lo < o.Hi && o.Lo < i.Hi
+}
+
+// IsEmpty returns true if i contains no points.
+func (i Interval[T]) IsEmpty() bool {
+	return !(i.Lo < i.Hi)
+}
+
+// Contains returns true if point p lies within i.
+func (i Interval[T]) Contains(p T) bool {
+	return i.Lo <= p && p < i.Hi
+}
+
+// String returns the interval as "[Lo, Hi)".
+func (i Interval[T]) String() string {
+	return fmt.Sprintf("[%v, %v)", i.Lo, i.Hi)
+}
+
+// less orders intervals by lower bound, then by upper bound.
+func less[T cmp.Ordered](a, b Interval[T]) bool {
+	if a.Lo != b.Lo {
+		return a.Lo < b.Lo
+	}
+	return a.Hi < b.Hi
+}
+
+// entry is the value stored in each node of the underlying tree: the user's value,
+// and the maximum upper bound of the intervals in the node's subtree.
+type entry[T cmp.Ordered, V any] struct {
+	value V
+	max   T
+}
+
+// node is a node of the underlying tree.
+type node[T cmp.Ordered, V any] = bst.Node[Interval[T], entry[T, V], rbtree.Color]
+
+// Tree represents an interval tree mapping intervals of type Interval[T] to values of type V.
+//
+// Trees must be created with New.
+type Tree[T cmp.Ordered, V any] struct {
+	tree *rbtree.Tree[Interval[T], entry[T, V]] // Underlying Red-Black Tree, augmented with subtree maximums
+}
+
+// New creates and returns a new empty interval tree.
+//
+// Returns:
+//   - A pointer to a newly created Tree[T, V] instance.
+func New[T cmp.Ordered, V any]() *Tree[T, V] {
+	t := &Tree[T, V]{tree: rbtree.New[Interval[T], entry[T, V]](less[T])}
+	t.tree.SetAugmentFunc(t.augment)
+	return t
+}
+
+// augment recomputes the maximum upper bound of the subtree rooted at n.
+func (t *Tree[T, V]) augment(n *node[T, V]) {
+	e := t.tree.Value(n)
+	e.max = t.tree.Key(n).Hi
+	for _, c := range [2]*node[T, V]{t.tree.Left(n), t.tree.Right(n)} {
+		if !t.tree.IsNil(c) {
+			e.max = max(e.max, t.tree.Value(c).max)
+		}
+	}
+	t.tree.SetValue(n, e)
+}
+
+// Size returns the number of intervals in the tree.
+//
+// This is an O(1) operation.
+func (t *Tree[T, V]) Size() int {
+	return t.tree.Size()
+}
+
+// Insert inserts the given interval and value into the tree.
+//
+// If the interval already exists, its value is updated.
+// Panics if iv is empty.
+//
+// Returns:
+//   - true if a new interval was inserted.
+//   - false if the interval existed and its value was updated.
+func (t *Tree[T, V]) Insert(iv Interval[T], value V) bool {
+	if iv.IsEmpty() {
+		panic(fmt.Sprintf("interval: invalid interval %v", iv))
+	}
+	_, inserted := t.tree.Insert(iv, entry[T, V]{value: value})
+	return inserted
+}
+
+// Search returns the value associated with interval iv.
+//
+// Returns:
+//   - (value, true) if the interval exists in the tree.
+//   - (zero value, false) if the interval is not found.
+func (t *Tree[T, V]) Search(iv Interval[T]) (V, bool) {
+	if n, found := t.tree.Search(iv); found {
+		return t.tree.Value(n).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete removes interval iv from the tree.
+//
+// Returns:
+//   - true if the interval was found and removed.
+//   - false if the interval was not found.
+func (t *Tree[T, V]) Delete(iv Interval[T]) bool {
+	n, found := t.tree.Search(iv)
+	if !found {
+		return false
+	}
+	_, deleted := t.tree.Delete(n)
+	return deleted
+}
+
+// AnyOverlap returns an interval in the tree overlapping query, and its value, in O(log n) time.
+//
+// Returns:
+//   - (interval, value, true) if an overlapping interval exists.
+//   - (zero interval, zero value, false) otherwise.
+func (t *Tree[T, V]) AnyOverlap(query Interval[T]) (Interval[T], V, bool) {
+	n := t.tree.Root()
+	for !query.IsEmpty() && !t.tree.IsNil(n) {
+		if t.tree.Key(n).Overlaps(query) {
+			return t.tree.Key(n), t.tree.Value(n).value, true
+		}
+
+		// if any interval on the left ends after the query begins, but none overlaps it,
+		// then that interval (and every interval on the right) begins after the query ends
+		if l := t.tree.Left(n); !t.tree.IsNil(l) && t.tree.Value(l).max > query.Lo {
+			n = l
+		} else {
+			n = t.tree.Right(n)
+		}
+	}
+	var zero V
+	return Interval[T]{}, zero, false
+}
+
+// AllOverlaps calls f for each interval in the tree overlapping query and its value,
+// in ascending order, until f returns false.
+//
+// The tree must not be modified during iteration.
+func (t *Tree[T, V]) AllOverlaps(query Interval[T], f func(iv Interval[T], value V) bool) {
+	t.visit(t.tree.Root(), query.Lo, query.Hi, query.Overlaps, f)
+}
+
+// Stab calls f for each interval in the tree containing point p and its value,
+// in ascending order, until f returns false.
+//
+// The tree must not be modified during iteration.
+func (t *Tree[T, V]) Stab(p T, f func(iv Interval[T], value V) bool) {
+	t.visit(t.tree.Root(), p, p, func(iv Interval[T]) bool { return iv.Contains(p) }, f)
+}
+
+// visit calls f, in order, for each interval matching match in the subtree rooted at n.
+// Subtrees whose intervals all end at or before lo, and right subtrees of intervals beginning
+// after hi, are skipped. It returns false if iteration was stopped by f.
+func (t *Tree[T, V]) visit(n *node[T, V], lo, hi T, match func(iv Interval[T]) bool, f func(iv Interval[T], value V) bool) bool {
+	if t.tree.IsNil(n) || t.tree.Value(n).max <= lo {
+		return true
+	}
+	if !t.visit(t.tree.Left(n), lo, hi, match, f) {
+		return false
+	}
+	iv := t.tree.Key(n)
+	if match(iv) && !f(iv, t.tree.Value(n).value) {
+		return false
+	}
+	if hi < iv.Lo {
+		return true
+	}
+	return t.visit(t.tree.Right(n), lo, hi, match, f)
+}
+
+// Ascend calls f for each interval in the tree and its value, in ascending order, until f returns false.
+//
+// The tree must not be modified during iteration.
+func (t *Tree[T, V]) Ascend(f func(iv Interval[T], value V) bool) {
+	if t.Size() == 0 {
+		return
+	}
+	t.tree.TraverseInOrder(t.tree.Root(), func(n *node[T, V]) bool {
+		return f(t.tree.Key(n), t.tree.Value(n).value)
+	})
+}
+
+// IsTreeValid checks whether the underlying tree is a valid Red-Black Tree (see rbtree.Tree.IsTreeValid),
+// and whether every node holds the maximum upper bound of its subtree.
+//
+// Returns:
+//   - nil if the tree is valid.
+//   - An error describing the first violation found otherwise.
+func (t *Tree[T, V]) IsTreeValid() error {
+	if err := t.tree.IsTreeValid(); err != nil {
+		return err
+	}
+	if t.Size() == 0 {
+		return nil
+	}
+	return t.tree.TraverseInOrderErr(t.tree.Root(), func(n *node[T, V]) error {
+		m := t.tree.Key(n).Hi
+		for _, c := range [2]*node[T, V]{t.tree.Left(n), t.tree.Right(n)} {
+			if !t.tree.IsNil(c) {
+				m = max(m, t.tree.Value(c).max)
+			}
+		}
+		if got := t.tree.Value(n).max; got != m {
+			return fmt.Errorf("node %v has subtree maximum %v, expected %v", t.tree.Key(n), got, m)
+		}
+		return nil
+	})
+}
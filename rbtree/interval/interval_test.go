@@ -0,0 +1,139 @@
+package interval
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func randomInterval(rng *rand.Rand) Interval[int] {
+	lo := rng.Intn(1000) - 500
+	return Interval[int]{lo, lo + 1 + rng.Intn(50)}
+}
+
+func TestTree_InsertSearchDelete(t *testing.T) {
+	tree := New[int, int]()
+	require.NoError(t, tree.IsTreeValid())
+	tree.Ascend(func(Interval[int], int) bool {
+		t.Error("expected empty tree to have no intervals")
+		return true
+	})
+
+	rng := rand.New(rand.NewSource(1))
+	expected := make(map[Interval[int]]int)
+
+	for i := 0; i < 10000; i++ {
+		iv := randomInterval(rng)
+		_, exists := expected[iv]
+		if rng.Intn(3) == 0 {
+			assert.Equal(t, exists, tree.Delete(iv), "unexpected result deleting %v", iv)
+			delete(expected, iv)
+		} else {
+			assert.Equal(t, !exists, tree.Insert(iv, i), "unexpected result inserting %v", iv)
+			expected[iv] = i
+		}
+		if i%500 == 0 {
+			require.NoError(t, tree.IsTreeValid())
+		}
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, len(expected), tree.Size())
+
+	for iv, value := range expected {
+		v, found := tree.Search(iv)
+		assert.True(t, found)
+		assert.Equal(t, value, v)
+	}
+	_, found := tree.Search(Interval[int]{1000, 1001})
+	assert.False(t, found)
+
+	var prev *Interval[int]
+	tree.Ascend(func(iv Interval[int], _ int) bool {
+		if prev != nil {
+			assert.True(t, less(*prev, iv), "intervals out of order")
+		}
+		prev = &iv
+		return true
+	})
+
+	assert.Panics(t, func() { tree.Insert(Interval[int]{2, 1}, 0) })
+	assert.Panics(t, func() { tree.Insert(Interval[int]{2, 2}, 0) })
+}
+
+func TestTree_queries(t *testing.T) {
+	tree := New[int, struct{}]()
+	rng := rand.New(rand.NewSource(1))
+	var intervals []Interval[int]
+	for i := 0; i < 2000; i++ {
+		iv := randomInterval(rng)
+		if tree.Insert(iv, struct{}{}) {
+			intervals = append(intervals, iv)
+		}
+	}
+	slices.SortFunc(intervals, func(a, b Interval[int]) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		}
+		return 0
+	})
+
+	for i := 0; i < 300; i++ {
+		query := randomInterval(rng)
+		var expected, got []Interval[int]
+		for _, iv := range intervals {
+			if iv.Overlaps(query) {
+				expected = append(expected, iv)
+			}
+		}
+		tree.AllOverlaps(query, func(iv Interval[int], _ struct{}) bool {
+			got = append(got, iv)
+			return true
+		})
+		assert.Equal(t, expected, got, "unexpected overlaps of %v", query)
+
+		iv, _, found := tree.AnyOverlap(query)
+		assert.Equal(t, len(expected) > 0, found, "unexpected result for any overlap of %v", query)
+		if found {
+			assert.True(t, iv.Overlaps(query))
+		}
+
+		p := query.Lo
+		expected, got = nil, nil
+		for _, iv := range intervals {
+			if iv.Contains(p) {
+				expected = append(expected, iv)
+			}
+		}
+		tree.Stab(p, func(iv Interval[int], _ struct{}) bool {
+			got = append(got, iv)
+			return true
+		})
+		assert.Equal(t, expected, got, "unexpected intervals containing %d", p)
+	}
+
+	_, _, found := tree.AnyOverlap(Interval[int]{0, 0})
+	assert.False(t, found, "expected empty query to overlap nothing")
+
+	count := 0
+	tree.AllOverlaps(Interval[int]{-1000, 1000}, func(Interval[int], struct{}) bool {
+		count++
+		return false
+	})
+	assert.Equal(t, 1, count, "expected iteration to stop early")
+}
+
+func TestInterval(t *testing.T) {
+	a := Interval[int]{1, 3}
+	assert.True(t, a.Overlaps(Interval[int]{2, 5}))
+	assert.False(t, a.Overlaps(Interval[int]{3, 5}), "half-open intervals sharing an endpoint do not overlap")
+	assert.False(t, a.Overlaps(Interval[int]{2, 2}), "empty intervals overlap nothing")
+	assert.True(t, Interval[int]{2, 2}.IsEmpty())
+	assert.True(t, a.Contains(1))
+	assert.False(t, a.Contains(3))
+	assert.Equal(t, "[1, 3)", a.String())
+}
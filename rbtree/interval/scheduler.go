@@ -0,0 +1,96 @@
+package interval
+
+import (
+	"fmt"
+)
+
+// Number is the set of types that can be used as points by a Scheduler,
+// which adds durations to them.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Scheduler books non-overlapping reservations of a single resource, such as a meeting room,
+// each holding a value of type V.
+//
+// Reservations are half-open intervals [lo, hi), kept in an interval Tree, so that conflicts are
+// found with Tree.AnyOverlap in O(log n) time. Schedulers must be created with NewScheduler.
+type Scheduler[T Number, V any] struct {
+	tree *Tree[T, V] // Reservations, which never overlap
+}
+
+// NewScheduler creates and returns a new empty scheduler.
+//
+// Returns:
+//   - A pointer to a newly created Scheduler[T, V] instance.
+func NewScheduler[T Number, V any]() *Scheduler[T, V] {
+	return &Scheduler[T, V]{tree: New[T, V]()}
+}
+
+// Size returns the number of reservations.
+//
+// This is an O(1) operation.
+func (s *Scheduler[T, V]) Size() int {
+	return s.tree.Size()
+}
+
+// IsFree returns true if no reservation overlaps [lo, hi), in O(log n) time.
+func (s *Scheduler[T, V]) IsFree(lo, hi T) bool {
+	_, _, found := s.tree.AnyOverlap(Interval[T]{Lo: lo, Hi: hi})
+	return !found
+}
+
+// ReserveIfFree reserves [lo, hi) with the given value, unless it overlaps an existing reservation.
+// Panics if lo is not less than hi.
+//
+// Returns:
+//   - true if the reservation was made.
+//   - false if [lo, hi) overlaps an existing reservation.
+func (s *Scheduler[T, V]) ReserveIfFree(lo, hi T, value V) bool {
+	iv := Interval[T]{Lo: lo, Hi: hi}
+	if iv.IsEmpty() {
+		panic(fmt.Sprintf("interval: invalid reservation %v", iv))
+	}
+	if _, _, found := s.tree.AnyOverlap(iv); found {
+		return false
+	}
+	s.tree.Insert(iv, value)
+	return true
+}
+
+// Release cancels the reservation [lo, hi).
+//
+// Returns:
+//   - true if the reservation was found and released.
+//   - false if there is no reservation with exactly these bounds.
+func (s *Scheduler[T, V]) Release(lo, hi T) bool {
+	return s.tree.Delete(Interval[T]{Lo: lo, Hi: hi})
+}
+
+// FindFirstFreeSlot returns the earliest free interval [lo, lo+duration) with lo >= after.
+//
+// Each reservation found in the way moves the candidate slot to its end, so this takes
+// O((k+1) log n) time, where k is the number of reservations skipped.
+// Panics if duration is not positive.
+func (s *Scheduler[T, V]) FindFirstFreeSlot(duration, after T) Interval[T] {
+	if duration <= 0 {
+		panic(fmt.Sprintf("interval: invalid duration %v", duration))
+	}
+	slot := Interval[T]{Lo: after, Hi: after + duration}
+	for {
+		iv, _, found := s.tree.AnyOverlap(slot)
+		if !found {
+			return slot
+		}
+		slot = Interval[T]{Lo: iv.Hi, Hi: iv.Hi + duration}
+	}
+}
+
+// Ascend calls f for each reservation and its value, in ascending order, until f returns false.
+//
+// The scheduler must not be modified during iteration.
+func (s *Scheduler[T, V]) Ascend(f func(iv Interval[T], value V) bool) {
+	s.tree.Ascend(f)
+}
@@ -0,0 +1,83 @@
+package interval
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func TestScheduler(t *testing.T) {
+	s := NewScheduler[int, int]()
+	assert.Equal(t, Interval[int]{5, 8}, s.FindFirstFreeSlot(3, 5))
+
+	// model of the schedule: the reservation holding each slot, if any
+	const length = 300
+	var model [length]*Interval[int]
+	isFree := func(lo, hi int) bool {
+		for p := max(lo, 0); p < min(hi, length); p++ {
+			if model[p] != nil {
+				return false
+			}
+		}
+		return true
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		lo := rng.Intn(length - 20)
+		hi := lo + 1 + rng.Intn(20)
+		switch rng.Intn(4) {
+		case 0, 1:
+			free := isFree(lo, hi)
+			assert.Equal(t, free, s.IsFree(lo, hi))
+			assert.Equal(t, free, s.ReserveIfFree(lo, hi, i))
+			if free {
+				iv := &Interval[int]{lo, hi}
+				for p := lo; p < hi; p++ {
+					model[p] = iv
+				}
+			}
+		case 2:
+			// release an existing reservation half of the time
+			if iv := model[lo]; iv != nil && rng.Intn(2) == 0 {
+				lo, hi = iv.Lo, iv.Hi
+			}
+			exists := model[lo] != nil && *model[lo] == Interval[int]{lo, hi}
+			assert.Equal(t, exists, s.Release(lo, hi))
+			if exists {
+				for p := lo; p < hi; p++ {
+					model[p] = nil
+				}
+			}
+		case 3:
+			duration := 1 + rng.Intn(10)
+			expected := lo
+			for !isFree(expected, expected+duration) {
+				expected++
+			}
+			assert.Equal(t, Interval[int]{expected, expected + duration}, s.FindFirstFreeSlot(duration, lo))
+		}
+
+		if i%100 == 0 {
+			require.NoError(t, s.tree.IsTreeValid())
+			var prev *Interval[int]
+			count := 0
+			s.Ascend(func(iv Interval[int], _ int) bool {
+				require.True(t, prev == nil || prev.Hi <= iv.Lo, "reservations %v and %v overlap", prev, iv)
+				require.Equal(t, iv, *model[iv.Lo])
+				prev = &iv
+				count++
+				return true
+			})
+			require.Equal(t, count, s.Size())
+		}
+	}
+}
+
+func TestScheduler_InvalidArguments(t *testing.T) {
+	s := NewScheduler[float64, string]()
+	assert.Panics(t, func() { s.ReserveIfFree(2, 2, "empty") })
+	assert.Panics(t, func() { s.FindFirstFreeSlot(0, 1) })
+	assert.Panics(t, func() { s.FindFirstFreeSlot(-1, 1) })
+}
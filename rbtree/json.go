@@ -0,0 +1,48 @@
+package rbtree
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/mikenye/gotrees/bst"
+)
+
+// MarshalJSON implements json.Marshaler, encoding the color as "red" or "black".
+func (c Color) MarshalJSON() ([]byte, error) {
+	if c == Black {
+		return []byte(`"black"`), nil
+	}
+	return []byte(`"red"`), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding a color encoded by Color.MarshalJSON.
+func (c *Color) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	switch s {
+	case "black":
+		*c = Black
+	case "red":
+		*c = Red
+	default:
+		return fmt.Errorf("invalid color: %q", s)
+	}
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, restoring a tree encoded by bst.Tree.MarshalJSON
+// (which rbtree.Tree exposes), including node colors.
+//
+// The tree must have been created with New. Its existing contents are replaced, and any handles
+// to its previous nodes become stale. The restored tree is validated with Tree.IsTreeValid, so that
+// both BST ordering and Red-Black properties hold. If the data cannot be decoded or describes an
+// invalid tree, an error is returned and the tree is left unchanged.
+//
+// ⚠️ Important: The restored tree replaces the underlying bst.Tree, so any functions registered
+// with bst.Tree.SetAugmentFunc, bst.Tree.SetNodeFormatter or bst.Tree.OnChange must be registered again.
+func (t *Base[K, V, M]) UnmarshalJSON(data []byte) error {
+	return t.restore(func(b *bst.Tree[K, V, M]) error {
+		return b.UnmarshalJSON(data)
+	})
+}
@@ -0,0 +1,78 @@
+package rbtree
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestColor_JSON(t *testing.T) {
+	data, err := json.Marshal([]Color{Red, Black})
+	require.NoError(t, err)
+	assert.Equal(t, `["red","black"]`, string(data))
+
+	var colors []Color
+	require.NoError(t, json.Unmarshal(data, &colors))
+	assert.Equal(t, []Color{Red, Black}, colors)
+
+	var c Color
+	assert.Error(t, json.Unmarshal([]byte(`"green"`), &c), "expected error for invalid color")
+	assert.Error(t, json.Unmarshal([]byte(`true`), &c), "expected error for non-string color")
+}
+
+func TestTree_JSON(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	tree := New[int, string](less)
+	for i := 0; i < 50; i++ {
+		tree.Insert(i, "value")
+	}
+
+	data, err := json.Marshal(tree)
+	require.NoError(t, err)
+
+	restored := New[int, string](less)
+	old, _ := restored.Insert(1000, "old")
+	require.NoError(t, json.Unmarshal(data, restored))
+	require.NoError(t, restored.IsTreeValid(), "expected valid tree")
+	assert.True(t, tree.EqualStructure(restored, func(a, b string) bool { return a == b }), "expected identical shape")
+	assert.Equal(t, 50, restored.Size(), "unexpected size")
+	assert.False(t, restored.Contains(old), "expected previous nodes to become stale")
+
+	// colors are restored
+	a, b := tree.Min(tree.Root()), restored.Min(restored.Root())
+	for !tree.IsNil(a) {
+		assert.Equal(t, tree.Metadata(a), restored.Metadata(b), "unexpected color at node %d", tree.Key(a))
+		a, b = tree.Successor(a), restored.Successor(b)
+	}
+
+	// the restored tree remains usable
+	for i := 0; i < 50; i += 2 {
+		n, _ := restored.Search(i)
+		_, deleted := restored.Delete(n)
+		require.True(t, deleted)
+	}
+	require.NoError(t, restored.IsTreeValid(), "expected valid tree")
+}
+
+func TestTree_UnmarshalJSON_errors(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	tests := map[string]string{
+		"red root":       `{"nodes":[{"key":1,"value":"","metadata":"red"}]}`,
+		"invalid color":  `{"nodes":[{"key":1,"value":"","metadata":"blue"}]}`,
+		"unordered keys": `{"nodes":[{"key":1,"value":"","metadata":"black","left":true},{"key":2,"value":"","metadata":"red"}]}`,
+		"black height":   `{"nodes":[{"key":1,"value":"","metadata":"black","right":true},{"key":2,"value":"","metadata":"black"}]}`,
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			tree := New[int, string](less)
+			n, _ := tree.Insert(5, "five")
+			assert.Error(t, json.Unmarshal([]byte(data), tree), "expected error")
+			require.NoError(t, tree.IsTreeValid(), "expected tree to remain valid")
+			assert.True(t, tree.Contains(n), "expected tree to be unchanged")
+		})
+	}
+
+	var tree Tree[int, string]
+	assert.Error(t, json.Unmarshal([]byte(`{"nodes":[]}`), &tree), "expected error unmarshaling into zero tree")
+}
@@ -0,0 +1,36 @@
+package ostree_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/bst"
+	"github.com/mikenye/gotrees/rbtree"
+	"github.com/mikenye/gotrees/rbtree/ostree"
+)
+
+func ExampleTree_AscendIndex() {
+
+	// create a leaderboard ordered by descending score
+	tree := ostree.New[int, string](func(a, b int) bool {
+		return a > b
+	})
+	tree.Insert(720, "carol")
+	tree.Insert(950, "alice")
+	tree.Insert(410, "erin")
+	tree.Insert(860, "bob")
+	tree.Insert(530, "dave")
+
+	// show places 2 to 4
+	tree.AscendIndex(1, 4, func(i int, n *bst.Node[int, string, rbtree.Color]) bool {
+		fmt.Printf("#%d %s (%d)\n", i+1, tree.Value(n), tree.Key(n))
+		return true
+	})
+
+	// find the place a score of 800 would take
+	fmt.Println("800 would place", tree.Rank(800)+1)
+
+	// Output:
+	// #2 bob (860)
+	// #3 carol (720)
+	// #4 dave (530)
+	// 800 would place 3
+}
@@ -0,0 +1,102 @@
+// Package ostree provides an order-statistic Red-Black Tree: a red-black tree whose nodes
+// can be addressed by their position in key order as well as by key.
+//
+// The tree extends rbtree.Tree. Every node records the size of its subtree, and sizes are kept
+// up to date by the underlying bst.Tree through insertions, deletions and the rotations performed
+// by the red-black fixups, so positional operations take O(log n) time:
+//   - Tree.Rank returns the number of keys less than a given key, and Tree.IndexOf the position of a node.
+//   - Tree.Select and Tree.At return the node or entry at a given position.
+//   - Tree.DeleteAt removes the node at a given position, and returns its key and value.
+//   - Tree.AscendIndex iterates over a range of positions.
+//
+// Positions (indexes) are zero-based, and ranges of positions are half-open intervals [lo, hi),
+// as with Go slices.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/rbtree/ostree"
+//
+//	tree := ostree.New[int, string](func(a, b int) bool { return a < b })
+//	tree.Insert(30, "thirty")
+//	tree.Insert(10, "ten")
+//	tree.Insert(20, "twenty")
+//	key, value, _ := tree.At(1) // 20, "twenty"
+//	i := tree.Rank(25)          // 2
+package ostree
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"github.com/mikenye/gotrees/rbtree"
+)
+
+// Tree represents an order-statistic Red-Black Tree, an extension of rbtree.Tree
+// with positional access to its nodes.
+type Tree[K, V any] struct {
+	*rbtree.Tree[K, V] // Underlying Red-Black Tree
+}
+
+// New creates a new empty order-statistic Red-Black Tree.
+//
+// Parameters:
+//   - less: A comparison function (bst.LessFunc[K]) that defines the ordering of keys.
+//   - opts: Optional behaviors to enable on the underlying bst.Tree (see bst.Option).
+//
+// Returns:
+//   - A pointer to a newly created Tree[K, V] instance.
+func New[K, V any](less bst.LessFunc[K], opts ...bst.Option) *Tree[K, V] {
+	return &Tree[K, V]{Tree: rbtree.New[K, V](less, opts...)}
+}
+
+// At returns the key and value at the given zero-based position in key order.
+//
+// Returns:
+//   - (key, value, true) if 0 ≤ i < Tree.Size.
+//   - (zero key, zero value, false) if i is out of range.
+func (t *Tree[K, V]) At(i int) (K, V, bool) {
+	n, found := t.Select(i)
+	if !found {
+		var k K
+		var v V
+		return k, v, false
+	}
+	return t.Key(n), t.Value(n), true
+}
+
+// IndexOf returns the zero-based position of node n in key order, in O(log n) time.
+//
+// Unlike Tree.Rank, this identifies a single node when duplicate keys are enabled.
+//
+// Returns:
+//   - The position of n, or -1 if n is nil, has been removed, or belongs to a different tree.
+func (t *Tree[K, V]) IndexOf(n *bst.Node[K, V, rbtree.Color]) int {
+	if t.IsNil(n) || !t.Contains(n) {
+		return -1
+	}
+
+	// count the nodes before n: its left subtree, and each ancestor it is a right descendant of,
+	// along with that ancestor's left subtree
+	i := t.SubtreeSize(t.Left(n))
+	for p := t.Parent(n); !t.IsNil(p); n, p = p, t.Parent(p) {
+		if t.Right(p) == n {
+			i += t.SubtreeSize(t.Left(p)) + 1
+		}
+	}
+	return i
+}
+
+// AscendIndex calls f for each node at a position in the half-open interval [lo, hi), in key order,
+// until f returns false. The bounds are clamped to [0, Tree.Size).
+//
+// Finding the first node takes O(log n) time, and each subsequent node O(1) amortized time
+// (see rbtree.Tree.AscendAt). The tree must not be modified during iteration.
+func (t *Tree[K, V]) AscendIndex(lo, hi int, f func(i int, n *bst.Node[K, V, rbtree.Color]) bool) {
+	lo, hi = max(lo, 0), min(hi, t.Size())
+	i := lo
+	t.AscendAt(lo, func(n *bst.Node[K, V, rbtree.Color]) bool {
+		if i >= hi || !f(i, n) {
+			return false
+		}
+		i++
+		return true
+	})
+}
@@ -0,0 +1,97 @@
+package ostree
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"github.com/mikenye/gotrees/rbtree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+func TestTree_positions(t *testing.T) {
+	tree := New[int, int](intLess)
+	rng := rand.New(rand.NewSource(1))
+	var keys []int // sorted keys expected in the tree
+
+	for i := 0; i < 5000; i++ {
+		key := rng.Intn(1000)
+		j, exists := slices.BinarySearch(keys, key)
+		switch {
+		case rng.Intn(3) == 0 && len(keys) > 0:
+			j = rng.Intn(len(keys))
+			k, v, deleted := tree.DeleteAt(j)
+			require.True(t, deleted)
+			require.Equal(t, keys[j], k)
+			require.Equal(t, keys[j]*10, v)
+			keys = slices.Delete(keys, j, j+1)
+		case !exists:
+			tree.Insert(key, key*10)
+			keys = slices.Insert(keys, j, key)
+		}
+		if i%250 == 0 {
+			require.NoError(t, tree.IsTreeValid())
+		}
+	}
+	require.NoError(t, tree.IsTreeValid())
+	require.Equal(t, len(keys), tree.Size())
+
+	for i, key := range keys {
+		k, v, found := tree.At(i)
+		require.True(t, found)
+		assert.Equal(t, key, k)
+		assert.Equal(t, key*10, v)
+		n, _ := tree.Search(key)
+		assert.Equal(t, i, tree.IndexOf(n))
+		assert.Equal(t, i, tree.Rank(key))
+	}
+	_, _, found := tree.At(len(keys))
+	assert.False(t, found)
+	_, _, found = tree.At(-1)
+	assert.False(t, found)
+	_, _, found = tree.DeleteAt(len(keys))
+	assert.False(t, found)
+
+	var got []int
+	tree.AscendIndex(10, 20, func(i int, n *bst.Node[int, int, rbtree.Color]) bool {
+		assert.Equal(t, keys[i], tree.Key(n))
+		got = append(got, tree.Key(n))
+		return true
+	})
+	assert.Equal(t, keys[10:20], got)
+
+	count := 0
+	tree.AscendIndex(-5, len(keys)+5, func(int, *bst.Node[int, int, rbtree.Color]) bool {
+		count++
+		return true
+	})
+	assert.Equal(t, len(keys), count, "expected bounds to be clamped")
+
+	count = 0
+	tree.AscendIndex(0, len(keys), func(int, *bst.Node[int, int, rbtree.Color]) bool {
+		count++
+		return false
+	})
+	assert.Equal(t, 1, count, "expected iteration to stop early")
+}
+
+func TestTree_IndexOf_duplicateKeys(t *testing.T) {
+	tree := New[int, int](intLess, bst.WithDuplicateKeys())
+	var nodes []*bst.Node[int, int, rbtree.Color]
+	for i := 0; i < 50; i++ {
+		n, _ := tree.Insert(i/10, i)
+		nodes = append(nodes, n)
+	}
+	for i, n := range nodes {
+		assert.Equal(t, i, tree.IndexOf(n))
+	}
+
+	tree.Delete(nodes[0])
+	assert.Equal(t, -1, tree.IndexOf(nodes[0]), "expected removed node to have no position")
+	assert.Equal(t, 0, tree.IndexOf(nodes[1]))
+	assert.Equal(t, -1, tree.IndexOf(tree.Sentinel()))
+	assert.Equal(t, -1, tree.IndexOf(nil))
+}
@@ -22,48 +22,71 @@
 //		tree.Delete(node)
 //	}
 //
-// # Safe Inherited Methods from bst.Tree
+// # Methods from bst.Tree
 //
-// The following methods are inherited from bst.Tree and can be used safely:
+// The underlying bst.Tree is not embedded. Instead, the methods of bst.Tree that cannot break
+// Red-Black properties are exposed by Tree, forwarding to the underlying tree, including:
 //   - [bst.Tree.Root]: Returns the root node.
 //   - [bst.Tree.Search]: Finds a node by key.
 //   - [bst.Tree.Successor]: Returns the next in-order node.
 //   - [bst.Tree.Predecessor]: Returns the previous in-order node.
 //   - [bst.Tree.TraverseInOrder]: In-order traversal.
+//   - [bst.Tree.All], [bst.Tree.Backward], [bst.Tree.Keys], [bst.Tree.Values]: Iterators for range-over-func loops and the slices and maps packages.
 //   - [bst.Tree.Min]: Returns the node with the smallest key.
 //   - [bst.Tree.Max]: Returns the node with the largest key.
+//   - [bst.Tree.FirstEntry], [bst.Tree.LastEntry]: Return the smallest and largest keys, and their values.
 //   - [bst.Tree.Floor]: Returns the largest node with key ≤ given key.
 //   - [bst.Tree.Ceiling]: Returns the smallest node with key ≥ given key.
+//   - [bst.Tree.Lower]: Returns the largest node with key < given key.
+//   - [bst.Tree.Higher]: Returns the smallest node with key > given key.
+//   - [bst.Tree.Nearest]: Returns the node with the key closest to a given key.
 //   - [bst.Tree.IsNil]: Checks if a node is the sentinel nil node.
 //   - [bst.Tree.Parent]: Returns the parent of a node.
-//
-// # Unsafe Inherited Methods from bst.Tree
-//
-// The following methods from bst.Tree should not be used in rbtree, as they can violate Red-Black properties.
-// They have been shadowed in rbtree, and modified to panic if used:
-//
-//   - [bst.Tree.MustSetMetadata]: ❌ Do not use
-//   - [bst.Tree.SetKey]: ❌ Do not use
-//   - [bst.Tree.SetLeft]: ❌ Do not use
-//   - [bst.Tree.SetMetadata]: ❌ Do not use
-//   - [bst.Tree.SetParent]: ❌ Do not use
-//   - [bst.Tree.SetRight]: ❌ Do not use
-//   - [bst.Tree.SetRoot]: ❌ Do not use
-//   - [bst.Tree.Transplant]: ❌ Do not use
-//
-// ⚠️ Warning: Using any of these methods will likely break the Red-Black properties and cause undefined behavior.
+//   - [bst.Tree.Size]: Returns the number of nodes in the tree.
+//   - [bst.Tree.Rank]: Returns the number of keys less than a given key.
+//   - [bst.Tree.Select]: Returns the node with a given zero-based rank.
+//   - [bst.Tree.KthSmallest]: Returns the node with the k-th smallest key.
+//   - [bst.Tree.KthLargest]: Returns the node with the k-th largest key.
+//   - [bst.Tree.AscendAt]: Iterates in key order from the node with a given zero-based rank.
+//   - [bst.Tree.SliceByRank]: Returns the nodes with zero-based ranks in a half-open interval.
+//   - [bst.Tree.SetAugmentFunc]: Maintains user-defined aggregates through insertions, deletions and rotations.
+//     As rbtree stores colors in node metadata, aggregates must be stored in node values,
+//     or in the user data of an AuxTree.
+//
+// The methods of bst.Tree that relink, recolor or re-key nodes are not available at all, so that
+// misusing them is a compile-time error rather than a corrupted tree:
+//
+//   - [bst.Tree.AttachSubtree], [bst.Tree.DetachSubtree]
+//   - [bst.Tree.MustSetMetadata], [bst.Tree.SetMetadata]
+//   - [bst.Tree.LoadSortedFunc] (see Tree.LoadSorted)
+//   - [bst.Tree.Rebalance], [bst.Tree.RebuildSubtree]
+//   - [bst.Tree.RotateLeft], [bst.Tree.RotateRight]
+//   - [bst.Tree.SetHooks], [bst.Tree.SetKey], [bst.Tree.SetLeft], [bst.Tree.SetParent], [bst.Tree.SetRight], [bst.Tree.SetRoot]
+//   - [bst.Tree.Transplant]
+//
+// Functions of package bst taking a *bst.Tree have rbtree counterparts where they apply, such as Diff.
 //
 // # Limitations
 //
 //   - Not Thread-Safe – Requires external synchronization for concurrent use.
-//   - No Duplicate Keys – Keys must be unique.
+//   - Unique Keys by Default – Duplicate keys require multiset mode (see bst.WithDuplicateKeys).
 package rbtree
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
 	"github.com/mikenye/gotrees/bst"
 )
 
+// Errors wrapped by the errors returned by Tree.IsTreeValid, identifying the Red-Black property violated.
+var (
+	ErrRedRoot             = errors.New("root node is not black")
+	ErrRedSentinel         = errors.New("sentinel nil node is not black")
+	ErrRedRedViolation     = errors.New("red-red violation")
+	ErrBlackHeightMismatch = errors.New("black-height mismatch")
+)
+
 // Color represents the color of a node in a Red-Black Tree.
 //
 // Nodes are either:
@@ -91,98 +114,220 @@ func (c Color) String() string {
 	}
 }
 
-// Tree represents a Red-Black Tree, an extension of bst.Tree that maintains self-balancing properties.
+// NodeColor returns c, so that Color can be used as the metadata of a Base tree (see ColorMetadata).
+func (c Color) NodeColor() Color {
+	return c
+}
+
+// WithNodeColor returns color, so that Color can be used as the metadata of a Base tree (see ColorMetadata).
+func (c Color) WithNodeColor(color Color) Color {
+	return color
+}
+
+// ColorMetadata constrains the node metadata of a Base tree, which holds each node's Color,
+// and possibly user data alongside it. It is implemented by Color, and by Aux.
+type ColorMetadata[M any] interface {
+	NodeColor() Color            // NodeColor returns the color of the node.
+	WithNodeColor(color Color) M // WithNodeColor returns a copy of the metadata, with the node's color set to color.
+}
+
+// Base represents a Red-Black Tree, an extension of bst.Tree that maintains self-balancing properties,
+// whose nodes hold metadata of type M recording their color.
 //
 // This tree ensures:
 //   - O(log n) insertions, deletions, and lookups.
 //   - Automatic re-balancing using the Red-Black Tree rules.
 //   - Strict BST ordering with an additional node metadata Color for balancing.
 //
-// The tree embeds a generic Binary Search Tree bst.Tree, using Color as metadata
-// to track whether a node is `Red` or `Black`. The `size` field keeps track of the total
-// number of nodes.
-type Tree[K, V any] struct {
-	*bst.Tree[K, V, Color]     // Underlying BST structure
-	size                   int // Total number of nodes
+// The tree wraps a generic Binary Search Tree bst.Tree, using M as metadata to track whether
+// a node is `Red` or `Black`. Subtree sizes are maintained by bst.Tree, providing the total
+// number of nodes and order statistics (see bst.Tree.Rank and bst.Tree.Select).
+//
+// Base is used through Tree, whose metadata is just the Color of each node, or AuxTree,
+// whose metadata also holds user data.
+type Base[K, V any, M ColorMetadata[M]] struct {
+	tree     *bst.Tree[K, V, M] // Underlying BST structure, not embedded so that only its safe methods are exposed (see bst.go)
+	less     bst.LessFunc[K]    // Function to compare keys and maintain order
+	opts     []bst.Option       // Options the underlying BST was created with
+	trace    *trace[K]          // Tracer of rebalancing steps, or nil (see Tree.SetTracer)
+	strategy Strategy           // How Insert and Delete rebalance the tree (see Tree.SetStrategy)
+
+	tombstones     map[*bst.Node[K, V, M]]struct{} // Nodes deleted lazily, yet to be compacted (see Tree.SetLazyDeletion)
+	tombstoneRatio float64                         // Share of tombstones triggering compaction, or 0 if lazy deletion is disabled
 }
 
-// isBlack returns true if the passed node is black or nil (nil leaves are considered black)
-func (t *Tree[K, V]) isBlack(n *bst.Node[K, V, Color]) bool {
-	if t.IsNil(n) || t.Metadata(n) != Red {
+// Tree represents a Red-Black Tree whose node metadata is the Color of each node (see Base).
+type Tree[K, V any] = Base[K, V, Color]
+
+// color returns the color of node n.
+func (t *Base[K, V, M]) color(n *bst.Node[K, V, M]) Color {
+	return t.Metadata(n).NodeColor()
+}
+
+// IsBlack reports whether node n is black. The sentinel nil node is black, as are the nil leaves of a Red-Black Tree.
+func (t *Base[K, V, M]) IsBlack(n *bst.Node[K, V, M]) bool {
+	if t.IsNil(n) || t.color(n) != Red {
 		return true
 	}
 	return false
 }
 
-// isRed returns true if the passed node is not nil and red
-func (t *Tree[K, V]) isRed(n *bst.Node[K, V, Color]) bool {
-	if !t.IsNil(n) && t.Metadata(n) == Red {
+// IsRed reports whether node n is red. The sentinel nil node is never red.
+func (t *Base[K, V, M]) IsRed(n *bst.Node[K, V, M]) bool {
+	if !t.IsNil(n) && t.color(n) == Red {
 		return true
 	}
 	return false
 }
 
+// BlackHeight returns the black height of the tree: the number of black nodes on any path from the root
+// to a nil leaf, counting the root but not the leaf. As every such path holds the same number of black
+// nodes, it is found by following the leftmost path, in O(log n) time.
+//
+// Returns:
+//   - The black height of the tree, which is 0 for an empty tree.
+func (t *Base[K, V, M]) BlackHeight() int {
+	h := 0
+	for n := t.Root(); !t.IsNil(n); n = t.Left(n) {
+		if t.IsBlack(n) {
+			h++
+		}
+	}
+	return h
+}
+
 // setColor sets the color of node n, if node n is not the sentinel nil node
-func (t *Tree[K, V]) setColor(n *bst.Node[K, V, Color], c Color) {
-	if !t.IsNil(n) {
-		t.Tree.SetMetadata(n, c)
+func (t *Base[K, V, M]) setColor(n *bst.Node[K, V, M], c Color) {
+	if t.IsNil(n) {
+		return
 	}
+	m := t.Metadata(n).WithNodeColor(c)
+	if t.trace != nil && t.color(n) != c {
+		t.step(StepRecolor, n, func() { t.tree.SetMetadata(n, m) })
+		return
+	}
+	t.tree.SetMetadata(n, m)
 }
 
 // Delete removes the given node z from the Red-Black Tree while maintaining tree balance.
 //
 // Deleting a node modifies tree structure and may trigger rotation/recoloring
-// to maintain Red-Black Tree properties.
-func (t *Tree[K, V]) Delete(z *bst.Node[K, V, Color]) bool {
-	// if nil input, don't delete anything and give nil output
-	if t.IsNil(z) || z == nil {
-		return false
+// to maintain Red-Black Tree properties, after the removal, or on the way down
+// with the TopDown strategy (see Tree.SetStrategy).
+//
+// Nodes are relinked rather than having their keys and values copied, so handles to
+// other nodes remain valid after the deletion. The deleted node z is released
+// (see bst.Tree.Release) and must no longer be used with the tree.
+//
+// With lazy deletion, z is marked as a tombstone instead, and removed later (see Tree.SetLazyDeletion).
+//
+// Returns:
+//   - (value, true) if z was deleted, where value is the value z held, e.g. for acting on an evicted entry.
+//   - (zero value, false) if z is nil, has already been removed, or belongs to a different tree.
+func (t *Base[K, V, M]) Delete(z *bst.Node[K, V, M]) (V, bool) {
+	// if nil, stale or foreign input, don't delete anything
+	if t.IsNil(z) || !z.BelongsTo(t.tree) || t.IsTombstone(z) {
+		var zero V
+		return zero, false
 	}
-
-	var x, y *bst.Node[K, V, Color]
-
-	// if node being deleted has one child
-	if t.IsNil(t.Left(z)) || t.IsNil(t.Right(z)) {
-		y = z // deletion case 1
-	} else {
-		y = t.Successor(z) // deletion case 2
+	value := t.Value(z)
+	if t.tombstoneRatio > 0 {
+		t.bury(z)
+		return value, true
 	}
+	t.erase(z)
+	return value, true
+}
 
-	if !t.IsNil(t.Left(y)) {
-		// if node being deleted has left child, set x to left child
-		x = t.Left(y)
+// erase removes node z from the tree with the strategy of the tree, and releases it.
+func (t *Base[K, V, M]) erase(z *bst.Node[K, V, M]) {
+	if t.strategy == TopDown {
+		t.deleteTopDown(z)
 	} else {
-		// otherwise, set x to right child
-		x = t.Right(y)
+		t.unlink(z)
 	}
+	t.tree.Release(z)
+}
 
-	// update replacement node's parent
-	t.Tree.SetParent(x, t.Parent(y))
-	if t.IsNil(t.Parent(y)) {
-		// if replacement has no parent, it becomes root
-		t.SetRoot(x)
+// unlink implements Tree.Delete, relinking the neighbours of node z so that it is no longer part of the
+// tree's structure, and restoring Red-Black Tree properties, without releasing z.
+func (t *Base[K, V, M]) unlink(z *bst.Node[K, V, M]) {
+	t.traceCase("delete", 0, false)
+	var x *bst.Node[K, V, M]
+	y := z
+	yOriginalColor := t.color(y)
+
+	if t.IsNil(t.Left(z)) {
+		// deletion case 1: no left child, replace z with its right child
+		x = t.Right(z)
+		t.transplant(z, t.Right(z))
+	} else if t.IsNil(t.Right(z)) {
+		// deletion case 2: no right child, replace z with its left child
+		x = t.Left(z)
+		t.transplant(z, t.Left(z))
 	} else {
-		// update parent/child relationships
-		if y == t.Left(t.Parent(y)) {
-			// if y is a left child
-			t.Tree.SetLeft(t.Parent(y), x)
+		// deletion case 3: two children, replace z with its successor y
+		y = t.tree.Min(t.Right(z))
+		yOriginalColor = t.color(y)
+		x = t.Right(y)
+		if t.Parent(y) == z {
+			t.tree.SetParent(x, y)
 		} else {
-			// if y is a right child
-			t.Tree.SetRight(t.Parent(y), x)
+			t.transplant(y, t.Right(y))
+			t.tree.SetRight(y, t.Right(z))
+			t.tree.SetParent(t.Right(y), y)
 		}
+		t.tree.SetLeft(y, t.Left(z))
+		t.tree.SetParent(t.Left(y), y)
+		t.transplant(z, y)
+		t.setColor(y, t.color(z))
 	}
-	if y != z {
-		// copy y’s satellite data into z
-		t.Tree.SetKey(z, t.Key(y))
-		t.Tree.SetValue(z, t.Value(y))
-	}
+
+	// update subtree sizes from the splice point up, before any fixup rotations
+	t.tree.RefreshPath(t.Parent(x))
 
 	// fixup
-	if t.isBlack(y) {
+	if yOriginalColor == Black {
 		t.deleteFixup(x)
 	}
 	t.resetSentinelNodeProperties()
-	t.size--
+}
+
+// UpdateKey changes the key of node z to key, preserving its value and any user data in its metadata,
+// while maintaining Red-Black Tree properties. z remains a valid handle to the same entry.
+//
+// If key keeps z between its predecessor and successor, the key is updated in place. Otherwise, z is
+// unlinked as with Tree.Delete, then linked back and rebalanced as with Tree.Insert, without being released.
+// See bst.Tree.UpdateKey for details.
+//
+// Returns:
+//   - true if the key of z was updated.
+//   - false if z is nil, has been removed, or belongs to a different tree, or if another node already
+//     holds key and duplicate keys are not enabled (see bst.WithDuplicateKeys).
+func (t *Base[K, V, M]) UpdateKey(z *bst.Node[K, V, M], key K) bool {
+	if t.IsTombstone(z) {
+		return false
+	}
+	t.Compact()
+	return bst.UpdateKeyFunc(t.tree, z, key, t.move)
+}
+
+// move implements Tree.UpdateKey for a node whose new key does not keep it in place.
+func (t *Base[K, V, M]) move(z *bst.Node[K, V, M], key K) {
+	t.unlink(z)
+	t.tree.Relink(z, key)
+	t.traceCase("insert", 0, false)
+	t.setColor(z, Red)
+	t.insertFixup(z)
+}
+
+// remove deletes node z (see Tree.Delete), discarding its value, for the helpers of bst
+// taking a deletion function, such as bst.PopMinFunc. z is removed at once, even with lazy deletion.
+func (t *Base[K, V, M]) remove(z *bst.Node[K, V, M]) bool {
+	if t.IsNil(z) || !z.BelongsTo(t.tree) {
+		return false
+	}
+	t.erase(z)
 	return true
 }
 
@@ -202,39 +347,42 @@ func (t *Tree[K, V]) Delete(z *bst.Node[K, V, Color]) bool {
 // 4. Sibling has one red child (near side is red): Rotate parent, recolor, and fix final issues.
 //
 // The function proceeds iteratively, moving up the tree until balance is restored.
-func (t *Tree[K, V]) deleteFixup(x *bst.Node[K, V, Color]) {
-	for x != t.Root() && t.isBlack(x) {
+func (t *Base[K, V, M]) deleteFixup(x *bst.Node[K, V, M]) {
+	for x != t.Root() && t.IsBlack(x) {
 		if x == t.Left(t.Parent(x)) { // is x a left child?
 			w := t.Right(t.Parent(x))
-			if t.isRed(w) {
+			if t.IsRed(w) {
 
 				// Case 1: Sibling w is red
 				// Convert to case 2, 3, or 4 by recoloring and rotating
 				// This increases the black height of x's subtree
+				t.traceCase("delete", 1, false)
 				t.setColor(w, Black)
 				t.setColor(t.Parent(x), Red)
-				t.Tree.RotateLeft(t.Parent(x))
+				t.rotateLeft(t.Parent(x))
 				w = t.Right(t.Parent(x))
 
 			}
-			if t.isBlack(t.Left(w)) && t.isBlack(t.Right(w)) {
+			if t.IsBlack(t.Left(w)) && t.IsBlack(t.Right(w)) {
 
 				// Case 2: Sibling w is black and both of its children are black
 				// Make sibling red to balance the black height
 				// Move the double-black problem up the tree to the parent
+				t.traceCase("delete", 2, false)
 				t.setColor(w, Red)
 				x = t.Parent(x)
 
 			} else {
 
-				if t.isBlack(t.Right(w)) {
+				if t.IsBlack(t.Right(w)) {
 
 					// Case 3: Sibling w is black, its left child is red, right child is black
 					// Transform to case 4 by recoloring and right rotation
 					// This moves the red color to the far side (right child)
+					t.traceCase("delete", 3, false)
 					t.setColor(t.Left(w), Black)
 					t.setColor(w, Red)
-					t.Tree.RotateRight(w)
+					t.rotateRight(w)
 					w = t.Right(t.Parent(x))
 				}
 
@@ -242,10 +390,11 @@ func (t *Tree[K, V]) deleteFixup(x *bst.Node[K, V, Color]) {
 				// Final resolution - fix the double-black problem completely
 				// Copy parent's color to sibling, make parent and sibling's right child black
 				// Left rotate to rebalance, then set x to root to exit the loop
-				t.setColor(w, t.Metadata(t.Parent(x)))
+				t.traceCase("delete", 4, false)
+				t.setColor(w, t.color(t.Parent(x)))
 				t.setColor(t.Parent(x), Black)
 				t.setColor(t.Right(w), Black)
-				t.Tree.RotateLeft(t.Parent(x))
+				t.rotateLeft(t.Parent(x))
 				x = t.Root()
 			}
 		} else {
@@ -254,35 +403,38 @@ func (t *Tree[K, V]) deleteFixup(x *bst.Node[K, V, Color]) {
 			// The logic is the same but the directions are reversed
 
 			w := t.Left(t.Parent(x))
-			if t.isRed(w) {
+			if t.IsRed(w) {
 
 				// Case 1 (mirrored): Sibling w is red
 				// Convert to case 2, 3, or 4 by recoloring and rotating
 				// This increases the black height of x's subtree
+				t.traceCase("delete", 1, true)
 				t.setColor(w, Black)
 				t.setColor(t.Parent(x), Red)
-				t.Tree.RotateRight(t.Parent(x))
+				t.rotateRight(t.Parent(x))
 				w = t.Left(t.Parent(x))
 
 			}
-			if t.isBlack(t.Right(w)) && t.isBlack(t.Left(w)) {
+			if t.IsBlack(t.Right(w)) && t.IsBlack(t.Left(w)) {
 
 				// Case 2 (mirrored): Sibling w is black and both of its children are black
 				// Make sibling red to balance the black height
 				// Move the double-black problem up the tree to the parent
+				t.traceCase("delete", 2, true)
 				t.setColor(w, Red)
 				x = t.Parent(x)
 
 			} else {
 
-				if t.isBlack(t.Left(w)) {
+				if t.IsBlack(t.Left(w)) {
 
 					// Case 3 (mirrored): Sibling w is black, its right child is red, left child is black
 					// Transform to case 4 by recoloring and left rotation
 					// This moves the red color to the far side (left child)
+					t.traceCase("delete", 3, true)
 					t.setColor(t.Right(w), Black)
 					t.setColor(w, Red)
-					t.Tree.RotateLeft(w)
+					t.rotateLeft(w)
 					w = t.Left(t.Parent(x))
 				}
 
@@ -290,38 +442,112 @@ func (t *Tree[K, V]) deleteFixup(x *bst.Node[K, V, Color]) {
 				// Final resolution - fix the double-black problem completely
 				// Copy parent's color to sibling, make parent and sibling's left child black
 				// Right rotate to rebalance, then set x to root to exit the loop
-				t.setColor(w, t.Metadata(t.Parent(x)))
+				t.traceCase("delete", 4, true)
+				t.setColor(w, t.color(t.Parent(x)))
 				t.setColor(t.Parent(x), Black)
 				t.setColor(t.Left(w), Black)
-				t.Tree.RotateRight(t.Parent(x))
+				t.rotateRight(t.Parent(x))
 				x = t.Root()
 			}
 		}
 	}
+	t.traceCase("delete", 0, false)
 	t.setColor(x, Black)
 }
 
+// Equal reports whether the tree and other contain the same keys with the same values.
+//
+// See bst.Tree.Equal for details. If valueEq is nil, only the key sets are compared. Both trees are
+// compacted first, if any nodes have been deleted lazily (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) Equal(other *Base[K, V, M], valueEq func(a, b V) bool) bool {
+	t.Compact()
+	other.Compact()
+	return t.tree.Equal(other.tree, valueEq)
+}
+
+// EqualStructure reports whether the tree and other are equal and have an identical shape.
+//
+// See bst.Tree.EqualStructure for details. Node colors are not compared. Both trees are compacted
+// first, if any nodes have been deleted lazily (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) EqualStructure(other *Base[K, V, M], valueEq func(a, b V) bool) bool {
+	t.Compact()
+	other.Compact()
+	return t.tree.EqualStructure(other.tree, valueEq)
+}
+
 // Insert adds a new key-value pair to the Red-Black Tree while maintaining self-balancing properties.
 //
-//   - If the key already exists, its value is updated, and no fixup is needed.
+//   - If the key already exists, it is resolved by the tree's duplicate policy (see bst.WithDuplicatePolicy):
+//     by default, its value is updated, and no fixup is needed.
 //   - If the key is new, the node is inserted colored red, and the tree undergoes fixup rotations/recoloring
 //     to maintain Red-Black Tree properties.
 //
+// In multiset mode (see bst.WithDuplicateKeys), a new node is always inserted after any equal keys.
+//
+// With the TopDown strategy, the tree is rebalanced on the way down instead (see Tree.SetStrategy).
+// With lazy deletion, inserting the key of a node deleted lazily revives that node (see Tree.SetLazyDeletion).
+//
+// Insert panics with an error wrapping bst.ErrDuplicateKey if the key exists under the bst.DuplicateError
+// policy. Use Tree.TryInsert to handle the error instead.
+//
 // Returns:
 //   - The inserted or updated node.
 //   - true if a new node was inserted, false if an existing node was updated.
-func (t *Tree[K, V]) Insert(key K, value V) (*bst.Node[K, V, Color], bool) {
-	n, updated := t.Tree.Insert(key, value)
-	if !updated {
-		return n, false
+func (t *Base[K, V, M]) Insert(key K, value V) (*bst.Node[K, V, M], bool) {
+	n, inserted, err := t.InsertWithPolicy(key, value, t.tree.DuplicatePolicy())
+	if err != nil {
+		panic(err)
 	}
+	return n, inserted
+}
+
+// TryInsert adds a new key-value pair to the tree, as Tree.Insert does, but returns an error rather than
+// panicking if the key exists under the bst.DuplicateError policy.
+//
+// See bst.Tree.TryInsert.
+func (t *Base[K, V, M]) TryInsert(key K, value V) (*bst.Node[K, V, M], bool, error) {
+	return t.InsertWithPolicy(key, value, t.tree.DuplicatePolicy())
+}
+
+// InsertIfAbsent adds a new key-value pair to the tree, only if the key is not already in the tree,
+// whatever the tree's duplicate policy.
+//
+// See bst.Tree.InsertIfAbsent.
+func (t *Base[K, V, M]) InsertIfAbsent(key K, value V) (*bst.Node[K, V, M], bool) {
+	n, inserted, _ := t.InsertWithPolicy(key, value, bst.DuplicateIgnore)
+	return n, inserted
+}
+
+// InsertReturningOld adds a new key-value pair to the tree, as Tree.Insert does, and returns the value
+// the key held before.
+//
+// See bst.Tree.InsertReturningOld.
+func (t *Base[K, V, M]) InsertReturningOld(key K, value V) (V, bool) {
+	return bst.InsertReturningOldFunc(t.tree, key, value, t.InsertWithPolicy)
+}
+
+// InsertWithPolicy adds a new key-value pair to the tree, resolving an existing equal key with policy
+// rather than with the tree's duplicate policy, and rebalancing the tree if a new node is inserted.
+//
+// See bst.Tree.InsertWithPolicy.
+func (t *Base[K, V, M]) InsertWithPolicy(key K, value V, policy bst.DuplicatePolicy) (*bst.Node[K, V, M], bool, error) {
+	if n, revived := t.revive(key, value); revived {
+		return n, true, nil
+	}
+	if t.strategy == TopDown {
+		return t.insertTopDown(key, value, policy)
+	}
+	n, inserted, err := t.tree.InsertWithPolicy(key, value, policy)
+	if !inserted {
+		return n, false, err
+	}
+	t.traceCase("insert", 0, false)
 	t.setColor(n, Red)
 
 	// Fixup after insertion
 	t.insertFixup(n)
 
-	t.size++
-	return n, true
+	return n, true, nil
 }
 
 // insertFixup performs recoloring/rotation of the red-black tree after an insertion takes place
@@ -335,44 +561,51 @@ func (t *Tree[K, V]) Insert(key K, value V) (*bst.Node[K, V, Color], bool) {
 //  3. Parent is red, uncle is black, and inserted node is a left child: Rotate right.
 //
 // The function also ensures that the root always remains black after insertion.
-func (t *Tree[K, V]) insertFixup(z *bst.Node[K, V, Color]) {
-	for t.isRed(t.Parent(z)) {
+func (t *Base[K, V, M]) insertFixup(z *bst.Node[K, V, M]) {
+	for t.IsRed(t.Parent(z)) {
 		if t.Parent(z) == t.Left(t.Parent(t.Parent(z))) { // If z's parent is a left child
 			y := t.Right(t.Parent(t.Parent(z))) // y is z's uncle
-			if t.isRed(y) {                     // Case 1: Parent & Uncle are Red
+			if t.IsRed(y) {                     // Case 1: Parent & Uncle are Red
+				t.traceCase("insert", 1, false)
 				t.setColor(t.Parent(z), Black)
 				t.setColor(y, Black)
 				t.setColor(t.Parent(t.Parent(z)), Red)
 				z = t.Parent(t.Parent(z))
 			} else {
 				if z == t.Right(t.Parent(z)) { // Case 2: z is a right child
+					t.traceCase("insert", 2, false)
 					z = t.Parent(z)
-					t.Tree.RotateLeft(z)
+					t.rotateLeft(z)
 				}
 				// Case 3: z is a left child
+				t.traceCase("insert", 3, false)
 				t.setColor(t.Parent(z), Black)
 				t.setColor(t.Parent(t.Parent(z)), Red)
-				t.Tree.RotateRight(t.Parent(t.Parent(z)))
+				t.rotateRight(t.Parent(t.Parent(z)))
 			}
 		} else {
 			// Mirror the logic with left/right swapped
 			y := t.Left(t.Parent(t.Parent(z)))
-			if t.isRed(y) {
+			if t.IsRed(y) {
+				t.traceCase("insert", 1, true)
 				t.setColor(t.Parent(z), Black)
 				t.setColor(y, Black)
 				t.setColor(t.Parent(t.Parent(z)), Red)
 				z = t.Parent(t.Parent(z))
 			} else {
 				if z == t.Left(t.Parent(z)) {
+					t.traceCase("insert", 2, true)
 					z = t.Parent(z)
-					t.Tree.RotateRight(z)
+					t.rotateRight(z)
 				}
+				t.traceCase("insert", 3, true)
 				t.setColor(t.Parent(z), Black)
 				t.setColor(t.Parent(t.Parent(z)), Red)
-				t.Tree.RotateLeft(t.Parent(t.Parent(z)))
+				t.rotateLeft(t.Parent(t.Parent(z)))
 			}
 		}
 	}
+	t.traceCase("insert", 0, false)
 	t.setColor(t.Root(), Black)
 }
 
@@ -381,148 +614,228 @@ func (t *Tree[K, V]) insertFixup(z *bst.Node[K, V, Color]) {
 // The function first validates the underlying BST structure, then applies the Red-Black Tree checks.
 //
 // This function checks the following five Red-Black Tree invariants:
-//  1. Every node is either red or black: Enforced by using `Color` as metadata, which by its nature can only have Red (false) or Black (true).
+//  1. Every node is either red or black: Enforced by recording a `Color` in metadata, which by its nature can only have Red (false) or Black (true).
 //  2. The root is always black: If the root is red, the tree is invalid.
 //  3. Every leaf (sentinel nil node) is black: Ensures correct tree termination.
 //  4. Red nodes cannot have red children: Prevents consecutive red nodes (ensures balancing).
 //  5. All paths from a node to its descendant leaves must have the same number of black nodes.
 //
+// The error returned wraps one of ErrRedRoot, ErrRedSentinel, ErrRedRedViolation or ErrBlackHeightMismatch,
+// or an error of the underlying BST (see bst.Tree.IsTreeValid), so that the kind of violation can be tested
+// with errors.Is.
+//
 // Returns:
 //   - nil if the tree is valid; or:
 //   - An error describing the first detected violation if the tree is invalid.
-func (t *Tree[K, V]) IsTreeValid() error {
+func (t *Base[K, V, M]) IsTreeValid() error {
 	var err error
 
 	// check underlying BST
-	err = t.Tree.IsTreeValid()
+	err = t.tree.IsTreeValid()
 	if err != nil {
-		return fmt.Errorf("underlying BST is invalid: %v", err)
+		return fmt.Errorf("underlying BST is invalid: %w", err)
 	}
 
 	// check the red-black tree invariants
 	// invariant 1: every node is either red or black.
-	// this invariant is enforced due to t.Tree's M being type Color.
+	// this invariant is enforced due to t.tree's M recording a Color.
 
 	// invariant 2: the root is black
-	if !t.isBlack(t.Root()) {
-		return fmt.Errorf("root node is not black")
+	if !t.IsBlack(t.Root()) {
+		return ErrRedRoot
 	}
 
 	// invariant 3: Every leaf (nil sentinel) is black.
-	if t.Metadata(t.Parent(t.Root())) != Black {
-		return fmt.Errorf("sentinel nil node is not black")
+	if t.color(t.Parent(t.Root())) != Black {
+		return ErrRedSentinel
 	}
 
-	firstLeaf := true
-	blackCount := 0
-
-	t.TraverseInOrder(t.Root(), func(n *bst.Node[K, V, Color]) bool {
-
-		// invariant 4: if a node is red, then both its children are black
-		if t.isRed(n) && t.isRed(t.Left(n)) {
-			err = fmt.Errorf("node %v is red and has red left child", t.Key(n))
-			return false
+	// walk the tree once, from the root down, tracking the number of black nodes above each node,
+	// so that the black heights of every path are compared in O(n) time
+	type frame struct {
+		n     *bst.Node[K, V, M]
+		black int // black nodes on the path from the root to n, exclusive of n
+	}
+	blackHeight := -1
+	stack := []frame{{t.Root(), 0}}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		n := f.n
+		if t.IsNil(n) {
+			continue
 		}
-		if t.isRed(n) && t.isRed(t.Right(n)) {
-			err = fmt.Errorf("node %v is red and has red right child", t.Key(n))
-			return false
+		if t.IsBlack(n) {
+			f.black++
 		}
+		for i, c := range [2]*bst.Node[K, V, M]{t.Right(n), t.Left(n)} {
 
-		// invariant 5: For each node, all simple paths from the node to descendant
-		// leaves contain the same number of black nodes.
-		if !(t.IsLeaf(n) || t.IsUnary(n)) {
-			return true // skip this check if not a leaf node
-		}
-		bc := 0
-		for !t.IsNil(n) {
-			if t.isBlack(n) {
-				bc++
+			// invariant 4: if a node is red, then both its children are black
+			if t.IsRed(n) && t.IsRed(c) {
+				return fmt.Errorf("%w: node %v is red and has red %s child", ErrRedRedViolation, t.Key(n), [2]string{"right", "left"}[i])
+			}
+
+			// invariant 5: For each node, all simple paths from the node to descendant
+			// leaves contain the same number of black nodes.
+			if !t.IsNil(c) {
+				stack = append(stack, frame{c, f.black})
+			} else if blackHeight < 0 {
+				blackHeight = f.black
+			} else if f.black != blackHeight {
+				return fmt.Errorf("%w: node %v has black count mismatch", ErrBlackHeightMismatch, t.Key(n))
 			}
-			n = t.Parent(n)
-		}
-		if firstLeaf {
-			blackCount = bc
-			firstLeaf = false
-			return true
-		}
-		if bc != blackCount {
-			err = fmt.Errorf("node %v has black count mismatch", t.Key(n))
-			return false
 		}
-		return true
-	})
-	if err != nil {
-		return err
 	}
 	return nil
 }
 
-// Deprecated: Should not be called on an rbtree.Tree, doing so may corrupt the tree.
-func (t *Tree[K, V]) MustSetMetadata() {
-	panic(fmt.Errorf("MustSetMetadata should not be called on an rbtree.Tree, doing so may corrupt the tree"))
+// RangeDelete removes every node whose key falls within the half-open interval [lo, hi),
+// maintaining Red-Black Tree properties after each removal.
+//
+// If hi is not greater than lo, no nodes are removed.
+//
+// Returns:
+//   - The number of nodes removed from the tree.
+func (t *Base[K, V, M]) RangeDelete(lo, hi K) int {
+	count := 0
+	n, found := t.Ceiling(lo)
+	for found && t.tree.Less(t.Key(n), hi) {
+		next := t.Successor(n)
+		t.Delete(n)
+		count++
+		n, found = next, !t.IsNil(next)
+	}
+	return count
 }
 
-// resetSentinelNodeProperties re-initializes the sentinel nil node to maintain Red-Black Tree invariants.
+// DeleteWhere removes every node whose key and value satisfy pred, maintaining Red-Black Tree properties.
 //
-// In a Red-Black Tree, the sentinel node serves as a placeholder for all nil references.
+// The tree is traversed once in order, and each matching node is removed as soon as the traversal has
+// moved past it (see Tree.AscendDelete), so no key is searched for and no nodes are collected beforehand.
+// The removal of a node performs at most three rotations, so purging k nodes runs in O(n + k log n) time.
+// pred must not modify the tree.
 //
-// This function ensures that the sentinel node:
-//   - Has no left or right children (nil pointers).
-//   - Has itself as its parent (ensuring a valid reference).
-//   - Is always Black (as required by Red-Black Tree rules).
+// Example Usage:
 //
-// This function should be called after deletions to prevent corruption of the sentinel node's state.
-func (t *Tree[K, V]) resetSentinelNodeProperties() {
-	t.Tree.SetLeft(t.Sentinel(), nil)
-	t.Tree.SetRight(t.Sentinel(), nil)
-	t.Tree.SetParent(t.Sentinel(), t.Sentinel())
-	t.setColor(t.Sentinel(), Black)
+//	// purge the sessions that have expired
+//	tree.DeleteWhere(func(id string, s *Session) bool {
+//		return s.Expires.Before(now)
+//	})
+//
+// Returns:
+//   - The number of nodes removed from the tree.
+func (t *Base[K, V, M]) DeleteWhere(pred func(key K, value V) bool) int {
+	return t.AscendDelete(func(n *bst.Node[K, V, M]) bst.Action {
+		if pred(n.Key(), n.Value()) {
+			return bst.ActionDelete
+		}
+		return bst.ActionContinue
+	})
+}
+
+// AscendDelete calls f for each node of the tree in ascending key order, deleting the nodes for
+// which f returns an Action including bst.ActionDelete (see bst.Tree.AscendDelete), maintaining Red-Black Tree properties after each removal.
+//
+// Returns:
+//   - The number of nodes deleted.
+func (t *Base[K, V, M]) AscendDelete(f func(n *bst.Node[K, V, M]) bst.Action) int {
+	t.Compact()
+	return bst.AscendDeleteFunc(t.tree, t.remove, f)
 }
 
-// Deprecated: Should not be called on an rbtree.Tree, doing so may corrupt the tree.
-func (t *Tree[K, V]) RotateLeft() {
-	panic(fmt.Errorf("RotateLeft should not be called on an rbtree.Tree, doing so may corrupt the tree"))
+// PopMin removes the node with the smallest key from the tree, and returns its key and value
+// (see bst.Tree.PopMin), maintaining Red-Black Tree properties after the removal.
+//
+// Returns:
+//   - (key, value, true) if the tree was not empty.
+//   - (zero key, zero value, false) if the tree is empty.
+func (t *Base[K, V, M]) PopMin() (K, V, bool) {
+	t.Compact()
+	return bst.PopMinFunc(t.tree, t.remove)
 }
 
-// Deprecated: Should not be called on an rbtree.Tree, doing so may corrupt the tree.
-func (t *Tree[K, V]) RotateRight() {
-	panic(fmt.Errorf("RotateRight should not be called on an rbtree.Tree, doing so may corrupt the tree"))
+// PopMax removes the node with the largest key from the tree, and returns its key and value
+// (see bst.Tree.PopMax), maintaining Red-Black Tree properties after the removal.
+//
+// Returns:
+//   - (key, value, true) if the tree was not empty.
+//   - (zero key, zero value, false) if the tree is empty.
+func (t *Base[K, V, M]) PopMax() (K, V, bool) {
+	t.Compact()
+	return bst.PopMaxFunc(t.tree, t.remove)
 }
 
-// Deprecated: Should not be called on an rbtree.Tree, doing so may corrupt the tree.
-func (t *Tree[K, V]) SetLeft() {
-	panic(fmt.Errorf("SetLeft should not be called on an rbtree.Tree, doing so may corrupt the tree"))
+// DeleteAt removes the node with zero-based rank i from the tree, and returns its key and value
+// (see bst.Tree.DeleteAt), maintaining Red-Black Tree properties after the removal.
+//
+// Returns:
+//   - (key, value, true) if 0 ≤ i < Tree.Size.
+//   - (zero key, zero value, false) if i is out of range.
+func (t *Base[K, V, M]) DeleteAt(i int) (K, V, bool) {
+	t.Compact()
+	return bst.DeleteAtFunc(t.tree, i, t.remove)
 }
 
-// Deprecated: Should not be called on an rbtree.Tree, doing so may corrupt the tree.
-func (t *Tree[K, V]) SetMetadata() {
-	panic(fmt.Errorf("SetMetadata should not be called on an rbtree.Tree, doing so may corrupt the tree"))
+// ApplyDelta replays the changes of d on the tree (see bst.Delta.Apply), maintaining Red-Black Tree properties as keys are inserted and deleted.
+func (t *Base[K, V, M]) ApplyDelta(d *bst.Delta[K, V]) {
+	d.Apply(func(key K, value V) {
+		t.InsertWithPolicy(key, value, bst.DuplicateReplace)
+	}, func(key K) {
+		if n, found := t.Search(key); found {
+			t.Delete(n)
+		}
+	}, t.Clear)
 }
 
-// Deprecated: Should not be called on an rbtree.Tree, doing so may corrupt the tree.
-func (t *Tree[K, V]) SetParent() {
-	panic(fmt.Errorf("SetParent should not be called on an rbtree.Tree, doing so may corrupt the tree"))
+// ApplyPatch applies p to the tree (see bst.Patch.Apply), maintaining Red-Black Tree properties as keys are inserted and deleted.
+func (t *Base[K, V, M]) ApplyPatch(p *bst.Patch[K, V]) {
+	p.Apply(func(key K, value V) {
+		t.InsertWithPolicy(key, value, bst.DuplicateReplace)
+	}, func(key K) {
+		if n, found := t.Search(key); found {
+			t.Delete(n)
+		}
+	})
 }
 
-// Deprecated: Should not be called on an rbtree.Tree, doing so may corrupt the tree.
-func (t *Tree[K, V]) SetRight() {
-	panic(fmt.Errorf("SetRight should not be called on an rbtree.Tree, doing so may corrupt the tree"))
+// Diff returns the patch turning tree a into tree b (see bst.Diff), which can be applied with Tree.ApplyPatch.
+//
+// If valueEq is nil, values are not compared, and only added and removed keys are reported.
+func Diff[K, V any, M ColorMetadata[M]](a, b *Base[K, V, M], valueEq func(a, b V) bool) *bst.Patch[K, V] {
+	return bst.Diff(a.tree, b.tree, valueEq)
 }
 
-// Size returns the total number of nodes in the Red-Black Tree.
+// resetSentinelNodeProperties re-initializes the sentinel nil node to maintain Red-Black Tree invariants.
 //
-// This function provides an O(1) operation to retrieve the node count, which is
-// maintained dynamically during insertions and deletions.
+// In a Red-Black Tree, the sentinel node serves as a placeholder for all nil references.
 //
-// Returns:
-//   - The number of nodes currently stored in the tree.
-func (t *Tree[K, V]) Size() int {
-	return t.size
+// This function ensures that the sentinel node:
+//   - Has no left or right children (nil pointers).
+//   - Has itself as its parent (ensuring a valid reference).
+//   - Is always Black (as required by Red-Black Tree rules).
+//
+// This function should be called after deletions to prevent corruption of the sentinel node's state.
+func (t *Base[K, V, M]) resetSentinelNodeProperties() {
+	t.tree.SetLeft(t.Sentinel(), nil)
+	t.tree.SetRight(t.Sentinel(), nil)
+	t.tree.SetParent(t.Sentinel(), t.Sentinel())
+	t.setColor(t.Sentinel(), Black)
 }
 
-// Deprecated: Should not be called on an rbtree.Tree, doing so may corrupt the tree.
-func (t *Tree[K, V]) Transplant() {
-	panic(fmt.Errorf("Transplant should not be called on an rbtree.Tree, doing so may corrupt the tree"))
+// transplant replaces the subtree rooted at u with the subtree rooted at v.
+//
+// Unlike bst.Tree.Transplant, the parent of v is always updated, even if v is the sentinel nil node,
+// as deleteFixup relies on the sentinel's parent pointer. The subtree rooted at v must be fully linked,
+// as bst.Tree.SetRoot recomputes the cached minimum and maximum from v if u is the root.
+func (t *Base[K, V, M]) transplant(u, v *bst.Node[K, V, M]) {
+	if t.IsNil(t.Parent(u)) {
+		t.tree.SetRoot(v)
+	} else if u == t.Left(t.Parent(u)) {
+		t.tree.SetLeft(t.Parent(u), v)
+	} else {
+		t.tree.SetRight(t.Parent(u), v)
+	}
+	t.tree.SetParent(v, t.Parent(u))
 }
 
 // New creates a new Red-Black Tree with the given key comparison function.
@@ -538,6 +851,7 @@ func (t *Tree[K, V]) Transplant() {
 //
 // Parameters:
 //   - less: A comparison function (bst.LessFunc[K]) that defines the ordering of keys.
+//   - opts: Optional behaviors to enable on the underlying bst.Tree (see bst.Option).
 //
 // Behavior:
 //   - Initializes an empty Red-Black Tree.
@@ -546,10 +860,38 @@ func (t *Tree[K, V]) Transplant() {
 //
 // Returns:
 //   - A pointer to a newly created Tree[K, V] instance.
-func New[K, V any](less bst.LessFunc[K]) *Tree[K, V] {
-	t := &Tree[K, V]{
-		Tree: bst.New[K, V, Color](less),
+//
+// New panics if opts include bst.WithAutoRebalance, as rebalancing would discard node colors.
+func New[K, V any](less bst.LessFunc[K], opts ...bst.Option) *Tree[K, V] {
+	return newBase[K, V, Color](less, opts...)
+}
+
+// newBase creates and returns a new empty Red-Black Tree with node metadata of type M (see New).
+func newBase[K, V any, M ColorMetadata[M]](less bst.LessFunc[K], opts ...bst.Option) *Base[K, V, M] {
+	t := &Base[K, V, M]{
+		tree: bst.New[K, V, M](less, opts...),
+		less: less,
+		opts: opts,
+	}
+	if t.tree.AutoRebalance() != 0 {
+		panic("rbtree: bst.WithAutoRebalance cannot be used with a Red-Black Tree, which balances itself")
 	}
-	t.Tree.MustSetMetadata(t.Root(), Black) // set sentinel nil to black
+	var sentinel M
+	t.tree.MustSetMetadata(t.Root(), sentinel.WithNodeColor(Black)) // set sentinel nil to black
 	return t
 }
+
+// NewOrdered creates a new Red-Black Tree whose keys are ordered with cmp.Less,
+// for keys of an ordered type such as int, string or float64.
+//
+// It is equivalent to New with cmp.Less[K] as the comparison function, which orders NaN keys
+// before every other key. Use New for keys of other types, or to order keys differently.
+//
+// Parameters:
+//   - opts: Optional behaviors to enable on the underlying bst.Tree (see bst.Option).
+//
+// Returns:
+//   - A pointer to a newly created Tree[K, V] instance.
+func NewOrdered[K cmp.Ordered, V any](opts ...bst.Option) *Tree[K, V] {
+	return New[K, V](cmp.Less[K], opts...)
+}
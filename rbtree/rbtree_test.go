@@ -1,9 +1,15 @@
 package rbtree
 
 import (
+	"bytes"
 	"fmt"
+	"github.com/mikenye/gotrees/bst"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 )
 
@@ -54,7 +60,7 @@ func FuzzTree(f *testing.F) {
 			}
 
 			// delete node
-			deleted := tree.Delete(n)
+			_, deleted := tree.Delete(n)
 			if !deleted && !alreadyDeleted {
 				// if node not deleted and hasn't already been deleted, something is wrong
 				t.Errorf("node %d not deleted", keys[i])
@@ -75,6 +81,41 @@ func FuzzTree(f *testing.F) {
 	})
 }
 
+func TestNewOrdered(t *testing.T) {
+	tree := NewOrdered[int, string]()
+	for i := 100; i > 0; i-- {
+		tree.Insert(i, fmt.Sprint(i))
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 1, tree.Key(tree.Min(tree.Root())))
+	assert.Equal(t, 100, tree.Key(tree.Max(tree.Root())))
+
+	multiset := NewOrdered[string, int](bst.WithDuplicateKeys())
+	multiset.Insert("a", 1)
+	multiset.Insert("a", 2)
+	assert.Equal(t, 2, multiset.Count("a"))
+}
+
+func TestNew_autoRebalance(t *testing.T) {
+	assert.Panics(t, func() {
+		New[int, int](func(a, b int) bool { return a < b }, bst.WithAutoRebalance(2))
+	}, "expected rebalancing to be rejected, as it would discard node colors")
+	assert.Panics(t, func() {
+		NewAux[int, int, string](func(a, b int) bool { return a < b }, bst.WithAutoRebalance(2))
+	})
+
+	// degradation reports are harmless, and sorted insertions never degrade the tree
+	reported := 0
+	tree := New[int, int](func(a, b int) bool { return a < b }, bst.WithDegradationFunc(2, func(depth, size int) {
+		reported++
+	}))
+	for i := 0; i < 200; i++ {
+		tree.Insert(i, i)
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Zero(t, reported)
+}
+
 func TestTree_Delete(t *testing.T) {
 	// todo: add structure checks
 	tests := map[string]struct {
@@ -85,9 +126,9 @@ func TestTree_Delete(t *testing.T) {
 		"nil node": {
 			keys: []int{20, 10, 30},
 			deletion: func(t *testing.T, tree *Tree[int, struct{}]) {
-				deleted := tree.Delete(nil)
+				_, deleted := tree.Delete(nil)
 				require.False(t, deleted, "expected nil node to not be deleted")
-				deleted = tree.Delete(tree.Sentinel())
+				_, deleted = tree.Delete(tree.Sentinel())
 				require.False(t, deleted, "expected nil node to not be deleted")
 			},
 			checks: func(t *testing.T, tree *Tree[int, struct{}]) {
@@ -101,7 +142,7 @@ func TestTree_Delete(t *testing.T) {
 			keys: []int{14, 11, 69, 3, 12, 50, 82, 1, 4, 77},
 			deletion: func(t *testing.T, tree *Tree[int, struct{}]) {
 				n1, _ := tree.Search(1)
-				ok := tree.Delete(n1)
+				_, ok := tree.Delete(n1)
 				require.True(t, ok)
 			},
 			checks: func(t *testing.T, tree *Tree[int, struct{}]) {
@@ -120,7 +161,7 @@ func TestTree_Delete(t *testing.T) {
 				tree.Delete(n1)
 				// no assertions for above deletions as this follows on from previous case(s) above
 				n11, _ := tree.Search(11)
-				ok := tree.Delete(n11)
+				_, ok := tree.Delete(n11)
 				require.True(t, ok)
 			},
 			checks: func(t *testing.T, tree *Tree[int, struct{}]) {
@@ -147,7 +188,7 @@ func TestTree_Delete(t *testing.T) {
 				tree.Delete(n11)
 				// no assertions for above deletions as this follows on from previous case(s) above
 				n12, _ := tree.Search(12)
-				ok := tree.Delete(n12)
+				_, ok := tree.Delete(n12)
 				require.True(t, ok)
 			},
 			checks: func(t *testing.T, tree *Tree[int, struct{}]) {
@@ -173,7 +214,7 @@ func TestTree_Delete(t *testing.T) {
 				tree.Delete(n12)
 				// no assertions for above deletions as this follows on from previous case(s) above
 				n69, _ := tree.Search(69)
-				ok := tree.Delete(n69)
+				_, ok := tree.Delete(n69)
 				require.True(t, ok)
 			},
 			checks: func(t *testing.T, tree *Tree[int, struct{}]) {
@@ -204,7 +245,7 @@ func TestTree_Delete(t *testing.T) {
 				tree.Delete(n69)
 				// no assertions for above deletions as this follows on from previous case(s) above
 				n4, _ := tree.Search(4)
-				ok := tree.Delete(n4)
+				_, ok := tree.Delete(n4)
 				require.True(t, ok)
 			},
 			checks: func(t *testing.T, tree *Tree[int, struct{}]) {
@@ -230,7 +271,7 @@ func TestTree_Delete(t *testing.T) {
 				tree.Delete(n4)
 				// no assertions for above deletions as this follows on from previous case(s) above
 				n14, _ := tree.Search(14)
-				ok := tree.Delete(n14)
+				_, ok := tree.Delete(n14)
 				require.True(t, ok)
 			},
 			checks: func(t *testing.T, tree *Tree[int, struct{}]) {
@@ -268,7 +309,7 @@ func TestTree_Delete(t *testing.T) {
 				tree.Delete(n14)
 				// no assertions for above deletions as this follows on from previous case(s) above
 				n82, _ := tree.Search(82)
-				ok := tree.Delete(n82)
+				_, ok := tree.Delete(n82)
 				require.True(t, ok)
 			},
 			checks: func(t *testing.T, tree *Tree[int, struct{}]) {
@@ -304,7 +345,7 @@ func TestTree_Delete(t *testing.T) {
 				tree.Delete(n82)
 				// no assertions for above deletions as this follows on from previous case(s) above
 				n50, _ := tree.Search(50)
-				ok := tree.Delete(n50)
+				_, ok := tree.Delete(n50)
 				require.True(t, ok)
 			},
 			checks: func(t *testing.T, tree *Tree[int, struct{}]) {
@@ -339,7 +380,7 @@ func TestTree_Delete(t *testing.T) {
 				tree.Delete(n50)
 				// no assertions for above deletions as this follows on from previous case(s) above
 				n77, _ := tree.Search(77)
-				ok := tree.Delete(n77)
+				_, ok := tree.Delete(n77)
 				require.True(t, ok)
 			},
 			checks: func(t *testing.T, tree *Tree[int, struct{}]) {
@@ -372,7 +413,7 @@ func TestTree_Delete(t *testing.T) {
 				tree.Delete(n77)
 				// no assertions for above deletions as this follows on from previous case(s) above
 				n3, _ := tree.Search(3)
-				ok := tree.Delete(n3)
+				_, ok := tree.Delete(n3)
 				require.True(t, ok)
 			},
 			checks: func(t *testing.T, tree *Tree[int, struct{}]) {
@@ -470,6 +511,63 @@ func TestTree_Insert_update(t *testing.T) {
 	assert.Equal(t, "updated", tree.Value(n4))
 }
 
+func TestTree_duplicatePolicy(t *testing.T) {
+	for _, strategy := range []Strategy{BottomUp, TopDown} {
+		tree := New[int, string](func(a, b int) bool { return a < b }, bst.WithDuplicatePolicy(bst.DuplicateError))
+		tree.SetStrategy(strategy)
+		for i := range 100 {
+			tree.Insert(i, "first")
+		}
+
+		n, inserted, err := tree.TryInsert(50, "second")
+		assert.ErrorIs(t, err, bst.ErrDuplicateKey, "%v: expected the duplicate to be rejected", strategy)
+		assert.False(t, inserted)
+		assert.Equal(t, "first", tree.Value(n))
+		assert.Panics(t, func() { tree.Insert(50, "second") }, "%v: expected Insert to panic", strategy)
+
+		_, inserted = tree.InsertIfAbsent(50, "second")
+		assert.False(t, inserted)
+		_, inserted = tree.InsertIfAbsent(100, "second")
+		assert.True(t, inserted)
+		assert.Equal(t, bst.DuplicateError, tree.DuplicatePolicy())
+		require.NoError(t, tree.IsTreeValid(), "%v", strategy)
+		assert.Equal(t, 101, tree.Size())
+	}
+}
+
+func TestTree_InsertReturningOld(t *testing.T) {
+	for _, strategy := range []Strategy{BottomUp, TopDown} {
+		tree := New[int, int](func(a, b int) bool { return a < b })
+		tree.SetStrategy(strategy)
+		total := 0
+		for i := range 1000 {
+			key := i % 100
+			if old, existed := tree.InsertReturningOld(key, i); existed {
+				total -= old
+			}
+			total += i
+		}
+		sum := 0
+		for value := range tree.Values() {
+			sum += value
+		}
+		assert.Equal(t, sum, total, "%v: expected the displaced values to be returned", strategy)
+		require.NoError(t, tree.IsTreeValid(), "%v", strategy)
+	}
+}
+
+func TestTree_InsertIfAbsent_tombstone(t *testing.T) {
+	tree := New[int, string](func(a, b int) bool { return a < b })
+	tree.SetLazyDeletion(1)
+	n, _ := tree.Insert(1, "first")
+	tree.Delete(n)
+
+	revived, inserted := tree.InsertIfAbsent(1, "second")
+	assert.True(t, inserted, "expected a key deleted lazily to be absent")
+	assert.Equal(t, "second", tree.Value(revived))
+	require.NoError(t, tree.IsTreeValid())
+}
+
 func TestTree_IsTreeValid(t *testing.T) {
 	tests := map[string]struct {
 		creation func() *Tree[int, struct{}]
@@ -502,10 +600,10 @@ func TestTree_IsTreeValid(t *testing.T) {
 				return tree
 			},
 			mutation: func(tree *Tree[int, struct{}]) {
-				tree.Tree.MustSetMetadata(tree.Root(), Red)
+				tree.tree.MustSetMetadata(tree.Root(), Red)
 			},
 			checks: func(t *testing.T, tree *Tree[int, struct{}]) {
-				assert.Error(t, tree.IsTreeValid(), "expected invalid tree")
+				assert.ErrorIs(t, tree.IsTreeValid(), ErrRedRoot, "expected invalid tree")
 			},
 		},
 		"nil leaf nodes are not black": {
@@ -515,10 +613,10 @@ func TestTree_IsTreeValid(t *testing.T) {
 				return tree
 			},
 			mutation: func(tree *Tree[int, struct{}]) {
-				tree.Tree.MustSetMetadata(tree.Left(tree.Root()), Red)
+				tree.tree.MustSetMetadata(tree.Left(tree.Root()), Red)
 			},
 			checks: func(t *testing.T, tree *Tree[int, struct{}]) {
-				assert.Error(t, tree.IsTreeValid(), "expected invalid tree")
+				assert.ErrorIs(t, tree.IsTreeValid(), ErrRedSentinel, "expected invalid tree")
 			},
 		},
 		"node is red and has red left child": {
@@ -532,12 +630,12 @@ func TestTree_IsTreeValid(t *testing.T) {
 			},
 			mutation: func(tree *Tree[int, struct{}]) {
 				n, _ := tree.Search(5)
-				tree.Tree.MustSetMetadata(n, Red)
+				tree.tree.MustSetMetadata(n, Red)
 				n, _ = tree.Search(15)
-				tree.Tree.MustSetMetadata(n, Red)
+				tree.tree.MustSetMetadata(n, Red)
 			},
 			checks: func(t *testing.T, tree *Tree[int, struct{}]) {
-				assert.Error(t, tree.IsTreeValid(), "expected invalid tree")
+				assert.ErrorIs(t, tree.IsTreeValid(), ErrRedRedViolation, "expected invalid tree")
 			},
 		},
 		"node is red and has red right child": {
@@ -551,12 +649,12 @@ func TestTree_IsTreeValid(t *testing.T) {
 			},
 			mutation: func(tree *Tree[int, struct{}]) {
 				n, _ := tree.Search(5)
-				tree.Tree.MustSetMetadata(n, Red)
+				tree.tree.MustSetMetadata(n, Red)
 				n, _ = tree.Search(15)
-				tree.Tree.MustSetMetadata(n, Red)
+				tree.tree.MustSetMetadata(n, Red)
 			},
 			checks: func(t *testing.T, tree *Tree[int, struct{}]) {
-				assert.Error(t, tree.IsTreeValid(), "expected invalid tree")
+				assert.ErrorIs(t, tree.IsTreeValid(), ErrRedRedViolation, "expected invalid tree")
 			},
 		},
 		"node has black count mismatch": {
@@ -570,10 +668,10 @@ func TestTree_IsTreeValid(t *testing.T) {
 			},
 			mutation: func(tree *Tree[int, struct{}]) {
 				n, _ := tree.Search(14)
-				tree.Tree.MustSetMetadata(n, Black)
+				tree.tree.MustSetMetadata(n, Black)
 			},
 			checks: func(t *testing.T, tree *Tree[int, struct{}]) {
-				assert.Error(t, tree.IsTreeValid(), "expected invalid tree")
+				assert.ErrorIs(t, tree.IsTreeValid(), ErrBlackHeightMismatch, "expected invalid tree")
 			},
 		},
 	}
@@ -591,32 +689,59 @@ func TestTree_IsTreeValid(t *testing.T) {
 	}
 }
 
-func TestTree_panics(t *testing.T) {
+func TestTree_unsafeMethodsUnavailable(t *testing.T) {
+	// the methods of bst.Tree that may corrupt a Red-Black Tree are not part of its API
 	tree := New[int, struct{}](func(a, b int) bool { return a < b })
-	assert.Panics(t, func() {
-		tree.MustSetMetadata()
-	})
-	assert.Panics(t, func() {
-		tree.SetMetadata()
-	})
-	assert.Panics(t, func() {
-		tree.RotateLeft()
-	})
-	assert.Panics(t, func() {
-		tree.RotateRight()
-	})
-	assert.Panics(t, func() {
-		tree.SetLeft()
-	})
-	assert.Panics(t, func() {
-		tree.SetParent()
-	})
-	assert.Panics(t, func() {
-		tree.SetRight()
-	})
-	assert.Panics(t, func() {
-		tree.Transplant()
-	})
+	for _, name := range []string{
+		"AttachSubtree", "DetachSubtree", "InsertAt", "LoadSortedFunc", "MustSetMetadata", "Rebalance", "RebuildSubtree", "RefreshPath", "Release", "Relink",
+		"RotateLeft", "RotateRight", "SetHooks", "SetKey", "SetLeft", "SetMetadata", "SetParent", "SetRight", "SetRoot", "Transplant",
+	} {
+		_, found := reflect.TypeOf(tree).MethodByName(name)
+		assert.False(t, found, "expected %s not to be available", name)
+	}
+}
+
+func TestTree_FloorCeilingLowerHigher(t *testing.T) {
+	tree := New[int, struct{}](func(a, b int) bool { return a < b })
+	rng := rand.New(rand.NewSource(1))
+	var keys []int // keys of the tree, in ascending order
+	for len(keys) < 200 {
+		k := rng.Intn(1000)
+		if _, inserted := tree.Insert(k, struct{}{}); inserted {
+			keys = append(keys, k)
+		}
+	}
+	sort.Ints(keys)
+
+	// check returns the expected key at index i of keys, if any
+	check := func(name string, key int, n *bst.Node[int, struct{}, Color], found bool, i int) {
+		if i < 0 || i >= len(keys) {
+			assert.False(t, found, "expected %s(%d) not to be found", name, key)
+			assert.True(t, tree.IsNil(n), "expected %s(%d) to return the nil node", name, key)
+			return
+		}
+		require.True(t, found, "expected %s(%d) to be found", name, key)
+		assert.Equal(t, keys[i], tree.Key(n), "unexpected %s(%d)", name, key)
+	}
+	for key := -1; key <= 1000; key++ {
+		i := sort.SearchInts(keys, key) // first index with keys[i] >= key
+		exact := i < len(keys) && keys[i] == key
+		n, found := tree.Ceiling(key)
+		check("Ceiling", key, n, found, i)
+		n, found = tree.Lower(key)
+		check("Lower", key, n, found, i-1)
+		if exact {
+			n, found = tree.Floor(key)
+			check("Floor", key, n, found, i)
+			n, found = tree.Higher(key)
+			check("Higher", key, n, found, i+1)
+		} else {
+			n, found = tree.Floor(key)
+			check("Floor", key, n, found, i-1)
+			n, found = tree.Higher(key)
+			check("Higher", key, n, found, i)
+		}
+	}
 }
 
 func TestTree_Size(t *testing.T) {
@@ -628,3 +753,628 @@ func TestTree_Size(t *testing.T) {
 	tree.Insert(14, struct{}{})
 	assert.Equal(t, 4, tree.Size(), "expected 4 nodes in tree")
 }
+
+func TestTree_RangeDelete(t *testing.T) {
+	tree := New[int, int](func(a, b int) bool { return a < b })
+	for i := 0; i < 100; i++ {
+		tree.Insert(i, i*10)
+	}
+
+	count := tree.RangeDelete(20, 70)
+	assert.Equal(t, 50, count, "unexpected number of deleted nodes")
+	assert.Equal(t, 50, tree.Size(), "unexpected size after RangeDelete")
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+
+	for i := 0; i < 100; i++ {
+		n, found := tree.Search(i)
+		if i >= 20 && i < 70 {
+			assert.False(t, found, "expected key %d to be deleted", i)
+		} else {
+			require.True(t, found, "expected key %d to remain", i)
+			assert.Equal(t, i*10, tree.Value(n), "unexpected value for key %d", i)
+		}
+	}
+
+	// empty range removes nothing
+	assert.Equal(t, 0, tree.RangeDelete(80, 80))
+	assert.Equal(t, 50, tree.Size(), "unexpected size after empty RangeDelete")
+
+	// remaining keys
+	assert.Equal(t, 50, tree.RangeDelete(-10, 1000))
+	assert.Equal(t, 0, tree.Size(), "expected empty tree")
+	assert.True(t, tree.IsNil(tree.Root()), "expected nil root")
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+}
+
+func TestTree_AscendDelete(t *testing.T) {
+	tree := New[int, int](func(a, b int) bool { return a < b })
+	for i := 0; i < 100; i++ {
+		tree.Insert(i, i*10)
+	}
+
+	// delete even keys, stopping after key 79
+	count := tree.AscendDelete(func(n *bst.Node[int, int, Color]) bst.Action {
+		action := bst.ActionContinue
+		if n.Key()%2 == 0 {
+			action |= bst.ActionDelete
+		}
+		if n.Key() == 79 {
+			action |= bst.ActionStop
+		}
+		return action
+	})
+	assert.Equal(t, 40, count, "unexpected number of deleted nodes")
+	assert.Equal(t, 60, tree.Size(), "unexpected size after AscendDelete")
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+
+	for i := 0; i < 100; i++ {
+		_, found := tree.Search(i)
+		assert.Equal(t, i%2 == 1 || i >= 80, found, "unexpected presence of key %d", i)
+	}
+}
+
+func TestTree_PopMin(t *testing.T) {
+	tree := New[int, int](func(a, b int) bool { return a < b })
+	for i := 0; i < 100; i++ {
+		tree.Insert(i, i*10)
+	}
+	for i := 0; i < 50; i++ {
+		first, firstValue, _ := tree.FirstEntry()
+		last, _ := tree.LastKey()
+		key, value, ok := tree.PopMin()
+		require.True(t, ok)
+		assert.Equal(t, i, key)
+		assert.Equal(t, i*10, value)
+		assert.Equal(t, key, first, "expected FirstEntry to peek at the minimum")
+		assert.Equal(t, value, firstValue)
+		key, _, ok = tree.PopMax()
+		assert.Equal(t, key, last, "expected LastKey to peek at the maximum")
+		require.True(t, ok)
+		assert.Equal(t, 99-i, key)
+		require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	}
+	_, _, ok := tree.PopMin()
+	assert.False(t, ok, "expected empty tree to have no minimum")
+	_, _, ok = tree.LastEntry()
+	assert.False(t, ok, "expected empty tree to have no maximum")
+	_, ok = tree.FirstKey()
+	assert.False(t, ok, "expected empty tree to have no minimum")
+}
+
+func TestTree_SliceByRank(t *testing.T) {
+	tree := New[int, int](func(a, b int) bool { return a < b })
+	for _, key := range rand.New(rand.NewSource(1)).Perm(1000) {
+		tree.Insert(key, key)
+	}
+	page := tree.SliceByRank(500, 510)
+	require.Len(t, page, 10)
+	for i, n := range page {
+		assert.Equal(t, 500+i, tree.Key(n), "unexpected key at rank %d", 500+i)
+	}
+
+	count := 0
+	tree.AscendAt(990, func(n *bst.Node[int, int, Color]) bool {
+		assert.Equal(t, 990+count, tree.Key(n))
+		count++
+		return true
+	})
+	assert.Equal(t, 10, count, "expected AscendAt to visit the last 10 nodes")
+}
+
+func TestTree_Delete_root_minMax(t *testing.T) {
+	for _, strategy := range []Strategy{BottomUp, TopDown} {
+		t.Run(strategy.String(), func(t *testing.T) {
+			tree := New[int, int](func(a, b int) bool { return a < b })
+			tree.SetStrategy(strategy)
+			for _, key := range rand.New(rand.NewSource(1)).Perm(100) {
+				tree.Insert(key, key)
+			}
+
+			// deleting the root relinks its successor at the top of the tree
+			for tree.Size() > 1 {
+				tree.Delete(tree.Root())
+				lo, hi := tree.Root(), tree.Root()
+				for !tree.IsNil(tree.Left(lo)) {
+					lo = tree.Left(lo)
+				}
+				for !tree.IsNil(tree.Right(hi)) {
+					hi = tree.Right(hi)
+				}
+				require.Equal(t, lo, tree.Min(tree.Root()), "stale cached minimum")
+				require.Equal(t, hi, tree.Max(tree.Root()), "stale cached maximum")
+			}
+		})
+	}
+}
+
+func TestTree_DeleteAt(t *testing.T) {
+	tree := New[int, int](func(a, b int) bool { return a < b })
+	tree.SetLazyDeletion(0.5)
+	for i := 0; i < 100; i++ {
+		tree.Insert(i, i)
+	}
+	n, _ := tree.Search(0)
+	tree.Delete(n) // deleted lazily, so not counted by rank
+
+	// a sliding window evicting by position
+	for i := 0; i < 50; i++ {
+		key, value, ok := tree.DeleteAt(tree.Size() - 1)
+		require.True(t, ok)
+		assert.Equal(t, 99-i, key)
+		assert.Equal(t, key, value)
+		require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	}
+	key, _, ok := tree.DeleteAt(0)
+	require.True(t, ok)
+	assert.Equal(t, 1, key, "expected the node deleted lazily to have no rank")
+	_, _, ok = tree.DeleteAt(-1)
+	assert.False(t, ok, "expected out of range rank to delete nothing")
+	assert.Equal(t, 48, tree.Size())
+}
+
+func TestTree_OnChange(t *testing.T) {
+	tree := New[int, int](func(a, b int) bool { return a < b })
+	mirror := make(map[int]int)
+	tree.OnChange(func(op bst.ChangeOp, key int, value int) {
+		if op == bst.ChangeDelete {
+			delete(mirror, key)
+		} else {
+			mirror[key] = value
+		}
+	})
+	for i := 0; i < 100; i++ {
+		tree.Insert(i, i)
+	}
+	tree.Insert(50, -50)
+	for i := 0; i < 100; i += 3 {
+		n, _ := tree.Search(i)
+		_, deleted := tree.Delete(n)
+		require.True(t, deleted)
+	}
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+
+	assert.Equal(t, tree.Size(), len(mirror))
+	for key, value := range mirror {
+		n, found := tree.Search(key)
+		require.True(t, found, "unexpected key %d in mirror", key)
+		assert.Equal(t, tree.Value(n), value)
+	}
+	assert.Equal(t, -50, mirror[50])
+}
+
+func TestTree_DeltaSince(t *testing.T) {
+	leader := New[int, int](func(a, b int) bool { return a < b }, bst.WithDeltaLog(1000))
+	for i := 0; i < 100; i++ {
+		leader.Insert(i, i)
+	}
+
+	var snapshot bytes.Buffer
+	require.NoError(t, leader.SnapshotTo(&snapshot))
+	follower := New[int, int](func(a, b int) bool { return a < b })
+	version, err := follower.ReadSnapshot(&snapshot)
+	require.NoError(t, err)
+	require.NoError(t, follower.IsTreeValid(), "expected valid tree after reading the snapshot")
+
+	for i := 0; i < 100; i += 3 {
+		n, _ := leader.Search(i)
+		_, deleted := leader.Delete(n)
+		require.True(t, deleted)
+	}
+	leader.Insert(50, -50)
+	leader.Insert(200, 200)
+
+	delta, err := leader.DeltaSince(version)
+	require.NoError(t, err)
+	follower.ApplyDelta(delta)
+	require.NoError(t, follower.IsTreeValid(), "expected valid tree after applying the delta")
+	assert.True(t, leader.Equal(follower, func(a, b int) bool { return a == b }))
+}
+
+func TestTree_ApplyPatch(t *testing.T) {
+	a := New[int, int](func(a, b int) bool { return a < b })
+	b := New[int, int](func(a, b int) bool { return a < b })
+	for i := 0; i < 100; i++ {
+		a.Insert(i, i)
+		b.Insert(i+50, -i)
+	}
+	valueEq := func(a, b int) bool { return a == b }
+	a.ApplyPatch(Diff(a, b, valueEq))
+	require.NoError(t, a.IsTreeValid(), "expected valid tree after applying the patch")
+	assert.True(t, a.Equal(b, valueEq))
+}
+
+func TestTree_BlackHeight(t *testing.T) {
+	tree := New[int, int](func(a, b int) bool { return a < b })
+	assert.Equal(t, 0, tree.BlackHeight(), "expected empty tree to have black height 0")
+	tree.Insert(1, 1)
+	assert.Equal(t, 1, tree.BlackHeight())
+
+	for i := 2; i <= 1000; i++ {
+		tree.Insert(i, i)
+
+		// every path from the root to a leaf holds the same number of black nodes
+		h := 0
+		for n := tree.Root(); !tree.IsNil(n); n = tree.Right(n) {
+			if tree.IsBlack(n) {
+				h++
+			}
+		}
+		require.Equal(t, h, tree.BlackHeight(), "unexpected black height with %d nodes", i)
+		require.LessOrEqual(t, 1<<tree.BlackHeight()-1, i, "a tree of black height h holds at least 2^h-1 nodes")
+	}
+}
+
+func TestTree_IsRed_IsBlack(t *testing.T) {
+	tree := New[int, int](func(a, b int) bool { return a < b })
+	for i := 0; i < 10; i++ {
+		tree.Insert(i, i)
+	}
+	assert.True(t, tree.IsBlack(tree.Root()), "expected black root")
+	assert.False(t, tree.IsRed(tree.Root()), "expected black root")
+	assert.True(t, tree.IsBlack(tree.Parent(tree.Root())), "expected black sentinel")
+	assert.False(t, tree.IsRed(tree.Parent(tree.Root())), "expected black sentinel")
+
+	tree.TraverseInOrder(tree.Root(), func(n *bst.Node[int, int, Color]) bool {
+		assert.NotEqual(t, tree.IsRed(n), tree.IsBlack(n), "expected node %d to be either red or black", n.Key())
+		assert.Equal(t, tree.Metadata(n) == Red, tree.IsRed(n))
+		return true
+	})
+}
+
+func TestTree_Equal(t *testing.T) {
+	a := New[int, int](func(a, b int) bool { return a < b })
+	b := New[int, int](func(a, b int) bool { return a < b })
+	for i := 0; i < 20; i++ {
+		a.Insert(i, i)
+		b.Insert(19-i, 19-i)
+	}
+	valueEq := func(a, b int) bool { return a == b }
+	assert.True(t, a.Equal(b, valueEq), "expected trees with same contents to be equal")
+
+	n, _ := b.Search(7)
+	b.SetValue(n, 70)
+	assert.False(t, a.Equal(b, valueEq), "expected trees with different values not to be equal")
+	assert.True(t, a.Equal(b, nil), "expected nil valueEq to ignore values")
+
+	c := New[int, int](func(a, b int) bool { return a < b })
+	for i := 0; i < 20; i++ {
+		c.Insert(i, i)
+	}
+	assert.True(t, a.EqualStructure(c, valueEq), "expected trees built identically to be structurally equal")
+}
+
+func TestTree_Rank_Select(t *testing.T) {
+	tree := New[int, struct{}](func(a, b int) bool { return a < b })
+	for i := 0; i < 200; i++ {
+		tree.Insert((i*37)%200, struct{}{})
+	}
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+
+	// delete all multiples of 3
+	for i := 0; i < 200; i += 3 {
+		n, _ := tree.Search(i)
+		tree.Delete(n)
+	}
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+
+	rank := 0
+	for i := 0; i < 200; i++ {
+		if i%3 == 0 {
+			continue
+		}
+		assert.Equal(t, rank, tree.Rank(i), "unexpected rank for key %d", i)
+		n, found := tree.Select(rank)
+		require.True(t, found, "expected Select(%d) to succeed", rank)
+		assert.Equal(t, i, tree.Key(n), "unexpected key from Select(%d)", rank)
+		rank++
+	}
+	assert.Equal(t, rank, tree.Size(), "unexpected size")
+	assert.Equal(t, 10, tree.CountRange(0, 15), "unexpected CountRange")
+}
+
+func TestTree_KthSmallest_KthLargest(t *testing.T) {
+	tree := New[int, struct{}](func(a, b int) bool { return a < b })
+	for i := 1; i <= 100; i++ {
+		tree.Insert(i, struct{}{})
+	}
+	for k := 1; k <= 100; k++ {
+		n, found := tree.KthSmallest(k)
+		require.True(t, found)
+		assert.Equal(t, k, tree.Key(n), "unexpected KthSmallest(%d)", k)
+		n, found = tree.KthLargest(k)
+		require.True(t, found)
+		assert.Equal(t, 101-k, tree.Key(n), "unexpected KthLargest(%d)", k)
+	}
+	_, found := tree.KthSmallest(101)
+	assert.False(t, found, "expected KthSmallest out of range to fail")
+}
+
+func TestTree_WithDuplicateKeys(t *testing.T) {
+	tree := New[int, int](func(a, b int) bool { return a < b }, bst.WithDuplicateKeys())
+	for i := 0; i < 300; i++ {
+		_, inserted := tree.Insert(i%10, i)
+		require.True(t, inserted, "expected duplicate key to be inserted")
+	}
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	assert.Equal(t, 300, tree.Size(), "unexpected size")
+	for k := 0; k < 10; k++ {
+		assert.Equal(t, 30, tree.Count(k), "unexpected count for key %d", k)
+	}
+
+	// equal keys remain in insertion order after rebalancing
+	last := make(map[int]int)
+	tree.TraverseInOrder(tree.Root(), func(n *bst.Node[int, int, Color]) bool {
+		if v, ok := last[tree.Key(n)]; ok {
+			assert.Less(t, v, tree.Value(n), "expected insertion order among equal keys")
+		}
+		last[tree.Key(n)] = tree.Value(n)
+		return true
+	})
+
+	// delete the first instance of each key until empty
+	for tree.Size() > 0 {
+		n, found := tree.Search(tree.Key(tree.Min(tree.Root())))
+		require.True(t, found)
+		_, deleted := tree.Delete(n)
+		require.True(t, deleted)
+		require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	}
+}
+
+func TestTree_WithReverseOrder(t *testing.T) {
+	tree := NewOrdered[int, int](bst.WithReverseOrder())
+	for i := 0; i < 100; i++ {
+		tree.Insert(i, i)
+	}
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	assert.Equal(t, 99, tree.Key(tree.Min(tree.Root())))
+
+	// keys k such that 20 < k <= 50, in the tree's order
+	assert.Equal(t, 30, tree.RangeDelete(50, 20))
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	_, found := tree.Search(50)
+	assert.False(t, found)
+	_, found = tree.Search(20)
+	assert.True(t, found)
+}
+
+func TestTree_Cursor(t *testing.T) {
+	tree := NewOrdered[int, int]()
+	for i := 0; i < 1000; i++ {
+		tree.Insert(i, i)
+	}
+
+	// rebalancing while deleting and inserting does not disturb the cursor
+	var visited []int
+	for c := tree.Cursor(); c.Next(); {
+		visited = append(visited, c.Key())
+		if c.Key() >= 1000 {
+			continue
+		}
+		tree.Delete(c.Node())
+		n, _ := tree.Search(c.Key() + 1)
+		tree.Delete(n)
+		tree.Insert(c.Key()+1000, 0)
+		require.NoError(t, tree.IsTreeValid())
+	}
+	require.Len(t, visited, 1000)
+	for i, key := range visited {
+		assert.Equal(t, i*2, key)
+	}
+	assert.Equal(t, 500, tree.Size())
+}
+
+func TestTree_Delete_staleAndForeignNodes(t *testing.T) {
+	tree := New[int, int](func(a, b int) bool { return a < b })
+	other := New[int, int](func(a, b int) bool { return a < b })
+	nodes := make(map[int]*bst.Node[int, int, Color])
+	for i := 0; i < 20; i++ {
+		nodes[i], _ = tree.Insert(i, i)
+		other.Insert(i, i)
+	}
+
+	// deleting a node with two children keeps handles to other nodes valid
+	root := tree.Root()
+	rootKey := tree.Key(root)
+	require.False(t, tree.IsNil(tree.Left(root)) || tree.IsNil(tree.Right(root)), "expected root with two children")
+	value, deleted := tree.Delete(root)
+	require.True(t, deleted)
+	assert.Equal(t, rootKey, value, "expected the deleted node's value to be returned")
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	assert.False(t, tree.Contains(root), "expected deleted node not to be contained")
+	for k, n := range nodes {
+		if k == rootKey {
+			continue
+		}
+		assert.True(t, tree.Contains(n), "expected node %d to remain valid", k)
+		assert.Equal(t, k, tree.Key(n), "expected node %d to keep its key", k)
+		assert.Equal(t, k, tree.Value(n), "expected node %d to keep its value", k)
+	}
+
+	// stale and foreign nodes are not deleted
+	value, deleted = tree.Delete(root)
+	assert.False(t, deleted, "expected stale node not to be deleted")
+	assert.Zero(t, value, "expected zero value for stale node")
+	foreign, _ := other.Search(5)
+	_, deleted = tree.Delete(foreign)
+	assert.False(t, deleted, "expected foreign node not to be deleted")
+	assert.Equal(t, 19, tree.Size(), "expected tree to be unchanged")
+	assert.Equal(t, 20, other.Size(), "expected other tree to be unchanged")
+	require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	require.NoError(t, other.IsTreeValid(), "expected valid tree")
+}
+
+func TestTree_SetAugmentFunc(t *testing.T) {
+	// values hold a payload and the maximum payload in the node's subtree,
+	// as rbtree uses the node metadata for colors
+	type entry struct {
+		payload, max int
+	}
+	tree := New[int, entry](func(a, b int) bool { return a < b })
+	tree.SetAugmentFunc(func(n *bst.Node[int, entry, Color]) {
+		e := tree.Value(n)
+		e.max = e.payload
+		for _, c := range []*bst.Node[int, entry, Color]{tree.Left(n), tree.Right(n)} {
+			if !tree.IsNil(c) && tree.Value(c).max > e.max {
+				e.max = tree.Value(c).max
+			}
+		}
+		tree.SetValue(n, e)
+	})
+
+	var maxPayload func(n *bst.Node[int, entry, Color]) int
+	maxPayload = func(n *bst.Node[int, entry, Color]) int {
+		if tree.IsNil(n) {
+			return -1
+		}
+		return max(tree.Value(n).payload, maxPayload(tree.Left(n)), maxPayload(tree.Right(n)))
+	}
+	check := func() {
+		require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+		tree.TraverseInOrder(tree.Root(), func(n *bst.Node[int, entry, Color]) bool {
+			assert.Equal(t, maxPayload(n), tree.Value(n).max, "unexpected aggregate at node %d", tree.Key(n))
+			return true
+		})
+	}
+
+	// insertions trigger rotations and recolorings
+	for i := 0; i < 200; i++ {
+		key := (i * 73) % 200
+		tree.Insert(key, entry{payload: (key * 31) % 97})
+		check()
+	}
+
+	// value updates
+	n, _ := tree.Search(10)
+	tree.SetValue(n, entry{payload: 500})
+	check()
+	assert.Equal(t, 500, tree.Value(tree.Root()).max, "expected updated payload to reach the root")
+
+	// deletions, including nodes with two children
+	for i := 0; i < 200; i += 3 {
+		n, _ := tree.Search((i * 73) % 200)
+		_, deleted := tree.Delete(n)
+		require.True(t, deleted)
+		check()
+	}
+}
+
+func TestTree_WriteSVG(t *testing.T) {
+	tree := New[int, string](func(a, b int) bool { return a < b })
+	for i := 0; i < 10; i++ {
+		tree.Insert(i, "value")
+	}
+
+	var buf strings.Builder
+	require.NoError(t, tree.WriteSVG(&buf))
+	svg := buf.String()
+
+	reds, blacks := 0, 0
+	tree.TraverseInOrder(tree.Root(), func(n *bst.Node[int, string, Color]) bool {
+		if tree.Metadata(n) == Red {
+			reds++
+		} else {
+			blacks++
+		}
+		return true
+	})
+	assert.Equal(t, reds, strings.Count(svg, `fill="#d32f2f"`), "expected red nodes drawn red")
+	assert.Equal(t, blacks, strings.Count(svg, `fill="#212121"`), "expected black nodes drawn black")
+}
+
+func TestTree_DeleteWhere(t *testing.T) {
+	for name, tc := range map[string]struct {
+		size int
+		pred func(k int) bool
+	}{
+		"none":         {size: 500, pred: func(k int) bool { return false }},
+		"few":          {size: 500, pred: func(k int) bool { return k%50 == 0 }},
+		"most":         {size: 500, pred: func(k int) bool { return k%10 != 0 }},
+		"all":          {size: 500, pred: func(k int) bool { return true }},
+		"small tree":   {size: 10, pred: func(k int) bool { return k%2 == 0 }},
+		"prefix":       {size: 1000, pred: func(k int) bool { return k < 700 }},
+		"perfect size": {size: 1023, pred: func(k int) bool { return k >= 511 }},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tree := NewOrdered[int, int]()
+			nodes := make(map[int]*bst.Node[int, int, Color])
+			for _, k := range rand.New(rand.NewSource(1)).Perm(tc.size) {
+				nodes[k], _ = tree.Insert(k, k*10)
+			}
+
+			var removed []int
+			tree.OnChange(func(op bst.ChangeOp, key int, value int) {
+				if op == bst.ChangeDelete {
+					removed = append(removed, key)
+				}
+			})
+			deleted := tree.DeleteWhere(func(k, v int) bool {
+				assert.Equal(t, k*10, v, "expected value of key %d", k)
+				return tc.pred(k)
+			})
+
+			want := 0
+			for k, n := range nodes {
+				if tc.pred(k) {
+					want++
+					assert.False(t, tree.Contains(n), "expected node %d to be deleted", k)
+				} else {
+					assert.True(t, tree.Contains(n), "expected node %d to remain valid", k)
+					assert.Equal(t, k*10, tree.Value(n), "expected node %d to keep its value", k)
+				}
+			}
+			assert.Equal(t, want, deleted, "unexpected number of deleted nodes")
+			assert.Len(t, removed, want, "expected a change notified per deleted node")
+			assert.Equal(t, tc.size-want, tree.Size(), "unexpected size")
+			require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+
+			// the tree remains usable
+			for k := range nodes {
+				tree.Insert(k, k)
+			}
+			assert.Equal(t, tc.size, tree.Size(), "unexpected size after reinsertion")
+			require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+		})
+	}
+}
+
+func TestTree_UpdateKey(t *testing.T) {
+	tree := NewOrdered[int, int]()
+	rng := rand.New(rand.NewSource(1))
+	handles := make(map[int]*bst.Node[int, int, Color])
+	for len(handles) < 300 {
+		key := rng.Intn(1000)
+		handles[key], _ = tree.Insert(key, key)
+	}
+
+	for i := 0; i < 3000; i++ {
+		var oldKey int
+		for oldKey = range handles {
+			break
+		}
+		n, newKey := handles[oldKey], rng.Intn(1000)
+		value := tree.Value(n)
+		_, taken := handles[newKey]
+		updated := tree.UpdateKey(n, newKey)
+		if taken && newKey != oldKey {
+			require.False(t, updated, "expected key %d to be rejected as taken", newKey)
+		} else {
+			require.True(t, updated, "expected key %d to be updated to %d", oldKey, newKey)
+			delete(handles, oldKey)
+			handles[newKey] = n
+		}
+		require.True(t, tree.Contains(n), "expected node to remain in the tree")
+		require.Equal(t, value, tree.Value(n), "expected value to be preserved")
+		require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	}
+	require.Equal(t, len(handles), tree.Size())
+	for key, n := range handles {
+		found, _ := tree.Search(key)
+		assert.Same(t, n, found, "expected key %d to be held by the same node", key)
+	}
+
+	// stale nodes are not updated
+	n := handles[tree.Key(tree.Root())]
+	tree.Delete(n)
+	assert.False(t, tree.UpdateKey(n, 5000), "expected stale node not to be updated")
+}
@@ -0,0 +1,158 @@
+package rbtree
+
+import (
+	"errors"
+	"fmt"
+	"github.com/mikenye/gotrees/bst"
+)
+
+// ErrOutOfRange is wrapped by the error returned by SubMap.Insert when the key falls outside the bounds of the view.
+var ErrOutOfRange = errors.New("key out of range")
+
+// SubMap is a view of the keys of a tree within the half-open interval [lo, hi), as returned by
+// Tree.SubMap, mirroring NavigableMap.subMap in Java.
+//
+// A view holds no nodes of its own: every method searches the underlying tree within the bounds, so the
+// view reflects later changes to the tree, and changes made through the view, which are range checked,
+// apply to the tree. Creating a view is an O(1) operation, and its methods take O(log n) time, as those
+// of the tree do, plus O(m) time when visiting m nodes.
+type SubMap[K, V any, M ColorMetadata[M]] struct {
+	tree   *Base[K, V, M]
+	lo, hi K
+}
+
+// SubMap returns a view of the keys of the tree within the half-open interval [lo, hi), through
+// which those keys can be searched, iterated over, inserted and deleted without copying the tree.
+//
+// If hi is not greater than lo, the view is empty, and every insertion through it fails.
+//
+// Example Usage:
+//
+//	// process the keys in [100, 200) only
+//	view := tree.SubMap(100, 200)
+//	view.Ascend(func(n *bst.Node[int, string, rbtree.Color]) bool {
+//		fmt.Println(view.Tree().Key(n))
+//		return true
+//	})
+func (t *Base[K, V, M]) SubMap(lo, hi K) *SubMap[K, V, M] {
+	return &SubMap[K, V, M]{tree: t, lo: lo, hi: hi}
+}
+
+// Tree returns the underlying tree of the view.
+func (s *SubMap[K, V, M]) Tree() *Base[K, V, M] {
+	return s.tree
+}
+
+// Bounds returns the bounds of the half-open interval [lo, hi) of keys in the view.
+func (s *SubMap[K, V, M]) Bounds() (lo, hi K) {
+	return s.lo, s.hi
+}
+
+// InRange reports whether key falls within the bounds of the view.
+func (s *SubMap[K, V, M]) InRange(key K) bool {
+	return !s.tree.tree.Less(key, s.lo) && s.tree.tree.Less(key, s.hi)
+}
+
+// Size returns the number of nodes of the tree within the bounds of the view.
+//
+// This is an O(log n) operation (see Tree.CountRange), plus O(d) time for d nodes deleted lazily
+// (see Tree.SetLazyDeletion).
+func (s *SubMap[K, V, M]) Size() int {
+	return s.tree.CountRange(s.lo, s.hi)
+}
+
+// Search looks for a node with the given key within the bounds of the view.
+//
+// Returns:
+//   - (*bst.Node[K, V, M], true) if the key is in range and found in the tree.
+//   - (sentinel nil node, false) otherwise.
+func (s *SubMap[K, V, M]) Search(key K) (*bst.Node[K, V, M], bool) {
+	if !s.InRange(key) {
+		return s.tree.Sentinel(), false
+	}
+	return s.tree.Search(key)
+}
+
+// Min returns the node with the smallest key within the bounds of the view.
+//
+// Returns:
+//   - (*bst.Node[K, V, M], true) if the view is not empty.
+//   - (sentinel nil node, false) if the view is empty.
+func (s *SubMap[K, V, M]) Min() (*bst.Node[K, V, M], bool) {
+	n, found := s.tree.Ceiling(s.lo)
+	if !found || !s.tree.tree.Less(s.tree.Key(n), s.hi) {
+		return s.tree.Sentinel(), false
+	}
+	return n, true
+}
+
+// Max returns the node with the largest key within the bounds of the view.
+//
+// Returns:
+//   - (*bst.Node[K, V, M], true) if the view is not empty.
+//   - (sentinel nil node, false) if the view is empty.
+func (s *SubMap[K, V, M]) Max() (*bst.Node[K, V, M], bool) {
+	n, found := s.tree.Lower(s.hi)
+	if !found || s.tree.tree.Less(s.tree.Key(n), s.lo) {
+		return s.tree.Sentinel(), false
+	}
+	return n, true
+}
+
+// Ascend calls f for each node within the bounds of the view in ascending key order, until f returns false.
+//
+// f must not modify the tree. To delete the nodes of the view, use SubMap.Clear.
+func (s *SubMap[K, V, M]) Ascend(f func(n *bst.Node[K, V, M]) bool) {
+	n, found := s.Min()
+	for found && f(n) {
+		n = s.tree.Successor(n)
+		found = !s.tree.IsNil(n) && s.tree.tree.Less(s.tree.Key(n), s.hi)
+	}
+}
+
+// Descend calls f for each node within the bounds of the view in descending key order, until f returns false.
+//
+// f must not modify the tree. To delete the nodes of the view, use SubMap.Clear.
+func (s *SubMap[K, V, M]) Descend(f func(n *bst.Node[K, V, M]) bool) {
+	n, found := s.Max()
+	for found && f(n) {
+		n = s.tree.Predecessor(n)
+		found = !s.tree.IsNil(n) && !s.tree.tree.Less(s.tree.Key(n), s.lo)
+	}
+}
+
+// Insert inserts a node with the given key and value into the tree, if key falls within the bounds of the
+// view, as Tree.Insert does.
+//
+// Returns:
+//   - (*bst.Node[K, V, M], true, nil) if a new node was inserted.
+//   - (*bst.Node[K, V, M], false, nil) if the key existed and the value was updated.
+//   - (sentinel nil node, false, error wrapping ErrOutOfRange) if key is out of range. The tree is then unchanged.
+func (s *SubMap[K, V, M]) Insert(key K, value V) (*bst.Node[K, V, M], bool, error) {
+	if !s.InRange(key) {
+		return s.tree.Sentinel(), false, fmt.Errorf("%w: %v not in [%v, %v)", ErrOutOfRange, key, s.lo, s.hi)
+	}
+	n, inserted := s.tree.Insert(key, value)
+	return n, inserted, nil
+}
+
+// Delete removes node n from the tree, if its key falls within the bounds of the view, as Tree.Delete does.
+//
+// Returns:
+//   - (value, true) if the node was removed, where value is the value it held.
+//   - (zero value, false) if n is nil, is not in the tree, or its key is out of range.
+func (s *SubMap[K, V, M]) Delete(n *bst.Node[K, V, M]) (V, bool) {
+	if !s.tree.Contains(n) || !s.InRange(s.tree.Key(n)) {
+		var zero V
+		return zero, false
+	}
+	return s.tree.Delete(n)
+}
+
+// Clear removes every node within the bounds of the view from the tree (see Tree.RangeDelete).
+//
+// Returns:
+//   - The number of nodes removed from the tree.
+func (s *SubMap[K, V, M]) Clear() int {
+	return s.tree.RangeDelete(s.lo, s.hi)
+}
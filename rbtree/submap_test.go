@@ -0,0 +1,122 @@
+package rbtree
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestTree_SubMap(t *testing.T) {
+	tree := New[int, string](func(a, b int) bool { return a < b })
+	for i := 0; i < 20; i += 2 {
+		tree.Insert(i, "v")
+	}
+	view := tree.SubMap(5, 12)
+	lo, hi := view.Bounds()
+	assert.Equal(t, 5, lo)
+	assert.Equal(t, 12, hi)
+	assert.Same(t, tree, view.Tree())
+	assert.Equal(t, 3, view.Size())
+
+	// searches are limited to the bounds
+	_, found := view.Search(6)
+	assert.True(t, found)
+	_, found = view.Search(4)
+	assert.False(t, found, "expected a key below the bounds not to be found")
+	_, found = view.Search(12)
+	assert.False(t, found, "expected the upper bound to be excluded")
+	n, found := view.Min()
+	require.True(t, found)
+	assert.Equal(t, 6, tree.Key(n))
+	n, found = view.Max()
+	require.True(t, found)
+	assert.Equal(t, 10, tree.Key(n))
+
+	var keys []int
+	view.Ascend(func(n *bst.Node[int, string, Color]) bool {
+		keys = append(keys, tree.Key(n))
+		return true
+	})
+	assert.Equal(t, []int{6, 8, 10}, keys)
+	keys = nil
+	view.Descend(func(n *bst.Node[int, string, Color]) bool {
+		keys = append(keys, tree.Key(n))
+		return tree.Key(n) > 8
+	})
+	assert.Equal(t, []int{10, 8}, keys)
+
+	// writes are range checked, and apply to the tree
+	n, inserted, err := view.Insert(7, "seven")
+	require.NoError(t, err)
+	assert.True(t, inserted)
+	assert.Equal(t, 7, tree.Key(n))
+	assert.Equal(t, 11, tree.Size())
+	_, _, err = view.Insert(12, "twelve")
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	assert.Equal(t, 11, tree.Size())
+
+	outside, _ := tree.Search(4)
+	_, deleted := view.Delete(outside)
+	assert.False(t, deleted, "expected a node out of range not to be deleted")
+	inside, _ := tree.Search(8)
+	value, deleted := view.Delete(inside)
+	assert.True(t, deleted)
+	assert.Equal(t, "v", value)
+
+	// changes to the tree are reflected in the view
+	tree.Insert(5, "five")
+	assert.Equal(t, 4, view.Size())
+	assert.Equal(t, 4, view.Clear())
+	assert.Equal(t, 0, view.Size())
+	_, found = view.Min()
+	assert.False(t, found)
+	_, found = view.Max()
+	assert.False(t, found)
+	assert.Equal(t, 7, tree.Size())
+	require.NoError(t, tree.IsTreeValid())
+}
+
+func TestTree_SubMap_empty(t *testing.T) {
+	tree := New[int, string](func(a, b int) bool { return a < b })
+	for i := 0; i < 10; i++ {
+		tree.Insert(i, "v")
+	}
+	for _, view := range []*SubMap[int, string, Color]{tree.SubMap(5, 5), tree.SubMap(7, 3), tree.SubMap(20, 30)} {
+		assert.Equal(t, 0, view.Size())
+		_, found := view.Min()
+		assert.False(t, found)
+		_, found = view.Max()
+		assert.False(t, found)
+		view.Ascend(func(n *bst.Node[int, string, Color]) bool {
+			t.Errorf("unexpected node %d", tree.Key(n))
+			return true
+		})
+		_, _, err := view.Insert(5, "v")
+		assert.ErrorIs(t, err, ErrOutOfRange)
+	}
+}
+
+func TestTree_SubMap_tombstones(t *testing.T) {
+	tree := New[int, string](func(a, b int) bool { return a < b })
+	tree.SetLazyDeletion(1)
+	for i := 0; i < 10; i++ {
+		tree.Insert(i, "v")
+	}
+	for _, k := range []int{2, 5, 7} {
+		n, _ := tree.Search(k)
+		tree.Delete(n)
+	}
+	view := tree.SubMap(2, 8)
+	assert.Equal(t, 3, view.Size())
+	n, _ := view.Min()
+	assert.Equal(t, 3, tree.Key(n))
+	n, _ = view.Max()
+	assert.Equal(t, 6, tree.Key(n))
+	var keys []int
+	view.Ascend(func(n *bst.Node[int, string, Color]) bool {
+		keys = append(keys, tree.Key(n))
+		return true
+	})
+	assert.Equal(t, []int{3, 4, 6}, keys)
+}
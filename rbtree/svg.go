@@ -0,0 +1,20 @@
+package rbtree
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"io"
+)
+
+// WriteSVG writes a standalone SVG drawing of the Red-Black Tree to w, with each node
+// drawn in its color (red or black). See bst.Tree.WriteSVG for details of the layout.
+//
+// Returns:
+//   - Any error returned by w.
+func (t *Base[K, V, M]) WriteSVG(w io.Writer) error {
+	return t.tree.WriteSVG(w, func(n *bst.Node[K, V, M]) bst.SVGNodeStyle {
+		if t.color(n) == Red {
+			return bst.SVGNodeStyle{Fill: "#d32f2f", Stroke: "#8e0000", Text: "white"}
+		}
+		return bst.SVGNodeStyle{Fill: "#212121", Stroke: "black", Text: "white"}
+	})
+}
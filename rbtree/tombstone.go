@@ -0,0 +1,136 @@
+package rbtree
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"slices"
+)
+
+// SetLazyDeletion enables lazy deletion if ratio is positive, or disables it otherwise.
+//
+// With lazy deletion, Tree.Delete marks a node as a tombstone rather than removing it, so that no
+// rotation or recoloring takes place. Tombstones are removed by Tree.Compact, which is called by
+// Tree.Delete once the tombstones exceed ratio times the number of nodes in the tree, including the
+// tombstones. In unique mode, inserting the key of a tombstone revives it with the new value, also
+// without any rebalancing. This suits workloads deleting many keys and soon reinserting the same keys,
+// which would otherwise rebalance the tree twice for each key.
+//
+// Tombstones are hidden by Search, FingerSearch, Contains, Size, Count, CountRange, Min, Max, FirstEntry,
+// LastEntry, Floor, Ceiling, FingerCeiling, Lower, Higher, Nearest, Successor, Predecessor, TraverseInOrder
+// and TraverseInOrderErr, and tombstones are never deleted twice. AscendDelete, Cursor, DeleteWhere, PopMin,
+// PopMax, UpdateKey, the order statistics (Rank, Select, KthSmallest, KthLargest, AscendAt and SliceByRank),
+// the comparisons (Equal and EqualStructure), the encodings (MarshalJSON, MarshalBinary, MarshalWith,
+// MarshalProto and SnapshotTo) and Snapshot compact the tree first. Other methods see tombstones as
+// ordinary nodes, including navigation (such as Root, Left and Right) and rendering: call Tree.Compact
+// first where exact results are needed.
+// Tombstones keep their values until they are compacted, and their removal is notified to the function
+// registered with Tree.OnChange when they are compacted.
+//
+// Disabling lazy deletion compacts the tree.
+//
+// Example Usage:
+//
+//	tree.SetLazyDeletion(0.25) // compact once a quarter of the nodes are tombstones
+func (t *Base[K, V, M]) SetLazyDeletion(ratio float64) {
+	if ratio <= 0 {
+		t.Compact()
+		t.tombstoneRatio = 0
+		return
+	}
+	t.tombstoneRatio = ratio
+	if t.tombstones == nil {
+		t.tombstones = make(map[*bst.Node[K, V, M]]struct{})
+	}
+}
+
+// IsTombstone reports whether node n has been deleted lazily, and is yet to be removed by Tree.Compact
+// (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) IsTombstone(n *bst.Node[K, V, M]) bool {
+	if len(t.tombstones) == 0 {
+		return false
+	}
+	_, found := t.tombstones[n]
+	return found
+}
+
+// Tombstones returns the number of nodes deleted lazily, and yet to be removed by Tree.Compact
+// (see Tree.SetLazyDeletion).
+func (t *Base[K, V, M]) Tombstones() int {
+	return len(t.tombstones)
+}
+
+// Compact removes every node deleted lazily from the tree, maintaining Red-Black Tree properties
+// after each removal (see Tree.SetLazyDeletion). Nodes are removed in ascending key order, so that
+// the resulting shape, and the order in which removals are notified, do not vary between runs.
+//
+// Returns:
+//   - The number of nodes removed from the tree.
+func (t *Base[K, V, M]) Compact() int {
+	if len(t.tombstones) == 0 {
+		return 0
+	}
+	type tombstone struct {
+		n   *bst.Node[K, V, M]
+		pos int
+	}
+	buried := make([]tombstone, 0, len(t.tombstones))
+	for n := range t.tombstones {
+		buried = append(buried, tombstone{n, t.position(n)})
+	}
+	slices.SortFunc(buried, func(a, b tombstone) int { return a.pos - b.pos })
+	for _, b := range buried {
+		delete(t.tombstones, b.n)
+		t.erase(b.n)
+	}
+	return len(buried)
+}
+
+// position returns the zero-based in-order position of node n in the tree, counting tombstones,
+// in O(h) time.
+func (t *Base[K, V, M]) position(n *bst.Node[K, V, M]) int {
+	pos := t.SubtreeSize(t.Left(n))
+	for p := t.Parent(n); !t.IsNil(p); n, p = p, t.Parent(p) {
+		if n == t.Right(p) {
+			pos += t.SubtreeSize(t.Left(p)) + 1
+		}
+	}
+	return pos
+}
+
+// countTombstones returns the number of nodes deleted lazily whose key satisfies f.
+func (t *Base[K, V, M]) countTombstones(f func(K) bool) int {
+	count := 0
+	for n := range t.tombstones {
+		if f(t.Key(n)) {
+			count++
+		}
+	}
+	return count
+}
+
+// bury marks node z as deleted lazily, compacting the tree once the tombstones exceed the ratio
+// given to Tree.SetLazyDeletion.
+func (t *Base[K, V, M]) bury(z *bst.Node[K, V, M]) {
+	t.tombstones[z] = struct{}{}
+	if float64(len(t.tombstones)) > t.tombstoneRatio*float64(t.tree.Size()) {
+		t.Compact()
+	}
+}
+
+// revive inserts key with value by reviving the tombstone holding key, if any, in unique mode
+// (see Tree.SetLazyDeletion).
+//
+// Returns:
+//   - The revived node, and true if a tombstone was revived.
+//   - The sentinel nil node, and false otherwise.
+func (t *Base[K, V, M]) revive(key K, value V) (*bst.Node[K, V, M], bool) {
+	if len(t.tombstones) == 0 || t.tree.Duplicates() {
+		return t.Sentinel(), false
+	}
+	n, found := t.tree.Search(key)
+	if !found || !t.IsTombstone(n) {
+		return t.Sentinel(), false
+	}
+	delete(t.tombstones, n)
+	t.SetValue(n, value)
+	return n, true
+}
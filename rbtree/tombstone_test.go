@@ -0,0 +1,402 @@
+package rbtree
+
+import (
+	"bytes"
+	"github.com/mikenye/gotrees/bst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestTree_SetLazyDeletion(t *testing.T) {
+	tree := New[int, string](func(a, b int) bool { return a < b })
+	tree.SetLazyDeletion(1) // never compact automatically
+	nodes := make(map[int]*bst.Node[int, string, Color])
+	for i := 1; i <= 10; i++ {
+		nodes[i], _ = tree.Insert(i, "v")
+	}
+	rotations := tree.Rotations()
+
+	for _, k := range []int{1, 4, 5, 10} {
+		value, deleted := tree.Delete(nodes[k])
+		assert.True(t, deleted, "expected %d to be deleted", k)
+		assert.Equal(t, "v", value)
+	}
+	_, deleted := tree.Delete(nodes[4])
+	assert.False(t, deleted, "expected a tombstone not to be deleted twice")
+	assert.Equal(t, rotations, tree.Rotations(), "expected no rebalancing")
+	assert.Equal(t, 4, tree.Tombstones())
+	assert.True(t, tree.IsTombstone(nodes[4]))
+	require.NoError(t, tree.IsTreeValid())
+
+	// tombstones are hidden
+	assert.Equal(t, 6, tree.Size())
+	assert.False(t, tree.Contains(nodes[5]))
+	_, found := tree.Search(5)
+	assert.False(t, found)
+	assert.Equal(t, 2, tree.Key(tree.Min(tree.Root())))
+	assert.Equal(t, 9, tree.Key(tree.Max(tree.Root())))
+	first, _, _ := tree.FirstEntry()
+	assert.Equal(t, 2, first)
+	last, _ := tree.LastKey()
+	assert.Equal(t, 9, last)
+	assert.Equal(t, 6, tree.Key(tree.Successor(nodes[3])))
+	assert.Equal(t, 3, tree.Key(tree.Predecessor(nodes[6])))
+	n, found := tree.Floor(5)
+	assert.True(t, found)
+	assert.Equal(t, 3, tree.Key(n))
+	n, found = tree.Ceiling(4)
+	assert.True(t, found)
+	assert.Equal(t, 6, tree.Key(n))
+	_, found = tree.Ceiling(10)
+	assert.False(t, found)
+	n, found = tree.Lower(6)
+	assert.True(t, found)
+	assert.Equal(t, 3, tree.Key(n))
+	n, found = tree.Higher(3)
+	assert.True(t, found)
+	assert.Equal(t, 6, tree.Key(n))
+	_, found = tree.Higher(9)
+	assert.False(t, found)
+	var keys []int
+	tree.TraverseInOrder(tree.Root(), func(n *bst.Node[int, string, Color]) bool {
+		keys = append(keys, tree.Key(n))
+		return true
+	})
+	assert.Equal(t, []int{2, 3, 6, 7, 8, 9}, keys)
+
+	// reinserting the key of a tombstone revives it
+	n, inserted := tree.Insert(5, "five")
+	assert.True(t, inserted, "expected a revived key to be reported as inserted")
+	assert.Same(t, nodes[5], n, "expected the tombstone to be revived")
+	assert.Equal(t, "five", tree.Value(n))
+	assert.Equal(t, 3, tree.Tombstones())
+	assert.Equal(t, rotations, tree.Rotations(), "expected no rebalancing")
+
+	// compaction removes the tombstones
+	var removed []int
+	tree.OnChange(func(op bst.ChangeOp, key int, value string) {
+		if op == bst.ChangeDelete {
+			removed = append(removed, key)
+		}
+	})
+	assert.Equal(t, 3, tree.Compact())
+	assert.ElementsMatch(t, []int{1, 4, 10}, removed)
+	assert.Equal(t, 0, tree.Tombstones())
+	assert.Equal(t, 7, tree.Size())
+	require.NoError(t, tree.IsTreeValid())
+
+	// disabling lazy deletion compacts the tree, and deletes at once
+	tree.Delete(nodes[2])
+	tree.SetLazyDeletion(0)
+	assert.Equal(t, 0, tree.Tombstones())
+	tree.Delete(nodes[3])
+	assert.Equal(t, 0, tree.Tombstones())
+	assert.Equal(t, 5, tree.Size())
+	require.NoError(t, tree.IsTreeValid())
+}
+
+func TestTree_SetLazyDeletion_ratio(t *testing.T) {
+	tree := New[int, struct{}](func(a, b int) bool { return a < b })
+	tree.SetLazyDeletion(0.25)
+	for i := 0; i < 100; i++ {
+		tree.Insert(i, struct{}{})
+	}
+	for i := 0; i < 25; i++ {
+		n, _ := tree.Search(i)
+		tree.Delete(n)
+	}
+	assert.Equal(t, 25, tree.Tombstones(), "expected no compaction at the ratio")
+	n, _ := tree.Search(25)
+	tree.Delete(n)
+	assert.Equal(t, 0, tree.Tombstones(), "expected compaction above the ratio")
+	assert.Equal(t, 74, tree.Size())
+	require.NoError(t, tree.IsTreeValid())
+}
+
+func TestTree_SetLazyDeletion_compacting(t *testing.T) {
+	tree := New[int, int](func(a, b int) bool { return a < b })
+	tree.SetLazyDeletion(1)
+	for i := 0; i < 10; i++ {
+		tree.Insert(i, i)
+	}
+	deleteKey := func(k int) {
+		n, _ := tree.Search(k)
+		tree.Delete(n)
+	}
+
+	deleteKey(0)
+	k, _, ok := tree.PopMin()
+	assert.True(t, ok)
+	assert.Equal(t, 1, k, "expected PopMin to skip tombstones")
+	deleteKey(9)
+	k, _, ok = tree.PopMax()
+	assert.True(t, ok)
+	assert.Equal(t, 8, k, "expected PopMax to skip tombstones")
+
+	deleteKey(2)
+	visited := 0
+	tree.DeleteWhere(func(k, v int) bool {
+		visited++
+		return false
+	})
+	assert.Equal(t, 5, visited, "expected DeleteWhere to skip tombstones")
+
+	n, _ := tree.Search(3)
+	deleteKey(4)
+	assert.True(t, tree.UpdateKey(n, 4), "expected the key of a compacted tombstone to be free")
+	assert.Equal(t, 0, tree.Tombstones())
+	require.NoError(t, tree.IsTreeValid())
+
+	tree.Delete(n)
+	tree.Clear()
+	assert.Equal(t, 0, tree.Tombstones())
+	assert.Equal(t, 0, tree.Size())
+}
+
+func TestTree_SetLazyDeletion_random(t *testing.T) {
+	for name, opts := range map[string][]bst.Option{
+		"unique":     nil,
+		"duplicates": {bst.WithDuplicateKeys()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tree := New[int, int](func(a, b int) bool { return a < b }, opts...)
+			tree.SetLazyDeletion(0.3)
+			rng := rand.New(rand.NewSource(1))
+			counts := make(map[int]int)
+			var handles []*bst.Node[int, int, Color]
+			for i := 0; i < 5000; i++ {
+				if len(handles) == 0 || rng.Intn(2) == 0 {
+					key := rng.Intn(200)
+					n, inserted := tree.Insert(key, i)
+					if inserted {
+						handles = append(handles, n)
+						counts[key]++
+					}
+				} else {
+					j := rng.Intn(len(handles))
+					n := handles[j]
+					handles[j] = handles[len(handles)-1]
+					handles = handles[:len(handles)-1]
+					counts[tree.Key(n)]--
+					_, deleted := tree.Delete(n)
+					require.True(t, deleted, "expected node to be deleted")
+				}
+				require.Equal(t, len(handles), tree.Size(), "unexpected size after operation %d", i)
+				key := rng.Intn(200)
+				_, found := tree.Search(key)
+				require.Equal(t, counts[key] > 0, found, "unexpected search result for %d after operation %d", key, i)
+			}
+			require.NoError(t, tree.IsTreeValid())
+
+			var keys []int
+			tree.TraverseInOrder(tree.Root(), func(n *bst.Node[int, int, Color]) bool {
+				keys = append(keys, tree.Key(n))
+				return true
+			})
+			require.Len(t, keys, len(handles))
+			for _, n := range handles {
+				assert.True(t, tree.Contains(n), "expected node %d to remain in the tree", tree.Key(n))
+			}
+		})
+	}
+}
+
+func TestTree_All_tombstones(t *testing.T) {
+	tree := New[int, string](func(a, b int) bool { return a < b })
+	tree.SetLazyDeletion(1)
+	for i := 1; i <= 6; i++ {
+		tree.Insert(i, "v")
+	}
+	for _, k := range []int{1, 3, 6} {
+		n, _ := tree.Search(k)
+		tree.Delete(n)
+	}
+	require.Equal(t, 3, tree.Tombstones())
+
+	assert.Equal(t, []int{2, 4, 5}, slices.Collect(tree.Keys()), "expected tombstones to be skipped")
+	assert.Equal(t, []string{"v", "v", "v"}, slices.Collect(tree.Values()))
+	var backward []int
+	for key := range tree.Backward() {
+		backward = append(backward, key)
+	}
+	assert.Equal(t, []int{5, 4, 2}, backward)
+}
+
+func TestTree_SetLazyDeletion_lookups(t *testing.T) {
+	tree := New[int, int](func(a, b int) bool { return a < b })
+	tree.SetLazyDeletion(0.9)
+	for i := 0; i < 10; i++ {
+		tree.Insert(i, i)
+	}
+	for _, k := range []int{3, 4, 5} {
+		n, _ := tree.Search(k)
+		tree.Delete(n)
+	}
+	require.Equal(t, 3, tree.Tombstones())
+	distance := func(a, b int) int {
+		if a < b {
+			return b - a
+		}
+		return a - b
+	}
+
+	_, found := tree.FingerSearch(tree.Root(), 5)
+	assert.False(t, found, "expected FingerSearch to skip tombstones")
+	n, found := tree.FingerSearch(tree.Root(), 6)
+	assert.True(t, found)
+	assert.Equal(t, 6, tree.Key(n))
+	n, found = tree.FingerCeiling(tree.Root(), 4)
+	assert.True(t, found)
+	assert.Equal(t, 6, tree.Key(n), "expected FingerCeiling to skip tombstones")
+	n, found = tree.Nearest(5, distance)
+	assert.True(t, found)
+	assert.Equal(t, 6, tree.Key(n), "expected Nearest to skip tombstones")
+	n, found = tree.Nearest(4, distance)
+	assert.True(t, found)
+	assert.Equal(t, 2, tree.Key(n))
+	assert.Equal(t, 0, tree.Count(5), "expected Count to skip tombstones")
+	assert.Equal(t, 1, tree.Count(6))
+	assert.Equal(t, 7, tree.CountRange(0, 10), "expected CountRange to skip tombstones")
+	assert.Equal(t, 1, tree.CountRange(2, 6))
+	assert.Equal(t, tree.Size(), tree.CountRange(0, 10))
+
+	var keys []int
+	for c := tree.Cursor(); c.Next(); {
+		keys = append(keys, c.Key())
+	}
+	assert.Equal(t, []int{0, 1, 2, 6, 7, 8, 9}, keys, "expected Cursor to skip tombstones")
+	assert.Equal(t, 0, tree.Tombstones())
+	require.NoError(t, tree.IsTreeValid())
+}
+
+func TestTree_Snapshot_tombstones(t *testing.T) {
+	tree := New[int, int](func(a, b int) bool { return a < b })
+	tree.SetLazyDeletion(1)
+	for i := 0; i < 10; i++ {
+		tree.Insert(i, i)
+	}
+	s := tree.Snapshot()
+	for _, k := range []int{2, 5} {
+		n, _ := tree.Search(k)
+		tree.Delete(n)
+	}
+	var keys []int
+	tree.Snapshot().Ascend(func(k, _ int) bool {
+		keys = append(keys, k)
+		return true
+	})
+	assert.Equal(t, []int{0, 1, 3, 4, 6, 7, 8, 9}, keys, "expected the snapshot to skip tombstones")
+	assert.Equal(t, 10, s.Len(), "expected the earlier snapshot to be unchanged")
+	require.NoError(t, tree.IsTreeValid())
+}
+
+func TestTree_SetLazyDeletion_orderStatistics(t *testing.T) {
+	tree := New[int, int](func(a, b int) bool { return a < b })
+	tree.SetLazyDeletion(1)
+	for i := 0; i < 10; i++ {
+		tree.Insert(i, i)
+	}
+	for _, k := range []int{2, 3, 9} {
+		n, _ := tree.Search(k)
+		tree.Delete(n)
+	}
+
+	n, found := tree.KthLargest(1)
+	assert.True(t, found)
+	assert.Equal(t, 8, tree.Key(n), "expected KthLargest to skip tombstones")
+	_, found = tree.KthLargest(7)
+	assert.True(t, found)
+	_, found = tree.KthLargest(8)
+	assert.False(t, found, "expected no rank beyond Size")
+	assert.Equal(t, 0, tree.Tombstones(), "expected order statistics to compact the tree")
+
+	n, found = tree.KthSmallest(3)
+	assert.True(t, found)
+	assert.Equal(t, 4, tree.Key(n))
+	n, found = tree.Select(2)
+	assert.True(t, found)
+	assert.Equal(t, 4, tree.Key(n))
+	assert.Equal(t, 2, tree.Rank(4))
+	var keys []int
+	for _, n := range tree.SliceByRank(1, 4) {
+		keys = append(keys, tree.Key(n))
+	}
+	assert.Equal(t, []int{1, 4, 5}, keys)
+	require.NoError(t, tree.IsTreeValid())
+}
+
+func TestTree_SetLazyDeletion_roundTrip(t *testing.T) {
+	newTree := func() *Tree[int, int] {
+		tree := New[int, int](func(a, b int) bool { return a < b })
+		tree.SetLazyDeletion(1)
+		for i := 0; i < 10; i++ {
+			tree.Insert(i, i)
+		}
+		n, _ := tree.Search(3)
+		tree.Delete(n)
+		return tree
+	}
+	check := func(name string, restored *Tree[int, int]) {
+		assert.Equal(t, 9, restored.Size(), "%s: unexpected size", name)
+		_, found := restored.Search(3)
+		assert.False(t, found, "%s: expected the deleted key to stay deleted", name)
+		assert.True(t, restored.Equal(newTree(), nil), "%s: expected equal trees", name)
+	}
+
+	data, err := newTree().MarshalJSON()
+	require.NoError(t, err)
+	restored := New[int, int](func(a, b int) bool { return a < b })
+	require.NoError(t, restored.UnmarshalJSON(data))
+	check("json", restored)
+
+	data, err = newTree().MarshalBinary()
+	require.NoError(t, err)
+	restored = New[int, int](func(a, b int) bool { return a < b })
+	require.NoError(t, restored.UnmarshalBinary(data))
+	check("binary", restored)
+
+	var buf bytes.Buffer
+	require.NoError(t, newTree().SnapshotTo(&buf))
+	restored = New[int, int](func(a, b int) bool { return a < b })
+	_, err = restored.ReadSnapshot(&buf)
+	require.NoError(t, err)
+	check("snapshot", restored)
+
+	// a tree with a tombstone differs from one still holding the key
+	full := New[int, int](func(a, b int) bool { return a < b })
+	for i := 0; i < 10; i++ {
+		full.Insert(i, i)
+	}
+	assert.False(t, newTree().Equal(full, nil), "expected tombstones not to be compared")
+}
+
+func TestTree_Compact_order(t *testing.T) {
+	run := func() ([]int, *Tree[int, int]) {
+		tree := New[int, int](func(a, b int) bool { return a < b })
+		tree.SetLazyDeletion(1)
+		for i := 0; i < 64; i++ {
+			tree.Insert(i, i)
+		}
+		for i := 0; i < 64; i += 3 {
+			n, _ := tree.Search(i)
+			tree.Delete(n)
+		}
+		var removed []int
+		tree.OnChange(func(_ bst.ChangeOp, key, _ int) {
+			removed = append(removed, key)
+		})
+		tree.Compact()
+		return removed, tree
+	}
+	removed, tree := run()
+	assert.True(t, slices.IsSorted(removed), "expected tombstones to be removed in ascending key order")
+	for i := 0; i < 10; i++ {
+		again, other := run()
+		require.Equal(t, removed, again)
+		require.True(t, tree.EqualStructure(other, nil), "expected the same shape after every compaction")
+	}
+	require.NoError(t, tree.IsTreeValid())
+}
@@ -0,0 +1,326 @@
+package rbtree
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/bst"
+)
+
+// Strategy selects how Tree.Insert and Tree.Delete restore Red-Black Tree properties.
+type Strategy int
+
+const (
+	// BottomUp inserts or removes a node as in a plain BST, then walks back up from the change
+	// with the fixup cases of Cormen et al., "Introduction to Algorithms" (CLRS). This is the default.
+	BottomUp Strategy = iota
+
+	// TopDown rebalances the tree on the way down to the position of the change, after
+	// Guibas and Sedgewick, so that the change itself never needs rebalancing.
+	TopDown
+)
+
+// String returns "bottom-up" or "top-down".
+func (s Strategy) String() string {
+	switch s {
+	case BottomUp:
+		return "bottom-up"
+	case TopDown:
+		return "top-down"
+	default:
+		return fmt.Sprintf("Strategy(%d)", int(s))
+	}
+}
+
+// SetStrategy selects how Tree.Insert and Tree.Delete restore Red-Black Tree properties.
+// The strategy can be changed at any time, as both strategies keep the same invariants.
+//
+//   - BottomUp (the default) descends once to insert or remove a node, then rebalances the tree
+//     on the way back up, stopping as soon as the properties hold. On average, an insertion or
+//     deletion recolors and rotates only a constant number of nodes.
+//   - TopDown recolors and rotates nodes while descending, so that the new node can be linked
+//     under a black parent, and the removed node is red or has a single red child. Rebalancing
+//     needs no walk back up, but many more nodes are recolored on the way down.
+//
+// Subtree sizes and augmented data (see bst.Tree.SetAugmentFunc) are still refreshed from the
+// changed node up to the root, so neither strategy makes a single pass over the tree. Insertions
+// perform about the same with both strategies, while TopDown deletions are about twice as slow
+// (see BenchmarkTree_Strategy). TopDown is offered for comparison and teaching, e.g. together with
+// Tree.SetTracer. Tree.UpdateKey always rebalances bottom-up.
+//
+// Example Usage:
+//
+//	tree := rbtree.New[int, string](func(a, b int) bool { return a < b })
+//	tree.SetStrategy(rbtree.TopDown)
+func (t *Base[K, V, M]) SetStrategy(s Strategy) {
+	t.strategy = s
+}
+
+// Strategy returns the strategy used by Tree.Insert and Tree.Delete (see Tree.SetStrategy).
+func (t *Base[K, V, M]) Strategy() Strategy {
+	return t.strategy
+}
+
+// insertTopDown implements Tree.InsertWithPolicy with the TopDown strategy.
+//
+// Top-Down Insertion Cases
+// While descending towards the position of the new node:
+//
+//  1. A node with two red children is recolored red, and its children black.
+//     If its parent is also red, the red-red violation is removed with cases 2 and 3.
+//  2. The red node is an inner grandchild of a black node: Rotate it above its parent.
+//  3. The red node is an outer grandchild of a black node: Rotate its parent above the grandparent.
+//
+// Case 1 ensures that the sibling of a red parent is black, so cases 2 and 3 complete the
+// rebalancing, as for bottom-up insertion. The new node is linked red, and a red parent is
+// handled with cases 2 and 3.
+func (t *Base[K, V, M]) insertTopDown(key K, value V, policy bst.DuplicatePolicy) (*bst.Node[K, V, M], bool, error) {
+	duplicates := t.tree.Duplicates()
+	if policy == bst.DuplicateAppend && !duplicates {
+		panic(fmt.Sprintf("rbtree: invalid duplicate policy %v outside of multiset mode", policy))
+	}
+	appending := policy == bst.DuplicateAppend || (duplicates && policy == bst.DuplicateReplace)
+	t.traceCase("insert", 0, false)
+	parent := t.Sentinel()
+	x := t.Root()
+	for !t.IsNil(x) {
+		if t.IsRed(t.Left(x)) && t.IsRed(t.Right(x)) {
+			t.traceCase("insert", 1, false)
+			t.setColor(x, Red)
+			t.setColor(t.Left(x), Black)
+			t.setColor(t.Right(x), Black)
+			if t.IsRed(t.Parent(x)) {
+				t.splitRedRed(x)
+			}
+		}
+
+		if !appending && !t.tree.Less(key, t.Key(x)) && !t.tree.Less(t.Key(x), key) {
+			err := t.tree.ResolveDuplicate(x, value, policy)
+			t.traceCase("insert", 0, false)
+			t.setColor(t.Root(), Black)
+			return x, false, err
+		}
+
+		parent = x
+		if t.tree.Less(key, t.Key(x)) {
+			x = t.Left(x)
+		} else {
+			x = t.Right(x)
+		}
+	}
+
+	n := t.tree.InsertAt(parent, key, value)
+	t.setColor(n, Red)
+	if t.IsRed(parent) {
+		t.splitRedRed(n)
+	}
+	t.traceCase("insert", 0, false)
+	t.setColor(t.Root(), Black)
+	return n, true, nil
+}
+
+// splitRedRed removes the violation between red node z and its red parent, whose sibling is black,
+// with top-down insertion cases 2 and 3 (see insertTopDown).
+func (t *Base[K, V, M]) splitRedRed(z *bst.Node[K, V, M]) {
+	p := t.Parent(z)
+	g := t.Parent(p)
+	if p == t.Left(g) {
+		if z == t.Right(p) {
+			t.traceCase("insert", 2, false)
+			t.rotateLeft(p)
+			p = z
+		}
+		t.traceCase("insert", 3, false)
+		t.setColor(p, Black)
+		t.setColor(g, Red)
+		t.rotateRight(g)
+	} else {
+		if z == t.Left(p) {
+			t.traceCase("insert", 2, true)
+			t.rotateRight(p)
+			p = z
+		}
+		t.traceCase("insert", 3, true)
+		t.setColor(p, Black)
+		t.setColor(g, Red)
+		t.rotateLeft(g)
+	}
+}
+
+// deleteTopDown implements Tree.Delete with the TopDown strategy, relinking the neighbours of node z
+// so that it is no longer part of the tree's structure, without releasing z.
+//
+// Top-Down Deletion Cases
+// The node y actually removed is z if it has at most one child, and the successor of z otherwise.
+// While descending towards y, the current black node x is made red, or is moved below a red parent,
+// so that y is eventually red, or black with a single red child, and can be removed without changing
+// the black height of the tree. Each black node x reached has a red parent (or is the root) and a black
+// sibling s:
+//
+//  1. x and s have black children: Recolor x and s red, and their parent black.
+//  2. x has black children, and s has a red outer child: Rotate s above the parent, and recolor.
+//  3. x has black children, and s has a red inner child: Rotate that child above the parent, and recolor.
+//  4. x has a red child, and the way down leads to its black child: Rotate the red child above x,
+//     and recolor, so that the black child gets a red parent.
+//
+// Otherwise, x has a red child on the way down, which is made the new x.
+func (t *Base[K, V, M]) deleteTopDown(z *bst.Node[K, V, M]) {
+	t.traceCase("delete", 0, false)
+	d := descent[K, V, M]{t: t, z: z}
+	if t.tree.Duplicates() {
+		// equal keys do not tell the way to z
+		d.path = t.Path(z)
+	}
+
+	x := t.Root()
+	for {
+		if t.IsBlack(t.Left(x)) && t.IsBlack(t.Right(x)) {
+			t.redden(x)
+			if d.isTarget(x) {
+				break
+			}
+			x = d.next(x)
+			continue
+		}
+
+		if d.isTarget(x) {
+			// x is black, with a single red child
+			break
+		}
+		c := d.next(x)
+		if t.IsRed(c) {
+			if d.isTarget(c) {
+				x = c
+				break
+			}
+			x = d.next(c)
+			continue
+		}
+
+		// case 4: c is black, and its sibling is red
+		s := t.Sibling(c)
+		if c == t.Left(x) {
+			t.traceCase("delete", 4, false)
+			t.rotateLeft(x)
+		} else {
+			t.traceCase("delete", 4, true)
+			t.rotateRight(x)
+		}
+		t.setColor(s, Black)
+		t.setColor(x, Red)
+		x = c
+	}
+	t.removeTopDown(z, x)
+	t.traceCase("delete", 0, false)
+	t.setColor(t.Root(), Black)
+	t.resetSentinelNodeProperties()
+}
+
+// redden makes black node x red with top-down deletion cases 1 to 3 (see deleteTopDown),
+// given that x has black children, a red parent (or is the root), and a black sibling.
+func (t *Base[K, V, M]) redden(x *bst.Node[K, V, M]) {
+	p := t.Parent(x)
+	if t.IsNil(p) {
+		t.traceCase("delete", 1, false)
+		t.setColor(x, Red)
+		return
+	}
+	s := t.Sibling(x)
+	mirrored := x == t.Right(p)
+	outer, inner := t.Right(s), t.Left(s)
+	if mirrored {
+		outer, inner = inner, outer
+	}
+
+	switch {
+	case t.IsBlack(outer) && t.IsBlack(inner):
+		t.traceCase("delete", 1, mirrored)
+		t.setColor(p, Black)
+		t.setColor(s, Red)
+	case t.IsRed(outer):
+		t.traceCase("delete", 2, mirrored)
+		t.rotateTowards(p, mirrored)
+		t.setColor(s, Red)
+		t.setColor(p, Black)
+		t.setColor(outer, Black)
+	default:
+		t.traceCase("delete", 3, mirrored)
+		t.rotateTowards(s, !mirrored)
+		t.rotateTowards(p, mirrored)
+		t.setColor(p, Black)
+	}
+	t.setColor(x, Red)
+}
+
+// rotateTowards performs a left rotation about n, or a right rotation if mirrored is true.
+func (t *Base[K, V, M]) rotateTowards(n *bst.Node[K, V, M], mirrored bool) {
+	if mirrored {
+		t.rotateRight(n)
+	} else {
+		t.rotateLeft(n)
+	}
+}
+
+// removeTopDown removes node y, which is red or has a single red child, replacing it with its child,
+// and moves y into the place of z if y is the successor of z (see deleteTopDown).
+func (t *Base[K, V, M]) removeTopDown(z, y *bst.Node[K, V, M]) {
+	r := t.Right(y)
+	if t.IsNil(r) {
+		r = t.Left(y)
+	}
+	refreshFrom := t.Parent(y)
+	t.transplant(y, r)
+	t.setColor(r, Black)
+
+	if y != z {
+		if refreshFrom == z {
+			refreshFrom = y
+		}
+		t.tree.SetLeft(y, t.Left(z))
+		t.tree.SetParent(t.Left(y), y)
+		t.tree.SetRight(y, t.Right(z))
+		t.tree.SetParent(t.Right(y), y)
+		t.transplant(z, y)
+		t.setColor(y, t.color(z))
+	}
+
+	// update subtree sizes from the splice point up
+	t.tree.RefreshPath(refreshFrom)
+}
+
+// descent finds the way down to the node removed by deleteTopDown.
+type descent[K, V any, M ColorMetadata[M]] struct {
+	t       *Base[K, V, M]
+	z       *bst.Node[K, V, M]   // node being deleted
+	path    []*bst.Node[K, V, M] // path from the root to z in multiset mode, or nil
+	depth   int                  // depth of the current node, while on the path to z
+	reached bool                 // true once z has been passed, on the way to its successor
+}
+
+// next returns the child of x on the way down from x, which is z or an ancestor of z until z is reached,
+// then the right child of z, then left children down to the successor of z.
+func (d *descent[K, V, M]) next(x *bst.Node[K, V, M]) *bst.Node[K, V, M] {
+	t := d.t
+	switch {
+	case x == d.z:
+		d.reached = true
+		return t.Right(x)
+	case d.reached:
+		return t.Left(x)
+	case d.path != nil:
+		d.depth++
+		return d.path[d.depth]
+	case t.tree.Less(t.Key(d.z), t.Key(x)):
+		return t.Left(x)
+	default:
+		return t.Right(x)
+	}
+}
+
+// isTarget reports whether x is the node to remove: z if it has at most one child,
+// or else the successor of z.
+func (d *descent[K, V, M]) isTarget(x *bst.Node[K, V, M]) bool {
+	t := d.t
+	if x == d.z {
+		return t.IsNil(t.Left(x)) || t.IsNil(t.Right(x))
+	}
+	return d.reached && t.IsNil(t.Left(x))
+}
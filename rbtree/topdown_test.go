@@ -0,0 +1,117 @@
+package rbtree
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func TestStrategy_String(t *testing.T) {
+	assert.Equal(t, "bottom-up", BottomUp.String())
+	assert.Equal(t, "top-down", TopDown.String())
+	assert.Equal(t, "Strategy(7)", Strategy(7).String())
+}
+
+func TestTree_SetStrategy(t *testing.T) {
+	tree := New[int, string](func(a, b int) bool { return a < b })
+	assert.Equal(t, BottomUp, tree.Strategy(), "expected bottom-up by default")
+	tree.SetStrategy(TopDown)
+	assert.Equal(t, TopDown, tree.Strategy())
+
+	for i := 1; i <= 10; i++ {
+		_, inserted := tree.Insert(i, "")
+		require.True(t, inserted, "expected %d to be inserted", i)
+		require.NoError(t, tree.IsTreeValid(), "expected valid tree after inserting %d", i)
+	}
+	n, inserted := tree.Insert(5, "five")
+	assert.False(t, inserted, "expected existing key to be updated")
+	assert.Equal(t, "five", tree.Value(n))
+	assert.Equal(t, 10, tree.Size())
+
+	value, deleted := tree.Delete(n)
+	assert.True(t, deleted)
+	assert.Equal(t, "five", value)
+	require.NoError(t, tree.IsTreeValid())
+
+	// the strategy can be changed on a populated tree
+	tree.SetStrategy(BottomUp)
+	for i := 1; i <= 10; i++ {
+		if n, found := tree.Search(i); found {
+			tree.Delete(n)
+			require.NoError(t, tree.IsTreeValid(), "expected valid tree after deleting %d", i)
+		}
+	}
+	assert.Equal(t, 0, tree.Size())
+}
+
+func TestTree_SetStrategy_random(t *testing.T) {
+	for name, opts := range map[string][]bst.Option{
+		"unique":     nil,
+		"duplicates": {bst.WithDuplicateKeys()},
+		"reverse":    {bst.WithReverseOrder()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tree := New[int, int](func(a, b int) bool { return a < b }, opts...)
+			tree.SetStrategy(TopDown)
+			rng := rand.New(rand.NewSource(1))
+			var handles []*bst.Node[int, int, Color]
+			for i := 0; i < 5000; i++ {
+				if len(handles) == 0 || rng.Intn(3) != 0 {
+					key := rng.Intn(500)
+					n, inserted := tree.Insert(key, i)
+					if inserted {
+						handles = append(handles, n)
+					}
+					require.Equal(t, i, tree.Value(n), "expected value of key %d", key)
+				} else {
+					j := rng.Intn(len(handles))
+					n := handles[j]
+					handles[j] = handles[len(handles)-1]
+					handles = handles[:len(handles)-1]
+					value := tree.Value(n)
+					deleted, ok := tree.Delete(n)
+					require.True(t, ok, "expected node to be deleted")
+					require.Equal(t, value, deleted, "expected deleted value")
+				}
+				require.NoError(t, tree.IsTreeValid(), "expected valid tree after operation %d", i)
+				require.Equal(t, len(handles), tree.Size(), "unexpected size after operation %d", i)
+			}
+
+			// handles to the remaining nodes are still valid
+			for _, n := range handles {
+				assert.True(t, tree.Contains(n), "expected node %d to remain in the tree", tree.Key(n))
+			}
+		})
+	}
+}
+
+func TestTree_SetStrategy_tracer(t *testing.T) {
+	tree := New[int, string](func(a, b int) bool { return a < b })
+	tree.SetStrategy(TopDown)
+	var steps []Step[int]
+	tree.SetTracer(func(step Step[int]) {
+		steps = append(steps, step)
+	})
+
+	tree.Insert(1, "one")
+	tree.Insert(2, "two")
+	tree.Insert(3, "three")
+	tree.Insert(4, "four")
+
+	descriptions := make([]string, len(steps))
+	for i, step := range steps {
+		descriptions[i] = step.String()
+	}
+	assert.Equal(t, []string{
+		"insert: recolor 1 black",
+		"insert case 3 (mirrored): recolor 2 black",
+		"insert case 3 (mirrored): recolor 1 red",
+		"insert case 3 (mirrored): rotate-left 1",
+		"insert case 1: recolor 2 red",
+		"insert case 1: recolor 1 black",
+		"insert case 1: recolor 3 black",
+		"insert: recolor 2 black",
+	}, descriptions)
+}
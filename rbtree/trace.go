@@ -0,0 +1,126 @@
+package rbtree
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/mikenye/gotrees/bst"
+)
+
+// StepKind identifies the kind of a rebalancing step recorded while tracing.
+type StepKind string
+
+const (
+	StepRotateLeft  StepKind = "rotate-left"  // a left rotation about the node
+	StepRotateRight StepKind = "rotate-right" // a right rotation about the node
+	StepRecolor     StepKind = "recolor"      // a change of the node's color
+)
+
+// Step is a single rotation or recoloring performed by Insert or Delete, as passed to a tracer
+// registered with Tree.SetTracer.
+//
+// Steps encode to JSON, so that a sequence of steps can be written out and replayed, e.g. to
+// animate the rebalancing of a tree. Before and After hold the whole tree, as encoded by
+// bst.Tree.MarshalJSON, including node colors.
+type Step[K any] struct {
+	Operation string          `json:"operation"`          // "insert" or "delete"
+	Case      int             `json:"case"`               // fixup case (see insertFixup and deleteFixup, or insertTopDown and deleteTopDown), or 0 outside of the fixup cases
+	Mirrored  bool            `json:"mirrored,omitempty"` // true if the case was applied with left and right exchanged
+	Kind      StepKind        `json:"kind"`               // rotation or recoloring
+	Key       K               `json:"key"`                // key of the node rotated about or recolored
+	Color     Color           `json:"color"`              // color of the node after the step
+	Before    json.RawMessage `json:"before"`             // tree before the step, or null if it cannot be encoded
+	After     json.RawMessage `json:"after"`              // tree after the step, or null if it cannot be encoded
+}
+
+// String describes the step, such as "insert case 3 (mirrored): rotate-left 10".
+func (s Step[K]) String() string {
+	desc := s.Operation
+	if s.Case != 0 {
+		desc += fmt.Sprintf(" case %d", s.Case)
+	}
+	if s.Mirrored {
+		desc += " (mirrored)"
+	}
+	if s.Kind == StepRecolor {
+		return fmt.Sprintf("%s: recolor %v %s", desc, s.Key, colorName(s.Color))
+	}
+	return fmt.Sprintf("%s: %s %v", desc, s.Kind, s.Key)
+}
+
+// colorName returns "red" or "black".
+func colorName(c Color) string {
+	if c == Black {
+		return "black"
+	}
+	return "red"
+}
+
+// trace holds the tracer of a tree, and the fixup case being applied.
+type trace[K any] struct {
+	f         func(step Step[K])
+	operation string
+	fixupCase int
+	mirrored  bool
+}
+
+// SetTracer registers a function called for every rotation and recoloring performed while
+// rebalancing the tree, in the order they are applied. Passing nil disables tracing.
+//
+// Tracing is intended for teaching and debugging, e.g. to produce an animation of how a sequence
+// of insertions and deletions rebalances a small tree. Every step encodes the whole tree twice,
+// so tracing is slow, and the keys and values should be encodable with encoding/json.
+//
+// A tracer must not modify the tree.
+func (t *Base[K, V, M]) SetTracer(f func(step Step[K])) {
+	if f == nil {
+		t.trace = nil
+		return
+	}
+	t.trace = &trace[K]{f: f}
+}
+
+// traceCase records the operation and fixup case that subsequent steps belong to.
+func (t *Base[K, V, M]) traceCase(operation string, fixupCase int, mirrored bool) {
+	if t.trace != nil {
+		t.trace.operation, t.trace.fixupCase, t.trace.mirrored = operation, fixupCase, mirrored
+	}
+}
+
+// step applies a rotation or recoloring of node n, reporting it to the tracer, if any.
+func (t *Base[K, V, M]) step(kind StepKind, n *bst.Node[K, V, M], apply func()) {
+	if t.trace == nil {
+		apply()
+		return
+	}
+	before := t.encode()
+	apply()
+	t.trace.f(Step[K]{
+		Operation: t.trace.operation,
+		Case:      t.trace.fixupCase,
+		Mirrored:  t.trace.mirrored,
+		Kind:      kind,
+		Key:       t.Key(n),
+		Color:     t.color(n),
+		Before:    before,
+		After:     t.encode(),
+	})
+}
+
+// encode returns the tree encoded by bst.Tree.MarshalJSON, or nil if it cannot be encoded.
+func (t *Base[K, V, M]) encode() json.RawMessage {
+	data, err := t.tree.MarshalJSON()
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// rotateLeft performs a left rotation about n.
+func (t *Base[K, V, M]) rotateLeft(n *bst.Node[K, V, M]) {
+	t.step(StepRotateLeft, n, func() { t.tree.RotateLeft(n) })
+}
+
+// rotateRight performs a right rotation about n.
+func (t *Base[K, V, M]) rotateRight(n *bst.Node[K, V, M]) {
+	t.step(StepRotateRight, n, func() { t.tree.RotateRight(n) })
+}
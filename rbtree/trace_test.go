@@ -0,0 +1,106 @@
+package rbtree
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func TestTree_SetTracer(t *testing.T) {
+	tree := New[int, string](func(a, b int) bool { return a < b })
+	var steps []Step[int]
+	tree.SetTracer(func(step Step[int]) {
+		steps = append(steps, step)
+	})
+
+	tree.Insert(1, "one")
+	tree.Insert(2, "two")
+	tree.Insert(3, "three")
+
+	descriptions := make([]string, len(steps))
+	for i, step := range steps {
+		descriptions[i] = step.String()
+	}
+	assert.Equal(t, []string{
+		"insert: recolor 1 black",
+		"insert case 3 (mirrored): recolor 2 black",
+		"insert case 3 (mirrored): recolor 1 red",
+		"insert case 3 (mirrored): rotate-left 1",
+	}, descriptions)
+
+	// each step starts from the tree the previous step left
+	assert.JSONEq(t, string(steps[1].After), string(steps[2].Before))
+	assert.JSONEq(t, string(steps[2].After), string(steps[3].Before))
+	final, err := tree.MarshalJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, string(final), string(steps[3].After))
+
+	// steps encode to JSON
+	data, err := json.Marshal(steps[3])
+	require.NoError(t, err)
+	var decoded Step[int]
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, steps[3].Kind, decoded.Kind)
+	assert.Equal(t, steps[3].Case, decoded.Case)
+	assert.True(t, decoded.Mirrored)
+	assert.Equal(t, Red, decoded.Color)
+
+	// a nil tracer disables tracing
+	steps = nil
+	tree.SetTracer(nil)
+	tree.Insert(4, "four")
+	n, _ := tree.Search(1)
+	tree.Delete(n)
+	assert.Empty(t, steps)
+}
+
+func TestTree_SetTracer_random(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	traced := New[int, int](func(a, b int) bool { return a < b })
+	untraced := New[int, int](func(a, b int) bool { return a < b })
+
+	var steps []Step[int]
+	traced.SetTracer(func(step Step[int]) {
+		steps = append(steps, step)
+	})
+
+	for i := 0; i < 1000; i++ {
+		key := rng.Intn(100)
+		steps = nil
+		if rng.Intn(2) == 0 {
+			traced.Insert(key, i)
+			untraced.Insert(key, i)
+		} else {
+			if n, found := traced.Search(key); found {
+				traced.Delete(n)
+			}
+			if n, found := untraced.Search(key); found {
+				untraced.Delete(n)
+			}
+		}
+		require.NoError(t, traced.IsTreeValid())
+		require.True(t, traced.EqualStructure(untraced, func(a, b int) bool { return a == b }), "tracing changed the tree")
+
+		// replaying the steps of the operation ends at the tree
+		for j, step := range steps {
+			assert.Contains(t, []string{"insert", "delete"}, step.Operation)
+			assert.GreaterOrEqual(t, step.Case, 0)
+			assert.LessOrEqual(t, step.Case, 4)
+			if j > 0 {
+				require.JSONEq(t, string(steps[j-1].After), string(step.Before))
+			}
+		}
+		if len(steps) > 0 {
+			final, err := traced.MarshalJSON()
+			require.NoError(t, err)
+			require.JSONEq(t, string(final), string(steps[len(steps)-1].After))
+		}
+	}
+}
+
+func TestStep_String(t *testing.T) {
+	assert.Equal(t, "delete case 4: rotate-right 7", Step[int]{Operation: "delete", Case: 4, Kind: StepRotateRight, Key: 7}.String())
+	assert.Equal(t, "delete: recolor 7 red", Step[int]{Operation: "delete", Kind: StepRecolor, Key: 7, Color: Red}.String())
+}
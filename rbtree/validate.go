@@ -0,0 +1,112 @@
+package rbtree
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/bst"
+)
+
+// Kinds of violations of the Red-Black properties reported by Tree.ValidateAll,
+// in addition to the structural violations reported by bst.Tree.ValidateAll.
+const (
+	ViolationRedRoot     bst.ViolationKind = "red root"              // the root is red
+	ViolationRedSentinel bst.ViolationKind = "red sentinel"          // the sentinel nil node is red
+	ViolationRedRed      bst.ViolationKind = "red-red"               // the node is red and so is its parent
+	ViolationBlackHeight bst.ViolationKind = "black-height mismatch" // the node's subtrees have different black heights
+)
+
+// ValidateAll checks the same properties as Tree.IsTreeValid, but reports every violation found
+// rather than only the first, so that a corrupted tree can be diagnosed.
+//
+// The structural violations of the underlying BST are reported first (see bst.Tree.ValidateAll),
+// followed by the violations of the Red-Black properties:
+//   - ViolationRedRoot and ViolationRedSentinel, which are not tied to a node.
+//   - ViolationRedRed for each red node with a red parent, keyed by the child.
+//   - ViolationBlackHeight for each node whose left and right subtrees have different black heights.
+//
+// If the underlying BST contains a cycle, the Red-Black properties are not checked.
+//
+// Returns:
+//   - nil if the tree is valid.
+//   - The violations found otherwise.
+func (t *Base[K, V, M]) ValidateAll() []bst.Violation[K] {
+	violations := t.tree.ValidateAll()
+	for _, v := range violations {
+		if v.Kind == bst.ViolationCycle {
+			return violations
+		}
+	}
+
+	if t.IsRed(t.Root()) {
+		violations = append(violations, bst.Violation[K]{Kind: ViolationRedRoot})
+	}
+	if t.color(t.Sentinel()) != Black {
+		violations = append(violations, bst.Violation[K]{Kind: ViolationRedSentinel})
+	}
+
+	// blackHeight returns the number of black nodes on the left-most path from n down to a leaf,
+	// reporting the violations found in the subtree rooted at n (whose parent is red if parentRed)
+	var blackHeight func(n *bst.Node[K, V, M], parentRed bool, path []K) int
+	blackHeight = func(n *bst.Node[K, V, M], parentRed bool, path []K) int {
+		if t.IsNil(n) {
+			return 1
+		}
+		path = append(path[:len(path):len(path)], t.Key(n))
+		if t.IsRed(n) && parentRed {
+			violations = append(violations, bst.Violation[K]{Kind: ViolationRedRed, Key: t.Key(n), Path: path})
+		}
+		left, right := blackHeight(t.Left(n), t.IsRed(n), path), blackHeight(t.Right(n), t.IsRed(n), path)
+		if left != right {
+			violations = append(violations, bst.Violation[K]{Kind: ViolationBlackHeight, Key: t.Key(n), Path: path})
+		}
+		if t.IsBlack(n) {
+			left++
+		}
+		return left
+	}
+	blackHeight(t.Root(), false, nil)
+	return violations
+}
+
+// ValidatePath checks the same properties as Tree.IsTreeValid on the path from node n up to the root
+// only, e.g. to check the path touched by the last operation after every operation of a debug build.
+//
+// The underlying BST is checked along the path first (see bst.Tree.ValidatePath). Then, for each node
+// on the path, ValidatePath checks that it is not red with a red child, and that its subtrees have the
+// same black height, counting the black nodes on the left-most path of each subtree. This takes
+// O(log² n) time rather than O(n) time. Nodes off the path are not checked, so a valid path does not
+// imply a valid tree: Tree.IsTreeValid remains the full check.
+//
+// Returns:
+//   - nil if the path is valid, or n is the sentinel nil node.
+//   - An error wrapping the same errors as Tree.IsTreeValid otherwise.
+func (t *Base[K, V, M]) ValidatePath(n *bst.Node[K, V, M]) error {
+	if err := t.tree.ValidatePath(n); err != nil {
+		return fmt.Errorf("underlying BST is invalid: %w", err)
+	}
+	if !t.IsBlack(t.Root()) {
+		return ErrRedRoot
+	}
+	if t.color(t.Sentinel()) != Black {
+		return ErrRedSentinel
+	}
+
+	// blackHeight returns the number of black nodes on the left-most path from c down to a leaf
+	blackHeight := func(c *bst.Node[K, V, M]) int {
+		h := 0
+		for ; !t.IsNil(c); c = t.Left(c) {
+			if t.IsBlack(c) {
+				h++
+			}
+		}
+		return h
+	}
+	for ; !t.IsNil(n); n = t.Parent(n) {
+		if t.IsRed(n) && (t.IsRed(t.Left(n)) || t.IsRed(t.Right(n))) {
+			return fmt.Errorf("%w: node %v is red and has a red child", ErrRedRedViolation, t.Key(n))
+		}
+		if blackHeight(t.Left(n)) != blackHeight(t.Right(n)) {
+			return fmt.Errorf("%w: node %v has black count mismatch", ErrBlackHeightMismatch, t.Key(n))
+		}
+	}
+	return nil
+}
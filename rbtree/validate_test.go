@@ -0,0 +1,83 @@
+package rbtree
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func TestTree_ValidateAll(t *testing.T) {
+	tree := New[int, struct{}](func(a, b int) bool { return a < b })
+	for i := 1; i <= 10; i++ {
+		tree.Insert(i, struct{}{})
+	}
+	assert.Nil(t, tree.ValidateAll())
+
+	// recolor nodes to break several Red-Black properties at once
+	tree.tree.SetMetadata(tree.Root(), Red)
+	for _, key := range []int{1, 9} {
+		n, _ := tree.Search(key)
+		tree.tree.SetMetadata(n, Red)
+	}
+	t.Logf("tree after recoloring:\n%s", tree)
+
+	violations := tree.ValidateAll()
+	assert.Equal(t, []bst.Violation[int]{
+		{Kind: ViolationRedRoot},
+		{Kind: ViolationBlackHeight, Key: 2, Path: []int{4, 2}},
+		{Kind: ViolationRedRed, Key: 9, Path: []int{4, 6, 8, 9}},
+		{Kind: ViolationRedRed, Key: 10, Path: []int{4, 6, 8, 9, 10}},
+		{Kind: ViolationBlackHeight, Key: 8, Path: []int{4, 6, 8}},
+		{Kind: ViolationBlackHeight, Key: 4, Path: []int{4}},
+	}, violations)
+	assert.EqualError(t, violations[2], "red-red at node 9 (path: 4 → 6 → 8 → 9)")
+	assert.Error(t, tree.IsTreeValid())
+
+	// structural violations are reported first
+	n, _ := tree.Search(3)
+	tree.tree.SetKey(n, 0)
+	assert.Equal(t, bst.Violation[int]{Kind: bst.ViolationOutOfOrder, Key: 0, Path: []int{4, 2, 0}}, tree.ValidateAll()[0])
+	assert.ErrorIs(t, tree.IsTreeValid(), bst.ErrOrderViolation)
+
+	// cycles stop the Red-Black checks
+	n, _ = tree.Search(10)
+	tree.tree.SetRight(n, tree.Root())
+	violations = tree.ValidateAll()
+	assert.Contains(t, violations, bst.Violation[int]{Kind: bst.ViolationCycle, Key: 4, Path: []int{4, 6, 8, 9, 10, 4}})
+	assert.NotContains(t, violations, bst.Violation[int]{Kind: ViolationRedRoot})
+}
+
+func TestTree_ValidatePath(t *testing.T) {
+	tree := New[int, int](func(a, b int) bool { return a < b })
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		key := rng.Intn(500)
+		if n, found := tree.Search(key); found {
+			p := tree.Parent(n)
+			tree.Delete(n)
+			require.NoError(t, tree.ValidatePath(p), "expected valid path after deleting %d", key)
+		} else {
+			n, _ := tree.Insert(key, i)
+			require.NoError(t, tree.ValidatePath(n), "expected valid path after inserting %d", key)
+		}
+	}
+
+	n := tree.Min(tree.Root())
+	tree.tree.MustSetMetadata(n, !tree.color(n))
+	assert.Error(t, tree.ValidatePath(n))
+	assert.Error(t, tree.IsTreeValid())
+
+	tree = New[int, int](func(a, b int) bool { return a < b })
+	for _, key := range []int{10, 5, 15, 14} {
+		tree.Insert(key, key)
+	}
+	n, _ = tree.Search(14)
+	tree.tree.MustSetMetadata(n, Black)
+	assert.ErrorIs(t, tree.ValidatePath(n), ErrBlackHeightMismatch)
+	tree.tree.MustSetMetadata(n, Red)
+	n, _ = tree.Search(15)
+	tree.tree.MustSetMetadata(n, Red)
+	assert.ErrorIs(t, tree.ValidatePath(n), ErrRedRedViolation)
+}
@@ -0,0 +1,544 @@
+package scapegoat
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"io"
+	"iter"
+)
+
+// The methods below are those of bst.Tree that cannot break the ordering or the height bound of
+// a Scapegoat Tree. The underlying bst.Tree is not embedded, so that the methods linking or relinking
+// nodes directly (such as bst.Tree.InsertAt, bst.Tree.RotateLeft or bst.Tree.SetLeft) are not part of
+// the API of Tree at all, and misusing them is a compile-time error.
+
+// All returns an iterator over the keys and values of the tree, in ascending key order.
+//
+// See bst.Tree.All.
+func (t *Tree[K, V]) All() iter.Seq2[K, V] {
+	return t.tree.All()
+}
+
+// AscendAt calls f for each node in ascending key order, starting from the node with zero-based rank i, until f returns false.
+//
+// See bst.Tree.AscendAt.
+func (t *Tree[K, V]) AscendAt(i int, f bst.TraversalFunc[K, V, struct{}]) {
+	t.tree.AscendAt(i, f)
+}
+
+// Backward returns an iterator over the keys and values of the tree, in descending key order.
+//
+// See bst.Tree.Backward.
+func (t *Tree[K, V]) Backward() iter.Seq2[K, V] {
+	return t.tree.Backward()
+}
+
+// Ceiling finds the smallest key in the tree greater than or equal to key.
+//
+// See bst.Tree.Ceiling.
+func (t *Tree[K, V]) Ceiling(key K) (*bst.Node[K, V, struct{}], bool) {
+	return t.tree.Ceiling(key)
+}
+
+// Contains checks whether the given node n is present in the tree.
+//
+// See bst.Tree.Contains.
+func (t *Tree[K, V]) Contains(n *bst.Node[K, V, struct{}]) bool {
+	return t.tree.Contains(n)
+}
+
+// CopySubtree returns a new standalone tree holding a copy of the subtree rooted at node n, with the same shape, keys, values and metadata.
+//
+// See bst.Tree.CopySubtree.
+func (t *Tree[K, V]) CopySubtree(n *bst.Node[K, V, struct{}]) (*bst.Tree[K, V, struct{}], bool) {
+	return t.tree.CopySubtree(n)
+}
+
+// Count returns the number of nodes in the tree with a key equal to key.
+//
+// See bst.Tree.Count.
+func (t *Tree[K, V]) Count(key K) int {
+	return t.tree.Count(key)
+}
+
+// CountRange returns the number of nodes whose key falls within the half-open interval [lo, hi).
+//
+// See bst.Tree.CountRange.
+func (t *Tree[K, V]) CountRange(lo, hi K) int {
+	return t.tree.CountRange(lo, hi)
+}
+
+// Cursor returns a new cursor over the tree, positioned before the first node, so that the first call to Cursor.Next moves it to the node with the smallest key.
+//
+// See bst.Tree.Cursor.
+func (t *Tree[K, V]) Cursor() *bst.Cursor[K, V, struct{}] {
+	return t.tree.Cursor()
+}
+
+// DeltaSince returns the changes made to the tree since the given version, as recorded by the delta log (see WithDeltaLog).
+//
+// See bst.Tree.DeltaSince.
+func (t *Tree[K, V]) DeltaSince(version uint64) (*bst.Delta[K, V], error) {
+	return t.tree.DeltaSince(version)
+}
+
+// Depth returns the depth of node n.
+//
+// See bst.Tree.Depth.
+func (t *Tree[K, V]) Depth(n *bst.Node[K, V, struct{}]) int {
+	return t.tree.Depth(n)
+}
+
+// DuplicatePolicy returns the policy resolving insertions of existing keys.
+//
+// See bst.Tree.DuplicatePolicy.
+func (t *Tree[K, V]) DuplicatePolicy() bst.DuplicatePolicy {
+	return t.tree.DuplicatePolicy()
+}
+
+// Duplicates reports whether the tree is in multiset mode, allowing several nodes with equal keys.
+//
+// See bst.Tree.Duplicates.
+func (t *Tree[K, V]) Duplicates() bool {
+	return t.tree.Duplicates()
+}
+
+// FingerCeiling finds the smallest key in the tree greater than or equal to key, as Tree.Ceiling does, starting from the node finger rather than from the root.
+//
+// See bst.Tree.FingerCeiling.
+func (t *Tree[K, V]) FingerCeiling(finger *bst.Node[K, V, struct{}], key K) (*bst.Node[K, V, struct{}], bool) {
+	return t.tree.FingerCeiling(finger, key)
+}
+
+// FingerSearch looks for a node with the given key, starting from the node finger rather than from the root, as Tree.Search would.
+//
+// See bst.Tree.FingerSearch.
+func (t *Tree[K, V]) FingerSearch(finger *bst.Node[K, V, struct{}], key K) (*bst.Node[K, V, struct{}], bool) {
+	return t.tree.FingerSearch(finger, key)
+}
+
+// FirstEntry returns the smallest key in the tree and its value, without exposing the node holding them.
+//
+// See bst.Tree.FirstEntry.
+func (t *Tree[K, V]) FirstEntry() (K, V, bool) {
+	return t.tree.FirstEntry()
+}
+
+// FirstKey returns the smallest key in the tree.
+//
+// See bst.Tree.FirstKey.
+func (t *Tree[K, V]) FirstKey() (K, bool) {
+	return t.tree.FirstKey()
+}
+
+// Floor finds the largest key in the tree less than or equal to key.
+//
+// See bst.Tree.Floor.
+func (t *Tree[K, V]) Floor(key K) (*bst.Node[K, V, struct{}], bool) {
+	return t.tree.Floor(key)
+}
+
+// Higher finds the smallest key in the tree strictly greater than key.
+//
+// See bst.Tree.Higher.
+func (t *Tree[K, V]) Higher(key K) (*bst.Node[K, V, struct{}], bool) {
+	return t.tree.Higher(key)
+}
+
+// Height returns the number of edges on the longest path from the root to a leaf, or -1 if the tree is empty.
+//
+// See bst.Tree.Height.
+func (t *Tree[K, V]) Height() int {
+	return t.tree.Height()
+}
+
+// IsFull returns true if the given node n has both left and right children.
+//
+// See bst.Tree.IsFull.
+func (t *Tree[K, V]) IsFull(n *bst.Node[K, V, struct{}]) bool {
+	return t.tree.IsFull(n)
+}
+
+// IsInternal returns true if the given node n is an internal node, meaning it has at least one child (left or right).
+//
+// See bst.Tree.IsInternal.
+func (t *Tree[K, V]) IsInternal(n *bst.Node[K, V, struct{}]) bool {
+	return t.tree.IsInternal(n)
+}
+
+// IsLeaf returns true if the given node n has no children, meaning both its left and right pointers are nil.
+//
+// See bst.Tree.IsLeaf.
+func (t *Tree[K, V]) IsLeaf(n *bst.Node[K, V, struct{}]) bool {
+	return t.tree.IsLeaf(n)
+}
+
+// IsNil returns true if the given node n is the tree's sentinel nil node.
+//
+// See bst.Tree.IsNil.
+func (t *Tree[K, V]) IsNil(n *bst.Node[K, V, struct{}]) bool {
+	return t.tree.IsNil(n)
+}
+
+// IsUnary returns true if the given node n has exactly one child (either left or right, but not both).
+//
+// See bst.Tree.IsUnary.
+func (t *Tree[K, V]) IsUnary(n *bst.Node[K, V, struct{}]) bool {
+	return t.tree.IsUnary(n)
+}
+
+// Key returns the key of the given node n.
+//
+// See bst.Tree.Key.
+func (t *Tree[K, V]) Key(n *bst.Node[K, V, struct{}]) K {
+	return t.tree.Key(n)
+}
+
+// Keys returns an iterator over the keys of the tree, in ascending order.
+//
+// See bst.Tree.Keys.
+func (t *Tree[K, V]) Keys() iter.Seq[K] {
+	return t.tree.Keys()
+}
+
+// KthLargest returns the node holding the k-th largest key in the tree, where k is one-based (KthLargest(1) is the node with the maximum key).
+//
+// See bst.Tree.KthLargest.
+func (t *Tree[K, V]) KthLargest(k int) (*bst.Node[K, V, struct{}], bool) {
+	return t.tree.KthLargest(k)
+}
+
+// KthSmallest returns the node holding the k-th smallest key in the tree, where k is one-based (KthSmallest(1) is the node with the minimum key).
+//
+// See bst.Tree.KthSmallest.
+func (t *Tree[K, V]) KthSmallest(k int) (*bst.Node[K, V, struct{}], bool) {
+	return t.tree.KthSmallest(k)
+}
+
+// LastEntry returns the largest key in the tree and its value, without exposing the node holding them.
+//
+// See bst.Tree.LastEntry.
+func (t *Tree[K, V]) LastEntry() (K, V, bool) {
+	return t.tree.LastEntry()
+}
+
+// LastKey returns the largest key in the tree.
+//
+// See bst.Tree.LastKey.
+func (t *Tree[K, V]) LastKey() (K, bool) {
+	return t.tree.LastKey()
+}
+
+// Left returns the left child of the given node n.
+//
+// See bst.Tree.Left.
+func (t *Tree[K, V]) Left(n *bst.Node[K, V, struct{}]) *bst.Node[K, V, struct{}] {
+	return t.tree.Left(n)
+}
+
+// Less reports whether key a is ordered before key b in the tree.
+//
+// See bst.Tree.Less.
+func (t *Tree[K, V]) Less(a, b K) bool {
+	return t.tree.Less(a, b)
+}
+
+// Lower finds the largest key in the tree strictly less than key.
+//
+// See bst.Tree.Lower.
+func (t *Tree[K, V]) Lower(key K) (*bst.Node[K, V, struct{}], bool) {
+	return t.tree.Lower(key)
+}
+
+// LowestCommonAncestor returns the deepest node that has both a and b as descendants, where a node is considered a descendant of itself.
+//
+// See bst.Tree.LowestCommonAncestor.
+func (t *Tree[K, V]) LowestCommonAncestor(a, b *bst.Node[K, V, struct{}]) *bst.Node[K, V, struct{}] {
+	return t.tree.LowestCommonAncestor(a, b)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+//
+// See bst.Tree.MarshalBinary.
+func (t *Tree[K, V]) MarshalBinary() ([]byte, error) {
+	return t.tree.MarshalBinary()
+}
+
+// MarshalJSON implements json.Marshaler.
+//
+// See bst.Tree.MarshalJSON.
+func (t *Tree[K, V]) MarshalJSON() ([]byte, error) {
+	return t.tree.MarshalJSON()
+}
+
+// MarshalProto encodes the tree as a gotrees.bst.Tree protobuf message (see tree.proto), so that it can be embedded in existing protobuf and gRPC messages.
+//
+// See bst.Tree.MarshalProto.
+func (t *Tree[K, V]) MarshalProto(c bst.ProtoCodec[K, V, struct{}]) ([]byte, error) {
+	return t.tree.MarshalProto(c)
+}
+
+// MarshalWith encodes the tree with marshal, typically the Marshal function of a CBOR or MessagePack library.
+//
+// See bst.Tree.MarshalWith.
+func (t *Tree[K, V]) MarshalWith(marshal bst.MarshalFunc) ([]byte, error) {
+	return t.tree.MarshalWith(marshal)
+}
+
+// Max returns the node with the maximum key in the subtree rooted at n.
+//
+// See bst.Tree.Max.
+func (t *Tree[K, V]) Max(n *bst.Node[K, V, struct{}]) *bst.Node[K, V, struct{}] {
+	return t.tree.Max(n)
+}
+
+// Min returns the node with the minimum key in the subtree rooted at n.
+//
+// See bst.Tree.Min.
+func (t *Tree[K, V]) Min(n *bst.Node[K, V, struct{}]) *bst.Node[K, V, struct{}] {
+	return t.tree.Min(n)
+}
+
+// Nearest finds the node whose key is closest to key, as measured by distance.
+//
+// See bst.Tree.Nearest.
+func (t *Tree[K, V]) Nearest(key K, distance func(a, b K) int) (*bst.Node[K, V, struct{}], bool) {
+	return t.tree.Nearest(key, distance)
+}
+
+// OnChange registers a function notified after each successful change to the tree, so that applications can mirror the tree (e.g., into a cache) without funneling every change through a wrapper.
+//
+// See bst.Tree.OnChange.
+func (t *Tree[K, V]) OnChange(f bst.ChangeFunc[K, V]) {
+	t.tree.OnChange(f)
+}
+
+// Parent returns the parent of the given node n.
+//
+// See bst.Tree.Parent.
+func (t *Tree[K, V]) Parent(n *bst.Node[K, V, struct{}]) *bst.Node[K, V, struct{}] {
+	return t.tree.Parent(n)
+}
+
+// Path returns the nodes on the path from the root to n, inclusive of both.
+//
+// See bst.Tree.Path.
+func (t *Tree[K, V]) Path(n *bst.Node[K, V, struct{}]) []*bst.Node[K, V, struct{}] {
+	return t.tree.Path(n)
+}
+
+// Predecessor returns the in-order predecessor of the given node n.
+//
+// See bst.Tree.Predecessor.
+func (t *Tree[K, V]) Predecessor(n *bst.Node[K, V, struct{}]) *bst.Node[K, V, struct{}] {
+	return t.tree.Predecessor(n)
+}
+
+// Rank returns the number of nodes in the tree with a key strictly less than key.
+//
+// See bst.Tree.Rank.
+func (t *Tree[K, V]) Rank(key K) int {
+	return t.tree.Rank(key)
+}
+
+// Render returns a visual representation of the binary search tree (BST), drawn according to opts.
+//
+// See bst.Tree.Render.
+func (t *Tree[K, V]) Render(opts bst.RenderOptions) string {
+	return t.tree.Render(opts)
+}
+
+// Right returns the right child of the given node n.
+//
+// See bst.Tree.Right.
+func (t *Tree[K, V]) Right(n *bst.Node[K, V, struct{}]) *bst.Node[K, V, struct{}] {
+	return t.tree.Right(n)
+}
+
+// Root returns the root node of the tree.
+//
+// See bst.Tree.Root.
+func (t *Tree[K, V]) Root() *bst.Node[K, V, struct{}] {
+	return t.tree.Root()
+}
+
+// Rotations returns the number of rotations performed on the tree since it was created, by Tree.RotateLeft and Tree.RotateRight, whether directly or while rebalancing the tree.
+//
+// See bst.Tree.Rotations.
+func (t *Tree[K, V]) Rotations() uint64 {
+	return t.tree.Rotations()
+}
+
+// Search looks for a node with the given key in the tree.
+//
+// See bst.Tree.Search.
+func (t *Tree[K, V]) Search(key K) (*bst.Node[K, V, struct{}], bool) {
+	return t.tree.Search(key)
+}
+
+// Select returns the node with the given zero-based rank i, that is, the (i+1)-th smallest key in the tree.
+//
+// See bst.Tree.Select.
+func (t *Tree[K, V]) Select(i int) (*bst.Node[K, V, struct{}], bool) {
+	return t.tree.Select(i)
+}
+
+// Sentinel returns the sentinel nil node.
+//
+// See bst.Tree.Sentinel.
+func (t *Tree[K, V]) Sentinel() *bst.Node[K, V, struct{}] {
+	return t.tree.Sentinel()
+}
+
+// SetAugmentFunc registers f to maintain user-defined augmented data, such as subtree sums, maximum interval endpoints or other aggregates, typically stored in node metadata.
+//
+// See bst.Tree.SetAugmentFunc.
+func (t *Tree[K, V]) SetAugmentFunc(f bst.AugmentFunc[K, V, struct{}]) {
+	t.tree.SetAugmentFunc(f)
+}
+
+// SetNodeFormatter registers f to format each node when drawing the tree with Tree.String and Tree.WriteSVG, instead of the default layout (see Node.String).
+//
+// See bst.Tree.SetNodeFormatter.
+func (t *Tree[K, V]) SetNodeFormatter(f bst.NodeFormatter[K, V, struct{}]) {
+	t.tree.SetNodeFormatter(f)
+}
+
+// SetValue updates the value of the given node n.
+//
+// See bst.Tree.SetValue.
+func (t *Tree[K, V]) SetValue(n *bst.Node[K, V, struct{}], value V) {
+	t.tree.SetValue(n, value)
+}
+
+// Sibling returns the sibling of the given node n.
+//
+// See bst.Tree.Sibling.
+func (t *Tree[K, V]) Sibling(n *bst.Node[K, V, struct{}]) *bst.Node[K, V, struct{}] {
+	return t.tree.Sibling(n)
+}
+
+// Size returns the total number of nodes in the tree.
+//
+// See bst.Tree.Size.
+func (t *Tree[K, V]) Size() int {
+	return t.tree.Size()
+}
+
+// SliceByRank returns the nodes with zero-based ranks in the half-open interval [i, j), in ascending key order.
+//
+// See bst.Tree.SliceByRank.
+func (t *Tree[K, V]) SliceByRank(i, j int) []*bst.Node[K, V, struct{}] {
+	return t.tree.SliceByRank(i, j)
+}
+
+// Snapshot returns an immutable view of the keys and values in the tree, as they are now.
+//
+// See bst.Tree.Snapshot.
+func (t *Tree[K, V]) Snapshot() *bst.Snapshot[K, V] {
+	return t.tree.Snapshot()
+}
+
+// SnapshotTo writes a full snapshot of the tree to w: its version (see Tree.Version), followed by the binary encoding of the tree (see Tree.MarshalBinary), as an encoding/gob stream.
+//
+// See bst.Tree.SnapshotTo.
+func (t *Tree[K, V]) SnapshotTo(w io.Writer) error {
+	return t.tree.SnapshotTo(w)
+}
+
+// String returns a visual representation of the binary search tree (BST).
+//
+// See bst.Tree.String.
+func (t *Tree[K, V]) String() string {
+	return t.tree.String()
+}
+
+// SubtreeSize returns the number of nodes in the subtree rooted at n, including n itself.
+//
+// See bst.Tree.SubtreeSize.
+func (t *Tree[K, V]) SubtreeSize(n *bst.Node[K, V, struct{}]) int {
+	return t.tree.SubtreeSize(n)
+}
+
+// Successor returns the in-order successor of the given node n.
+//
+// See bst.Tree.Successor.
+func (t *Tree[K, V]) Successor(n *bst.Node[K, V, struct{}]) *bst.Node[K, V, struct{}] {
+	return t.tree.Successor(n)
+}
+
+// TraverseInOrder performs an in-order traversal of the tree starting from node n.
+//
+// See bst.Tree.TraverseInOrder.
+func (t *Tree[K, V]) TraverseInOrder(n *bst.Node[K, V, struct{}], f bst.TraversalFunc[K, V, struct{}]) bool {
+	return t.tree.TraverseInOrder(n, f)
+}
+
+// TraverseInOrderErr performs an in-order traversal of the tree starting from node n, stopping at the first error returned by f.
+//
+// See bst.Tree.TraverseInOrderErr.
+func (t *Tree[K, V]) TraverseInOrderErr(n *bst.Node[K, V, struct{}], f bst.TraversalErrFunc[K, V, struct{}]) error {
+	return t.tree.TraverseInOrderErr(n, f)
+}
+
+// Value returns the value associated with the given node n.
+//
+// See bst.Tree.Value.
+func (t *Tree[K, V]) Value(n *bst.Node[K, V, struct{}]) V {
+	return t.tree.Value(n)
+}
+
+// Values returns an iterator over the values of the tree, in ascending key order.
+//
+// See bst.Tree.Values.
+func (t *Tree[K, V]) Values() iter.Seq[V] {
+	return t.tree.Values()
+}
+
+// Version returns the version of the tree, which is incremented by every change notified to the function registered with Tree.OnChange, starting from 0 for a new tree.
+//
+// See bst.Tree.Version.
+func (t *Tree[K, V]) Version() uint64 {
+	return t.tree.Version()
+}
+
+// Equal reports whether the tree and other contain the same keys with the same values.
+//
+// See bst.Tree.Equal for details. If valueEq is nil, only the key sets are compared.
+func (t *Tree[K, V]) Equal(other *Tree[K, V], valueEq func(a, b V) bool) bool {
+	return t.tree.Equal(other.tree, valueEq)
+}
+
+// EqualStructure reports whether the tree and other are equal and have an identical shape.
+//
+// See bst.Tree.EqualStructure for details.
+func (t *Tree[K, V]) EqualStructure(other *Tree[K, V], valueEq func(a, b V) bool) bool {
+	return t.tree.EqualStructure(other.tree, valueEq)
+}
+
+// ValidateAll performs the same structural validation as bst.Tree.IsTreeValid, but reports every violation found rather than only the first.
+//
+// See bst.Tree.ValidateAll. The height bound checked by Tree.IsTreeValid is not validated.
+func (t *Tree[K, V]) ValidateAll() []bst.Violation[K] {
+	return t.tree.ValidateAll()
+}
+
+// ValidatePath performs the structural validation of bst.Tree.IsTreeValid on the path from node n up to the root only.
+//
+// See bst.Tree.ValidatePath.
+func (t *Tree[K, V]) ValidatePath(n *bst.Node[K, V, struct{}]) error {
+	return t.tree.ValidatePath(n)
+}
+
+// WriteSVG writes a standalone SVG drawing of the tree to w.
+//
+// See bst.Tree.WriteSVG.
+func (t *Tree[K, V]) WriteSVG(w io.Writer, style func(n *bst.Node[K, V, struct{}]) bst.SVGNodeStyle) error {
+	return t.tree.WriteSVG(w, style)
+}
+
+// Diff returns the patch turning tree a into tree b (see bst.Diff), which can be applied with Tree.ApplyPatch.
+//
+// If valueEq is nil, values are not compared, and only added and removed keys are reported.
+func Diff[K, V any](a, b *Tree[K, V], valueEq func(a, b V) bool) *bst.Patch[K, V] {
+	return bst.Diff(a.tree, b.tree, valueEq)
+}
@@ -0,0 +1,37 @@
+package scapegoat_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/scapegoat"
+)
+
+func ExampleTree_Insert() {
+
+	// create the tree with integer keys and string values
+	tree := scapegoat.New[int, string](func(a, b int) bool {
+		return a < b
+	}, scapegoat.DefaultAlpha)
+
+	// insert keys in ascending order, which would degenerate an unbalanced tree into a list.
+	// the scapegoat tree rebuilds subtrees to keep the height within log_{1/α}(n).
+	tree.Insert(0, "zero")
+	tree.Insert(1, "one")
+	tree.Insert(2, "two")
+	tree.Insert(3, "three")
+	tree.Insert(4, "four")
+	tree.Insert(5, "five")
+	tree.Insert(6, "six")
+
+	// show the tree
+	fmt.Printf("Scapegoat Tree after insert:\n%s", tree)
+
+	// Output:
+	// Scapegoat Tree after insert:
+	// 0: zero [{}]
+	//  │         ╭── 1: one [{}]
+	//  │    ╭── 2: two [{}]
+	//  ╰── 3: three [{}]
+	//       ╰── 4: four [{}]
+	//            ╰── 5: five [{}]
+	//                 ╰── 6: six [{}]
+}
@@ -0,0 +1,372 @@
+// Package scapegoat provides a generic, self-balancing Scapegoat Tree implementation.
+//
+// A scapegoat tree extends bst.Tree without storing any balancing information in its nodes:
+// node metadata is struct{}, so nodes are as small as those of an unbalanced bst.Tree.
+// Instead, the tree stays within a constant factor of optimal height by occasionally rebuilding
+// a subtree into a perfectly balanced shape (see bst.Tree.RebuildSubtree):
+//   - After an insertion leaves a node deeper than log_{1/α}(n), the lowest ancestor whose
+//     subtree is too unbalanced (the "scapegoat") is rebuilt.
+//   - After deletions shrink the tree below α times its maximum size since the last full rebuild,
+//     the whole tree is rebuilt.
+//
+// α (alpha), between 0.5 and 1, trades lookup speed for update speed: lower values keep the tree
+// closer to perfectly balanced at the cost of more frequent rebuilds. Searches take O(log n) time
+// in the worst case, while insertions and deletions take O(log n) amortized time.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/scapegoat"
+//
+//	tree := scapegoat.New[int, string](func(a, b int) bool { return a < b }, scapegoat.DefaultAlpha)
+//	tree.Insert(10, "ten")
+//	tree.Insert(20, "twenty")
+//	node, found := tree.Search(10)
+//
+//	if found {
+//		tree.Delete(node)
+//	}
+//
+// # Methods from bst.Tree
+//
+// The underlying bst.Tree is not embedded. Instead, the methods of bst.Tree that cannot break the
+// ordering or the height bound of the tree are exposed by Tree, forwarding to the underlying tree
+// (e.g., Search, Floor, Ceiling, Rank, Select, TraverseInOrder, Successor and Predecessor), and the
+// methods inserting or deleting nodes rebuild subtrees as required. The methods of bst.Tree that
+// link, relink or re-key nodes directly (such as bst.Tree.InsertAt, bst.Tree.AttachSubtree,
+// bst.Tree.RotateLeft or bst.Tree.SetLeft) are not available at all, so that misusing them is a
+// compile-time error rather than a corrupted tree.
+//
+// Functions of package bst taking a *bst.Tree have scapegoat counterparts where they apply, such as Diff.
+package scapegoat
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/bst"
+	"io"
+	"math"
+)
+
+// DefaultAlpha is a general-purpose balance factor for New, keeping the height of the tree
+// within about 1.7 times the optimum.
+const DefaultAlpha = 2.0 / 3.0
+
+// Tree represents a Scapegoat Tree, an extension of bst.Tree that keeps itself balanced
+// by partially rebuilding subtrees.
+//
+// The tree wraps a generic Binary Search Tree bst.Tree. No metadata is needed, so struct{}
+// is used as the metadata type.
+type Tree[K, V any] struct {
+	tree    *bst.Tree[K, V, struct{}] // Underlying BST structure, not embedded so that only its safe methods are exposed (see bst.go)
+	alpha   float64                   // Balance factor, in [0.5, 1)
+	maxSize int                       // Maximum size of the tree since it was last rebuilt from the root
+}
+
+// New creates a new Scapegoat Tree with the given key comparison function and balance factor.
+//
+// Parameters:
+//   - less: A comparison function (bst.LessFunc[K]) that defines the ordering of keys.
+//   - alpha: The balance factor, which must be at least 0.5 and less than 1 (see DefaultAlpha).
+//   - opts: Optional behaviors to enable on the underlying bst.Tree (see bst.Option).
+//
+// Returns:
+//   - A pointer to a newly created Tree[K, V] instance.
+//
+// New panics if alpha is outside [0.5, 1).
+func New[K, V any](less bst.LessFunc[K], alpha float64, opts ...bst.Option) *Tree[K, V] {
+	if !(alpha >= 0.5 && alpha < 1) {
+		panic(fmt.Sprintf("scapegoat: invalid alpha %v, must be in [0.5, 1)", alpha))
+	}
+	return &Tree[K, V]{
+		tree:  bst.New[K, V, struct{}](less, opts...),
+		alpha: alpha,
+	}
+}
+
+// Alpha returns the balance factor of the tree.
+func (t *Tree[K, V]) Alpha() float64 {
+	return t.alpha
+}
+
+// Insert inserts a new node with the given key and value into the tree, rebuilding the
+// subtree rooted at the scapegoat if the new node is too deep.
+//
+// If a node with the same key already exists (and duplicate keys are not enabled), it is resolved by
+// the tree's duplicate policy (see bst.WithDuplicatePolicy): by default, its value is updated, and the
+// existing node is returned with false. Insert panics with an error wrapping bst.ErrDuplicateKey if the
+// key exists under the bst.DuplicateError policy.
+//
+// Returns:
+//   - (*bst.Node[K, V, struct{}], true) if a new node was inserted.
+//   - (*bst.Node[K, V, struct{}], false) if the key existed and the value was updated.
+func (t *Tree[K, V]) Insert(key K, value V) (*bst.Node[K, V, struct{}], bool) {
+	n, inserted, err := t.InsertWithPolicy(key, value, t.DuplicatePolicy())
+	if err != nil {
+		panic(err)
+	}
+	return n, inserted
+}
+
+// TryInsert inserts a new node with the given key and value into the tree, as Tree.Insert does, but
+// returns an error rather than panicking if the key exists under the bst.DuplicateError policy.
+//
+// See bst.Tree.TryInsert.
+func (t *Tree[K, V]) TryInsert(key K, value V) (*bst.Node[K, V, struct{}], bool, error) {
+	return t.InsertWithPolicy(key, value, t.DuplicatePolicy())
+}
+
+// InsertIfAbsent inserts a new node with the given key and value into the tree, only if the key is not
+// already in the tree, whatever the tree's duplicate policy.
+//
+// See bst.Tree.InsertIfAbsent.
+func (t *Tree[K, V]) InsertIfAbsent(key K, value V) (*bst.Node[K, V, struct{}], bool) {
+	n, inserted, _ := t.InsertWithPolicy(key, value, bst.DuplicateIgnore)
+	return n, inserted
+}
+
+// InsertReturningOld inserts a new node with the given key and value into the tree, as Tree.Insert does,
+// and returns the value the key held before.
+//
+// See bst.Tree.InsertReturningOld.
+func (t *Tree[K, V]) InsertReturningOld(key K, value V) (V, bool) {
+	return bst.InsertReturningOldFunc(t.tree, key, value, t.InsertWithPolicy)
+}
+
+// InsertWithPolicy inserts a new node with the given key and value into the tree, resolving an existing
+// equal key with policy rather than with the tree's duplicate policy, and rebuilding the subtree of the scapegoat if the new node is too deep.
+//
+// See bst.Tree.InsertWithPolicy.
+func (t *Tree[K, V]) InsertWithPolicy(key K, value V, policy bst.DuplicatePolicy) (*bst.Node[K, V, struct{}], bool, error) {
+	n, inserted, err := t.tree.InsertWithPolicy(key, value, policy)
+	if !inserted {
+		return n, false, err
+	}
+	t.maxSize = max(t.maxSize, t.Size())
+	t.rebuildScapegoat(n)
+	return n, true, nil
+}
+
+// rebuildScapegoat rebuilds the subtree of the scapegoat of node n, if n has just been linked
+// as a leaf deeper than the height allowed by alpha.
+func (t *Tree[K, V]) rebuildScapegoat(n *bst.Node[K, V, struct{}]) {
+	if t.Depth(n) <= t.maxDepth(t.Size()) {
+		return
+	}
+
+	// find the scapegoat: the lowest ancestor whose child on the path to n holds more than
+	// alpha of its subtree. One must exist, as otherwise n would be no deeper than maxDepth.
+	child, parent := n, t.Parent(n)
+	for !t.IsNil(parent) && float64(t.SubtreeSize(child)) <= t.alpha*float64(t.SubtreeSize(parent)) {
+		child, parent = parent, t.Parent(parent)
+	}
+	if t.IsNil(parent) {
+		parent = t.Root()
+	}
+	t.tree.RebuildSubtree(parent)
+}
+
+// UpdateKey changes the key of node n to key, preserving its value (see bst.Tree.UpdateKey),
+// rebuilding the subtree of a scapegoat if n is moved deeper than the height allowed by alpha.
+//
+// Returns:
+//   - true if the key of n was updated.
+//   - false if n is nil, has been removed, or belongs to a different tree, or if another node already
+//     holds key and duplicate keys are not enabled (see bst.WithDuplicateKeys).
+func (t *Tree[K, V]) UpdateKey(n *bst.Node[K, V, struct{}], key K) bool {
+	if !t.tree.UpdateKey(n, key) {
+		return false
+	}
+	t.rebuildScapegoat(n)
+	return true
+}
+
+// Delete removes the given node from the tree, rebuilding the whole tree if it has shrunk
+// below alpha times its maximum size since it was last rebuilt.
+//
+// Returns:
+//   - true if the node was removed.
+//   - false if the node is nil, has already been removed, or belongs to a different tree.
+func (t *Tree[K, V]) Delete(n *bst.Node[K, V, struct{}]) bool {
+	if _, deleted := t.tree.Delete(n); !deleted {
+		return false
+	}
+	if float64(t.Size()) < t.alpha*float64(t.maxSize) {
+		t.tree.RebuildSubtree(t.Root())
+		t.maxSize = t.Size()
+	}
+	return true
+}
+
+// RangeDelete removes every node whose key falls within the half-open interval [lo, hi),
+// rebuilding the tree as required.
+//
+// If hi is not greater than lo, no nodes are removed.
+//
+// Returns:
+//   - The number of nodes removed from the tree.
+func (t *Tree[K, V]) RangeDelete(lo, hi K) int {
+	count := t.tree.RangeDelete(lo, hi)
+	if count > 0 && float64(t.Size()) < t.alpha*float64(t.maxSize) {
+		t.tree.RebuildSubtree(t.Root())
+		t.maxSize = t.Size()
+	}
+	return count
+}
+
+// AscendDelete calls f for each node of the tree in ascending key order, deleting the nodes for
+// which f returns an Action including bst.ActionDelete (see bst.Tree.AscendDelete), rebuilding the tree as required.
+//
+// Returns:
+//   - The number of nodes deleted.
+func (t *Tree[K, V]) AscendDelete(f func(n *bst.Node[K, V, struct{}]) bst.Action) int {
+	return bst.AscendDeleteFunc(t.tree, t.Delete, f)
+}
+
+// PopMin removes the node with the smallest key from the tree, and returns its key and value
+// (see bst.Tree.PopMin), rebuilding the tree as required.
+//
+// Returns:
+//   - (key, value, true) if the tree was not empty.
+//   - (zero key, zero value, false) if the tree is empty.
+func (t *Tree[K, V]) PopMin() (K, V, bool) {
+	return bst.PopMinFunc(t.tree, t.Delete)
+}
+
+// PopMax removes the node with the largest key from the tree, and returns its key and value
+// (see bst.Tree.PopMax), rebuilding the tree as required.
+//
+// Returns:
+//   - (key, value, true) if the tree was not empty.
+//   - (zero key, zero value, false) if the tree is empty.
+func (t *Tree[K, V]) PopMax() (K, V, bool) {
+	return bst.PopMaxFunc(t.tree, t.Delete)
+}
+
+// DeleteAt removes the node with zero-based rank i from the tree, and returns its key and value
+// (see bst.Tree.DeleteAt), rebuilding the tree as required.
+//
+// Returns:
+//   - (key, value, true) if 0 ≤ i < Tree.Size.
+//   - (zero key, zero value, false) if i is out of range.
+func (t *Tree[K, V]) DeleteAt(i int) (K, V, bool) {
+	return bst.DeleteAtFunc(t.tree, i, t.Delete)
+}
+
+// Clear removes every node from the tree, leaving it empty (see bst.Tree.Clear).
+func (t *Tree[K, V]) Clear() {
+	t.tree.Clear()
+	t.maxSize = 0
+}
+
+// LoadSorted replaces the contents of the tree with the given keys and values, building a perfectly
+// balanced tree in O(n) time on up to parallelism goroutines (see bst.Tree.LoadSorted).
+//
+// Returns:
+//   - nil if the tree was loaded.
+//   - An error if the keys are out of order, or values and keys differ in length. The tree is then
+//     left unchanged.
+func (t *Tree[K, V]) LoadSorted(keys []K, values []V, parallelism int) error {
+	if err := t.tree.LoadSorted(keys, values, parallelism); err != nil {
+		return err
+	}
+	t.maxSize = t.Size()
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the contents of the tree with a tree encoded
+// by bst.Tree.MarshalJSON (see bst.Tree.UnmarshalJSON), rebuilding the tree as required.
+func (t *Tree[K, V]) UnmarshalJSON(data []byte) error {
+	return t.restored(t.tree.UnmarshalJSON(data))
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, replacing the contents of the tree with a tree
+// encoded by bst.Tree.MarshalBinary (see bst.Tree.UnmarshalBinary), rebuilding the tree as required.
+func (t *Tree[K, V]) UnmarshalBinary(data []byte) error {
+	return t.restored(t.tree.UnmarshalBinary(data))
+}
+
+// UnmarshalWith replaces the contents of the tree with a tree encoded by bst.Tree.MarshalWith
+// (see bst.Tree.UnmarshalWith), rebuilding the tree as required.
+func (t *Tree[K, V]) UnmarshalWith(data []byte, unmarshal bst.UnmarshalFunc) error {
+	return t.restored(t.tree.UnmarshalWith(data, unmarshal))
+}
+
+// UnmarshalProto replaces the contents of the tree with a tree encoded by bst.Tree.MarshalProto
+// (see bst.Tree.UnmarshalProto), rebuilding the tree as required.
+func (t *Tree[K, V]) UnmarshalProto(data []byte, c bst.ProtoCodec[K, V, struct{}]) error {
+	return t.restored(t.tree.UnmarshalProto(data, c))
+}
+
+// ReadSnapshot replaces the contents of the tree with a snapshot written by bst.Tree.SnapshotTo
+// (see bst.Tree.ReadSnapshot), rebuilding the tree as required.
+//
+// Returns:
+//   - The version of the tree when the snapshot was written, and nil if the snapshot was read.
+//   - An error if the snapshot cannot be decoded. The tree is then left unchanged.
+func (t *Tree[K, V]) ReadSnapshot(r io.Reader) (uint64, error) {
+	return bst.ReadSnapshotFunc(r, t.UnmarshalBinary)
+}
+
+// restored completes the replacement of the contents of the tree by unmarshaling, which returned err:
+// the maximum size of the tree is reset to its new size, as for LoadSorted, and the tree is rebuilt if
+// its shape, restored as encoded, is deeper than a scapegoat tree of that size may be.
+func (t *Tree[K, V]) restored(err error) error {
+	if err != nil {
+		return err
+	}
+	t.maxSize = t.Size()
+	if t.Height() > t.maxDepth(t.maxSize)+1 {
+		t.tree.RebuildSubtree(t.Root())
+	}
+	return nil
+}
+
+// ApplyDelta replays the changes of d on the tree (see bst.Delta.Apply), rebuilding the tree as required.
+func (t *Tree[K, V]) ApplyDelta(d *bst.Delta[K, V]) {
+	d.Apply(func(key K, value V) {
+		t.InsertWithPolicy(key, value, bst.DuplicateReplace)
+	}, func(key K) {
+		if n, found := t.Search(key); found {
+			t.Delete(n)
+		}
+	}, t.Clear)
+}
+
+// ApplyPatch applies p to the tree (see bst.Patch.Apply), rebuilding the tree as required.
+func (t *Tree[K, V]) ApplyPatch(p *bst.Patch[K, V]) {
+	p.Apply(func(key K, value V) {
+		t.InsertWithPolicy(key, value, bst.DuplicateReplace)
+	}, func(key K) {
+		if n, found := t.Search(key); found {
+			t.Delete(n)
+		}
+	})
+}
+
+// IsTreeValid checks whether the tree is a valid binary search tree (see bst.Tree.IsTreeValid),
+// and whether its height is within the bound maintained by a scapegoat tree.
+//
+// Returns:
+//   - nil if the tree is valid.
+//   - An error describing the first violation found otherwise.
+func (t *Tree[K, V]) IsTreeValid() error {
+	if err := t.tree.IsTreeValid(); err != nil {
+		return err
+	}
+	limit := t.maxDepth(t.maxSize) + 1
+
+	// walk the tree iteratively, so that a degenerate tree is reported rather than overflowing the stack
+	for n := t.Min(t.Root()); !t.IsNil(n); n = t.Successor(n) {
+		if d := t.Depth(n); d > limit {
+			return fmt.Errorf("node %v has depth %d, exceeding the limit of %d", t.Key(n), d, limit)
+		}
+	}
+	return nil
+}
+
+// maxDepth returns the maximum depth of a node in a balanced tree of size n, log_{1/α}(n).
+func (t *Tree[K, V]) maxDepth(n int) int {
+	if n <= 1 {
+		return 0
+	}
+	return int(math.Floor(math.Log(float64(n)) / math.Log(1/t.alpha)))
+}
@@ -0,0 +1,349 @@
+package scapegoat
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/mikenye/gotrees/bst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+// height returns the number of levels in the tree.
+func height[K, V any](tree *Tree[K, V]) int {
+	h := 0
+	tree.TraverseInOrder(tree.Root(), func(n *bst.Node[K, V, struct{}]) bool {
+		h = max(h, tree.Depth(n)+1)
+		return true
+	})
+	return h
+}
+
+func TestNew_invalidAlpha(t *testing.T) {
+	for _, alpha := range []float64{0, 0.49, 1, 1.5, math.NaN()} {
+		assert.Panics(t, func() { New[int, int](intLess, alpha) }, "expected panic for alpha %v", alpha)
+	}
+	assert.Equal(t, 0.5, New[int, int](intLess, 0.5).Alpha())
+}
+
+func TestTree_Insert_sequential(t *testing.T) {
+	for _, alpha := range []float64{0.5, 0.6, DefaultAlpha, 0.9} {
+		tree := New[int, int](intLess, alpha)
+		for i := 0; i < 10000; i++ {
+			_, inserted := tree.Insert(i, i)
+			require.True(t, inserted)
+		}
+		require.NoError(t, tree.IsTreeValid(), "alpha %v", alpha)
+		limit := int(math.Log(10000)/math.Log(1/alpha)) + 1
+		assert.LessOrEqual(t, height(tree), limit, "alpha %v: expected height to be bounded despite sorted inserts", alpha)
+
+		// updating an existing key does not insert
+		_, inserted := tree.Insert(5, -5)
+		assert.False(t, inserted)
+		n, _ := tree.Search(5)
+		assert.Equal(t, -5, tree.Value(n))
+	}
+}
+
+func TestTree_InsertDelete_random(t *testing.T) {
+	tree := New[int, int](intLess, DefaultAlpha)
+	rng := rand.New(rand.NewSource(1))
+	expected := make(map[int]int)
+
+	for i := 0; i < 20000; i++ {
+		key := rng.Intn(2000)
+		if rng.Intn(2) == 0 {
+			n, found := tree.Search(key)
+			_, exists := expected[key]
+			require.Equal(t, exists, found)
+			if found {
+				assert.True(t, tree.Delete(n))
+				assert.False(t, tree.Delete(n), "expected stale node not to be deleted")
+				delete(expected, key)
+			}
+		} else {
+			tree.Insert(key, i)
+			expected[key] = i
+		}
+		if i%1000 == 0 {
+			require.NoError(t, tree.IsTreeValid(), "iteration %d", i)
+		}
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, len(expected), tree.Size())
+	for key, value := range expected {
+		n, found := tree.Search(key)
+		require.True(t, found)
+		assert.Equal(t, value, tree.Value(n))
+	}
+}
+
+func TestTree_Delete_rebuild(t *testing.T) {
+	tree := New[int, struct{}](intLess, DefaultAlpha)
+	var nodes []*bst.Node[int, struct{}, struct{}]
+	for i := 0; i < 1000; i++ {
+		n, _ := tree.Insert(i, struct{}{})
+		nodes = append(nodes, n)
+	}
+
+	// delete the upper half, leaving the remaining nodes in an unbalanced shape
+	for _, n := range nodes[500:] {
+		require.True(t, tree.Delete(n))
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.LessOrEqual(t, height(tree), int(math.Log(500)/math.Log(1/DefaultAlpha))+1)
+
+	assert.Equal(t, 400, tree.RangeDelete(0, 400))
+	assert.Equal(t, 0, tree.RangeDelete(0, 400))
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 100, tree.Size())
+	assert.LessOrEqual(t, height(tree), int(math.Log(100)/math.Log(1/DefaultAlpha))+1)
+}
+
+func TestTree_PopMin(t *testing.T) {
+	tree := New[int, struct{}](intLess, DefaultAlpha)
+	for i := 0; i < 1000; i++ {
+		tree.Insert(i, struct{}{})
+	}
+
+	// popping most nodes triggers rebuilds
+	for i := 0; i < 900; i++ {
+		key, _, ok := tree.PopMin()
+		require.True(t, ok)
+		assert.Equal(t, i, key)
+	}
+	key, _, ok := tree.PopMax()
+	require.True(t, ok)
+	assert.Equal(t, 999, key)
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 99, tree.Size())
+}
+
+func TestTree_DeleteAt(t *testing.T) {
+	tree := New[int, struct{}](intLess, DefaultAlpha)
+	for i := 0; i < 1000; i++ {
+		tree.Insert(i, struct{}{})
+	}
+
+	// deleting the median repeatedly triggers rebuilds
+	for i := 0; i < 900; i++ {
+		median := tree.Size() / 2
+		expected, _ := tree.Select(median)
+		expectedKey := tree.Key(expected)
+		key, _, ok := tree.DeleteAt(median)
+		require.True(t, ok)
+		assert.Equal(t, expectedKey, key)
+	}
+	_, _, ok := tree.DeleteAt(100)
+	assert.False(t, ok, "expected out of range rank to delete nothing")
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 100, tree.Size())
+}
+
+func TestTree_AscendDelete(t *testing.T) {
+	tree := New[int, struct{}](intLess, DefaultAlpha)
+	for i := 0; i < 1000; i++ {
+		tree.Insert(i, struct{}{})
+	}
+
+	// deleting most nodes triggers rebuilds during the traversal
+	var seen int
+	count := tree.AscendDelete(func(n *bst.Node[int, struct{}, struct{}]) bst.Action {
+		seen++
+		if n.Key()%10 != 0 {
+			return bst.ActionDelete
+		}
+		return bst.ActionContinue
+	})
+	assert.Equal(t, 1000, seen)
+	assert.Equal(t, 900, count)
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 100, tree.Size())
+	assert.LessOrEqual(t, height(tree), int(math.Log(100)/math.Log(1/DefaultAlpha))+1)
+}
+
+func TestTree_Clear(t *testing.T) {
+	tree := New[int, struct{}](intLess, DefaultAlpha)
+	for i := 0; i < 1000; i++ {
+		tree.Insert(i, struct{}{})
+	}
+	tree.Clear()
+	assert.Equal(t, 0, tree.Size())
+	assert.Equal(t, 0, tree.maxSize)
+	for i := 0; i < 100; i++ {
+		tree.Insert(i, struct{}{})
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.LessOrEqual(t, height(tree), int(math.Log(100)/math.Log(1/DefaultAlpha))+1)
+}
+
+func TestTree_LoadSorted(t *testing.T) {
+	tree := New[int, int](intLess, DefaultAlpha)
+	keys := make([]int, 1000)
+	for i := range keys {
+		keys[i] = i
+	}
+	require.NoError(t, tree.LoadSorted(keys, keys, 0))
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 1000, tree.maxSize)
+	assert.Error(t, tree.LoadSorted([]int{2, 1}, nil, 0))
+	assert.Equal(t, 1000, tree.Size(), "expected the tree to be unchanged")
+
+	// deletions rebuild the whole tree once it shrinks below alpha times its loaded size
+	for i := 0; i < 900; i++ {
+		n, _ := tree.Search(i)
+		tree.Delete(n)
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.LessOrEqual(t, height(tree), int(math.Log(100)/math.Log(1/DefaultAlpha))+1)
+}
+
+func TestTree_restore(t *testing.T) {
+	src := New[int, int](intLess, DefaultAlpha)
+	for i := 0; i < 200; i++ {
+		src.Insert(i, i)
+	}
+	require.NoError(t, src.IsTreeValid())
+	jsonData, err := src.MarshalJSON()
+	require.NoError(t, err)
+	binaryData, err := src.MarshalBinary()
+	require.NoError(t, err)
+	var snapshot bytes.Buffer
+	require.NoError(t, src.SnapshotTo(&snapshot))
+
+	for name, restore := range map[string]func(tree *Tree[int, int]) error{
+		"UnmarshalJSON":   func(tree *Tree[int, int]) error { return tree.UnmarshalJSON(jsonData) },
+		"UnmarshalBinary": func(tree *Tree[int, int]) error { return tree.UnmarshalBinary(binaryData) },
+		"UnmarshalWith": func(tree *Tree[int, int]) error {
+			data, err := src.MarshalWith(json.Marshal)
+			require.NoError(t, err)
+			return tree.UnmarshalWith(data, json.Unmarshal)
+		},
+		"ReadSnapshot": func(tree *Tree[int, int]) error {
+			_, err := tree.ReadSnapshot(bytes.NewReader(snapshot.Bytes()))
+			return err
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tree := New[int, int](intLess, DefaultAlpha)
+			require.NoError(t, restore(tree))
+			assert.Equal(t, 200, tree.Size())
+			assert.Equal(t, 200, tree.maxSize)
+			require.NoError(t, tree.IsTreeValid())
+
+			// the tree shrinking below alpha times its restored size is rebuilt
+			for i := 0; i < 190; i++ {
+				n, _ := tree.Search(i)
+				require.True(t, tree.Delete(n))
+			}
+			require.NoError(t, tree.IsTreeValid())
+			assert.LessOrEqual(t, height(tree), int(math.Log(10)/math.Log(1/DefaultAlpha))+1)
+		})
+	}
+
+	// a restored shape too deep for a scapegoat tree is rebuilt
+	deep := bst.New[int, int, struct{}](intLess)
+	for i := 0; i < 100; i++ {
+		deep.Insert(i, i)
+	}
+	data, err := deep.MarshalBinary()
+	require.NoError(t, err)
+	tree := New[int, int](intLess, DefaultAlpha)
+	require.NoError(t, tree.UnmarshalBinary(data))
+	require.NoError(t, tree.IsTreeValid())
+	assert.Error(t, tree.UnmarshalBinary([]byte("invalid")))
+	assert.Equal(t, 100, tree.maxSize, "expected the tree to be unchanged")
+}
+
+func TestTree_IsTreeValid_tooDeep(t *testing.T) {
+	tree := New[int, struct{}](intLess, DefaultAlpha)
+	for i := 0; i < 100; i++ {
+		tree.tree.Insert(i, struct{}{}) // bypass scapegoat balancing
+	}
+	tree.maxSize = tree.Size()
+	assert.Error(t, tree.IsTreeValid(), "expected degenerate tree to be invalid")
+}
+
+func TestTree_unsafeMethodsUnavailable(t *testing.T) {
+	// the methods of bst.Tree that may corrupt a Scapegoat Tree are not part of its API
+	tree := New[int, struct{}](intLess, DefaultAlpha)
+	for _, name := range []string{
+		"AttachSubtree", "DetachSubtree", "InsertAt", "LoadSortedFunc", "MustSetMetadata", "Rebalance", "RebuildSubtree",
+		"RefreshPath", "Release", "Relink", "RotateLeft", "RotateRight", "SetHooks", "SetKey", "SetLeft", "SetMetadata",
+		"SetParent", "SetRight", "SetRoot", "Transplant",
+	} {
+		_, found := reflect.TypeOf(tree).MethodByName(name)
+		assert.False(t, found, "expected %s not to be available", name)
+	}
+}
+
+func TestTree_UpdateKey(t *testing.T) {
+	tree := New[int, int](intLess, 0.6)
+	rng := rand.New(rand.NewSource(1))
+	handles := make(map[int]*bst.Node[int, int, struct{}])
+	for len(handles) < 300 {
+		key := rng.Intn(1000)
+		handles[key], _ = tree.Insert(key, key)
+	}
+
+	for i := 0; i < 3000; i++ {
+		var oldKey int
+		for oldKey = range handles {
+			break
+		}
+		n, newKey := handles[oldKey], rng.Intn(1000)
+		value := tree.Value(n)
+		if _, taken := handles[newKey]; taken && newKey != oldKey {
+			require.False(t, tree.UpdateKey(n, newKey), "expected key %d to be rejected as taken", newKey)
+		} else {
+			require.True(t, tree.UpdateKey(n, newKey), "expected key %d to be updated to %d", oldKey, newKey)
+			delete(handles, oldKey)
+			handles[newKey] = n
+		}
+		require.Equal(t, value, tree.Value(n), "expected value to be preserved")
+		require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	}
+	for key, n := range handles {
+		found, _ := tree.Search(key)
+		assert.Same(t, n, found, "expected key %d to be held by the same node", key)
+	}
+}
+
+func TestTree_duplicatePolicy(t *testing.T) {
+	tree := New[int, int](intLess, DefaultAlpha, bst.WithDuplicatePolicy(bst.DuplicateIgnore))
+	for i := 0; i < 1000; i++ {
+		tree.Insert(i, i)
+	}
+	for i := 0; i < 1000; i++ {
+		_, inserted, err := tree.TryInsert(i, -i)
+		require.NoError(t, err)
+		assert.False(t, inserted)
+	}
+	n, _ := tree.Search(5)
+	assert.Equal(t, 5, tree.Value(n), "expected the existing value to be kept")
+
+	_, inserted := tree.InsertIfAbsent(1000, 1000)
+	assert.True(t, inserted)
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 1001, tree.Size())
+}
+
+func TestTree_InsertReturningOld(t *testing.T) {
+	tree := New[int, int](intLess, DefaultAlpha)
+	for i := 0; i < 1000; i++ {
+		_, existed := tree.InsertReturningOld(i, i)
+		assert.False(t, existed)
+	}
+	for i := 0; i < 1000; i++ {
+		old, existed := tree.InsertReturningOld(i, -i)
+		assert.True(t, existed)
+		assert.Equal(t, i, old)
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 1000, tree.Size())
+}
@@ -0,0 +1,39 @@
+package segtree_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/segtree"
+)
+
+func ExampleTree_Query() {
+
+	// create a tree holding the maximum of each range
+	tree := segtree.New([]int{5, 3, 8, 1, 9, 2}, func(a, b int) int {
+		return max(a, b)
+	}, 0)
+
+	fmt.Println("Max of [0, 3):", tree.Query(0, 3))
+	tree.Set(1, 10)
+	fmt.Println("Max of [0, 3):", tree.Query(0, 3))
+
+	// Output:
+	// Max of [0, 3): 8
+	// Max of [0, 3): 10
+}
+
+func ExampleLazyTree_Update() {
+
+	// create a tree of sums, supporting adding a value to every element in a range
+	tree := segtree.NewLazy([]int{1, 2, 3, 4, 5},
+		func(a, b int) int { return a + b }, 0,
+		func(sum, add, length int) int { return sum + add*length },
+		func(earlier, later int) int { return earlier + later })
+
+	fmt.Println("Sum of [1, 4):", tree.Query(1, 4))
+	tree.Update(0, 3, 10) // add 10 to the first three elements
+	fmt.Println("Sum of [1, 4):", tree.Query(1, 4))
+
+	// Output:
+	// Sum of [1, 4): 9
+	// Sum of [1, 4): 29
+}
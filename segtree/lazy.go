@@ -0,0 +1,189 @@
+package segtree
+
+// ApplyFunc applies a range update to the aggregate of a range of length elements,
+// returning the updated aggregate.
+//
+// For example, adding u to every element of a range changes its sum by u*length,
+// and its minimum by u.
+type ApplyFunc[T, U any] func(aggregate T, update U, length int) T
+
+// ComposeFunc composes two range updates into a single update, equivalent to applying
+// earlier and then later. For example, two additions compose into their sum, while for
+// assignments the later update wins.
+type ComposeFunc[U any] func(earlier, later U) U
+
+// LazyTree is a segment tree supporting range updates as well as range aggregate queries.
+//
+// Range updates of type U are applied to the aggregates of whole subtrees, and only pushed
+// down to their children when a later operation needs to look inside them (lazy propagation),
+// so that both updates and queries take O(log n) time.
+type LazyTree[T, U any] struct {
+	n        int             // length of the sequence
+	nodes    []T             // aggregates of each subtree, with the root at index 1
+	lazy     []U             // updates not yet pushed down to the children of each subtree
+	pending  []bool          // whether each subtree has an update not yet pushed down
+	combine  CombineFunc[T]  // function combining aggregates
+	identity T               // identity element of combine
+	apply    ApplyFunc[T, U] // function applying updates to aggregates
+	compose  ComposeFunc[U]  // function composing updates
+}
+
+// NewLazy creates a segment tree with lazy propagation over a copy of values.
+//
+// Parameters:
+//   - values: The initial sequence. Its length is fixed for the lifetime of the tree.
+//   - combine: An associative function combining the aggregates of adjacent ranges.
+//   - identity: The identity element of combine, returned for empty ranges.
+//   - apply: A function applying an update to the aggregate of a range.
+//   - compose: A function composing two updates into one.
+//
+// Building the tree takes O(n) time.
+//
+// Example, a tree of sums supporting adding a value to a range:
+//
+//	tree := segtree.NewLazy(values,
+//		func(a, b int) int { return a + b }, 0,
+//		func(sum, add, length int) int { return sum + add*length },
+//		func(earlier, later int) int { return earlier + later })
+func NewLazy[T, U any](values []T, combine CombineFunc[T], identity T, apply ApplyFunc[T, U], compose ComposeFunc[U]) *LazyTree[T, U] {
+	n := len(values)
+	t := &LazyTree[T, U]{
+		n:        n,
+		nodes:    make([]T, 4*n),
+		lazy:     make([]U, 4*n),
+		pending:  make([]bool, 4*n),
+		combine:  combine,
+		identity: identity,
+		apply:    apply,
+		compose:  compose,
+	}
+	if n > 0 {
+		t.build(1, 0, n, values)
+	}
+	return t
+}
+
+// build initialises the subtree rooted at node, covering [lo, hi), from values.
+func (t *LazyTree[T, U]) build(node, lo, hi int, values []T) {
+	if hi-lo == 1 {
+		t.nodes[node] = values[lo]
+		return
+	}
+	mid := (lo + hi) / 2
+	t.build(2*node, lo, mid, values)
+	t.build(2*node+1, mid, hi, values)
+	t.nodes[node] = t.combine(t.nodes[2*node], t.nodes[2*node+1])
+}
+
+// Len returns the length of the sequence.
+func (t *LazyTree[T, U]) Len() int {
+	return t.n
+}
+
+// Get returns the element at index i.
+func (t *LazyTree[T, U]) Get(i int) T {
+	checkIndex(i, t.n)
+	return t.query(1, 0, t.n, i, i+1)
+}
+
+// Set sets the element at index i to value, in O(log n) time.
+func (t *LazyTree[T, U]) Set(i int, value T) {
+	checkIndex(i, t.n)
+	t.set(1, 0, t.n, i, value)
+}
+
+// Update applies update to every element in [lo, hi), in O(log n) time.
+func (t *LazyTree[T, U]) Update(lo, hi int, update U) {
+	checkRange(lo, hi, t.n)
+	if lo < hi {
+		t.update(1, 0, t.n, lo, hi, update)
+	}
+}
+
+// Query returns the aggregate of the elements in [lo, hi), in O(log n) time.
+//
+// If the range is empty, the identity element is returned.
+func (t *LazyTree[T, U]) Query(lo, hi int) T {
+	checkRange(lo, hi, t.n)
+	if lo == hi {
+		return t.identity
+	}
+	return t.query(1, 0, t.n, lo, hi)
+}
+
+// applyTo applies update to the subtree rooted at node, which covers length elements,
+// deferring the update of its children until they are needed.
+func (t *LazyTree[T, U]) applyTo(node, length int, update U) {
+	t.nodes[node] = t.apply(t.nodes[node], update, length)
+	if length > 1 {
+		if t.pending[node] {
+			t.lazy[node] = t.compose(t.lazy[node], update)
+		} else {
+			t.lazy[node] = update
+			t.pending[node] = true
+		}
+	}
+}
+
+// push pushes any pending update of the subtree rooted at node, covering [lo, hi), down to its children.
+func (t *LazyTree[T, U]) push(node, lo, mid, hi int) {
+	if !t.pending[node] {
+		return
+	}
+	t.applyTo(2*node, mid-lo, t.lazy[node])
+	t.applyTo(2*node+1, hi-mid, t.lazy[node])
+	var zero U
+	t.lazy[node] = zero
+	t.pending[node] = false
+}
+
+// set sets element i to value within the subtree rooted at node, covering [lo, hi).
+func (t *LazyTree[T, U]) set(node, lo, hi, i int, value T) {
+	if hi-lo == 1 {
+		t.nodes[node] = value
+		return
+	}
+	mid := (lo + hi) / 2
+	t.push(node, lo, mid, hi)
+	if i < mid {
+		t.set(2*node, lo, mid, i, value)
+	} else {
+		t.set(2*node+1, mid, hi, i, value)
+	}
+	t.nodes[node] = t.combine(t.nodes[2*node], t.nodes[2*node+1])
+}
+
+// update applies update to [qlo, qhi) within the subtree rooted at node, covering [lo, hi).
+func (t *LazyTree[T, U]) update(node, lo, hi, qlo, qhi int, update U) {
+	if qlo <= lo && hi <= qhi {
+		t.applyTo(node, hi-lo, update)
+		return
+	}
+	mid := (lo + hi) / 2
+	t.push(node, lo, mid, hi)
+	if qlo < mid {
+		t.update(2*node, lo, mid, qlo, qhi, update)
+	}
+	if qhi > mid {
+		t.update(2*node+1, mid, hi, qlo, qhi, update)
+	}
+	t.nodes[node] = t.combine(t.nodes[2*node], t.nodes[2*node+1])
+}
+
+// query returns the aggregate of [qlo, qhi) within the subtree rooted at node, covering [lo, hi).
+// The ranges must overlap.
+func (t *LazyTree[T, U]) query(node, lo, hi, qlo, qhi int) T {
+	if qlo <= lo && hi <= qhi {
+		return t.nodes[node]
+	}
+	mid := (lo + hi) / 2
+	t.push(node, lo, mid, hi)
+	switch {
+	case qhi <= mid:
+		return t.query(2*node, lo, mid, qlo, qhi)
+	case qlo >= mid:
+		return t.query(2*node+1, mid, hi, qlo, qhi)
+	default:
+		return t.combine(t.query(2*node, lo, mid, qlo, qhi), t.query(2*node+1, mid, hi, qlo, qhi))
+	}
+}
@@ -0,0 +1,87 @@
+package segtree
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestLazyTree_addSum(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	values := make([]int, 50)
+	for i := range values {
+		values[i] = rng.Intn(100)
+	}
+	tree := NewLazy(values, sum, 0,
+		func(s, add, length int) int { return s + add*length },
+		func(earlier, later int) int { return earlier + later })
+	assert.Equal(t, len(values), tree.Len())
+
+	for i := 0; i < 2000; i++ {
+		lo := rng.Intn(len(values))
+		hi := lo + rng.Intn(len(values)-lo+1)
+		switch rng.Intn(3) {
+		case 0:
+			add := rng.Intn(21) - 10
+			tree.Update(lo, hi, add)
+			for j := lo; j < hi; j++ {
+				values[j] += add
+			}
+		case 1:
+			v := rng.Intn(100)
+			tree.Set(lo%len(values), v)
+			values[lo%len(values)] = v
+		default:
+			expected := 0
+			for _, v := range values[lo:hi] {
+				expected += v
+			}
+			assert.Equal(t, expected, tree.Query(lo, hi), "unexpected sum of [%d, %d)", lo, hi)
+		}
+	}
+	for i, v := range values {
+		assert.Equal(t, v, tree.Get(i), "unexpected value at %d", i)
+	}
+}
+
+func TestLazyTree_assignMin(t *testing.T) {
+	values := []int{5, 3, 8, 1, 9, 2, 7}
+	tree := NewLazy(values, func(a, b int) int { return min(a, b) }, math.MaxInt,
+		func(_, assign, _ int) int { return assign },
+		func(_, later int) int { return later })
+
+	assert.Equal(t, 1, tree.Query(0, 7))
+	tree.Update(2, 5, 10) // 5 3 10 10 10 2 7
+	assert.Equal(t, 10, tree.Query(2, 5))
+	assert.Equal(t, 2, tree.Query(0, 7))
+	tree.Update(0, 7, 4) // all 4
+	tree.Update(6, 7, 0) // 4 4 4 4 4 4 0
+	assert.Equal(t, 4, tree.Query(0, 6))
+	assert.Equal(t, 0, tree.Query(0, 7))
+	assert.Equal(t, 4, tree.Get(3))
+	assert.Equal(t, math.MaxInt, tree.Query(3, 3), "expected identity for empty range")
+	tree.Update(3, 3, -1) // empty update is a no-op
+	assert.Equal(t, 0, tree.Query(0, 7))
+}
+
+func TestLazyTree_outOfRange(t *testing.T) {
+	tree := NewLazy([]int{1, 2, 3}, sum, 0,
+		func(s, add, length int) int { return s + add*length },
+		func(earlier, later int) int { return earlier + later })
+
+	tests := map[string]func(){
+		"get past end":    func() { tree.Get(3) },
+		"set negative":    func() { tree.Set(-1, 0) },
+		"update past end": func() { tree.Update(1, 4, 1) },
+		"query reversed":  func() { tree.Query(2, 1) },
+	}
+	for name, f := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Panics(t, f)
+		})
+	}
+
+	empty := NewLazy[int, int](nil, sum, 0, nil, nil)
+	assert.Equal(t, 0, empty.Query(0, 0))
+}
@@ -0,0 +1,121 @@
+// Package segtree provides generic segment trees, answering aggregate queries (such as sums,
+// minimums or maximums) over ranges of a fixed-length sequence in O(log n) time.
+//
+// The aggregate is defined by a user-supplied CombineFunc, which must be associative,
+// and its identity element (e.g., 0 for sums, +∞ for minimums). The combine function
+// need not be commutative: ranges are always combined in left-to-right order.
+//
+// Two trees are provided:
+//   - Tree supports point updates (Tree.Set) and range queries (Tree.Query).
+//   - LazyTree additionally supports range updates (LazyTree.Update), such as adding to or
+//     assigning every element in a range, using lazy propagation.
+//
+// Ranges are half-open intervals [lo, hi) of zero-based indexes, as with Go slices.
+// Methods panic if given an index or range outside the sequence, as slice indexing does.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/segtree"
+//
+//	tree := segtree.New([]int{5, 3, 8, 1}, func(a, b int) int { return a + b }, 0)
+//	tree.Set(1, 4)
+//	sum := tree.Query(1, 3) // 4 + 8 = 12
+package segtree
+
+import "fmt"
+
+// CombineFunc combines the aggregates of two adjacent ranges, a on the left and b on the right,
+// into the aggregate of the combined range. It must be associative.
+type CombineFunc[T any] func(a, b T) T
+
+// Tree is a segment tree supporting point updates and range aggregate queries.
+//
+// It is stored as a flat slice of 2n aggregates, where the leaves (the sequence itself) are
+// stored in the second half and each internal node i holds the aggregate of nodes 2i and 2i+1.
+type Tree[T any] struct {
+	n        int            // length of the sequence
+	nodes    []T            // aggregates; nodes[n+i] holds element i
+	combine  CombineFunc[T] // function combining aggregates
+	identity T              // identity element of combine
+}
+
+// New creates a segment tree over a copy of values.
+//
+// Parameters:
+//   - values: The initial sequence. Its length is fixed for the lifetime of the tree.
+//   - combine: An associative function combining the aggregates of adjacent ranges.
+//   - identity: The identity element of combine, returned for empty ranges.
+//
+// Building the tree takes O(n) time.
+func New[T any](values []T, combine CombineFunc[T], identity T) *Tree[T] {
+	n := len(values)
+	t := &Tree[T]{
+		n:        n,
+		nodes:    make([]T, 2*n),
+		combine:  combine,
+		identity: identity,
+	}
+	copy(t.nodes[n:], values)
+	for i := n - 1; i > 0; i-- {
+		t.nodes[i] = combine(t.nodes[2*i], t.nodes[2*i+1])
+	}
+	return t
+}
+
+// Len returns the length of the sequence.
+func (t *Tree[T]) Len() int {
+	return t.n
+}
+
+// Get returns the element at index i.
+func (t *Tree[T]) Get(i int) T {
+	checkIndex(i, t.n)
+	return t.nodes[t.n+i]
+}
+
+// Set sets the element at index i to value, updating the aggregates above it in O(log n) time.
+func (t *Tree[T]) Set(i int, value T) {
+	checkIndex(i, t.n)
+	i += t.n
+	t.nodes[i] = value
+	for i > 1 {
+		i /= 2
+		t.nodes[i] = t.combine(t.nodes[2*i], t.nodes[2*i+1])
+	}
+}
+
+// Query returns the aggregate of the elements in [lo, hi), in O(log n) time.
+//
+// If the range is empty, the identity element is returned.
+func (t *Tree[T]) Query(lo, hi int) T {
+	checkRange(lo, hi, t.n)
+
+	// combine from both ends towards the middle, keeping the left and right
+	// aggregates separate so that combine need not be commutative
+	left, right := t.identity, t.identity
+	for lo, hi = lo+t.n, hi+t.n; lo < hi; lo, hi = lo/2, hi/2 {
+		if lo%2 == 1 {
+			left = t.combine(left, t.nodes[lo])
+			lo++
+		}
+		if hi%2 == 1 {
+			hi--
+			right = t.combine(t.nodes[hi], right)
+		}
+	}
+	return t.combine(left, right)
+}
+
+// checkIndex panics if i is not a valid index into a sequence of length n.
+func checkIndex(i, n int) {
+	if i < 0 || i >= n {
+		panic(fmt.Sprintf("segtree: index %d out of range [0, %d)", i, n))
+	}
+}
+
+// checkRange panics if [lo, hi) is not a valid range of a sequence of length n.
+func checkRange(lo, hi, n int) {
+	if lo < 0 || hi > n || lo > hi {
+		panic(fmt.Sprintf("segtree: range [%d, %d) out of range [0, %d)", lo, hi, n))
+	}
+}
@@ -0,0 +1,82 @@
+package segtree
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func sum(a, b int) int { return a + b }
+
+func TestTree_Query(t *testing.T) {
+	values := []int{5, 3, 8, 1, 9, 2, 7}
+	tree := New(values, sum, 0)
+	assert.Equal(t, len(values), tree.Len())
+
+	for lo := 0; lo <= len(values); lo++ {
+		for hi := lo; hi <= len(values); hi++ {
+			expected := 0
+			for _, v := range values[lo:hi] {
+				expected += v
+			}
+			assert.Equal(t, expected, tree.Query(lo, hi), "unexpected sum of [%d, %d)", lo, hi)
+		}
+	}
+
+	// the tree holds a copy of values
+	values[0] = 100
+	assert.Equal(t, 5, tree.Get(0))
+}
+
+func TestTree_Set(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	values := make([]int, 100)
+	tree := New(values, func(a, b int) int { return min(a, b) }, math.MaxInt)
+
+	for i := 0; i < 1000; i++ {
+		idx, v := rng.Intn(len(values)), rng.Intn(1000)
+		values[idx] = v
+		tree.Set(idx, v)
+		assert.Equal(t, v, tree.Get(idx))
+
+		lo := rng.Intn(len(values))
+		hi := lo + rng.Intn(len(values)-lo+1)
+		expected := math.MaxInt
+		for _, v := range values[lo:hi] {
+			expected = min(expected, v)
+		}
+		assert.Equal(t, expected, tree.Query(lo, hi), "unexpected min of [%d, %d)", lo, hi)
+	}
+}
+
+func TestTree_nonCommutative(t *testing.T) {
+	tree := New([]string{"a", "b", "c", "d", "e"}, func(a, b string) string { return a + b }, "")
+	assert.Equal(t, "abcde", tree.Query(0, 5))
+	assert.Equal(t, "bcd", tree.Query(1, 4))
+	tree.Set(2, "X")
+	assert.Equal(t, "bXd", tree.Query(1, 4))
+	assert.Equal(t, "", tree.Query(3, 3))
+}
+
+func TestTree_outOfRange(t *testing.T) {
+	tree := New([]int{1, 2, 3}, sum, 0)
+
+	tests := map[string]func(){
+		"get negative":   func() { tree.Get(-1) },
+		"get past end":   func() { tree.Get(3) },
+		"set past end":   func() { tree.Set(3, 1) },
+		"query past end": func() { tree.Query(0, 4) },
+		"query reversed": func() { tree.Query(2, 1) },
+		"query negative": func() { tree.Query(-1, 2) },
+	}
+	for name, f := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Panics(t, f)
+		})
+	}
+
+	empty := New[int](nil, sum, 0)
+	assert.Equal(t, 0, empty.Len())
+	assert.Equal(t, 0, empty.Query(0, 0))
+}
@@ -0,0 +1,38 @@
+package shardedmap_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/shardedmap"
+)
+
+func ExampleSplitKeys() {
+
+	// learn split keys for 4 shards from a sample of the keys
+	less := func(a, b int) bool { return a < b }
+	var sample []int
+	for i := 0; i < 100; i++ {
+		sample = append(sample, i*10)
+	}
+	splits := shardedmap.SplitKeys(less, sample, 4)
+	fmt.Println("Splits:", splits)
+
+	// create a map partitioned by the split keys
+	m := shardedmap.NewSplit[int, string](less, splits)
+	m.Set(990, "last")
+	m.Set(5, "first")
+	m.Set(500, "middle")
+	fmt.Println("Shard sizes:", m.ShardLens())
+
+	// iterate over the shards in key order
+	m.Range(func(key int, value string) bool {
+		fmt.Printf("%d: %s\n", key, value)
+		return true
+	})
+
+	// Output:
+	// Splits: [250 500 750]
+	// Shard sizes: [1 0 1 1]
+	// 5: first
+	// 500: middle
+	// 990: last
+}
@@ -0,0 +1,333 @@
+// Package shardedmap provides a generic ordered map partitioned across shards, for parallel writes.
+//
+// Map partitions its keys across a fixed number of shards, each a Red-Black Tree (rbtree.Tree)
+// guarded by its own lock, so that writes to different shards proceed in parallel, where a single
+// tree behind a single lock serializes every write. Keys are assigned to shards either:
+//   - by a partition function, such as a hash of the key (see New), spreading any workload evenly; or
+//   - by split keys, each shard holding a contiguous range of keys (see NewSplit). Split keys can be
+//     learned from a sample of the keys with SplitKeys.
+//
+// Every key belongs to a single shard, so Get, Set and Delete lock a single shard. Ordered iteration
+// merges the shards with a k-way merge, in O(log s) time per key for s shards.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/shardedmap"
+//
+//	less := func(a, b string) bool { return a < b }
+//	m := shardedmap.NewSplit[string, int](less, shardedmap.SplitKeys(less, sample, 16))
+//	m.Set("b", 2)
+//	m.Set("a", 1)
+//	v, ok := m.Get("a") // 1, true
+//	keys := m.Keys()    // [a b]
+//
+// # Limitations
+//
+// Operations spanning several shards, such as Len, Range and MinKey, lock one shard at a time, so they
+// are not atomic with respect to concurrent writes (see Map.Range).
+package shardedmap
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/bst"
+	"github.com/mikenye/gotrees/heap"
+	"github.com/mikenye/gotrees/rbtree"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// shard is a partition of the map, holding its keys in a Red-Black Tree guarded by a lock.
+//
+// Reads take the read lock only: the read-only methods of the tree, including Min and Max on the root,
+// never write to it, so concurrent readers do not race.
+type shard[K, V any] struct {
+	mu   sync.RWMutex
+	tree *rbtree.Tree[K, V]
+}
+
+// Map represents an ordered map from keys of type K to values of type V, partitioned across shards.
+//
+// Unlike the other containers of this module, Map is safe for concurrent use.
+//
+// Maps must be created with New or NewSplit.
+type Map[K, V any] struct {
+	less      bst.LessFunc[K] // Function to compare keys and maintain order
+	partition func(key K) int // Function returning the index of the shard holding a key
+	shards    []*shard[K, V]  // Shards of the map
+}
+
+// New creates and returns a new empty map of the given number of shards, assigning each key to
+// the shard returned by partition.
+//
+// partition must always return the same shard for keys that are equal according to less, such as
+// a hash of the key modulo the number of shards.
+//
+// Parameters:
+//   - less: A function that defines the ordering of keys.
+//   - shards: The number of shards, at least 1.
+//   - partition: A function returning the index of the shard holding a key, in [0, shards).
+//
+// Returns:
+//   - A pointer to a newly created Map[K, V] instance.
+func New[K, V any](less bst.LessFunc[K], shards int, partition func(key K) int) *Map[K, V] {
+	if shards < 1 {
+		panic(fmt.Sprintf("shardedmap: invalid number of shards %d", shards))
+	}
+	m := &Map[K, V]{
+		less:      less,
+		partition: partition,
+		shards:    make([]*shard[K, V], shards),
+	}
+	for i := range m.shards {
+		m.shards[i] = &shard[K, V]{tree: rbtree.New[K, V](less)}
+	}
+	return m
+}
+
+// NewSplit creates and returns a new empty map partitioned by split keys: shard 0 holds the keys less
+// than splits[0], shard i the keys in [splits[i-1], splits[i]), and the last shard the keys greater than or
+// equal to the last split key, for len(splits)+1 shards.
+//
+// Split keys spreading the keys evenly can be learned from a sample of the keys with SplitKeys.
+//
+// Parameters:
+//   - less: A function that defines the ordering of keys.
+//   - splits: The split keys, in strictly ascending order.
+//
+// Returns:
+//   - A pointer to a newly created Map[K, V] instance.
+func NewSplit[K, V any](less bst.LessFunc[K], splits []K) *Map[K, V] {
+	splits = slices.Clone(splits)
+	for i := 1; i < len(splits); i++ {
+		if !less(splits[i-1], splits[i]) {
+			panic(fmt.Sprintf("shardedmap: split keys out of order at %v", splits[i]))
+		}
+	}
+	return New[K, V](less, len(splits)+1, func(key K) int {
+		return sort.Search(len(splits), func(i int) bool {
+			return less(key, splits[i])
+		})
+	})
+}
+
+// SplitKeys returns the split keys partitioning sample into the given number of shards of about the same
+// number of keys, for NewSplit. With a sample representative of the keys of the map, the shards of the map
+// hold about the same number of keys, and share the writes evenly if writes are spread evenly across keys.
+//
+// Sampling a few thousand keys is usually enough. Fewer split keys are returned if the sample holds too few
+// distinct keys, so that no shard is empty.
+//
+// Parameters:
+//   - less: A function that defines the ordering of keys.
+//   - sample: A sample of the keys, in any order, which is not modified.
+//   - shards: The number of shards, at least 1.
+//
+// Returns:
+//   - The split keys, in strictly ascending order, at most shards-1 of them.
+func SplitKeys[K any](less bst.LessFunc[K], sample []K, shards int) []K {
+	if shards < 1 {
+		panic(fmt.Sprintf("shardedmap: invalid number of shards %d", shards))
+	}
+	sorted := slices.Clone(sample)
+	slices.SortFunc(sorted, func(a, b K) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		default:
+			return 0
+		}
+	})
+	sorted = slices.CompactFunc(sorted, func(a, b K) bool {
+		return !less(a, b) && !less(b, a)
+	})
+
+	// split at the first key of each shard but the first, skipping empty shards
+	var splits []K
+	prev := 0
+	for i := 1; i < shards; i++ {
+		if j := i * len(sorted) / shards; j > prev {
+			splits = append(splits, sorted[j])
+			prev = j
+		}
+	}
+	return splits
+}
+
+// shard returns the shard holding key.
+func (m *Map[K, V]) shard(key K) *shard[K, V] {
+	return m.shards[m.partition(key)]
+}
+
+// Shards returns the number of shards of the map.
+func (m *Map[K, V]) Shards() int {
+	return len(m.shards)
+}
+
+// Len returns the number of entries in the map, summing the sizes of the shards.
+//
+// This is an O(s) operation, for s shards.
+func (m *Map[K, V]) Len() int {
+	n := 0
+	for _, s := range m.shards {
+		s.mu.RLock()
+		n += s.tree.Size()
+		s.mu.RUnlock()
+	}
+	return n
+}
+
+// ShardLens returns the number of entries in each shard, e.g. to check that the keys are spread evenly.
+func (m *Map[K, V]) ShardLens() []int {
+	lens := make([]int, len(m.shards))
+	for i, s := range m.shards {
+		s.mu.RLock()
+		lens[i] = s.tree.Size()
+		s.mu.RUnlock()
+	}
+	return lens
+}
+
+// Get returns the value associated with key.
+//
+// Returns:
+//   - (value, true) if the key exists in the map.
+//   - (zero value, false) if the key is not found.
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	s := m.shard(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if n, found := s.tree.Search(key); found {
+		return s.tree.Value(n), true
+	}
+	var zero V
+	return zero, false
+}
+
+// Set associates value with key, replacing any existing value.
+func (m *Map[K, V]) Set(key K, value V) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tree.Insert(key, value)
+}
+
+// Delete removes key from the map.
+//
+// Returns:
+//   - true if the key was found and removed.
+//   - false if the key was not found.
+func (m *Map[K, V]) Delete(key K) bool {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, found := s.tree.Search(key)
+	if !found {
+		return false
+	}
+	s.tree.Delete(n)
+	return true
+}
+
+// cursor is the next entry of a shard in a k-way merge of the shards.
+type cursor[K, V any] struct {
+	key   K
+	value V
+	shard int
+}
+
+// next returns the entry of shard i following key, or the first entry of the shard if first is true.
+func (m *Map[K, V]) next(i int, key K, first bool) (cursor[K, V], bool) {
+	s := m.shards[i]
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var n *bst.Node[K, V, rbtree.Color]
+	if first {
+		n = s.tree.Min(s.tree.Root())
+	} else if n, _ = s.tree.Ceiling(key); !s.tree.IsNil(n) && !m.less(key, s.tree.Key(n)) {
+		n = s.tree.Successor(n)
+	}
+	if s.tree.IsNil(n) {
+		return cursor[K, V]{}, false
+	}
+	return cursor[K, V]{key: s.tree.Key(n), value: s.tree.Value(n), shard: i}, true
+}
+
+// Range calls f for each key and value in ascending key order, until f returns false, merging the
+// shards with a k-way merge.
+//
+// No lock is held while f runs, so f may modify the map. Each shard is locked only to find its entry
+// following the last key visited in that shard, so Range is not a snapshot: an entry set or deleted
+// during iteration is visited if and only if the iteration has not yet moved past its key.
+// Every key is visited at most once, in ascending order.
+func (m *Map[K, V]) Range(f func(key K, value V) bool) {
+	h := heap.NewBinary(func(a, b cursor[K, V]) bool {
+		return m.less(a.key, b.key)
+	})
+	var zero K
+	for i := range m.shards {
+		if c, ok := m.next(i, zero, true); ok {
+			h.Push(c)
+		}
+	}
+	for c, ok := h.Pop(); ok; c, ok = h.Pop() {
+		if !f(c.key, c.value) {
+			return
+		}
+		if next, ok := m.next(c.shard, c.key, false); ok {
+			h.Push(next)
+		}
+	}
+}
+
+// Keys returns the keys of the map, in ascending order (see Map.Range).
+func (m *Map[K, V]) Keys() []K {
+	var keys []K
+	m.Range(func(key K, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// MinKey returns the smallest key in the map.
+//
+// Returns:
+//   - (key, true) if the map is not empty.
+//   - (zero key, false) if the map is empty.
+func (m *Map[K, V]) MinKey() (K, bool) {
+	return m.bound(func(t *rbtree.Tree[K, V]) *bst.Node[K, V, rbtree.Color] {
+		return t.Min(t.Root())
+	}, m.less)
+}
+
+// MaxKey returns the largest key in the map.
+//
+// Returns:
+//   - (key, true) if the map is not empty.
+//   - (zero key, false) if the map is empty.
+func (m *Map[K, V]) MaxKey() (K, bool) {
+	return m.bound(func(t *rbtree.Tree[K, V]) *bst.Node[K, V, rbtree.Color] {
+		return t.Max(t.Root())
+	}, func(a, b K) bool {
+		return m.less(b, a)
+	})
+}
+
+// bound returns the key of the node returned by find for each shard that comes first according to before.
+func (m *Map[K, V]) bound(find func(t *rbtree.Tree[K, V]) *bst.Node[K, V, rbtree.Color], before bst.LessFunc[K]) (K, bool) {
+	var (
+		bound K
+		found bool
+	)
+	for _, s := range m.shards {
+		s.mu.RLock()
+		if n := find(s.tree); !s.tree.IsNil(n) && (!found || before(s.tree.Key(n), bound)) {
+			bound, found = s.tree.Key(n), true
+		}
+		s.mu.RUnlock()
+	}
+	return bound, found
+}
@@ -0,0 +1,202 @@
+package shardedmap
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+// maps returns a map partitioned by hash, and a map partitioned by split keys.
+func maps() map[string]*Map[int, int] {
+	return map[string]*Map[int, int]{
+		"partition": New[int, int](intLess, 4, func(key int) int { return (key%4 + 4) % 4 }),
+		"split":     NewSplit[int, int](intLess, []int{250, 500, 750}),
+	}
+}
+
+func TestMap_GetSetDelete(t *testing.T) {
+	for name, m := range maps() {
+		t.Run(name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			expected := make(map[int]int)
+			for i := 0; i < 10000; i++ {
+				key := rng.Intn(1000)
+				if rng.Intn(3) == 0 {
+					_, exists := expected[key]
+					assert.Equal(t, exists, m.Delete(key), "unexpected Delete(%d) result", key)
+					delete(expected, key)
+				} else {
+					m.Set(key, i)
+					expected[key] = i
+				}
+			}
+			assert.Equal(t, len(expected), m.Len())
+			for key, value := range expected {
+				v, found := m.Get(key)
+				require.True(t, found, "expected key %d to be found", key)
+				assert.Equal(t, value, v)
+			}
+			_, found := m.Get(-1)
+			assert.False(t, found)
+
+			// ordered iteration merges the shards
+			var keys []int
+			for key := range expected {
+				keys = append(keys, key)
+			}
+			sort.Ints(keys)
+			assert.Equal(t, keys, m.Keys())
+			minKey, _ := m.MinKey()
+			maxKey, _ := m.MaxKey()
+			assert.Equal(t, keys[0], minKey)
+			assert.Equal(t, keys[len(keys)-1], maxKey)
+
+			sum := 0
+			for _, n := range m.ShardLens() {
+				assert.Positive(t, n, "expected every shard to hold keys")
+				sum += n
+			}
+			assert.Equal(t, len(expected), sum)
+		})
+	}
+}
+
+func TestMap_empty(t *testing.T) {
+	m := NewSplit[int, int](intLess, nil)
+	assert.Equal(t, 1, m.Shards())
+	assert.Equal(t, 0, m.Len())
+	assert.Empty(t, m.Keys())
+	_, found := m.MinKey()
+	assert.False(t, found)
+	_, found = m.MaxKey()
+	assert.False(t, found)
+	assert.False(t, m.Delete(1))
+}
+
+func TestMap_Range(t *testing.T) {
+	m := NewSplit[int, int](intLess, []int{10, 20})
+	for i := 0; i < 30; i++ {
+		m.Set(i, i)
+	}
+
+	// iteration stops when f returns false
+	var visited []int
+	m.Range(func(key, value int) bool {
+		visited = append(visited, key)
+		return key < 4
+	})
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, visited)
+
+	// f may modify the map: keys deleted ahead are not visited, keys set ahead are
+	visited = nil
+	m.Range(func(key, value int) bool {
+		visited = append(visited, key)
+		if key == 5 {
+			m.Delete(15)
+			m.Set(25, -1)
+			m.Set(100, 100)
+		}
+		return true
+	})
+	assert.NotContains(t, visited, 15)
+	assert.Contains(t, visited, 100)
+	assert.True(t, sort.IntsAreSorted(visited))
+}
+
+func TestMap_concurrent(t *testing.T) {
+	m := New[int, int](intLess, 8, func(key int) int { return key % 8 })
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := w*1000 + i
+				m.Set(key, key)
+				if i%2 == 0 {
+					m.Delete(key)
+				}
+			}
+		}(w)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			prev := -1
+			m.Range(func(key, value int) bool {
+				assert.Less(t, prev, key, "expected keys in ascending order")
+				prev = key
+				return true
+			})
+		}
+	}()
+	wg.Wait()
+	assert.Equal(t, 4000, m.Len())
+}
+
+func TestMap_concurrentReaders(t *testing.T) {
+	for name, m := range maps() {
+		for i := 0; i < 1000; i++ {
+			m.Set(i, i)
+		}
+
+		// deleting the smallest and largest keys leaves the readers below to find the new ones
+		for i := 0; i < 100; i++ {
+			m.Delete(999 - i)
+		}
+		for i := 0; i < 100; i++ {
+			m.Delete(i)
+		}
+		var wg sync.WaitGroup
+		for r := 0; r < 8; r++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 50; i++ {
+					minKey, _ := m.MinKey()
+					maxKey, _ := m.MaxKey()
+					assert.Equal(t, 100, minKey, name)
+					assert.Equal(t, 899, maxKey, name)
+					prev := -1
+					m.Range(func(key, value int) bool {
+						assert.Less(t, prev, key, "%s: expected keys in ascending order", name)
+						prev = key
+						return key < 500
+					})
+				}
+			}()
+		}
+		wg.Wait()
+	}
+}
+
+func TestSplitKeys(t *testing.T) {
+	sample := rand.New(rand.NewSource(1)).Perm(1000)
+	splits := SplitKeys(intLess, sample, 4)
+	assert.Equal(t, []int{250, 500, 750}, splits)
+
+	// few distinct keys give fewer splits
+	assert.Equal(t, []int{2}, SplitKeys(intLess, []int{1, 2, 1, 2, 2}, 4))
+	assert.Empty(t, SplitKeys(intLess, []int{7, 7, 7}, 4))
+	assert.Empty(t, SplitKeys(intLess, nil, 4))
+	assert.Empty(t, SplitKeys(intLess, sample, 1))
+
+	// keys learned from a sample spread the keys evenly
+	m := NewSplit[int, struct{}](intLess, splits)
+	for _, key := range sample {
+		m.Set(key, struct{}{})
+	}
+	assert.Equal(t, []int{250, 250, 250, 250}, m.ShardLens())
+}
+
+func TestMap_panics(t *testing.T) {
+	assert.Panics(t, func() { New[int, int](intLess, 0, nil) })
+	assert.Panics(t, func() { NewSplit[int, int](intLess, []int{2, 1}) })
+	assert.Panics(t, func() { SplitKeys(intLess, nil, 0) })
+}
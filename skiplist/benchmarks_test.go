@@ -0,0 +1,34 @@
+package skiplist
+
+import (
+	"testing"
+)
+
+// BenchmarkList_Insert inserts items into a list in the benchmarking loop.
+func BenchmarkList_Insert(b *testing.B) {
+	list := New[int, struct{}](func(a, b int) bool {
+		return a < b
+	})
+	i := 0
+	b.ResetTimer()
+	for b.Loop() {
+		list.Insert(i, struct{}{})
+		i++
+	}
+}
+
+// BenchmarkList_Search searches a large list (1M entries) in the benchmarking loop.
+func BenchmarkList_Search(b *testing.B) {
+	list := New[int, struct{}](func(a, b int) bool {
+		return a < b
+	})
+	for i := 0; i < 1_000_000; i++ {
+		list.Insert(i, struct{}{})
+	}
+	i := 0
+	b.ResetTimer()
+	for b.Loop() {
+		list.Search(i % 1_000_000)
+		i++
+	}
+}
@@ -0,0 +1,32 @@
+package skiplist_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/skiplist"
+)
+
+func ExampleList_AscendRange() {
+
+	// create the list with integer keys and string values
+	list := skiplist.New[int, string](func(a, b int) bool {
+		return a < b
+	})
+
+	// insert some keys in the list
+	list.Insert(8, "eight")
+	list.Insert(2, "two")
+	list.Insert(6, "six")
+	list.Insert(4, "four")
+	list.Insert(10, "ten")
+
+	// find the keys in [4, 10)
+	list.AscendRange(4, 10, func(key int, value string) bool {
+		fmt.Printf("%d: %s\n", key, value)
+		return true
+	})
+
+	// Output:
+	// 4: four
+	// 6: six
+	// 8: eight
+}
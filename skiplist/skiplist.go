@@ -0,0 +1,288 @@
+// Package skiplist provides a generic skip list, a probabilistic alternative to balanced
+// binary search trees offering the same ordered key-value operations.
+//
+// A skip list is a sorted linked list with additional "express lanes": each entry is
+// linked into a random number of levels, so searches can skip over most entries.
+// Insertions, deletions and lookups take O(log n) expected time, without any rebalancing.
+//
+// The API mirrors the key-based API of btree.Tree (Insert, Search, Delete, Min, Max, Floor,
+// Ceiling, Ascend, AscendRange and Descend), so that the two can be used interchangeably.
+// Keys are ordered with the same LessFunc used by the bst and rbtree packages.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/skiplist"
+//
+//	list := skiplist.New[int, string](func(a, b int) bool { return a < b })
+//	list.Insert(10, "ten")
+//	list.Insert(20, "twenty")
+//	value, found := list.Search(10)
+//
+//	if found {
+//		list.Delete(10)
+//	}
+//
+// # Limitations
+//
+// Keys are unique, and the list is not safe for concurrent use.
+package skiplist
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"math/rand/v2"
+)
+
+// maxLevel is the maximum number of levels an entry can be linked into,
+// enough for 4^32 entries with the default promotion probability.
+const maxLevel = 32
+
+// List represents a skip list of key-value pairs, ordered by a LessFunc.
+//
+// Lists must be created with New.
+type List[K, V any] struct {
+	head  *node[K, V]     // Sentinel head node, linked into every level. Holds no key.
+	tail  *node[K, V]     // Last node in the list, nil if the list is empty.
+	less  bst.LessFunc[K] // Function to compare keys and maintain order.
+	level int             // Number of levels currently in use.
+	size  int             // Number of entries in the list.
+}
+
+// node represents a single entry of the skip list.
+//
+// next[i] is the following node at level i. prev is the preceding node at level 0,
+// which allows the list to be walked backwards.
+type node[K, V any] struct {
+	key   K
+	value V
+	next  []*node[K, V]
+	prev  *node[K, V]
+}
+
+// New creates and returns a new empty skip list.
+//
+// Parameters:
+//   - less: A function that defines the ordering of keys.
+//
+// Returns:
+//   - A pointer to a newly created List[K, V] instance.
+func New[K, V any](less bst.LessFunc[K]) *List[K, V] {
+	return &List[K, V]{
+		head:  &node[K, V]{next: make([]*node[K, V], maxLevel)},
+		less:  less,
+		level: 1,
+	}
+}
+
+// Size returns the number of entries in the list.
+//
+// This is an O(1) operation.
+func (l *List[K, V]) Size() int {
+	return l.size
+}
+
+// randomLevel returns the number of levels for a new entry. Each additional level
+// is used with probability 1/4, giving an expected 1.33 pointers per entry.
+func randomLevel() int {
+	level := 1
+	for level < maxLevel && rand.Uint32()&3 == 0 {
+		level++
+	}
+	return level
+}
+
+// findPredecessors returns the last node before key at each level (the head if there is none),
+// filling update. The node following update[0] is the first node with a key not less than key.
+func (l *List[K, V]) findPredecessors(key K, update []*node[K, V]) {
+	n := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for n.next[i] != nil && l.less(n.next[i].key, key) {
+			n = n.next[i]
+		}
+		update[i] = n
+	}
+}
+
+// ceiling returns the first node with a key not less than key, or nil if there is none.
+func (l *List[K, V]) ceiling(key K) *node[K, V] {
+	n := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for n.next[i] != nil && l.less(n.next[i].key, key) {
+			n = n.next[i]
+		}
+	}
+	return n.next[0]
+}
+
+// equal returns true if n is not nil and holds key.
+func (l *List[K, V]) equal(n *node[K, V], key K) bool {
+	return n != nil && !l.less(key, n.key)
+}
+
+// Search returns the value associated with key.
+//
+// Returns:
+//   - (value, true) if the key exists in the list.
+//   - (zero value, false) if the key is not found.
+func (l *List[K, V]) Search(key K) (V, bool) {
+	if n := l.ceiling(key); l.equal(n, key) {
+		return n.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Insert inserts the given key and value into the list.
+//
+// If the key already exists, its value is updated.
+//
+// Returns:
+//   - true if a new key was inserted.
+//   - false if the key existed and its value was updated.
+func (l *List[K, V]) Insert(key K, value V) bool {
+	var update [maxLevel]*node[K, V]
+	l.findPredecessors(key, update[:])
+
+	if n := update[0].next[0]; l.equal(n, key) {
+		n.value = value
+		return false
+	}
+
+	level := randomLevel()
+	for i := l.level; i < level; i++ {
+		update[i] = l.head
+	}
+	l.level = max(l.level, level)
+
+	n := &node[K, V]{key: key, value: value, next: make([]*node[K, V], level)}
+	for i := 0; i < level; i++ {
+		n.next[i] = update[i].next[i]
+		update[i].next[i] = n
+	}
+	if update[0] != l.head {
+		n.prev = update[0]
+	}
+	if n.next[0] != nil {
+		n.next[0].prev = n
+	} else {
+		l.tail = n
+	}
+	l.size++
+	return true
+}
+
+// Delete removes key from the list.
+//
+// Returns:
+//   - true if the key was found and removed.
+//   - false if the key was not found.
+func (l *List[K, V]) Delete(key K) bool {
+	var update [maxLevel]*node[K, V]
+	l.findPredecessors(key, update[:])
+
+	n := update[0].next[0]
+	if !l.equal(n, key) {
+		return false
+	}
+
+	for i := range n.next {
+		update[i].next[i] = n.next[i]
+	}
+	if n.next[0] != nil {
+		n.next[0].prev = n.prev
+	} else {
+		l.tail = n.prev
+	}
+	for l.level > 1 && l.head.next[l.level-1] == nil {
+		l.level--
+	}
+	l.size--
+	return true
+}
+
+// Min returns the smallest key in the list and its value.
+//
+// Returns:
+//   - (key, value, true) if the list is not empty.
+//   - (zero key, zero value, false) if the list is empty.
+func (l *List[K, V]) Min() (K, V, bool) {
+	return l.entry(l.head.next[0])
+}
+
+// Max returns the largest key in the list and its value.
+//
+// Returns:
+//   - (key, value, true) if the list is not empty.
+//   - (zero key, zero value, false) if the list is empty.
+func (l *List[K, V]) Max() (K, V, bool) {
+	return l.entry(l.tail)
+}
+
+// Floor returns the largest key in the list less than or equal to key, and its value.
+//
+// Returns:
+//   - (key, value, true) if such a key exists.
+//   - (zero key, zero value, false) otherwise.
+func (l *List[K, V]) Floor(key K) (K, V, bool) {
+	n := l.ceiling(key)
+	switch {
+	case l.equal(n, key):
+		return l.entry(n)
+	case n == nil:
+		return l.entry(l.tail)
+	default:
+		return l.entry(n.prev)
+	}
+}
+
+// Ceiling returns the smallest key in the list greater than or equal to key, and its value.
+//
+// Returns:
+//   - (key, value, true) if such a key exists.
+//   - (zero key, zero value, false) otherwise.
+func (l *List[K, V]) Ceiling(key K) (K, V, bool) {
+	return l.entry(l.ceiling(key))
+}
+
+// entry returns the key and value of n, and whether n is not nil.
+func (l *List[K, V]) entry(n *node[K, V]) (K, V, bool) {
+	if n == nil {
+		var k K
+		var v V
+		return k, v, false
+	}
+	return n.key, n.value, true
+}
+
+// Ascend calls f for each key and value in ascending key order, until f returns false.
+//
+// The list must not be modified during iteration.
+func (l *List[K, V]) Ascend(f func(key K, value V) bool) {
+	for n := l.head.next[0]; n != nil; n = n.next[0] {
+		if !f(n.key, n.value) {
+			return
+		}
+	}
+}
+
+// AscendRange calls f for each key and value with a key in the half-open interval [lo, hi),
+// in ascending key order, until f returns false.
+//
+// The list must not be modified during iteration.
+func (l *List[K, V]) AscendRange(lo, hi K, f func(key K, value V) bool) {
+	for n := l.ceiling(lo); n != nil && l.less(n.key, hi); n = n.next[0] {
+		if !f(n.key, n.value) {
+			return
+		}
+	}
+}
+
+// Descend calls f for each key and value in descending key order, until f returns false.
+//
+// The list must not be modified during iteration.
+func (l *List[K, V]) Descend(f func(key K, value V) bool) {
+	for n := l.tail; n != nil; n = n.prev {
+		if !f(n.key, n.value) {
+			return
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package skiplist
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+// checkList verifies that every level of the list is sorted, that each level is a sublist
+// of the level below, and that back links and the tail are consistent.
+func checkList[K, V any](t *testing.T, l *List[K, V]) {
+	t.Helper()
+	for i := 0; i < l.level; i++ {
+		for n := l.head.next[i]; n != nil && n.next[i] != nil; n = n.next[i] {
+			require.True(t, l.less(n.key, n.next[i].key), "level %d is out of order", i)
+		}
+	}
+	count := 0
+	var prev *node[K, V]
+	for n := l.head.next[0]; n != nil; n = n.next[0] {
+		require.Equal(t, prev, n.prev, "inconsistent back link")
+		prev = n
+		count++
+	}
+	require.Equal(t, prev, l.tail, "inconsistent tail")
+	require.Equal(t, l.size, count, "inconsistent size")
+}
+
+func TestList_InsertSearchDelete(t *testing.T) {
+	l := New[int, int](intLess)
+	rng := rand.New(rand.NewSource(1))
+	expected := make(map[int]int)
+
+	for i := 0; i < 20000; i++ {
+		key := rng.Intn(1000)
+		_, exists := expected[key]
+		if rng.Intn(3) == 0 {
+			assert.Equal(t, exists, l.Delete(key), "unexpected result deleting %d", key)
+			delete(expected, key)
+		} else {
+			assert.Equal(t, !exists, l.Insert(key, i), "unexpected result inserting %d", key)
+			expected[key] = i
+		}
+		if i%1000 == 0 {
+			checkList(t, l)
+		}
+	}
+	checkList(t, l)
+	assert.Equal(t, len(expected), l.Size())
+
+	for key, value := range expected {
+		v, found := l.Search(key)
+		assert.True(t, found, "expected to find %d", key)
+		assert.Equal(t, value, v)
+	}
+	_, found := l.Search(-1)
+	assert.False(t, found)
+
+	for key := range expected {
+		require.True(t, l.Delete(key))
+	}
+	checkList(t, l)
+	assert.Equal(t, 0, l.Size())
+	assert.Equal(t, 1, l.level, "expected unused levels to be released")
+	assert.False(t, l.Delete(1))
+}
+
+func TestList_MinMaxFloorCeiling(t *testing.T) {
+	l := New[int, string](intLess)
+
+	_, _, found := l.Min()
+	assert.False(t, found)
+	_, _, found = l.Max()
+	assert.False(t, found)
+	_, _, found = l.Floor(1)
+	assert.False(t, found)
+	_, _, found = l.Ceiling(1)
+	assert.False(t, found)
+
+	for i := 100; i >= 10; i -= 10 {
+		l.Insert(i, "v")
+	}
+
+	k, _, found := l.Min()
+	assert.True(t, found)
+	assert.Equal(t, 10, k)
+	k, _, found = l.Max()
+	assert.True(t, found)
+	assert.Equal(t, 100, k)
+
+	tests := map[string]struct {
+		key                int
+		floor, ceiling     int
+		floorOK, ceilingOK bool
+	}{
+		"below min":  {key: 5, ceiling: 10, ceilingOK: true},
+		"exact":      {key: 40, floor: 40, ceiling: 40, floorOK: true, ceilingOK: true},
+		"between":    {key: 45, floor: 40, ceiling: 50, floorOK: true, ceilingOK: true},
+		"above max":  {key: 105, floor: 100, floorOK: true},
+		"equals max": {key: 100, floor: 100, ceiling: 100, floorOK: true, ceilingOK: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			k, _, ok := l.Floor(tc.key)
+			assert.Equal(t, tc.floorOK, ok)
+			if ok {
+				assert.Equal(t, tc.floor, k)
+			}
+			k, _, ok = l.Ceiling(tc.key)
+			assert.Equal(t, tc.ceilingOK, ok)
+			if ok {
+				assert.Equal(t, tc.ceiling, k)
+			}
+		})
+	}
+}
+
+func TestList_Ascend_Descend(t *testing.T) {
+	l := New[int, int](intLess)
+	keys := rand.New(rand.NewSource(1)).Perm(300)
+	for _, key := range keys {
+		l.Insert(key, key*2)
+	}
+	sort.Ints(keys)
+
+	var got []int
+	l.Ascend(func(k, v int) bool {
+		assert.Equal(t, k*2, v)
+		got = append(got, k)
+		return true
+	})
+	assert.Equal(t, keys, got)
+
+	got = nil
+	l.Descend(func(k, _ int) bool {
+		got = append(got, k)
+		return len(got) < 3
+	})
+	assert.Equal(t, []int{299, 298, 297}, got)
+
+	got = nil
+	l.Ascend(func(k, _ int) bool {
+		got = append(got, k)
+		return len(got) < 2
+	})
+	assert.Equal(t, []int{0, 1}, got)
+
+	tests := map[string]struct {
+		lo, hi   int
+		expected []int
+	}{
+		"middle":       {lo: 100, hi: 103, expected: []int{100, 101, 102}},
+		"empty":        {lo: 10, hi: 10, expected: nil},
+		"below":        {lo: -10, hi: 2, expected: []int{0, 1}},
+		"above":        {lo: 298, hi: 1000, expected: []int{298, 299}},
+		"out of range": {lo: 600, hi: 700, expected: nil},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []int
+			l.AscendRange(tc.lo, tc.hi, func(k, _ int) bool {
+				got = append(got, k)
+				return true
+			})
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
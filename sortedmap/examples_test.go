@@ -0,0 +1,48 @@
+package sortedmap_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/sortedmap"
+)
+
+func ExampleMap_Range() {
+
+	// create a map of word counts, ordered by word
+	counts := sortedmap.New[string, int](func(a, b string) bool {
+		return a < b
+	})
+	for _, word := range []string{"pear", "apple", "fig", "apple", "pear", "apple"} {
+		n, _ := counts.Get(word)
+		counts.Set(word, n+1)
+	}
+
+	counts.Range(func(word string, n int) bool {
+		fmt.Printf("%s: %d\n", word, n)
+		return true
+	})
+
+	first, _ := counts.MinKey()
+	last, _ := counts.MaxKey()
+	fmt.Println("First:", first, "Last:", last)
+
+	// Output:
+	// apple: 3
+	// fig: 1
+	// pear: 2
+	// First: apple Last: pear
+}
+
+func ExampleNewSmall() {
+
+	// create a map holding up to 8 entries in a sorted slice
+	tags := sortedmap.NewSmall[string, bool](func(a, b string) bool {
+		return a < b
+	}, 8)
+	tags.Set("urgent", true)
+	tags.Set("backend", true)
+
+	fmt.Println(tags.Keys())
+
+	// Output:
+	// [backend urgent]
+}
@@ -0,0 +1,235 @@
+// Package sortedmap provides a generic ordered map with a map-like API.
+//
+// Map wraps rbtree.Tree, so every operation takes O(log n) time, but no node handles are exposed:
+// entries are accessed by key only, much like a built-in map whose keys are kept in order.
+//
+// Maps created with NewSmall hold their first entries in a sorted slice, searched by binary search,
+// and only move them to a Red-Black Tree once they outgrow it. Applications holding many small maps
+// thereby avoid the per-node overhead of the tree (three pointers, a color and a subtree size per entry).
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/sortedmap"
+//
+//	m := sortedmap.New[string, int](func(a, b string) bool { return a < b })
+//	m.Set("b", 2)
+//	m.Set("a", 1)
+//	v, ok := m.Get("a") // 1, true
+//	keys := m.Keys()    // [a b]
+//
+// # Limitations
+//
+// The map is not safe for concurrent use.
+package sortedmap
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"github.com/mikenye/gotrees/rbtree"
+	"slices"
+	"sort"
+)
+
+// entry is a key-value pair held in the sorted slice of a small map.
+type entry[K, V any] struct {
+	key   K
+	value V
+}
+
+// Map represents an ordered map from keys of type K to values of type V, ordered by a LessFunc.
+//
+// Maps must be created with New or NewSmall.
+type Map[K, V any] struct {
+	tree      *rbtree.Tree[K, V] // Underlying Red-Black Tree, or nil while the map holds its entries in small
+	small     []entry[K, V]      // Entries in ascending key order, while tree is nil
+	threshold int                // Maximum number of entries held in small
+	less      bst.LessFunc[K]    // Function to compare keys and maintain order
+}
+
+// New creates and returns a new empty map.
+//
+// Parameters:
+//   - less: A function that defines the ordering of keys.
+//
+// Returns:
+//   - A pointer to a newly created Map[K, V] instance.
+func New[K, V any](less bst.LessFunc[K]) *Map[K, V] {
+	return &Map[K, V]{tree: rbtree.New[K, V](less), less: less}
+}
+
+// NewSmall creates and returns a new empty map, which holds up to threshold entries in a sorted slice,
+// and moves them to a Red-Black Tree when a new key would exceed it.
+//
+// While the map holds at most threshold entries, Get takes O(log n) time, but Set and Delete take O(n)
+// time to shift the slice, which is faster than the tree for small n. Once moved to the tree, the
+// entries stay there, even if the map later shrinks.
+//
+// Parameters:
+//   - less: A function that defines the ordering of keys.
+//   - threshold: The maximum number of entries held in the slice. A threshold of 0 behaves as New.
+//
+// Returns:
+//   - A pointer to a newly created Map[K, V] instance.
+func NewSmall[K, V any](less bst.LessFunc[K], threshold int) *Map[K, V] {
+	if threshold <= 0 {
+		return New[K, V](less)
+	}
+	return &Map[K, V]{threshold: threshold, less: less}
+}
+
+// search returns the index of the first entry of the slice whose key is not less than key,
+// and whether its key is equal to key.
+func (m *Map[K, V]) search(key K) (int, bool) {
+	i := sort.Search(len(m.small), func(i int) bool {
+		return !m.less(m.small[i].key, key)
+	})
+	return i, i < len(m.small) && !m.less(key, m.small[i].key)
+}
+
+// grow moves the entries of the slice to a new Red-Black Tree.
+func (m *Map[K, V]) grow() {
+	m.tree = rbtree.New[K, V](m.less)
+	for _, e := range m.small {
+		m.tree.Insert(e.key, e.value)
+	}
+	m.small = nil
+}
+
+// Len returns the number of entries in the map.
+//
+// This is an O(1) operation.
+func (m *Map[K, V]) Len() int {
+	if m.tree == nil {
+		return len(m.small)
+	}
+	return m.tree.Size()
+}
+
+// Get returns the value associated with key.
+//
+// Returns:
+//   - (value, true) if the key exists in the map.
+//   - (zero value, false) if the key is not found.
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	if m.tree == nil {
+		if i, found := m.search(key); found {
+			return m.small[i].value, true
+		}
+		var zero V
+		return zero, false
+	}
+	if n, found := m.tree.Search(key); found {
+		return m.tree.Value(n), true
+	}
+	var zero V
+	return zero, false
+}
+
+// Set associates value with key, replacing any existing value.
+func (m *Map[K, V]) Set(key K, value V) {
+	if m.tree == nil {
+		i, found := m.search(key)
+		switch {
+		case found:
+			m.small[i].value = value
+			return
+		case len(m.small) < m.threshold:
+			m.small = slices.Insert(m.small, i, entry[K, V]{key: key, value: value})
+			return
+		}
+		m.grow()
+	}
+	m.tree.Insert(key, value)
+}
+
+// Delete removes key from the map.
+//
+// Returns:
+//   - true if the key was found and removed.
+//   - false if the key was not found.
+func (m *Map[K, V]) Delete(key K) bool {
+	if m.tree == nil {
+		i, found := m.search(key)
+		if found {
+			m.small = slices.Delete(m.small, i, i+1)
+		}
+		return found
+	}
+	n, found := m.tree.Search(key)
+	if !found {
+		return false
+	}
+	_, deleted := m.tree.Delete(n)
+	return deleted
+}
+
+// Keys returns the keys of the map, in ascending order.
+func (m *Map[K, V]) Keys() []K {
+	keys := make([]K, 0, m.Len())
+	m.Range(func(key K, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Range calls f for each key and value in ascending key order, until f returns false.
+//
+// The map must not be modified during iteration.
+func (m *Map[K, V]) Range(f func(key K, value V) bool) {
+	if m.tree == nil {
+		for _, e := range m.small {
+			if !f(e.key, e.value) {
+				return
+			}
+		}
+		return
+	}
+	if m.Len() == 0 {
+		return
+	}
+	m.tree.TraverseInOrder(m.tree.Root(), func(n *bst.Node[K, V, rbtree.Color]) bool {
+		return f(m.tree.Key(n), m.tree.Value(n))
+	})
+}
+
+// MinKey returns the smallest key in the map.
+//
+// Returns:
+//   - (key, true) if the map is not empty.
+//   - (zero key, false) if the map is empty.
+func (m *Map[K, V]) MinKey() (K, bool) {
+	if m.tree == nil {
+		return m.smallKey(0)
+	}
+	return m.key(m.tree.Min(m.tree.Root()))
+}
+
+// MaxKey returns the largest key in the map.
+//
+// Returns:
+//   - (key, true) if the map is not empty.
+//   - (zero key, false) if the map is empty.
+func (m *Map[K, V]) MaxKey() (K, bool) {
+	if m.tree == nil {
+		return m.smallKey(len(m.small) - 1)
+	}
+	return m.key(m.tree.Max(m.tree.Root()))
+}
+
+// key returns the key of n, and whether n is not the sentinel nil node.
+func (m *Map[K, V]) key(n *bst.Node[K, V, rbtree.Color]) (K, bool) {
+	if m.tree.IsNil(n) {
+		var zero K
+		return zero, false
+	}
+	return m.tree.Key(n), true
+}
+
+// smallKey returns the key of the i-th entry of the slice, and whether there is such an entry.
+func (m *Map[K, V]) smallKey(i int) (K, bool) {
+	if i < 0 || i >= len(m.small) {
+		var zero K
+		return zero, false
+	}
+	return m.small[i].key, true
+}
@@ -0,0 +1,128 @@
+package sortedmap
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+func TestMap(t *testing.T) {
+	m := New[int, int](intLess)
+	_, ok := m.MinKey()
+	assert.False(t, ok)
+	_, ok = m.MaxKey()
+	assert.False(t, ok)
+	assert.Empty(t, m.Keys())
+
+	rng := rand.New(rand.NewSource(1))
+	expected := make(map[int]int)
+	for i := 0; i < 10000; i++ {
+		key := rng.Intn(1000)
+		if rng.Intn(3) == 0 {
+			_, exists := expected[key]
+			assert.Equal(t, exists, m.Delete(key), "unexpected result deleting %d", key)
+			delete(expected, key)
+		} else {
+			m.Set(key, i)
+			expected[key] = i
+		}
+	}
+	assert.Equal(t, len(expected), m.Len())
+
+	var keys []int
+	for key, value := range expected {
+		keys = append(keys, key)
+		v, ok := m.Get(key)
+		assert.True(t, ok)
+		assert.Equal(t, value, v)
+	}
+	sort.Ints(keys)
+	assert.Equal(t, keys, m.Keys())
+
+	minKey, ok := m.MinKey()
+	assert.True(t, ok)
+	assert.Equal(t, keys[0], minKey)
+	maxKey, ok := m.MaxKey()
+	assert.True(t, ok)
+	assert.Equal(t, keys[len(keys)-1], maxKey)
+
+	_, ok = m.Get(-1)
+	assert.False(t, ok)
+
+	count := 0
+	m.Range(func(key, value int) bool {
+		assert.Equal(t, expected[key], value)
+		count++
+		return count < 10
+	})
+	assert.Equal(t, 10, count, "expected iteration to stop early")
+}
+
+func TestNewSmall(t *testing.T) {
+	m := NewSmall[int, int](intLess, 8)
+	_, ok := m.MinKey()
+	assert.False(t, ok)
+	_, ok = m.MaxKey()
+	assert.False(t, ok)
+
+	for _, key := range []int{5, 3, 7, 1, 3} {
+		m.Set(key, key*10)
+	}
+	assert.Nil(t, m.tree, "expected entries to be held in the slice")
+	assert.Equal(t, 4, m.Len())
+	assert.Equal(t, []int{1, 3, 5, 7}, m.Keys())
+	v, ok := m.Get(3)
+	assert.True(t, ok)
+	assert.Equal(t, 30, v)
+	assert.True(t, m.Delete(3))
+	assert.False(t, m.Delete(3))
+	_, ok = m.Get(3)
+	assert.False(t, ok)
+	minKey, _ := m.MinKey()
+	maxKey, _ := m.MaxKey()
+	assert.Equal(t, 1, minKey)
+	assert.Equal(t, 7, maxKey)
+
+	// the ninth key moves the entries to the tree
+	for key := 10; m.Len() < 8; key++ {
+		m.Set(key, key*10)
+	}
+	assert.Nil(t, m.tree)
+	m.Set(100, 1000)
+	assert.NotNil(t, m.tree, "expected entries to be moved to the tree")
+	assert.Nil(t, m.small)
+	assert.Equal(t, []int{1, 5, 7, 10, 11, 12, 13, 14, 100}, m.Keys())
+	v, ok = m.Get(7)
+	assert.True(t, ok)
+	assert.Equal(t, 70, v)
+
+	// a threshold of 0 uses the tree from the start
+	assert.NotNil(t, NewSmall[int, int](intLess, 0).tree)
+}
+
+func TestNewSmall_random(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		m := NewSmall[int, int](intLess, 16)
+		expected := make(map[int]int)
+		for j := 0; j < 40; j++ {
+			key := rng.Intn(30)
+			if rng.Intn(3) == 0 {
+				_, exists := expected[key]
+				assert.Equal(t, exists, m.Delete(key), "unexpected result deleting %d", key)
+				delete(expected, key)
+			} else {
+				m.Set(key, j)
+				expected[key] = j
+			}
+		}
+		assert.Equal(t, len(expected), m.Len())
+		m.Range(func(key, value int) bool {
+			assert.Equal(t, expected[key], value)
+			return true
+		})
+	}
+}
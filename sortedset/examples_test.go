@@ -0,0 +1,29 @@
+package sortedset_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/sortedset"
+)
+
+func ExampleSet_Intersect() {
+	less := func(a, b string) bool { return a < b }
+
+	// the tags of two articles
+	first := sortedset.New[string](less)
+	for _, tag := range []string{"go", "trees", "generics", "algorithms"} {
+		first.Add(tag)
+	}
+	second := sortedset.New[string](less)
+	for _, tag := range []string{"trees", "go", "databases"} {
+		second.Add(tag)
+	}
+
+	fmt.Println("Shared:", first.Intersect(second).Keys())
+	fmt.Println("All:", first.Union(second).Keys())
+	fmt.Println("Only first:", first.Difference(second).Keys())
+
+	// Output:
+	// Shared: [go trees]
+	// All: [algorithms databases generics go trees]
+	// Only first: [algorithms generics]
+}
@@ -0,0 +1,177 @@
+// Package sortedset provides a generic ordered set, with set algebra and ordered iteration.
+//
+// Set wraps rbtree.Tree, using struct{} values, so membership operations take O(log n) time.
+// Set operations (Union, Intersect and Difference) merge the elements of both sets in order,
+// in O((n + m) log(n + m)) time, and return a new set.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/sortedset"
+//
+//	a := sortedset.New[int](func(a, b int) bool { return a < b })
+//	a.Add(1)
+//	a.Add(2)
+//	b := sortedset.New[int](func(a, b int) bool { return a < b })
+//	b.Add(2)
+//	b.Add(3)
+//	keys := a.Union(b).Keys() // [1 2 3]
+//
+// # Limitations
+//
+// Sets combined by Union, Intersect and Difference must use the same ordering.
+// The set is not safe for concurrent use.
+package sortedset
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"github.com/mikenye/gotrees/rbtree"
+)
+
+// Set represents an ordered set of keys of type K, ordered by a LessFunc.
+//
+// Sets must be created with New.
+type Set[K any] struct {
+	tree *rbtree.Tree[K, struct{}] // Underlying Red-Black Tree
+	less bst.LessFunc[K]           // Function to compare keys and maintain order
+}
+
+// New creates and returns a new empty set.
+//
+// Parameters:
+//   - less: A function that defines the ordering of keys.
+//
+// Returns:
+//   - A pointer to a newly created Set[K] instance.
+func New[K any](less bst.LessFunc[K]) *Set[K] {
+	return &Set[K]{
+		tree: rbtree.New[K, struct{}](less),
+		less: less,
+	}
+}
+
+// Len returns the number of keys in the set.
+//
+// This is an O(1) operation.
+func (s *Set[K]) Len() int {
+	return s.tree.Size()
+}
+
+// Add adds key to the set.
+//
+// Returns:
+//   - true if the key was added.
+//   - false if the key was already in the set.
+func (s *Set[K]) Add(key K) bool {
+	_, added := s.tree.Insert(key, struct{}{})
+	return added
+}
+
+// Remove removes key from the set.
+//
+// Returns:
+//   - true if the key was found and removed.
+//   - false if the key was not in the set.
+func (s *Set[K]) Remove(key K) bool {
+	n, found := s.tree.Search(key)
+	if !found {
+		return false
+	}
+	_, deleted := s.tree.Delete(n)
+	return deleted
+}
+
+// Contains returns true if key is in the set.
+func (s *Set[K]) Contains(key K) bool {
+	_, found := s.tree.Search(key)
+	return found
+}
+
+// Min returns the smallest key in the set.
+//
+// Returns:
+//   - (key, true) if the set is not empty.
+//   - (zero key, false) if the set is empty.
+func (s *Set[K]) Min() (K, bool) {
+	return s.key(s.tree.Min(s.tree.Root()))
+}
+
+// Max returns the largest key in the set.
+//
+// Returns:
+//   - (key, true) if the set is not empty.
+//   - (zero key, false) if the set is empty.
+func (s *Set[K]) Max() (K, bool) {
+	return s.key(s.tree.Max(s.tree.Root()))
+}
+
+// key returns the key of n, and whether n is not the sentinel nil node.
+func (s *Set[K]) key(n *bst.Node[K, struct{}, rbtree.Color]) (K, bool) {
+	if s.tree.IsNil(n) {
+		var zero K
+		return zero, false
+	}
+	return s.tree.Key(n), true
+}
+
+// Keys returns the keys of the set, in ascending order.
+func (s *Set[K]) Keys() []K {
+	keys := make([]K, 0, s.Len())
+	s.Range(func(key K) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Range calls f for each key in ascending order, until f returns false.
+//
+// The set must not be modified during iteration.
+func (s *Set[K]) Range(f func(key K) bool) {
+	for n := s.tree.Min(s.tree.Root()); !s.tree.IsNil(n); n = s.tree.Successor(n) {
+		if !f(s.tree.Key(n)) {
+			return
+		}
+	}
+}
+
+// Union returns a new set holding the keys in s, other, or both.
+func (s *Set[K]) Union(other *Set[K]) *Set[K] {
+	return s.merge(other, true, true, true)
+}
+
+// Intersect returns a new set holding the keys in both s and other.
+func (s *Set[K]) Intersect(other *Set[K]) *Set[K] {
+	return s.merge(other, false, true, false)
+}
+
+// Difference returns a new set holding the keys in s that are not in other.
+func (s *Set[K]) Difference(other *Set[K]) *Set[K] {
+	return s.merge(other, true, false, false)
+}
+
+// merge walks the keys of s and other in order, and returns a new set holding the keys
+// only in s if onlyS is set, those in both if both is set, and those only in other if onlyOther is set.
+func (s *Set[K]) merge(other *Set[K], onlyS, both, onlyOther bool) *Set[K] {
+	result := New[K](s.less)
+	a, b := s.tree.Min(s.tree.Root()), other.tree.Min(other.tree.Root())
+	for !s.tree.IsNil(a) || !other.tree.IsNil(b) {
+		switch {
+		case other.tree.IsNil(b) || (!s.tree.IsNil(a) && s.less(s.tree.Key(a), other.tree.Key(b))):
+			if onlyS {
+				result.Add(s.tree.Key(a))
+			}
+			a = s.tree.Successor(a)
+		case s.tree.IsNil(a) || s.less(other.tree.Key(b), s.tree.Key(a)):
+			if onlyOther {
+				result.Add(other.tree.Key(b))
+			}
+			b = other.tree.Successor(b)
+		default:
+			if both {
+				result.Add(s.tree.Key(a))
+			}
+			a, b = s.tree.Successor(a), other.tree.Successor(b)
+		}
+	}
+	return result
+}
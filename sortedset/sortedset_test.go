@@ -0,0 +1,84 @@
+package sortedset
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+// fromKeys returns a set holding keys.
+func fromKeys(keys ...int) *Set[int] {
+	s := New[int](intLess)
+	for _, k := range keys {
+		s.Add(k)
+	}
+	return s
+}
+
+func TestSet_AddRemoveContains(t *testing.T) {
+	s := New[int](intLess)
+	_, ok := s.Min()
+	assert.False(t, ok)
+	_, ok = s.Max()
+	assert.False(t, ok)
+	assert.Empty(t, s.Keys())
+
+	rng := rand.New(rand.NewSource(1))
+	expected := make(map[int]bool)
+	for i := 0; i < 10000; i++ {
+		key := rng.Intn(1000)
+		if rng.Intn(3) == 0 {
+			assert.Equal(t, expected[key], s.Remove(key), "unexpected result removing %d", key)
+			delete(expected, key)
+		} else {
+			assert.Equal(t, !expected[key], s.Add(key), "unexpected result adding %d", key)
+			expected[key] = true
+		}
+	}
+	assert.Equal(t, len(expected), s.Len())
+
+	var keys []int
+	for key := range expected {
+		keys = append(keys, key)
+		assert.True(t, s.Contains(key))
+	}
+	sort.Ints(keys)
+	assert.Equal(t, keys, s.Keys())
+	assert.False(t, s.Contains(-1))
+
+	minKey, _ := s.Min()
+	maxKey, _ := s.Max()
+	assert.Equal(t, keys[0], minKey)
+	assert.Equal(t, keys[len(keys)-1], maxKey)
+
+	count := 0
+	s.Range(func(int) bool {
+		count++
+		return count < 5
+	})
+	assert.Equal(t, 5, count, "expected iteration to stop early")
+}
+
+func TestSet_algebra(t *testing.T) {
+	a := fromKeys(1, 3, 5, 7, 9)
+	b := fromKeys(3, 4, 5, 6)
+	empty := fromKeys()
+
+	assert.Equal(t, []int{1, 3, 4, 5, 6, 7, 9}, a.Union(b).Keys())
+	assert.Equal(t, []int{3, 5}, a.Intersect(b).Keys())
+	assert.Equal(t, []int{1, 7, 9}, a.Difference(b).Keys())
+	assert.Equal(t, []int{4, 6}, b.Difference(a).Keys())
+
+	assert.Equal(t, a.Keys(), a.Union(empty).Keys())
+	assert.Equal(t, a.Keys(), empty.Union(a).Keys())
+	assert.Empty(t, a.Intersect(empty).Keys())
+	assert.Equal(t, a.Keys(), a.Difference(empty).Keys())
+	assert.Empty(t, a.Difference(a).Keys())
+
+	// the operands are unchanged
+	assert.Equal(t, []int{1, 3, 5, 7, 9}, a.Keys())
+	assert.Equal(t, []int{3, 4, 5, 6}, b.Keys())
+}
@@ -0,0 +1,33 @@
+package topk_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/topk"
+	"strings"
+)
+
+func ExampleTracker_Items() {
+
+	// count the words of a text
+	counts := make(map[string]int)
+	for _, word := range strings.Fields("the cat sat on the mat and the dog sat on the cat") {
+		counts[word]++
+	}
+
+	// keep the three most frequent words
+	top := topk.New[string, int](3, func(a, b int) bool {
+		return a < b
+	})
+	for _, word := range []string{"the", "cat", "sat", "on", "mat", "and", "dog"} {
+		top.Offer(word, counts[word])
+	}
+
+	for _, item := range top.Items() {
+		fmt.Println(item.Value, item.Score)
+	}
+
+	// Output:
+	// the 4
+	// cat 2
+	// sat 2
+}
@@ -0,0 +1,146 @@
+// Package topk provides a generic tracker of the K items with the largest scores seen in a stream.
+//
+// Tracker keeps at most K items in an rbtree.Tree keyed by score, so:
+//   - Tracker.Offer adds an item while fewer than K are held, and otherwise replaces the item
+//     with the smallest score if the new item scores higher, in O(log K) time.
+//   - Tracker.Min returns the smallest score held, the threshold a new item must beat.
+//   - Tracker.Items extracts the items in descending score order, without disturbing the tracker.
+//
+// Items with equal scores are ranked in the order they were offered, earliest first, so an item
+// scoring the same as the smallest item held does not replace it, and the most recently offered
+// of several items with the smallest score is replaced first.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/topk"
+//
+//	top := topk.New[string, int](3, func(a, b int) bool { return a < b })
+//	for word, count := range counts {
+//		top.Offer(word, count)
+//	}
+//	for _, item := range top.Items() {
+//		fmt.Println(item.Value, item.Score)
+//	}
+//
+// # Limitations
+//
+// The tracker is not safe for concurrent use.
+package topk
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/bst"
+	"github.com/mikenye/gotrees/rbtree"
+)
+
+// key orders the items of a tracker by score, then by descending arrival,
+// so that among equal scores the earliest offered item is the greatest.
+type key[S any] struct {
+	score S
+	seq   uint64 // arrival order, increasing with each offer
+}
+
+// Item represents an item held by a tracker, with its score.
+type Item[T, S any] struct {
+	Value T
+	Score S
+}
+
+// Tracker keeps the K items of type T with the largest scores of type S.
+//
+// Trackers must be created with New.
+type Tracker[T, S any] struct {
+	tree *rbtree.Tree[key[S], T] // Items held
+	less bst.LessFunc[S]         // Ordering of scores
+	k    int                     // Maximum number of items held
+	seq  uint64                  // Arrival order of the next item
+}
+
+// New creates and returns a new empty tracker.
+//
+// Parameters:
+//   - k: The number of items to keep. Must be at least 1.
+//   - less: A function that defines the ordering of scores. Items with greater scores are kept.
+//
+// Returns:
+//   - A pointer to a newly created Tracker[T, S] instance.
+func New[T, S any](k int, less bst.LessFunc[S]) *Tracker[T, S] {
+	if k < 1 {
+		panic(fmt.Sprintf("topk: invalid k %d", k))
+	}
+	t := &Tracker[T, S]{less: less, k: k}
+	t.Reset()
+	return t
+}
+
+// K returns the maximum number of items held by the tracker.
+func (t *Tracker[T, S]) K() int {
+	return t.k
+}
+
+// Len returns the number of items held by the tracker, at most K.
+//
+// This is an O(1) operation.
+func (t *Tracker[T, S]) Len() int {
+	return t.tree.Size()
+}
+
+// Offer offers an item with the given score to the tracker.
+//
+// The item is kept if the tracker holds fewer than K items. Otherwise, it replaces the item
+// with the smallest score if its score is greater.
+//
+// Returns:
+//   - true if the item was kept.
+//   - false if it did not score high enough.
+func (t *Tracker[T, S]) Offer(value T, score S) bool {
+	if t.tree.Size() == t.k {
+		n := t.tree.Min(t.tree.Root())
+		if !t.less(t.tree.Key(n).score, score) {
+			return false
+		}
+		t.tree.Delete(n)
+	}
+	t.tree.Insert(key[S]{score, t.seq}, value)
+	t.seq++
+	return true
+}
+
+// Min returns the item with the smallest score held by the tracker, in O(log K) time.
+// Once the tracker is full, new items must score higher than this to be kept.
+//
+// Returns:
+//   - (item, true) if the tracker is not empty.
+//   - (zero item, false) otherwise.
+func (t *Tracker[T, S]) Min() (Item[T, S], bool) {
+	n := t.tree.Min(t.tree.Root())
+	if t.tree.IsNil(n) {
+		return Item[T, S]{}, false
+	}
+	return Item[T, S]{Value: t.tree.Value(n), Score: t.tree.Key(n).score}, true
+}
+
+// Items returns the items held by the tracker in descending score order, in O(K) time.
+// Items with equal scores are returned in the order they were offered.
+func (t *Tracker[T, S]) Items() []Item[T, S] {
+	items := make([]Item[T, S], t.tree.Size())
+	i := len(items) - 1
+	for n := t.tree.Min(t.tree.Root()); !t.tree.IsNil(n); n = t.tree.Successor(n) {
+		items[i] = Item[T, S]{Value: t.tree.Value(n), Score: t.tree.Key(n).score}
+		i--
+	}
+	return items
+}
+
+// Reset removes every item from the tracker.
+func (t *Tracker[T, S]) Reset() {
+	t.tree = rbtree.New[key[S], T](func(a, b key[S]) bool {
+		if t.less(a.score, b.score) {
+			return true
+		}
+		if t.less(b.score, a.score) {
+			return false
+		}
+		return a.seq > b.seq
+	})
+}
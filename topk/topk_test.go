@@ -0,0 +1,64 @@
+package topk
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+func TestTracker(t *testing.T) {
+	for _, k := range []int{1, 5, 50} {
+		top := New[int, int](k, intLess)
+		assert.Equal(t, k, top.K())
+		_, found := top.Min()
+		assert.False(t, found)
+		assert.Empty(t, top.Items())
+
+		// model of the tracker: every item offered, its value being its arrival order
+		rng := rand.New(rand.NewSource(1))
+		var offered []Item[int, int]
+		for i := 0; i < 2000; i++ {
+			item := Item[int, int]{Value: i, Score: rng.Intn(100)}
+			smallest, full := top.Min()
+			full = full && top.Len() == k
+			kept := top.Offer(item.Value, item.Score)
+			assert.Equal(t, !full || item.Score > smallest.Score, kept)
+			offered = append(offered, item)
+
+			if i%20 == 0 {
+				require.NoError(t, top.tree.IsTreeValid())
+				expected := append([]Item[int, int](nil), offered...)
+				sort.SliceStable(expected, func(i, j int) bool {
+					return expected[i].Score > expected[j].Score
+				})
+				expected = expected[:min(k, len(expected))]
+				require.Equal(t, expected, top.Items())
+				require.Equal(t, len(expected), top.Len())
+				smallest, found := top.Min()
+				require.True(t, found)
+				require.Equal(t, expected[len(expected)-1], smallest)
+			}
+		}
+
+		top.Reset()
+		assert.Equal(t, 0, top.Len())
+		assert.True(t, top.Offer(1, 1))
+	}
+}
+
+func TestTracker_Ties(t *testing.T) {
+	top := New[string, int](2, intLess)
+	top.Offer("a", 5)
+	top.Offer("b", 5)
+	assert.False(t, top.Offer("c", 5))
+	assert.True(t, top.Offer("d", 6))
+	assert.Equal(t, []Item[string, int]{{"d", 6}, {"a", 5}}, top.Items())
+}
+
+func TestTracker_InvalidK(t *testing.T) {
+	assert.Panics(t, func() { New[int, int](0, intLess) })
+}
@@ -0,0 +1,37 @@
+package trees_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/btree"
+	"github.com/mikenye/gotrees/rbtree"
+	"github.com/mikenye/gotrees/skiplist"
+	"github.com/mikenye/gotrees/trees"
+)
+
+func ExampleSorted() {
+	less := func(a, b int) bool { return a < b }
+
+	// the same code works with any implementation
+	for _, index := range []trees.Sorted[int, string]{
+		btree.New[int, string](less, 2),
+		skiplist.New[int, string](less),
+		trees.FromRBTree(rbtree.New[int, string](less)),
+	} {
+		index.Insert(30, "thirty")
+		index.Insert(10, "ten")
+		index.Insert(20, "twenty")
+
+		key, value, _ := index.Floor(25)
+		fmt.Print(key, " ", value, ":")
+		index.Descend(func(key int, value string) bool {
+			fmt.Print(" ", value)
+			return true
+		})
+		fmt.Println()
+	}
+
+	// Output:
+	// 20 twenty: thirty twenty ten
+	// 20 twenty: thirty twenty ten
+	// 20 twenty: thirty twenty ten
+}
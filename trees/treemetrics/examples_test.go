@@ -0,0 +1,31 @@
+package treemetrics_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/rbtree"
+	"github.com/mikenye/gotrees/trees"
+	"github.com/mikenye/gotrees/trees/treemetrics"
+)
+
+func ExampleTree_Stats() {
+
+	// instrument a Red-Black Tree, reporting its height and rotations
+	rb := rbtree.New[int, string](func(a, b int) bool { return a < b })
+	tree := treemetrics.New(trees.FromRBTree(rb),
+		treemetrics.WithHeight(rb.Height),
+		treemetrics.WithRotations(rb.Rotations),
+	)
+
+	for i := 0; i < 7; i++ {
+		tree.Insert(i, "value")
+	}
+	tree.Search(3)
+
+	stats := tree.Stats()
+	fmt.Println("size:", stats.Size, "height:", stats.Height, "rotations:", stats.Rotations)
+	fmt.Println("inserts:", stats.Ops["insert"].Count, "searches:", stats.Ops["search"].Count)
+
+	// Output:
+	// size: 7 height: 3 rotations: 3
+	// inserts: 7 searches: 1
+}
@@ -0,0 +1,274 @@
+// Package treemetrics instruments implementations of trees.Sorted, exposing their size, height,
+// operation counts and latencies, and rotation counts, for monitoring.
+//
+// Tree wraps a tree, timing every operation made through it. Its statistics are available as a
+// Stats value, for use with any metrics library, and as an expvar.Var, published with Tree.Publish
+// to be served as JSON by the expvar handler (under /debug/vars).
+//
+// The package only depends on the standard library. To export the statistics to Prometheus,
+// register collectors reading Tree.Stats, e.g. with prometheus.NewGaugeFunc and prometheus.NewCounterFunc.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/trees/treemetrics"
+//
+//	rb := rbtree.New[int, string](func(a, b int) bool { return a < b })
+//	tree := treemetrics.New(trees.FromRBTree(rb), treemetrics.WithHeight(rb.Height), treemetrics.WithRotations(rb.Rotations))
+//	tree.Publish("sessions")
+//	tree.Insert(1, "one")
+//
+// # Limitations
+//
+// Operations made through the Tree are serialized by a mutex, so that statistics can be read
+// from other goroutines, e.g. by the expvar handler. Operations made directly on the wrapped tree
+// are not counted, and must not run concurrently with operations through the Tree or with Tree.Stats.
+package treemetrics
+
+import (
+	"expvar"
+	"github.com/mikenye/gotrees/trees"
+	"sync"
+	"time"
+)
+
+// Op identifies an operation of trees.Sorted.
+type Op uint8
+
+const (
+	OpSearch      Op = iota // Sorted.Search
+	OpInsert                // Sorted.Insert
+	OpDelete                // Sorted.Delete
+	OpMin                   // Sorted.Min
+	OpMax                   // Sorted.Max
+	OpFloor                 // Sorted.Floor
+	OpCeiling               // Sorted.Ceiling
+	OpAscend                // Sorted.Ascend
+	OpAscendRange           // Sorted.AscendRange
+	OpDescend               // Sorted.Descend
+	numOps
+)
+
+// opNames holds the name of each Op, as used in Stats.
+var opNames = [numOps]string{"search", "insert", "delete", "min", "max", "floor", "ceiling", "ascend", "ascend_range", "descend"}
+
+// String returns the name of the operation, such as "search".
+func (op Op) String() string {
+	if op < numOps {
+		return opNames[op]
+	}
+	return "unknown"
+}
+
+// OpStats holds the statistics of one operation.
+type OpStats struct {
+	Count uint64        `json:"count"`    // Number of calls
+	Total time.Duration `json:"total_ns"` // Total time spent in calls, including callbacks of iterations
+	Max   time.Duration `json:"max_ns"`   // Longest call
+}
+
+// Mean returns the mean duration of a call, or 0 if there were none.
+func (s OpStats) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Count)
+}
+
+// Stats holds the statistics of a Tree, as returned by Tree.Stats.
+type Stats struct {
+	Size      int                `json:"size"`      // Number of keys
+	Height    int                `json:"height"`    // Height of the tree, or -1 if unknown (see WithHeight)
+	Rotations uint64             `json:"rotations"` // Number of rotations, or 0 if unknown (see WithRotations)
+	Ops       map[string]OpStats `json:"ops"`       // Statistics of each operation called at least once, by Op name
+}
+
+// options holds the configuration of a Tree.
+type options struct {
+	height    func() int
+	rotations func() uint64
+}
+
+// Option configures a Tree created with New.
+type Option func(o *options)
+
+// WithHeight reports the height of the tree in Stats, as returned by f, such as bst.Tree.Height or btree.Tree.Height.
+//
+// f is called on every call to Tree.Stats. As computing the height of a binary tree visits every node,
+// scraping the statistics of a large tree often may be costly.
+func WithHeight(f func() int) Option {
+	return func(o *options) {
+		o.height = f
+	}
+}
+
+// WithRotations reports the number of rotations performed by the tree in Stats, as returned by f,
+// such as bst.Tree.Rotations.
+func WithRotations(f func() uint64) Option {
+	return func(o *options) {
+		o.rotations = f
+	}
+}
+
+// Tree wraps an implementation of trees.Sorted, recording statistics of the operations made through it.
+// It implements trees.Sorted itself, so can be used in place of the wrapped tree.
+//
+// Trees must be created with New.
+type Tree[K, V any] struct {
+	mu   sync.Mutex
+	tree trees.Sorted[K, V]
+	ops  [numOps]OpStats
+	options
+}
+
+// New wraps tree, returning a Tree recording statistics of the operations made through it.
+//
+// Parameters:
+//   - tree: The tree to instrument.
+//   - opts: Optional statistics to report (see WithHeight and WithRotations).
+//
+// Returns:
+//   - A pointer to a newly created Tree[K, V] instance.
+func New[K, V any](tree trees.Sorted[K, V], opts ...Option) *Tree[K, V] {
+	t := &Tree[K, V]{tree: tree}
+	for _, opt := range opts {
+		opt(&t.options)
+	}
+	return t
+}
+
+// start locks the tree, and returns the time an operation starts.
+func (t *Tree[K, V]) start() time.Time {
+	t.mu.Lock()
+	return time.Now()
+}
+
+// done records a call of op that started at start, and unlocks the tree.
+func (t *Tree[K, V]) done(op Op, start time.Time) {
+	elapsed := time.Since(start)
+	s := &t.ops[op]
+	s.Count++
+	s.Total += elapsed
+	s.Max = max(s.Max, elapsed)
+	t.mu.Unlock()
+}
+
+// Stats returns the statistics of the tree. It is safe to call concurrently with operations made through the tree.
+func (t *Tree[K, V]) Stats() Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := Stats{
+		Size:   t.tree.Size(),
+		Height: -1,
+		Ops:    make(map[string]OpStats),
+	}
+	if t.height != nil {
+		stats.Height = t.height()
+	}
+	if t.rotations != nil {
+		stats.Rotations = t.rotations()
+	}
+	for op, s := range t.ops {
+		if s.Count > 0 {
+			stats.Ops[Op(op).String()] = s
+		}
+	}
+	return stats
+}
+
+// Var returns an expvar.Var reporting the statistics of the tree, encoded as JSON.
+func (t *Tree[K, V]) Var() expvar.Var {
+	return expvar.Func(func() any {
+		return t.Stats()
+	})
+}
+
+// Publish publishes the statistics of the tree as the expvar variable name (see Tree.Var).
+// As with expvar.Publish, it panics if name is already registered.
+func (t *Tree[K, V]) Publish(name string) {
+	expvar.Publish(name, t.Var())
+}
+
+// Size returns the number of keys. It is not counted as an operation.
+func (t *Tree[K, V]) Size() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.tree.Size()
+}
+
+// Search returns the value associated with key, and whether the key was found.
+func (t *Tree[K, V]) Search(key K) (V, bool) {
+	defer t.done(OpSearch, t.start())
+	return t.tree.Search(key)
+}
+
+// Insert associates value with key, returning true if the key is new, or false if its value was updated.
+func (t *Tree[K, V]) Insert(key K, value V) bool {
+	defer t.done(OpInsert, t.start())
+	return t.tree.Insert(key, value)
+}
+
+// Delete removes key, returning true if it was found.
+func (t *Tree[K, V]) Delete(key K) bool {
+	defer t.done(OpDelete, t.start())
+	return t.tree.Delete(key)
+}
+
+// Min returns the smallest key and its value, and false if the tree is empty.
+func (t *Tree[K, V]) Min() (K, V, bool) {
+	defer t.done(OpMin, t.start())
+	return t.tree.Min()
+}
+
+// Max returns the largest key and its value, and false if the tree is empty.
+func (t *Tree[K, V]) Max() (K, V, bool) {
+	defer t.done(OpMax, t.start())
+	return t.tree.Max()
+}
+
+// Floor returns the largest key less than or equal to key, and its value, and false if there is none.
+func (t *Tree[K, V]) Floor(key K) (K, V, bool) {
+	defer t.done(OpFloor, t.start())
+	return t.tree.Floor(key)
+}
+
+// Ceiling returns the smallest key greater than or equal to key, and its value, and false if there is none.
+func (t *Tree[K, V]) Ceiling(key K) (K, V, bool) {
+	defer t.done(OpCeiling, t.start())
+	return t.tree.Ceiling(key)
+}
+
+// Ascend calls f for each key and value in ascending key order, until f returns false.
+// f must not call methods of the Tree, as the tree is locked during iteration.
+func (t *Tree[K, V]) Ascend(f func(key K, value V) bool) {
+	defer t.done(OpAscend, t.start())
+	t.tree.Ascend(f)
+}
+
+// AscendRange calls f for each key in the half-open interval [lo, hi) and its value,
+// in ascending key order, until f returns false.
+// f must not call methods of the Tree, as the tree is locked during iteration.
+func (t *Tree[K, V]) AscendRange(lo, hi K, f func(key K, value V) bool) {
+	defer t.done(OpAscendRange, t.start())
+	t.tree.AscendRange(lo, hi, f)
+}
+
+// Descend calls f for each key and value in descending key order, until f returns false.
+// f must not call methods of the Tree, as the tree is locked during iteration.
+func (t *Tree[K, V]) Descend(f func(key K, value V) bool) {
+	defer t.done(OpDescend, t.start())
+	t.tree.Descend(f)
+}
+
+// IsTreeValid checks the properties of the wrapped tree, if it has an IsTreeValid method.
+//
+// Returns:
+//   - nil if the tree is valid, or cannot be checked.
+//   - An error describing the first violation found otherwise.
+func (t *Tree[K, V]) IsTreeValid() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if v, ok := t.tree.(interface{ IsTreeValid() error }); ok {
+		return v.IsTreeValid()
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package treemetrics
+
+import (
+	"encoding/json"
+	"github.com/mikenye/gotrees/rbtree"
+	"github.com/mikenye/gotrees/trees"
+	"github.com/mikenye/gotrees/trees/treetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+func TestTree_conformance(t *testing.T) {
+	treetest.Run(t, func() trees.Sorted[int, int] {
+		return New(trees.FromRBTree(rbtree.New[int, int](intLess)))
+	})
+}
+
+func TestTree_Stats(t *testing.T) {
+	rb := rbtree.New[int, string](intLess)
+	tree := New(trees.FromRBTree(rb), WithHeight(rb.Height), WithRotations(rb.Rotations))
+
+	stats := tree.Stats()
+	assert.Equal(t, 0, stats.Size)
+	assert.Equal(t, -1, stats.Height)
+	assert.Empty(t, stats.Ops)
+
+	for i := 0; i < 100; i++ {
+		tree.Insert(i, "value")
+	}
+	tree.Search(1)
+	tree.Delete(1)
+	tree.Ascend(func(key int, value string) bool { return true })
+
+	stats = tree.Stats()
+	assert.Equal(t, 99, stats.Size)
+	assert.Equal(t, rb.Height(), stats.Height)
+	assert.Positive(t, stats.Rotations, "expected sorted insertions to rotate the tree")
+	assert.Equal(t, rb.Rotations(), stats.Rotations)
+	assert.Len(t, stats.Ops, 4)
+	assert.Equal(t, uint64(100), stats.Ops["insert"].Count)
+	assert.Equal(t, uint64(1), stats.Ops["search"].Count)
+	assert.Equal(t, uint64(1), stats.Ops["delete"].Count)
+	assert.Equal(t, uint64(1), stats.Ops["ascend"].Count)
+	assert.LessOrEqual(t, stats.Ops["insert"].Max, stats.Ops["insert"].Total)
+	assert.LessOrEqual(t, stats.Ops["insert"].Mean(), stats.Ops["insert"].Max)
+
+	// without options, height and rotations are unknown
+	stats = New(trees.FromRBTree(rb)).Stats()
+	assert.Equal(t, -1, stats.Height)
+	assert.Zero(t, stats.Rotations)
+}
+
+func TestTree_Var(t *testing.T) {
+	tree := New(trees.FromRBTree(rbtree.New[int, int](intLess)))
+	tree.Insert(1, 1)
+	tree.Publish("treemetrics_test")
+	assert.Panics(t, func() { tree.Publish("treemetrics_test") }, "expected duplicate name to panic")
+
+	var decoded Stats
+	require.NoError(t, json.Unmarshal([]byte(tree.Var().String()), &decoded))
+	assert.Equal(t, 1, decoded.Size)
+	assert.Equal(t, uint64(1), decoded.Ops["insert"].Count)
+}
+
+func TestOp_String(t *testing.T) {
+	assert.Equal(t, "ascend_range", OpAscendRange.String())
+	assert.Equal(t, "unknown", numOps.String())
+}
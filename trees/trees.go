@@ -0,0 +1,229 @@
+// Package trees defines Sorted, a common key-based interface for the ordered containers of this module,
+// so that applications can swap implementations, and tests can be shared between them.
+//
+// btree.Tree, indextree.Tree, llrb.Tree, llrb.Concurrent and skiplist.List implement Sorted directly. The node-based trees, whose
+// methods take and return node handles, are adapted to it:
+//   - FromNodes adapts scapegoat.Tree, ziptree.Tree, and other trees extending bst.Tree
+//     whose Delete method returns a bool.
+//   - FromRBTree adapts rbtree.Tree and rbtree.AuxTree.
+//   - FromBST adapts a plain bst.Tree.
+//
+// The treetest subpackage provides a conformance test suite for implementations of Sorted.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/trees"
+//
+//	less := func(a, b int) bool { return a < b }
+//	var index trees.Sorted[int, string] = btree.New[int, string](less, 32)
+//	if smallMaps {
+//		index = trees.FromRBTree(rbtree.New[int, string](less))
+//	}
+//	index.Insert(1, "one")
+//
+// # Limitations
+//
+// Sorted assumes unique keys: adapted trees must not be in multiset mode (see bst.WithDuplicateKeys).
+package trees
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"github.com/mikenye/gotrees/rbtree"
+)
+
+// Sorted is the key-based interface of an ordered map from keys of type K to values of type V.
+type Sorted[K, V any] interface {
+	// Size returns the number of keys.
+	Size() int
+
+	// Search returns the value associated with key, and whether the key was found.
+	Search(key K) (V, bool)
+
+	// Insert associates value with key, returning true if the key is new, or false if its value was updated.
+	Insert(key K, value V) bool
+
+	// Delete removes key, returning true if it was found.
+	Delete(key K) bool
+
+	// Min returns the smallest key and its value, and false if the map is empty.
+	Min() (K, V, bool)
+
+	// Max returns the largest key and its value, and false if the map is empty.
+	Max() (K, V, bool)
+
+	// Floor returns the largest key less than or equal to key, and its value, and false if there is none.
+	Floor(key K) (K, V, bool)
+
+	// Ceiling returns the smallest key greater than or equal to key, and its value, and false if there is none.
+	Ceiling(key K) (K, V, bool)
+
+	// Ascend calls f for each key and value in ascending key order, until f returns false.
+	Ascend(f func(key K, value V) bool)
+
+	// AscendRange calls f for each key in the half-open interval [lo, hi) and its value,
+	// in ascending key order, until f returns false.
+	AscendRange(lo, hi K, f func(key K, value V) bool)
+
+	// Descend calls f for each key and value in descending key order, until f returns false.
+	Descend(f func(key K, value V) bool)
+}
+
+// NodeTree is the node-based interface shared by the trees extending bst.Tree,
+// such as rbtree.Tree, scapegoat.Tree and ziptree.Tree.
+type NodeTree[K, V, M any] interface {
+	Size() int
+	Root() *bst.Node[K, V, M]
+	IsNil(n *bst.Node[K, V, M]) bool
+	Key(n *bst.Node[K, V, M]) K
+	Value(n *bst.Node[K, V, M]) V
+	Min(n *bst.Node[K, V, M]) *bst.Node[K, V, M]
+	Max(n *bst.Node[K, V, M]) *bst.Node[K, V, M]
+	Successor(n *bst.Node[K, V, M]) *bst.Node[K, V, M]
+	Predecessor(n *bst.Node[K, V, M]) *bst.Node[K, V, M]
+	Search(key K) (*bst.Node[K, V, M], bool)
+	Floor(key K) (*bst.Node[K, V, M], bool)
+	Ceiling(key K) (*bst.Node[K, V, M], bool)
+	Rank(key K) int
+	Insert(key K, value V) (*bst.Node[K, V, M], bool)
+	Delete(n *bst.Node[K, V, M]) bool
+}
+
+// nodes adapts a NodeTree to the Sorted interface.
+type nodes[K, V, M any] struct {
+	tree NodeTree[K, V, M]
+}
+
+// FromNodes adapts a node-based tree to the Sorted interface.
+//
+// The adapter holds no state of its own: the tree can still be used directly,
+// and changes made either way are visible through both.
+//
+// Returns:
+//   - A Sorted[K, V] backed by t.
+func FromNodes[K, V, M any](t NodeTree[K, V, M]) Sorted[K, V] {
+	return nodes[K, V, M]{tree: t}
+}
+
+// bstTree gives a bst.Tree the Delete method of NodeTree.
+type bstTree[K, V, M any] struct {
+	*bst.Tree[K, V, M]
+}
+
+// Delete removes node n, returning true if it was removed.
+func (t bstTree[K, V, M]) Delete(n *bst.Node[K, V, M]) bool {
+	_, deleted := t.Tree.Delete(n)
+	return deleted
+}
+
+// FromBST adapts a plain, unbalanced bst.Tree to the Sorted interface (see FromNodes).
+//
+// Returns:
+//   - A Sorted[K, V] backed by t.
+func FromBST[K, V, M any](t *bst.Tree[K, V, M]) Sorted[K, V] {
+	return FromNodes[K, V, M](bstTree[K, V, M]{t})
+}
+
+// rbTree gives an rbtree.Base the Delete method of NodeTree.
+type rbTree[K, V any, M rbtree.ColorMetadata[M]] struct {
+	*rbtree.Base[K, V, M]
+}
+
+// Delete removes node n, returning true if it was removed.
+func (t rbTree[K, V, M]) Delete(n *bst.Node[K, V, M]) bool {
+	_, deleted := t.Base.Delete(n)
+	return deleted
+}
+
+// FromRBTree adapts a Red-Black Tree, such as an rbtree.Tree, or the Base of an rbtree.AuxTree,
+// to the Sorted interface (see FromNodes).
+//
+// Returns:
+//   - A Sorted[K, V] backed by t.
+func FromRBTree[K, V any, M rbtree.ColorMetadata[M]](t *rbtree.Base[K, V, M]) Sorted[K, V] {
+	return FromNodes[K, V, M](rbTree[K, V, M]{t})
+}
+
+// entry returns the key and value of n, and false if n is nil.
+func (a nodes[K, V, M]) entry(n *bst.Node[K, V, M], found bool) (K, V, bool) {
+	if !found || a.tree.IsNil(n) {
+		var k K
+		var v V
+		return k, v, false
+	}
+	return a.tree.Key(n), a.tree.Value(n), true
+}
+
+func (a nodes[K, V, M]) Size() int {
+	return a.tree.Size()
+}
+
+func (a nodes[K, V, M]) Search(key K) (V, bool) {
+	_, v, found := a.entry(a.tree.Search(key))
+	return v, found
+}
+
+func (a nodes[K, V, M]) Insert(key K, value V) bool {
+	_, inserted := a.tree.Insert(key, value)
+	return inserted
+}
+
+func (a nodes[K, V, M]) Delete(key K) bool {
+	n, found := a.tree.Search(key)
+	return found && a.tree.Delete(n)
+}
+
+func (a nodes[K, V, M]) Min() (K, V, bool) {
+	return a.entry(a.tree.Min(a.tree.Root()), true)
+}
+
+func (a nodes[K, V, M]) Max() (K, V, bool) {
+	return a.entry(a.tree.Max(a.tree.Root()), true)
+}
+
+func (a nodes[K, V, M]) Floor(key K) (K, V, bool) {
+	return a.entry(a.tree.Floor(key))
+}
+
+func (a nodes[K, V, M]) Ceiling(key K) (K, V, bool) {
+	return a.entry(a.tree.Ceiling(key))
+}
+
+func (a nodes[K, V, M]) Ascend(f func(key K, value V) bool) {
+	for n := a.tree.Min(a.tree.Root()); !a.tree.IsNil(n); n = a.tree.Successor(n) {
+		if !f(a.tree.Key(n), a.tree.Value(n)) {
+			return
+		}
+	}
+}
+
+func (a nodes[K, V, M]) AscendRange(lo, hi K, f func(key K, value V) bool) {
+	// the subtree sizes give the number of keys in [lo, hi), following the first key at or after lo
+	count := a.tree.Rank(hi) - a.tree.Rank(lo)
+	n, _ := a.tree.Ceiling(lo)
+	for ; count > 0; count-- {
+		if !f(a.tree.Key(n), a.tree.Value(n)) {
+			return
+		}
+		n = a.tree.Successor(n)
+	}
+}
+
+// IsTreeValid checks the properties of the adapted tree, if it has an IsTreeValid method.
+//
+// Returns:
+//   - nil if the tree is valid, or cannot be checked.
+//   - An error describing the first violation found otherwise.
+func (a nodes[K, V, M]) IsTreeValid() error {
+	if v, ok := a.tree.(interface{ IsTreeValid() error }); ok {
+		return v.IsTreeValid()
+	}
+	return nil
+}
+
+func (a nodes[K, V, M]) Descend(f func(key K, value V) bool) {
+	for n := a.tree.Max(a.tree.Root()); !a.tree.IsNil(n); n = a.tree.Predecessor(n) {
+		if !f(a.tree.Key(n), a.tree.Value(n)) {
+			return
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package trees_test
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"github.com/mikenye/gotrees/btree"
+	"github.com/mikenye/gotrees/indextree"
+	"github.com/mikenye/gotrees/llrb"
+	"github.com/mikenye/gotrees/rbtree"
+	"github.com/mikenye/gotrees/scapegoat"
+	"github.com/mikenye/gotrees/skiplist"
+	"github.com/mikenye/gotrees/trees"
+	"github.com/mikenye/gotrees/trees/treetest"
+	"github.com/mikenye/gotrees/ziptree"
+	"testing"
+)
+
+// the key-based containers implement Sorted directly
+var (
+	_ trees.Sorted[int, int] = (*btree.Tree[int, int])(nil)
+	_ trees.Sorted[int, int] = (*indextree.Tree[int, int])(nil)
+	_ trees.Sorted[int, int] = (*llrb.Tree[int, int])(nil)
+	_ trees.Sorted[int, int] = (*llrb.Concurrent[int, int])(nil)
+	_ trees.Sorted[int, int] = (*skiplist.List[int, int])(nil)
+)
+
+func intLess(a, b int) bool { return a < b }
+
+// implementations returns a constructor for each implementation of Sorted.
+func implementations() map[string]func() trees.Sorted[int, int] {
+	return map[string]func() trees.Sorted[int, int]{
+		"bst":        func() trees.Sorted[int, int] { return trees.FromBST(bst.New[int, int, struct{}](intLess)) },
+		"btree":      func() trees.Sorted[int, int] { return btree.New[int, int](intLess, 3) },
+		"indextree":  func() trees.Sorted[int, int] { return indextree.New[int, int](intLess) },
+		"llrb":       func() trees.Sorted[int, int] { return llrb.New[int, int](intLess) },
+		"concurrent": func() trees.Sorted[int, int] { return llrb.NewConcurrent[int, int](intLess) },
+		"rbtree":     func() trees.Sorted[int, int] { return trees.FromRBTree(rbtree.New[int, int](intLess)) },
+		"rbtree/lazy": func() trees.Sorted[int, int] {
+			t := rbtree.New[int, int](intLess)
+			t.SetLazyDeletion(1) // keep every tombstone until compacted
+			return trees.FromRBTree(t)
+		},
+		"scapegoat": func() trees.Sorted[int, int] { return trees.FromNodes(scapegoat.New[int, int](intLess, 0.7)) },
+		"skiplist":  func() trees.Sorted[int, int] { return skiplist.New[int, int](intLess) },
+		"ziptree":   func() trees.Sorted[int, int] { return trees.FromNodes(ziptree.New[int, int](intLess)) },
+	}
+}
+
+func TestSorted(t *testing.T) {
+	for name, newSorted := range implementations() {
+		t.Run(name, func(t *testing.T) {
+			treetest.Run(t, newSorted)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package treesql_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/rbtree"
+	"github.com/mikenye/gotrees/trees/treesql"
+)
+
+func ExampleColumn() {
+
+	// the column value passed to the driver, e.g. db.Exec("UPDATE players SET scores = $1", treesql.Column(scores))
+	scores := rbtree.New[int, float64](func(a, b int) bool { return a < b })
+	scores.Insert(1, 98.5)
+	scores.Insert(2, 87.0)
+	value, err := treesql.Column(scores).Value()
+	if err != nil {
+		panic(err)
+	}
+
+	// scanning the column back, e.g. db.QueryRow("SELECT scores FROM players").Scan(treesql.Column(loaded))
+	loaded := rbtree.New[int, float64](func(a, b int) bool { return a < b })
+	if err := treesql.Column(loaded).Scan(value); err != nil {
+		panic(err)
+	}
+	for day, score := range loaded.All() {
+		fmt.Println(day, score)
+	}
+
+	// Output:
+	// 1 98.5
+	// 2 87
+}
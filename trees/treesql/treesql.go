@@ -0,0 +1,95 @@
+// Package treesql stores trees in database columns, implementing sql.Scanner and driver.Valuer with the
+// binary encoding of the trees (see bst.Tree.MarshalBinary), for use with any database/sql driver.
+//
+// Column wraps any tree implementing encoding.BinaryMarshaler and encoding.BinaryUnmarshaler, such as
+// bst.Tree, rbtree.Tree, scapegoat.Tree and ziptree.Tree, to be passed as an argument to sql.DB.Exec,
+// or as a destination to sql.Row.Scan. Trees are stored as binary large objects, e.g. in a bytea column
+// in PostgreSQL or a BLOB column in MySQL and SQLite, and suit small trees stored in each row.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/trees/treesql"
+//
+//	// store a history of scores keyed by time
+//	scores := rbtree.New[time.Time, float64](func(a, b time.Time) bool { return a.Before(b) })
+//	scores.Insert(time.Now(), 98.5)
+//	_, err := db.Exec("UPDATE players SET scores = $1 WHERE id = $2", treesql.Column(scores), id)
+//
+//	// load it back, into a tree created with the same LessFunc
+//	loaded := rbtree.New[time.Time, float64](func(a, b time.Time) bool { return a.Before(b) })
+//	err = db.QueryRow("SELECT scores FROM players WHERE id = $1", id).Scan(treesql.Column(loaded))
+//
+// # Limitations
+//
+// Keys, values and metadata must be encodable by encoding/gob. The LessFunc and options of a tree are
+// not stored, so a scanned tree must be created beforehand. Trees are read and written whole: storing
+// a large or frequently updated tree as a blob rewrites the whole tree on every update.
+package treesql
+
+import (
+	"database/sql/driver"
+	"encoding"
+	"fmt"
+)
+
+// Tree is implemented by the trees that can be stored in a column, such as *bst.Tree and *rbtree.Tree.
+type Tree interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// Blob stores a tree in a column, and implements sql.Scanner and driver.Valuer. See Column.
+type Blob[T Tree] struct {
+	Tree T    // Tree read or written
+	Null bool // Whether the column is NULL, rather than holding Tree
+}
+
+// Column returns a Blob storing tree in a column, to pass as an argument to sql.DB.Exec, or as a
+// destination to sql.Row.Scan.
+//
+// Parameters:
+//   - tree: The tree to store, or to restore from the column, replacing its contents. A tree to restore
+//     must have been created with the LessFunc of the stored tree.
+//
+// Returns:
+//   - A pointer to a Blob wrapping tree, which is not NULL.
+func Column[T Tree](tree T) *Blob[T] {
+	return &Blob[T]{Tree: tree}
+}
+
+// Scan implements sql.Scanner, restoring the tree from the binary encoding stored in the column
+// (see bst.Tree.UnmarshalBinary).
+//
+// If the column is NULL, Null is set and the tree is left unchanged.
+//
+// Returns:
+//   - nil if the tree was restored, or the column is NULL.
+//   - An error if the column does not hold a valid encoding of a tree. The tree is then left unchanged.
+func (b *Blob[T]) Scan(src any) error {
+	var data []byte
+	switch src := src.(type) {
+	case nil:
+		b.Null = true
+		return nil
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	default:
+		return fmt.Errorf("cannot scan %T into a tree", src)
+	}
+	if err := b.Tree.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	b.Null = false
+	return nil
+}
+
+// Value implements driver.Valuer, returning the binary encoding of the tree (see bst.Tree.MarshalBinary),
+// or nil if Null is set.
+func (b *Blob[T]) Value() (driver.Value, error) {
+	if b.Null {
+		return nil, nil
+	}
+	return b.Tree.MarshalBinary()
+}
@@ -0,0 +1,81 @@
+package treesql
+
+import (
+	"database/sql/driver"
+	"github.com/mikenye/gotrees/bst"
+	"github.com/mikenye/gotrees/rbtree"
+	"github.com/mikenye/gotrees/scapegoat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func less(a, b int) bool { return a < b }
+
+func TestBlob(t *testing.T) {
+	tree := rbtree.New[int, string](less)
+	for i := range 10 {
+		tree.Insert(i, "v")
+	}
+
+	value, err := Column(tree).Value()
+	require.NoError(t, err)
+	assert.True(t, driver.IsValue(value), "expected a valid driver value")
+	assert.IsType(t, []byte(nil), value)
+
+	restored := rbtree.New[int, string](less)
+	restored.Insert(100, "stale")
+	blob := Column(restored)
+	require.NoError(t, blob.Scan(value))
+	assert.False(t, blob.Null)
+	require.NoError(t, restored.IsTreeValid())
+	assert.Equal(t, slices.Collect(tree.Keys()), slices.Collect(restored.Keys()), "expected the contents to be replaced")
+
+	// some drivers return text columns as strings
+	restored = rbtree.New[int, string](less)
+	require.NoError(t, Column(restored).Scan(string(value.([]byte))))
+	assert.Equal(t, 10, restored.Size())
+}
+
+func TestBlob_extensions(t *testing.T) {
+	tree := scapegoat.New[int, int](less, scapegoat.DefaultAlpha)
+	for i := range 100 {
+		tree.Insert(i, i*i)
+	}
+	value, err := Column(tree).Value()
+	require.NoError(t, err)
+
+	restored := bst.New[int, int, struct{}](less)
+	require.NoError(t, Column(restored).Scan(value))
+	assert.Equal(t, maps.Collect(tree.All()), maps.Collect(restored.All()))
+}
+
+func TestBlob_null(t *testing.T) {
+	tree := bst.New[int, string, struct{}](less)
+	tree.Insert(1, "one")
+
+	blob := Column(tree)
+	require.NoError(t, blob.Scan(nil))
+	assert.True(t, blob.Null, "expected a NULL column to be recorded")
+	assert.Equal(t, 1, tree.Size(), "expected the tree to be left unchanged")
+
+	value, err := blob.Value()
+	require.NoError(t, err)
+	assert.Nil(t, value, "expected NULL to be written")
+
+	data, err := tree.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, blob.Scan(data))
+	assert.False(t, blob.Null, "expected a scanned tree not to be NULL")
+}
+
+func TestBlob_invalid(t *testing.T) {
+	tree := bst.New[int, string, struct{}](less)
+	tree.Insert(1, "one")
+
+	assert.Error(t, Column(tree).Scan(int64(1)), "expected an error scanning an integer")
+	assert.Error(t, Column(tree).Scan([]byte("not a tree")), "expected an error scanning invalid data")
+	assert.Equal(t, 1, tree.Size(), "expected the tree to be left unchanged")
+}
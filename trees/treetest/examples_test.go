@@ -0,0 +1,37 @@
+package treetest_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/mikenye/gotrees/rbtree"
+	"github.com/mikenye/gotrees/trees"
+	"github.com/mikenye/gotrees/trees/treetest"
+)
+
+// forgetful is a broken tree, which ignores insertions of the key 13.
+type forgetful struct {
+	trees.Sorted[int, int]
+}
+
+func (f forgetful) Insert(key, value int) bool {
+	if key == 13 {
+		_, found := f.Search(key)
+		return !found
+	}
+	return f.Sorted.Insert(key, value)
+}
+
+func ExampleStress() {
+	tree := forgetful{trees.FromRBTree(rbtree.New[int, int](func(a, b int) bool { return a < b }))}
+
+	err := treetest.Stress(tree, 1000, 1)
+	var d *treetest.Divergence
+	if errors.As(err, &d) {
+		fmt.Println(len(d.Log), "operations, ending with", d.Log[len(d.Log)-1])
+		fmt.Println(d.Err)
+	}
+
+	// Output:
+	// 389 operations, ending with Insert(13, 389)
+	// Size() returned 50, expected 51
+}
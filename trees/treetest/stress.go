@@ -0,0 +1,135 @@
+package treetest
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/trees"
+	"math/rand"
+	"strings"
+)
+
+// OpKind identifies the kind of an operation applied by Stress.
+type OpKind int
+
+const (
+	OpInsert OpKind = iota // Tree.Insert(Key, Value)
+	OpDelete               // Tree.Delete(Key)
+	OpSearch               // Tree.Search(Key)
+)
+
+// Op is an operation applied to a tree by Stress.
+type Op struct {
+	Kind  OpKind
+	Key   int
+	Value int // Value inserted, for OpInsert: the position of the operation in the log, counting from 1
+}
+
+// String returns the operation as a method call, such as "Insert(3, 7)".
+func (o Op) String() string {
+	switch o.Kind {
+	case OpInsert:
+		return fmt.Sprintf("Insert(%d, %d)", o.Key, o.Value)
+	case OpDelete:
+		return fmt.Sprintf("Delete(%d)", o.Key)
+	case OpSearch:
+		return fmt.Sprintf("Search(%d)", o.Key)
+	}
+	return fmt.Sprintf("Op(%d)", o.Kind)
+}
+
+// Divergence is the error returned by Stress when a tree first disagrees with the oracle.
+type Divergence struct {
+	Log []Op  // Operations applied, the last one being the one after which the tree diverged
+	Err error // Description of the divergence
+}
+
+// divergenceLogLines is the number of operations listed by Divergence.Error.
+const divergenceLogLines = 20
+
+// Error describes the divergence, followed by the last operations of the log.
+// The complete log is available in Divergence.Log.
+func (d *Divergence) Error() string {
+	builder := strings.Builder{}
+	fmt.Fprintf(&builder, "tree diverged from the oracle after operation %d: %v\noperation log:", len(d.Log), d.Err)
+	start := max(len(d.Log)-divergenceLogLines, 0)
+	if start > 0 {
+		fmt.Fprintf(&builder, "\n\t... %d earlier operations", start)
+	}
+	for i := start; i < len(d.Log); i++ {
+		fmt.Fprintf(&builder, "\n\t%d: %v", i+1, d.Log[i])
+	}
+	return builder.String()
+}
+
+// Unwrap returns the description of the divergence.
+func (d *Divergence) Unwrap() error {
+	return d.Err
+}
+
+// Stress applies a random sequence of insertions, deletions and searches to tree, comparing
+// every result against an oracle (a map and a sorted slice of its keys). The results of
+// each operation and the size of the tree are compared after every operation, and every query
+// of the tree (including IsTreeValid, if it has one) after every batch of operations.
+//
+// Keys are drawn from a range small enough that keys are frequently updated and deleted.
+// The same seed always produces the same sequence of operations, so divergences can be replayed.
+//
+// Parameters:
+//   - tree: The tree to test, which must be empty.
+//   - ops: The number of operations to apply.
+//   - seed: The seed of the random sequence of operations.
+//
+// Returns:
+//   - nil if the tree agreed with the oracle throughout.
+//   - A *Divergence holding the operation log if it did not.
+func Stress(tree trees.Sorted[int, int], ops int, seed int64) error {
+	rng := rand.New(rand.NewSource(seed))
+	m := newModel()
+	keys := ops/10 + 10
+	log := make([]Op, 0, ops)
+
+	diverged := func(format string, args ...any) error {
+		return &Divergence{Log: log, Err: fmt.Errorf(format, args...)}
+	}
+	if tree.Size() != 0 {
+		return diverged("tree is not empty, it has size %d", tree.Size())
+	}
+
+	for i := 0; i < ops; i++ {
+		op := Op{Key: rng.Intn(keys)}
+		switch r := rng.Intn(5); {
+		case r < 2:
+			op.Kind, op.Value = OpInsert, i+1
+		case r < 4:
+			op.Kind = OpDelete
+		default:
+			op.Kind = OpSearch
+		}
+		log = append(log, op)
+
+		switch op.Kind {
+		case OpInsert:
+			if got, want := tree.Insert(op.Key, op.Value), m.insert(op.Key, op.Value); got != want {
+				return diverged("Insert returned %v, expected %v", got, want)
+			}
+		case OpDelete:
+			if got, want := tree.Delete(op.Key), m.delete(op.Key); got != want {
+				return diverged("Delete returned %v, expected %v", got, want)
+			}
+		case OpSearch:
+			value, found := tree.Search(op.Key)
+			expected, exists := m.values[op.Key]
+			if found != exists || value != expected {
+				return diverged("Search returned (%d, %v), expected (%d, %v)", value, found, expected, exists)
+			}
+		}
+		if tree.Size() != len(m.keys) {
+			return diverged("Size() returned %d, expected %d", tree.Size(), len(m.keys))
+		}
+		if i%checkEvery == 0 || i == ops-1 {
+			if err := checkTree(tree, m, rng); err != nil {
+				return &Divergence{Log: log, Err: err}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,267 @@
+// Package treetest implements a conformance test suite for implementations of trees.Sorted.
+//
+// Run exercises a tree with property tests: keys are visited in order, sizes stay consistent,
+// iteration visits every key exactly once and stops when asked to, and lookups agree with a
+// reference model, through sequential and random sequences of insertions and deletions.
+//
+// Stress runs a random sequence of operations against a tree and a reference oracle, returning
+// the first divergence along with the log of operations leading to it. It can be used on its
+// own, e.g. to stress a tree with many seeds.
+//
+// If the tree has an IsTreeValid() error method, as the trees of this module do, it is called
+// after every batch of operations, so that implementations extending bst.Tree (such as a new
+// self-balancing tree) can validate their fixup logic.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/trees/treetest"
+//
+//	func TestConformance(t *testing.T) {
+//		treetest.Run(t, func() trees.Sorted[int, int] {
+//			return trees.FromNodes(avl.New[int, int](func(a, b int) bool { return a < b }))
+//		})
+//	}
+package treetest
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/trees"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// checkEvery is the number of operations between full checks of a tree against the model.
+const checkEvery = 50
+
+// validator is implemented by trees that can check their own invariants.
+type validator interface {
+	IsTreeValid() error
+}
+
+// Run runs the conformance test suite against the trees returned by newTree,
+// each test starting from a new, empty tree. Trees are keyed by int.
+func Run(t *testing.T, newTree func() trees.Sorted[int, int]) {
+	t.Helper()
+	t.Run("Empty", func(t *testing.T) {
+		testEmpty(t, newTree())
+	})
+	t.Run("Ascending", func(t *testing.T) {
+		testSequence(t, newTree(), sequence(1000, func(i int) int { return i }))
+	})
+	t.Run("Descending", func(t *testing.T) {
+		testSequence(t, newTree(), sequence(1000, func(i int) int { return -i }))
+	})
+	t.Run("Random", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1))
+		testSequence(t, newTree(), sequence(1000, func(int) int { return rng.Intn(10000) }))
+	})
+	t.Run("RandomOperations", func(t *testing.T) {
+		if err := Stress(newTree(), 5000, 1); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+// sequence returns the keys f(0), f(1), ..., f(n-1).
+func sequence(n int, f func(i int) int) []int {
+	keys := make([]int, n)
+	for i := range keys {
+		keys[i] = f(i)
+	}
+	return keys
+}
+
+// model is a reference implementation of a sorted map, against which trees are checked.
+type model struct {
+	values map[int]int
+	keys   []int // keys of values, in ascending order
+}
+
+func newModel() *model {
+	return &model{values: make(map[int]int)}
+}
+
+func (m *model) insert(key, value int) bool {
+	_, exists := m.values[key]
+	m.values[key] = value
+	if !exists {
+		i, _ := slices.BinarySearch(m.keys, key)
+		m.keys = slices.Insert(m.keys, i, key)
+	}
+	return !exists
+}
+
+func (m *model) delete(key int) bool {
+	i, exists := slices.BinarySearch(m.keys, key)
+	if exists {
+		m.keys = slices.Delete(m.keys, i, i+1)
+		delete(m.values, key)
+	}
+	return exists
+}
+
+func testEmpty(t *testing.T, tree trees.Sorted[int, int]) {
+	check(t, tree, newModel(), rand.New(rand.NewSource(1)))
+	if tree.Delete(0) {
+		t.Errorf("Delete(0) on an empty tree returned true")
+	}
+}
+
+// testSequence inserts keys into tree, then deletes them all in the order they were inserted.
+func testSequence(t *testing.T, tree trees.Sorted[int, int], keys []int) {
+	m := newModel()
+	rng := rand.New(rand.NewSource(1))
+	for i, key := range keys {
+		if got, want := tree.Insert(key, i), m.insert(key, i); got != want {
+			t.Fatalf("Insert(%d) returned %v, expected %v", key, got, want)
+		}
+		checkSize(t, tree, m)
+		if i%checkEvery == 0 {
+			check(t, tree, m, rng)
+		}
+	}
+	check(t, tree, m, rng)
+
+	for i, key := range keys {
+		if got, want := tree.Delete(key), m.delete(key); got != want {
+			t.Fatalf("Delete(%d) returned %v, expected %v", key, got, want)
+		}
+		checkSize(t, tree, m)
+		if i%checkEvery == 0 {
+			check(t, tree, m, rng)
+		}
+	}
+	check(t, tree, m, rng)
+}
+
+func checkSize(t *testing.T, tree trees.Sorted[int, int], m *model) {
+	t.Helper()
+	if tree.Size() != len(m.keys) {
+		t.Fatalf("Size() returned %d, expected %d", tree.Size(), len(m.keys))
+	}
+}
+
+// check compares every query of tree against m, failing the test at the first difference.
+func check(t *testing.T, tree trees.Sorted[int, int], m *model, rng *rand.Rand) {
+	t.Helper()
+	if err := checkTree(tree, m, rng); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkTree compares every query of tree against m, and returns an error describing the first difference.
+func checkTree(tree trees.Sorted[int, int], m *model, rng *rand.Rand) error {
+	if v, ok := tree.(validator); ok {
+		if err := v.IsTreeValid(); err != nil {
+			return fmt.Errorf("IsTreeValid: %w", err)
+		}
+	}
+	if tree.Size() != len(m.keys) {
+		return fmt.Errorf("Size() returned %d, expected %d", tree.Size(), len(m.keys))
+	}
+
+	// ordering and completeness of iteration, and early termination
+	if err := checkKeys("Ascend", collect(tree.Ascend, -1), m.keys); err != nil {
+		return err
+	}
+	descending := slices.Clone(m.keys)
+	slices.Reverse(descending)
+	if err := checkKeys("Descend", collect(tree.Descend, -1), descending); err != nil {
+		return err
+	}
+	if limit := len(m.keys) / 2; limit > 0 {
+		if err := checkKeys("Ascend (stopped early)", collect(tree.Ascend, limit), m.keys[:limit]); err != nil {
+			return err
+		}
+		if err := checkKeys("Descend (stopped early)", collect(tree.Descend, limit), descending[:limit]); err != nil {
+			return err
+		}
+	}
+
+	// extremes
+	for _, q := range []struct {
+		name string
+		get  func() (int, int, bool)
+		i    int
+	}{{"Min", tree.Min, 0}, {"Max", tree.Max, len(m.keys) - 1}} {
+		if err := checkEntry(q.name+"()", m, q.i, q.get); err != nil {
+			return err
+		}
+	}
+
+	// point queries, around and between the keys of the model
+	lo, hi := -10, 10
+	if len(m.keys) > 0 {
+		lo, hi = m.keys[0]-10, m.keys[len(m.keys)-1]+10
+	}
+	for range 20 {
+		key := lo + rng.Intn(hi-lo)
+		value, found := tree.Search(key)
+		expected, exists := m.values[key]
+		if found != exists || value != expected {
+			return fmt.Errorf("Search(%d) returned (%d, %v), expected (%d, %v)", key, value, found, expected, exists)
+		}
+
+		i, exact := slices.BinarySearch(m.keys, key)
+		if err := checkEntry(fmt.Sprintf("Ceiling(%d)", key), m, i, func() (int, int, bool) { return tree.Ceiling(key) }); err != nil {
+			return err
+		}
+		if !exact {
+			i--
+		}
+		if err := checkEntry(fmt.Sprintf("Floor(%d)", key), m, i, func() (int, int, bool) { return tree.Floor(key) }); err != nil {
+			return err
+		}
+
+		// ranges, including empty and inverted ones
+		end := key + rng.Intn(100) - 10
+		var expectedKeys []int
+		for _, k := range m.keys {
+			if key <= k && k < end {
+				expectedKeys = append(expectedKeys, k)
+			}
+		}
+		rangeKeys := collect(func(f func(key, value int) bool) { tree.AscendRange(key, end, f) }, -1)
+		if err := checkKeys(fmt.Sprintf("AscendRange(%d, %d)", key, end), rangeKeys, expectedKeys); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collect returns the keys visited by an iteration method of a tree, stopping after limit keys
+// (if limit is not negative).
+func collect(iterate func(f func(key, value int) bool), limit int) []int {
+	var keys []int
+	iterate(func(key, value int) bool {
+		keys = append(keys, key)
+		return limit < 0 || len(keys) < limit
+	})
+	return keys
+}
+
+// checkKeys returns an error if the keys visited by an iteration differ from the expected keys.
+func checkKeys(name string, keys, expected []int) error {
+	if !slices.Equal(keys, expected) {
+		return fmt.Errorf("%s visited keys %v, expected %v", name, keys, expected)
+	}
+	return nil
+}
+
+// checkEntry returns an error if the entry returned by a query differs from the i-th entry of m,
+// or if the query found an entry and i is out of range.
+func checkEntry(name string, m *model, i int, query func() (int, int, bool)) error {
+	key, value, found := query()
+	if i < 0 || i >= len(m.keys) {
+		if found {
+			return fmt.Errorf("%s returned (%d, %d, true), expected no entry", name, key, value)
+		}
+		return nil
+	}
+	expectedKey := m.keys[i]
+	if !found || key != expectedKey || value != m.values[expectedKey] {
+		return fmt.Errorf("%s returned (%d, %d, %v), expected (%d, %d, true)", name, key, value, found, expectedKey, m.values[expectedKey])
+	}
+	return nil
+}
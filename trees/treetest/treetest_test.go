@@ -0,0 +1,130 @@
+package treetest
+
+import (
+	"errors"
+	"github.com/mikenye/gotrees/btree"
+	"github.com/mikenye/gotrees/trees"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+func newBTree() trees.Sorted[int, int] {
+	return btree.New[int, int](intLess, 2)
+}
+
+func TestRun(t *testing.T) {
+	Run(t, newBTree)
+}
+
+// brokenFloor returns the wrong entry from Floor for keys that are not in the tree.
+type brokenFloor struct {
+	trees.Sorted[int, int]
+}
+
+func (b brokenFloor) Floor(key int) (int, int, bool) {
+	if _, found := b.Search(key); !found {
+		return b.Ceiling(key)
+	}
+	return b.Sorted.Floor(key)
+}
+
+// brokenDescend stops one key early.
+type brokenDescend struct {
+	trees.Sorted[int, int]
+}
+
+func (b brokenDescend) Descend(f func(key, value int) bool) {
+	n := 0
+	b.Sorted.Descend(func(key, value int) bool {
+		n++
+		return n < b.Size() && f(key, value)
+	})
+}
+
+// invalid reports a broken invariant.
+type invalid struct {
+	trees.Sorted[int, int]
+}
+
+func (invalid) IsTreeValid() error {
+	return errors.New("broken invariant")
+}
+
+func TestCheckTree(t *testing.T) {
+	m := newModel()
+	tree := newBTree()
+	for i := 0; i < 100; i += 2 {
+		tree.Insert(i, i*i)
+		m.insert(i, i*i)
+	}
+	rng := rand.New(rand.NewSource(1))
+	assert.NoError(t, checkTree(tree, m, rng))
+
+	assert.ErrorContains(t, checkTree(brokenFloor{tree}, m, rng), "Floor")
+	assert.ErrorContains(t, checkTree(brokenDescend{tree}, m, rng), "Descend")
+	assert.ErrorContains(t, checkTree(invalid{tree}, m, rng), "broken invariant")
+
+	// a value differing from the model is reported by the point queries
+	tree.Insert(50, 0)
+	err := checkTree(tree, m, rng)
+	for i := 0; err == nil && i < 100; i++ {
+		err = checkTree(tree, m, rng)
+	}
+	assert.Error(t, err)
+}
+
+// brokenDelete fails to delete keys divisible by 7.
+type brokenDelete struct {
+	trees.Sorted[int, int]
+}
+
+func (b brokenDelete) Delete(key int) bool {
+	if key%7 == 0 {
+		_, found := b.Search(key)
+		return found
+	}
+	return b.Sorted.Delete(key)
+}
+
+func TestStress(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		assert.NoError(t, Stress(newBTree(), 2000, seed))
+	}
+
+	err := Stress(brokenDelete{newBTree()}, 2000, 1)
+	var d *Divergence
+	require.ErrorAs(t, err, &d)
+	require.NotEmpty(t, d.Log)
+	assert.LessOrEqual(t, len(d.Log), 2000)
+	last := d.Log[len(d.Log)-1]
+	assert.Contains(t, err.Error(), last.String())
+
+	// the log replays the divergence
+	tree := brokenDelete{newBTree()}
+	m := newModel()
+	for _, op := range d.Log {
+		switch op.Kind {
+		case OpInsert:
+			tree.Insert(op.Key, op.Value)
+			m.insert(op.Key, op.Value)
+		case OpDelete:
+			tree.Delete(op.Key)
+			m.delete(op.Key)
+		}
+	}
+	assert.Error(t, checkTree(tree, m, rand.New(rand.NewSource(1))))
+
+	tree = brokenDelete{newBTree()}
+	tree.Insert(1, 1)
+	assert.ErrorContains(t, Stress(tree, 10, 1), "not empty")
+}
+
+func TestOp_String(t *testing.T) {
+	assert.Equal(t, "Insert(3, 7)", Op{OpInsert, 3, 7}.String())
+	assert.Equal(t, "Delete(3)", Op{Kind: OpDelete, Key: 3}.String())
+	assert.Equal(t, "Search(3)", Op{Kind: OpSearch, Key: 3}.String())
+}
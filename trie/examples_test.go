@@ -0,0 +1,27 @@
+package trie_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/trie"
+)
+
+func ExampleTrie_CountPrefix() {
+
+	// create a trie of token sequences
+	tokens := trie.New[uint16, string]()
+	tokens.Insert([]uint16{101, 7, 42}, "first")
+	tokens.Insert([]uint16{101, 7, 43}, "second")
+	tokens.Insert([]uint16{101, 8}, "third")
+
+	fmt.Println("Sequences starting with [101]:", tokens.CountPrefix([]uint16{101}))
+	fmt.Println("Sequences starting with [101 7]:", tokens.CountPrefix([]uint16{101, 7}))
+
+	// remove every sequence starting with [101 7]
+	tokens.DeletePrefix([]uint16{101, 7})
+	fmt.Println("Sequences remaining:", tokens.Size())
+
+	// Output:
+	// Sequences starting with [101]: 3
+	// Sequences starting with [101 7]: 2
+	// Sequences remaining: 1
+}
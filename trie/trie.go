@@ -0,0 +1,226 @@
+// Package trie provides a generic trie (prefix tree) keyed by slices of comparable elements.
+//
+// Unlike string tries, keys can be sequences of any comparable type: bytes, runes,
+// token IDs ([]uint16), path segments ([]string), and so on. Each node has one child
+// per distinct next element, so lookups take O(k) time for a key of length k.
+//
+// Besides exact lookups, the trie supports prefix queries:
+//   - Trie.WalkPrefix visits every key starting with a prefix.
+//   - Trie.CountPrefix counts the keys starting with a prefix, in O(k) time.
+//   - Trie.LongestPrefix finds the longest key that is a prefix of a given sequence.
+//   - Trie.DeletePrefix removes every key starting with a prefix, in O(k) time.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/trie"
+//
+//	t := trie.New[uint16, string]()
+//	t.Insert([]uint16{12, 7, 99}, "a")
+//	t.Insert([]uint16{12, 7}, "b")
+//	n := t.CountPrefix([]uint16{12}) // 2
+//
+// # Limitations
+//
+// Children are held in maps, so keys are visited in an unspecified order.
+// The trie is not safe for concurrent use.
+package trie
+
+import "slices"
+
+// Trie represents a trie mapping keys of type []E to values of type V.
+//
+// The zero value is not usable; tries must be created with New.
+type Trie[E comparable, V any] struct {
+	root *node[E, V] // Root node, holding the empty key.
+}
+
+// node represents a single node of the trie.
+//
+// The key of a node is the sequence of elements on the path from the root.
+// Only nodes with leaf set hold a value.
+type node[E comparable, V any] struct {
+	children map[E]*node[E, V]
+	leaf     bool
+	value    V
+	count    int // number of keys in the subtree rooted at this node
+}
+
+// New creates and returns a new empty trie.
+func New[E comparable, V any]() *Trie[E, V] {
+	return &Trie[E, V]{root: &node[E, V]{}}
+}
+
+// Size returns the number of keys in the trie.
+//
+// This is an O(1) operation.
+func (t *Trie[E, V]) Size() int {
+	return t.root.count
+}
+
+// find returns the node for key, or nil if no key starts with key.
+func (t *Trie[E, V]) find(key []E) *node[E, V] {
+	n := t.root
+	for _, e := range key {
+		if n = n.children[e]; n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// Insert inserts the given key and value into the trie.
+//
+// If the key already exists, its value is updated. The key slice is not retained.
+//
+// Returns:
+//   - true if a new key was inserted.
+//   - false if the key existed and its value was updated.
+func (t *Trie[E, V]) Insert(key []E, value V) bool {
+	// if the key exists, only the value changes
+	if n := t.find(key); n != nil && n.leaf {
+		n.value = value
+		return false
+	}
+
+	n := t.root
+	n.count++
+	for _, e := range key {
+		child := n.children[e]
+		if child == nil {
+			if n.children == nil {
+				n.children = make(map[E]*node[E, V])
+			}
+			child = &node[E, V]{}
+			n.children[e] = child
+		}
+		n = child
+		n.count++
+	}
+	n.leaf, n.value = true, value
+	return true
+}
+
+// Search returns the value associated with key.
+//
+// Returns:
+//   - (value, true) if the key exists in the trie.
+//   - (zero value, false) if the key is not found.
+func (t *Trie[E, V]) Search(key []E) (V, bool) {
+	if n := t.find(key); n != nil && n.leaf {
+		return n.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete removes key from the trie, pruning nodes that no longer lead to any key.
+//
+// Returns:
+//   - true if the key was found and removed.
+//   - false if the key was not found.
+func (t *Trie[E, V]) Delete(key []E) bool {
+	if n := t.find(key); n == nil || !n.leaf {
+		return false
+	}
+	n := t.remove(key, 1)
+	var zero V
+	n.leaf, n.value = false, zero
+	return true
+}
+
+// DeletePrefix removes every key starting with prefix, in O(k) time for a prefix of length k.
+//
+// Returns:
+//   - The number of keys removed.
+func (t *Trie[E, V]) DeletePrefix(prefix []E) int {
+	n := t.find(prefix)
+	if n == nil || n.count == 0 {
+		return 0
+	}
+	count := n.count
+	n = t.remove(prefix, count)
+	var zero V
+	n.children, n.leaf, n.value = nil, false, zero
+	return count
+}
+
+// remove subtracts count keys from the nodes along the path to key, which must exist,
+// and detaches the first node left without keys. It returns the node for key.
+func (t *Trie[E, V]) remove(key []E, count int) *node[E, V] {
+	n := t.root
+	n.count -= count
+	for _, e := range key {
+		child := n.children[e]
+		child.count -= count
+		if child.count == 0 {
+			// nothing below child leads to a key any more
+			delete(n.children, e)
+		}
+		n = child
+	}
+	return n
+}
+
+// HasPrefix returns true if any key in the trie starts with prefix.
+func (t *Trie[E, V]) HasPrefix(prefix []E) bool {
+	n := t.find(prefix)
+	return n != nil && n.count > 0
+}
+
+// CountPrefix returns the number of keys starting with prefix, in O(k) time for a prefix of length k.
+func (t *Trie[E, V]) CountPrefix(prefix []E) int {
+	if n := t.find(prefix); n != nil {
+		return n.count
+	}
+	return 0
+}
+
+// LongestPrefix returns the length of the longest key in the trie that is a prefix of s, and its value.
+// The key itself is s[:length].
+//
+// Returns:
+//   - (length, value, true) if such a key exists.
+//   - (0, zero value, false) otherwise.
+func (t *Trie[E, V]) LongestPrefix(s []E) (int, V, bool) {
+	var match *node[E, V]
+	length := 0
+	n := t.root
+	for i := 0; n != nil; i++ {
+		if n.leaf {
+			match, length = n, i
+		}
+		if i == len(s) {
+			break
+		}
+		n = n.children[s[i]]
+	}
+	if match == nil {
+		var zero V
+		return 0, zero, false
+	}
+	return length, match.value, true
+}
+
+// WalkPrefix calls f for each key starting with prefix and its value, until f returns false.
+//
+// Keys are visited in an unspecified order, and each key passed to f is a new slice owned by the caller.
+// The trie must not be modified during iteration.
+func (t *Trie[E, V]) WalkPrefix(prefix []E, f func(key []E, value V) bool) {
+	if n := t.find(prefix); n != nil {
+		walk(n, slices.Clone(prefix), f)
+	}
+}
+
+// walk calls f for each key in the subtree rooted at n, whose key is key.
+// It returns false if iteration was stopped by f.
+func walk[E comparable, V any](n *node[E, V], key []E, f func(key []E, value V) bool) bool {
+	if n.leaf && !f(slices.Clone(key), n.value) {
+		return false
+	}
+	for e, child := range n.children {
+		if !walk(child, append(key, e), f) {
+			return false
+		}
+	}
+	return true
+}
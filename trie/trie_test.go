@@ -0,0 +1,166 @@
+package trie
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+)
+
+// checkTrie verifies the subtree counts of every node, and that no node is left without keys.
+func checkTrie[E comparable, V any](t *testing.T, tr *Trie[E, V]) {
+	t.Helper()
+	var check func(n *node[E, V]) int
+	check = func(n *node[E, V]) int {
+		count := 0
+		if n.leaf {
+			count++
+		}
+		for _, child := range n.children {
+			c := check(child)
+			require.Positive(t, c, "expected empty nodes to be pruned")
+			count += c
+		}
+		require.Equal(t, count, n.count, "inconsistent count")
+		return count
+	}
+	check(tr.root)
+}
+
+func randomKey(rng *rand.Rand) []uint16 {
+	key := make([]uint16, rng.Intn(5))
+	for i := range key {
+		key[i] = uint16(rng.Intn(3))
+	}
+	return key
+}
+
+func TestTrie_InsertSearchDelete(t *testing.T) {
+	tr := New[uint16, int]()
+	rng := rand.New(rand.NewSource(1))
+	expected := make(map[string]int)
+
+	for i := 0; i < 20000; i++ {
+		key := randomKey(rng)
+		k := fmt.Sprint(key)
+		_, exists := expected[k]
+		if rng.Intn(3) == 0 {
+			assert.Equal(t, exists, tr.Delete(key), "unexpected result deleting %v", key)
+			delete(expected, k)
+		} else {
+			assert.Equal(t, !exists, tr.Insert(key, i), "unexpected result inserting %v", key)
+			expected[k] = i
+		}
+		if i%500 == 0 {
+			checkTrie(t, tr)
+		}
+	}
+	checkTrie(t, tr)
+	assert.Equal(t, len(expected), tr.Size())
+
+	count := 0
+	tr.WalkPrefix(nil, func(key []uint16, value int) bool {
+		assert.Equal(t, expected[fmt.Sprint(key)], value)
+		v, found := tr.Search(key)
+		assert.True(t, found)
+		assert.Equal(t, value, v)
+		count++
+		return true
+	})
+	assert.Equal(t, len(expected), count)
+
+	_, found := tr.Search([]uint16{9})
+	assert.False(t, found)
+	assert.False(t, tr.Delete([]uint16{9}))
+}
+
+func TestTrie_prefixes(t *testing.T) {
+	tr := New[string, int]()
+	paths := [][]string{
+		{"usr"},
+		{"usr", "bin", "go"},
+		{"usr", "bin", "git"},
+		{"usr", "lib"},
+		{"etc", "hosts"},
+	}
+	for i, p := range paths {
+		tr.Insert(p, i)
+	}
+
+	assert.True(t, tr.HasPrefix([]string{"usr", "bin"}))
+	assert.False(t, tr.HasPrefix([]string{"var"}))
+	assert.Equal(t, 4, tr.CountPrefix([]string{"usr"}))
+	assert.Equal(t, 2, tr.CountPrefix([]string{"usr", "bin"}))
+	assert.Equal(t, 0, tr.CountPrefix([]string{"usr", "bin", "go", "x"}))
+	assert.Equal(t, 5, tr.CountPrefix(nil))
+
+	var got []string
+	tr.WalkPrefix([]string{"usr", "bin"}, func(key []string, _ int) bool {
+		got = append(got, fmt.Sprint(key))
+		return true
+	})
+	sort.Strings(got)
+	assert.Equal(t, []string{"[usr bin git]", "[usr bin go]"}, got)
+
+	got = nil
+	tr.WalkPrefix([]string{"usr"}, func(key []string, _ int) bool {
+		got = append(got, fmt.Sprint(key))
+		return false
+	})
+	assert.Len(t, got, 1, "expected walk to stop early")
+
+	// keys passed to f are owned by the caller
+	var keys [][]string
+	tr.WalkPrefix(nil, func(key []string, _ int) bool {
+		keys = append(keys, key)
+		return true
+	})
+	for _, key := range keys {
+		assert.True(t, slices.ContainsFunc(paths, func(p []string) bool { return slices.Equal(p, key) }), "unexpected key %v", key)
+	}
+
+	length, v, found := tr.LongestPrefix([]string{"usr", "bin", "go", "doc"})
+	assert.True(t, found)
+	assert.Equal(t, 3, length)
+	assert.Equal(t, 1, v)
+	length, v, found = tr.LongestPrefix([]string{"usr", "share"})
+	assert.True(t, found)
+	assert.Equal(t, 1, length)
+	assert.Equal(t, 0, v)
+	_, _, found = tr.LongestPrefix([]string{"etc"})
+	assert.False(t, found)
+
+	// subtree deletion
+	assert.Equal(t, 2, tr.DeletePrefix([]string{"usr", "bin"}))
+	checkTrie(t, tr)
+	assert.Equal(t, 3, tr.Size())
+	assert.False(t, tr.HasPrefix([]string{"usr", "bin"}))
+	_, found = tr.Search([]string{"usr"})
+	assert.True(t, found, "expected keys above the prefix to remain")
+	assert.Equal(t, 0, tr.DeletePrefix([]string{"usr", "bin"}))
+	assert.Equal(t, 0, tr.DeletePrefix([]string{"var"}))
+
+	assert.Equal(t, 3, tr.DeletePrefix(nil))
+	checkTrie(t, tr)
+	assert.Equal(t, 0, tr.Size())
+}
+
+func TestTrie_emptyKey(t *testing.T) {
+	tr := New[byte, string]()
+	assert.True(t, tr.Insert(nil, "empty"))
+	assert.False(t, tr.Insert([]byte{}, "updated"))
+	v, found := tr.Search(nil)
+	assert.True(t, found)
+	assert.Equal(t, "updated", v)
+
+	length, _, found := tr.LongestPrefix([]byte("abc"))
+	assert.True(t, found)
+	assert.Equal(t, 0, length)
+
+	assert.True(t, tr.Delete(nil))
+	assert.Equal(t, 0, tr.Size())
+	checkTrie(t, tr)
+}
@@ -0,0 +1,35 @@
+package ziptree_test
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/ziptree"
+)
+
+func ExampleTree_Delete() {
+
+	// create the tree with integer keys and string values
+	tree := ziptree.New[int, string](func(a, b int) bool {
+		return a < b
+	})
+
+	// insert some keys in the tree
+	for i, name := range []string{"zero", "one", "two", "three", "four"} {
+		tree.Insert(i, name)
+	}
+
+	// delete a key
+	if node, found := tree.Search(2); found {
+		tree.Delete(node)
+	}
+
+	// list the remaining keys
+	for n := tree.Min(tree.Root()); !tree.IsNil(n); n = tree.Successor(n) {
+		fmt.Printf("%d: %s\n", tree.Key(n), tree.Value(n))
+	}
+
+	// Output:
+	// 0: zero
+	// 1: one
+	// 3: three
+	// 4: four
+}
@@ -0,0 +1,502 @@
+// Package ziptree provides a generic, self-balancing Zip Tree implementation.
+//
+// A zip tree (Tarjan, Levy and Timmel, 2019) extends bst.Tree with a random rank per node,
+// stored in node metadata. Ranks are drawn from a geometric distribution, and the tree is
+// kept heap-ordered by rank: a node's rank is at least that of its children, and strictly
+// greater than that of its left child. This gives the tree the same shape distribution as a
+// skip list, with an expected depth of O(log n) for every node.
+//
+// Unlike treaps, zip trees never rotate. Instead:
+//   - An insertion walks down the search path until it reaches a node of lower rank, and
+//     "unzips" the path below it into the left and right subtrees of the new node.
+//   - A deletion "zips" the right spine of the node's left subtree and the left spine of its
+//     right subtree into a single path, which replaces the node.
+//
+// Each update relinks a single path of nodes, and handles to other nodes remain valid.
+//
+// # Usage Example
+//
+//	import "github.com/mikenye/gotrees/ziptree"
+//
+//	tree := ziptree.New[int, string](func(a, b int) bool { return a < b })
+//	tree.Insert(10, "ten")
+//	tree.Insert(20, "twenty")
+//	node, found := tree.Search(10)
+//
+//	if found {
+//		tree.Delete(node)
+//	}
+//
+// # Inherited Methods from bst.Tree
+//
+// Read-only methods inherited from bst.Tree (e.g., Search, Floor, Ceiling, Rank, Select,
+// TraverseInOrder, Successor and Predecessor) can be used safely. Methods that relink nodes or
+// change their ranks could violate the zip tree properties; they have been shadowed in ziptree,
+// and modified to panic if used:
+//
+//   - [bst.Tree.MustSetMetadata]: ❌ Do not use
+//   - [bst.Tree.RebuildSubtree]: ❌ Do not use
+//   - [bst.Tree.RotateLeft]: ❌ Do not use
+//   - [bst.Tree.RotateRight]: ❌ Do not use
+//   - [bst.Tree.SetHooks]: ❌ Do not use
+//   - [bst.Tree.SetKey]: ❌ Do not use
+//   - [bst.Tree.SetLeft]: ❌ Do not use
+//   - [bst.Tree.SetMetadata]: ❌ Do not use
+//   - [bst.Tree.SetParent]: ❌ Do not use
+//   - [bst.Tree.SetRight]: ❌ Do not use
+//   - [bst.Tree.SetRoot]: ❌ Do not use
+//   - [bst.Tree.Transplant]: ❌ Do not use
+package ziptree
+
+import (
+	"fmt"
+	"github.com/mikenye/gotrees/bst"
+	"math/bits"
+	"math/rand/v2"
+)
+
+// Tree represents a Zip Tree, an extension of bst.Tree that keeps itself balanced
+// by zipping and unzipping paths according to random node ranks.
+//
+// The tree embeds a generic Binary Search Tree bst.Tree, using each node's rank (uint8)
+// as metadata.
+type Tree[K, V any] struct {
+	*bst.Tree[K, V, uint8]                 // Underlying BST structure
+	less                   bst.LessFunc[K] // Function to compare keys and maintain order
+}
+
+// New creates a new Zip Tree with the given key comparison function.
+//
+// Parameters:
+//   - less: A comparison function (bst.LessFunc[K]) that defines the ordering of keys.
+//   - opts: Optional behaviors to enable on the underlying bst.Tree (see bst.Option).
+//
+// Returns:
+//   - A pointer to a newly created Tree[K, V] instance.
+//
+// New panics if opts include bst.WithAutoRebalance, as rebalancing would discard node ranks.
+func New[K, V any](less bst.LessFunc[K], opts ...bst.Option) *Tree[K, V] {
+	tree := bst.New[K, V, uint8](less, opts...)
+	if tree.AutoRebalance() != 0 {
+		panic("ziptree: bst.WithAutoRebalance cannot be used with a Zip Tree, which balances itself")
+	}
+	return &Tree[K, V]{
+		Tree: tree,
+		less: tree.Less, // honors bst.WithReverseOrder
+	}
+}
+
+// randomRank returns a rank drawn from a geometric distribution with mean 1:
+// rank r is returned with probability 1/2^(r+1).
+func randomRank() uint8 {
+	return uint8(bits.TrailingZeros64(rand.Uint64()))
+}
+
+// above returns true if node x belongs above node y: x has the higher rank, or the ranks
+// are equal and x has the smaller key.
+func (t *Tree[K, V]) above(x, y *bst.Node[K, V, uint8]) bool {
+	rx, ry := t.Metadata(x), t.Metadata(y)
+	return rx > ry || (rx == ry && t.less(t.Key(x), t.Key(y)))
+}
+
+// Insert inserts a new node with the given key and value into the tree, with a random rank.
+//
+// The search path is unzipped below the first node of lower rank, and the new node takes its place.
+// If a node with the same key already exists (and duplicate keys are not enabled), it is resolved by
+// the tree's duplicate policy (see bst.WithDuplicatePolicy): by default, its value is updated, and the
+// existing node is returned with false. Insert panics with an error wrapping bst.ErrDuplicateKey if the
+// key exists under the bst.DuplicateError policy.
+//
+// Returns:
+//   - (*bst.Node[K, V, uint8], true) if a new node was inserted.
+//   - (*bst.Node[K, V, uint8], false) if the key existed and the value was updated.
+func (t *Tree[K, V]) Insert(key K, value V) (*bst.Node[K, V, uint8], bool) {
+	x, inserted, err := t.InsertWithPolicy(key, value, t.DuplicatePolicy())
+	if err != nil {
+		panic(err)
+	}
+	return x, inserted
+}
+
+// TryInsert inserts a new node with the given key and value into the tree, as Tree.Insert does, but
+// returns an error rather than panicking if the key exists under the bst.DuplicateError policy.
+//
+// See bst.Tree.TryInsert.
+func (t *Tree[K, V]) TryInsert(key K, value V) (*bst.Node[K, V, uint8], bool, error) {
+	return t.InsertWithPolicy(key, value, t.DuplicatePolicy())
+}
+
+// InsertIfAbsent inserts a new node with the given key and value into the tree, only if the key is not
+// already in the tree, whatever the tree's duplicate policy.
+//
+// See bst.Tree.InsertIfAbsent.
+func (t *Tree[K, V]) InsertIfAbsent(key K, value V) (*bst.Node[K, V, uint8], bool) {
+	x, inserted, _ := t.InsertWithPolicy(key, value, bst.DuplicateIgnore)
+	return x, inserted
+}
+
+// InsertReturningOld inserts a new node with the given key and value into the tree, as Tree.Insert does,
+// and returns the value the key held before.
+//
+// See bst.Tree.InsertReturningOld.
+func (t *Tree[K, V]) InsertReturningOld(key K, value V) (V, bool) {
+	return bst.InsertReturningOldFunc(t.Tree, key, value, t.InsertWithPolicy)
+}
+
+// InsertWithPolicy inserts a new node with the given key and value into the tree, resolving an existing
+// equal key with policy rather than with the tree's duplicate policy, and rising the new node to its place according to a random rank.
+//
+// See bst.Tree.InsertWithPolicy.
+func (t *Tree[K, V]) InsertWithPolicy(key K, value V, policy bst.DuplicatePolicy) (*bst.Node[K, V, uint8], bool, error) {
+	x, inserted, err := t.Tree.InsertWithPolicy(key, value, policy)
+	if !inserted {
+		return x, false, err
+	}
+	t.Tree.SetMetadata(x, randomRank())
+	t.rise(x)
+	return x, true, nil
+}
+
+// rise moves node x, which has just been linked as a leaf at the end of its search path, up to its
+// place according to its rank, unzipping the path below it.
+func (t *Tree[K, V]) rise(x *bst.Node[K, V, uint8]) {
+	key := t.Key(x)
+
+	// find the first node on the path that x belongs above.
+	y := t.Root()
+	for y != x && !t.above(x, y) {
+		if t.less(key, t.Key(y)) {
+			y = t.Left(y)
+		} else {
+			y = t.Right(y)
+		}
+	}
+	if y == x {
+		return // x is already in place
+	}
+
+	// detach x from the end of the path, and put it in the place of y
+	if p := t.Parent(x); t.Left(p) == x {
+		t.Tree.SetLeft(p, t.Sentinel())
+	} else {
+		t.Tree.SetRight(p, t.Sentinel())
+	}
+	t.Tree.Transplant(y, x)
+
+	// unzip the path starting at y: nodes with keys less than (or equal to) x form the
+	// right spine of x's left subtree, and the others the left spine of x's right subtree
+	left, right := x, x // last node on each spine
+	for cur := y; !t.IsNil(cur); {
+		if t.less(key, t.Key(cur)) {
+			if right == x {
+				t.Tree.SetRight(x, cur)
+			} else {
+				t.Tree.SetLeft(right, cur)
+			}
+			t.Tree.SetParent(cur, right)
+			right, cur = cur, t.Left(cur)
+		} else {
+			if left == x {
+				t.Tree.SetLeft(x, cur)
+			} else {
+				t.Tree.SetRight(left, cur)
+			}
+			t.Tree.SetParent(cur, left)
+			left, cur = cur, t.Right(cur)
+		}
+	}
+	if left != x {
+		t.Tree.SetRight(left, t.Sentinel())
+	}
+	if right != x {
+		t.Tree.SetLeft(right, t.Sentinel())
+	}
+
+	t.RefreshPath(left)
+	t.RefreshPath(right)
+}
+
+// Delete removes the given node from the tree, replacing it with the zip of its left and right subtrees.
+//
+// The deleted node is released (see bst.Tree.Release) and must no longer be used with the tree.
+//
+// Returns:
+//   - true if the node was removed.
+//   - false if the node is nil, has already been removed, or belongs to a different tree.
+func (t *Tree[K, V]) Delete(x *bst.Node[K, V, uint8]) bool {
+	if t.IsNil(x) || !x.BelongsTo(t.Tree) {
+		return false
+	}
+	t.unlink(x)
+	t.Tree.Release(x)
+	return true
+}
+
+// unlink implements Tree.Delete, replacing node x with the zip of its subtrees, without releasing x.
+func (t *Tree[K, V]) unlink(x *bst.Node[K, V, uint8]) {
+	var lowest *bst.Node[K, V, uint8]
+	parent := t.Parent(x)
+	t.Tree.Transplant(x, t.zip(t.Left(x), t.Right(x), &lowest))
+	if lowest == nil {
+		lowest = parent
+	}
+	t.RefreshPath(lowest)
+}
+
+// UpdateKey changes the key of node x to key, preserving its value and rank (see bst.Tree.UpdateKey).
+//
+// If key does not keep x between its predecessor and successor, x is unlinked as with Tree.Delete, and
+// linked back as with Tree.Insert, at the place its rank gives it on the search path of its new key.
+//
+// Returns:
+//   - true if the key of x was updated.
+//   - false if x is nil, has been removed, or belongs to a different tree, or if another node already
+//     holds key and duplicate keys are not enabled (see bst.WithDuplicateKeys).
+func (t *Tree[K, V]) UpdateKey(x *bst.Node[K, V, uint8], key K) bool {
+	return bst.UpdateKeyFunc(t.Tree, x, key, t.move)
+}
+
+// move implements Tree.UpdateKey for a node whose new key does not keep it in place.
+func (t *Tree[K, V]) move(x *bst.Node[K, V, uint8], key K) {
+	t.unlink(x)
+	t.Tree.Relink(x, key)
+	t.rise(x)
+}
+
+// zip merges the subtrees rooted at l and r, where every key in l precedes every key in r,
+// by interleaving the right spine of l with the left spine of r in rank order.
+// It returns the root of the merged subtree, and sets lowest to the deepest relinked node, if any.
+func (t *Tree[K, V]) zip(l, r *bst.Node[K, V, uint8], lowest **bst.Node[K, V, uint8]) *bst.Node[K, V, uint8] {
+	switch {
+	case t.IsNil(l):
+		return r
+	case t.IsNil(r):
+		return l
+	case t.Metadata(l) >= t.Metadata(r):
+		// l has the smaller keys, so stays above r when the ranks are equal
+		t.Tree.SetRight(l, t.zip(t.Right(l), r, lowest))
+		t.Tree.SetParent(t.Right(l), l)
+		if *lowest == nil {
+			*lowest = l
+		}
+		return l
+	default:
+		t.Tree.SetLeft(r, t.zip(l, t.Left(r), lowest))
+		t.Tree.SetParent(t.Left(r), r)
+		if *lowest == nil {
+			*lowest = r
+		}
+		return r
+	}
+}
+
+// RangeDelete removes every node whose key falls within the half-open interval [lo, hi),
+// zipping the tree after each removal.
+//
+// If hi is not greater than lo, no nodes are removed.
+//
+// Returns:
+//   - The number of nodes removed from the tree.
+func (t *Tree[K, V]) RangeDelete(lo, hi K) int {
+	count := 0
+	n, found := t.Ceiling(lo)
+	for found && t.less(t.Key(n), hi) {
+		next := t.Successor(n)
+		t.Delete(n)
+		count++
+		n, found = next, !t.IsNil(next)
+	}
+	return count
+}
+
+// AscendDelete calls f for each node of the tree in ascending key order, deleting the nodes for
+// which f returns an Action including bst.ActionDelete (see bst.Tree.AscendDelete), zipping the tree after each removal.
+//
+// Returns:
+//   - The number of nodes deleted.
+func (t *Tree[K, V]) AscendDelete(f func(n *bst.Node[K, V, uint8]) bst.Action) int {
+	return bst.AscendDeleteFunc(t.Tree, t.Delete, f)
+}
+
+// PopMin removes the node with the smallest key from the tree, and returns its key and value
+// (see bst.Tree.PopMin), zipping the tree after the removal.
+//
+// Returns:
+//   - (key, value, true) if the tree was not empty.
+//   - (zero key, zero value, false) if the tree is empty.
+func (t *Tree[K, V]) PopMin() (K, V, bool) {
+	return bst.PopMinFunc(t.Tree, t.Delete)
+}
+
+// PopMax removes the node with the largest key from the tree, and returns its key and value
+// (see bst.Tree.PopMax), zipping the tree after the removal.
+//
+// Returns:
+//   - (key, value, true) if the tree was not empty.
+//   - (zero key, zero value, false) if the tree is empty.
+func (t *Tree[K, V]) PopMax() (K, V, bool) {
+	return bst.PopMaxFunc(t.Tree, t.Delete)
+}
+
+// DeleteAt removes the node with zero-based rank i from the tree, and returns its key and value
+// (see bst.Tree.DeleteAt), zipping the tree after the removal.
+//
+// Returns:
+//   - (key, value, true) if 0 ≤ i < Tree.Size.
+//   - (zero key, zero value, false) if i is out of range.
+func (t *Tree[K, V]) DeleteAt(i int) (K, V, bool) {
+	return bst.DeleteAtFunc(t.Tree, i, t.Delete)
+}
+
+// LoadSorted replaces the contents of the tree with the given keys and values, building a perfectly
+// balanced tree in O(n) time on up to parallelism goroutines (see bst.Tree.LoadSorted).
+//
+// Rather than random ranks, each node is given its height in the tree as its rank, i.e. ⌊log2(n)⌋ minus
+// its depth, which orders the ranks as a zip tree requires. As about half of the nodes are leaves, a
+// quarter are their parents, and so on, the ranks follow the same geometric distribution as random ranks,
+// so later insertions and deletions keep the tree balanced as usual.
+//
+// Returns:
+//   - nil if the tree was loaded.
+//   - An error if the keys are out of order, or values and keys differ in length. The tree is then
+//     left unchanged.
+func (t *Tree[K, V]) LoadSorted(keys []K, values []V, parallelism int) error {
+	height := bits.Len(uint(len(keys))) - 1
+	return t.Tree.LoadSortedFunc(keys, values, parallelism, func(n *bst.Node[K, V, uint8], depth int) {
+		t.Tree.MustSetMetadata(n, uint8(height-depth))
+	})
+}
+
+// ApplyDelta replays the changes of d on the tree (see bst.Delta.Apply), zipping the tree as keys are inserted and deleted.
+func (t *Tree[K, V]) ApplyDelta(d *bst.Delta[K, V]) {
+	d.Apply(func(key K, value V) {
+		t.InsertWithPolicy(key, value, bst.DuplicateReplace)
+	}, func(key K) {
+		if n, found := t.Search(key); found {
+			t.Delete(n)
+		}
+	}, t.Clear)
+}
+
+// ApplyPatch applies p to the tree (see bst.Patch.Apply), zipping the tree as keys are inserted and deleted.
+func (t *Tree[K, V]) ApplyPatch(p *bst.Patch[K, V]) {
+	p.Apply(func(key K, value V) {
+		t.InsertWithPolicy(key, value, bst.DuplicateReplace)
+	}, func(key K) {
+		if n, found := t.Search(key); found {
+			t.Delete(n)
+		}
+	})
+}
+
+// IsTreeValid checks whether the tree is a valid binary search tree (see bst.Tree.IsTreeValid),
+// and whether it is heap-ordered by rank: every node's rank is greater than that of its left
+// child, and at least that of its right child.
+//
+// Returns:
+//   - nil if the tree is valid.
+//   - An error describing the first violation found otherwise.
+func (t *Tree[K, V]) IsTreeValid() error {
+	if err := t.Tree.IsTreeValid(); err != nil {
+		return fmt.Errorf("underlying BST is invalid: %w", err)
+	}
+	for n := t.Min(t.Root()); !t.IsNil(n); n = t.Successor(n) {
+		if l := t.Left(n); !t.IsNil(l) && t.Metadata(l) >= t.Metadata(n) {
+			return fmt.Errorf("node %v has rank %d, not greater than its left child's rank %d", t.Key(n), t.Metadata(n), t.Metadata(l))
+		}
+		if r := t.Right(n); !t.IsNil(r) && t.Metadata(r) > t.Metadata(n) {
+			return fmt.Errorf("node %v has rank %d, less than its right child's rank %d", t.Key(n), t.Metadata(n), t.Metadata(r))
+		}
+	}
+	return nil
+}
+
+// Deprecated: Should not be called on a ziptree.Tree, doing so may corrupt the tree.
+func (t *Tree[K, V]) AttachSubtree() {
+	panic(fmt.Errorf("AttachSubtree should not be called on a ziptree.Tree, doing so may corrupt the tree"))
+}
+
+// Deprecated: Should not be called on a ziptree.Tree, doing so may corrupt the tree.
+func (t *Tree[K, V]) DetachSubtree() {
+	panic(fmt.Errorf("DetachSubtree should not be called on a ziptree.Tree, doing so may corrupt the tree"))
+}
+
+// Deprecated: Should not be called on a ziptree.Tree, doing so may corrupt the tree.
+func (t *Tree[K, V]) LoadSortedFunc() {
+	panic(fmt.Errorf("LoadSortedFunc should not be called on a ziptree.Tree, doing so may corrupt the tree"))
+}
+
+// Deprecated: Should not be called on a ziptree.Tree, doing so may corrupt the tree.
+func (t *Tree[K, V]) MustSetMetadata() {
+	panic(fmt.Errorf("MustSetMetadata should not be called on a ziptree.Tree, doing so may corrupt the tree"))
+}
+
+// Deprecated: Should not be called on a ziptree.Tree, doing so may corrupt the tree.
+func (t *Tree[K, V]) Rebalance() {
+	panic(fmt.Errorf("Rebalance should not be called on a ziptree.Tree, doing so may corrupt the tree"))
+}
+
+// Deprecated: Should not be called on a ziptree.Tree, doing so may corrupt the tree.
+func (t *Tree[K, V]) RebuildSubtree() {
+	panic(fmt.Errorf("RebuildSubtree should not be called on a ziptree.Tree, doing so may corrupt the tree"))
+}
+
+// Deprecated: Should not be called on a ziptree.Tree, doing so may corrupt the tree.
+func (t *Tree[K, V]) InsertAt() {
+	panic(fmt.Errorf("InsertAt should not be called on a ziptree.Tree, doing so may corrupt the tree"))
+}
+
+// Deprecated: Should not be called on a ziptree.Tree, doing so may corrupt the tree.
+func (t *Tree[K, V]) Relink() {
+	panic(fmt.Errorf("Relink should not be called on a ziptree.Tree, doing so may corrupt the tree"))
+}
+
+// Deprecated: Should not be called on a ziptree.Tree, doing so may corrupt the tree.
+func (t *Tree[K, V]) RotateLeft() {
+	panic(fmt.Errorf("RotateLeft should not be called on a ziptree.Tree, doing so may corrupt the tree"))
+}
+
+// Deprecated: Should not be called on a ziptree.Tree, doing so may corrupt the tree.
+func (t *Tree[K, V]) RotateRight() {
+	panic(fmt.Errorf("RotateRight should not be called on a ziptree.Tree, doing so may corrupt the tree"))
+}
+
+// Deprecated: Should not be called on a ziptree.Tree, doing so may corrupt the tree.
+func (t *Tree[K, V]) SetHooks() {
+	panic(fmt.Errorf("SetHooks should not be called on a ziptree.Tree, doing so may corrupt the tree"))
+}
+
+// Deprecated: Should not be called on a ziptree.Tree, doing so may corrupt the tree.
+func (t *Tree[K, V]) SetKey() {
+	panic(fmt.Errorf("SetKey should not be called on a ziptree.Tree, doing so may corrupt the tree"))
+}
+
+// Deprecated: Should not be called on a ziptree.Tree, doing so may corrupt the tree.
+func (t *Tree[K, V]) SetLeft() {
+	panic(fmt.Errorf("SetLeft should not be called on a ziptree.Tree, doing so may corrupt the tree"))
+}
+
+// Deprecated: Should not be called on a ziptree.Tree, doing so may corrupt the tree.
+func (t *Tree[K, V]) SetMetadata() {
+	panic(fmt.Errorf("SetMetadata should not be called on a ziptree.Tree, doing so may corrupt the tree"))
+}
+
+// Deprecated: Should not be called on a ziptree.Tree, doing so may corrupt the tree.
+func (t *Tree[K, V]) SetParent() {
+	panic(fmt.Errorf("SetParent should not be called on a ziptree.Tree, doing so may corrupt the tree"))
+}
+
+// Deprecated: Should not be called on a ziptree.Tree, doing so may corrupt the tree.
+func (t *Tree[K, V]) SetRight() {
+	panic(fmt.Errorf("SetRight should not be called on a ziptree.Tree, doing so may corrupt the tree"))
+}
+
+// Deprecated: Should not be called on a ziptree.Tree, doing so may corrupt the tree.
+func (t *Tree[K, V]) SetRoot() {
+	panic(fmt.Errorf("SetRoot should not be called on a ziptree.Tree, doing so may corrupt the tree"))
+}
+
+// Deprecated: Should not be called on a ziptree.Tree, doing so may corrupt the tree.
+func (t *Tree[K, V]) Transplant() {
+	panic(fmt.Errorf("Transplant should not be called on a ziptree.Tree, doing so may corrupt the tree"))
+}
@@ -0,0 +1,287 @@
+package ziptree
+
+import (
+	"github.com/mikenye/gotrees/bst"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+// height returns the number of levels in the tree.
+func height[K, V any](tree *Tree[K, V]) int {
+	h := 0
+	tree.TraverseInOrder(tree.Root(), func(n *bst.Node[K, V, uint8]) bool {
+		h = max(h, tree.Depth(n)+1)
+		return true
+	})
+	return h
+}
+
+func TestNew_autoRebalance(t *testing.T) {
+	assert.Panics(t, func() {
+		New[int, int](intLess, bst.WithAutoRebalance(2))
+	}, "expected rebalancing to be rejected, as it would discard node ranks")
+}
+
+func TestTree_Insert_sequential(t *testing.T) {
+	tree := New[int, int](intLess)
+	for i := 0; i < 10000; i++ {
+		_, inserted := tree.Insert(i, i)
+		require.True(t, inserted)
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 10000, tree.Size())
+	assert.Less(t, height(tree), 4*int(math.Log2(10000)), "expected height to be logarithmic despite sorted inserts")
+
+	// updating an existing key does not insert
+	_, inserted := tree.Insert(5, -5)
+	assert.False(t, inserted)
+	n, _ := tree.Search(5)
+	assert.Equal(t, -5, tree.Value(n))
+}
+
+func TestTree_InsertDelete_random(t *testing.T) {
+	tree := New[int, int](intLess)
+	rng := rand.New(rand.NewSource(1))
+	expected := make(map[int]int)
+	handles := make(map[int]*bst.Node[int, int, uint8])
+
+	for i := 0; i < 20000; i++ {
+		key := rng.Intn(2000)
+		if rng.Intn(2) == 0 {
+			n, found := tree.Search(key)
+			_, exists := expected[key]
+			require.Equal(t, exists, found)
+			if found {
+				assert.Same(t, handles[key], n, "expected node handles to remain valid")
+				assert.True(t, tree.Delete(n))
+				assert.False(t, tree.Delete(n), "expected stale node not to be deleted")
+				delete(expected, key)
+			}
+		} else {
+			n, _ := tree.Insert(key, i)
+			expected[key] = i
+			handles[key] = n
+		}
+		if i%1000 == 0 {
+			require.NoError(t, tree.IsTreeValid())
+		}
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, len(expected), tree.Size())
+	for key, value := range expected {
+		n, found := tree.Search(key)
+		require.True(t, found)
+		assert.Equal(t, value, tree.Value(n))
+	}
+
+	assert.Equal(t, len(expected), tree.RangeDelete(0, 2000))
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 0, tree.Size())
+}
+
+func TestTree_AscendDelete(t *testing.T) {
+	tree := New[int, int](intLess)
+	for i := 0; i < 1000; i++ {
+		tree.Insert(i, i)
+	}
+
+	count := tree.AscendDelete(func(n *bst.Node[int, int, uint8]) bst.Action {
+		if n.Key()%3 == 0 {
+			return bst.ActionDelete
+		}
+		return bst.ActionContinue
+	})
+	assert.Equal(t, 334, count)
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 666, tree.Size())
+	_, found := tree.Search(999)
+	assert.False(t, found)
+}
+
+func TestTree_PopMin(t *testing.T) {
+	tree := New[int, int](intLess)
+	for i := 0; i < 1000; i++ {
+		tree.Insert(i, i)
+	}
+	for i := 0; i < 500; i++ {
+		key, _, ok := tree.PopMin()
+		require.True(t, ok)
+		assert.Equal(t, i, key)
+		key, _, ok = tree.PopMax()
+		require.True(t, ok)
+		assert.Equal(t, 999-i, key)
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 0, tree.Size())
+}
+
+func TestTree_DeleteAt(t *testing.T) {
+	tree := New[int, int](intLess)
+	for i := 0; i < 1000; i++ {
+		tree.Insert(i, i)
+	}
+
+	// evict every other key by position
+	for i := 0; i < 500; i++ {
+		key, value, ok := tree.DeleteAt(i)
+		require.True(t, ok)
+		assert.Equal(t, 2*i, key)
+		assert.Equal(t, key, value)
+	}
+	_, _, ok := tree.DeleteAt(500)
+	assert.False(t, ok, "expected out of range rank to delete nothing")
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 500, tree.Size())
+}
+
+func TestTree_duplicateKeys(t *testing.T) {
+	tree := New[int, int](intLess, bst.WithDuplicateKeys())
+	for i := 0; i < 1000; i++ {
+		tree.Insert(i%10, i)
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 100, tree.Count(3))
+
+	// equal keys are kept in insertion order
+	prev := -1
+	tree.TraverseInOrder(tree.Root(), func(n *bst.Node[int, int, uint8]) bool {
+		if tree.Key(n) == 3 {
+			assert.Greater(t, tree.Value(n), prev)
+			prev = tree.Value(n)
+		}
+		return true
+	})
+
+	n, _ := tree.Search(3)
+	assert.True(t, tree.Delete(n))
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 99, tree.Count(3))
+}
+
+func TestTree_WithReverseOrder(t *testing.T) {
+	tree := New[int, int](intLess, bst.WithReverseOrder())
+	for i := 0; i < 1000; i++ {
+		tree.Insert(i, i)
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 999, tree.Key(tree.Min(tree.Root())))
+	assert.Equal(t, 500, tree.RangeDelete(999, 499))
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 499, tree.Key(tree.Min(tree.Root())))
+}
+
+func TestTree_LoadSorted(t *testing.T) {
+	tree := New[int, int](intLess)
+	keys := make([]int, 10000)
+	for i := range keys {
+		keys[i] = i
+	}
+	require.NoError(t, tree.LoadSorted(keys, keys, 0))
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 10000, tree.Size())
+	assert.Equal(t, 14, height(tree))
+	assert.Equal(t, uint8(13), tree.Metadata(tree.Root()))
+
+	// ranks are ordered, so later insertions and deletions keep the tree valid
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		n, _ := tree.Search(rng.Intn(10000))
+		tree.Delete(n)
+		tree.Insert(10000+i, i)
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Less(t, height(tree), 4*int(math.Log2(10000)))
+}
+
+func TestTree_unsafeMethods(t *testing.T) {
+	tree := New[int, int](intLess)
+	assert.Panics(t, func() { tree.AttachSubtree() })
+	assert.Panics(t, func() { tree.DetachSubtree() })
+	assert.Panics(t, func() { tree.MustSetMetadata() })
+	assert.Panics(t, func() { tree.Rebalance() })
+	assert.Panics(t, func() { tree.RebuildSubtree() })
+	assert.Panics(t, func() { tree.InsertAt() })
+	assert.Panics(t, func() { tree.LoadSortedFunc() })
+	assert.Panics(t, func() { tree.Relink() })
+	assert.Panics(t, func() { tree.RotateLeft() })
+	assert.Panics(t, func() { tree.RotateRight() })
+	assert.Panics(t, func() { tree.SetHooks() })
+	assert.Panics(t, func() { tree.SetKey() })
+	assert.Panics(t, func() { tree.SetLeft() })
+	assert.Panics(t, func() { tree.SetMetadata() })
+	assert.Panics(t, func() { tree.SetParent() })
+	assert.Panics(t, func() { tree.SetRight() })
+	assert.Panics(t, func() { tree.SetRoot() })
+	assert.Panics(t, func() { tree.Transplant() })
+}
+
+func TestTree_UpdateKey(t *testing.T) {
+	tree := New[int, int](intLess)
+	rng := rand.New(rand.NewSource(1))
+	handles := make(map[int]*bst.Node[int, int, uint8])
+	for len(handles) < 300 {
+		key := rng.Intn(1000)
+		handles[key], _ = tree.Insert(key, key)
+	}
+
+	for i := 0; i < 3000; i++ {
+		var oldKey int
+		for oldKey = range handles {
+			break
+		}
+		n, newKey := handles[oldKey], rng.Intn(1000)
+		value := tree.Value(n)
+		if _, taken := handles[newKey]; taken && newKey != oldKey {
+			require.False(t, tree.UpdateKey(n, newKey), "expected key %d to be rejected as taken", newKey)
+		} else {
+			require.True(t, tree.UpdateKey(n, newKey), "expected key %d to be updated to %d", oldKey, newKey)
+			delete(handles, oldKey)
+			handles[newKey] = n
+		}
+		require.Equal(t, value, tree.Value(n), "expected value to be preserved")
+		require.NoError(t, tree.IsTreeValid(), "expected valid tree")
+	}
+	for key, n := range handles {
+		found, _ := tree.Search(key)
+		assert.Same(t, n, found, "expected key %d to be held by the same node", key)
+	}
+}
+
+func TestTree_duplicatePolicy(t *testing.T) {
+	tree := New[int, int](intLess, bst.WithDuplicatePolicy(bst.DuplicateIgnore))
+	for i := 0; i < 1000; i++ {
+		tree.Insert(i, i)
+	}
+	for i := 0; i < 1000; i++ {
+		_, inserted, err := tree.TryInsert(i, -i)
+		require.NoError(t, err)
+		assert.False(t, inserted)
+	}
+	n, _ := tree.Search(5)
+	assert.Equal(t, 5, tree.Value(n), "expected the existing value to be kept")
+
+	_, inserted := tree.InsertIfAbsent(1000, 1000)
+	assert.True(t, inserted)
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 1001, tree.Size())
+}
+
+func TestTree_InsertReturningOld(t *testing.T) {
+	tree := New[int, int](intLess)
+	for i := 0; i < 1000; i++ {
+		_, existed := tree.InsertReturningOld(i, i)
+		assert.False(t, existed)
+	}
+	for i := 0; i < 1000; i++ {
+		old, existed := tree.InsertReturningOld(i, -i)
+		assert.True(t, existed)
+		assert.Equal(t, i, old)
+	}
+	require.NoError(t, tree.IsTreeValid())
+	assert.Equal(t, 1000, tree.Size())
+}